@@ -4,24 +4,31 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 
 	"github.com/ammerola/resell-be/internal/adapters/db"
 	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/internal/core/domain"
 	"github.com/ammerola/resell-be/internal/core/ports"
 	"github.com/ammerola/resell-be/internal/core/services"
 	"github.com/ammerola/resell-be/internal/handlers"
 	"github.com/ammerola/resell-be/internal/handlers/middleware"
 	"github.com/ammerola/resell-be/internal/pkg/config"
 	"github.com/ammerola/resell-be/internal/pkg/logger"
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
 )
 
 // Build information injected at compile time
@@ -32,6 +39,9 @@ var (
 )
 
 func main() {
+	allowMissing := flag.Bool("allow-missing", false, "Start even if required environment variables are unset (development only)")
+	flag.Parse()
+
 	// Initialize structured logger
 	slogger := logger.SetupLogger("debug", "json")
 
@@ -43,19 +53,28 @@ func main() {
 
 	// Load configuration
 	slogger.Info("loading configuration")
-	cfg, err := config.Load(slogger.Logger)
+	loader := config.NewConfigLoader(slogger.Logger).AllowMissing(*allowMissing)
+	cfg, err := loader.Load(context.Background())
 	if err != nil {
 		slogger.Error("failed to load configuration", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
-	// Reconfigure logger with loaded settings
-	slogger = logger.SetupLogger(cfg.App.LogLevel, cfg.App.LogFormat)
+	// Reconfigure logger with loaded settings and outputs, sampling info logs if enabled
+	configured, err := logger.Setup(cfg.LogConfig())
+	if err != nil {
+		slogger.Error("failed to set up log outputs", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	slogger = configured
+	defer slogger.Close()
 
 	// Setup ELK logging if enabled
 	if cfg.Logging.EnableELK {
 		slogger = logger.SetupELKLogging(cfg.Logging.ELKConfig)
 		slog.SetDefault(slogger.Logger)
+		// Ship whatever is still queued before exiting
+		defer slogger.Close()
 	}
 
 	slogger.Info("configuration loaded",
@@ -67,13 +86,19 @@ func main() {
 	ctx := context.Background()
 
 	// Initialize dependencies
-	deps, err := initializeDependencies(ctx, cfg, slogger.Logger)
+	secretsWatcher := loader.SecretsWatcher(cfg)
+	deps, err := initializeDependencies(ctx, cfg, secretsWatcher, slogger.Logger)
 	if err != nil {
 		slogger.Error("failed to initialize dependencies", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 	defer deps.cleanup()
 
+	// Pick up rotated DB/Redis passwords without a restart
+	if secretsWatcher != nil {
+		go secretsWatcher.Run(ctx)
+	}
+
 	// Run database migrations if enabled
 	if cfg.App.Environment != "production" {
 		if err := runMigrations(ctx, cfg, slogger.Logger); err != nil {
@@ -83,7 +108,7 @@ func main() {
 	}
 
 	// Setup HTTP server
-	server := setupHTTPServer(cfg, deps, slogger)
+	server, reloadable := setupHTTPServer(cfg, deps, slogger)
 
 	// Start server in goroutine
 	serverErrors := make(chan error, 1)
@@ -100,6 +125,24 @@ func main() {
 		}
 	}()
 
+	// SIGHUP reloads the settings that can change without a restart
+	configReloader := &reloader{
+		running:        cfg,
+		allowMissing:   *allowMissing,
+		logger:         slogger,
+		middleware:     reloadable,
+		rateLimiter:    deps.rateLimiter,
+		trustedProxies: deps.trustedProxies,
+	}
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			slogger.Info("reloading configuration")
+			configReloader.Reload(ctx)
+		}
+	}()
+
 	// Setup signal handling for graceful shutdown
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
@@ -143,12 +186,22 @@ type dependencies struct {
 	redisCache       ports.CacheRepository
 	asynqClient      *asynq.Client
 	asynqInspector   *asynq.Inspector
+	migrator         *db.Migrator
 	inventoryService *services.InventoryService
 	inventoryHandler *handlers.InventoryHandler
+	qrCodeHandler    *handlers.QRCodeHandler
+	searchHandler    *handlers.SearchHandler
+	platformHandler  *handlers.PlatformHandler
+	categoryHandler  *handlers.CategoryMappingHandler
+	adminHandler     *handlers.AdminHandler
+	queueHandler     *handlers.QueueHandler
 	healthHandler    *handlers.HealthHandler
 	dashboardHandler *handlers.DashboardHandler
 	exportHandler    *handlers.ExportHandler
 	importHandler    *handlers.ImportHandler
+	authHandler      *handlers.AuthHandler
+	trustedProxies   []*net.IPNet
+	rateLimiter      *middleware.RateLimiter
 }
 
 func (d *dependencies) cleanup() {
@@ -161,11 +214,24 @@ func (d *dependencies) cleanup() {
 	if d.asynqClient != nil {
 		d.asynqClient.Close()
 	}
+	if d.migrator != nil {
+		d.migrator.Close()
+	}
 }
 
-func initializeDependencies(ctx context.Context, cfg *config.Config, slogger *slog.Logger) (*dependencies, error) {
+func initializeDependencies(ctx context.Context, cfg *config.Config, secretsWatcher *config.SecretsWatcher, slogger *slog.Logger) (*dependencies, error) {
 	deps := &dependencies{}
 
+	// Forwarded client IPs are only trusted from these networks
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.Security.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trusted proxies: %w", err)
+	}
+	deps.trustedProxies = trustedProxies
+
+	// One limiter serves the process so its buckets survive configuration reloads
+	deps.rateLimiter = middleware.NewRateLimiter(rateLimitConfig(cfg, trustedProxies))
+
 	// Initialize database connection
 	slogger.Info("connecting to database",
 		slog.String("host", cfg.Database.Host),
@@ -199,21 +265,22 @@ func initializeDependencies(ctx context.Context, cfg *config.Config, slogger *sl
 		slog.String("port", cfg.Redis.Port),
 	)
 
+	redisCredentials := redis_a.NewCredentials(cfg.Redis.Password)
 	redisOpts := &redis.Options{
-		Addr:            fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
-		Password:        cfg.Redis.Password,
-		DB:              cfg.Redis.DB,
-		MaxRetries:      cfg.Redis.MaxRetries,
-		MinRetryBackoff: cfg.Redis.MinRetryBackoff,
-		MaxRetryBackoff: cfg.Redis.MaxRetryBackoff,
-		DialTimeout:     cfg.Redis.DialTimeout,
-		ReadTimeout:     cfg.Redis.ReadTimeout,
-		WriteTimeout:    cfg.Redis.WriteTimeout,
-		PoolSize:        cfg.Redis.PoolSize,
-		MinIdleConns:    cfg.Redis.MinIdleConns,
-		ConnMaxLifetime: cfg.Redis.MaxConnAge,
-		PoolTimeout:     cfg.Redis.PoolTimeout,
-		ConnMaxIdleTime: cfg.Redis.IdleTimeout,
+		Addr:                fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		CredentialsProvider: redisCredentials.Provider(),
+		DB:                  cfg.Redis.DB,
+		MaxRetries:          cfg.Redis.MaxRetries,
+		MinRetryBackoff:     cfg.Redis.MinRetryBackoff,
+		MaxRetryBackoff:     cfg.Redis.MaxRetryBackoff,
+		DialTimeout:         cfg.Redis.DialTimeout,
+		ReadTimeout:         cfg.Redis.ReadTimeout,
+		WriteTimeout:        cfg.Redis.WriteTimeout,
+		PoolSize:            cfg.Redis.PoolSize,
+		MinIdleConns:        cfg.Redis.MinIdleConns,
+		ConnMaxLifetime:     cfg.Redis.MaxConnAge,
+		PoolTimeout:         cfg.Redis.PoolTimeout,
+		ConnMaxIdleTime:     cfg.Redis.IdleTimeout,
 	}
 
 	redisClient := redis.NewClient(redisOpts)
@@ -224,6 +291,13 @@ func initializeDependencies(ctx context.Context, cfg *config.Config, slogger *sl
 	}
 	deps.redisClient = redisClient
 
+	if secretsWatcher != nil {
+		secretsWatcher.Watch("DB_PASSWORD", cfg.Database.Password, database.UpdatePassword)
+		secretsWatcher.Watch("REDIS_PASSWORD", cfg.Redis.Password, func(ctx context.Context, password string) error {
+			return redisCredentials.Rotate(ctx, redisClient, password)
+		})
+	}
+
 	// Create Redis cache wrapper
 	deps.redisCache = redis_a.NewCache(redisClient, cfg.Redis.TTL, slogger)
 
@@ -242,33 +316,87 @@ func initializeDependencies(ctx context.Context, cfg *config.Config, slogger *sl
 	asynqInspector := asynq.NewInspector(asynqRedisOpt)
 	deps.asynqInspector = asynqInspector
 
+	// Readiness reports the schema version through a long-lived migrator
+	migrator, err := db.NewMigrator(migrationConfig(cfg), slogger)
+	if err != nil {
+		slogger.WarnContext(ctx, "migration status unavailable, readiness will skip the schema check",
+			slog.String("error", err.Error()))
+	} else {
+		deps.migrator = migrator
+	}
+
 	// Initialize repositories
 	inventoryRepo := db.NewInventoryRepository(database, slogger)
+	listingRepo := db.NewListingRepository(database, slogger)
+	categoryMappingRepo := db.NewCategoryMappingRepository(database, slogger)
+	dashboardRepo := db.NewDashboardRepository(database, slogger)
+	userRepo := db.NewUserRepository(database, slogger)
 
 	// Initialize services
 	deps.inventoryService = services.NewInventoryService(inventoryRepo, database.Pool(), slogger)
+	categoryMapper := services.NewCategoryMapper(categoryMappingRepo, slogger)
+	if err := categoryMapper.Load(ctx); err != nil {
+		// Listings still work without the table, they just fall back to the default category
+		slogger.WarnContext(ctx, "failed to load category mappings",
+			slog.String("error", err.Error()))
+	}
+	listingService := services.NewListingService(listingRepo, inventoryRepo, categoryMapper, slogger)
 
 	// Initialize handlers
-	deps.inventoryHandler = handlers.NewInventoryHandler(deps.inventoryService, slogger)
+	deps.inventoryHandler = handlers.NewInventoryHandler(deps.inventoryService, deps.redisCache, cfg.App.IdempotencyTTL, int64(cfg.Server.MaxBodyBytes), slogger)
+	qrTemplate := domain.QRTemplate(cfg.App.QRTemplate)
+	if err := qrTemplate.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid QR_URL_TEMPLATE: %w", err)
+	}
+	deps.qrCodeHandler = handlers.NewQRCodeHandler(deps.inventoryService, qrTemplate, slogger)
+	deps.searchHandler = handlers.NewSearchHandler(deps.inventoryService, deps.redisCache, slogger)
+	deps.platformHandler = handlers.NewPlatformHandler(listingService, deps.asynqClient, slogger)
+	deps.categoryHandler = handlers.NewCategoryMappingHandler(categoryMapper, slogger)
+	deps.adminHandler = handlers.NewAdminHandler(database, deps.redisCache, deps.asynqClient, slogger)
+	deps.queueHandler = handlers.NewQueueHandler(asynqInspector, slogger)
+	// Leave the interface nil rather than wrapping a nil *db.Migrator
+	var schemaVersioner ports.SchemaVersioner
+	if deps.migrator != nil {
+		schemaVersioner = deps.migrator
+	}
 	deps.healthHandler = handlers.NewHealthHandler(
 		database,
 		redisClient,
 		asynqInspector,
+		schemaVersioner,
 		cfg,
 		slogger,
 	)
-	deps.dashboardHandler = handlers.NewDashboardHandler(database, deps.redisCache, slogger)
-	deps.exportHandler = handlers.NewExportHandler(deps.inventoryService, database, deps.redisCache, slogger)
+	deps.dashboardHandler = handlers.NewDashboardHandler(deps.inventoryService, dashboardRepo, database, deps.redisCache, slogger)
+	deps.exportHandler = handlers.NewExportHandler(deps.inventoryService, database, deps.redisCache, slogger, cfg.FileProcessing.ExportStreamThreshold)
+
+	// Uploads go to shared storage so any worker replica can process them
+	fileStorage, err := storage.NewStorageClient(ctx, cfg, slogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file storage: %w", err)
+	}
 
 	// Calculate max file size in bytes
 	maxFileSize := int64(cfg.FileProcessing.PDFMaxSizeMB * 1024 * 1024)
-	deps.importHandler = handlers.NewImportHandler(asynqClient, slogger, maxFileSize, cfg.FileProcessing.TempDir)
+	invoiceProfiles, err := pdfparse.LoadProfiles(cfg.FileProcessing.InvoiceProfilesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invoice profiles: %w", err)
+	}
+	deps.importHandler = handlers.NewImportHandler(asynqClient, asynqInspector, database, fileStorage, slogger, maxFileSize, invoiceProfiles)
+	deps.authHandler = handlers.NewAuthHandler(
+		userRepo,
+		cfg.Security.JWTSecret,
+		cfg.Security.JWTExpiration,
+		cfg.Security.JWTRefreshExpiration,
+		cfg.Security.BcryptCost,
+		slogger,
+	)
 
 	slogger.Info("all dependencies initialized successfully")
 	return deps, nil
 }
 
-func setupHTTPServer(cfg *config.Config, deps *dependencies, l *logger.Logger) *http.Server {
+func setupHTTPServer(cfg *config.Config, deps *dependencies, l *logger.Logger) (*http.Server, *middleware.Reloadable) {
 	// Create new ServeMux using Go 1.22+ features
 	mux := http.NewServeMux()
 
@@ -276,24 +404,40 @@ func setupHTTPServer(cfg *config.Config, deps *dependencies, l *logger.Logger) *
 	var handler http.Handler = mux
 
 	// Apply middleware in reverse order (innermost first)
+	if cfg.Server.EnableMetrics {
+		// Innermost so the matched route pattern is visible after the mux returns
+		handler = middleware.MetricsMiddleware(handler)
+	}
+
+	if cfg.Server.EnableCompression {
+		handler = middleware.Compression(handler)
+	}
+
+	if cfg.Server.RequestTimeout > 0 {
+		handler = middleware.Timeout(cfg.Server.RequestTimeout, cfg.Server.TimeoutExempt)(handler)
+	}
+
 	if cfg.App.Environment != "test" {
-		handler = middleware.RequestID(handler)
-		handler = middleware.Logger(l)(handler)
+		handler = middleware.Logger(l, cfg.Security.JWTSecret, deps.trustedProxies)(handler)
 		handler = middleware.Recovery(l.Logger)(handler)
 	}
 
-	if cfg.Security.RateLimitRequests > 0 {
-		handler = middleware.RateLimit(cfg.Security.RateLimitRequests, cfg.Security.RateLimitDuration)(handler)
+	if cfg.Security.CSRFProtection {
+		handler = middleware.CSRF(cfg.Security.CSRFExemptPaths)(handler)
 	}
 
-	if len(cfg.Security.AllowedOrigins) > 0 {
-		handler = middleware.CORS(cfg.Security.AllowedOrigins)(handler)
-	}
+	// Rate limiting and CORS are swapped in place when the configuration is reloaded
+	reloadable := middleware.NewReloadable(handler, reloadableMiddleware(cfg, deps.rateLimiter, deps.trustedProxies))
+	handler = reloadable
 
 	if cfg.Security.SecureHeaders {
 		handler = middleware.SecureHeaders(handler)
 	}
 
+	// Outermost so every response, including rate limit and panic responses, carries the ID
+	// that Logger and Recovery read from the context
+	handler = middleware.RequestID(handler)
+
 	// Register routes using Go 1.22 method-specific routing
 	registerRoutes(mux, deps, l.Logger, cfg)
 
@@ -308,7 +452,28 @@ func setupHTTPServer(cfg *config.Config, deps *dependencies, l *logger.Logger) *
 		ErrorLog:       slog.NewLogLogger(l.Handler(), slog.LevelError),
 	}
 
-	return server
+	return server, reloadable
+}
+
+// rateLimitConfig builds the rate limiter settings, applying the global duration to every route
+func rateLimitConfig(cfg *config.Config, trustedProxies []*net.IPNet) middleware.RateLimitConfig {
+	rlc := middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{
+			Requests: cfg.Security.RateLimitRequests,
+			Period:   cfg.Security.RateLimitDuration,
+			Burst:    cfg.Security.RateLimitBurst,
+		},
+		Routes:         make(map[string]middleware.RateLimitRule, len(cfg.Security.RateLimitRoutes)),
+		TrustedProxies: trustedProxies,
+	}
+	for prefix, route := range cfg.Security.RateLimitRoutes {
+		rlc.Routes[prefix] = middleware.RateLimitRule{
+			Requests: route.Requests,
+			Period:   cfg.Security.RateLimitDuration,
+			Burst:    route.Burst,
+		}
+	}
+	return rlc
 }
 
 func registerRoutes(mux *http.ServeMux, deps *dependencies, slogger *slog.Logger, cfg *config.Config) {
@@ -321,42 +486,83 @@ func registerRoutes(mux *http.ServeMux, deps *dependencies, slogger *slog.Logger
 		mux.HandleFunc("GET "+apiV1+"/health", deps.healthHandler.Health)
 	}
 
+	// Token issuance is public; the refresh token itself authenticates a refresh
+	mux.HandleFunc("POST "+apiV1+"/auth/login", deps.authHandler.Login)
+	mux.HandleFunc("POST "+apiV1+"/auth/refresh", deps.authHandler.Refresh)
+
+	// Writes require a bearer token; reads only when REQUIRE_AUTH_FOR_READS is set
+	requireAuth := middleware.Auth(cfg.Security.JWTSecret)
+	write := func(h http.HandlerFunc) http.Handler { return requireAuth(h) }
+	read := func(h http.HandlerFunc) http.Handler {
+		if cfg.Security.RequireAuthForReads {
+			return requireAuth(h)
+		}
+		return h
+	}
+
 	// Inventory endpoints - using the real handlers
-	mux.HandleFunc("GET "+apiV1+"/inventory/{id}", deps.inventoryHandler.GetInventory)
-	mux.HandleFunc("GET "+apiV1+"/inventory", deps.inventoryHandler.ListInventory)
-	mux.HandleFunc("POST "+apiV1+"/inventory", deps.inventoryHandler.CreateInventory)
-	mux.HandleFunc("PUT "+apiV1+"/inventory/{id}", deps.inventoryHandler.UpdateInventory)
-	mux.HandleFunc("DELETE "+apiV1+"/inventory/{id}", deps.inventoryHandler.DeleteInventory)
+	mux.Handle("GET "+apiV1+"/inventory/{id}", read(deps.inventoryHandler.GetInventory))
+	mux.Handle("GET "+apiV1+"/inventory", read(deps.inventoryHandler.ListInventory))
+	mux.Handle("POST "+apiV1+"/inventory", write(deps.inventoryHandler.CreateInventory))
+	mux.Handle("PUT "+apiV1+"/inventory/{id}", write(deps.inventoryHandler.UpdateInventory))
+	mux.Handle("PATCH "+apiV1+"/inventory/{id}", write(deps.inventoryHandler.PatchInventory))
+	mux.Handle("PATCH "+apiV1+"/inventory/bulk", write(deps.inventoryHandler.BulkUpdateInventory))
+	mux.Handle("POST "+apiV1+"/inventory/reclassify", write(deps.inventoryHandler.ReclassifyInventory))
+	mux.Handle("DELETE "+apiV1+"/inventory/{id}", write(deps.inventoryHandler.DeleteInventory))
+	mux.Handle("POST "+apiV1+"/inventory/{id}/restore", write(deps.inventoryHandler.RestoreInventory))
+	mux.Handle("GET "+apiV1+"/inventory/{id}/history", read(deps.inventoryHandler.GetInventoryHistory))
+	mux.Handle("GET "+apiV1+"/inventory/{id}/qr", read(deps.qrCodeHandler.GetQRCode))
+	mux.Handle("POST "+apiV1+"/inventory/qr", write(deps.qrCodeHandler.GenerateQRCodes))
+	mux.Handle("POST "+apiV1+"/inventory/{id}/sale", write(deps.platformHandler.RecordSale))
+	mux.Handle("GET "+apiV1+"/invoices", read(deps.inventoryHandler.ListInvoices))
+	mux.Handle("GET "+apiV1+"/invoices/{invoiceId}/items", read(deps.inventoryHandler.GetInvoiceItems))
+	mux.Handle("GET "+apiV1+"/storage/summary", read(deps.inventoryHandler.StorageSummary))
 
 	// Import endpoints
-	mux.HandleFunc("POST "+apiV1+"/import/pdf", deps.importHandler.ImportPDF)
-	mux.HandleFunc("POST "+apiV1+"/import/excel", deps.importHandler.ImportExcel)
-	mux.HandleFunc("POST "+apiV1+"/import/batch", deps.importHandler.ImportBatch)
-	mux.HandleFunc("GET "+apiV1+"/import/status/{jobId}", deps.importHandler.ImportStatus)
+	mux.Handle("POST "+apiV1+"/import/pdf", write(deps.importHandler.ImportPDF))
+	mux.Handle("POST "+apiV1+"/import/excel", write(deps.importHandler.ImportExcel))
+	mux.Handle("POST "+apiV1+"/import/batch", write(deps.importHandler.ImportBatch))
+	mux.Handle("GET "+apiV1+"/import/status/{jobId}", read(deps.importHandler.ImportStatus))
+	mux.Handle("DELETE "+apiV1+"/import/{jobId}", write(deps.importHandler.CancelImport))
 
 	// Export endpoints
-	mux.HandleFunc("GET "+apiV1+"/export/excel", deps.exportHandler.ExportExcel)
-	mux.HandleFunc("GET "+apiV1+"/export/json", deps.exportHandler.ExportJSON)
-	mux.HandleFunc("GET "+apiV1+"/export/pdf", deps.exportHandler.ExportPDF)
+	mux.Handle("GET "+apiV1+"/export/excel", read(deps.exportHandler.ExportExcel))
+	mux.Handle("GET "+apiV1+"/export/json", read(deps.exportHandler.ExportJSON))
+	mux.Handle("GET "+apiV1+"/export/csv", read(deps.exportHandler.ExportCSV))
+	mux.Handle("GET "+apiV1+"/export/pdf", read(deps.exportHandler.ExportPDF))
+	mux.Handle("GET "+apiV1+"/export/labels", read(deps.qrCodeHandler.ExportLabels))
 
 	// Dashboard endpoints
-	mux.HandleFunc("GET "+apiV1+"/dashboard", deps.dashboardHandler.GetDashboard)
-	mux.HandleFunc("GET "+apiV1+"/dashboard/analytics", deps.dashboardHandler.GetAnalytics)
-
-	// Platform listing endpoints (placeholder handlers for now)
-	mux.HandleFunc("GET "+apiV1+"/platforms/{platform}/listings", handlePlatformListings)
-	mux.HandleFunc("POST "+apiV1+"/platforms/{platform}/list", handleCreateListing)
-	mux.HandleFunc("PUT "+apiV1+"/platforms/{platform}/listings/{id}", handleUpdateListing)
+	mux.Handle("GET "+apiV1+"/dashboard", read(deps.dashboardHandler.GetDashboard))
+	mux.Handle("GET "+apiV1+"/dashboard/analytics", read(deps.dashboardHandler.GetAnalytics))
+	mux.Handle("GET "+apiV1+"/dashboard/aging", read(deps.dashboardHandler.GetAgingInventory))
+	mux.Handle("GET "+apiV1+"/dashboard/variance", read(deps.dashboardHandler.GetValueVariance))
+
+	// Platform listing endpoints
+	mux.Handle("GET "+apiV1+"/platforms/{platform}/listings", read(deps.platformHandler.ListListings))
+	mux.Handle("POST "+apiV1+"/platforms/{platform}/list", write(deps.platformHandler.CreateListing))
+	mux.Handle("PUT "+apiV1+"/platforms/{platform}/listings/{id}", write(deps.platformHandler.UpdateListing))
+
+	// Admin endpoints, gated by the admin API key
+	adminAuth := middleware.AdminAuth(cfg.Security.AdminAPIKey)
+	mux.Handle("GET "+apiV1+"/admin/category-mappings/ebay", adminAuth(http.HandlerFunc(deps.categoryHandler.ListEbayMappings)))
+	mux.Handle("PUT "+apiV1+"/admin/category-mappings/ebay/{category}", adminAuth(http.HandlerFunc(deps.categoryHandler.UpdateEbayMapping)))
+	mux.Handle("POST "+apiV1+"/admin/refresh-views", adminAuth(http.HandlerFunc(deps.adminHandler.RefreshViews)))
+	mux.Handle("GET "+apiV1+"/admin/queues", adminAuth(http.HandlerFunc(deps.queueHandler.ListQueues)))
+	mux.Handle("GET "+apiV1+"/admin/queues/{queue}/archived", adminAuth(http.HandlerFunc(deps.queueHandler.ListArchivedTasks)))
+	mux.Handle("POST "+apiV1+"/admin/queues/{queue}/tasks/{id}/retry", adminAuth(http.HandlerFunc(deps.queueHandler.RetryTask)))
 
 	// Search endpoint
-	mux.HandleFunc("GET "+apiV1+"/search", handleSearch)
+	mux.Handle("GET "+apiV1+"/search", read(deps.searchHandler.Search))
+	mux.Handle("GET "+apiV1+"/search/suggest", read(deps.searchHandler.Suggest))
 
 	// File serving with wildcard
 	mux.HandleFunc("GET "+apiV1+"/files/{path...}", handleFiles)
 
 	// Metrics endpoint
 	if cfg.Server.EnableMetrics {
-		// mux.Handle("GET /metrics", promhttp.Handler())
+		prometheus.MustRegister(db.NewPoolCollector(deps.database.Stats))
+		mux.Handle("GET /metrics", promhttp.Handler())
 	}
 
 	// pprof endpoints (development only)
@@ -366,31 +572,6 @@ func registerRoutes(mux *http.ServeMux, deps *dependencies, slogger *slog.Logger
 }
 
 // Placeholder handlers for unimplemented endpoints
-func handlePlatformListings(w http.ResponseWriter, r *http.Request) {
-	platform := r.PathValue("platform")
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, `{"message": "Listings for platform %s"}`, platform)
-}
-
-func handleCreateListing(w http.ResponseWriter, r *http.Request) {
-	platform := r.PathValue("platform")
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, `{"message": "Create listing on %s"}`, platform)
-}
-
-func handleUpdateListing(w http.ResponseWriter, r *http.Request) {
-	platform := r.PathValue("platform")
-	id := r.PathValue("id")
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, `{"message": "Update listing %s on %s"}`, id, platform)
-}
-
-func handleSearch(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, `{"message": "Search results for: %s"}`, query)
-}
-
 func handleFiles(w http.ResponseWriter, r *http.Request) {
 	path := r.PathValue("path")
 	w.Header().Set("Content-Type", "application/json")
@@ -400,12 +581,15 @@ func handleFiles(w http.ResponseWriter, r *http.Request) {
 func runMigrations(ctx context.Context, cfg *config.Config, slogger *slog.Logger) error {
 	slogger.Info("running database migrations")
 
-	migrationConfig := &db.MigrationConfig{
+	return db.RunMigrationsWithRetry(ctx, migrationConfig(cfg), slogger, 3)
+}
+
+// migrationConfig describes where migrations live and how they are tracked
+func migrationConfig(cfg *config.Config) *db.MigrationConfig {
+	return &db.MigrationConfig{
 		DatabaseURL: cfg.GetDatabaseURL(),
 		SourcePath:  cfg.Database.MigrationPath,
 		TableName:   "schema_migrations",
 		SchemaName:  "public",
 	}
-
-	return db.RunMigrationsWithRetry(ctx, migrationConfig, slogger, 3)
 }
@@ -0,0 +1,139 @@
+// cmd/api/reload.go
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/ammerola/resell-be/internal/handlers/middleware"
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// reloadTimeout bounds re-reading the configuration, including secrets, on SIGHUP
+const reloadTimeout = 30 * time.Second
+
+// reloader applies configuration reloads to the running API server
+type reloader struct {
+	running        *config.Config
+	allowMissing   bool
+	logger         *logger.Logger
+	middleware     *middleware.Reloadable
+	rateLimiter    *middleware.RateLimiter
+	trustedProxies []*net.IPNet
+}
+
+// Reload re-reads the configuration and applies it, keeping the running configuration when
+// the new one doesn't load or validate
+func (r *reloader) Reload(ctx context.Context) {
+	// Cancelling the context also stops any secrets refresh started by the loader
+	ctx, cancel := context.WithTimeout(ctx, reloadTimeout)
+	defer cancel()
+
+	next, err := config.NewConfigLoader(r.logger.Logger).AllowMissing(r.allowMissing).Load(ctx)
+	if err != nil {
+		r.logger.Error("failed to reload configuration, keeping the running configuration",
+			slog.String("error", err.Error()))
+		return
+	}
+
+	r.apply(next)
+}
+
+// apply swaps in the settings that are safe to change while serving: the log level, rate
+// limits and CORS. Other changes are logged as needing a restart and aren't applied.
+func (r *reloader) apply(next *config.Config) {
+	applied := *r.running
+
+	if next.App.LogLevel != applied.App.LogLevel {
+		r.logger.SetLevel(next.App.LogLevel)
+		r.logger.Info("log level reloaded",
+			slog.String("from", applied.App.LogLevel),
+			slog.String("to", next.App.LogLevel))
+	}
+
+	if !reflect.DeepEqual(applied.Security, withHotSettings(applied, next).Security) {
+		r.middleware.Reload(reloadableMiddleware(next, r.rateLimiter, r.trustedProxies))
+		r.logger.Info("rate limits and CORS reloaded",
+			slog.Int("rate_limit_requests", next.Security.RateLimitRequests),
+			slog.Int("rate_limit_burst", next.Security.RateLimitBurst),
+			slog.Any("allowed_origins", next.Security.AllowedOrigins))
+	}
+
+	applied = withHotSettings(applied, next)
+	if sections := changedSections(applied, *next); len(sections) > 0 {
+		r.logger.Warn("configuration changes require restart",
+			slog.Any("sections", sections))
+	}
+
+	r.running = &applied
+}
+
+// withHotSettings returns cfg with the settings that can be reloaded taken from next
+func withHotSettings(cfg config.Config, next *config.Config) config.Config {
+	cfg.App.LogLevel = next.App.LogLevel
+	cfg.Logging.Level = next.Logging.Level
+
+	cfg.Security.RateLimitRequests = next.Security.RateLimitRequests
+	cfg.Security.RateLimitDuration = next.Security.RateLimitDuration
+	cfg.Security.RateLimitBurst = next.Security.RateLimitBurst
+	cfg.Security.RateLimitRoutes = next.Security.RateLimitRoutes
+	cfg.Security.AllowedOrigins = next.Security.AllowedOrigins
+	cfg.Security.CORSAllowedMethods = next.Security.CORSAllowedMethods
+	cfg.Security.CORSAllowedHeaders = next.Security.CORSAllowedHeaders
+	cfg.Security.CORSAllowCredentials = next.Security.CORSAllowCredentials
+
+	// Rotated passwords are applied by the secrets watcher
+	cfg.Database.Password = next.Database.Password
+	cfg.Redis.Password = next.Redis.Password
+	cfg.Asynq.RedisPassword = next.Asynq.RedisPassword
+
+	return cfg
+}
+
+// changedSections names the top-level configuration sections that differ
+func changedSections(a, b config.Config) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	var sections []string
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			sections = append(sections, va.Type().Field(i).Name)
+		}
+	}
+	return sections
+}
+
+// reloadableMiddleware builds the rate limiting and CORS middleware from cfg. The rules are
+// updated on limiter rather than replaced, so clients keep their remaining allowance.
+func reloadableMiddleware(cfg *config.Config, limiter *middleware.RateLimiter, trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	var rateLimit func(http.Handler) http.Handler
+	if cfg.Security.RateLimitRequests > 0 {
+		limiter.Update(rateLimitConfig(cfg, trustedProxies))
+		rateLimit = limiter.Middleware
+	}
+
+	var cors func(http.Handler) http.Handler
+	if len(cfg.Security.AllowedOrigins) > 0 {
+		cors = middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.Security.AllowedOrigins,
+			AllowedMethods:   cfg.Security.CORSAllowedMethods,
+			AllowedHeaders:   cfg.Security.CORSAllowedHeaders,
+			AllowCredentials: cfg.Security.CORSAllowCredentials,
+		})
+	}
+
+	return func(next http.Handler) http.Handler {
+		if rateLimit != nil {
+			next = rateLimit(next)
+		}
+		if cors != nil {
+			next = cors(next)
+		}
+		return next
+	}
+}
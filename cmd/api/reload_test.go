@@ -0,0 +1,97 @@
+// cmd/api/reload_test.go
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ammerola/resell-be/internal/handlers/middleware"
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+func newTestReloader(cfg *config.Config) (*reloader, http.Handler) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	limiter := middleware.NewRateLimiter(rateLimitConfig(cfg, nil))
+	reloadable := middleware.NewReloadable(ok, reloadableMiddleware(cfg, limiter, nil))
+
+	return &reloader{
+		running:     cfg,
+		logger:      logger.NewLogger(&logger.LogConfig{Level: cfg.App.LogLevel, Format: "json", Output: "stderr"}),
+		middleware:  reloadable,
+		rateLimiter: limiter,
+	}, reloadable
+}
+
+func TestReloader_ChangesLogLevel(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := helpers.LoadTestConfig()
+	cfg.App.LogLevel = "warn"
+	r, _ := newTestReloader(cfg)
+	assert.False(t, r.logger.Enabled(ctx, slog.LevelDebug))
+
+	next := *cfg
+	next.App.LogLevel = "debug"
+	r.apply(&next)
+
+	assert.True(t, r.logger.Enabled(ctx, slog.LevelDebug), "the running logger picks up the new level")
+	assert.Equal(t, slog.LevelDebug, r.logger.Level())
+	assert.Equal(t, "debug", r.running.App.LogLevel)
+}
+
+func TestReloader_SwapsRateLimit(t *testing.T) {
+	cfg := helpers.LoadTestConfig()
+	cfg.Security.RateLimitRequests = 1
+	cfg.Security.RateLimitBurst = 1
+	cfg.Security.RateLimitDuration = time.Hour
+	r, handler := newTestReloader(cfg)
+
+	get := func(client string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory", nil)
+		req.RemoteAddr = client + ":4321"
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, get("203.0.113.7"))
+	assert.Equal(t, http.StatusTooManyRequests, get("203.0.113.7"))
+
+	// One token every 10ms with room for 100
+	next := *cfg
+	next.Security.RateLimitRequests = 100
+	next.Security.RateLimitBurst = 100
+	next.Security.RateLimitDuration = time.Second
+	r.apply(&next)
+	assert.Equal(t, 100, r.running.Security.RateLimitRequests)
+
+	// A reload doesn't refill the exhausted bucket, but it now refills at the new rate
+	assert.Equal(t, http.StatusTooManyRequests, get("203.0.113.7"), "a reload must not reset the limit")
+	assert.Eventually(t, func() bool { return get("203.0.113.7") == http.StatusOK }, time.Second, 20*time.Millisecond)
+
+	// New clients get the new burst
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, http.StatusOK, get("198.51.100.4"))
+	}
+}
+
+func TestReloader_KeepsRestartOnlySettings(t *testing.T) {
+	cfg := helpers.LoadTestConfig()
+	r, _ := newTestReloader(cfg)
+
+	next := *cfg
+	next.Server.Port = "9999"
+	next.Database.Host = "db.internal"
+	r.apply(&next)
+
+	assert.Equal(t, cfg.Server.Port, r.running.Server.Port)
+	assert.Equal(t, cfg.Database.Host, r.running.Database.Host)
+	assert.Equal(t, []string{"Database", "Server"}, changedSections(*r.running, next))
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -11,8 +11,13 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/services"
+	"github.com/ammerola/resell-be/internal/pkg/keywords"
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,48 +26,6 @@ import (
 	"github.com/tealeg/xlsx/v3"
 )
 
-// Enums matching database schema
-type ItemCategory string
-
-const (
-	CategoryAntiques     ItemCategory = "antiques"
-	CategoryArt          ItemCategory = "art"
-	CategoryBooks        ItemCategory = "books"
-	CategoryCeramics     ItemCategory = "ceramics"
-	CategoryChina        ItemCategory = "china"
-	CategoryClothing     ItemCategory = "clothing"
-	CategoryCoins        ItemCategory = "coins"
-	CategoryCollectibles ItemCategory = "collectibles"
-	CategoryElectronics  ItemCategory = "electronics"
-	CategoryFurniture    ItemCategory = "furniture"
-	CategoryGlass        ItemCategory = "glass"
-	CategoryJewelry      ItemCategory = "jewelry"
-	CategoryLinens       ItemCategory = "linens"
-	CategoryMemorabilia  ItemCategory = "memorabilia"
-	CategoryMusical      ItemCategory = "musical"
-	CategoryPottery      ItemCategory = "pottery"
-	CategorySilver       ItemCategory = "silver"
-	CategoryStamps       ItemCategory = "stamps"
-	CategoryTools        ItemCategory = "tools"
-	CategoryToys         ItemCategory = "toys"
-	CategoryVintage      ItemCategory = "vintage"
-	CategoryOther        ItemCategory = "other"
-)
-
-type ItemCondition string
-
-const (
-	ConditionMint        ItemCondition = "mint"
-	ConditionExcellent   ItemCondition = "excellent"
-	ConditionVeryGood    ItemCondition = "very_good"
-	ConditionGood        ItemCondition = "good"
-	ConditionFair        ItemCondition = "fair"
-	ConditionPoor        ItemCondition = "poor"
-	ConditionRestoration ItemCondition = "restoration"
-	ConditionParts       ItemCondition = "parts"
-	ConditionUnknown     ItemCondition = "unknown"
-)
-
 // InventoryItem represents a single inventory item
 type InventoryItem struct {
 	LotID           uuid.UUID
@@ -70,8 +33,8 @@ type InventoryItem struct {
 	AuctionID       int
 	ItemName        string
 	Description     string
-	Category        ItemCategory
-	Condition       ItemCondition
+	Category        domain.ItemCategory
+	Condition       domain.ItemCondition
 	Quantity        int
 	BidAmount       decimal.Decimal
 	BuyersPremium   decimal.Decimal
@@ -81,6 +44,7 @@ type InventoryItem struct {
 	CostPerItem     decimal.Decimal
 	AcquisitionDate time.Time
 	Keywords        []string
+	Notes           string
 }
 
 // AuctionInfo holds auction metadata
@@ -90,128 +54,123 @@ type AuctionInfo struct {
 	Date                 time.Time
 	BuyersPremiumPercent float64
 	SalesTaxPercent      float64
+	PremiumSchedule      PremiumSchedule
 }
 
-// CategoryClassifier handles intelligent categorization
-type CategoryClassifier struct {
-	categoryKeywords  map[ItemCategory][]string
-	conditionKeywords map[ItemCondition][]string
-}
-
-func NewCategoryClassifier() *CategoryClassifier {
-	return &CategoryClassifier{
-		categoryKeywords: map[ItemCategory][]string{
-			CategoryAntiques: {"antique", "victorian", "edwardian", "georgian", "art deco",
-				"art nouveau", "mid century", "mcm", "vintage"},
-			CategoryArt: {"painting", "print", "lithograph", "etching", "drawing", "framed",
-				"sculpture", "statue", "canvas", "watercolor", "oil painting", "serigraph"},
-			CategoryBooks: {"book", "volume", "edition", "manuscript", "atlas",
-				"encyclopedia", "novel", "hardcover", "paperback"},
-			CategoryCeramics: {"ceramic", "porcelain", "pottery", "stoneware", "earthenware",
-				"terracotta", "faience", "majolica", "capodimonte", "capidimonte"},
-			CategoryChina: {"china", "dinnerware", "plate", "bowl", "teacup", "saucer",
-				"serving", "platter", "tureen", "gravy boat", "ming"},
-			CategoryClothing: {"dress", "shirt", "pants", "jacket", "coat", "shoes",
-				"hat", "scarf", "vintage clothing", "designer"},
-			CategoryCoins: {"coin", "numismatic", "currency", "mint", "proof",
-				"commemorative", "gold coin", "silver coin"},
-			CategoryCollectibles: {"collectible", "limited edition", "memorabilia", "trading card",
-				"figurine", "model", "diecast", "precious moments", "danbury mint", "enesco", "lladro"},
-			CategoryElectronics: {"electronic", "computer", "phone", "camera", "stereo",
-				"radio", "television", "console", "gadget", "sewing machine", "grinder"},
-			CategoryFurniture: {"table", "chair", "desk", "cabinet", "dresser", "sofa", "lamp",
-				"bench", "ottoman", "bookcase", "sideboard", "chest", "console", "barstool", "shelves"},
-			CategoryGlass: {"glass", "crystal", "cut glass", "pressed glass", "blown glass",
-				"stained glass", "depression glass", "carnival glass", "art glass", "vase", "bowl"},
-			CategoryJewelry: {"jewelry", "ring", "necklace", "bracelet", "earring",
-				"brooch", "pendant", "gold", "silver", "diamond", "gemstone", "sterling"},
-			CategoryLinens: {"linen", "tablecloth", "napkin", "doily", "runner",
-				"bedding", "quilt", "blanket", "textile", "fabric"},
-			CategoryMusical: {"musical", "instrument", "piano", "guitar", "violin",
-				"trumpet", "saxophone", "drum", "sheet music", "music box"},
-			CategorySilver: {"sterling", "silver", "silverplate", "flatware", "hollowware",
-				"tea set", "candelabra", "serving piece"},
-			CategoryStamps: {"stamp", "philatelic", "postage", "first day cover",
-				"postmark", "album"},
-			CategoryTools: {"tool", "drill", "saw", "hammer", "wrench", "pliers",
-				"vintage tool", "woodworking", "machinist", "grinder"},
-			CategoryToys: {"toy", "doll", "action figure", "game", "puzzle",
-				"teddy bear", "train set", "lego", "vintage toy", "lionel"},
-			CategoryVintage: {"brass", "cherub", "andirons", "bookend", "dolphin", "copper", "bronze"},
-		},
-		conditionKeywords: map[ItemCondition][]string{
-			ConditionMint:        {"mint", "pristine", "perfect", "new"},
-			ConditionExcellent:   {"excellent", "near mint", "superb"},
-			ConditionVeryGood:    {"very good", "vg", "great"},
-			ConditionGood:        {"good", "nice", "decent"},
-			ConditionFair:        {"fair", "acceptable", "wear"},
-			ConditionPoor:        {"poor", "damaged", "broken", "torn"},
-			ConditionRestoration: {"restored", "repaired", "refinished"},
-			ConditionParts:       {"parts", "repair", "incomplete", "as-is"},
-		},
-	}
+// PremiumBand is one tier of a buyer's premium schedule.
+// UpTo is the bid amount where the band ends; zero means no upper limit.
+type PremiumBand struct {
+	UpTo    float64
+	Percent float64
 }
 
-func (c *CategoryClassifier) Classify(text string) (ItemCategory, ItemCondition) {
-	textLower := strings.ToLower(text)
+// PremiumSchedule holds buyer's premium bands ordered by threshold
+type PremiumSchedule []PremiumBand
 
-	// Find category with highest keyword match score
-	categoryScores := make(map[ItemCategory]int)
-	for category, keywords := range c.categoryKeywords {
-		score := 0
-		for _, kw := range keywords {
-			if strings.Contains(textLower, kw) {
-				score++
-			}
-		}
-		if score > 0 {
-			categoryScores[category] = score
-		}
-	}
+// Premium computes the buyer's premium for a bid, charging each band's rate
+// on the portion of the bid that falls inside that band
+func (s PremiumSchedule) Premium(bid decimal.Decimal) decimal.Decimal {
+	premium := decimal.Zero
+	lower := decimal.Zero
 
-	category := CategoryOther
-	maxScore := 0
-	for cat, score := range categoryScores {
-		if score > maxScore {
-			category = cat
-			maxScore = score
+	for i, band := range s {
+		if !bid.GreaterThan(lower) {
+			break
 		}
-	}
 
-	// Find condition
-	condition := ConditionUnknown
-	for cond, keywords := range c.conditionKeywords {
-		for _, kw := range keywords {
-			if strings.Contains(textLower, kw) {
-				condition = cond
-				break
+		upper := bid
+		last := i == len(s)-1 || band.UpTo <= 0
+		if !last {
+			upTo := decimal.NewFromFloat(band.UpTo)
+			if upTo.LessThan(bid) {
+				upper = upTo
 			}
 		}
-		if condition != ConditionUnknown {
+
+		rate := decimal.NewFromFloat(band.Percent / 100)
+		premium = premium.Add(upper.Sub(lower).Mul(rate))
+
+		if last {
 			break
 		}
+		lower = upper
 	}
 
-	return category, condition
+	return premium.Round(2)
+}
+
+// premiumSchedule returns the auction's tiered schedule, or a single flat band
+func (a AuctionInfo) premiumSchedule() PremiumSchedule {
+	if len(a.PremiumSchedule) > 0 {
+		return a.PremiumSchedule
+	}
+	return PremiumSchedule{{Percent: a.BuyersPremiumPercent}}
 }
 
+// lowConfidenceThreshold marks classifications that should be reviewed by hand
+const lowConfidenceThreshold = 0.2
+
+// maxKeywords caps the search keywords stored per item
+const maxKeywords = 20
+
+// Invoice layouts supported by the extractor
+const (
+	LayoutSingleColumn = pdfparse.LayoutSingleColumn
+	LayoutTwoColumn    = pdfparse.LayoutTwoColumn
+)
+
 // PDFExtractor handles PDF parsing with enhanced logic
 type PDFExtractor struct {
-	classifier *CategoryClassifier
+	classifier *services.CategoryClassifier
 	logger     *slog.Logger
 	auctions   map[string]AuctionInfo
 	db         *pgxpool.Pool
+	layout     string
+	profiles   *pdfparse.Profiles
+	profile    string
+	password   string
 }
 
 func NewPDFExtractor(db *pgxpool.Pool, logger *slog.Logger) *PDFExtractor {
 	return &PDFExtractor{
-		classifier: NewCategoryClassifier(),
+		classifier: services.NewCategoryClassifier(),
 		logger:     logger,
 		auctions:   make(map[string]AuctionInfo),
 		db:         db,
+		layout:     LayoutSingleColumn,
 	}
 }
 
+// SetLayout selects the invoice layout used when extracting items
+func (e *PDFExtractor) SetLayout(layout string) error {
+	switch layout {
+	case "", LayoutSingleColumn:
+		e.layout = LayoutSingleColumn
+	case LayoutTwoColumn:
+		e.layout = LayoutTwoColumn
+	default:
+		return fmt.Errorf("unsupported layout: %s", layout)
+	}
+	return nil
+}
+
+// SetProfiles sets the invoice profiles available to the extractor and the one to parse every
+// invoice with. An empty name picks the profile assigned to each invoice's auction, else the
+// default.
+func (e *PDFExtractor) SetProfiles(profiles *pdfparse.Profiles, name string) error {
+	if _, ok := profiles.Lookup(name); !ok {
+		return fmt.Errorf("unknown invoice profile %q (known: %s)", name, strings.Join(profiles.Names(), ", "))
+	}
+	e.profiles = profiles
+	e.profile = name
+	return nil
+}
+
+// SetPassword sets the password used to decrypt encrypted invoices
+func (e *PDFExtractor) SetPassword(password string) {
+	e.password = password
+}
+
 // LoadAuctions loads auction metadata from Excel file
 func (e *PDFExtractor) LoadAuctions(filepath string) error {
 	file, err := xlsx.OpenFile(filepath)
@@ -255,12 +214,35 @@ func (e *PDFExtractor) LoadAuctions(filepath string) error {
 		bpPercent, _ := strconv.ParseFloat(get(3), 64)
 		taxPercent, _ := strconv.ParseFloat(get(4), 64)
 
+		// Optional tier columns come in pairs after the tax column:
+		// the bid where the current band ends, then the next band's percent
+		schedule := PremiumSchedule{{Percent: bpPercent}}
+		for col := 5; get(col) != ""; col += 2 {
+			upTo, err := strconv.ParseFloat(get(col), 64)
+			if err != nil {
+				e.logger.Warn("Invalid premium tier threshold",
+					slog.String("invoice_id", invoiceID),
+					slog.String("value", get(col)))
+				break
+			}
+			nextPercent, err := strconv.ParseFloat(get(col+1), 64)
+			if err != nil {
+				e.logger.Warn("Invalid premium tier percent",
+					slog.String("invoice_id", invoiceID),
+					slog.String("value", get(col+1)))
+				break
+			}
+			schedule[len(schedule)-1].UpTo = upTo
+			schedule = append(schedule, PremiumBand{Percent: nextPercent})
+		}
+
 		e.auctions[invoiceID] = AuctionInfo{
 			AuctionID:            auctionID,
 			InvoiceID:            invoiceID,
 			Date:                 date,
 			BuyersPremiumPercent: bpPercent,
 			SalesTaxPercent:      taxPercent,
+			PremiumSchedule:      schedule,
 		}
 		return nil
 	})
@@ -287,13 +269,25 @@ func (e *PDFExtractor) ExtractItemsFromPDF(filepath string, invoiceID string) ([
 		return nil, fmt.Errorf("failed to extract text: %w", err)
 	}
 
-	// Parse items using the corrected logic
-	rawItems := e.extractItemsFromInvoice(textLines)
+	// Parse items between the invoice header and footer
+	profile, err := e.profiles.Resolve(e.profile, auctionInfo.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	parsed := pdfparse.Options{Layout: e.layout, Profile: profile}.ParseInvoice(textLines)
 
 	// Create inventory items
-	items := make([]InventoryItem, 0, len(rawItems))
-	for _, rawItem := range rawItems {
-		item := e.createInventoryItem(rawItem.description, rawItem.bid, invoiceID, auctionInfo)
+	items := make([]InventoryItem, 0, len(parsed))
+	for _, p := range parsed {
+		// Invoices list one item per line
+		item, err := e.createInventoryItem(p.Description, p.Bid.InexactFloat64(), 1, invoiceID, auctionInfo)
+		if err != nil {
+			e.logger.Warn("Skipping invoice item",
+				slog.String("invoice_id", invoiceID),
+				slog.String("description", p.Description),
+				slog.String("error", err.Error()))
+			continue
+		}
 		items = append(items, item)
 	}
 
@@ -305,7 +299,7 @@ func (e *PDFExtractor) ExtractItemsFromPDF(filepath string, invoiceID string) ([
 }
 
 func (e *PDFExtractor) extractTextLines(filepath string) ([]string, error) {
-	f, r, err := pdf.Open(filepath)
+	f, r, err := openPDF(filepath, e.password)
 	if err != nil {
 		return nil, err
 	}
@@ -335,126 +329,41 @@ func (e *PDFExtractor) extractTextLines(filepath string) ([]string, error) {
 	return textLines, nil
 }
 
-type rawItem struct {
-	description string
-	bid         float64
-}
+// ErrEncryptedPDF is returned when a PDF is encrypted and no valid password was supplied
+var ErrEncryptedPDF = errors.New("PDF is encrypted")
 
-// extractItemsFromInvoice - Fixed to match the working Python logic
-// extractItemsFromInvoice - robust line-buffering and zero-price support
-func (e *PDFExtractor) extractItemsFromInvoice(textLines []string) []rawItem {
-	var items []rawItem
-
-	// Header/footer and helpers
-	headerRe := regexp.MustCompile(`(?i)(LOT.*PRICE|LEAD.*ITEM.*PRICE)`)
-	dashRe := regexp.MustCompile(`-{7,}`)
-	footerRe := regexp.MustCompile(`(?i)(A payment of|SUBTOTAL)`)
-	// allow optional $ and thousands separators, anchored to end of line
-	priceRe := regexp.MustCompile(`\$?\s*\d{1,3}(?:,\d{3})*\.\d{2}\s*$`)
-
-	// Find start (line after header)
-	start := 0
-	for idx, line := range textLines {
-		if headerRe.MatchString(line) {
-			start = idx + 1
-			e.logger.Debug("Found header", slog.Int("line", idx))
-			break
-		}
-	}
-	if start == 0 {
-		e.logger.Warn("No header found, starting from beginning")
+// openPDF opens a PDF, decrypting it with password when the document is encrypted
+func openPDF(filepath, password string) (*os.File, *pdf.Reader, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	// Buffer description lines until we see a price
-	var pendingDesc []string
-
-	// helper to finalize one item
-	addItem := func(desc string, price float64) {
-		desc = cleanDescription(desc)
-		if strings.TrimSpace(desc) == "" {
-			return
-		}
-		items = append(items, rawItem{
-			description: desc,
-			bid:         price, // may be 0.00
-		})
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
 	}
 
-	for i := start; i < len(textLines); i++ {
-		line := strings.TrimSpace(textLines[i])
-		if line == "" {
-			continue
+	tried := false
+	r, err := pdf.NewReaderEncrypted(f, fi.Size(), func() string {
+		if tried {
+			return ""
 		}
-
-		// Hit footer: stop parsing items
-		if footerRe.MatchString(line) {
-			e.logger.Debug("Found footer, stopping", slog.String("line", line))
-			break
-		}
-
-		// Strip long filler dashes if present (keep left part as content)
-		if dashRe.MatchString(line) {
-			parts := dashRe.Split(line, 2)
-			line = strings.TrimSpace(parts[0])
-			if line == "" {
-				continue
+		tried = true
+		return password
+	})
+	if err != nil {
+		f.Close()
+		if errors.Is(err, pdf.ErrInvalidPassword) {
+			if password == "" {
+				return nil, nil, fmt.Errorf("%w: use -pdf-password to supply one", ErrEncryptedPDF)
 			}
+			return nil, nil, fmt.Errorf("%w: the password was rejected", ErrEncryptedPDF)
 		}
-
-		// If the line ends with a price, finalize the buffered description + inline desc fragment
-		if priceRe.MatchString(line) {
-			// Extract numeric price string
-			priceStr := strings.TrimSpace(priceRe.FindString(line))
-			price := parseCurrency(priceStr)
-
-			// Description fragment on same line (before the price)
-			descPart := strings.TrimSpace(priceRe.ReplaceAllString(line, ""))
-
-			// Some PDFs place lot/metadata between desc and price on the same line
-			// Example patterns like "18488 17" or "6607 28" or "131811 65 G2CG2C"
-			metaRe := regexp.MustCompile(`\b[0-9A-Z]{2,}(?:\s+[0-9A-Z]{1,}){0,3}$`)
-			descPart = strings.TrimSpace(metaRe.ReplaceAllString(descPart, ""))
-
-			// Merge: buffered + inline fragment
-			fullDesc := strings.Join(append(pendingDesc, descPart), " ")
-			fullDesc = strings.TrimSpace(fullDesc)
-
-			addItem(fullDesc, price)
-
-			// Reset buffer for next item
-			pendingDesc = pendingDesc[:0]
-			continue
-		}
-
-		// Otherwise, this is part of the description—buffer it
-		pendingDesc = append(pendingDesc, line)
+		return nil, nil, err
 	}
 
-	// Note: do NOT emit a trailing buffered item without a detected price.
-	// These invoices always have a SUBTOTAL after items; if we never saw a price,
-	// we likely buffered non-item text (headers/notes).
-	e.logger.Info("Extracted raw items", slog.Int("count", len(items)))
-	return items
-}
-
-func cleanDescription(desc string) string {
-	// Remove item IDs and lot numbers that might be embedded
-	desc = regexp.MustCompile(`\b\d{5,6}\s+\d{1,3}\s+[A-Z0-9]+\b`).ReplaceAllString(desc, "")
-
-	// Remove standalone numbers that are likely IDs
-	desc = regexp.MustCompile(`^\d+\s+`).ReplaceAllString(desc, "")
-	desc = regexp.MustCompile(`\s+\d{4,}$`).ReplaceAllString(desc, "")
-
-	// Remove multiple spaces
-	desc = regexp.MustCompile(`\s+`).ReplaceAllString(desc, " ")
-
-	// Remove dashes used as fillers
-	desc = regexp.MustCompile(`-{3,}`).ReplaceAllString(desc, " ")
-
-	// Clean up
-	desc = strings.TrimSpace(desc)
-
-	return desc
+	return f, r, nil
 }
 
 func (e *PDFExtractor) getAuctionInfo(invoiceID string) AuctionInfo {
@@ -472,24 +381,32 @@ func (e *PDFExtractor) getAuctionInfo(invoiceID string) AuctionInfo {
 	}
 }
 
-func (e *PDFExtractor) createInventoryItem(description string, bid float64, invoiceID string, auctionInfo AuctionInfo) InventoryItem {
-	// Convert to decimal for precision
-	bidDecimal := decimal.NewFromFloat(bid)
-
-	// Calculate costs
-	bpRate := decimal.NewFromFloat(auctionInfo.BuyersPremiumPercent / 100)
-	taxRate := decimal.NewFromFloat(auctionInfo.SalesTaxPercent / 100)
+// createInventoryItem builds the item for one invoice line. The bid is for the whole lot, so
+// the cost per item is its total cost split across quantity.
+func (e *PDFExtractor) createInventoryItem(description string, bid float64, quantity int, invoiceID string, auctionInfo AuctionInfo) (InventoryItem, error) {
+	// Calculate costs, charging the premium by the auction's schedule
+	costs := domain.InventoryItem{BidAmount: decimal.NewFromFloat(bid), Quantity: quantity}
+	costs.BuyersPremium = auctionInfo.premiumSchedule().Premium(costs.BidAmount)
+	costs.SalesTax = domain.CostRates{SalesTaxPercent: decimal.NewFromFloat(auctionInfo.SalesTaxPercent)}.
+		SalesTax(costs.BidAmount, costs.BuyersPremium)
+	if err := costs.CalculateCosts(nil); err != nil {
+		return InventoryItem{}, err
+	}
 
-	buyersPremium := bidDecimal.Mul(bpRate).Round(2)
-	subtotal := bidDecimal.Add(buyersPremium)
-	salesTax := subtotal.Mul(taxRate).Round(2)
-	totalCost := subtotal.Add(salesTax)
+	// Classify item, flagging weak matches for manual review
+	category := domain.CategoryOther
+	scores, condition, confidence := e.classifier.ClassifyWithScores(description)
+	if len(scores) > 0 {
+		category = scores[0].Category
+	}
 
-	// Classify item
-	category, condition := e.classifier.Classify(description)
+	var notes string
+	if confidence < lowConfidenceThreshold {
+		notes = fmt.Sprintf("review_needed: category confidence %.2f", confidence)
+	}
 
 	// Extract keywords
-	keywords := extractKeywords(description)
+	itemKeywords := keywords.Extract(description, maxKeywords)
 
 	// Generate item name
 	itemName := generateItemName(description)
@@ -502,15 +419,16 @@ func (e *PDFExtractor) createInventoryItem(description string, bid float64, invo
 		Description:     description,
 		Category:        category,
 		Condition:       condition,
-		Quantity:        1,
-		BidAmount:       bidDecimal,
-		BuyersPremium:   buyersPremium,
-		SalesTax:        salesTax,
-		TotalCost:       totalCost,
-		CostPerItem:     totalCost,
+		Quantity:        costs.Quantity,
+		BidAmount:       costs.BidAmount,
+		BuyersPremium:   costs.BuyersPremium,
+		SalesTax:        costs.SalesTax,
+		TotalCost:       costs.TotalCost,
+		CostPerItem:     costs.CostPerItem,
 		AcquisitionDate: auctionInfo.Date,
-		Keywords:        keywords,
-	}
+		Keywords:        itemKeywords,
+		Notes:           notes,
+	}, nil
 }
 
 // SaveItems persists inventory items to the database
@@ -536,13 +454,13 @@ func (e *PDFExtractor) SaveItems(ctx context.Context, items []InventoryItem) err
 			INSERT INTO inventory (
 				lot_id, invoice_id, auction_id, item_name, description,
 				category, condition, quantity, bid_amount, buyers_premium,
-				sales_tax, shipping_cost, acquisition_date, keywords
+				sales_tax, shipping_cost, acquisition_date, keywords, notes
 			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, '')
 			) ON CONFLICT (lot_id) DO NOTHING`,
 			item.LotID, item.InvoiceID, item.AuctionID, item.ItemName, item.Description,
 			item.Category, item.Condition, item.Quantity, item.BidAmount, item.BuyersPremium,
-			item.SalesTax, item.ShippingCost, item.AcquisitionDate, keywordsStr,
+			item.SalesTax, item.ShippingCost, item.AcquisitionDate, keywordsStr, item.Notes,
 		)
 	}
 
@@ -573,19 +491,6 @@ func (e *PDFExtractor) SaveItems(ctx context.Context, items []InventoryItem) err
 }
 
 // Helper functions
-func parseCurrency(val string) float64 {
-	// Remove dollar sign, commas, and spaces
-	cleaned := strings.ReplaceAll(val, "$", "")
-	cleaned = strings.ReplaceAll(cleaned, ",", "")
-	cleaned = strings.TrimSpace(cleaned)
-
-	result, err := strconv.ParseFloat(cleaned, 64)
-	if err != nil {
-		return 0.0
-	}
-	return result
-}
-
 func generateItemName(description string) string {
 	// Take first 60 characters or first sentence
 	name := description
@@ -617,36 +522,6 @@ func generateItemName(description string) string {
 	return strings.Join(words, " ")
 }
 
-func extractKeywords(description string) []string {
-	stopWords := map[string]bool{
-		"the": true, "a": true, "an": true, "and": true, "or": true,
-		"but": true, "in": true, "on": true, "at": true, "to": true,
-		"for": true, "of": true, "with": true, "by": true, "from": true,
-		"is": true, "was": true, "are": true, "were": true, "total": true,
-		"set": true, "lot": true, "pair": true,
-	}
-
-	// Extract words
-	wordRe := regexp.MustCompile(`\b[a-zA-Z]+\b`)
-	words := wordRe.FindAllString(strings.ToLower(description), -1)
-
-	// Filter and deduplicate
-	seen := make(map[string]bool)
-	var keywords []string
-
-	for _, word := range words {
-		if !stopWords[word] && len(word) > 2 && !seen[word] {
-			keywords = append(keywords, word)
-			seen[word] = true
-			if len(keywords) >= 20 {
-				break
-			}
-		}
-	}
-
-	return keywords
-}
-
 func main() {
 	// Parse flags
 	var (
@@ -656,9 +531,28 @@ func main() {
 		logLevel     = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
 		dryRun       = flag.Bool("dry-run", false, "Preview changes without modifying database")
 		force        = flag.Bool("force", false, "Reprocess all invoices")
+		layout       = flag.String("layout", LayoutSingleColumn, "Invoice layout (single-column, two-column)")
+		pdfPassword  = flag.String("pdf-password", "", "Password for encrypted PDF invoices")
+		profilesFile = flag.String("profiles", "", "JSON file of invoice profiles for other auction houses")
+		profile      = flag.String("profile", "", "Invoice profile for every PDF (default: the auction's profile, else default)")
+		singleFile   = flag.String("file", "", "Process only this PDF, without scanning -invoices or updating the state file")
+		invoiceID    = flag.String("invoice-id", "", "Invoice ID for -file (default: the file name without .pdf)")
+		concurrency  = flag.Int("concurrency", 1, "Number of PDFs to process at once, each in its own transaction")
+		reportFormat = flag.String("report", reportText, "Summary report format (text, json, csv)")
+		reportFile   = flag.String("report-file", "", "File for json and csv reports (default: seed_report.<format>)")
 	)
 	flag.Parse()
 
+	if err := validateReportFormat(*reportFormat); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *invoiceID != "" && *singleFile == "" {
+		fmt.Fprintln(os.Stderr, "-invoice-id can only be used with -file")
+		os.Exit(2)
+	}
+
 	// Setup logging
 	var slogLevel slog.Level
 	switch *logLevel {
@@ -704,6 +598,21 @@ func main() {
 
 	// Create extractor
 	extractor := NewPDFExtractor(db, logger)
+	if err := extractor.SetLayout(*layout); err != nil {
+		logger.Error("Invalid layout", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	extractor.SetPassword(*pdfPassword)
+
+	profiles, err := pdfparse.LoadProfiles(*profilesFile)
+	if err != nil {
+		logger.Error("Failed to load invoice profiles", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if err := extractor.SetProfiles(profiles, *profile); err != nil {
+		logger.Error("Invalid invoice profile", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
 	// Load auctions if file exists
 	if _, err := os.Stat(*auctionsFile); err == nil {
@@ -713,18 +622,38 @@ func main() {
 		}
 	}
 
-	// Load state
-	type SeederState struct {
-		ProcessedInvoices []string  `json:"processed_invoices"`
-		ProcessedCount    int       `json:"processed_count"`
-		LastUpdate        time.Time `json:"last_update"`
+	// Reprocess one invoice without scanning the directory or touching the state file
+	if *singleFile != "" {
+		id := *invoiceID
+		if id == "" {
+			id = strings.TrimSuffix(filepath.Base(*singleFile), ".pdf")
+		}
+
+		count, err := processFile(ctx, extractor, *singleFile, id, *dryRun)
+		if err != nil {
+			logger.Error("Failed to process invoice",
+				slog.String("invoice_id", id),
+				slog.String("error", err.Error()))
+			fmt.Printf("ERROR: Failed to process invoice_id:%s - %v\n", id, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("SUCCESS: Processed invoice_id:%s - %d items\n", id, count)
+		if *dryRun {
+			fmt.Println("\n[DRY RUN] No changes were made to the database")
+		}
+		return
 	}
 
-	var state SeederState
+	// Load state
+	state := newSeederState()
 	if !*force {
-		if stateData, err := os.ReadFile(*stateFile); err == nil {
-			json.Unmarshal(stateData, &state)
+		loaded, err := loadSeederState(*stateFile)
+		if err != nil {
+			logger.Error("Failed to load state, use -force to start over", slog.String("error", err.Error()))
+			os.Exit(1)
 		}
+		state = loaded
 	}
 
 	// Process PDFs
@@ -734,86 +663,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	totalProcessed := 0
-	totalItems := 0
-	failedInvoices := []string{}
-	successDetails := map[string]int{}
-
-	for i, pdfFile := range pdfFiles {
-		invoiceID := strings.TrimSuffix(filepath.Base(pdfFile), ".pdf")
-
-		// Progress indicator
-		fmt.Printf("PROGRESS: Processing %d/%d: %s\n", i+1, len(pdfFiles), invoiceID)
-
-		// Check if already processed
-		if !*force {
-			processed := false
-			for _, pid := range state.ProcessedInvoices {
-				if pid == invoiceID {
-					processed = true
-					break
-				}
-			}
-			if processed {
-				logger.Info("Skipping already processed invoice", slog.String("invoice_id", invoiceID))
-				continue
-			}
-		}
-
-		// Extract items
-		items, err := extractor.ExtractItemsFromPDF(pdfFile, invoiceID)
-		if err != nil {
-			logger.Error("Failed to extract items",
-				slog.String("invoice_id", invoiceID),
-				slog.String("error", err.Error()))
-			failedInvoices = append(failedInvoices, invoiceID)
-			fmt.Printf("ERROR: Failed to process invoice_id:%s - %v\n", invoiceID, err)
-			continue
-		}
-
-		if len(items) == 0 {
-			logger.Warn("No items extracted",
-				slog.String("invoice_id", invoiceID))
-			fmt.Printf("WARNING: No items found in invoice_id:%s\n", invoiceID)
-			failedInvoices = append(failedInvoices, fmt.Sprintf("%s (0 items)", invoiceID))
-			continue
-		}
-
-		// Save to database
-		if !*dryRun && len(items) > 0 {
-			if err := extractor.SaveItems(ctx, items); err != nil {
-				logger.Error("Failed to save items",
-					slog.String("invoice_id", invoiceID),
-					slog.String("error", err.Error()))
-				failedInvoices = append(failedInvoices, invoiceID)
-				fmt.Printf("ERROR: Failed to save invoice_id:%s - %v\n", invoiceID, err)
-				continue
-			}
-		}
-
-		fmt.Printf("SUCCESS: Processed invoice_id:%s - %d items\n", invoiceID, len(items))
-		successDetails[invoiceID] = len(items)
-
-		totalProcessed++
-		totalItems += len(items)
-
-		// Update state
-		state.ProcessedInvoices = append(state.ProcessedInvoices, invoiceID)
-		state.ProcessedCount = len(state.ProcessedInvoices)
-		state.LastUpdate = time.Now()
-
-		// Save state periodically
-		if !*dryRun && i%10 == 0 {
-			stateData, _ := json.MarshalIndent(state, "", "  ")
-			os.WriteFile(*stateFile, stateData, 0644)
-		}
+	run := &seedRun{
+		logger:      logger,
+		state:       state,
+		concurrency: *concurrency,
+		process: func(path, invoiceID string) (int, error) {
+			return processFile(ctx, extractor, path, invoiceID, *dryRun)
+		},
 	}
-
-	// Save final state
 	if !*dryRun {
-		stateData, _ := json.MarshalIndent(state, "", "  ")
-		os.WriteFile(*stateFile, stateData, 0644)
+		run.persist = func() error { return state.Save(*stateFile) }
 	}
+	summary := run.Run(pdfFiles)
+	totalProcessed, totalItems, failedInvoices := summary.processed, summary.items, summary.failed
 
 	// Summary
 	fmt.Println("\n" + strings.Repeat("=", 60))
@@ -826,10 +688,10 @@ func main() {
 	}
 
 	// Show successful extractions
-	if len(successDetails) > 0 {
-		fmt.Printf("\n✅ Successfully Processed (%d invoices):\n", len(successDetails))
-		for inv, count := range successDetails {
-			fmt.Printf("  - %s: %d items\n", inv, count)
+	if len(summary.succeeded) > 0 {
+		fmt.Printf("\n✅ Successfully Processed (%d invoices):\n", len(summary.succeeded))
+		for _, result := range summary.succeeded {
+			fmt.Printf("  - %s: %d items\n", result.invoiceID, result.items)
 		}
 	}
 
@@ -845,11 +707,161 @@ func main() {
 		slog.Int("items_created", totalItems),
 		slog.Int("failed_invoices", len(failedInvoices)))
 
+	if *reportFormat != reportText {
+		path := *reportFile
+		if path == "" {
+			path = "seed_report." + *reportFormat
+		}
+		if err := writeReportFile(path, *reportFormat, newSeedReport(summary, *dryRun)); err != nil {
+			logger.Error("Failed to write report", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		fmt.Printf("\nReport written to %s\n", path)
+	}
+
 	if *dryRun {
 		fmt.Println("\n[DRY RUN] No changes were made to the database")
 	}
 }
 
+// errNoItems is returned by processFile when an invoice yields no items
+var errNoItems = errors.New("no items found")
+
+// processFile extracts the items of one invoice and, unless dryRun, saves them. It returns the
+// number of items extracted.
+func processFile(ctx context.Context, extractor *PDFExtractor, path, invoiceID string, dryRun bool) (int, error) {
+	items, err := extractor.ExtractItemsFromPDF(path, invoiceID)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, errNoItems
+	}
+
+	if !dryRun {
+		if err := extractor.SaveItems(ctx, items); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+// seedRun processes invoices, skipping those already in state and persisting the state after
+// each one so an interrupted run loses no progress
+type seedRun struct {
+	logger      *slog.Logger
+	state       *seederState
+	concurrency int // files processed at once; 1 or less processes them in order
+	process     func(path, invoiceID string) (int, error)
+	persist     func() error // nil in dry runs
+}
+
+// invoiceResult is the outcome of processing one invoice
+type invoiceResult struct {
+	invoiceID string
+	items     int
+	skipped   bool
+	err       error
+}
+
+// seedSummary totals a seedRun, listing invoices in input order whatever order they finished in
+type seedSummary struct {
+	processed int
+	items     int
+	failed    []string
+	succeeded []invoiceResult
+	results   []invoiceResult // every invoice, skipped ones included
+}
+
+// Run processes pdfFiles and returns the totals
+func (r *seedRun) Run(pdfFiles []string) seedSummary {
+	results := make([]invoiceResult, len(pdfFiles))
+
+	workers := min(r.concurrency, len(pdfFiles))
+	if workers <= 1 {
+		for i, pdfFile := range pdfFiles {
+			results[i] = r.processOne(i, len(pdfFiles), pdfFile)
+		}
+	} else {
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					results[i] = r.processOne(i, len(pdfFiles), pdfFiles[i])
+				}
+			}()
+		}
+		for i := range pdfFiles {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	summary := seedSummary{failed: []string{}, results: results}
+	for _, result := range results {
+		switch {
+		case result.skipped:
+		case errors.Is(result.err, errNoItems):
+			summary.failed = append(summary.failed, fmt.Sprintf("%s (0 items)", result.invoiceID))
+		case result.err != nil:
+			summary.failed = append(summary.failed, result.invoiceID)
+		default:
+			summary.succeeded = append(summary.succeeded, result)
+			summary.processed++
+			summary.items += result.items
+		}
+	}
+	return summary
+}
+
+// processOne processes the i-th of total invoices and records it in state when it succeeds
+func (r *seedRun) processOne(i, total int, pdfFile string) invoiceResult {
+	invoiceID := strings.TrimSuffix(filepath.Base(pdfFile), ".pdf")
+	result := invoiceResult{invoiceID: invoiceID}
+
+	// Progress indicator
+	fmt.Printf("PROGRESS: Processing %d/%d: %s\n", i+1, total, invoiceID)
+
+	// Check if already processed
+	if r.state.Processed(invoiceID) {
+		r.logger.Info("Skipping already processed invoice", slog.String("invoice_id", invoiceID))
+		result.skipped = true
+		return result
+	}
+
+	result.items, result.err = r.process(pdfFile, invoiceID)
+	if errors.Is(result.err, errNoItems) {
+		r.logger.Warn("No items extracted",
+			slog.String("invoice_id", invoiceID))
+		fmt.Printf("WARNING: No items found in invoice_id:%s\n", invoiceID)
+		return result
+	}
+	if result.err != nil {
+		r.logger.Error("Failed to process invoice",
+			slog.String("invoice_id", invoiceID),
+			slog.String("error", result.err.Error()))
+		fmt.Printf("ERROR: Failed to process invoice_id:%s - %v\n", invoiceID, result.err)
+		return result
+	}
+
+	fmt.Printf("SUCCESS: Processed invoice_id:%s - %d items\n", invoiceID, result.items)
+
+	// Record the invoice before moving on so a crash can't lose it
+	r.state.MarkProcessed(invoiceID)
+	if r.persist != nil {
+		if err := r.persist(); err != nil {
+			r.logger.Error("Failed to save state",
+				slog.String("invoice_id", invoiceID),
+				slog.String("error", err.Error()))
+		}
+	}
+	return result
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+func TestPDFExtractor_ExtractTextLines_Encrypted(t *testing.T) {
+	path := helpers.CreateEncryptedTestPDF(t, []string{"Brass table lamp 12.00"}, "hunter2")
+
+	tests := []struct {
+		name      string
+		password  string
+		expectErr string
+	}{
+		{name: "correct_password", password: "hunter2"},
+		{name: "missing_password", expectErr: "use -pdf-password"},
+		{name: "wrong_password", password: "nope", expectErr: "the password was rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor := NewPDFExtractor(nil, slog.New(slog.DiscardHandler))
+			extractor.SetPassword(tt.password)
+
+			lines, err := extractor.extractTextLines(path)
+			if tt.expectErr != "" {
+				require.ErrorIs(t, err, ErrEncryptedPDF)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, lines, "Brass table lamp 12.00")
+		})
+	}
+}
+
+func TestProcessFile_DryRun(t *testing.T) {
+	path := helpers.CreateTestPDF(t, []string{
+		"LOT DESCRIPTION PRICE",
+		"Brass table lamp 12.00",
+		"Pink depression glass candy dish",
+		"with lid 8.50",
+		"Sterling silver cake plate 40.00",
+		"SUBTOTAL 60.50",
+	})
+
+	// A nil pool would panic if dry-run tried to save
+	extractor := NewPDFExtractor(nil, slog.New(slog.DiscardHandler))
+
+	count, err := processFile(context.Background(), extractor, path, "INV-1001", true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	empty := helpers.CreateTestPDF(t, []string{"LOT DESCRIPTION PRICE", "SUBTOTAL 0.00"})
+	_, err = processFile(context.Background(), extractor, empty, "INV-1002", true)
+	assert.ErrorIs(t, err, errNoItems)
+}
+
+func TestSeedRun_ResumesAfterInterruption(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, ".seed_state.json")
+	invoices := []string{"INV-1.pdf", "INV-2.pdf", "INV-3.pdf", "INV-4.pdf"}
+
+	newRun := func(state *seederState, process func(path, invoiceID string) (int, error)) *seedRun {
+		return &seedRun{
+			logger:  slog.New(slog.DiscardHandler),
+			state:   state,
+			process: process,
+			persist: func() error { return state.Save(statePath) },
+		}
+	}
+
+	// The first run crashes while processing the third invoice
+	var firstRun []string
+	state, err := loadSeederState(statePath)
+	require.NoError(t, err)
+	assert.Panics(t, func() {
+		newRun(state, func(path, invoiceID string) (int, error) {
+			if invoiceID == "INV-3" {
+				panic("killed")
+			}
+			firstRun = append(firstRun, invoiceID)
+			return 2, nil
+		}).Run(invoices)
+	})
+	assert.Equal(t, []string{"INV-1", "INV-2"}, firstRun)
+
+	// The next run picks up where it stopped
+	var secondRun []string
+	state, err = loadSeederState(statePath)
+	require.NoError(t, err)
+	summary := newRun(state, func(path, invoiceID string) (int, error) {
+		secondRun = append(secondRun, invoiceID)
+		if invoiceID == "INV-4" {
+			return 0, errNoItems
+		}
+		return 3, nil
+	}).Run(invoices)
+
+	assert.Equal(t, []string{"INV-3", "INV-4"}, secondRun, "completed invoices aren't reprocessed")
+	assert.Equal(t, 1, summary.processed)
+	assert.Equal(t, []string{"INV-4 (0 items)"}, summary.failed)
+
+	state, err = loadSeederState(statePath)
+	require.NoError(t, err)
+	for _, id := range []string{"INV-1", "INV-2", "INV-3"} {
+		assert.True(t, state.Processed(id), id)
+	}
+	assert.False(t, state.Processed("INV-4"), "failed invoices are retried next run")
+
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers, "no temp files are left behind")
+}
+
+func TestLoadSeederState(t *testing.T) {
+	dir := t.TempDir()
+
+	state, err := loadSeederState(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.False(t, state.Processed("INV-1"))
+
+	// Files written before the state was kept as a map still load
+	path := filepath.Join(dir, "state.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"processed_invoices":["INV-1","INV-2"],"processed_count":2}`), 0644))
+	state, err = loadSeederState(path)
+	require.NoError(t, err)
+	assert.True(t, state.Processed("INV-2"))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"processed_invoices":["INV-1"`), 0644))
+	_, err = loadSeederState(path)
+	assert.ErrorContains(t, err, "failed to parse state file", "a corrupt file isn't mistaken for a fresh start")
+}
+
+func TestSeedRun_ConcurrentMatchesSequential(t *testing.T) {
+	fixtures := []string{
+		helpers.CreateTestPDF(t, []string{"LOT DESCRIPTION PRICE", "Brass table lamp 12.00", "SUBTOTAL 12.00"}),
+		helpers.CreateTestPDF(t, []string{"LOT DESCRIPTION PRICE", "Oak side table 30.00", "Wool rug 15.00", "SUBTOTAL 45.00"}),
+		helpers.CreateTestPDF(t, []string{"LOT DESCRIPTION PRICE", "SUBTOTAL 0.00"}),
+		helpers.CreateTempFile(t, []byte("not a pdf"), ".pdf"),
+	}
+	for i := 0; i < 8; i++ {
+		fixtures = append(fixtures, helpers.CreateTestPDF(t, []string{
+			"LOT DESCRIPTION PRICE", "Pink depression glass bowl 8.50", "Sterling silver spoon 20.00", "Box lot 1.00", "SUBTOTAL 29.50",
+		}))
+	}
+
+	extractor := NewPDFExtractor(nil, slog.New(slog.DiscardHandler))
+	run := func(concurrency int) (seedSummary, *seederState) {
+		state := newSeederState()
+		summary := (&seedRun{
+			logger:      slog.New(slog.DiscardHandler),
+			state:       state,
+			concurrency: concurrency,
+			process: func(path, invoiceID string) (int, error) {
+				return processFile(context.Background(), extractor, path, invoiceID, true)
+			},
+		}).Run(fixtures)
+		return summary, state
+	}
+
+	sequential, sequentialState := run(1)
+	require.Equal(t, 10, sequential.processed)
+	require.Equal(t, 1+2+8*3, sequential.items)
+	require.Len(t, sequential.failed, 2)
+
+	for _, concurrency := range []int{2, 4, 16} {
+		concurrent, concurrentState := run(concurrency)
+		assert.Equal(t, sequential.processed, concurrent.processed, concurrency)
+		assert.Equal(t, sequential.items, concurrent.items, concurrency)
+		assert.Equal(t, sequential.failed, concurrent.failed, concurrency)
+		assert.Equal(t, sequential.succeeded, concurrent.succeeded, concurrency)
+		assert.Equal(t, sequentialState.processed, concurrentState.processed, concurrency)
+	}
+}
+
+func TestSeedReport_JSONSchema(t *testing.T) {
+	state := newSeederState()
+	state.MarkProcessed("INV-0")
+	summary := (&seedRun{
+		logger: slog.New(slog.DiscardHandler),
+		state:  state,
+		process: func(path, invoiceID string) (int, error) {
+			switch invoiceID {
+			case "INV-2":
+				return 0, errNoItems
+			case "INV-3":
+				return 0, errors.New("failed to extract text: not a PDF file")
+			}
+			return 4, nil
+		},
+	}).Run([]string{"INV-0.pdf", "INV-1.pdf", "INV-2.pdf", "INV-3.pdf", "INV-4.pdf"})
+
+	var buf bytes.Buffer
+	require.NoError(t, writeReport(&buf, reportJSON, newSeedReport(summary, true)))
+
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.ElementsMatch(t, []string{"generated_at", "dry_run", "totals", "invoices"}, mapKeys(report))
+	assert.Equal(t, true, report["dry_run"])
+	_, err := time.Parse(time.RFC3339, report["generated_at"].(string))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"invoices": 5.0, "succeeded": 2.0, "failed": 2.0, "skipped": 1.0, "items": 8.0, "average_items": 4.0,
+	}, report["totals"])
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"invoice_id": "INV-0", "status": "skipped", "items": 0.0},
+		map[string]interface{}{"invoice_id": "INV-1", "status": "succeeded", "items": 4.0},
+		map[string]interface{}{"invoice_id": "INV-2", "status": "empty", "items": 0.0},
+		map[string]interface{}{"invoice_id": "INV-3", "status": "failed", "items": 0.0, "error": "failed to extract text: not a PDF file"},
+		map[string]interface{}{"invoice_id": "INV-4", "status": "succeeded", "items": 4.0},
+	}, report["invoices"])
+
+	buf.Reset()
+	require.NoError(t, writeReport(&buf, reportCSV, newSeedReport(summary, true)))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invoice_id", "status", "items", "detail"}, rows[0])
+	assert.Equal(t, []string{"", "total", "8", "2 succeeded, 2 failed, 1 skipped"}, rows[len(rows)-1])
+	assert.Len(t, rows, 7)
+}
+
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestPDFExtractor_CreateInventoryItem_CostPerItem(t *testing.T) {
+	extractor := NewPDFExtractor(nil, slog.New(slog.DiscardHandler))
+	auction := AuctionInfo{InvoiceID: "INV-4", BuyersPremiumPercent: 18, SalesTaxPercent: 8.625}
+
+	single, err := extractor.createInventoryItem("Set of four pressed glass tumblers", 100, 1, "INV-4", auction)
+	require.NoError(t, err)
+	assert.True(t, single.CostPerItem.Equal(single.TotalCost))
+
+	lot, err := extractor.createInventoryItem("Set of four pressed glass tumblers", 100, 4, "INV-4", auction)
+	require.NoError(t, err)
+	assert.Equal(t, 4, lot.Quantity)
+	assert.True(t, lot.TotalCost.Equal(single.TotalCost), "total: %s", lot.TotalCost)
+
+	// 100 + 18.00 premium + 10.18 tax = 128.18, a quarter of which is 32.045
+	assert.Equal(t, "128.18", lot.TotalCost.StringFixed(2))
+	assert.Equal(t, "32.05", lot.CostPerItem.StringFixed(2))
+
+	_, err = extractor.createInventoryItem("Empty lot", 100, 0, "INV-4", auction)
+	assert.ErrorContains(t, err, "quantity must be positive")
+}
+
+func TestPremiumSchedule_Premium(t *testing.T) {
+	flat := PremiumSchedule{{Percent: 18}}
+	tiered := PremiumSchedule{{UpTo: 1000, Percent: 25}, {Percent: 20}}
+
+	tests := []struct {
+		name     string
+		schedule PremiumSchedule
+		bid      string
+		want     string
+	}{
+		{"flat", flat, "123.45", "22.22"},
+		{"flat_large_bid", flat, "5000", "900.00"},
+		{"two_bands_below_threshold", tiered, "200", "50.00"},
+		{"two_bands_across_threshold", tiered, "1500", "350.00"},
+		{"bid_on_threshold", tiered, "1000", "250.00"},
+		{"zero_bid", tiered, "0", "0.00"},
+		{"zero_bid_flat", flat, "0", "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.Premium(decimal.RequireFromString(tt.bid))
+			assert.Equal(t, tt.want, got.StringFixed(2))
+		})
+	}
+
+	// A single band must match the flat rate the seeder charged before schedules existed
+	for _, bid := range []string{"0.01", "7.77", "123.45", "999.99", "12345.67"} {
+		b := decimal.RequireFromString(bid)
+		old := b.Mul(decimal.NewFromFloat(18.0 / 100)).Round(2)
+		assert.True(t, old.Equal(flat.Premium(b)), "bid %s: %s != %s", bid, flat.Premium(b), old)
+	}
+}
+
+func TestPDFExtractor_LoadAuctions(t *testing.T) {
+	path := helpers.CreateTestExcel(t, [][]string{
+		{"invoice_id", "auction_id", "date", "buyers_premium", "sales_tax", "tier_1_up_to", "tier_2_percent"},
+		{"INV-1", "11", "2024-03-01", "18", "8.625"},
+		{"INV-2", "12", "2024-03-02", "25", "8.625", "1000", "20", "5000", "15"},
+		{"INV-3", "13", "2024-03-03", "25", "8.625", "1000", "twenty"},
+	})
+
+	extractor := NewPDFExtractor(nil, slog.New(slog.DiscardHandler))
+	require.NoError(t, extractor.LoadAuctions(path))
+	require.Len(t, extractor.auctions, 3)
+
+	flat := extractor.auctions["INV-1"]
+	assert.Equal(t, 11, flat.AuctionID)
+	assert.Equal(t, 18.0, flat.BuyersPremiumPercent)
+	assert.Equal(t, 8.625, flat.SalesTaxPercent)
+	assert.Equal(t, PremiumSchedule{{Percent: 18}}, flat.PremiumSchedule)
+
+	tiered := extractor.auctions["INV-2"]
+	assert.Equal(t, 25.0, tiered.BuyersPremiumPercent)
+	assert.Equal(t, PremiumSchedule{{UpTo: 1000, Percent: 25}, {UpTo: 5000, Percent: 20}, {Percent: 15}}, tiered.PremiumSchedule)
+	assert.Equal(t, "1200.00", tiered.premiumSchedule().Premium(decimal.RequireFromString("6000")).StringFixed(2))
+
+	// A malformed tier stops parsing and keeps the bands read so far
+	assert.Equal(t, PremiumSchedule{{Percent: 25}}, extractor.auctions["INV-3"].PremiumSchedule)
+}
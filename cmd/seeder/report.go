@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Report formats for -report
+const (
+	reportText = "text"
+	reportJSON = "json"
+	reportCSV  = "csv"
+)
+
+// Invoice statuses in a seedReport
+const (
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+	statusEmpty     = "empty" // the invoice parsed but had no items
+	statusSkipped   = "skipped"
+)
+
+// seedReport is the machine-readable summary of a seeding run
+type seedReport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	DryRun      bool            `json:"dry_run"`
+	Totals      reportTotals    `json:"totals"`
+	Invoices    []reportInvoice `json:"invoices"`
+}
+
+type reportTotals struct {
+	Invoices     int     `json:"invoices"`
+	Succeeded    int     `json:"succeeded"`
+	Failed       int     `json:"failed"`
+	Skipped      int     `json:"skipped"`
+	Items        int     `json:"items"`
+	AverageItems float64 `json:"average_items"`
+}
+
+type reportInvoice struct {
+	InvoiceID string `json:"invoice_id"`
+	Status    string `json:"status"`
+	Items     int    `json:"items"`
+	Error     string `json:"error,omitempty"`
+}
+
+func validateReportFormat(format string) error {
+	switch format {
+	case reportText, reportJSON, reportCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format %q: use text, json or csv", format)
+	}
+}
+
+// newSeedReport builds the report for summary, listing invoices in input order
+func newSeedReport(summary seedSummary, dryRun bool) seedReport {
+	report := seedReport{
+		GeneratedAt: time.Now().UTC(),
+		DryRun:      dryRun,
+		Invoices:    make([]reportInvoice, 0, len(summary.results)),
+	}
+
+	for _, result := range summary.results {
+		invoice := reportInvoice{InvoiceID: result.invoiceID, Items: result.items}
+		switch {
+		case result.skipped:
+			invoice.Status = statusSkipped
+			report.Totals.Skipped++
+		case errors.Is(result.err, errNoItems):
+			invoice.Status = statusEmpty
+			report.Totals.Failed++
+		case result.err != nil:
+			invoice.Status = statusFailed
+			invoice.Error = result.err.Error()
+			report.Totals.Failed++
+		default:
+			invoice.Status = statusSucceeded
+			report.Totals.Succeeded++
+			report.Totals.Items += result.items
+		}
+		report.Invoices = append(report.Invoices, invoice)
+	}
+
+	report.Totals.Invoices = len(report.Invoices)
+	if report.Totals.Succeeded > 0 {
+		report.Totals.AverageItems = float64(report.Totals.Items) / float64(report.Totals.Succeeded)
+	}
+	return report
+}
+
+// writeReportFile writes report to path in format
+func writeReportFile(path, format string, report seedReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := writeReport(f, format, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeReport encodes report as json, or as csv with one row per invoice followed by a
+// "total" row. The csv detail column holds the error of failed invoices.
+func writeReport(w io.Writer, format string, report seedReport) error {
+	switch format {
+	case reportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+
+	case reportCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"invoice_id", "status", "items", "detail"})
+		for _, invoice := range report.Invoices {
+			cw.Write([]string{invoice.InvoiceID, invoice.Status, strconv.Itoa(invoice.Items), invoice.Error})
+		}
+		cw.Write([]string{"", "total", strconv.Itoa(report.Totals.Items),
+			fmt.Sprintf("%d succeeded, %d failed, %d skipped", report.Totals.Succeeded, report.Totals.Failed, report.Totals.Skipped)})
+		cw.Flush()
+		return cw.Error()
+
+	default:
+		return fmt.Errorf("no structured report for format %q", format)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// seederState records which invoices have been seeded so an interrupted run can resume. It is
+// safe for concurrent use.
+type seederState struct {
+	saveMu     sync.Mutex // serializes Save so an older snapshot never replaces a newer one
+	mu         sync.Mutex
+	processed  map[string]bool
+	lastUpdate time.Time
+}
+
+// seederStateFile is the on-disk form of seederState
+type seederStateFile struct {
+	ProcessedInvoices []string  `json:"processed_invoices"`
+	ProcessedCount    int       `json:"processed_count"`
+	LastUpdate        time.Time `json:"last_update"`
+}
+
+func newSeederState() *seederState {
+	return &seederState{processed: make(map[string]bool)}
+}
+
+// loadSeederState reads the state file at path. A missing file is an empty state; an
+// unreadable one is an error rather than silently starting over.
+func loadSeederState(path string) (*seederState, error) {
+	state := newSeederState()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var file seederStateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	for _, id := range file.ProcessedInvoices {
+		state.processed[id] = true
+	}
+	state.lastUpdate = file.LastUpdate
+
+	return state, nil
+}
+
+// Processed reports whether invoiceID has already been seeded
+func (s *seederState) Processed(invoiceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[invoiceID]
+}
+
+// MarkProcessed records invoiceID as seeded
+func (s *seederState) MarkProcessed(invoiceID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed[invoiceID] = true
+	s.lastUpdate = time.Now()
+}
+
+// Save writes the state to path atomically: a crash leaves either the old file or the new
+// one, never a partial write
+func (s *seederState) Save(path string) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	file := seederStateFile{
+		ProcessedInvoices: make([]string, 0, len(s.processed)),
+		ProcessedCount:    len(s.processed),
+		LastUpdate:        s.lastUpdate,
+	}
+	for id := range s.processed {
+		file.ProcessedInvoices = append(file.ProcessedInvoices, id)
+	}
+	s.mu.Unlock()
+	sort.Strings(file.ProcessedInvoices)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	// The temp file must be on the same filesystem for the rename to be atomic
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,26 +13,39 @@ import (
 	"time"
 
 	"github.com/ammerola/resell-be/internal/adapters/db"
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/adapters/storage"
 	"github.com/ammerola/resell-be/internal/core/services"
 	"github.com/ammerola/resell-be/internal/pkg/config"
 	"github.com/ammerola/resell-be/internal/pkg/logger"
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
 	"github.com/ammerola/resell-be/internal/workers"
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
+	allowMissing := flag.Bool("allow-missing", false, "Start even if required environment variables are unset (development only)")
+	flag.Parse()
+
 	// Setup temporary logger for config loading
 	tempLogger := logger.SetupLogger("info", "json")
 
 	// Load configuration
-	cfg, err := config.Load(tempLogger.Logger)
+	loader := config.NewConfigLoader(tempLogger.Logger).AllowMissing(*allowMissing)
+	cfg, err := loader.Load(context.Background())
 	if err != nil {
 		tempLogger.Error("failed to load configuration", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
-	// Reconfigure logger with loaded settings
-	slogger := logger.SetupLogger(cfg.App.LogLevel, cfg.App.LogFormat)
+	// Reconfigure logger with loaded settings and outputs, sampling info logs if enabled
+	slogger, err := logger.Setup(cfg.LogConfig())
+	if err != nil {
+		tempLogger.Error("failed to set up log outputs", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer slogger.Close()
 	slogger.Info("starting worker",
 		slog.String("environment", cfg.App.Environment),
 		slog.String("redis_addr", cfg.Asynq.RedisAddr))
@@ -44,54 +59,119 @@ func main() {
 	}
 	defer database.Close()
 
+	// Initialize Redis cache, used to release locks taken by the API
+	redisCredentials := redis_a.NewCredentials(cfg.Redis.Password)
+	redisClient := initRedis(cfg, redisCredentials)
+	defer redisClient.Close()
+	cache := redis_a.NewCache(redisClient, cfg.Redis.TTL, slogger.Logger)
+
+	// Pick up rotated DB/Redis passwords without a restart
+	if secretsWatcher := loader.SecretsWatcher(cfg); secretsWatcher != nil {
+		secretsWatcher.Watch("DB_PASSWORD", cfg.Database.Password, database.UpdatePassword)
+		secretsWatcher.Watch("REDIS_PASSWORD", cfg.Redis.Password, func(ctx context.Context, password string) error {
+			return redisCredentials.Rotate(ctx, redisClient, password)
+		})
+		go secretsWatcher.Run(ctx)
+	}
+
+	// Initialize storage shared with the API for uploads and report artifacts
+	fileStorage, err := storage.NewStorageClient(ctx, cfg, slogger.Logger)
+	if err != nil {
+		slogger.Error("failed to initialize file storage", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	// Initialize repositories and services
 	inventoryRepo := db.NewInventoryRepository(database, slogger.Logger)
 	inventoryService := services.NewInventoryService(inventoryRepo, database.Pool(), slogger.Logger)
 
+	redisOpt := asynq.RedisClientOpt{
+		Addr:     cfg.Asynq.RedisAddr,
+		Password: cfg.Asynq.RedisPassword,
+		DB:       cfg.Asynq.RedisDB,
+	}
+
 	// Create Asynq server
 	srv := asynq.NewServer(
-		asynq.RedisClientOpt{
-			Addr:     cfg.Asynq.RedisAddr,
-			Password: cfg.Asynq.RedisPassword,
-			DB:       cfg.Asynq.RedisDB,
-		},
+		redisOpt,
 		asynq.Config{
 			Concurrency:     cfg.Asynq.Concurrency,
 			Queues:          cfg.Asynq.Queues,
 			StrictPriority:  cfg.Asynq.StrictPriority,
 			ErrorHandler:    asynq.ErrorHandlerFunc(handleError),
-			RetryDelayFunc:  exponentialBackoff,
+			RetryDelayFunc:  retryDelay(workers.ExponentialBackoff(cfg.Asynq.RetryBaseDelay, cfg.Asynq.RetryMaxDelay)),
+			IsFailure:       isFailure,
 			ShutdownTimeout: cfg.Asynq.ShutdownTimeout,
 			HealthCheckFunc: healthCheck,
 			Logger:          newAsynqLogger(slogger.Logger),
 		},
 	)
 
+	// Client for follow-up tasks such as import notification emails
+	asynqClient := asynq.NewClient(redisOpt)
+	defer asynqClient.Close()
+	importNotifier := workers.NewImportNotifier(asynqClient, slogger.Logger)
+
 	// Create task handlers
 	mux := asynq.NewServeMux()
 
+	// Per-queue limits keep one queue's jobs from taking every worker
+	mux.Use(workers.NewQueueLimiter(cfg.Asynq.QueueLimits).Middleware)
+
+	// Track running jobs so any interrupted by shutdown can be put back to queued
+	jobTracker := workers.NewJobTracker(database, slogger.Logger)
+	mux.Use(jobTracker.Middleware)
+
 	// Register PDF processing handler
-	pdfProcessor := workers.NewPDFProcessor(inventoryService, database, slogger.Logger)
+	var ocr workers.OCREngine
+	if cfg.FileProcessing.EnableOCR {
+		ocr = workers.NewTesseractOCR()
+	}
+	invoiceProfiles, err := pdfparse.LoadProfiles(cfg.FileProcessing.InvoiceProfilesFile)
+	if err != nil {
+		slogger.Error("failed to load invoice profiles", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	pdfProcessor := workers.NewPDFProcessor(inventoryService, database, fileStorage, slogger.Logger, cfg.FileProcessing.ProgressInterval, ocr, importNotifier, invoiceProfiles)
 	mux.HandleFunc(workers.TypePDFProcess, pdfProcessor.ProcessPDF)
 
 	// Register Excel processing handler
-	excelProcessor := workers.NewExcelProcessor(inventoryService, database, slogger.Logger)
+	excelProcessor := workers.NewExcelProcessor(inventoryService, database, fileStorage, slogger.Logger, importNotifier)
 	mux.HandleFunc(workers.TypeExcelImport, excelProcessor.ProcessExcel)
 
 	// Register analytics handler
-	analyticsProcessor := workers.NewAnalyticsProcessor(database, slogger.Logger)
+	analyticsProcessor := workers.NewAnalyticsProcessor(database, cache, fileStorage, slogger.Logger)
 	mux.HandleFunc(workers.TypeRefreshAnalytics, analyticsProcessor.RefreshAnalytics)
 	mux.HandleFunc(workers.TypeGenerateReport, analyticsProcessor.GenerateReport)
 
 	// Register email notification handler
-	notificationProcessor := workers.NewNotificationProcessor(cfg, slogger.Logger)
+	var emailSender workers.EmailSender = workers.NewLogEmailSender(slogger.Logger)
+	if cfg.Email.Sender == "ses" {
+		sesSender, err := workers.NewSESEmailSender(ctx, cfg.AWS)
+		if err != nil {
+			slogger.Error("failed to initialize SES email sender", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		emailSender = sesSender
+	}
+	notificationProcessor := workers.NewNotificationProcessor(cfg, emailSender, slogger.Logger)
 	mux.HandleFunc(workers.TypeSendEmail, notificationProcessor.SendEmail)
 
 	// Register cleanup handler
-	cleanupProcessor := workers.NewCleanupProcessor(database, cfg, slogger.Logger)
+	cleanupProcessor := workers.NewCleanupProcessor(database, fileStorage, cfg, slogger.Logger)
 	mux.HandleFunc(workers.TypeCleanupOldData, cleanupProcessor.CleanupOldData)
 	mux.HandleFunc(workers.TypeCleanupTempFiles, cleanupProcessor.CleanupTempFiles)
 
+	// Schedule periodic tasks handled by the mux above
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
+		Logger:          newAsynqLogger(slogger.Logger),
+		PostEnqueueFunc: handleScheduledEnqueue,
+	})
+	if err := workers.RegisterPeriodicTasks(scheduler, cfg.Asynq, slogger.Logger); err != nil {
+		slogger.Error("failed to register periodic tasks", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	// Handle shutdown gracefully
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
@@ -103,16 +183,35 @@ func main() {
 		}
 	}()
 
+	if err := scheduler.Start(); err != nil {
+		slogger.Error("failed to start scheduler", slog.String("error", err.Error()))
+		shutdown <- syscall.SIGTERM
+	}
+
 	slogger.Info("worker started successfully",
 		slog.Int("concurrency", cfg.Asynq.Concurrency),
-		slog.Any("queues", cfg.Asynq.Queues))
+		slog.Any("queues", cfg.Asynq.Queues),
+		slog.Any("queue_limits", cfg.Asynq.QueueLimits))
 
 	// Wait for shutdown signal
 	sig := <-shutdown
 	slogger.Info("shutdown signal received", slog.String("signal", sig.String()))
 
-	// Gracefully shutdown
+	// Stop pulling new tasks, then give in-flight tasks up to ShutdownTimeout to finish
+	scheduler.Shutdown()
+	srv.Stop()
+	slogger.Info("draining in-flight tasks",
+		slog.Any("job_ids", jobTracker.Active()),
+		slog.Duration("timeout", cfg.Asynq.ShutdownTimeout))
 	srv.Shutdown()
+
+	// asynq requeues tasks that didn't finish in time; mark their jobs queued to match
+	requeueCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := jobTracker.RequeueUnfinished(requeueCtx); err != nil {
+		slogger.Error("failed to requeue interrupted jobs", slog.String("error", err.Error()))
+	}
+
 	slogger.Info("worker shutdown complete")
 }
 
@@ -137,21 +236,51 @@ func initDatabase(ctx context.Context, cfg *config.Config, slogger *slog.Logger)
 	return db.NewDatabase(ctx, dbConfig, slogger)
 }
 
+func initRedis(cfg *config.Config, credentials *redis_a.Credentials) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:                fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		CredentialsProvider: credentials.Provider(),
+		DB:                  cfg.Redis.DB,
+		MaxRetries:          cfg.Redis.MaxRetries,
+		MinRetryBackoff:     cfg.Redis.MinRetryBackoff,
+		MaxRetryBackoff:     cfg.Redis.MaxRetryBackoff,
+		DialTimeout:         cfg.Redis.DialTimeout,
+		ReadTimeout:         cfg.Redis.ReadTimeout,
+		WriteTimeout:        cfg.Redis.WriteTimeout,
+	})
+}
+
 func handleError(ctx context.Context, task *asynq.Task, err error) {
+	// Tasks deferred by a queue limit run again shortly and aren't failures
+	if workers.IsQueueLimitError(err) {
+		return
+	}
 	slog.ErrorContext(ctx, "task processing failed",
 		slog.String("type", task.Type()),
 		slog.String("payload", string(task.Payload())),
 		slog.String("error", err.Error()))
 }
 
-func exponentialBackoff(n int, e error, t *asynq.Task) time.Duration {
-	baseDelay := time.Second
-	maxDelay := 10 * time.Minute
-	delay := baseDelay * time.Duration(1<<uint(n))
-	if delay > maxDelay {
-		delay = maxDelay
+func handleScheduledEnqueue(info *asynq.TaskInfo, err error) {
+	// Duplicates are expected when an on-demand refresh is already queued
+	if err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		slog.Error("failed to enqueue scheduled task", slog.String("error", err.Error()))
+	}
+}
+
+// retryDelay waits out a queue limit and uses backoff after real failures
+func retryDelay(backoff asynq.RetryDelayFunc) asynq.RetryDelayFunc {
+	return func(n int, e error, t *asynq.Task) time.Duration {
+		if delay, ok := workers.QueueLimitRetryDelay(e); ok {
+			return delay
+		}
+		return backoff(n, e, t)
 	}
-	return delay
+}
+
+// isFailure keeps tasks deferred by a queue limit from using up their retries
+func isFailure(err error) bool {
+	return !workers.IsQueueLimitError(err)
 }
 
 func healthCheck(err error) {
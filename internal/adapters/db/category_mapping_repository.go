@@ -0,0 +1,85 @@
+// internal/adapters/db/category_mapping_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// categoryMappingRepository implements ports.CategoryMappingRepository
+type categoryMappingRepository struct {
+	db     *Database
+	logger *slog.Logger
+	qb     squirrel.StatementBuilderType
+}
+
+// NewCategoryMappingRepository creates a new category mapping repository
+func NewCategoryMappingRepository(db *Database, logger *slog.Logger) ports.CategoryMappingRepository {
+	return &categoryMappingRepository{
+		db:     db,
+		logger: logger.With(slog.String("repository", "category_mapping")),
+		qb:     squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// FindAllEbay returns every stored eBay category mapping
+func (r *categoryMappingRepository) FindAllEbay(ctx context.Context) (map[domain.ItemCategory]string, error) {
+	sql, args, err := r.qb.Select("category", "ebay_category_id").
+		From("ebay_category_mappings").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category mappings: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := make(map[domain.ItemCategory]string)
+	for rows.Next() {
+		var category domain.ItemCategory
+		var ebayCategoryID string
+		if err := rows.Scan(&category, &ebayCategoryID); err != nil {
+			return nil, fmt.Errorf("failed to scan category mapping: %w", err)
+		}
+		mappings[category] = ebayCategoryID
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate category mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+// UpsertEbay inserts or replaces the eBay mapping for a category
+func (r *categoryMappingRepository) UpsertEbay(ctx context.Context, category domain.ItemCategory, ebayCategoryID string) error {
+	now := time.Now()
+	sql, args, err := r.qb.Insert("ebay_category_mappings").
+		Columns("category", "ebay_category_id", "updated_at").
+		Values(category, ebayCategoryID, now).
+		Suffix("ON CONFLICT (category) DO UPDATE SET ebay_category_id = EXCLUDED.ebay_category_id, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build upsert query: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to upsert category mapping: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "category mapping saved",
+		slog.String("category", string(category)),
+		slog.String("ebay_category_id", ebayCategoryID))
+
+	return nil
+}
@@ -0,0 +1,209 @@
+// internal/adapters/db/dashboard_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// dashboardRepository implements ports.DashboardRepository
+type dashboardRepository struct {
+	db     *Database
+	logger *slog.Logger
+	qb     squirrel.StatementBuilderType
+}
+
+// NewDashboardRepository creates a new dashboard aggregates repository
+func NewDashboardRepository(db *Database, logger *slog.Logger) ports.DashboardRepository {
+	return &dashboardRepository{
+		db:     db,
+		logger: logger.With(slog.String("repository", "dashboard")),
+		qb:     squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// CategoryBreakdown counts the active items per category with their summed total cost and
+// estimated value, how many sold, and the average ROI of those sales
+func (r *dashboardRepository) CategoryBreakdown(ctx context.Context, filters ports.DashboardFilters) ([]ports.CategoryBreakdown, error) {
+	sql, args, err := r.buildCategoryBreakdownQuery(filters).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build category breakdown query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	categories := make([]ports.CategoryBreakdown, 0)
+	for rows.Next() {
+		var c ports.CategoryBreakdown
+		if err := rows.Scan(&c.Category, &c.Count, &c.TotalCost, &c.EstimatedValue, &c.SoldCount, &c.AvgROI); err != nil {
+			return nil, fmt.Errorf("failed to scan category breakdown: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate category breakdown: %w", err)
+	}
+
+	return categories, nil
+}
+
+// buildCategoryBreakdownQuery builds the grouped query for CategoryBreakdown. Each item joins
+// one sale row, its highest sold price with the fees of all its sold listings (both NULL-safe
+// when unsold), matching the net profit of the export view. Items without a category count as
+// other, and grouping only ever yields categories that have items.
+func (r *dashboardRepository) buildCategoryBreakdownQuery(filters ports.DashboardFilters) squirrel.SelectBuilder {
+	query := r.qb.Select().
+		Column("COALESCE(i.category::text, 'other')").
+		Column("COUNT(*)").
+		Column("COALESCE(SUM(i.total_cost), 0)").
+		Column("COALESCE(SUM(i.estimated_value), 0)").
+		Column("COUNT(s.sold_price)").
+		Column("COALESCE(ROUND(AVG((s.sold_price - s.fees - i.total_cost) / NULLIF(i.total_cost, 0) * 100), 2), 0)").
+		From("inventory i").
+		JoinClause(`LEFT JOIN LATERAL (
+			SELECT MAX(pl.sold_price) AS sold_price, COALESCE(SUM(pl.platform_fees), 0) AS fees
+			FROM platform_listings pl
+			WHERE pl.lot_id = i.lot_id AND pl.status = 'sold' AND pl.sold_price IS NOT NULL
+		) s ON true`).
+		Where("i.deleted_at IS NULL")
+
+	if filters.From != nil {
+		query = query.Where(squirrel.GtOrEq{"i.acquisition_date": *filters.From})
+	}
+	if filters.To != nil {
+		query = query.Where(squirrel.Lt{"i.acquisition_date": *filters.To})
+	}
+
+	return query.
+		GroupBy("1").
+		OrderBy("3 DESC", "1")
+}
+
+// AgingInventory lists the unsold active items acquired more than params.MinDays ago
+func (r *dashboardRepository) AgingInventory(ctx context.Context, params ports.AgingParams) ([]ports.AgingItem, error) {
+	sql, args, err := r.buildAgingInventoryQuery(params).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build aging inventory query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aging inventory: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ports.AgingItem, 0)
+	for rows.Next() {
+		item, err := scanAgingItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate aging inventory: %w", err)
+	}
+
+	return items, nil
+}
+
+// buildAgingInventoryQuery builds the query for AgingInventory. An item counts as sold once any
+// of its listings is, and days held are the whole days elapsed since acquisition.
+func (r *dashboardRepository) buildAgingInventoryQuery(params ports.AgingParams) squirrel.SelectBuilder {
+	return r.qb.Select(
+		"i.lot_id", "i.item_name", "i.category", "i.storage_location", "i.acquisition_date",
+		"EXTRACT(DAY FROM NOW() - i.acquisition_date)::int",
+		"i.total_cost", "i.estimated_value",
+	).
+		From("inventory i").
+		Where("i.deleted_at IS NULL").
+		Where("i.acquisition_date < NOW() - make_interval(days => ?)", params.MinDays).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM platform_listings pl
+			WHERE pl.lot_id = i.lot_id AND pl.status = 'sold'
+		)`).
+		OrderBy("i.acquisition_date", "i.total_cost DESC", "i.lot_id").
+		Limit(uint64(params.Limit))
+}
+
+// scanAgingItem scans one row of the aging inventory query
+func scanAgingItem(row pgx.Row) (ports.AgingItem, error) {
+	var item ports.AgingItem
+	var storageLocation sql.NullString
+	var estimatedValue decimal.NullDecimal
+
+	if err := row.Scan(
+		&item.LotID, &item.ItemName, &item.Category, &storageLocation, &item.AcquisitionDate,
+		&item.DaysHeld, &item.TotalCost, &estimatedValue,
+	); err != nil {
+		return item, fmt.Errorf("failed to scan aging item: %w", err)
+	}
+
+	item.StorageLocation = storageLocation.String
+	if estimatedValue.Valid {
+		item.EstimatedValue = &estimatedValue.Decimal
+	}
+
+	return item, nil
+}
+
+// ValueVariance lists the active items whose estimated value strays from their total cost by
+// more than params.MinPercent
+func (r *dashboardRepository) ValueVariance(ctx context.Context, params ports.VarianceParams) ([]ports.VarianceItem, error) {
+	sql, args, err := r.buildValueVarianceQuery(params).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build value variance query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query value variance: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ports.VarianceItem, 0)
+	for rows.Next() {
+		var item ports.VarianceItem
+		if err := rows.Scan(
+			&item.LotID, &item.ItemName, &item.Category, &item.TotalCost, &item.EstimatedValue,
+			&item.Variance, &item.VariancePercent,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan value variance: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate value variance: %w", err)
+	}
+
+	return items, nil
+}
+
+// buildValueVarianceQuery builds the query for ValueVariance. The percentage is compared
+// against the cost in SQL, so over- and under-valued items pass the same threshold.
+func (r *dashboardRepository) buildValueVarianceQuery(params ports.VarianceParams) squirrel.SelectBuilder {
+	return r.qb.Select(
+		"i.lot_id", "i.item_name", "i.category", "i.total_cost", "i.estimated_value",
+		"i.estimated_value - i.total_cost",
+		"ROUND((i.estimated_value - i.total_cost) / i.total_cost * 100, 2)",
+	).
+		From("inventory i").
+		Where("i.deleted_at IS NULL").
+		Where("i.estimated_value IS NOT NULL").
+		Where("i.total_cost > 0").
+		Where("ABS(i.estimated_value - i.total_cost) > i.total_cost * ? / 100", params.MinPercent).
+		OrderBy("ABS(i.estimated_value - i.total_cost) / i.total_cost DESC", "i.lot_id").
+		Limit(uint64(params.Limit))
+}
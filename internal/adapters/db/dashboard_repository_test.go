@@ -0,0 +1,275 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/adapters/db"
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+func TestBuildCategoryBreakdownQuery(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filters   ports.DashboardFilters
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "all_active_items",
+			wantWhere: "WHERE i.deleted_at IS NULL GROUP BY",
+		},
+		{
+			name:      "acquired_in_range",
+			filters:   ports.DashboardFilters{From: &from, To: &to},
+			wantWhere: "WHERE i.deleted_at IS NULL AND i.acquisition_date >= $1 AND i.acquisition_date < $2 GROUP BY",
+			wantArgs:  []interface{}{from, to},
+		},
+		{
+			name:      "open_start",
+			filters:   ports.DashboardFilters{To: &to},
+			wantWhere: "WHERE i.deleted_at IS NULL AND i.acquisition_date < $1 GROUP BY",
+			wantArgs:  []interface{}{to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := db.BuildCategoryBreakdownQuery(tt.filters).ToSql()
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(sql,
+				"SELECT COALESCE(i.category::text, 'other'), COUNT(*), COALESCE(SUM(i.total_cost), 0), "+
+					"COALESCE(SUM(i.estimated_value), 0), COUNT(s.sold_price), "), sql)
+			assert.Contains(t, sql, "FROM inventory i LEFT JOIN LATERAL (")
+			assert.Contains(t, sql, tt.wantWhere)
+			assert.True(t, strings.HasSuffix(sql, "GROUP BY 1 ORDER BY 3 DESC, 1"), sql)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestDashboardRepository_CategoryBreakdown_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	inventory := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	repo := db.NewDashboardRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	march := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	newItem := func(category domain.ItemCategory, bid, value int64, acquired time.Time) *domain.InventoryItem {
+		item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+			v := decimal.NewFromInt(value)
+			i.Category = category
+			i.BidAmount = decimal.NewFromInt(bid)
+			i.EstimatedValue = &v
+			i.AcquisitionDate = acquired
+		})
+		require.NoError(t, inventory.Save(ctx, item))
+		return item
+	}
+	sell := func(item *domain.InventoryItem, price, fees decimal.Decimal) {
+		_, err := testDB.PgxPool.Exec(ctx, `
+			INSERT INTO platform_listings (lot_id, platform, status, list_price, sold_price, platform_fees, sold_date)
+			VALUES ($1, 'ebay', 'sold', $2::numeric, $2::numeric, $3::numeric, NOW())`,
+			item.LotID, price.String(), fees.String())
+		require.NoError(t, err)
+	}
+
+	furnitureA := newItem(domain.CategoryFurniture, 100, 300, march(2))
+	furnitureB := newItem(domain.CategoryFurniture, 200, 250, march(10))
+	furnitureC := newItem(domain.CategoryFurniture, 50, 80, march(20))
+	art := newItem(domain.CategoryArt, 40, 120, march(5))
+	deleted := newItem(domain.CategoryAntiques, 500, 900, march(5))
+	require.NoError(t, inventory.SoftDelete(ctx, deleted.LotID))
+	artApril := newItem(domain.CategoryArt, 10, 15, time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC))
+
+	// ROI is (sold - fees - cost) / cost: 100% on the first furniture sale, 0% on the second
+	fees := decimal.NewFromInt(10)
+	sell(furnitureA, furnitureA.TotalCost.Mul(decimal.NewFromInt(2)).Add(fees), fees)
+	sell(furnitureB, furnitureB.TotalCost, decimal.Zero)
+
+	byCategory := func(categories []ports.CategoryBreakdown) map[string]ports.CategoryBreakdown {
+		got := make(map[string]ports.CategoryBreakdown, len(categories))
+		for _, c := range categories {
+			got[c.Category] = c
+		}
+		return got
+	}
+
+	all, err := repo.CategoryBreakdown(ctx, ports.DashboardFilters{})
+	require.NoError(t, err)
+	require.Len(t, all, 2, "deleted items and empty categories are omitted")
+	assert.Equal(t, string(domain.CategoryFurniture), all[0].Category, "largest total cost first")
+
+	furniture := byCategory(all)[string(domain.CategoryFurniture)]
+	assert.Equal(t, 3, furniture.Count)
+	assert.Equal(t, 2, furniture.SoldCount)
+	assert.True(t, decimal.NewFromInt(630).Equal(furniture.EstimatedValue), "estimated value %s", furniture.EstimatedValue)
+	wantCost := furnitureA.TotalCost.Add(furnitureB.TotalCost).Add(furnitureC.TotalCost)
+	assert.True(t, wantCost.Equal(furniture.TotalCost), "total cost %s, want %s", furniture.TotalCost, wantCost)
+	assert.True(t, decimal.NewFromInt(50).Equal(furniture.AvgROI), "average ROI %s", furniture.AvgROI)
+
+	artSummary := byCategory(all)[string(domain.CategoryArt)]
+	assert.Equal(t, 2, artSummary.Count)
+	assert.Zero(t, artSummary.SoldCount)
+	assert.True(t, artSummary.AvgROI.IsZero())
+	assert.True(t, art.TotalCost.Add(artApril.TotalCost).Equal(artSummary.TotalCost), "art total cost %s", artSummary.TotalCost)
+
+	// Only items acquired in March count toward the filtered breakdown
+	from, to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	march24, err := repo.CategoryBreakdown(ctx, ports.DashboardFilters{From: &from, To: &to})
+	require.NoError(t, err)
+	artMarch := byCategory(march24)[string(domain.CategoryArt)]
+	assert.Equal(t, 1, artMarch.Count)
+	assert.True(t, decimal.NewFromInt(120).Equal(artMarch.EstimatedValue))
+	assert.True(t, art.TotalCost.Equal(artMarch.TotalCost))
+	assert.Equal(t, 3, byCategory(march24)[string(domain.CategoryFurniture)].Count)
+}
+
+func TestBuildAgingInventoryQuery(t *testing.T) {
+	sql, args, err := db.BuildAgingInventoryQuery(ports.AgingParams{MinDays: 90, Limit: 50}).ToSql()
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "EXTRACT(DAY FROM NOW() - i.acquisition_date)::int")
+	assert.Contains(t, sql, "WHERE i.deleted_at IS NULL AND i.acquisition_date < NOW() - make_interval(days => $1) AND NOT EXISTS (")
+	assert.Contains(t, sql, "pl.status = 'sold'")
+	assert.True(t, strings.HasSuffix(sql, "ORDER BY i.acquisition_date, i.total_cost DESC, i.lot_id LIMIT 50"), sql)
+	assert.Equal(t, []interface{}{90}, args)
+}
+
+func TestDashboardRepository_AgingInventory_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	inventory := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	repo := db.NewDashboardRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	newItem := func(daysAgo int, bid int64) *domain.InventoryItem {
+		item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+			i.BidAmount = decimal.NewFromInt(bid)
+			i.AcquisitionDate = now.AddDate(0, 0, -daysAgo)
+		})
+		require.NoError(t, inventory.Save(ctx, item))
+		return item
+	}
+	list := func(item *domain.InventoryItem, status string) {
+		_, err := testDB.PgxPool.Exec(ctx, `
+			INSERT INTO platform_listings (lot_id, platform, status, list_price)
+			VALUES ($1, 'ebay', $2, 100)`, item.LotID, status)
+		require.NoError(t, err)
+	}
+
+	oldCostly := newItem(200, 300)
+	oldListed := newItem(200, 50)
+	list(oldListed, "active")
+	middling := newItem(120, 100)
+	sold := newItem(150, 80)
+	list(sold, "sold")
+	newItem(10, 500)
+	deleted := newItem(300, 500)
+	require.NoError(t, inventory.SoftDelete(ctx, deleted.LotID))
+
+	lotIDs := func(items []ports.AgingItem) []string {
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.LotID.String()
+		}
+		return ids
+	}
+
+	items, err := repo.AgingInventory(ctx, ports.AgingParams{MinDays: 90, Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldCostly.LotID.String(), oldListed.LotID.String(), middling.LotID.String()}, lotIDs(items),
+		"oldest first, then by total cost; sold, recent and deleted items are excluded")
+
+	assert.Equal(t, 200, items[0].DaysHeld)
+	assert.Equal(t, 120, items[2].DaysHeld)
+	assert.True(t, oldCostly.TotalCost.Equal(items[0].TotalCost))
+	assert.Equal(t, oldCostly.ItemName, items[0].ItemName)
+	assert.Equal(t, oldCostly.Category, items[0].Category)
+
+	// The threshold and limit narrow the report
+	items, err = repo.AgingInventory(ctx, ports.AgingParams{MinDays: 150, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldCostly.LotID.String()}, lotIDs(items))
+}
+
+func TestBuildValueVarianceQuery(t *testing.T) {
+	sql, args, err := db.BuildValueVarianceQuery(ports.VarianceParams{MinPercent: decimal.NewFromInt(25), Limit: 50}).ToSql()
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "i.estimated_value - i.total_cost, ROUND((i.estimated_value - i.total_cost) / i.total_cost * 100, 2)")
+	assert.Contains(t, sql, "WHERE i.deleted_at IS NULL AND i.estimated_value IS NOT NULL AND i.total_cost > 0 "+
+		"AND ABS(i.estimated_value - i.total_cost) > i.total_cost * $1 / 100")
+	assert.True(t, strings.HasSuffix(sql,
+		"ORDER BY ABS(i.estimated_value - i.total_cost) / i.total_cost DESC, i.lot_id LIMIT 50"), sql)
+	require.Len(t, args, 1)
+	assert.True(t, decimal.NewFromInt(25).Equal(args[0].(decimal.Decimal)))
+}
+
+func TestDashboardRepository_ValueVariance_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	inventory := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	repo := db.NewDashboardRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	// Every item costs 100 in total, so each estimate reads as a percentage
+	newItem := func(value *int64) *domain.InventoryItem {
+		item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+			i.BidAmount = decimal.NewFromInt(100)
+			i.BuyersPremium, i.SalesTax, i.ShippingCost = decimal.Zero, decimal.Zero, decimal.Zero
+			i.EstimatedValue = nil
+			if value != nil {
+				v := decimal.NewFromInt(*value)
+				i.EstimatedValue = &v
+			}
+		})
+		require.NoError(t, inventory.Save(ctx, item))
+		return item
+	}
+	value := func(v int64) *int64 { return &v }
+
+	overValued := newItem(value(200))
+	underValued := newItem(value(60))
+	newItem(value(110)) // within 25%
+	newItem(value(80))  // within 25%
+	newItem(nil)
+	deleted := newItem(value(300))
+	require.NoError(t, inventory.SoftDelete(ctx, deleted.LotID))
+
+	items, err := repo.ValueVariance(ctx, ports.VarianceParams{MinPercent: decimal.NewFromInt(25), Limit: 50})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	assert.Equal(t, overValued.LotID, items[0].LotID, "largest relative variance first")
+	assert.True(t, decimal.NewFromInt(100).Equal(items[0].VariancePercent), "variance %s", items[0].VariancePercent)
+	assert.True(t, decimal.NewFromInt(100).Equal(items[0].TotalCost), "total cost %s", items[0].TotalCost)
+	assert.True(t, decimal.NewFromInt(100).Equal(items[0].Variance), "variance %s", items[0].Variance)
+
+	assert.Equal(t, underValued.LotID, items[1].LotID)
+	assert.True(t, decimal.NewFromInt(-40).Equal(items[1].VariancePercent), "variance %s", items[1].VariancePercent)
+	assert.True(t, decimal.NewFromInt(-40).Equal(items[1].Variance), "variance %s", items[1].Variance)
+	assert.True(t, decimal.NewFromInt(60).Equal(items[1].EstimatedValue))
+
+	// A lower threshold takes in the items inside 25%
+	items, err = repo.ValueVariance(ctx, ports.VarianceParams{MinPercent: decimal.NewFromInt(5), Limit: 50})
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+}
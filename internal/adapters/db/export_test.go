@@ -0,0 +1,119 @@
+package db
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// BuildListQueries exposes the FindAll query builder to external tests, which
+// can then verify the generated SQL without a database
+func BuildListQueries(params ports.ListParams) (squirrel.SelectBuilder, squirrel.SelectBuilder) {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildListQueries(params)
+}
+
+// BuildBulkUpdateQuery exposes the BulkUpdateFields query builder to external tests
+func BuildBulkUpdateQuery(lotIDs []uuid.UUID, updates ports.BulkFieldUpdates, now time.Time) squirrel.UpdateBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildBulkUpdateQuery(lotIDs, updates, now)
+}
+
+// BuildPageQuery exposes the FindAll data query, including ordering and pagination
+func BuildPageQuery(params ports.ListParams) squirrel.SelectBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	dataQuery, _ := r.buildListQueries(params)
+	return r.applyPagination(dataQuery, params)
+}
+
+// BuildTotalsQuery exposes the SumTotals query builder to external tests
+func BuildTotalsQuery(params ports.ListParams) squirrel.SelectBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildTotalsQuery(params)
+}
+
+// BuildFillQRCodesQuery exposes the FillQRCodes query builder to external tests
+func BuildFillQRCodesQuery(template domain.QRTemplate, now time.Time) squirrel.UpdateBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildFillQRCodesQuery(template, now)
+}
+
+// BuildInvoiceQueries exposes the ListInvoices query builders to external tests
+func BuildInvoiceQueries(params ports.InvoiceListParams) (squirrel.SelectBuilder, squirrel.SelectBuilder) {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildInvoiceQueries(params)
+}
+
+// BuildStorageSummaryQuery exposes the StorageSummary query builder to external tests
+func BuildStorageSummaryQuery() squirrel.SelectBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildStorageSummaryQuery()
+}
+
+// BuildHighlightsQuery exposes the SearchHighlights query builder to external tests
+func BuildHighlightsQuery(search string, lotIDs []uuid.UUID) squirrel.SelectBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildHighlightsQuery(search, lotIDs)
+}
+
+// LikePrefixPattern exposes the Suggest prefix escaping to external tests
+func LikePrefixPattern(prefix string) string {
+	return likePrefixPattern(prefix)
+}
+
+// BuildUpsertByLotIDQuery exposes the lot_id upsert builder used by SaveBatchUpsert
+func BuildUpsertByLotIDQuery(item *domain.InventoryItem) squirrel.InsertBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildUpsertByLotIDQuery(item)
+}
+
+// BuildUpdateByInvoiceItemQuery exposes the natural-key update used by SaveBatchUpsert
+func BuildUpdateByInvoiceItemQuery(item *domain.InventoryItem) squirrel.UpdateBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildUpdateByInvoiceItemQuery(item)
+}
+
+// BuildUpdateQuery exposes the Update query builder to external tests
+func BuildUpdateQuery(item *domain.InventoryItem) squirrel.UpdateBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildUpdateQuery(item)
+}
+
+// BuildPatchQuery exposes the UpdatePartial query builder to external tests
+func BuildPatchQuery(lotID uuid.UUID, patch ports.InventoryPatch, now time.Time) squirrel.UpdateBuilder {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildPatchQuery(lotID, patch, now)
+}
+
+// DiffInventoryItems exposes the audit field diff to external tests
+func DiffInventoryItems(before, after *domain.InventoryItem) (map[string]domain.FieldChange, error) {
+	return diffInventoryItems(before, after)
+}
+
+// BuildAuditInsertQuery exposes the audit row insert builder to external tests
+func BuildAuditInsertQuery(op domain.AuditOperation, lotID uuid.UUID, changes map[string]domain.FieldChange, userID string) (squirrel.InsertBuilder, error) {
+	r := &inventoryRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildAuditInsertQuery(op, lotID, changes, userID)
+}
+
+// BuildCategoryBreakdownQuery exposes the CategoryBreakdown query builder to external tests
+func BuildCategoryBreakdownQuery(filters ports.DashboardFilters) squirrel.SelectBuilder {
+	r := &dashboardRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildCategoryBreakdownQuery(filters)
+}
+
+// BuildAgingInventoryQuery exposes the AgingInventory query builder to external tests
+func BuildAgingInventoryQuery(params ports.AgingParams) squirrel.SelectBuilder {
+	r := &dashboardRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildAgingInventoryQuery(params)
+}
+
+// BuildValueVarianceQuery exposes the ValueVariance query builder to external tests
+func BuildValueVarianceQuery(params ports.VarianceParams) squirrel.SelectBuilder {
+	r := &dashboardRepository{qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)}
+	return r.buildValueVarianceQuery(params)
+}
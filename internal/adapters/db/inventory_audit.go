@@ -0,0 +1,166 @@
+// internal/adapters/db/inventory_audit.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// auditIgnoredFields change on every write, so recording them would only add noise;
+// the writer is already kept in the entry's user_id
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+	"updated_by": true,
+	"version":    true,
+}
+
+// FindHistory returns the audit trail for an item, newest first. Entries are kept after
+// the item is deleted.
+func (r *inventoryRepository) FindHistory(ctx context.Context, lotID uuid.UUID) ([]domain.AuditEntry, error) {
+	sql, args, err := r.buildHistoryQuery(lotID).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build history query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// writeAudit records a write in the caller's transaction so the entry commits or rolls back with it
+func (r *inventoryRepository) writeAudit(ctx context.Context, tx pgx.Tx, op domain.AuditOperation, lotID uuid.UUID, changes map[string]domain.FieldChange) error {
+	query, err := r.buildAuditInsertQuery(op, lotID, changes, logger.UserIDFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build audit insert query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// buildAuditInsertQuery builds the insert for one audit row; an empty userID is stored as NULL
+func (r *inventoryRepository) buildAuditInsertQuery(op domain.AuditOperation, lotID uuid.UUID, changes map[string]domain.FieldChange, userID string) (squirrel.InsertBuilder, error) {
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return squirrel.InsertBuilder{}, fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	var user interface{}
+	if userID != "" {
+		user = userID
+	}
+
+	return r.qb.Insert("inventory_audit").
+		Columns("lot_id", "operation", "changes", "user_id").
+		Values(lotID, string(op), string(data), user), nil
+}
+
+// buildHistoryQuery selects an item's audit rows, newest first
+func (r *inventoryRepository) buildHistoryQuery(lotID uuid.UUID) squirrel.SelectBuilder {
+	return r.qb.Select("id", "lot_id", "operation", "changes", "user_id", "created_at").
+		From("inventory_audit").
+		Where(squirrel.Eq{"lot_id": lotID}).
+		OrderBy("created_at DESC", "id DESC")
+}
+
+// scanAuditEntry scans a single audit row
+func scanAuditEntry(row pgx.Row) (*domain.AuditEntry, error) {
+	entry := &domain.AuditEntry{}
+	var changes []byte
+	var userID sql.NullString
+
+	if err := row.Scan(&entry.ID, &entry.LotID, &entry.Operation, &changes, &userID, &entry.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+	}
+
+	if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+	}
+	entry.UserID = userID.String
+
+	return entry, nil
+}
+
+// diffInventoryItems returns the fields whose JSON values differ between before and after.
+// A nil before records a create and a nil after records a delete.
+func diffInventoryItems(before, after *domain.InventoryItem) (map[string]domain.FieldChange, error) {
+	oldFields, err := inventoryFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := inventoryFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	for field, oldValue := range oldFields {
+		if newValue := newFields[field]; !reflect.DeepEqual(oldValue, newValue) {
+			changes[field] = domain.FieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	for field, newValue := range newFields {
+		if _, seen := oldFields[field]; !seen {
+			changes[field] = domain.FieldChange{New: newValue}
+		}
+	}
+
+	for field := range auditIgnoredFields {
+		delete(changes, field)
+	}
+
+	return changes, nil
+}
+
+// inventoryFields flattens an item to its JSON fields so values compare the way the API shows them
+func inventoryFields(item *domain.InventoryItem) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if item == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode inventory item for audit: %w", err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode inventory item for audit: %w", err)
+	}
+
+	return fields, nil
+}
@@ -35,44 +35,39 @@ func NewInventoryRepository(db *Database, logger *slog.Logger) ports.InventoryRe
 	}
 }
 
-// Save creates a new inventory item with all fields properly handled
+// Save creates a new inventory item with all fields properly handled and records it in the audit trail
 func (r *inventoryRepository) Save(ctx context.Context, item *domain.InventoryItem) error {
 	query := r.qb.Insert("inventory").
-		Columns(
-			"lot_id", "invoice_id", "auction_id", "item_name", "description",
-			"category", "subcategory", "condition", "quantity",
-			"bid_amount", "buyers_premium", "sales_tax", "shipping_cost",
-			"acquisition_date", "storage_location", "storage_bin", "qr_code",
-			"estimated_value", "market_demand", "seasonality_notes",
-			"needs_repair", "is_consignment", "is_returned",
-			"keywords", "notes", "created_at", "updated_at",
-		).
-		Values(
-			item.LotID, item.InvoiceID, item.AuctionID, item.ItemName, item.Description,
-			item.Category, item.Subcategory, item.Condition, item.Quantity,
-			item.BidAmount, item.BuyersPremium, item.SalesTax, item.ShippingCost,
-			item.AcquisitionDate, item.StorageLocation, item.StorageBin, item.QRCode,
-			item.EstimatedValue, item.MarketDemand, item.SeasonalityNotes,
-			item.NeedsRepair, item.IsConsignment, item.IsReturned,
-			strings.Join(item.Keywords, ","), item.Notes, item.CreatedAt, item.UpdatedAt,
-		).
-		Suffix("RETURNING lot_id, total_cost, cost_per_item, created_at, updated_at")
+		Columns(inventoryWriteColumns...).
+		Values(inventoryWriteValues(item)...).
+		Suffix("RETURNING lot_id, total_cost, cost_per_item, created_at, updated_at, version")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build insert query: %w", err)
 	}
 
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
-		&item.LotID,
-		&item.TotalCost,
-		&item.CostPerItem,
-		&item.CreatedAt,
-		&item.UpdatedAt,
-	)
+	err = r.db.Transaction(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, sql, args...).Scan(
+			&item.LotID,
+			&item.TotalCost,
+			&item.CostPerItem,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save inventory item: %w", err)
+		}
 
+		changes, err := diffInventoryItems(nil, item)
+		if err != nil {
+			return err
+		}
+		return r.writeAudit(ctx, tx, domain.AuditCreate, item.LotID, changes)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save inventory item: %w", err)
+		return err
 	}
 
 	r.logger.DebugContext(ctx, "inventory item saved",
@@ -89,108 +84,139 @@ func (r *inventoryRepository) SaveBatch(ctx context.Context, items []domain.Inve
 	}
 
 	return r.db.Transaction(ctx, func(tx pgx.Tx) error {
-		batch := &pgx.Batch{}
-
-		insertQuery := r.qb.Insert("inventory").
-			Columns(
-				"lot_id", "invoice_id", "auction_id", "item_name", "description",
-				"category", "subcategory", "condition", "quantity",
-				"bid_amount", "buyers_premium", "sales_tax", "shipping_cost",
-				"acquisition_date", "storage_location", "storage_bin", "qr_code",
-				"estimated_value", "market_demand", "seasonality_notes",
-				"needs_repair", "is_consignment", "is_returned",
-				"keywords", "notes", "created_at", "updated_at",
-			).
-			Suffix("RETURNING lot_id, total_cost, cost_per_item")
-
-		for i := range items {
-			keywordsStr := strings.Join(items[i].Keywords, ",")
-
-			sql, args, err := insertQuery.Values(
-				items[i].LotID, items[i].InvoiceID, items[i].AuctionID, items[i].ItemName, items[i].Description,
-				items[i].Category, items[i].Subcategory, items[i].Condition, items[i].Quantity,
-				items[i].BidAmount, items[i].BuyersPremium, items[i].SalesTax, items[i].ShippingCost,
-				items[i].AcquisitionDate, items[i].StorageLocation, items[i].StorageBin, items[i].QRCode,
-				items[i].EstimatedValue, items[i].MarketDemand, items[i].SeasonalityNotes,
-				items[i].NeedsRepair, items[i].IsConsignment, items[i].IsReturned,
-				keywordsStr, items[i].Notes, items[i].CreatedAt, items[i].UpdatedAt,
-			).ToSql()
-
-			if err != nil {
-				return fmt.Errorf("failed to build batch insert query for item %d: %w", i, err)
+		return r.sendItemBatch(ctx, tx, items, r.buildInsertQuery)
+	})
+}
+
+// SaveBatchUpsert saves items in a single transaction, updating the existing row selected by
+// target instead of failing on a conflict. Each item's LotID is set to the row it was written to.
+func (r *inventoryRepository) SaveBatchUpsert(ctx context.Context, items []domain.InventoryItem, target ports.ConflictTarget) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	switch target {
+	case ports.ConflictOnLotID:
+		return r.db.Transaction(ctx, func(tx pgx.Tx) error {
+			return r.sendItemBatch(ctx, tx, items, r.buildUpsertByLotIDQuery)
+		})
+	case ports.ConflictOnInvoiceItem:
+		// There is no unique index on the natural key (an invoice may list the same name twice),
+		// so ON CONFLICT can't be used; update first and insert when nothing matched
+		return r.db.Transaction(ctx, func(tx pgx.Tx) error {
+			for i := range items {
+				if err := r.upsertByInvoiceItem(ctx, tx, &items[i]); err != nil {
+					return fmt.Errorf("failed to upsert item %d: %w", i, err)
+				}
 			}
+			return nil
+		})
+	default:
+		return fmt.Errorf("unsupported conflict target: %s", target)
+	}
+}
+
+// sendItemBatch queues one statement per item and scans back the stored lot_id and costs
+func (r *inventoryRepository) sendItemBatch(ctx context.Context, tx pgx.Tx, items []domain.InventoryItem, build func(*domain.InventoryItem) squirrel.InsertBuilder) error {
+	batch := &pgx.Batch{}
 
-			batch.Queue(sql, args...)
+	for i := range items {
+		sql, args, err := build(&items[i]).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build batch insert query for item %d: %w", i, err)
 		}
 
-		br := tx.SendBatch(ctx, batch)
-		defer br.Close()
+		batch.Queue(sql, args...)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
 
-		for i := range items {
-			err := br.QueryRow().Scan(
-				&items[i].LotID,
-				&items[i].TotalCost,
-				&items[i].CostPerItem,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to save item %d: %w", i, err)
-			}
+	for i := range items {
+		err := br.QueryRow().Scan(
+			&items[i].LotID,
+			&items[i].TotalCost,
+			&items[i].CostPerItem,
+			&items[i].Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save item %d: %w", i, err)
 		}
+	}
+
+	return nil
+}
+
+// upsertByInvoiceItem updates the active rows matching the item's invoice_id and item_name,
+// inserting the item when there are none
+func (r *inventoryRepository) upsertByInvoiceItem(ctx context.Context, tx pgx.Tx, item *domain.InventoryItem) error {
+	sql, args, err := r.buildUpdateByInvoiceItemQuery(item).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
 
+	err = tx.QueryRow(ctx, sql, args...).Scan(&item.LotID, &item.TotalCost, &item.CostPerItem, &item.Version)
+	if err == nil {
 		return nil
-	})
+	}
+	if err != pgx.ErrNoRows {
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+
+	sql, args, err = r.buildInsertQuery(item).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&item.LotID, &item.TotalCost, &item.CostPerItem, &item.Version); err != nil {
+		return fmt.Errorf("failed to insert item: %w", err)
+	}
+	return nil
 }
 
-// Update updates an existing inventory item
+// Update updates an existing inventory item and records the changed fields in the audit trail.
+// When item.Version is set the update only applies to that version, returning
+// domain.ErrVersionConflict if the item has since changed.
 func (r *inventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
 	item.UpdatedAt = time.Now()
-	keywordsStr := strings.Join(item.Keywords, ",")
 
-	query := r.qb.Update("inventory").
-		Set("invoice_id", item.InvoiceID).
-		Set("auction_id", item.AuctionID).
-		Set("item_name", item.ItemName).
-		Set("description", item.Description).
-		Set("category", item.Category).
-		Set("subcategory", item.Subcategory).
-		Set("condition", item.Condition).
-		Set("quantity", item.Quantity).
-		Set("bid_amount", item.BidAmount).
-		Set("buyers_premium", item.BuyersPremium).
-		Set("sales_tax", item.SalesTax).
-		Set("shipping_cost", item.ShippingCost).
-		Set("acquisition_date", item.AcquisitionDate).
-		Set("storage_location", item.StorageLocation).
-		Set("storage_bin", item.StorageBin).
-		Set("qr_code", item.QRCode).
-		Set("estimated_value", item.EstimatedValue).
-		Set("market_demand", item.MarketDemand).
-		Set("seasonality_notes", item.SeasonalityNotes).
-		Set("needs_repair", item.NeedsRepair).
-		Set("is_consignment", item.IsConsignment).
-		Set("is_returned", item.IsReturned).
-		Set("keywords", keywordsStr).
-		Set("notes", item.Notes).
-		Set("updated_at", item.UpdatedAt).
-		Where(squirrel.Eq{"lot_id": item.LotID}).
-		Where("deleted_at IS NULL").
-		Suffix("RETURNING total_cost, cost_per_item")
+	query := r.buildUpdateQuery(item)
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build update query: %w", err)
 	}
 
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
-		&item.TotalCost,
-		&item.CostPerItem,
-	)
+	err = r.db.Transaction(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockActiveItem(ctx, tx, item.LotID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, item.LotID)
+		}
 
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return fmt.Errorf("inventory item not found: %s", item.LotID)
+		after, err := r.scanInventoryItem(tx.QueryRow(ctx, sql, args...))
+		if err != nil {
+			return fmt.Errorf("failed to update inventory item: %w", err)
 		}
-		return fmt.Errorf("failed to update inventory item: %w", err)
+		// The row is locked and active, so no match means the version check failed
+		if after == nil {
+			return fmt.Errorf("%w: %s expected version %d", domain.ErrVersionConflict, item.LotID, item.Version)
+		}
+
+		item.TotalCost = after.TotalCost
+		item.CostPerItem = after.CostPerItem
+		item.Version = after.Version
+
+		changes, err := diffInventoryItems(before, after)
+		if err != nil {
+			return err
+		}
+		return r.writeAudit(ctx, tx, domain.AuditUpdate, item.LotID, changes)
+	})
+	if err != nil {
+		return err
 	}
 
 	r.logger.DebugContext(ctx, "inventory item updated",
@@ -199,6 +225,50 @@ func (r *inventoryRepository) Update(ctx context.Context, item *domain.Inventory
 	return nil
 }
 
+// UpdatePartial writes only the fields set in the patch, clearing those set to null, and
+// records the changes in the audit trail. Like BaseRepository.UpdatePartial it updates a column
+// map, but inside the locked transaction Update uses so versions and history stay consistent.
+// It returns the stored item.
+func (r *inventoryRepository) UpdatePartial(ctx context.Context, lotID uuid.UUID, patch ports.InventoryPatch) (*domain.InventoryItem, error) {
+	sql, args, err := r.buildPatchQuery(lotID, patch, time.Now()).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build patch query: %w", err)
+	}
+
+	var after *domain.InventoryItem
+	err = r.db.Transaction(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockActiveItem(ctx, tx, lotID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
+		}
+
+		after, err = r.scanInventoryItem(tx.QueryRow(ctx, sql, args...))
+		if err != nil {
+			return fmt.Errorf("failed to patch inventory item: %w", err)
+		}
+		if after == nil {
+			return fmt.Errorf("%w: %s expected version %d", domain.ErrVersionConflict, lotID, patch.Version)
+		}
+
+		changes, err := diffInventoryItems(before, after)
+		if err != nil {
+			return err
+		}
+		return r.writeAudit(ctx, tx, domain.AuditUpdate, lotID, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.DebugContext(ctx, "inventory item patched",
+		slog.String("lot_id", lotID.String()))
+
+	return after, nil
+}
+
 // FindByID retrieves a single inventory item by ID
 func (r *inventoryRepository) FindByID(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error) {
 	query := r.qb.Select(r.inventoryColumns()...).
@@ -240,143 +310,284 @@ func (r *inventoryRepository) FindByInvoiceID(ctx context.Context, invoiceID str
 // FindAll retrieves inventory items with comprehensive filtering, sorting, and pagination
 // This is the SINGLE source of truth for inventory queries - all filtering logic lives here
 func (r *inventoryRepository) FindAll(ctx context.Context, params ports.ListParams) ([]*domain.InventoryItem, int64, error) {
-	// Build the base query with all columns
-	baseQuery := r.qb.Select(r.inventoryColumns()...).
-		From("inventory").
-		Where("deleted_at IS NULL")
+	baseQuery, countQuery := r.buildListQueries(params)
 
-	// Apply search filter using PostgreSQL's full-text search
-	if params.Search != "" {
-		baseQuery = baseQuery.Where(
-			"search_vector @@ plainto_tsquery('english', ?)",
-			params.Search,
-		)
+	// Execute count query
+	countSQL, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
 	}
 
-	// Apply category filter
-	if params.Category != "" {
-		baseQuery = baseQuery.Where(squirrel.Eq{"category": params.Category})
+	var totalCount int64
+	err = r.db.QueryRow(ctx, countSQL, countArgs...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count inventory items: %w", err)
 	}
 
-	// Apply condition filter
-	if params.Condition != "" {
-		baseQuery = baseQuery.Where(squirrel.Eq{"condition": params.Condition})
+	// Apply sorting and pagination
+	baseQuery = r.applyPagination(baseQuery, params)
+
+	// Execute main query
+	sql, args, err := baseQuery.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	// Apply storage location filter
-	if params.StorageLocation != "" {
-		baseQuery = baseQuery.Where(squirrel.Eq{"storage_location": params.StorageLocation})
+	r.logger.DebugContext(ctx, "executing inventory query",
+		slog.String("sql", sql),
+		slog.Any("args", args))
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query inventory items: %w", err)
 	}
+	defer rows.Close()
 
-	// Apply storage bin filter
-	if params.StorageBin != "" {
-		baseQuery = baseQuery.Where(squirrel.Eq{"storage_bin": params.StorageBin})
+	items, err := r.scanInventoryItemPointers(rows)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Apply invoice ID filter
-	if params.InvoiceID != "" {
-		baseQuery = baseQuery.Where(squirrel.Eq{"invoice_id": params.InvoiceID})
+	return items, totalCount, nil
+}
+
+// SumTotals computes cost and estimated value sums over all items matching the list filters
+func (r *inventoryRepository) SumTotals(ctx context.Context, params ports.ListParams) (*ports.ListTotals, error) {
+	sql, args, err := r.buildTotalsQuery(params).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build totals query: %w", err)
 	}
 
-	// Apply needs repair filter
-	if params.NeedsRepair != nil {
-		baseQuery = baseQuery.Where(squirrel.Eq{"needs_repair": *params.NeedsRepair})
+	var totals ports.ListTotals
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&totals.TotalCost, &totals.EstimatedValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum inventory totals: %w", err)
 	}
 
-	// First, get the total count before pagination
-	countQuery := r.qb.Select("COUNT(*)").
-		From("inventory").
-		Where("deleted_at IS NULL")
+	return &totals, nil
+}
 
-	// Apply the same filters to the count query
-	if params.Search != "" {
-		countQuery = countQuery.Where(
-			"search_vector @@ plainto_tsquery('english', ?)",
-			params.Search,
-		)
-	}
-	if params.Category != "" {
-		countQuery = countQuery.Where(squirrel.Eq{"category": params.Category})
+// ListInvoices groups the active items by invoice, returning a page of invoice summaries and
+// the number of distinct invoices
+func (r *inventoryRepository) ListInvoices(ctx context.Context, params ports.InvoiceListParams) ([]ports.InvoiceSummary, int64, error) {
+	pageQuery, countQuery := r.buildInvoiceQueries(params)
+
+	countSQL, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build invoice count query: %w", err)
 	}
-	if params.Condition != "" {
-		countQuery = countQuery.Where(squirrel.Eq{"condition": params.Condition})
+	var totalCount int64
+	if err := r.db.QueryRow(ctx, countSQL, countArgs...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
 	}
-	if params.StorageLocation != "" {
-		countQuery = countQuery.Where(squirrel.Eq{"storage_location": params.StorageLocation})
+
+	sql, args, err := pageQuery.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build invoice query: %w", err)
 	}
-	if params.StorageBin != "" {
-		countQuery = countQuery.Where(squirrel.Eq{"storage_bin": params.StorageBin})
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query invoices: %w", err)
 	}
-	if params.InvoiceID != "" {
-		countQuery = countQuery.Where(squirrel.Eq{"invoice_id": params.InvoiceID})
+	defer rows.Close()
+
+	invoices := make([]ports.InvoiceSummary, 0)
+	for rows.Next() {
+		var invoice ports.InvoiceSummary
+		if err := rows.Scan(&invoice.InvoiceID, &invoice.AuctionID, &invoice.AcquisitionDate,
+			&invoice.ItemCount, &invoice.TotalCost); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
 	}
-	if params.NeedsRepair != nil {
-		countQuery = countQuery.Where(squirrel.Eq{"needs_repair": *params.NeedsRepair})
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate invoices: %w", err)
 	}
 
-	// Execute count query
-	countSQL, countArgs, err := countQuery.ToSql()
+	return invoices, totalCount, nil
+}
+
+// StorageSummary counts the active items and sums their estimated value per storage location
+// and bin, ordered by location then bin. Empty locations and bins are grouped under
+// ports.UnassignedStorage.
+func (r *inventoryRepository) StorageSummary(ctx context.Context) ([]ports.StorageBinSummary, error) {
+	sql, args, err := r.buildStorageSummaryQuery().ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+		return nil, fmt.Errorf("failed to build storage summary query: %w", err)
 	}
 
-	var totalCount int64
-	err = r.db.QueryRow(ctx, countSQL, countArgs...).Scan(&totalCount)
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count inventory items: %w", err)
+		return nil, fmt.Errorf("failed to query storage summary: %w", err)
 	}
+	defer rows.Close()
 
-	// Apply sorting
-	orderBy := r.buildOrderBy(params.SortBy, params.SortOrder)
-	baseQuery = baseQuery.OrderBy(orderBy)
-
-	// Apply pagination
-	if params.PageSize > 0 {
-		offset := (params.Page - 1) * params.PageSize
-		baseQuery = baseQuery.Limit(uint64(params.PageSize)).Offset(uint64(offset))
+	bins := make([]ports.StorageBinSummary, 0)
+	for rows.Next() {
+		var bin ports.StorageBinSummary
+		if err := rows.Scan(&bin.Location, &bin.Bin, &bin.ItemCount, &bin.EstimatedValue); err != nil {
+			return nil, fmt.Errorf("failed to scan storage bin: %w", err)
+		}
+		bins = append(bins, bin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate storage bins: %w", err)
 	}
 
-	// Execute main query
-	sql, args, err := baseQuery.ToSql()
+	return bins, nil
+}
+
+// timeSeriesQuery buckets acquisitions by acquisition date and sales by sold date with
+// date_trunc ($1 is the interval) in UTC, over [$2, $3). Only buckets with activity are returned.
+const timeSeriesQuery = `
+SELECT bucket, SUM(acquisitions), SUM(spend), SUM(items_sold), SUM(net_profit)
+FROM (
+	SELECT date_trunc($1, acquisition_date AT TIME ZONE 'UTC') AS bucket,
+		1 AS acquisitions, COALESCE(total_cost, 0) AS spend, 0 AS items_sold, 0 AS net_profit
+	FROM inventory
+	WHERE deleted_at IS NULL AND acquisition_date >= $2 AND acquisition_date < $3
+	UNION ALL
+	SELECT date_trunc($1, pl.sold_date AT TIME ZONE 'UTC'),
+		0, 0, 1, pl.sold_price - COALESCE(pl.platform_fees, 0) - COALESCE(i.total_cost, 0)
+	FROM platform_listings pl
+	JOIN inventory i ON i.lot_id = pl.lot_id
+	WHERE i.deleted_at IS NULL AND pl.status = 'sold' AND pl.sold_price IS NOT NULL
+		AND pl.sold_date >= $2 AND pl.sold_date < $3
+) events
+GROUP BY bucket
+ORDER BY bucket`
+
+// TimeSeries totals acquisitions, spend, sales and net profit per interval, ordered by bucket
+func (r *inventoryRepository) TimeSeries(ctx context.Context, params ports.TimeSeriesParams) ([]ports.TimeSeriesBucket, error) {
+	rows, err := r.db.Query(ctx, timeSeriesQuery, params.Interval, params.From, params.To)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+		return nil, fmt.Errorf("failed to query time series: %w", err)
 	}
+	defer rows.Close()
 
-	r.logger.DebugContext(ctx, "executing inventory query",
-		slog.String("sql", sql),
-		slog.Any("args", args))
+	buckets := make([]ports.TimeSeriesBucket, 0)
+	for rows.Next() {
+		var bucket ports.TimeSeriesBucket
+		if err := rows.Scan(&bucket.Start, &bucket.Acquisitions, &bucket.Spend, &bucket.ItemsSold, &bucket.NetProfit); err != nil {
+			return nil, fmt.Errorf("failed to scan time series bucket: %w", err)
+		}
+		bucket.Start = bucket.Start.UTC()
+		buckets = append(buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate time series: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// SearchHighlights returns ts_headline snippets of each listed item's description with the
+// search terms marked. It runs only over a page of IDs since ts_headline is expensive.
+func (r *inventoryRepository) SearchHighlights(ctx context.Context, search string, lotIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	sql, args, err := r.buildHighlightsQuery(search, lotIDs).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build highlights query: %w", err)
+	}
 
 	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query inventory items: %w", err)
+		return nil, fmt.Errorf("failed to query search highlights: %w", err)
 	}
 	defer rows.Close()
 
-	items, err := r.scanInventoryItemPointers(rows)
+	highlights := make(map[uuid.UUID]string, len(lotIDs))
+	for rows.Next() {
+		var lotID uuid.UUID
+		var snippet string
+		if err := rows.Scan(&lotID, &snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search highlight: %w", err)
+		}
+		highlights[lotID] = snippet
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search highlights: %w", err)
+	}
+
+	return highlights, nil
+}
+
+// suggestQuery matches item names and individual keywords by prefix, merging case-insensitive
+// duplicates and preferring the shortest matches
+const suggestQuery = `
+SELECT MIN(suggestion)
+FROM (
+	SELECT item_name AS suggestion
+	FROM inventory
+	WHERE deleted_at IS NULL AND item_name ILIKE $1
+	UNION ALL
+	SELECT btrim(keyword)
+	FROM inventory, unnest(string_to_array(keywords, ',')) AS keyword
+	WHERE deleted_at IS NULL AND btrim(keyword) ILIKE $1
+) matches
+GROUP BY lower(suggestion)
+ORDER BY length(MIN(suggestion)), MIN(suggestion)
+LIMIT $2`
+
+// Suggest returns distinct item names and keywords starting with prefix
+func (r *inventoryRepository) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, suggestQuery, likePrefixPattern(prefix), limit)
 	if err != nil {
-		return nil, 0, err
+		return nil, fmt.Errorf("failed to query suggestions: %w", err)
 	}
+	defer rows.Close()
 
-	return items, totalCount, nil
+	suggestions := make([]string, 0, limit)
+	for rows.Next() {
+		var suggestion string
+		if err := rows.Scan(&suggestion); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate suggestions: %w", err)
+	}
+
+	return suggestions, nil
 }
 
-// Delete performs a hard delete of an inventory item
+// likePrefixPattern escapes LIKE wildcards in prefix so it only matches literally
+func likePrefixPattern(prefix string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+	return escaped + "%"
+}
+
+// Delete performs a hard delete of an inventory item. Its audit trail is kept.
 func (r *inventoryRepository) Delete(ctx context.Context, lotID uuid.UUID) error {
 	query := r.qb.Delete("inventory").
-		Where(squirrel.Eq{"lot_id": lotID})
+		Where(squirrel.Eq{"lot_id": lotID}).
+		Suffix("RETURNING " + strings.Join(r.inventoryColumns(), ", "))
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build delete query: %w", err)
 	}
 
-	tag, err := r.db.Exec(ctx, sql, args...)
-	if err != nil {
-		return fmt.Errorf("failed to delete inventory item: %w", err)
-	}
+	err = r.db.Transaction(ctx, func(tx pgx.Tx) error {
+		deleted, err := r.scanInventoryItem(tx.QueryRow(ctx, sql, args...))
+		if err != nil {
+			return fmt.Errorf("failed to delete inventory item: %w", err)
+		}
+		if deleted == nil {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
+		}
 
-	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("inventory item not found: %s", lotID)
+		// The row is gone, so the audit entry keeps its last values
+		changes, err := diffInventoryItems(deleted, nil)
+		if err != nil {
+			return err
+		}
+		return r.writeAudit(ctx, tx, domain.AuditDelete, lotID, changes)
+	})
+	if err != nil {
+		return err
 	}
 
 	r.logger.InfoContext(ctx, "inventory item deleted",
@@ -400,21 +611,123 @@ func (r *inventoryRepository) SoftDelete(ctx context.Context, lotID uuid.UUID) e
 		return fmt.Errorf("failed to build soft delete query: %w", err)
 	}
 
+	err = r.db.Transaction(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return fmt.Errorf("failed to soft delete inventory item: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
+		}
+
+		changes := map[string]domain.FieldChange{"deleted_at": {New: now}}
+		return r.writeAudit(ctx, tx, domain.AuditSoftDelete, lotID, changes)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.InfoContext(ctx, "inventory item soft deleted",
+		slog.String("lot_id", lotID.String()))
+
+	return nil
+}
+
+// SoftDeleteByInvoiceID marks every active item on an invoice as deleted and returns how many were
+func (r *inventoryRepository) SoftDeleteByInvoiceID(ctx context.Context, invoiceID string) (int64, error) {
+	now := time.Now()
+
+	query := r.qb.Update("inventory").
+		Set("deleted_at", now).
+		Set("updated_at", now).
+		Where(squirrel.Eq{"invoice_id": invoiceID}).
+		Where("deleted_at IS NULL")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build soft delete query: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to soft delete invoice items: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "invoice items soft deleted",
+		slog.String("invoice_id", invoiceID),
+		slog.Int64("count", tag.RowsAffected()))
+
+	return tag.RowsAffected(), nil
+}
+
+// Restore clears deleted_at on a soft-deleted item
+func (r *inventoryRepository) Restore(ctx context.Context, lotID uuid.UUID) error {
+	query := r.qb.Update("inventory").
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"lot_id": lotID}).
+		Where("deleted_at IS NOT NULL")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build restore query: %w", err)
+	}
+
 	tag, err := r.db.Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("failed to soft delete inventory item: %w", err)
+		return fmt.Errorf("failed to restore inventory item: %w", err)
 	}
 
+	// Zero rows means the item is missing or was never deleted
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("inventory item not found: %s", lotID)
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
 	}
 
-	r.logger.InfoContext(ctx, "inventory item soft deleted",
+	r.logger.InfoContext(ctx, "inventory item restored",
 		slog.String("lot_id", lotID.String()))
 
 	return nil
 }
 
+// BulkUpdateFields applies the same field updates to every listed item in a single statement
+// and returns the number of rows affected
+func (r *inventoryRepository) BulkUpdateFields(ctx context.Context, lotIDs []uuid.UUID, updates ports.BulkFieldUpdates) (int64, error) {
+	sql, args, err := r.buildBulkUpdateQuery(lotIDs, updates, time.Now()).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build bulk update query: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk update inventory items: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "inventory items bulk updated",
+		slog.Int("requested", len(lotIDs)),
+		slog.Int64("affected", tag.RowsAffected()))
+
+	return tag.RowsAffected(), nil
+}
+
+// FillQRCodes stores the template's payload as the QR code of every active item without one
+func (r *inventoryRepository) FillQRCodes(ctx context.Context, template domain.QRTemplate) (int64, error) {
+	sql, args, err := r.buildFillQRCodesQuery(template, time.Now()).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build qr code query: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fill qr codes: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "inventory qr codes filled",
+		slog.Int64("affected", tag.RowsAffected()))
+
+	return tag.RowsAffected(), nil
+}
+
 // Count returns the total number of non-deleted inventory items
 func (r *inventoryRepository) Count(ctx context.Context) (int64, error) {
 	query := r.qb.Select("COUNT(*)").
@@ -460,8 +773,226 @@ func (r *inventoryRepository) Exists(ctx context.Context, lotID uuid.UUID) (bool
 	return true, nil
 }
 
+// HasInvoice checks if any active item belongs to the invoice
+func (r *inventoryRepository) HasInvoice(ctx context.Context, invoiceID string) (bool, error) {
+	query := r.qb.Select("1").
+		From("inventory").
+		Where(squirrel.Eq{"invoice_id": invoiceID}).
+		Where("deleted_at IS NULL").
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build invoice exists query: %w", err)
+	}
+
+	var exists int
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&exists)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check invoice existence: %w", err)
+	}
+
+	return true, nil
+}
+
 // Helper methods
 
+// lockActiveItem reads an active item and locks its row until the transaction ends.
+// It returns nil when the item is missing or soft-deleted.
+func (r *inventoryRepository) lockActiveItem(ctx context.Context, tx pgx.Tx, lotID uuid.UUID) (*domain.InventoryItem, error) {
+	sql, args, err := r.qb.Select(r.inventoryColumns()...).
+		From("inventory").
+		Where(squirrel.Eq{"lot_id": lotID}).
+		Where("deleted_at IS NULL").
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	return r.scanInventoryItem(tx.QueryRow(ctx, sql, args...))
+}
+
+// buildUpdateQuery builds the full-row update for Update, conditioned on the item's version when set
+func (r *inventoryRepository) buildUpdateQuery(item *domain.InventoryItem) squirrel.UpdateBuilder {
+	query := r.qb.Update("inventory").
+		Set("invoice_id", item.InvoiceID).
+		Set("auction_id", item.AuctionID).
+		Set("item_name", item.ItemName).
+		Set("description", item.Description).
+		Set("category", item.Category).
+		Set("subcategory", item.Subcategory).
+		Set("condition", item.Condition).
+		Set("quantity", item.Quantity).
+		Set("bid_amount", item.BidAmount).
+		Set("buyers_premium", item.BuyersPremium).
+		Set("sales_tax", item.SalesTax).
+		Set("shipping_cost", item.ShippingCost).
+		Set("acquisition_date", item.AcquisitionDate).
+		Set("storage_location", item.StorageLocation).
+		Set("storage_bin", item.StorageBin).
+		Set("qr_code", item.QRCode).
+		Set("estimated_value", item.EstimatedValue).
+		Set("market_demand", item.MarketDemand).
+		Set("seasonality_notes", item.SeasonalityNotes).
+		Set("needs_repair", item.NeedsRepair).
+		Set("is_consignment", item.IsConsignment).
+		Set("is_returned", item.IsReturned).
+		Set("keywords", strings.Join(item.Keywords, ",")).
+		Set("notes", item.Notes).
+		Set("updated_at", item.UpdatedAt).
+		Set("updated_by", item.UpdatedBy).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"lot_id": item.LotID}).
+		Where("deleted_at IS NULL").
+		Suffix("RETURNING " + strings.Join(r.inventoryColumns(), ", "))
+
+	// A caller that read the item passes its version so a concurrent edit isn't overwritten
+	if item.Version > 0 {
+		query = query.Where(squirrel.Eq{"version": item.Version})
+	}
+
+	return query
+}
+
+// buildPatchQuery builds the update for UpdatePartial, conditioned on the patch's version when set
+func (r *inventoryRepository) buildPatchQuery(lotID uuid.UUID, patch ports.InventoryPatch, now time.Time) squirrel.UpdateBuilder {
+	query := r.qb.Update("inventory").
+		SetMap(inventoryPatchColumns(patch)).
+		Set("updated_at", now).
+		Set("updated_by", patch.UpdatedBy).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"lot_id": lotID}).
+		Where("deleted_at IS NULL").
+		Suffix("RETURNING " + strings.Join(r.inventoryColumns(), ", "))
+
+	if patch.Version > 0 {
+		query = query.Where(squirrel.Eq{"version": patch.Version})
+	}
+
+	return query
+}
+
+// inventoryPatchColumns maps the set fields of a patch to column values, nil for null
+func inventoryPatchColumns(patch ports.InventoryPatch) map[string]interface{} {
+	columns := make(map[string]interface{})
+	setPatchColumn(columns, "invoice_id", patch.InvoiceID)
+	setPatchColumn(columns, "auction_id", patch.AuctionID)
+	setPatchColumn(columns, "item_name", patch.ItemName)
+	setPatchColumn(columns, "description", patch.Description)
+	setPatchColumn(columns, "category", patch.Category)
+	setPatchColumn(columns, "subcategory", patch.Subcategory)
+	setPatchColumn(columns, "condition", patch.Condition)
+	setPatchColumn(columns, "quantity", patch.Quantity)
+	setPatchColumn(columns, "bid_amount", patch.BidAmount)
+	setPatchColumn(columns, "buyers_premium", patch.BuyersPremium)
+	setPatchColumn(columns, "sales_tax", patch.SalesTax)
+	setPatchColumn(columns, "shipping_cost", patch.ShippingCost)
+	setPatchColumn(columns, "acquisition_date", patch.AcquisitionDate)
+	setPatchColumn(columns, "storage_location", patch.StorageLocation)
+	setPatchColumn(columns, "storage_bin", patch.StorageBin)
+	setPatchColumn(columns, "estimated_value", patch.EstimatedValue)
+	setPatchColumn(columns, "market_demand", patch.MarketDemand)
+	setPatchColumn(columns, "seasonality_notes", patch.SeasonalityNotes)
+	setPatchColumn(columns, "needs_repair", patch.NeedsRepair)
+	setPatchColumn(columns, "is_consignment", patch.IsConsignment)
+	setPatchColumn(columns, "is_returned", patch.IsReturned)
+	setPatchColumn(columns, "notes", patch.Notes)
+	if patch.Keywords.Set {
+		columns["keywords"] = nil
+		if patch.Keywords.Value != nil {
+			columns["keywords"] = strings.Join(*patch.Keywords.Value, ",")
+		}
+	}
+	return columns
+}
+
+// setPatchColumn adds a set patch field to columns, as nil when it is null
+func setPatchColumn[T any](columns map[string]interface{}, column string, field ports.Optional[T]) {
+	if !field.Set {
+		return
+	}
+	if field.Value == nil {
+		columns[column] = nil
+		return
+	}
+	columns[column] = *field.Value
+}
+
+// inventoryWriteColumns are the columns written when an item is inserted
+var inventoryWriteColumns = []string{
+	"lot_id", "invoice_id", "auction_id", "item_name", "description",
+	"category", "subcategory", "condition", "quantity",
+	"bid_amount", "buyers_premium", "sales_tax", "shipping_cost",
+	"acquisition_date", "storage_location", "storage_bin", "qr_code",
+	"estimated_value", "market_demand", "seasonality_notes",
+	"needs_repair", "is_consignment", "is_returned",
+	"keywords", "notes", "created_at", "updated_at", "created_by", "updated_by",
+}
+
+// inventoryWriteValues returns the item's values in inventoryWriteColumns order
+func inventoryWriteValues(item *domain.InventoryItem) []interface{} {
+	return []interface{}{
+		item.LotID, item.InvoiceID, item.AuctionID, item.ItemName, item.Description,
+		item.Category, item.Subcategory, item.Condition, item.Quantity,
+		item.BidAmount, item.BuyersPremium, item.SalesTax, item.ShippingCost,
+		item.AcquisitionDate, item.StorageLocation, item.StorageBin, item.QRCode,
+		item.EstimatedValue, item.MarketDemand, item.SeasonalityNotes,
+		item.NeedsRepair, item.IsConsignment, item.IsReturned,
+		strings.Join(item.Keywords, ","), item.Notes, item.CreatedAt, item.UpdatedAt, item.CreatedBy, item.UpdatedBy,
+	}
+}
+
+// isImmutableColumn reports whether an upsert must leave the existing value in place
+func isImmutableColumn(column string) bool {
+	return column == "lot_id" || column == "created_at" || column == "created_by"
+}
+
+// buildInsertQuery builds a plain insert returning the stored lot_id and generated costs
+func (r *inventoryRepository) buildInsertQuery(item *domain.InventoryItem) squirrel.InsertBuilder {
+	return r.qb.Insert("inventory").
+		Columns(inventoryWriteColumns...).
+		Values(inventoryWriteValues(item)...).
+		Suffix("RETURNING lot_id, total_cost, cost_per_item, version")
+}
+
+// buildUpsertByLotIDQuery builds an insert that overwrites the mutable columns of an existing lot
+func (r *inventoryRepository) buildUpsertByLotIDQuery(item *domain.InventoryItem) squirrel.InsertBuilder {
+	var sets []string
+	for _, column := range inventoryWriteColumns {
+		if !isImmutableColumn(column) {
+			sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+		}
+	}
+	sets = append(sets, "version = inventory.version + 1")
+
+	return r.qb.Insert("inventory").
+		Columns(inventoryWriteColumns...).
+		Values(inventoryWriteValues(item)...).
+		Suffix("ON CONFLICT (lot_id) DO UPDATE SET " + strings.Join(sets, ", ") +
+			" RETURNING lot_id, total_cost, cost_per_item, version")
+}
+
+// buildUpdateByInvoiceItemQuery builds an update of the active rows sharing the item's natural key
+func (r *inventoryRepository) buildUpdateByInvoiceItemQuery(item *domain.InventoryItem) squirrel.UpdateBuilder {
+	query := r.qb.Update("inventory")
+	values := inventoryWriteValues(item)
+	for i, column := range inventoryWriteColumns {
+		if !isImmutableColumn(column) {
+			query = query.Set(column, values[i])
+		}
+	}
+
+	return query.
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"invoice_id": item.InvoiceID, "item_name": item.ItemName}).
+		Where("deleted_at IS NULL").
+		Suffix("RETURNING lot_id, total_cost, cost_per_item, version")
+}
+
 // inventoryColumns returns the standard set of columns to select
 func (r *inventoryRepository) inventoryColumns() []string {
 	return []string{
@@ -472,8 +1003,223 @@ func (r *inventoryRepository) inventoryColumns() []string {
 		"storage_location", "storage_bin", "qr_code",
 		"estimated_value", "market_demand", "seasonality_notes",
 		"needs_repair", "is_consignment", "is_returned",
-		"keywords", "notes", "created_at", "updated_at",
+		"keywords", "notes", "created_at", "updated_at", "version",
+		"created_by", "updated_by",
+	}
+}
+
+// buildListQueries builds the data and count queries for a listing. Both go through
+// buildBulkUpdateQuery builds an UPDATE that sets only the provided fields on all listed items
+func (r *inventoryRepository) buildBulkUpdateQuery(lotIDs []uuid.UUID, updates ports.BulkFieldUpdates, now time.Time) squirrel.UpdateBuilder {
+	query := r.qb.Update("inventory")
+
+	if updates.StorageLocation != nil {
+		query = query.Set("storage_location", *updates.StorageLocation)
+	}
+	if updates.StorageBin != nil {
+		query = query.Set("storage_bin", *updates.StorageBin)
+	}
+	if updates.NeedsRepair != nil {
+		query = query.Set("needs_repair", *updates.NeedsRepair)
+	}
+	if updates.MarketDemand != nil {
+		query = query.Set("market_demand", *updates.MarketDemand)
+	}
+
+	return query.
+		Set("updated_at", now).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("lot_id = ANY(?)", lotIDs).
+		Where("deleted_at IS NULL")
+}
+
+// buildFillQRCodesQuery builds the FillQRCodes update. REPLACE substitutes the lot ID the way
+// QRTemplate.Payload does.
+func (r *inventoryRepository) buildFillQRCodesQuery(template domain.QRTemplate, now time.Time) squirrel.UpdateBuilder {
+	return r.qb.Update("inventory").
+		Set("qr_code", squirrel.Expr("REPLACE(?, ?, lot_id::text)", string(template), domain.QRLotIDPlaceholder)).
+		Set("updated_at", now).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("COALESCE(qr_code, '') = ''").
+		Where("deleted_at IS NULL")
+}
+
+// applyListFilters so the total count always matches the filtered rows.
+func (r *inventoryRepository) buildListQueries(params ports.ListParams) (squirrel.SelectBuilder, squirrel.SelectBuilder) {
+	dataQuery := r.qb.Select(r.inventoryColumns()...).
+		From("inventory")
+
+	countQuery := r.qb.Select("COUNT(*)").
+		From("inventory")
+
+	return applyListFilters(dataQuery, params), applyListFilters(countQuery, params)
+}
+
+// buildTotalsQuery builds the aggregate query for SumTotals using the same filters as FindAll
+func (r *inventoryRepository) buildTotalsQuery(params ports.ListParams) squirrel.SelectBuilder {
+	query := r.qb.Select("COALESCE(SUM(total_cost), 0)", "COALESCE(SUM(estimated_value), 0)").
+		From("inventory")
+
+	return applyListFilters(query, params)
+}
+
+// buildInvoiceQueries builds the grouped page query and the distinct count query for
+// ListInvoices. invoice_id breaks ties so pages are stable.
+func (r *inventoryRepository) buildInvoiceQueries(params ports.InvoiceListParams) (squirrel.SelectBuilder, squirrel.SelectBuilder) {
+	column := "acquisition_date"
+	if params.SortBy == ports.InvoiceSortTotal {
+		column = "total_cost"
+	}
+	order := "DESC"
+	if params.SortOrder == "asc" {
+		order = "ASC"
+	}
+
+	pageQuery := r.qb.Select(
+		"invoice_id",
+		"COALESCE(MAX(auction_id), 0)",
+		"MIN(acquisition_date) AS acquisition_date",
+		"COUNT(*)",
+		"COALESCE(SUM(total_cost), 0) AS total_cost",
+	).
+		From("inventory").
+		Where("deleted_at IS NULL").
+		GroupBy("invoice_id").
+		OrderBy(column+" "+order, "invoice_id "+order)
+	if params.PageSize > 0 {
+		pageQuery = pageQuery.
+			Limit(uint64(params.PageSize)).
+			Offset(uint64(max(params.Page-1, 0) * params.PageSize))
+	}
+
+	countQuery := r.qb.Select("COUNT(DISTINCT invoice_id)").
+		From("inventory").
+		Where("deleted_at IS NULL")
+
+	return pageQuery, countQuery
+}
+
+// buildStorageSummaryQuery builds the grouped query for StorageSummary. NULL, empty and blank
+// locations and bins all fall into the unassigned bucket. The groups are referenced by
+// position since each placeholder makes a distinct expression.
+func (r *inventoryRepository) buildStorageSummaryQuery() squirrel.SelectBuilder {
+	return r.qb.Select().
+		Column("COALESCE(NULLIF(TRIM(storage_location), ''), ?) AS location", ports.UnassignedStorage).
+		Column("COALESCE(NULLIF(TRIM(storage_bin), ''), ?) AS bin", ports.UnassignedStorage).
+		Column("COUNT(*)").
+		Column("COALESCE(SUM(estimated_value), 0)").
+		From("inventory").
+		Where("deleted_at IS NULL").
+		GroupBy("1", "2").
+		OrderBy("1", "2")
+}
+
+// buildHighlightsQuery builds the ts_headline query for SearchHighlights
+func (r *inventoryRepository) buildHighlightsQuery(search string, lotIDs []uuid.UUID) squirrel.SelectBuilder {
+	return r.qb.Select("lot_id").
+		Column(squirrel.Expr(
+			"ts_headline('english', coalesce(description, ''), plainto_tsquery('english', ?), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')",
+			search,
+		)).
+		From("inventory").
+		Where("lot_id = ANY(?)", lotIDs)
+}
+
+// applyPagination orders and limits the data query. Keyset mode (AfterCreatedAt set) always
+// orders by (created_at, lot_id) descending and seeks past the cursor instead of using OFFSET,
+// so deep pages cost the same as the first one.
+func (r *inventoryRepository) applyPagination(query squirrel.SelectBuilder, params ports.ListParams) squirrel.SelectBuilder {
+	if params.AfterCreatedAt != nil {
+		query = query.
+			Where("(created_at, lot_id) < (?, ?)", *params.AfterCreatedAt, params.AfterLotID).
+			OrderBy("created_at DESC", "lot_id DESC")
+		if params.PageSize > 0 {
+			query = query.Limit(uint64(params.PageSize))
+		}
+		return query
+	}
+
+	// Relevance needs a search term to rank against; without one the default sort applies
+	if params.SortBy == "relevance" && params.Search != "" {
+		query = query.
+			OrderByClause("ts_rank(search_vector, plainto_tsquery('english', ?)) DESC", params.Search).
+			OrderBy("lot_id DESC")
+	} else {
+		query = query.OrderBy(r.buildOrderBy(params.SortBy, params.SortOrder))
+	}
+	if params.PageSize > 0 {
+		offset := (params.Page - 1) * params.PageSize
+		query = query.Limit(uint64(params.PageSize)).Offset(uint64(offset))
+	}
+	return query
+}
+
+// applyListFilters adds the WHERE conditions for every filter set in params
+func applyListFilters(query squirrel.SelectBuilder, params ports.ListParams) squirrel.SelectBuilder {
+	// Soft-deleted rows are hidden unless explicitly requested
+	switch {
+	case params.OnlyDeleted:
+		query = query.Where("deleted_at IS NOT NULL")
+	case !params.IncludeDeleted:
+		query = query.Where("deleted_at IS NULL")
 	}
+
+	// Apply search filter using PostgreSQL's full-text search
+	if params.Search != "" {
+		query = query.Where(
+			"search_vector @@ plainto_tsquery('english', ?)",
+			params.Search,
+		)
+	}
+	if params.Category != "" {
+		query = query.Where(squirrel.Eq{"category": params.Category})
+	}
+	if params.Condition != "" {
+		query = query.Where(squirrel.Eq{"condition": params.Condition})
+	}
+	if len(params.Categories) > 0 {
+		query = query.Where(squirrel.Eq{"category": params.Categories})
+	}
+	if len(params.Conditions) > 0 {
+		query = query.Where(squirrel.Eq{"condition": params.Conditions})
+	}
+	if params.StorageLocation != "" {
+		query = query.Where(squirrel.Eq{"storage_location": params.StorageLocation})
+	}
+	if params.StorageBin != "" {
+		query = query.Where(squirrel.Eq{"storage_bin": params.StorageBin})
+	}
+	if params.InvoiceID != "" {
+		query = query.Where(squirrel.Eq{"invoice_id": params.InvoiceID})
+	}
+	if params.NeedsRepair != nil {
+		query = query.Where(squirrel.Eq{"needs_repair": *params.NeedsRepair})
+	}
+
+	// Apply cost ranges (bounds are inclusive)
+	if params.MinTotalCost != nil {
+		query = query.Where(squirrel.GtOrEq{"total_cost": *params.MinTotalCost})
+	}
+	if params.MaxTotalCost != nil {
+		query = query.Where(squirrel.LtOrEq{"total_cost": *params.MaxTotalCost})
+	}
+	if params.MinBidAmount != nil {
+		query = query.Where(squirrel.GtOrEq{"bid_amount": *params.MinBidAmount})
+	}
+	if params.MaxBidAmount != nil {
+		query = query.Where(squirrel.LtOrEq{"bid_amount": *params.MaxBidAmount})
+	}
+
+	// Apply acquisition date range; acquisition_date is a timestamp, so the upper
+	// bound is exclusive of the following day to include all of AcquiredTo
+	if params.AcquiredFrom != nil {
+		query = query.Where(squirrel.GtOrEq{"acquisition_date": *params.AcquiredFrom})
+	}
+	if params.AcquiredTo != nil {
+		query = query.Where(squirrel.Lt{"acquisition_date": params.AcquiredTo.AddDate(0, 0, 1)})
+	}
+
+	return query
 }
 
 // buildOrderBy constructs the ORDER BY clause based on sort parameters
@@ -514,7 +1260,9 @@ func (r *inventoryRepository) buildOrderBy(sortBy, sortOrder string) string {
 		column = "created_at"
 	}
 
-	return fmt.Sprintf("%s %s NULLS LAST", column, strings.ToUpper(sortOrder))
+	// lot_id breaks ties so pages never overlap or skip rows with equal sort values
+	order := strings.ToUpper(sortOrder)
+	return fmt.Sprintf("%s %s NULLS LAST, lot_id %s", column, order, order)
 }
 
 // scanInventoryItem scans a single row into an InventoryItem
@@ -526,6 +1274,7 @@ func (r *inventoryRepository) scanInventoryItem(row pgx.Row) (*domain.InventoryI
 	var estimatedValue pgtype.Numeric
 	var seasonalityNotes sql.NullString
 	var notes sql.NullString
+	var createdBy, updatedBy sql.NullString
 
 	err := row.Scan(
 		&item.LotID, &item.InvoiceID, &item.AuctionID, &item.ItemName, &item.Description,
@@ -535,7 +1284,8 @@ func (r *inventoryRepository) scanInventoryItem(row pgx.Row) (*domain.InventoryI
 		&storageLocation, &storageBin, &qrCode,
 		&estimatedValue, &item.MarketDemand, &seasonalityNotes,
 		&item.NeedsRepair, &item.IsConsignment, &item.IsReturned,
-		&keywordsStr, &notes, &item.CreatedAt, &item.UpdatedAt,
+		&keywordsStr, &notes, &item.CreatedAt, &item.UpdatedAt, &item.Version,
+		&createdBy, &updatedBy,
 	)
 
 	if err != nil {
@@ -552,6 +1302,8 @@ func (r *inventoryRepository) scanInventoryItem(row pgx.Row) (*domain.InventoryI
 	item.QRCode = qrCode.String
 	item.SeasonalityNotes = seasonalityNotes.String
 	item.Notes = notes.String
+	item.CreatedBy = createdBy.String
+	item.UpdatedBy = updatedBy.String
 
 	// Handle estimated value conversion
 	if estimatedValue.Valid {
@@ -578,6 +1330,7 @@ func (r *inventoryRepository) scanInventoryItems(rows pgx.Rows) ([]domain.Invent
 		var storageLocation, storageBin, qrCode sql.NullString
 		var estimatedValue pgtype.Numeric
 		var seasonalityNotes, notes sql.NullString
+		var createdBy, updatedBy sql.NullString
 
 		err := rows.Scan(
 			&item.LotID, &item.InvoiceID, &item.AuctionID, &item.ItemName, &item.Description,
@@ -587,7 +1340,8 @@ func (r *inventoryRepository) scanInventoryItems(rows pgx.Rows) ([]domain.Invent
 			&storageLocation, &storageBin, &qrCode,
 			&estimatedValue, &item.MarketDemand, &seasonalityNotes,
 			&item.NeedsRepair, &item.IsConsignment, &item.IsReturned,
-			&keywordsStr, &notes, &item.CreatedAt, &item.UpdatedAt,
+			&keywordsStr, &notes, &item.CreatedAt, &item.UpdatedAt, &item.Version,
+			&createdBy, &updatedBy,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
@@ -600,6 +1354,8 @@ func (r *inventoryRepository) scanInventoryItems(rows pgx.Rows) ([]domain.Invent
 		item.QRCode = qrCode.String
 		item.SeasonalityNotes = seasonalityNotes.String
 		item.Notes = notes.String
+		item.CreatedBy = createdBy.String
+		item.UpdatedBy = updatedBy.String
 
 		// Handle estimated value
 		if estimatedValue.Valid {
@@ -633,6 +1389,7 @@ func (r *inventoryRepository) scanInventoryItemPointers(rows pgx.Rows) ([]*domai
 		var storageLocation, storageBin, qrCode sql.NullString
 		var estimatedValue pgtype.Numeric
 		var seasonalityNotes, notes sql.NullString
+		var createdBy, updatedBy sql.NullString
 
 		err := rows.Scan(
 			&item.LotID, &item.InvoiceID, &item.AuctionID, &item.ItemName, &item.Description,
@@ -642,7 +1399,8 @@ func (r *inventoryRepository) scanInventoryItemPointers(rows pgx.Rows) ([]*domai
 			&storageLocation, &storageBin, &qrCode,
 			&estimatedValue, &item.MarketDemand, &seasonalityNotes,
 			&item.NeedsRepair, &item.IsConsignment, &item.IsReturned,
-			&keywordsStr, &notes, &item.CreatedAt, &item.UpdatedAt,
+			&keywordsStr, &notes, &item.CreatedAt, &item.UpdatedAt, &item.Version,
+			&createdBy, &updatedBy,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
@@ -655,6 +1413,8 @@ func (r *inventoryRepository) scanInventoryItemPointers(rows pgx.Rows) ([]*domai
 		item.QRCode = qrCode.String
 		item.SeasonalityNotes = seasonalityNotes.String
 		item.Notes = notes.String
+		item.CreatedBy = createdBy.String
+		item.UpdatedBy = updatedBy.String
 
 		// Handle estimated value
 		if estimatedValue.Valid {
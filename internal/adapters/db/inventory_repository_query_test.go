@@ -0,0 +1,555 @@
+package db_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/adapters/db"
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// whereClause returns the SQL after WHERE along with its arguments
+func whereClause(t *testing.T, query squirrel.SelectBuilder) (string, []interface{}) {
+	t.Helper()
+
+	sql, args, err := query.ToSql()
+	require.NoError(t, err)
+
+	_, where, found := strings.Cut(sql, " WHERE ")
+	require.True(t, found, "query has no WHERE clause: %s", sql)
+	return where, args
+}
+
+func TestBuildListQueries(t *testing.T) {
+	minCost := decimal.NewFromInt(10)
+	maxCost := decimal.RequireFromString("250.50")
+	minBid := decimal.Zero
+	maxBid := decimal.NewFromInt(100)
+	acquiredFrom := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	acquiredTo := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		params        ports.ListParams
+		expectedWhere string
+		expectedArgs  []interface{} // decimals are bound via their driver.Valuer string form
+	}{
+		{
+			name:          "no_filters",
+			params:        ports.ListParams{},
+			expectedWhere: "deleted_at IS NULL",
+		},
+		{
+			name: "cost_and_bid_ranges",
+			params: ports.ListParams{
+				MinTotalCost: &minCost,
+				MaxTotalCost: &maxCost,
+				MinBidAmount: &minBid,
+				MaxBidAmount: &maxBid,
+			},
+			expectedWhere: "deleted_at IS NULL AND total_cost >= $1 AND total_cost <= $2 AND bid_amount >= $3 AND bid_amount <= $4",
+			expectedArgs:  []interface{}{"10", "250.5", "0", "100"},
+		},
+		{
+			name: "ranges_compose_with_other_filters",
+			params: ports.ListParams{
+				Search:       "lamp",
+				Category:     "antiques",
+				MaxTotalCost: &maxCost,
+			},
+			expectedWhere: "deleted_at IS NULL AND search_vector @@ plainto_tsquery('english', $1) AND category = $2 AND total_cost <= $3",
+			expectedArgs:  []interface{}{"lamp", "antiques", "250.5"},
+		},
+		{
+			name:          "default_excludes_deleted_rows",
+			params:        ports.ListParams{Category: "glass"},
+			expectedWhere: "deleted_at IS NULL AND category = $1",
+			expectedArgs:  []interface{}{"glass"},
+		},
+		{
+			name:          "only_deleted_rows",
+			params:        ports.ListParams{OnlyDeleted: true, Category: "glass"},
+			expectedWhere: "deleted_at IS NOT NULL AND category = $1",
+			expectedArgs:  []interface{}{"glass"},
+		},
+		{
+			name:          "only_deleted_takes_precedence_over_include",
+			params:        ports.ListParams{OnlyDeleted: true, IncludeDeleted: true},
+			expectedWhere: "deleted_at IS NOT NULL",
+		},
+		{
+			name:          "include_deleted_drops_deleted_at_condition",
+			params:        ports.ListParams{IncludeDeleted: true, Category: "glass"},
+			expectedWhere: "category = $1",
+			expectedArgs:  []interface{}{"glass"},
+		},
+		{
+			name: "multi_value_category_and_condition",
+			params: ports.ListParams{
+				Categories: []string{"furniture", "glass"},
+				Conditions: []string{"good"},
+			},
+			expectedWhere: "deleted_at IS NULL AND category IN ($1,$2) AND condition IN ($3)",
+			expectedArgs:  []interface{}{"furniture", "glass", "good"},
+		},
+		{
+			name: "acquisition_date_range_includes_whole_last_day",
+			params: ports.ListParams{
+				AcquiredFrom: &acquiredFrom,
+				AcquiredTo:   &acquiredTo,
+			},
+			expectedWhere: "deleted_at IS NULL AND acquisition_date >= $1 AND acquisition_date < $2",
+			expectedArgs:  []interface{}{acquiredFrom, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "date_range_composes_with_search_and_category",
+			params: ports.ListParams{
+				Search:       "victorian",
+				Category:     "furniture",
+				AcquiredFrom: &acquiredFrom,
+			},
+			expectedWhere: "deleted_at IS NULL AND search_vector @@ plainto_tsquery('english', $1) AND category = $2 AND acquisition_date >= $3",
+			expectedArgs:  []interface{}{"victorian", "furniture", acquiredFrom},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataQuery, countQuery := db.BuildListQueries(tt.params)
+
+			dataWhere, dataArgs := whereClause(t, dataQuery)
+			countWhere, countArgs := whereClause(t, countQuery)
+
+			assert.Equal(t, tt.expectedWhere, dataWhere)
+			assert.Equal(t, tt.expectedArgs, dataArgs)
+
+			// The count must be filtered exactly like the data it totals
+			assert.Equal(t, dataWhere, countWhere)
+			assert.Equal(t, dataArgs, countArgs)
+		})
+	}
+}
+
+func TestBuildBulkUpdateQuery(t *testing.T) {
+	lotIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	location := "Warehouse B"
+	needsRepair := true
+	demand := domain.DemandHigh
+
+	tests := []struct {
+		name         string
+		updates      ports.BulkFieldUpdates
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "single_field",
+			updates:      ports.BulkFieldUpdates{StorageLocation: &location},
+			expectedSQL:  "UPDATE inventory SET storage_location = $1, updated_at = $2, version = version + 1 WHERE lot_id = ANY($3) AND deleted_at IS NULL",
+			expectedArgs: []interface{}{location, now, lotIDs},
+		},
+		{
+			name: "only_provided_fields_are_set",
+			updates: ports.BulkFieldUpdates{
+				NeedsRepair:  &needsRepair,
+				MarketDemand: &demand,
+			},
+			expectedSQL:  "UPDATE inventory SET needs_repair = $1, market_demand = $2, updated_at = $3, version = version + 1 WHERE lot_id = ANY($4) AND deleted_at IS NULL",
+			expectedArgs: []interface{}{true, demand, now, lotIDs},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := db.BuildBulkUpdateQuery(lotIDs, tt.updates, now).ToSql()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestBuildPatchQuery(t *testing.T) {
+	lotID := uuid.New()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		patch        ports.InventoryPatch
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name: "set_and_null_fields",
+			patch: ports.InventoryPatch{
+				StorageLocation: ports.Some("Garage"),
+				Notes:           ports.Null[string](),
+				UpdatedBy:       "user-1",
+			},
+			expectedSQL:  "UPDATE inventory SET notes = $1, storage_location = $2, updated_at = $3, updated_by = $4, version = version + 1 WHERE lot_id = $5 AND deleted_at IS NULL",
+			expectedArgs: []interface{}{nil, "Garage", now, "user-1", lotID.String()},
+		},
+		{
+			name: "version_guard",
+			patch: ports.InventoryPatch{
+				Keywords: ports.Some([]string{"vintage", "glass"}),
+				Version:  3,
+			},
+			expectedSQL:  "UPDATE inventory SET keywords = $1, updated_at = $2, updated_by = $3, version = version + 1 WHERE lot_id = $4 AND deleted_at IS NULL AND version = $5",
+			expectedArgs: []interface{}{"vintage,glass", now, "", lotID.String(), 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := db.BuildPatchQuery(lotID, tt.patch, now).ToSql()
+			require.NoError(t, err)
+
+			head, returning, ok := strings.Cut(sql, " RETURNING ")
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedSQL, head)
+			assert.True(t, strings.HasPrefix(returning, "lot_id, invoice_id"))
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestBuildPageQuery(t *testing.T) {
+	cursorTime := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+	cursorID := uuid.New()
+
+	t.Run("offset_mode_by_default", func(t *testing.T) {
+		sql, args, err := db.BuildPageQuery(ports.ListParams{Page: 3, PageSize: 20}).ToSql()
+		require.NoError(t, err)
+
+		_, tail, _ := strings.Cut(sql, " WHERE ")
+		assert.Equal(t, "deleted_at IS NULL ORDER BY created_at DESC NULLS LAST, lot_id DESC LIMIT 20 OFFSET 40", tail)
+		assert.Empty(t, args)
+	})
+
+	t.Run("keyset_mode_replaces_offset", func(t *testing.T) {
+		params := ports.ListParams{
+			Category:       "glass",
+			Page:           1000,
+			PageSize:       20,
+			SortBy:         "name",
+			AfterCreatedAt: &cursorTime,
+			AfterLotID:     cursorID,
+		}
+		sql, args, err := db.BuildPageQuery(params).ToSql()
+		require.NoError(t, err)
+
+		_, tail, _ := strings.Cut(sql, " WHERE ")
+		assert.Equal(t, "deleted_at IS NULL AND category = $1 AND (created_at, lot_id) < ($2, $3) ORDER BY created_at DESC, lot_id DESC LIMIT 20", tail)
+		assert.Equal(t, []interface{}{"glass", cursorTime, cursorID}, args)
+	})
+
+	t.Run("relevance_sort_ranks_by_search_term", func(t *testing.T) {
+		params := ports.ListParams{Search: "lantern", SortBy: "relevance", Page: 1, PageSize: 10}
+		sql, args, err := db.BuildPageQuery(params).ToSql()
+		require.NoError(t, err)
+
+		_, tail, _ := strings.Cut(sql, " WHERE ")
+		assert.Equal(t, "deleted_at IS NULL AND search_vector @@ plainto_tsquery('english', $1) ORDER BY ts_rank(search_vector, plainto_tsquery('english', $2)) DESC, lot_id DESC LIMIT 10 OFFSET 0", tail)
+		assert.Equal(t, []interface{}{"lantern", "lantern"}, args)
+	})
+
+	t.Run("relevance_sort_without_search_uses_default", func(t *testing.T) {
+		sql, _, err := db.BuildPageQuery(ports.ListParams{SortBy: "relevance", Page: 1, PageSize: 10}).ToSql()
+		require.NoError(t, err)
+
+		assert.Contains(t, sql, "ORDER BY created_at DESC NULLS LAST, lot_id DESC")
+		assert.NotContains(t, sql, "ts_rank")
+	})
+
+	t.Run("count_query_ignores_cursor", func(t *testing.T) {
+		params := ports.ListParams{AfterCreatedAt: &cursorTime, AfterLotID: cursorID}
+		_, countQuery := db.BuildListQueries(params)
+
+		where, args := whereClause(t, countQuery)
+		assert.Equal(t, "deleted_at IS NULL", where)
+		assert.Empty(t, args)
+	})
+}
+
+func TestBuildTotalsQuery(t *testing.T) {
+	maxCost := decimal.NewFromInt(500)
+	params := ports.ListParams{
+		Search:       "lamp",
+		Categories:   []string{"antiques", "art"},
+		MaxTotalCost: &maxCost,
+		Page:         4,
+		PageSize:     20,
+	}
+
+	sql, args, err := db.BuildTotalsQuery(params).ToSql()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sql, "SELECT COALESCE(SUM(total_cost), 0), COALESCE(SUM(estimated_value), 0) FROM inventory WHERE "))
+	assert.NotContains(t, sql, "LIMIT")
+
+	// The sums must cover exactly the rows the listing counts
+	totalsWhere, totalsArgs := whereClause(t, db.BuildTotalsQuery(params))
+	_, countQuery := db.BuildListQueries(params)
+	countWhere, countArgs := whereClause(t, countQuery)
+
+	assert.Equal(t, countWhere, totalsWhere)
+	assert.Equal(t, countArgs, totalsArgs)
+	assert.Equal(t, []interface{}{"lamp", "antiques", "art", "500"}, args)
+}
+
+func TestBuildFillQRCodesQuery(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	template := domain.QRTemplate("https://resell.example.com/inventory/{lot_id}")
+
+	sql, args, err := db.BuildFillQRCodesQuery(template, now).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE inventory SET qr_code = REPLACE($1, $2, lot_id::text), updated_at = $3, version = version + 1 "+
+		"WHERE COALESCE(qr_code, '') = '' AND deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{string(template), domain.QRLotIDPlaceholder, now}, args)
+}
+
+func TestBuildInvoiceQueries(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    ports.InvoiceListParams
+		wantOrder string
+		wantPage  string
+	}{
+		{
+			name:      "date_desc_by_default",
+			params:    ports.InvoiceListParams{Page: 1, PageSize: 50},
+			wantOrder: "ORDER BY acquisition_date DESC, invoice_id DESC",
+			wantPage:  "LIMIT 50 OFFSET 0",
+		},
+		{
+			name:      "total_asc",
+			params:    ports.InvoiceListParams{Page: 3, PageSize: 20, SortBy: ports.InvoiceSortTotal, SortOrder: "asc"},
+			wantOrder: "ORDER BY total_cost ASC, invoice_id ASC",
+			wantPage:  "LIMIT 20 OFFSET 40",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pageQuery, countQuery := db.BuildInvoiceQueries(tt.params)
+
+			sql, args, err := pageQuery.ToSql()
+			require.NoError(t, err)
+			assert.Contains(t, sql, "COUNT(*), COALESCE(SUM(total_cost), 0) AS total_cost FROM inventory")
+			assert.Contains(t, sql, "WHERE deleted_at IS NULL GROUP BY invoice_id "+tt.wantOrder)
+			assert.True(t, strings.HasSuffix(sql, tt.wantPage), sql)
+			assert.Empty(t, args)
+
+			sql, _, err = countQuery.ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT COUNT(DISTINCT invoice_id) FROM inventory WHERE deleted_at IS NULL", sql)
+		})
+	}
+}
+
+func TestBuildStorageSummaryQuery(t *testing.T) {
+	sql, args, err := db.BuildStorageSummaryQuery().ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"SELECT COALESCE(NULLIF(TRIM(storage_location), ''), $1) AS location, "+
+			"COALESCE(NULLIF(TRIM(storage_bin), ''), $2) AS bin, COUNT(*), COALESCE(SUM(estimated_value), 0) "+
+			"FROM inventory WHERE deleted_at IS NULL GROUP BY 1, 2 ORDER BY 1, 2",
+		sql)
+	assert.Equal(t, []interface{}{ports.UnassignedStorage, ports.UnassignedStorage}, args)
+}
+
+func TestBuildHighlightsQuery(t *testing.T) {
+	lotIDs := []uuid.UUID{uuid.New()}
+
+	sql, args, err := db.BuildHighlightsQuery("lantern", lotIDs).ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT lot_id, ts_headline('english', coalesce(description, ''), plainto_tsquery('english', $1), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') FROM inventory WHERE lot_id = ANY($2)", sql)
+	assert.Equal(t, []interface{}{"lantern", lotIDs}, args)
+}
+
+func TestLikePrefixPattern(t *testing.T) {
+	tests := map[string]string{
+		"vict":     "vict%",
+		"50%":      `50\%%`,
+		"lot_12":   `lot\_12%`,
+		`back\sla`: `back\\sla%`,
+	}
+
+	for prefix, expected := range tests {
+		assert.Equal(t, expected, db.LikePrefixPattern(prefix), prefix)
+	}
+}
+
+func TestBuildUpsertByLotIDQuery(t *testing.T) {
+	item := &domain.InventoryItem{LotID: uuid.New(), InvoiceID: "INV-1", ItemName: "Brass Lamp"}
+
+	sql, args, err := db.BuildUpsertByLotIDQuery(item).ToSql()
+	require.NoError(t, err)
+
+	_, conflict, found := strings.Cut(sql, " ON CONFLICT (lot_id) DO UPDATE SET ")
+	require.True(t, found, "query has no lot_id conflict clause: %s", sql)
+	assert.Contains(t, conflict, "item_name = EXCLUDED.item_name")
+	assert.Contains(t, conflict, "bid_amount = EXCLUDED.bid_amount")
+	assert.Contains(t, conflict, "updated_at = EXCLUDED.updated_at")
+	assert.True(t, strings.HasSuffix(conflict, ", version = inventory.version + 1 RETURNING lot_id, total_cost, cost_per_item, version"))
+
+	// The original identity and creation time survive the update
+	assert.NotContains(t, conflict, "lot_id = EXCLUDED.lot_id")
+	assert.NotContains(t, conflict, "created_at = EXCLUDED.created_at")
+	assert.NotContains(t, conflict, "created_by = EXCLUDED.created_by")
+	assert.Contains(t, conflict, "updated_by = EXCLUDED.updated_by")
+	assert.Equal(t, item.LotID, args[0])
+}
+
+func TestBuildUpdateByInvoiceItemQuery(t *testing.T) {
+	item := &domain.InventoryItem{LotID: uuid.New(), InvoiceID: "INV-1", ItemName: "Brass Lamp"}
+
+	sql, args, err := db.BuildUpdateByInvoiceItemQuery(item).ToSql()
+	require.NoError(t, err)
+
+	set, where, found := strings.Cut(sql, " WHERE ")
+	require.True(t, found, "query has no WHERE clause: %s", sql)
+	assert.Equal(t, "invoice_id = $27 AND item_name = $28 AND deleted_at IS NULL RETURNING lot_id, total_cost, cost_per_item, version", where)
+	assert.Equal(t, []interface{}{"INV-1", "Brass Lamp"}, args[len(args)-2:])
+	assert.NotContains(t, set, "lot_id =")
+	assert.NotContains(t, set, "created_at =")
+	assert.NotContains(t, set, "created_by =")
+	assert.Contains(t, set, "updated_at = $25, updated_by = $26, version = version + 1")
+}
+
+func TestBuildUpdateQuery(t *testing.T) {
+	lotID := uuid.New()
+
+	tests := []struct {
+		name          string
+		version       int
+		expectedWhere string
+		expectedArgs  []interface{} // squirrel binds the uuid via its driver.Valuer string form
+	}{
+		{
+			name:          "unversioned_update_always_applies",
+			expectedWhere: "lot_id = $27 AND deleted_at IS NULL",
+			expectedArgs:  []interface{}{lotID.String()},
+		},
+		{
+			name:          "versioned_update_requires_matching_version",
+			version:       4,
+			expectedWhere: "lot_id = $27 AND deleted_at IS NULL AND version = $28",
+			expectedArgs:  []interface{}{lotID.String(), 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &domain.InventoryItem{LotID: lotID, InvoiceID: "INV-1", ItemName: "Brass Lamp", Version: tt.version, UpdatedBy: "user-1"}
+
+			sql, args, err := db.BuildUpdateQuery(item).ToSql()
+			require.NoError(t, err)
+
+			set, where, found := strings.Cut(sql, " WHERE ")
+			require.True(t, found, "query has no WHERE clause: %s", sql)
+			assert.True(t, strings.HasSuffix(set, ", updated_by = $26, version = version + 1"))
+			assert.Equal(t, "user-1", args[25])
+			// The whole row is returned so Update can audit the stored values
+			where, returning, found := strings.Cut(where, " RETURNING ")
+			require.True(t, found, "query has no RETURNING clause: %s", sql)
+			assert.Equal(t, tt.expectedWhere, where)
+			assert.True(t, strings.HasPrefix(returning, "lot_id, "))
+			assert.True(t, strings.HasSuffix(returning, ", version, created_by, updated_by"))
+			assert.Equal(t, tt.expectedArgs, args[26:])
+		})
+	}
+}
+
+func TestDiffInventoryItems(t *testing.T) {
+	lotID := uuid.New()
+	before := &domain.InventoryItem{
+		LotID:           lotID,
+		ItemName:        "Brass Lamp",
+		StorageBin:      "A1",
+		BidAmount:       decimal.NewFromInt(10),
+		UpdatedAt:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:         1,
+		StorageLocation: "Shelf",
+	}
+
+	t.Run("update_records_only_changed_fields", func(t *testing.T) {
+		after := *before
+		after.StorageBin = "B2"
+		after.StorageLocation = ""
+		after.UpdatedAt = time.Now()
+		after.Version = 2
+
+		changes, err := db.DiffInventoryItems(before, &after)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]domain.FieldChange{
+			"storage_bin":      {Old: "A1", New: "B2"},
+			"storage_location": {Old: "Shelf", New: nil},
+		}, changes)
+	})
+
+	t.Run("create_records_new_values", func(t *testing.T) {
+		changes, err := db.DiffInventoryItems(nil, before)
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.FieldChange{New: "Brass Lamp"}, changes["item_name"])
+		assert.Equal(t, domain.FieldChange{New: lotID.String()}, changes["lot_id"])
+		assert.NotContains(t, changes, "updated_at")
+		assert.NotContains(t, changes, "version")
+	})
+
+	t.Run("delete_records_old_values", func(t *testing.T) {
+		changes, err := db.DiffInventoryItems(before, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.FieldChange{Old: "10"}, changes["bid_amount"])
+		assert.Equal(t, domain.FieldChange{Old: "A1"}, changes["storage_bin"])
+	})
+
+	t.Run("unchanged_item_has_no_changes", func(t *testing.T) {
+		same := *before
+		changes, err := db.DiffInventoryItems(before, &same)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+func TestBuildAuditInsertQuery(t *testing.T) {
+	lotID := uuid.New()
+	changes := map[string]domain.FieldChange{"storage_bin": {Old: "A1", New: "B2"}}
+
+	tests := []struct {
+		name         string
+		userID       string
+		expectedUser interface{}
+	}{
+		{name: "records_user", userID: "user-1", expectedUser: "user-1"},
+		{name: "anonymous_write_stores_null", expectedUser: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := db.BuildAuditInsertQuery(domain.AuditUpdate, lotID, changes, tt.userID)
+			require.NoError(t, err)
+
+			sql, args, err := query.ToSql()
+			require.NoError(t, err)
+
+			assert.Equal(t, "INSERT INTO inventory_audit (lot_id,operation,changes,user_id) VALUES ($1,$2,$3,$4)", sql)
+			assert.Equal(t, []interface{}{lotID, "update", `{"storage_bin":{"old":"A1","new":"B2"}}`, tt.expectedUser}, args)
+		})
+	}
+}
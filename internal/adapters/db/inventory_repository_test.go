@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/ammerola/resell-be/internal/adapters/db"
 	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
 	"github.com/ammerola/resell-be/test/helpers"
 )
 
@@ -36,6 +39,29 @@ func TestInventoryRepository_Save_Unit(t *testing.T) {
 	assert.NotZero(t, item.CostPerItem)
 }
 
+func TestInventoryRepository_Save_CostsMatchDomain_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	for _, quantity := range []int{1, 3, 4} {
+		t.Run(fmt.Sprintf("quantity_%d", quantity), func(t *testing.T) {
+			item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.Quantity = quantity
+			})
+			require.NoError(t, item.CalculateCosts(&domain.DefaultCostRates))
+			wantTotal, wantPerItem := item.TotalCost, item.CostPerItem
+
+			// Save returns the generated total_cost and cost_per_item columns
+			require.NoError(t, repo.Save(ctx, item))
+			assert.True(t, wantTotal.Equal(item.TotalCost), "total: go %s, db %s", wantTotal, item.TotalCost)
+			assert.True(t, wantPerItem.Equal(item.CostPerItem), "per item: go %s, db %s", wantPerItem, item.CostPerItem)
+		})
+	}
+}
+
 func TestInventoryRepository_FindByID_Unit(t *testing.T) {
 	testDB := helpers.SetupTestDB(t)
 	defer testDB.Database.Close()
@@ -116,6 +142,65 @@ func TestInventoryRepository_Update_Unit(t *testing.T) {
 	assert.Equal(t, "Updated Name", updated.ItemName)
 	assert.True(t, decimal.NewFromFloat(200).Equal(updated.BidAmount))
 	assert.Equal(t, 2, updated.Quantity)
+	assert.Equal(t, 2, updated.Version)
+
+	// A second writer still holding version 1 must not clobber the first
+	stale := *item
+	stale.Version = 1
+	stale.ItemName = "Stale Name"
+	err = repo.Update(ctx, &stale)
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+
+	// Updating from the current version succeeds and advances it
+	item.ItemName = "Second Update"
+	require.NoError(t, repo.Update(ctx, item))
+	assert.Equal(t, 3, item.Version)
+}
+
+func TestInventoryRepository_UpdatePartial_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+		i.StorageLocation = "Basement"
+		i.StorageBin = "B7"
+		i.Notes = "chipped rim"
+	})
+	require.NoError(t, repo.Save(ctx, item))
+
+	// Only the set fields change; null clears notes and the bin is left alone
+	patched, err := repo.UpdatePartial(ctx, item.LotID, ports.InventoryPatch{
+		StorageLocation: ports.Some("Garage"),
+		Notes:           ports.Null[string](),
+		Version:         1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Garage", patched.StorageLocation)
+	assert.Equal(t, "B7", patched.StorageBin)
+	assert.Empty(t, patched.Notes)
+	assert.Equal(t, item.ItemName, patched.ItemName)
+	assert.Equal(t, 2, patched.Version)
+
+	history, err := repo.FindHistory(ctx, item.LotID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.AuditUpdate, history[0].Operation)
+	assert.Equal(t, "Garage", history[0].Changes["storage_location"].New)
+	assert.Contains(t, history[0].Changes, "notes")
+	assert.NotContains(t, history[0].Changes, "storage_bin")
+
+	// A stale version is rejected
+	_, err = repo.UpdatePartial(ctx, item.LotID, ports.InventoryPatch{
+		StorageBin: ports.Some("C1"),
+		Version:    1,
+	})
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+
+	_, err = repo.UpdatePartial(ctx, uuid.New(), ports.InventoryPatch{StorageBin: ports.Some("C1")})
+	assert.ErrorContains(t, err, "inventory item not found")
 }
 
 func TestInventoryRepository_Delete_Unit(t *testing.T) {
@@ -145,6 +230,129 @@ func TestInventoryRepository_Delete_Unit(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func TestInventoryRepository_History_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.WithValue(context.Background(), logger.ContextKeyUserID, "user-1")
+
+	item := helpers.CreateTestInventoryItem()
+	require.NoError(t, repo.Save(ctx, item))
+
+	item.StorageBin = "Z9"
+	require.NoError(t, repo.Update(ctx, item))
+
+	// A rejected update must not leave an audit row behind
+	stale := *item
+	stale.Version = 1
+	require.ErrorIs(t, repo.Update(ctx, &stale), domain.ErrVersionConflict)
+
+	require.NoError(t, repo.SoftDelete(ctx, item.LotID))
+
+	history, err := repo.FindHistory(ctx, item.LotID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	// Newest first
+	assert.Equal(t, domain.AuditSoftDelete, history[0].Operation)
+	assert.Contains(t, history[0].Changes, "deleted_at")
+
+	assert.Equal(t, domain.AuditUpdate, history[1].Operation)
+	assert.Equal(t, "Z9", history[1].Changes["storage_bin"].New)
+	assert.NotContains(t, history[1].Changes, "item_name")
+
+	assert.Equal(t, domain.AuditCreate, history[2].Operation)
+	assert.Equal(t, item.ItemName, history[2].Changes["item_name"].New)
+	assert.Equal(t, "user-1", history[2].UserID)
+
+	// The trail outlives a hard delete, which records the last stored values
+	require.NoError(t, repo.Delete(ctx, item.LotID))
+	history, err = repo.FindHistory(ctx, item.LotID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, domain.AuditDelete, history[0].Operation)
+	assert.Equal(t, "Z9", history[0].Changes["storage_bin"].Old)
+}
+
+func TestInventoryRepository_Restore_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	item := helpers.CreateTestInventoryItem()
+	require.NoError(t, repo.Save(ctx, item))
+
+	// Restoring an active item is rejected
+	err := repo.Restore(ctx, item.LotID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "inventory item not found")
+
+	// Soft delete, then restore
+	require.NoError(t, repo.SoftDelete(ctx, item.LotID))
+	exists, err := repo.Exists(ctx, item.LotID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, repo.Restore(ctx, item.LotID))
+	exists, err = repo.Exists(ctx, item.LotID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Unknown items are not found
+	err = repo.Restore(ctx, uuid.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "inventory item not found")
+}
+
+func TestInventoryRepository_FindAll_RelevanceSort_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	// search_vector weights item_name (A) above description (B) above keywords (C)
+	inKeywords := helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+		item.ItemName = "Box Lot"
+		item.Description = "Assorted kitchen items"
+		item.Keywords = []string{"lantern"}
+	})
+	inName := helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+		item.ItemName = "Brass Lantern"
+		item.Description = "Railroad lantern with red globe"
+		item.Keywords = []string{"brass"}
+	})
+	inDescription := helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+		item.ItemName = "Camping Gear"
+		item.Description = "Tent and a lantern"
+		item.Keywords = []string{"camping"}
+	})
+	for _, item := range []*domain.InventoryItem{inKeywords, inName, inDescription} {
+		require.NoError(t, repo.Save(ctx, item))
+	}
+
+	items, total, err := repo.FindAll(ctx, ports.ListParams{
+		Search:   "lantern",
+		SortBy:   "relevance",
+		Page:     1,
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, inName.LotID, items[0].LotID)
+	assert.Equal(t, inDescription.LotID, items[1].LotID)
+	assert.Equal(t, inKeywords.LotID, items[2].LotID)
+
+	highlights, err := repo.SearchHighlights(ctx, "lantern", []uuid.UUID{inName.LotID})
+	require.NoError(t, err)
+	assert.Contains(t, highlights[inName.LotID], "<mark>lantern</mark>")
+}
+
 func TestInventoryRepository_FindByInvoiceID_Unit(t *testing.T) {
 	testDB := helpers.SetupTestDB(t)
 	defer testDB.Database.Close()
@@ -199,3 +407,241 @@ func TestInventoryRepository_SaveBatch_Unit(t *testing.T) {
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, count, int64(5))
 }
+
+func TestInventoryRepository_SaveBatchUpsert_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	t.Run("lot_id_conflict_updates_existing_row", func(t *testing.T) {
+		original := helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+			item.InvoiceID = "UPSERT-LOT"
+		})
+		require.NoError(t, repo.SaveBatch(ctx, []domain.InventoryItem{*original}))
+
+		updated := *original
+		updated.ItemName = "Renamed Tea Set"
+		updated.BidAmount = decimal.NewFromInt(300)
+		require.NoError(t, repo.SaveBatchUpsert(ctx, []domain.InventoryItem{updated}, ports.ConflictOnLotID))
+
+		saved, err := repo.FindByID(ctx, original.LotID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed Tea Set", saved.ItemName)
+		assert.True(t, decimal.NewFromInt(300).Equal(saved.BidAmount))
+
+		items, err := repo.FindByInvoiceID(ctx, "UPSERT-LOT")
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("invoice_item_conflict_updates_matching_row", func(t *testing.T) {
+		original := helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+			item.InvoiceID = "UPSERT-NATURAL"
+		})
+		require.NoError(t, repo.SaveBatch(ctx, []domain.InventoryItem{*original}))
+
+		// A re-import assigns fresh lot IDs
+		reimported := []domain.InventoryItem{
+			*helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+				item.InvoiceID = "UPSERT-NATURAL"
+				item.BidAmount = decimal.NewFromInt(175)
+			}),
+			*helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+				item.InvoiceID = "UPSERT-NATURAL"
+				item.ItemName = "Oak Side Chair"
+			}),
+		}
+		require.NoError(t, repo.SaveBatchUpsert(ctx, reimported, ports.ConflictOnInvoiceItem))
+
+		assert.Equal(t, original.LotID, reimported[0].LotID, "matching item should keep the existing lot")
+		saved, err := repo.FindByID(ctx, original.LotID)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(175).Equal(saved.BidAmount))
+
+		items, err := repo.FindByInvoiceID(ctx, "UPSERT-NATURAL")
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("unsupported_target", func(t *testing.T) {
+		err := repo.SaveBatchUpsert(ctx, helpers.CreateTestInventoryItems(1), "sku")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported conflict target: sku")
+	})
+}
+
+func TestInventoryRepository_ListInvoices_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	acquired := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	newItem := func(invoiceID string, bid int64, daysLater int) *domain.InventoryItem {
+		item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+			i.InvoiceID = invoiceID
+			i.AuctionID = 500
+			i.BidAmount = decimal.NewFromInt(bid)
+			i.BuyersPremium = decimal.Zero
+			i.SalesTax = decimal.Zero
+			i.ShippingCost = decimal.NewFromInt(5)
+			i.AcquisitionDate = acquired.AddDate(0, 0, daysLater)
+		})
+		require.NoError(t, repo.Save(ctx, item))
+		return item
+	}
+
+	newItem("INV-A", 100, 2)
+	newItem("INV-A", 40, 0)
+	newItem("INV-B", 300, 5)
+	deleted := newItem("INV-B", 1000, 5)
+	require.NoError(t, repo.SoftDelete(ctx, deleted.LotID))
+
+	invoices, total, err := repo.ListInvoices(ctx, ports.InvoiceListParams{
+		Page: 1, PageSize: 10, SortBy: ports.InvoiceSortDate, SortOrder: "asc",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, invoices, 2)
+
+	// Items are grouped per invoice, dated by the earliest acquisition, without deleted items
+	assert.Equal(t, "INV-A", invoices[0].InvoiceID)
+	assert.Equal(t, 500, invoices[0].AuctionID)
+	assert.Equal(t, 2, invoices[0].ItemCount)
+	assert.True(t, decimal.NewFromInt(150).Equal(invoices[0].TotalCost), "INV-A total %s", invoices[0].TotalCost)
+	assert.True(t, acquired.Equal(invoices[0].AcquisitionDate.UTC()), "INV-A date %s", invoices[0].AcquisitionDate)
+
+	assert.Equal(t, "INV-B", invoices[1].InvoiceID)
+	assert.Equal(t, 1, invoices[1].ItemCount)
+	assert.True(t, decimal.NewFromInt(305).Equal(invoices[1].TotalCost), "INV-B total %s", invoices[1].TotalCost)
+
+	// Sorted by total and paged
+	invoices, total, err = repo.ListInvoices(ctx, ports.InvoiceListParams{
+		Page: 2, PageSize: 1, SortBy: ports.InvoiceSortTotal, SortOrder: "desc",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, invoices, 1)
+	assert.Equal(t, "INV-A", invoices[0].InvoiceID)
+}
+
+func TestInventoryRepository_StorageSummary_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	newItem := func(location, bin string, value int64) *domain.InventoryItem {
+		item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+			v := decimal.NewFromInt(value)
+			i.StorageLocation = location
+			i.StorageBin = bin
+			i.EstimatedValue = &v
+		})
+		require.NoError(t, repo.Save(ctx, item))
+		return item
+	}
+
+	newItem("Garage", "A1", 100)
+	newItem("Garage", "A1", 50)
+	newItem("Garage", "", 20)
+	newItem("", "", 10)
+	newItem("  ", "", 5)
+	newItem("", "B2", 7)
+	deleted := newItem("", "", 1000)
+	require.NoError(t, repo.SoftDelete(ctx, deleted.LotID))
+
+	// Save writes empty strings, so set one location to NULL directly
+	_, err := testDB.PgxPool.Exec(ctx, "UPDATE inventory SET storage_location = NULL WHERE storage_bin = 'B2'")
+	require.NoError(t, err)
+
+	// NULL, empty and blank locations and bins share the unassigned bucket, without deleted items
+	bins, err := repo.StorageSummary(ctx)
+	require.NoError(t, err)
+
+	type key struct{ location, bin string }
+	got := make(map[key]ports.StorageBinSummary, len(bins))
+	for _, bin := range bins {
+		got[key{bin.Location, bin.Bin}] = bin
+	}
+	require.Len(t, got, 4)
+
+	want := map[key]struct {
+		count int
+		value int64
+	}{
+		{"Garage", "A1"}:                                   {2, 150},
+		{"Garage", ports.UnassignedStorage}:                {1, 20},
+		{ports.UnassignedStorage, ports.UnassignedStorage}: {2, 15},
+		{ports.UnassignedStorage, "B2"}:                    {1, 7},
+	}
+	for k, w := range want {
+		bin, ok := got[k]
+		require.True(t, ok, "missing bucket %v", k)
+		assert.Equal(t, w.count, bin.ItemCount, "%v count", k)
+		assert.True(t, decimal.NewFromInt(w.value).Equal(bin.EstimatedValue), "%v value %s", k, bin.EstimatedValue)
+	}
+}
+
+func TestInventoryRepository_TimeSeries_Unit(t *testing.T) {
+	testDB := helpers.SetupTestDB(t)
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	ctx := context.Background()
+
+	acquire := func(at time.Time) *domain.InventoryItem {
+		item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+			i.AcquisitionDate = at
+		})
+		require.NoError(t, repo.Save(ctx, item))
+		return item
+	}
+
+	// Monday 4 March to Sunday 10 March 2024, with items just outside the week on either side
+	day := func(d, hour, minute int) time.Time { return time.Date(2024, 3, d, hour, minute, 0, 0, time.UTC) }
+	sold := acquire(day(4, 9, 0))
+	acquire(day(4, 23, 59))
+	acquire(day(6, 12, 0))
+	acquire(day(10, 23, 59))
+	acquire(day(3, 23, 59))
+	acquire(day(11, 0, 0))
+	deleted := acquire(day(6, 13, 0))
+	require.NoError(t, repo.SoftDelete(ctx, deleted.LotID))
+
+	_, err := testDB.PgxPool.Exec(ctx, `
+		INSERT INTO platform_listings (lot_id, platform, status, list_price, sold_price, platform_fees, sold_date)
+		VALUES ($1, 'ebay', 'sold', 120, 100, 10, $2)`, sold.LotID, day(8, 18, 30))
+	require.NoError(t, err)
+
+	params := ports.TimeSeriesParams{Interval: ports.IntervalDay, From: day(4, 0, 0), To: day(11, 0, 0)}
+	buckets, err := repo.TimeSeries(ctx, params)
+	require.NoError(t, err)
+
+	require.Len(t, buckets, 4)
+	assert.Equal(t, day(4, 0, 0), buckets[0].Start)
+	assert.Equal(t, 2, buckets[0].Acquisitions)
+	assert.True(t, sold.TotalCost.Mul(decimal.NewFromInt(2)).Equal(buckets[0].Spend), "spend %s", buckets[0].Spend)
+	assert.Equal(t, day(6, 0, 0), buckets[1].Start)
+	assert.Equal(t, 1, buckets[1].Acquisitions)
+	assert.Equal(t, day(8, 0, 0), buckets[2].Start)
+	assert.Equal(t, 0, buckets[2].Acquisitions)
+	assert.Equal(t, 1, buckets[2].ItemsSold)
+	assert.True(t, decimal.NewFromInt(90).Sub(sold.TotalCost).Equal(buckets[2].NetProfit), "net profit %s", buckets[2].NetProfit)
+	assert.Equal(t, day(10, 0, 0), buckets[3].Start)
+	assert.Equal(t, 1, buckets[3].Acquisitions)
+
+	// The whole week falls in the bucket starting Monday
+	params.Interval = ports.IntervalWeek
+	buckets, err = repo.TimeSeries(ctx, params)
+	require.NoError(t, err)
+
+	require.Len(t, buckets, 1)
+	assert.Equal(t, day(4, 0, 0), buckets[0].Start)
+	assert.Equal(t, 4, buckets[0].Acquisitions)
+	assert.Equal(t, 1, buckets[0].ItemsSold)
+}
@@ -0,0 +1,239 @@
+// internal/adapters/db/listing_repository.go
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// listingRepository implements ports.ListingRepository
+type listingRepository struct {
+	db     *Database
+	logger *slog.Logger
+	qb     squirrel.StatementBuilderType
+}
+
+// NewListingRepository creates a new platform listing repository
+func NewListingRepository(db *Database, logger *slog.Logger) ports.ListingRepository {
+	return &listingRepository{
+		db:     db,
+		logger: logger.With(slog.String("repository", "listing")),
+		qb:     squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// listingColumns returns the columns scanned by scanListing, in order.
+// The platform category ID lives in the metadata JSONB column, and list_price
+// is NULL for sales recorded without a prior listing.
+func listingColumns() []string {
+	return []string{
+		"id", "lot_id", "platform", "status", "COALESCE(list_price, 0)",
+		"COALESCE(listing_title, '')", "COALESCE(listing_description, '')",
+		"COALESCE(metadata->>'category_id', '')", "COALESCE(listing_url, '')",
+		"created_at", "updated_at",
+		"sold_price", "sold_date", "COALESCE(shipping_paid, 0)",
+	}
+}
+
+// Create inserts a new platform listing
+func (r *listingRepository) Create(ctx context.Context, listing *domain.PlatformListing) error {
+	if listing.ID == uuid.Nil {
+		listing.ID = uuid.New()
+	}
+
+	metadata, err := json.Marshal(map[string]string{"category_id": listing.CategoryID})
+	if err != nil {
+		return fmt.Errorf("failed to encode listing metadata: %w", err)
+	}
+
+	query := r.qb.Insert("platform_listings").
+		Columns("id", "lot_id", "platform", "status", "list_price",
+			"listing_title", "listing_description", "metadata").
+		Values(listing.ID, listing.LotID, listing.Platform, listing.Status, listing.ListPrice,
+			listing.Title, listing.Description, metadata).
+		Suffix("RETURNING created_at, updated_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&listing.CreatedAt, &listing.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("listing already exists for lot %s on %s", listing.LotID, listing.Platform)
+		}
+		return fmt.Errorf("failed to insert listing: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "listing created",
+		slog.String("id", listing.ID.String()),
+		slog.String("lot_id", listing.LotID.String()),
+		slog.String("platform", string(listing.Platform)))
+
+	return nil
+}
+
+// Update saves the editable fields of an existing listing
+func (r *listingRepository) Update(ctx context.Context, listing *domain.PlatformListing) error {
+	query := r.qb.Update("platform_listings").
+		Set("list_price", listing.ListPrice).
+		Set("listing_title", listing.Title).
+		Set("listing_description", listing.Description).
+		Set("metadata", squirrel.Expr("COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('category_id', ?::text)", listing.CategoryID)).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"id": listing.ID}).
+		Suffix("RETURNING updated_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&listing.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("listing not found: %s", listing.ID)
+		}
+		return fmt.Errorf("failed to update listing: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a listing by ID, returning nil when it does not exist
+func (r *listingRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.PlatformListing, error) {
+	query := r.qb.Select(listingColumns()...).
+		From("platform_listings").
+		Where(squirrel.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	listing, err := scanListing(r.db.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan listing: %w", err)
+	}
+
+	return listing, nil
+}
+
+// FindByPlatform retrieves a page of listings for a platform, newest first
+func (r *listingRepository) FindByPlatform(ctx context.Context, platform domain.Platform, page, pageSize int) ([]*domain.PlatformListing, int64, error) {
+	countSQL, countArgs, err := r.qb.Select("COUNT(*)").
+		From("platform_listings").
+		Where(squirrel.Eq{"platform": platform}).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count listings: %w", err)
+	}
+
+	query := r.qb.Select(listingColumns()...).
+		From("platform_listings").
+		Where(squirrel.Eq{"platform": platform}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((page - 1) * pageSize))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query listings: %w", err)
+	}
+	defer rows.Close()
+
+	listings := make([]*domain.PlatformListing, 0, pageSize)
+	for rows.Next() {
+		listing, err := scanListing(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan listing: %w", err)
+		}
+		listings = append(listings, listing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate listings: %w", err)
+	}
+
+	return listings, total, nil
+}
+
+// RecordSale marks the item as sold on the sale's platform, creating the listing
+// row when the item was never listed there
+func (r *listingRepository) RecordSale(ctx context.Context, sale *domain.Sale) (*domain.PlatformListing, error) {
+	query := r.qb.Insert("platform_listings").
+		Columns("id", "lot_id", "platform", "status", "sold_price", "sold_date", "shipping_paid").
+		Values(uuid.New(), sale.LotID, sale.Platform, domain.StatusSold, sale.SalePrice, sale.SoldAt, sale.ShippingCharged).
+		Suffix(`ON CONFLICT (lot_id, platform) DO UPDATE SET
+			status = EXCLUDED.status,
+			sold_price = EXCLUDED.sold_price,
+			sold_date = EXCLUDED.sold_date,
+			shipping_paid = EXCLUDED.shipping_paid,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + strings.Join(listingColumns(), ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sale query: %w", err)
+	}
+
+	listing, err := scanListing(r.db.QueryRow(ctx, sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record sale: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "sale recorded",
+		slog.String("lot_id", sale.LotID.String()),
+		slog.String("platform", string(sale.Platform)),
+		slog.String("sale_price", sale.SalePrice.String()))
+
+	return listing, nil
+}
+
+// scanListing scans a row selected with listingColumns
+func scanListing(row pgx.Row) (*domain.PlatformListing, error) {
+	listing := &domain.PlatformListing{}
+	var soldPrice decimal.NullDecimal
+	err := row.Scan(
+		&listing.ID, &listing.LotID, &listing.Platform, &listing.Status, &listing.ListPrice,
+		&listing.Title, &listing.Description, &listing.CategoryID, &listing.ListingURL,
+		&listing.CreatedAt, &listing.UpdatedAt,
+		&soldPrice, &listing.SoldAt, &listing.ShippingCharged,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if soldPrice.Valid {
+		listing.SoldPrice = &soldPrice.Decimal
+	}
+	return listing, nil
+}
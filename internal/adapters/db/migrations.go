@@ -275,13 +275,13 @@ func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
 	version, dirty, err := m.migrate.Version()
 	if err != nil {
 		if err == migrate.ErrNilVersion {
-			m.logger.InfoContext(ctx, "no migrations applied yet")
+			m.logger.DebugContext(ctx, "no migrations applied yet")
 			return 0, false, nil
 		}
 		return 0, false, fmt.Errorf("failed to get version: %w", err)
 	}
 
-	m.logger.InfoContext(ctx, "current migration version",
+	m.logger.DebugContext(ctx, "current migration version",
 		slog.Uint64("version", uint64(version)),
 		slog.Bool("dirty", dirty))
 
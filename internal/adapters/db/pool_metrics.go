@@ -0,0 +1,62 @@
+// internal/adapters/db/pool_metrics.go
+package db
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// PoolCollector exports connection pool stats as Prometheus metrics, read fresh on every scrape
+type PoolCollector struct {
+	stats func() ports.PoolStats
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+// NewPoolCollector creates a collector for the pool behind stats
+func NewPoolCollector(stats func() ports.PoolStats) *PoolCollector {
+	return &PoolCollector{
+		stats:                stats,
+		acquiredConns:        prometheus.NewDesc("db_pool_acquired_connections", "Connections currently checked out of the pool.", nil, nil),
+		idleConns:            prometheus.NewDesc("db_pool_idle_connections", "Idle connections in the pool.", nil, nil),
+		totalConns:           prometheus.NewDesc("db_pool_total_connections", "Connections in the pool, acquired, idle or being opened.", nil, nil),
+		maxConns:             prometheus.NewDesc("db_pool_max_connections", "Maximum size of the pool.", nil, nil),
+		acquireCount:         prometheus.NewDesc("db_pool_acquires_total", "Successful connection acquires.", nil, nil),
+		acquireDuration:      prometheus.NewDesc("db_pool_acquire_wait_seconds_total", "Time spent waiting to acquire connections.", nil, nil),
+		emptyAcquireCount:    prometheus.NewDesc("db_pool_empty_acquires_total", "Acquires that had to wait because the pool was empty.", nil, nil),
+		canceledAcquireCount: prometheus.NewDesc("db_pool_canceled_acquires_total", "Acquires canceled by their context while waiting.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireCount
+	ch <- c.canceledAcquireCount
+}
+
+// Collect implements prometheus.Collector
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(s.MaxConns))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(s.AcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(s.EmptyAcquireCount))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(s.CanceledAcquireCount))
+}
@@ -5,12 +5,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
 )
 
 // Config holds database configuration
@@ -53,9 +56,10 @@ func DefaultConfig() *Config {
 
 // Database wraps pgxpool with additional functionality
 type Database struct {
-	pool   *pgxpool.Pool
-	config *Config
-	logger *slog.Logger
+	pool     *pgxpool.Pool
+	config   *Config
+	password atomic.Pointer[string]
+	logger   *slog.Logger
 }
 
 // NewDatabase creates a new database connection pool
@@ -69,6 +73,19 @@ func NewDatabase(ctx context.Context, config *Config, logger *slog.Logger) (*Dat
 		return nil, fmt.Errorf("failed to build pool config: %w", err)
 	}
 
+	db := &Database{
+		config: config,
+		logger: logger,
+	}
+	db.password.Store(&config.Password)
+
+	// New connections authenticate with the latest password so it can be rotated while running
+	beforeConnect := poolConfig.BeforeConnect
+	poolConfig.BeforeConnect = func(ctx context.Context, cfg *pgx.ConnConfig) error {
+		cfg.Password = *db.password.Load()
+		return beforeConnect(ctx, cfg)
+	}
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
@@ -79,12 +96,7 @@ func NewDatabase(ctx context.Context, config *Config, logger *slog.Logger) (*Dat
 		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
-
-	db := &Database{
-		pool:   pool,
-		config: config,
-		logger: logger,
-	}
+	db.pool = pool
 
 	logger.Info("database connection established",
 		slog.String("host", config.Host),
@@ -147,6 +159,21 @@ func (db *Database) Pool() *pgxpool.Pool {
 	return db.pool
 }
 
+// UpdatePassword switches the pool to a rotated password. Idle connections are closed right
+// away and busy ones when they're released, so the pool reconnects with the new password
+// without interrupting in-flight queries.
+func (db *Database) UpdatePassword(ctx context.Context, password string) error {
+	db.password.Store(&password)
+	db.pool.Reset()
+
+	if err := db.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to reconnect with rotated password: %w", err)
+	}
+
+	db.logger.InfoContext(ctx, "database pool reconnected with rotated password")
+	return nil
+}
+
 // Close closes all database connections
 func (db *Database) Close() {
 	db.pool.Close()
@@ -186,6 +213,21 @@ func (db *Database) Health(ctx context.Context) map[string]interface{} {
 	return health
 }
 
+// Stats returns a snapshot of connection pool usage
+func (db *Database) Stats() ports.PoolStats {
+	stats := db.pool.Stat()
+	return ports.PoolStats{
+		AcquiredConns:        stats.AcquiredConns(),
+		IdleConns:            stats.IdleConns(),
+		TotalConns:           stats.TotalConns(),
+		MaxConns:             stats.MaxConns(),
+		AcquireCount:         stats.AcquireCount(),
+		AcquireDuration:      stats.AcquireDuration(),
+		EmptyAcquireCount:    stats.EmptyAcquireCount(),
+		CanceledAcquireCount: stats.CanceledAcquireCount(),
+	}
+}
+
 // Transaction executes a function within a database transaction
 func (db *Database) Transaction(ctx context.Context, fn func(pgx.Tx) error) error {
 	tx, err := db.pool.BeginTx(ctx, pgx.TxOptions{})
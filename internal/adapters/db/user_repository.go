@@ -0,0 +1,89 @@
+// internal/adapters/db/user_repository.go
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// userRepository implements ports.UserRepository
+type userRepository struct {
+	db     *Database
+	logger *slog.Logger
+	qb     squirrel.StatementBuilderType
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *Database, logger *slog.Logger) ports.UserRepository {
+	return &userRepository{
+		db:     db,
+		logger: logger.With(slog.String("repository", "user")),
+		qb:     squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// FindByEmail loads a user by case-insensitive email
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.findOne(ctx, squirrel.Expr("LOWER(email) = LOWER(?)", email))
+}
+
+// FindByID loads a user by ID. A malformed ID is a miss rather than an error.
+func (r *userRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil
+	}
+	return r.findOne(ctx, squirrel.Eq{"id": userID})
+}
+
+func (r *userRepository) findOne(ctx context.Context, where squirrel.Sqlizer) (*domain.User, error) {
+	sql, args, err := r.qb.Select("id::text", "password_hash", "role", "is_active").
+		From("users").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var user domain.User
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.PasswordHash, &user.Role, &user.IsActive); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return &user, nil
+}
+
+// UpdatePasswordHash replaces a user's stored password hash
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id, passwordHash string) error {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("user not found: %s", id)
+	}
+
+	sql, args, err := r.qb.Update("users").
+		Set("password_hash", passwordHash).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "password hash updated", slog.String("user_id", id))
+	return nil
+}
@@ -9,26 +9,43 @@ import (
 	"time"
 
 	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
 // CacheKeyPrefix defines prefixes for different cache types
 type CacheKeyPrefix string
 
 const (
-	PrefixInventory CacheKeyPrefix = "inv"
-	PrefixDashboard CacheKeyPrefix = "dash"
-	PrefixAnalytics CacheKeyPrefix = "analytics"
-	PrefixSearch    CacheKeyPrefix = "search"
-	PrefixExport    CacheKeyPrefix = "export"
-	PrefixSession   CacheKeyPrefix = "session"
+	PrefixInventory   CacheKeyPrefix = "inv"
+	PrefixDashboard   CacheKeyPrefix = "dash"
+	PrefixAnalytics   CacheKeyPrefix = "analytics"
+	PrefixSearch      CacheKeyPrefix = "search"
+	PrefixExport      CacheKeyPrefix = "export"
+	PrefixSession     CacheKeyPrefix = "session"
+	PrefixLock        CacheKeyPrefix = "lock"
+	PrefixIdempotency CacheKeyPrefix = "idem"
 )
 
+// lockPollInterval is how often GetOrSet callers waiting on another caller's lock check for the value
+const lockPollInterval = 25 * time.Millisecond
+
+// compareAndDeleteScript deletes a key only while it still holds the expected value
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
 // Cache provides caching functionality with Redis
 type Cache struct {
 	client *redis.Client
 	ttl    time.Duration
 	logger *slog.Logger
+
+	// loads collapses concurrent GetOrSet misses for the same key into one loader call
+	loads singleflight.Group
 }
 
 // Statically assert that *Cache implements the CacheRepository interface.
@@ -54,14 +71,14 @@ func (c *Cache) SetWithTTL(ctx context.Context, key string, value interface{}, t
 	if err != nil {
 		c.logger.ErrorContext(ctx, "failed to marshal cache value",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("marshal error: %w", err)
 	}
 
 	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		c.logger.ErrorContext(ctx, "failed to set cache",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("redis set error: %w", err)
 	}
 
@@ -83,14 +100,14 @@ func (c *Cache) Get(ctx context.Context, key string, dest interface{}) error {
 		}
 		c.logger.ErrorContext(ctx, "failed to get cache",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("redis get error: %w", err)
 	}
 
 	if err := json.Unmarshal(data, dest); err != nil {
 		c.logger.ErrorContext(ctx, "failed to unmarshal cache value",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("unmarshal error: %w", err)
 	}
 
@@ -107,7 +124,7 @@ func (c *Cache) Delete(ctx context.Context, keys ...string) error {
 	if err := c.client.Del(ctx, keys...).Err(); err != nil {
 		c.logger.ErrorContext(ctx, "failed to delete cache",
 			slog.Any("keys", keys),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("redis del error: %w", err)
 	}
 
@@ -127,7 +144,7 @@ func (c *Cache) DeletePattern(ctx context.Context, pattern string) error {
 	if err := iter.Err(); err != nil {
 		c.logger.ErrorContext(ctx, "failed to scan keys",
 			slog.String("pattern", pattern),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("redis scan error: %w", err)
 	}
 
@@ -144,7 +161,7 @@ func (c *Cache) Exists(ctx context.Context, keys ...string) (bool, error) {
 	if err != nil {
 		c.logger.ErrorContext(ctx, "failed to check cache existence",
 			slog.Any("keys", keys),
-			err)
+			slog.String("error", err.Error()))
 		return false, fmt.Errorf("redis exists error: %w", err)
 	}
 
@@ -157,46 +174,152 @@ func (c *Cache) Expire(ctx context.Context, key string, ttl time.Duration) error
 		c.logger.ErrorContext(ctx, "failed to set expiration",
 			slog.String("key", key),
 			slog.Duration("ttl", ttl),
-			err)
+			slog.String("error", err.Error()))
 		return fmt.Errorf("redis expire error: %w", err)
 	}
 
 	return nil
 }
 
-// GetOrSet retrieves from cache or sets if not found
-func (c *Cache) GetOrSet(ctx context.Context, key string, dest interface{},
-	fetch func() (interface{}, error), ttl time.Duration) error {
+// GetOrSet returns the raw bytes stored under key. On a miss, or when Redis can't be read, it
+// runs loader, stores the result with ttl and returns it; concurrent misses for the same key
+// wait on a single loader call and share its result, so callers must not modify the returned
+// slice. ports.WithLock extends that protection across instances.
+func (c *Cache) GetOrSet(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error),
+	opts ...ports.GetOrSetOption) ([]byte, error) {
+
+	var o ports.GetOrSetOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-	// Try to get from cache first
-	err := c.Get(ctx, key, dest)
+	data, err := c.client.Get(ctx, key).Bytes()
 	if err == nil {
-		return nil // Cache hit
+		c.logger.DebugContext(ctx, "cache hit", slog.String("key", key))
+		return data, nil
+	}
+	if err != redis.Nil {
+		// Serve from the loader rather than fail when Redis is unavailable
+		c.logger.WarnContext(ctx, "failed to get cache, loading value",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
 	}
 
-	if err != ErrCacheMiss {
-		return err // Actual error
+	v, err, shared := c.loads.Do(key, func() (interface{}, error) {
+		if o.LockTTL > 0 {
+			return c.loadLocked(ctx, key, ttl, loader, o)
+		}
+		return c.load(ctx, key, ttl, loader, o)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Cache miss - fetch and store
-	value, err := fetch()
+	c.logger.DebugContext(ctx, "cache miss loaded",
+		slog.String("key", key),
+		slog.Bool("shared", shared))
+	return v.([]byte), nil
+}
+
+// load runs loader and caches its result, keeping a stale copy when StaleTTL is set
+func (c *Cache) load(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error),
+	o ports.GetOrSetOptions) ([]byte, error) {
+
+	data, err := loader()
 	if err != nil {
-		return fmt.Errorf("fetch error: %w", err)
+		return nil, fmt.Errorf("fetch error: %w", err)
 	}
 
-	// Store in cache
-	if err := c.SetWithTTL(ctx, key, value, ttl); err != nil {
+	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		// Log but don't fail if cache write fails
 		c.logger.WarnContext(ctx, "failed to cache value after fetch",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 	}
+	if o.StaleTTL > 0 {
+		if err := c.client.Set(ctx, staleKey(key), data, ttl+o.StaleTTL).Err(); err != nil {
+			c.logger.WarnContext(ctx, "failed to cache stale copy after fetch",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+		}
+	}
+	return data, nil
+}
 
-	// Copy value to destination
-	data, _ := json.Marshal(value)
-	json.Unmarshal(data, dest)
+// loadLocked runs loader while holding a distributed lock taken with SET NX PX. Callers that
+// lose the lock are served the stale copy when there is one, and otherwise poll for the
+// holder's value for up to LockWait before loading it themselves.
+func (c *Cache) loadLocked(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error),
+	o ports.GetOrSetOptions) ([]byte, error) {
+
+	lockKey := BuildKey(PrefixLock, "cache", key)
+	token := uuid.NewString()
+
+	err := c.client.Do(ctx, "SET", lockKey, token, "NX", "PX", o.LockTTL.Milliseconds()).Err()
+	switch {
+	case err == nil:
+		defer func() {
+			// Release even if the request was cancelled, so waiters don't sit out the lock TTL
+			releaseCtx := context.WithoutCancel(ctx)
+			if err := compareAndDeleteScript.Run(releaseCtx, c.client, []string{lockKey}, token).Err(); err != nil {
+				c.logger.WarnContext(ctx, "failed to release cache lock",
+					slog.String("key", lockKey),
+					slog.String("error", err.Error()))
+			}
+		}()
+
+		// Another instance may have cached the value between our miss and taking the lock
+		if data, err := c.client.Get(ctx, key).Bytes(); err == nil {
+			return data, nil
+		}
+		return c.load(ctx, key, ttl, loader, o)
 
-	return nil
+	case err != redis.Nil:
+		c.logger.WarnContext(ctx, "failed to take cache lock, loading value",
+			slog.String("key", lockKey),
+			slog.String("error", err.Error()))
+		return c.load(ctx, key, ttl, loader, o)
+	}
+
+	if o.StaleTTL > 0 {
+		if data, err := c.client.Get(ctx, staleKey(key)).Bytes(); err == nil {
+			c.logger.DebugContext(ctx, "serving stale value while locked", slog.String("key", key))
+			return data, nil
+		}
+	}
+
+	deadline := time.NewTimer(o.LockWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-deadline.C:
+			c.logger.WarnContext(ctx, "timed out waiting for cache lock, loading value",
+				slog.String("key", key),
+				slog.Duration("wait", o.LockWait))
+			return c.load(ctx, key, ttl, loader, o)
+
+		case <-ticker.C:
+			if data, err := c.client.Get(ctx, key).Bytes(); err == nil {
+				return data, nil
+			}
+			// The holder released the lock without caching a value, so contend for it again
+			if n, err := c.client.Exists(ctx, lockKey).Result(); err == nil && n == 0 {
+				return c.loadLocked(ctx, key, ttl, loader, o)
+			}
+		}
+	}
+}
+
+// staleKey is where GetOrSet keeps the copy of key served while it reloads. It shares key's
+// prefix, so pattern invalidation clears both.
+func staleKey(key string) string {
+	return key + ":stale"
 }
 
 // Increment increments a counter
@@ -205,7 +328,7 @@ func (c *Cache) Increment(ctx context.Context, key string) (int64, error) {
 	if err != nil {
 		c.logger.ErrorContext(ctx, "failed to increment counter",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return 0, fmt.Errorf("redis incr error: %w", err)
 	}
 
@@ -219,7 +342,7 @@ func (c *Cache) IncrementBy(ctx context.Context, key string, value int64) (int64
 		c.logger.ErrorContext(ctx, "failed to increment counter by value",
 			slog.String("key", key),
 			slog.Int64("value", value),
-			err)
+			slog.String("error", err.Error()))
 		return 0, fmt.Errorf("redis incrby error: %w", err)
 	}
 
@@ -237,20 +360,39 @@ func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, ttl ti
 	if err != nil {
 		c.logger.ErrorContext(ctx, "failed to setnx",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return false, fmt.Errorf("redis setnx error: %w", err)
 	}
 
 	return ok, nil
 }
 
+// CompareAndDelete removes a key only if it still holds value, so a lock holder
+// cannot release a lock that expired and was taken by someone else
+func (c *Cache) CompareAndDelete(ctx context.Context, key string, value interface{}) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("marshal error: %w", err)
+	}
+
+	deleted, err := compareAndDeleteScript.Run(ctx, c.client, []string{key}, data).Int64()
+	if err != nil {
+		c.logger.ErrorContext(ctx, "failed to compare and delete",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return false, fmt.Errorf("redis compare and delete error: %w", err)
+	}
+
+	return deleted == 1, nil
+}
+
 // TTL returns the time to live for a key
 func (c *Cache) TTL(ctx context.Context, key string) (time.Duration, error) {
 	ttl, err := c.client.TTL(ctx, key).Result()
 	if err != nil {
 		c.logger.ErrorContext(ctx, "failed to get TTL",
 			slog.String("key", key),
-			err)
+			slog.String("error", err.Error()))
 		return 0, fmt.Errorf("redis ttl error: %w", err)
 	}
 
@@ -260,7 +402,7 @@ func (c *Cache) TTL(ctx context.Context, key string) (time.Duration, error) {
 // Flush removes all keys from the current database
 func (c *Cache) Flush(ctx context.Context) error {
 	if err := c.client.FlushDB(ctx).Err(); err != nil {
-		c.logger.ErrorContext(ctx, "failed to flush database", err)
+		c.logger.ErrorContext(ctx, "failed to flush database", slog.String("error", err.Error()))
 		return fmt.Errorf("redis flushdb error: %w", err)
 	}
 
@@ -271,7 +413,7 @@ func (c *Cache) Flush(ctx context.Context) error {
 // Ping checks if Redis is accessible
 func (c *Cache) Ping(ctx context.Context) error {
 	if err := c.client.Ping(ctx).Err(); err != nil {
-		c.logger.ErrorContext(ctx, "redis ping failed", err)
+		c.logger.ErrorContext(ctx, "redis ping failed", slog.String("error", err.Error()))
 		return fmt.Errorf("redis ping error: %w", err)
 	}
 
@@ -327,19 +469,23 @@ func NewCacheManager(cache ports.CacheRepository, logger *slog.Logger) *CacheMan
 	}
 }
 
-// InvalidateInventoryCache invalidates all inventory-related cache entries
+// InvalidateInventoryCache invalidates all inventory-related cache entries, including every
+// cached list page, search suggestion and export since any item may appear in one
 func (m *CacheManager) InvalidateInventoryCache(ctx context.Context, lotID string) error {
 	patterns := []string{
 		fmt.Sprintf("%s:*%s*", PrefixInventory, lotID),
+		fmt.Sprintf("%s:list:*", PrefixInventory),
+		fmt.Sprintf("%s:*", PrefixSearch),
 		fmt.Sprintf("%s:*", PrefixDashboard),
 		fmt.Sprintf("%s:*", PrefixAnalytics),
+		fmt.Sprintf("%s:*", PrefixExport),
 	}
 
 	for _, pattern := range patterns {
 		if err := m.cache.DeletePattern(ctx, pattern); err != nil {
 			m.logger.WarnContext(ctx, "failed to invalidate cache pattern",
 				slog.String("pattern", pattern),
-				err)
+				slog.String("error", err.Error()))
 		}
 	}
 
@@ -3,6 +3,9 @@ package redis_a_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/core/ports"
 	"github.com/ammerola/resell-be/test/helpers"
 )
 
@@ -180,24 +184,199 @@ func TestCache_GetOrSet(t *testing.T) {
 	cache := redis_a.NewCache(client, 5*time.Minute, helpers.TestLogger())
 
 	fetchCount := 0
-	fetchFunc := func() (interface{}, error) {
+	loader := func() ([]byte, error) {
 		fetchCount++
-		return "fetched value", nil
+		return []byte(`{"value":"fetched"}`), nil
 	}
 
-	// First call should fetch
-	var result1 string
-	err := cache.GetOrSet(ctx, "getorset:test", &result1, fetchFunc, time.Minute)
+	// First call should fetch and store the bytes as-is
+	result1, err := cache.GetOrSet(ctx, "getorset:test", time.Minute, loader)
 	require.NoError(t, err)
-	assert.Equal(t, "fetched value", result1)
+	assert.JSONEq(t, `{"value":"fetched"}`, string(result1))
 	assert.Equal(t, 1, fetchCount)
 
+	stored, err := mr.Get("getorset:test")
+	require.NoError(t, err)
+	assert.Equal(t, `{"value":"fetched"}`, stored)
+	assert.Equal(t, time.Minute, mr.TTL("getorset:test"))
+
 	// Second call should get from cache
-	var result2 string
-	err = cache.GetOrSet(ctx, "getorset:test", &result2, fetchFunc, time.Minute)
+	result2, err := cache.GetOrSet(ctx, "getorset:test", time.Minute, loader)
 	require.NoError(t, err)
-	assert.Equal(t, "fetched value", result2)
+	assert.Equal(t, result1, result2)
 	assert.Equal(t, 1, fetchCount) // Should not increment
+
+	// Loader errors are returned and nothing is cached
+	_, err = cache.GetOrSet(ctx, "getorset:error", time.Minute, func() ([]byte, error) {
+		return nil, errors.New("load failed")
+	})
+	require.Error(t, err)
+	assert.False(t, mr.Exists("getorset:error"))
+}
+
+func TestCache_GetOrSet_ConcurrentMissesLoadOnce(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cache := redis_a.NewCache(client, 5*time.Minute, helpers.TestLogger())
+
+	const callers = 20
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func() ([]byte, error) {
+		loads.Add(1)
+		<-release // Hold the load open until every caller has missed
+		return []byte("expensive"), nil
+	}
+
+	var started, done sync.WaitGroup
+	results := make([][]byte, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			results[i], errs[i] = cache.GetOrSet(ctx, "getorset:concurrent", time.Minute, loader)
+		}(i)
+	}
+
+	started.Wait()
+	require.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // Let the other callers reach the shared load
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "expensive", string(results[i]))
+	}
+}
+
+// expireAndReload seeds key, expires it, then has callers separate Cache instances (standing in
+// for API replicas, so in-process single-flight can't help) call GetOrSet at once with a loader
+// that blocks until every caller is waiting. It returns the values and how often loader ran.
+func expireAndReload(t *testing.T, mr *miniredis.Miniredis, key string, callers int,
+	opts ...ports.GetOrSetOption) ([][]byte, int32) {
+	t.Helper()
+	ctx := context.Background()
+
+	newCache := func() ports.CacheRepository {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return redis_a.NewCache(client, 5*time.Minute, helpers.TestLogger())
+	}
+
+	_, err := newCache().GetOrSet(ctx, key, time.Minute, func() ([]byte, error) {
+		return []byte("old"), nil
+	}, opts...)
+	require.NoError(t, err)
+	mr.FastForward(2 * time.Minute)
+	require.False(t, mr.Exists(key))
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func() ([]byte, error) {
+		loads.Add(1)
+		<-release
+		return []byte("new"), nil
+	}
+
+	var done sync.WaitGroup
+	results := make([][]byte, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		cache := newCache()
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			results[i], errs[i] = cache.GetOrSet(ctx, key, time.Minute, loader, opts...)
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(100 * time.Millisecond) // Let the other callers find the lock taken
+	assert.True(t, mr.Exists("lock:cache:"+key))
+	close(release)
+	done.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.False(t, mr.Exists("lock:cache:"+key), "lock should be released")
+	return results, loads.Load()
+}
+
+func TestCache_GetOrSet_LockedExpiryLoadsOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	results, loads := expireAndReload(t, mr, "getorset:locked", 10, ports.WithLock(5*time.Second, 5*time.Second))
+
+	assert.Equal(t, int32(1), loads)
+	for _, result := range results {
+		assert.Equal(t, "new", string(result))
+	}
+}
+
+func TestCache_GetOrSet_LockedExpiryServesStale(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	results, loads := expireAndReload(t, mr, "getorset:stale", 10,
+		ports.WithLock(5*time.Second, 5*time.Second), ports.WithStale(time.Hour))
+
+	assert.Equal(t, int32(1), loads)
+	var fresh, stale int
+	for _, result := range results {
+		switch string(result) {
+		case "new":
+			fresh++
+		case "old":
+			stale++
+		}
+	}
+	assert.Equal(t, 1, fresh, "only the lock holder waits for the loader")
+	assert.Equal(t, 9, stale)
+
+	value, err := mr.Get("getorset:stale")
+	require.NoError(t, err)
+	assert.Equal(t, "new", value)
+}
+
+func TestCache_GetOrSet_LockWaitTimesOut(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cache := redis_a.NewCache(client, 5*time.Minute, helpers.TestLogger())
+
+	// A holder that never finishes doesn't block callers past the wait
+	require.NoError(t, mr.Set("lock:cache:getorset:stuck", "other-instance"))
+
+	result, err := cache.GetOrSet(ctx, "getorset:stuck", time.Minute, func() ([]byte, error) {
+		return []byte("loaded"), nil
+	}, ports.WithLock(time.Minute, 50*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", string(result))
+
+	lock, err := mr.Get("lock:cache:getorset:stuck")
+	require.NoError(t, err)
+	assert.Equal(t, "other-instance", lock, "another instance's lock is left alone")
+}
+
+func TestCache_GetOrSet_RedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cache := redis_a.NewCache(client, 5*time.Minute, helpers.TestLogger())
+	mr.Close()
+
+	// The loader still serves the value when the cache can't be read
+	result, err := cache.GetOrSet(ctx, "getorset:down", time.Minute, func() ([]byte, error) {
+		return []byte("fresh"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", string(result))
 }
 
 func TestCache_IncrementOperations(t *testing.T) {
@@ -248,6 +427,34 @@ func TestCache_SetNX(t *testing.T) {
 	assert.Equal(t, "first", result)
 }
 
+func TestCache_CompareAndDelete(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cache := redis_a.NewCache(client, 5*time.Minute, helpers.TestLogger())
+
+	ok, err := cache.SetNX(ctx, "lock:test", "owner-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// A different owner cannot release the lock
+	deleted, err := cache.CompareAndDelete(ctx, "lock:test", "owner-2")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	assert.True(t, mr.Exists("lock:test"))
+
+	// The owner can
+	deleted, err = cache.CompareAndDelete(ctx, "lock:test", "owner-1")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.False(t, mr.Exists("lock:test"))
+
+	// Missing keys are a no-op
+	deleted, err = cache.CompareAndDelete(ctx, "lock:test", "owner-1")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
 func TestCacheManager_InvalidateInventoryCache(t *testing.T) {
 	ctx := context.Background()
 	mr := miniredis.RunT(t)
@@ -260,8 +467,10 @@ func TestCacheManager_InvalidateInventoryCache(t *testing.T) {
 	keys := map[string]string{
 		"inv:test-lot-123:details": "inventory details",
 		"inv:list:page1":           "inventory list",
+		"search:suggest:10:tea":    "search suggestions",
 		"dash:summary":             "dashboard data",
 		"analytics:monthly":        "analytics data",
+		"export:json:all":          "export data",
 		"other:data":               "should not be deleted",
 	}
 
@@ -275,7 +484,7 @@ func TestCacheManager_InvalidateInventoryCache(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify related keys are invalidated
-	invalidated := []string{"inv:test-lot-123:details", "inv:list:page1", "dash:summary", "analytics:monthly"}
+	invalidated := []string{"inv:test-lot-123:details", "inv:list:page1", "search:suggest:10:tea", "dash:summary", "analytics:monthly", "export:json:all"}
 	for _, key := range invalidated {
 		var result string
 		err := cache.Get(ctx, key, &result)
@@ -0,0 +1,41 @@
+// internal/adapters/redis/credentials.go
+package redis_a
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Credentials holds a Redis password that can be rotated while the client is running
+type Credentials struct {
+	password atomic.Pointer[string]
+}
+
+// NewCredentials creates credentials starting with the given password
+func NewCredentials(password string) *Credentials {
+	c := &Credentials{}
+	c.password.Store(&password)
+	return c
+}
+
+// Provider returns the current password for redis.Options.CredentialsProvider, which the
+// client consults for every new connection
+func (c *Credentials) Provider() func() (string, string) {
+	return func() (string, string) {
+		return "", *c.password.Load()
+	}
+}
+
+// Rotate switches new connections to password and checks that the client can still reach
+// Redis. Open connections stay authenticated until they're recycled.
+func (c *Credentials) Rotate(ctx context.Context, client *redis.Client, password string) error {
+	c.password.Store(&password)
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to reach Redis after rotating password: %w", err)
+	}
+	return nil
+}
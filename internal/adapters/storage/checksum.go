@@ -0,0 +1,24 @@
+// internal/adapters/storage/checksum.go
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// ChecksumMetadataKey is the object metadata key holding the hex SHA-256 of the content
+const ChecksumMetadataKey = "sha256"
+
+// hashContent reads data into memory while computing its SHA-256. The checksum has to be
+// known before the request is sent because S3 metadata travels in the request headers.
+func hashContent(data io.Reader) (*bytes.Reader, string, error) {
+	var buf bytes.Buffer
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(&buf, hash), data); err != nil {
+		return nil, "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	return bytes.NewReader(buf.Bytes()), hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,232 @@
+// internal/adapters/storage/local.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStorage implements StorageClient on the local filesystem, for development and tests
+type LocalStorage struct {
+	basePath string
+	logger   *slog.Logger
+}
+
+// NewLocalStorage creates a new local storage client rooted at basePath
+func NewLocalStorage(basePath string, logger *slog.Logger) *LocalStorage {
+	return &LocalStorage{
+		basePath: basePath,
+		logger:   logger.With(slog.String("storage", "local")),
+	}
+}
+
+// path maps a key to a file under basePath, rejecting keys that would escape it
+func (l *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return filepath.Join(l.basePath, cleaned), nil
+}
+
+// Upload writes data to the file for key, creating parent directories as needed
+func (l *LocalStorage) Upload(ctx context.Context, key string, data io.Reader, contentType string) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, data); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	l.logger.DebugContext(ctx, "file stored", slog.String("key", key))
+	return path, nil
+}
+
+// UploadIfAbsent writes data unless the file for key already has the same SHA-256
+func (l *LocalStorage) UploadIfAbsent(ctx context.Context, key string, data io.Reader, contentType string) (string, bool, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	body, checksum, err := hashContent(data)
+	if err != nil {
+		return "", false, err
+	}
+
+	existing, err := os.Open(path)
+	switch {
+	case err == nil:
+		_, existingChecksum, hashErr := hashContent(existing)
+		existing.Close()
+		if hashErr != nil {
+			return "", false, fmt.Errorf("failed to check existing file: %w", hashErr)
+		}
+		if existingChecksum == checksum {
+			return path, true, nil
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return "", false, fmt.Errorf("failed to check existing file: %w", err)
+	}
+
+	location, err := l.Upload(ctx, key, body, contentType)
+	return location, false, err
+}
+
+// Download reads the file for key
+func (l *LocalStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes the file for key; deleting a missing key is not an error
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetPresignedURL returns a file:// URL; local files don't expire
+func (l *LocalStorage) GetPresignedURL(ctx context.Context, key string, duration time.Duration) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	return "file://" + filepath.ToSlash(path), nil
+}
+
+// List returns the keys under prefix
+func (l *LocalStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(l.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.basePath, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return keys, nil
+}
+
+// ListObjects returns the files under prefix with their size and modification time
+func (l *LocalStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(l.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.basePath, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return objects, nil
+}
+
+// Copy duplicates the file at sourceKey to destinationKey
+func (l *LocalStorage) Copy(ctx context.Context, sourceKey, destinationKey string) error {
+	src, err := l.path(sourceKey)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = l.Upload(ctx, destinationKey, f, "")
+	return err
+}
+
+// Exists reports whether a file exists for key
+func (l *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return true, nil
+}
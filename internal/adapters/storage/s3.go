@@ -2,8 +2,8 @@
 package storage
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -19,6 +19,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/google/uuid"
+
+	appconfig "github.com/ammerola/resell-be/internal/pkg/config"
 )
 
 // StorageClient defines the interface for file storage operations
@@ -28,13 +30,59 @@ type StorageClient interface {
 	Delete(ctx context.Context, key string) error
 	GetPresignedURL(ctx context.Context, key string, duration time.Duration) (string, error)
 	List(ctx context.Context, prefix string) ([]string, error)
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
 	Copy(ctx context.Context, sourceKey, destinationKey string) error
 	Exists(ctx context.Context, key string) (bool, error)
+	UploadIfAbsent(ctx context.Context, key string, data io.Reader, contentType string) (location string, existed bool, err error)
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// NewStorageClient returns the storage backend selected by FileProcessing.StorageBackend
+func NewStorageClient(ctx context.Context, cfg *appconfig.Config, logger *slog.Logger) (StorageClient, error) {
+	switch cfg.FileProcessing.StorageBackend {
+	case "s3":
+		s3Storage, err := NewS3Storage(ctx, &S3Config{
+			Region:          cfg.AWS.Region,
+			Bucket:          cfg.AWS.S3Bucket,
+			AccessKeyID:     cfg.AWS.AccessKeyID,
+			SecretAccessKey: cfg.AWS.SecretAccessKey,
+			Endpoint:        cfg.AWS.S3Endpoint,
+			UsePathStyle:    cfg.AWS.UsePathStyle,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		return s3Storage, nil
+	case "local", "":
+		return NewLocalStorage(cfg.FileProcessing.LocalStoragePath, logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", cfg.FileProcessing.StorageBackend)
+	}
+}
+
+// S3API is the subset of the S3 client used by S3Storage; *s3.Client satisfies it
+type S3API interface {
+	manager.UploadAPIClient
+	manager.DownloadAPIClient
+	s3.ListObjectsV2APIClient
+	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	CreateBucket(ctx context.Context, params *s3.CreateBucketInput, optFns ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
 }
 
 // S3Storage implements StorageClient using AWS S3
 type S3Storage struct {
-	client     *s3.Client
+	client     S3API
+	presigner  *s3.PresignClient
 	uploader   *manager.Uploader
 	downloader *manager.Downloader
 	bucket     string
@@ -68,18 +116,9 @@ func NewS3Storage(ctx context.Context, cfg *S3Config, logger *slog.Logger) (*S3S
 		o.UsePathStyle = cfg.UsePathStyle
 	})
 
-	// Create uploader and downloader
-	uploader := manager.NewUploader(client)
-	downloader := manager.NewDownloader(client)
-
-	storage := &S3Storage{
-		client:     client,
-		uploader:   uploader,
-		downloader: downloader,
-		bucket:     cfg.Bucket,
-		region:     cfg.Region,
-		logger:     logger.With(slog.String("storage", "s3")),
-	}
+	storage := NewS3StorageWithClient(client, cfg.Bucket, logger)
+	storage.region = cfg.Region
+	storage.presigner = s3.NewPresignClient(client)
 
 	// Verify bucket exists
 	if err := storage.ensureBucket(ctx); err != nil {
@@ -93,6 +132,18 @@ func NewS3Storage(ctx context.Context, cfg *S3Config, logger *slog.Logger) (*S3S
 	return storage, nil
 }
 
+// NewS3StorageWithClient wraps an existing S3 client without checking the bucket.
+// Presigned URLs are only available through NewS3Storage.
+func NewS3StorageWithClient(client S3API, bucket string, logger *slog.Logger) *S3Storage {
+	return &S3Storage{
+		client:     client,
+		uploader:   manager.NewUploader(client),
+		downloader: manager.NewDownloader(client),
+		bucket:     bucket,
+		logger:     logger.With(slog.String("storage", "s3")),
+	}
+}
+
 // buildAWSConfig builds AWS configuration
 func buildAWSConfig(ctx context.Context, cfg *S3Config) (aws.Config, error) {
 	// Use custom credentials if provided
@@ -138,8 +189,56 @@ func (s *S3Storage) ensureBucket(ctx context.Context) error {
 	return nil
 }
 
-// Upload uploads a file to S3
+// Upload uploads a file to S3, recording its SHA-256 in the object metadata
 func (s *S3Storage) Upload(ctx context.Context, key string, data io.Reader, contentType string) (string, error) {
+	body, checksum, err := hashContent(data)
+	if err != nil {
+		return "", err
+	}
+
+	location, err := s.put(ctx, key, body, contentType, checksum, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "file uploaded",
+		slog.String("key", key),
+		slog.String("location", location))
+
+	return location, nil
+}
+
+// UploadIfAbsent uploads a file unless an object with the same SHA-256 is already stored
+// at key. An object with different content is overwritten.
+func (s *S3Storage) UploadIfAbsent(ctx context.Context, key string, data io.Reader, contentType string) (string, bool, error) {
+	body, checksum, err := hashContent(data)
+	if err != nil {
+		return "", false, err
+	}
+
+	metadata, err := s.GetMetadata(ctx, key)
+	switch {
+	case err == nil && metadata[ChecksumMetadataKey] == checksum:
+		s.logger.DebugContext(ctx, "identical file already uploaded", slog.String("key", key))
+		return s.objectLocation(key), true, nil
+	case err != nil && !isNotFound(err):
+		return "", false, fmt.Errorf("failed to check existing file: %w", err)
+	}
+
+	location, err := s.put(ctx, key, body, contentType, checksum, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "file uploaded",
+		slog.String("key", key),
+		slog.String("location", location))
+
+	return location, false, nil
+}
+
+// put uploads body with the default metadata, the checksum and any extra metadata
+func (s *S3Storage) put(ctx context.Context, key string, body io.Reader, contentType, checksum string, metadata map[string]string) (string, error) {
 	// Determine content type if not provided
 	if contentType == "" {
 		contentType = mime.TypeByExtension(filepath.Ext(key))
@@ -148,31 +247,43 @@ func (s *S3Storage) Upload(ctx context.Context, key string, data io.Reader, cont
 		}
 	}
 
-	// Prepare upload input
-	input := &s3.PutObjectInput{
+	meta := map[string]string{
+		"uploaded-at":       time.Now().Format(time.RFC3339),
+		"upload-id":         uuid.New().String(),
+		ChecksumMetadataKey: checksum,
+	}
+	for k, v := range metadata {
+		meta[k] = v
+	}
+
+	result, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(key),
-		Body:        data,
+		Body:        body,
 		ContentType: aws.String(contentType),
-		Metadata: map[string]string{
-			"uploaded-at": time.Now().Format(time.RFC3339),
-			"upload-id":   uuid.New().String(),
-		},
-	}
-
-	// Perform upload
-	result, err := s.uploader.Upload(ctx, input)
+		Metadata:    meta,
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file: %w", err)
+		return "", err
 	}
 
-	s.logger.InfoContext(ctx, "file uploaded",
-		slog.String("key", key),
-		slog.String("location", result.Location))
-
 	return result.Location, nil
 }
 
+// objectLocation identifies an object that was not uploaded by this call
+func (s *S3Storage) objectLocation(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key)
+}
+
+// isNotFound reports whether err is S3's response for a missing object
+func isNotFound(err error) bool {
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	return strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "NotFound")
+}
+
 // Download downloads a file from S3
 func (s *S3Storage) Download(ctx context.Context, key string) ([]byte, error) {
 	// Create a buffer to write to
@@ -212,9 +323,11 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 
 // GetPresignedURL generates a pre-signed URL for downloading
 func (s *S3Storage) GetPresignedURL(ctx context.Context, key string, duration time.Duration) (string, error) {
-	presignClient := s3.NewPresignClient(s.client)
+	if s.presigner == nil {
+		return "", fmt.Errorf("presigned URLs are not supported by this client")
+	}
 
-	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+	request, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	}, func(opts *s3.PresignOptions) {
@@ -255,6 +368,33 @@ func (s *S3Storage) List(ctx context.Context, prefix string) ([]string, error) {
 	return keys, nil
 }
 
+// ListObjects lists the objects with a given prefix along with their size and age
+func (s *S3Storage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         obj.Size,
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
 // Copy copies a file within S3
 func (s *S3Storage) Copy(ctx context.Context, sourceKey, destinationKey string) error {
 	copySource := fmt.Sprintf("%s/%s", s.bucket, sourceKey)
@@ -284,8 +424,7 @@ func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
 	})
 
 	if err != nil {
-		// Check if it's a not found error
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "NotFound") {
+		if isNotFound(err) {
 			return false, nil
 		}
 		return false, fmt.Errorf("failed to check file existence: %w", err)
@@ -296,39 +435,17 @@ func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
 
 // UploadWithMetadata uploads a file with custom metadata
 func (s *S3Storage) UploadWithMetadata(ctx context.Context, key string, data io.Reader, contentType string, metadata map[string]string) (string, error) {
-	// Determine content type if not provided
-	if contentType == "" {
-		contentType = mime.TypeByExtension(filepath.Ext(key))
-		if contentType == "" {
-			contentType = "application/octet-stream"
-		}
-	}
-
-	// Merge default metadata with provided metadata
-	meta := map[string]string{
-		"uploaded-at": time.Now().Format(time.RFC3339),
-		"upload-id":   uuid.New().String(),
-	}
-	for k, v := range metadata {
-		meta[k] = v
-	}
-
-	// Prepare upload input
-	input := &s3.PutObjectInput{
-		Bucket:      aws.String(s.bucket),
-		Key:         aws.String(key),
-		Body:        data,
-		ContentType: aws.String(contentType),
-		Metadata:    meta,
+	body, checksum, err := hashContent(data)
+	if err != nil {
+		return "", err
 	}
 
-	// Perform upload
-	result, err := s.uploader.Upload(ctx, input)
+	location, err := s.put(ctx, key, body, contentType, checksum, metadata)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file with metadata: %w", err)
 	}
 
-	return result.Location, nil
+	return location, nil
 }
 
 // GetMetadata retrieves metadata for a file
@@ -347,9 +464,11 @@ func (s *S3Storage) GetMetadata(ctx context.Context, key string) (map[string]str
 
 // GenerateUploadPresignedURL generates a pre-signed URL for uploading
 func (s *S3Storage) GenerateUploadPresignedURL(ctx context.Context, key string, contentType string, duration time.Duration) (string, error) {
-	presignClient := s3.NewPresignClient(s.client)
+	if s.presigner == nil {
+		return "", fmt.Errorf("presigned URLs are not supported by this client")
+	}
 
-	request, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+	request, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(key),
 		ContentType: aws.String(contentType),
@@ -414,37 +533,3 @@ func (s *S3Storage) StreamUpload(ctx context.Context, key string, reader io.Read
 
 	return nil
 }
-
-// LocalStorage implements StorageClient using local filesystem (for testing)
-type LocalStorage struct {
-	basePath string
-	logger   *slog.Logger
-}
-
-// NewLocalStorage creates a new local storage client
-func NewLocalStorage(basePath string, logger *slog.Logger) *LocalStorage {
-	return &LocalStorage{
-		basePath: basePath,
-		logger:   logger.With(slog.String("storage", "local")),
-	}
-}
-
-// Upload saves a file locally
-func (l *LocalStorage) Upload(ctx context.Context, key string, data io.Reader, contentType string) (string, error) {
-	// Implementation for local file storage
-	// This is useful for testing without AWS
-	path := filepath.Join(l.basePath, key)
-
-	// Read data
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, data); err != nil {
-		return "", err
-	}
-
-	// TODO: Save to file
-	//
-
-	return path, nil
-}
-
-// Other LocalStorage methods would be implemented similarly...
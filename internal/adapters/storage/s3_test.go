@@ -0,0 +1,184 @@
+// internal/adapters/storage/s3_test.go
+package storage_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+type fakeObject struct {
+	data     []byte
+	metadata map[string]string
+}
+
+// fakeS3 stores objects in memory; operations it doesn't override panic
+type fakeS3 struct {
+	storage.S3API
+	objects map[string]fakeObject
+	headErr error
+	puts    int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string]fakeObject{}}
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.puts++
+	f.objects[*params.Key] = fakeObject{data: data, metadata: params.Metadata}
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	if f.headErr != nil {
+		return nil, f.headErr
+	}
+	obj, ok := f.objects[*params.Key]
+	if !ok {
+		return nil, &types.NotFound{}
+	}
+	return &s3.HeadObjectOutput{Metadata: obj.metadata}, nil
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestS3Storage_Upload_RecordsChecksum(t *testing.T) {
+	client := newFakeS3()
+	s3Storage := storage.NewS3StorageWithClient(client, "uploads", helpers.TestLogger())
+
+	_, err := s3Storage.Upload(context.Background(), "imports/invoice.pdf", strings.NewReader("invoice"), "application/pdf")
+
+	require.NoError(t, err)
+	obj := client.objects["imports/invoice.pdf"]
+	assert.Equal(t, []byte("invoice"), obj.data)
+	assert.Equal(t, sha256Hex("invoice"), obj.metadata[storage.ChecksumMetadataKey])
+	assert.NotEmpty(t, obj.metadata["upload-id"])
+}
+
+func TestS3Storage_UploadIfAbsent(t *testing.T) {
+	tests := []struct {
+		name          string
+		existing      string
+		headErr       error
+		expectExisted bool
+		expectPuts    int
+		expectError   string
+	}{
+		{
+			name:       "uploads_when_missing",
+			expectPuts: 1,
+		},
+		{
+			name:          "skips_identical_content",
+			existing:      "invoice",
+			expectExisted: true,
+		},
+		{
+			name:       "overwrites_different_content",
+			existing:   "older invoice",
+			expectPuts: 1,
+		},
+		{
+			name:        "fails_when_existing_object_cannot_be_checked",
+			headErr:     errors.New("access denied"),
+			expectError: "failed to check existing file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeS3()
+			if tt.existing != "" {
+				client.objects["imports/invoice.pdf"] = fakeObject{
+					data:     []byte(tt.existing),
+					metadata: map[string]string{storage.ChecksumMetadataKey: sha256Hex(tt.existing)},
+				}
+			}
+			client.headErr = tt.headErr
+			s3Storage := storage.NewS3StorageWithClient(client, "uploads", helpers.TestLogger())
+
+			location, existed, err := s3Storage.UploadIfAbsent(context.Background(), "imports/invoice.pdf", strings.NewReader("invoice"), "application/pdf")
+
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				assert.Zero(t, client.puts)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectExisted, existed)
+			assert.Equal(t, tt.expectPuts, client.puts)
+			if existed {
+				assert.Equal(t, "s3://uploads/imports/invoice.pdf", location)
+			}
+
+			obj := client.objects["imports/invoice.pdf"]
+			assert.Equal(t, []byte("invoice"), obj.data)
+			assert.Equal(t, sha256Hex("invoice"), obj.metadata[storage.ChecksumMetadataKey])
+		})
+	}
+}
+
+func TestLocalStorage_UploadIfAbsent(t *testing.T) {
+	ctx := context.Background()
+	local := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+
+	_, existed, err := local.UploadIfAbsent(ctx, "imports/invoice.pdf", strings.NewReader("invoice"), "")
+	require.NoError(t, err)
+	assert.False(t, existed)
+
+	_, existed, err = local.UploadIfAbsent(ctx, "imports/invoice.pdf", strings.NewReader("invoice"), "")
+	require.NoError(t, err)
+	assert.True(t, existed)
+
+	_, existed, err = local.UploadIfAbsent(ctx, "imports/invoice.pdf", strings.NewReader("revised invoice"), "")
+	require.NoError(t, err)
+	assert.False(t, existed)
+
+	data, err := local.Download(ctx, "imports/invoice.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("revised invoice"), data)
+}
+
+func TestLocalStorage_ListObjects(t *testing.T) {
+	ctx := context.Background()
+	local := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+
+	_, err := local.Upload(ctx, "imports/a.pdf", strings.NewReader("invoice"), "")
+	require.NoError(t, err)
+	_, err = local.Upload(ctx, "imports/nested/b.xlsx", strings.NewReader("sheet"), "")
+	require.NoError(t, err)
+	_, err = local.Upload(ctx, "reports/c.xlsx", strings.NewReader("report"), "")
+	require.NoError(t, err)
+
+	objects, err := local.ListObjects(ctx, "imports/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	sizes := map[string]int64{}
+	for _, obj := range objects {
+		sizes[obj.Key] = obj.Size
+		assert.False(t, obj.LastModified.IsZero())
+	}
+	assert.Equal(t, map[string]int64{"imports/a.pdf": 7, "imports/nested/b.xlsx": 5}, sizes)
+}
@@ -0,0 +1,36 @@
+// internal/core/domain/audit.go
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditOperation identifies the kind of write recorded in the audit trail
+type AuditOperation string
+
+// AuditOperation constants
+const (
+	AuditCreate     AuditOperation = "create"
+	AuditUpdate     AuditOperation = "update"
+	AuditSoftDelete AuditOperation = "soft_delete"
+	AuditDelete     AuditOperation = "delete"
+)
+
+// FieldChange holds a field's value before and after a write; Old is nil on create and
+// New is nil on delete
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditEntry is one recorded write to an inventory item
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	LotID     uuid.UUID              `json:"lot_id"`
+	Operation AuditOperation         `json:"operation"`
+	Changes   map[string]FieldChange `json:"changes"`
+	UserID    string                 `json:"user_id,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,173 @@
+// internal/core/domain/costs.go
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// costPlaces is the precision costs are kept at. The inventory table stores every amount as
+// DECIMAL(10, 2), rounding half away from zero as decimal.Round does, so rounding here keeps
+// the computed totals equal to the generated total_cost and cost_per_item columns.
+const costPlaces = 2
+
+var hundred = decimal.NewFromInt(100)
+
+// CostRates are the charges an auction adds to the hammer price
+type CostRates struct {
+	BuyersPremiumPercent decimal.Decimal
+	SalesTaxPercent      decimal.Decimal // charged on the bid plus the buyer's premium
+}
+
+// DefaultCostRates are the rates assumed when an invoice doesn't state its own: an 18% buyer's
+// premium and New York sales tax
+var DefaultCostRates = CostRates{
+	BuyersPremiumPercent: decimal.NewFromInt(18),
+	SalesTaxPercent:      decimal.RequireFromString("8.625"),
+}
+
+// BuyersPremium returns the premium charged on bid, rounded to the cent
+func (r CostRates) BuyersPremium(bid decimal.Decimal) decimal.Decimal {
+	return bid.Mul(r.BuyersPremiumPercent).Div(hundred).Round(costPlaces)
+}
+
+// SalesTax returns the tax charged on bid and its premium, rounded to the cent
+func (r CostRates) SalesTax(bid, buyersPremium decimal.Decimal) decimal.Decimal {
+	return bid.Add(buyersPremium).Mul(r.SalesTaxPercent).Div(hundred).Round(costPlaces)
+}
+
+// CalculateCosts sets the item's total cost and cost per item from its bid, buyer's premium,
+// sales tax and shipping. With rates the premium and tax are first charged at those rates;
+// without, the amounts already on the item are kept. Every amount is rounded to the cent before
+// it is summed, and the cost per item is the total divided by the quantity.
+func (i *InventoryItem) CalculateCosts(rates *CostRates) error {
+	if i.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	if err := i.validateAmounts(); err != nil {
+		return err
+	}
+	if rates != nil {
+		if rates.BuyersPremiumPercent.IsNegative() || rates.SalesTaxPercent.IsNegative() {
+			return fmt.Errorf("cost rates cannot be negative")
+		}
+		i.BuyersPremium = rates.BuyersPremium(i.BidAmount)
+		i.SalesTax = rates.SalesTax(i.BidAmount, i.BuyersPremium)
+	}
+
+	i.BidAmount = i.BidAmount.Round(costPlaces)
+	i.BuyersPremium = i.BuyersPremium.Round(costPlaces)
+	i.SalesTax = i.SalesTax.Round(costPlaces)
+	i.ShippingCost = i.ShippingCost.Round(costPlaces)
+
+	i.TotalCost = i.BidAmount.
+		Add(i.BuyersPremium).
+		Add(i.SalesTax).
+		Add(i.ShippingCost)
+	i.CostPerItem = i.TotalCost.DivRound(decimal.NewFromInt(int64(i.Quantity)), costPlaces)
+
+	return nil
+}
+
+// validateAmounts rejects negative cost components
+func (i *InventoryItem) validateAmounts() error {
+	amounts := []struct {
+		field string
+		value decimal.Decimal
+	}{
+		{"bid_amount", i.BidAmount},
+		{"buyers_premium", i.BuyersPremium},
+		{"sales_tax", i.SalesTax},
+		{"shipping_cost", i.ShippingCost},
+	}
+	for _, amount := range amounts {
+		if amount.value.IsNegative() {
+			return fmt.Errorf("%s cannot be negative", amount.field)
+		}
+	}
+	return nil
+}
+
+// ShippingAllocation is how shipping billed once per invoice is split across its items
+type ShippingAllocation string
+
+// Shipping allocation constants
+const (
+	ShippingProportional ShippingAllocation = "proportional" // by bid amount
+	ShippingEven         ShippingAllocation = "even"
+)
+
+// IsValid reports whether a is one of the known allocations
+func (a ShippingAllocation) IsValid() bool {
+	switch a {
+	case ShippingProportional, ShippingEven:
+		return true
+	}
+	return false
+}
+
+// AllocateShipping splits an invoice's shipping total across its items, replacing each item's
+// shipping cost and recalculating its costs. Proportional allocation weights items by bid and
+// falls back to even when every bid is zero; an empty allocation is proportional. Shares are
+// whole cents, with the cents left over by rounding down given to the items whose exact share
+// lost the most, so the shares always add up to the total.
+func AllocateShipping(items []InventoryItem, total decimal.Decimal, allocation ShippingAllocation) error {
+	if allocation == "" {
+		allocation = ShippingProportional
+	}
+	if !allocation.IsValid() {
+		return fmt.Errorf("unknown shipping allocation %q", allocation)
+	}
+	if total.IsNegative() {
+		return fmt.Errorf("shipping total cannot be negative")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	weights := make([]decimal.Decimal, len(items))
+	sum := decimal.Zero
+	if allocation == ShippingProportional {
+		for i := range items {
+			weights[i] = items[i].BidAmount
+			sum = sum.Add(weights[i])
+		}
+	}
+	if !sum.IsPositive() {
+		for i := range weights {
+			weights[i] = decimal.NewFromInt(1)
+		}
+		sum = decimal.NewFromInt(int64(len(items)))
+	}
+
+	cents := total.Round(costPlaces).Shift(costPlaces)
+	shares := make([]decimal.Decimal, len(items))
+	remainders := make([]decimal.Decimal, len(items))
+	left := cents
+	for i, weight := range weights {
+		// Exact division: the remainders share the denominator sum, so they compare exactly
+		shares[i], remainders[i] = cents.Mul(weight).QuoRem(sum, 0)
+		left = left.Sub(shares[i])
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].GreaterThan(remainders[order[b]])
+	})
+	for _, i := range order[:left.IntPart()] {
+		shares[i] = shares[i].Add(decimal.NewFromInt(1))
+	}
+
+	for i := range items {
+		items[i].ShippingCost = shares[i].Shift(-costPlaces)
+		if err := items[i].CalculateCosts(nil); err != nil {
+			return fmt.Errorf("item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
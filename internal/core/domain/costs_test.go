@@ -0,0 +1,127 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+func invoiceItems(bids ...string) []domain.InventoryItem {
+	items := make([]domain.InventoryItem, len(bids))
+	for i, bid := range bids {
+		items[i] = domain.InventoryItem{BidAmount: decimal.RequireFromString(bid), Quantity: 1}
+	}
+	return items
+}
+
+func shippingCosts(items []domain.InventoryItem) []string {
+	costs := make([]string, len(items))
+	for i, item := range items {
+		costs[i] = item.ShippingCost.StringFixed(2)
+	}
+	return costs
+}
+
+func TestAllocateShipping(t *testing.T) {
+	tests := []struct {
+		name       string
+		bids       []string
+		total      string
+		allocation domain.ShippingAllocation
+		want       []string
+	}{
+		{
+			name:       "proportional_by_bid",
+			bids:       []string{"100", "300", "600"},
+			total:      "50",
+			allocation: domain.ShippingProportional,
+			want:       []string{"5.00", "15.00", "30.00"},
+		},
+		{
+			name:       "proportional_gives_leftover_cents_to_largest_remainders",
+			bids:       []string{"10", "10", "20"},
+			total:      "10.01",
+			allocation: domain.ShippingProportional,
+			want:       []string{"2.50", "2.50", "5.01"},
+		},
+		{
+			name:       "empty_allocation_is_proportional",
+			bids:       []string{"25", "75"},
+			total:      "8",
+			allocation: "",
+			want:       []string{"2.00", "6.00"},
+		},
+		{
+			name:       "even",
+			bids:       []string{"100", "300", "600"},
+			total:      "30",
+			allocation: domain.ShippingEven,
+			want:       []string{"10.00", "10.00", "10.00"},
+		},
+		{
+			name:       "even_spreads_leftover_cents_from_the_first_item",
+			bids:       []string{"1", "2", "3"},
+			total:      "10",
+			allocation: domain.ShippingEven,
+			want:       []string{"3.34", "3.33", "3.33"},
+		},
+		{
+			name:       "proportional_without_bids_is_even",
+			bids:       []string{"0", "0"},
+			total:      "7.25",
+			allocation: domain.ShippingProportional,
+			want:       []string{"3.63", "3.62"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := invoiceItems(tt.bids...)
+			total := decimal.RequireFromString(tt.total)
+
+			require.NoError(t, domain.AllocateShipping(items, total, tt.allocation))
+			assert.Equal(t, tt.want, shippingCosts(items))
+
+			// The shares add back up to the invoice's shipping and are in each item's total
+			sum := decimal.Zero
+			for _, item := range items {
+				sum = sum.Add(item.ShippingCost)
+				assert.True(t, item.BidAmount.Add(item.ShippingCost).Equal(item.TotalCost))
+			}
+			assert.True(t, total.Equal(sum), "shares sum to %s, want %s", sum, total)
+		})
+	}
+}
+
+func TestAllocateShipping_SumsToTotal(t *testing.T) {
+	bids := []string{"12.50", "3.99", "0.01", "87.20", "45", "19.95", "7.77"}
+	for _, total := range []string{"0.01", "0.06", "9.99", "14.37", "123.45"} {
+		for _, allocation := range []domain.ShippingAllocation{domain.ShippingProportional, domain.ShippingEven} {
+			items := invoiceItems(bids...)
+			require.NoError(t, domain.AllocateShipping(items, decimal.RequireFromString(total), allocation))
+
+			sum := decimal.Zero
+			for _, item := range items {
+				assert.False(t, item.ShippingCost.IsNegative())
+				sum = sum.Add(item.ShippingCost)
+			}
+			assert.Equal(t, total, sum.StringFixed(2), "%s allocation of %s", allocation, total)
+		}
+	}
+}
+
+func TestAllocateShipping_Invalid(t *testing.T) {
+	items := invoiceItems("10")
+
+	err := domain.AllocateShipping(items, decimal.NewFromInt(5), "by_weight")
+	assert.EqualError(t, err, `unknown shipping allocation "by_weight"`)
+
+	err = domain.AllocateShipping(items, decimal.NewFromInt(-5), domain.ShippingEven)
+	assert.EqualError(t, err, "shipping total cannot be negative")
+
+	assert.True(t, items[0].ShippingCost.IsZero(), "items are left untouched")
+}
@@ -2,13 +2,22 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
+// ErrNotFound is returned, wrapped with the lot ID, when an inventory item doesn't exist or
+// has been deleted
+var ErrNotFound = errors.New("inventory item not found")
+
+// ErrVersionConflict is returned when an update's expected version no longer matches the stored item
+var ErrVersionConflict = errors.New("inventory item was modified by another request")
+
 // ItemCategory represents item categories
 type ItemCategory string
 
@@ -38,6 +47,25 @@ const (
 	CategoryOther        ItemCategory = "other"
 )
 
+// itemCategories lists the known categories, in the order of the database enum
+var itemCategories = []ItemCategory{
+	CategoryAntiques, CategoryArt, CategoryBooks, CategoryCeramics, CategoryChina,
+	CategoryClothing, CategoryCoins, CategoryCollectibles, CategoryElectronics,
+	CategoryFurniture, CategoryGlass, CategoryJewelry, CategoryLinens, CategoryMemorabilia,
+	CategoryMusical, CategoryPottery, CategorySilver, CategoryStamps, CategoryTools,
+	CategoryToys, CategoryVintage, CategoryOther,
+}
+
+// AllCategories returns the known categories, in the order of the database enum
+func AllCategories() []ItemCategory {
+	return slices.Clone(itemCategories)
+}
+
+// IsValid reports whether c is one of the known categories
+func (c ItemCategory) IsValid() bool {
+	return slices.Contains(itemCategories, c)
+}
+
 // ItemCondition represents item conditions
 type ItemCondition string
 
@@ -54,6 +82,22 @@ const (
 	ConditionUnknown     ItemCondition = "unknown"
 )
 
+// itemConditions lists the known conditions, best first
+var itemConditions = []ItemCondition{
+	ConditionMint, ConditionExcellent, ConditionVeryGood, ConditionGood, ConditionFair,
+	ConditionPoor, ConditionRestoration, ConditionParts, ConditionUnknown,
+}
+
+// AllConditions returns the known conditions, best first
+func AllConditions() []ItemCondition {
+	return slices.Clone(itemConditions)
+}
+
+// IsValid reports whether c is one of the known conditions
+func (c ItemCondition) IsValid() bool {
+	return slices.Contains(itemConditions, c)
+}
+
 // MarketDemandLevel represents market demand levels
 type MarketDemandLevel string
 
@@ -66,6 +110,19 @@ const (
 	DemandVeryLow  MarketDemandLevel = "very_low"
 )
 
+// marketDemandLevels lists the known demand levels, highest first
+var marketDemandLevels = []MarketDemandLevel{DemandVeryHigh, DemandHigh, DemandMedium, DemandLow, DemandVeryLow}
+
+// AllMarketDemandLevels returns the known demand levels, highest first
+func AllMarketDemandLevels() []MarketDemandLevel {
+	return slices.Clone(marketDemandLevels)
+}
+
+// IsValid reports whether d is one of the known demand levels
+func (d MarketDemandLevel) IsValid() bool {
+	return slices.Contains(marketDemandLevels, d)
+}
+
 // InventoryItem represents a single inventory item
 type InventoryItem struct {
 	LotID            uuid.UUID         `json:"lot_id"`
@@ -97,7 +154,10 @@ type InventoryItem struct {
 	Notes            string            `json:"notes,omitempty"`
 	CreatedAt        time.Time         `json:"created_at"`
 	UpdatedAt        time.Time         `json:"updated_at"`
+	CreatedBy        string            `json:"created_by,omitempty"` // User ID of the request that created the item
+	UpdatedBy        string            `json:"updated_by,omitempty"` // User ID of the request that last changed the item
 	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`
+	Version          int               `json:"version"` // Incremented on every update; 0 on an update skips the version check
 }
 
 // ListingStatus represents the status of an item listing
@@ -123,8 +183,8 @@ func (i *InventoryItem) Validate() error {
 	if i.Quantity <= 0 {
 		return fmt.Errorf("quantity must be positive")
 	}
-	if i.BidAmount.IsNegative() {
-		return fmt.Errorf("bid_amount cannot be negative")
+	if err := i.validateAmounts(); err != nil {
+		return err
 	}
 	if i.Category == "" {
 		i.Category = CategoryOther
@@ -138,28 +198,14 @@ func (i *InventoryItem) Validate() error {
 	return nil
 }
 
-// CalculateTotalCost calculates and sets the total cost fields
-func (i *InventoryItem) CalculateTotalCost() {
-	i.TotalCost = i.BidAmount.
-		Add(i.BuyersPremium).
-		Add(i.SalesTax).
-		Add(i.ShippingCost)
-
-	if i.Quantity > 0 {
-		i.CostPerItem = i.TotalCost.Div(decimal.NewFromInt(int64(i.Quantity)))
-	}
-}
-
-// PrepareForStorage prepares the item for database storage
+// PrepareForStorage prepares the item for database storage. Costs are calculated separately,
+// see CalculateCosts.
 func (i *InventoryItem) PrepareForStorage() {
 	// Ensure UUID is set
 	if i.LotID == uuid.Nil {
 		i.LotID = uuid.New()
 	}
 
-	// Calculate costs
-	i.CalculateTotalCost()
-
 	// Set timestamps if not set
 	now := time.Now()
 	if i.CreatedAt.IsZero() {
@@ -86,6 +86,18 @@ func TestInventoryItem_Validate(t *testing.T) {
 			wantError: true,
 			errorMsg:  "bid_amount cannot be negative",
 		},
+		{
+			name: "negative_buyers_premium",
+			item: &domain.InventoryItem{
+				InvoiceID:     "INV-001",
+				ItemName:      "Test Item",
+				Quantity:      1,
+				BidAmount:     decimal.NewFromFloat(50),
+				BuyersPremium: decimal.NewFromFloat(-9),
+			},
+			wantError: true,
+			errorMsg:  "buyers_premium cannot be negative",
+		},
 		{
 			name: "sets_default_category_when_empty",
 			item: &domain.InventoryItem{
@@ -148,7 +160,7 @@ func TestInventoryItem_Validate(t *testing.T) {
 	}
 }
 
-func TestInventoryItem_CalculateTotalCost(t *testing.T) {
+func TestInventoryItem_CalculateCosts(t *testing.T) {
 	tests := []struct {
 		name            string
 		bidAmount       decimal.Decimal
@@ -179,6 +191,46 @@ func TestInventoryItem_CalculateTotalCost(t *testing.T) {
 			expectedTotal:   decimal.NewFromFloat(276.36),
 			expectedPerItem: decimal.NewFromFloat(138.18),
 		},
+		{
+			name:            "quantity_four_is_a_quarter_each",
+			bidAmount:       decimal.NewFromFloat(400),
+			buyersPremium:   decimal.NewFromFloat(72),
+			salesTax:        decimal.NewFromFloat(40.71),
+			shippingCost:    decimal.NewFromFloat(12),
+			quantity:        4,
+			expectedTotal:   decimal.NewFromFloat(524.71),
+			expectedPerItem: decimal.NewFromFloat(131.18),
+		},
+		{
+			name:            "per_item_rounds_to_the_cent",
+			bidAmount:       decimal.NewFromFloat(100),
+			buyersPremium:   decimal.Zero,
+			salesTax:        decimal.Zero,
+			shippingCost:    decimal.Zero,
+			quantity:        3,
+			expectedTotal:   decimal.NewFromFloat(100),
+			expectedPerItem: decimal.NewFromFloat(33.33),
+		},
+		{
+			name:            "per_item_rounds_half_away_from_zero",
+			bidAmount:       decimal.NewFromFloat(0.05),
+			buyersPremium:   decimal.Zero,
+			salesTax:        decimal.Zero,
+			shippingCost:    decimal.Zero,
+			quantity:        2,
+			expectedTotal:   decimal.NewFromFloat(0.05),
+			expectedPerItem: decimal.NewFromFloat(0.03),
+		},
+		{
+			name:            "fractional_cents_are_rounded_before_summing",
+			bidAmount:       decimal.NewFromFloat(10.005),
+			buyersPremium:   decimal.NewFromFloat(1.005),
+			salesTax:        decimal.Zero,
+			shippingCost:    decimal.Zero,
+			quantity:        1,
+			expectedTotal:   decimal.NewFromFloat(11.02),
+			expectedPerItem: decimal.NewFromFloat(11.02),
+		},
 		{
 			name:            "zero_values",
 			bidAmount:       decimal.NewFromFloat(50),
@@ -211,7 +263,7 @@ func TestInventoryItem_CalculateTotalCost(t *testing.T) {
 				Quantity:      tt.quantity,
 			}
 
-			item.CalculateTotalCost()
+			require.NoError(t, item.CalculateCosts(nil))
 
 			assert.True(t, item.TotalCost.Equal(tt.expectedTotal),
 				"Expected total: %s, Got: %s", tt.expectedTotal, item.TotalCost)
@@ -221,6 +273,90 @@ func TestInventoryItem_CalculateTotalCost(t *testing.T) {
 	}
 }
 
+func TestInventoryItem_CalculateCosts_WithRates(t *testing.T) {
+	t.Run("charges_default_rates", func(t *testing.T) {
+		item := &domain.InventoryItem{BidAmount: decimal.NewFromFloat(100), Quantity: 1}
+
+		require.NoError(t, item.CalculateCosts(&domain.DefaultCostRates))
+
+		// 18% premium, then 8.625% tax on 118.00 = 10.1775
+		assert.True(t, decimal.NewFromFloat(18).Equal(item.BuyersPremium), "premium: %s", item.BuyersPremium)
+		assert.True(t, decimal.NewFromFloat(10.18).Equal(item.SalesTax), "tax: %s", item.SalesTax)
+		assert.True(t, decimal.NewFromFloat(128.18).Equal(item.TotalCost), "total: %s", item.TotalCost)
+		assert.True(t, item.TotalCost.Equal(item.CostPerItem))
+	})
+
+	t.Run("divides_multi_quantity_lots", func(t *testing.T) {
+		item := &domain.InventoryItem{
+			BidAmount:    decimal.NewFromFloat(250),
+			ShippingCost: decimal.NewFromFloat(20),
+			Quantity:     6,
+		}
+
+		require.NoError(t, item.CalculateCosts(&domain.DefaultCostRates))
+
+		// 250 + 45.00 premium + 25.44 tax + 20 shipping = 340.44, 56.74 each
+		assert.True(t, decimal.NewFromFloat(340.44).Equal(item.TotalCost), "total: %s", item.TotalCost)
+		assert.True(t, decimal.NewFromFloat(56.74).Equal(item.CostPerItem), "per item: %s", item.CostPerItem)
+	})
+
+	t.Run("replaces_stated_premium_and_tax", func(t *testing.T) {
+		item := &domain.InventoryItem{
+			BidAmount:     decimal.NewFromFloat(100),
+			BuyersPremium: decimal.NewFromFloat(99),
+			SalesTax:      decimal.NewFromFloat(99),
+			Quantity:      2,
+		}
+		rates := domain.CostRates{BuyersPremiumPercent: decimal.NewFromInt(10)}
+
+		require.NoError(t, item.CalculateCosts(&rates))
+
+		assert.True(t, decimal.NewFromFloat(10).Equal(item.BuyersPremium))
+		assert.True(t, item.SalesTax.IsZero())
+		assert.True(t, decimal.NewFromFloat(55).Equal(item.CostPerItem))
+	})
+}
+
+func TestInventoryItem_CalculateCosts_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    domain.InventoryItem
+		rates   *domain.CostRates
+		wantErr string
+	}{
+		{
+			name:    "zero_quantity",
+			item:    domain.InventoryItem{BidAmount: decimal.NewFromFloat(10)},
+			wantErr: "quantity must be positive",
+		},
+		{
+			name:    "negative_bid",
+			item:    domain.InventoryItem{BidAmount: decimal.NewFromFloat(-10), Quantity: 1},
+			wantErr: "bid_amount cannot be negative",
+		},
+		{
+			name:    "negative_shipping",
+			item:    domain.InventoryItem{ShippingCost: decimal.NewFromFloat(-5), Quantity: 1},
+			wantErr: "shipping_cost cannot be negative",
+		},
+		{
+			name:    "negative_rate",
+			item:    domain.InventoryItem{BidAmount: decimal.NewFromFloat(10), Quantity: 1},
+			rates:   &domain.CostRates{SalesTaxPercent: decimal.NewFromInt(-1)},
+			wantErr: "cost rates cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			err := item.CalculateCosts(tt.rates)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.True(t, item.TotalCost.IsZero(), "costs are left unset")
+		})
+	}
+}
+
 func TestInventoryItem_PrepareForStorage(t *testing.T) {
 	t.Run("generates_uuid_when_nil", func(t *testing.T) {
 		item := &domain.InventoryItem{
@@ -256,7 +392,7 @@ func TestInventoryItem_PrepareForStorage(t *testing.T) {
 		assert.WithinDuration(t, now, item.AcquisitionDate, time.Second)
 	})
 
-	t.Run("calculates_costs", func(t *testing.T) {
+	t.Run("leaves_costs_to_calculate_costs", func(t *testing.T) {
 		item := &domain.InventoryItem{
 			BidAmount:     decimal.NewFromFloat(100),
 			BuyersPremium: decimal.NewFromFloat(18),
@@ -266,9 +402,8 @@ func TestInventoryItem_PrepareForStorage(t *testing.T) {
 
 		item.PrepareForStorage()
 
-		expectedTotal := decimal.NewFromFloat(128.18)
-		assert.True(t, item.TotalCost.Equal(expectedTotal))
-		assert.True(t, item.CostPerItem.Equal(expectedTotal))
+		assert.True(t, item.TotalCost.IsZero())
+		assert.True(t, item.CostPerItem.IsZero())
 	})
 }
 
@@ -287,7 +422,7 @@ func BenchmarkInventoryItem_Validate(b *testing.B) {
 	}
 }
 
-func BenchmarkInventoryItem_CalculateTotalCost(b *testing.B) {
+func BenchmarkInventoryItem_CalculateCosts(b *testing.B) {
 	item := &domain.InventoryItem{
 		BidAmount:     decimal.NewFromFloat(100),
 		BuyersPremium: decimal.NewFromFloat(18),
@@ -298,6 +433,60 @@ func BenchmarkInventoryItem_CalculateTotalCost(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		item.CalculateTotalCost()
+		_ = item.CalculateCosts(nil)
 	}
 }
+
+func TestItemCategory_IsValid(t *testing.T) {
+	assert.True(t, domain.CategoryFurniture.IsValid())
+	assert.True(t, domain.CategoryOther.IsValid())
+	assert.False(t, domain.ItemCategory("spaceships").IsValid())
+	assert.False(t, domain.ItemCategory("").IsValid())
+}
+
+func TestItemCondition_IsValid(t *testing.T) {
+	assert.True(t, domain.ConditionVeryGood.IsValid())
+	assert.True(t, domain.ConditionUnknown.IsValid())
+	assert.False(t, domain.ItemCondition("pristine").IsValid())
+	assert.False(t, domain.ItemCondition("").IsValid())
+}
+
+func TestMarketDemandLevel_IsValid(t *testing.T) {
+	assert.True(t, domain.DemandVeryHigh.IsValid())
+	assert.True(t, domain.DemandVeryLow.IsValid())
+	assert.False(t, domain.MarketDemandLevel("extreme").IsValid())
+	assert.False(t, domain.MarketDemandLevel("").IsValid())
+}
+
+func TestAllEnumValues_AreValid(t *testing.T) {
+	categories := domain.AllCategories()
+	assert.Len(t, categories, 22)
+	assert.Equal(t, domain.CategoryAntiques, categories[0])
+	assert.Equal(t, domain.CategoryOther, categories[len(categories)-1])
+	for _, c := range categories {
+		assert.True(t, c.IsValid(), "category %q", c)
+	}
+
+	conditions := domain.AllConditions()
+	assert.Len(t, conditions, 9)
+	assert.Equal(t, domain.ConditionMint, conditions[0])
+	for _, c := range conditions {
+		assert.True(t, c.IsValid(), "condition %q", c)
+	}
+
+	demand := domain.AllMarketDemandLevels()
+	assert.Equal(t, []domain.MarketDemandLevel{
+		domain.DemandVeryHigh, domain.DemandHigh, domain.DemandMedium, domain.DemandLow, domain.DemandVeryLow,
+	}, demand)
+	for _, d := range demand {
+		assert.True(t, d.IsValid(), "market demand %q", d)
+	}
+}
+
+func TestAllCategories_ReturnsACopy(t *testing.T) {
+	categories := domain.AllCategories()
+	categories[0] = "spaceships"
+
+	assert.Equal(t, domain.CategoryAntiques, domain.AllCategories()[0])
+	assert.False(t, domain.ItemCategory("spaceships").IsValid())
+}
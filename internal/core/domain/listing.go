@@ -0,0 +1,110 @@
+// internal/core/domain/listing.go
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Platform identifies a marketplace an item can be listed on
+type Platform string
+
+// Platform constants
+const (
+	PlatformEbay       Platform = "ebay"
+	PlatformEtsy       Platform = "etsy"
+	PlatformFacebook   Platform = "facebook"
+	PlatformChairish   Platform = "chairish"
+	PlatformWorthpoint Platform = "worthpoint"
+	PlatformLocal      Platform = "local"
+	PlatformOther      Platform = "other"
+)
+
+// IsValid reports whether p is one of the known platforms
+func (p Platform) IsValid() bool {
+	switch p {
+	case PlatformEbay, PlatformEtsy, PlatformFacebook, PlatformChairish,
+		PlatformWorthpoint, PlatformLocal, PlatformOther:
+		return true
+	}
+	return false
+}
+
+// EbayTitleMaxLength is the longest listing title eBay accepts
+const EbayTitleMaxLength = 80
+
+// PlatformListing links an inventory item to a listing on an external platform
+type PlatformListing struct {
+	ID          uuid.UUID       `json:"id"`
+	LotID       uuid.UUID       `json:"lot_id"`
+	Platform    Platform        `json:"platform"`
+	Status      ListingStatus   `json:"status"`
+	ListPrice   decimal.Decimal `json:"list_price"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	CategoryID  string          `json:"category_id,omitempty"`
+	ListingURL  string          `json:"listing_url,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+
+	// Sale details, set once the item sells on this platform
+	SoldPrice       *decimal.Decimal `json:"sold_price,omitempty"`
+	SoldAt          *time.Time       `json:"sold_at,omitempty"`
+	ShippingCharged decimal.Decimal  `json:"shipping_charged"`
+}
+
+// Validate performs domain validation on the listing
+func (l *PlatformListing) Validate() error {
+	if l.LotID == uuid.Nil {
+		return fmt.Errorf("lot_id is required")
+	}
+	if !l.Platform.IsValid() {
+		return fmt.Errorf("unknown platform %q", l.Platform)
+	}
+	if l.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if l.Platform == PlatformEbay && len([]rune(l.Title)) > EbayTitleMaxLength {
+		return fmt.Errorf("title cannot exceed %d characters on eBay", EbayTitleMaxLength)
+	}
+	if !l.ListPrice.IsPositive() {
+		return fmt.Errorf("list_price must be positive")
+	}
+	if l.Status == "" {
+		l.Status = StatusDraft
+	}
+	return nil
+}
+
+// Sale records an item selling on a platform. Profit and ROI are derived from
+// it by the database when the export view is refreshed.
+type Sale struct {
+	LotID           uuid.UUID
+	Platform        Platform
+	SalePrice       decimal.Decimal
+	ShippingCharged decimal.Decimal
+	SoldAt          time.Time
+}
+
+// Validate performs domain validation on the sale
+func (s *Sale) Validate() error {
+	if s.LotID == uuid.Nil {
+		return fmt.Errorf("lot_id is required")
+	}
+	if !s.Platform.IsValid() {
+		return fmt.Errorf("unknown platform %q", s.Platform)
+	}
+	if s.SalePrice.IsNegative() {
+		return fmt.Errorf("sale_price cannot be negative")
+	}
+	if s.ShippingCharged.IsNegative() {
+		return fmt.Errorf("shipping_charged cannot be negative")
+	}
+	if s.SoldAt.IsZero() {
+		return fmt.Errorf("sold_at is required")
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+func TestPlatformListing_Validate(t *testing.T) {
+	valid := func(mods ...func(*domain.PlatformListing)) *domain.PlatformListing {
+		l := &domain.PlatformListing{
+			LotID:     uuid.New(),
+			Platform:  domain.PlatformEbay,
+			ListPrice: decimal.NewFromInt(50),
+			Title:     "Victorian Tea Set",
+		}
+		for _, mod := range mods {
+			mod(l)
+		}
+		return l
+	}
+
+	tests := []struct {
+		name      string
+		listing   *domain.PlatformListing
+		wantError bool
+		errorMsg  string
+	}{
+		{name: "valid_listing", listing: valid()},
+		{
+			name:      "missing_lot_id",
+			listing:   valid(func(l *domain.PlatformListing) { l.LotID = uuid.Nil }),
+			wantError: true,
+			errorMsg:  "lot_id is required",
+		},
+		{
+			name:      "unknown_platform",
+			listing:   valid(func(l *domain.PlatformListing) { l.Platform = "craigslist" }),
+			wantError: true,
+			errorMsg:  "unknown platform",
+		},
+		{
+			name:      "missing_title",
+			listing:   valid(func(l *domain.PlatformListing) { l.Title = "" }),
+			wantError: true,
+			errorMsg:  "title is required",
+		},
+		{
+			name:      "ebay_title_too_long",
+			listing:   valid(func(l *domain.PlatformListing) { l.Title = strings.Repeat("a", 81) }),
+			wantError: true,
+			errorMsg:  "title cannot exceed 80 characters",
+		},
+		{
+			name: "long_title_allowed_off_ebay",
+			listing: valid(func(l *domain.PlatformListing) {
+				l.Platform = domain.PlatformEtsy
+				l.Title = strings.Repeat("a", 81)
+			}),
+		},
+		{
+			name:      "zero_price",
+			listing:   valid(func(l *domain.PlatformListing) { l.ListPrice = decimal.Zero }),
+			wantError: true,
+			errorMsg:  "list_price must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.listing.Validate()
+			if tt.wantError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.StatusDraft, tt.listing.Status)
+		})
+	}
+}
+
+func TestSale_Validate(t *testing.T) {
+	valid := func(mods ...func(*domain.Sale)) *domain.Sale {
+		s := &domain.Sale{
+			LotID:     uuid.New(),
+			Platform:  domain.PlatformEbay,
+			SalePrice: decimal.NewFromInt(250),
+			SoldAt:    time.Now(),
+		}
+		for _, mod := range mods {
+			mod(s)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		sale      *domain.Sale
+		wantError bool
+		errorMsg  string
+	}{
+		{name: "valid_sale", sale: valid()},
+		{name: "zero_price_allowed", sale: valid(func(s *domain.Sale) { s.SalePrice = decimal.Zero })},
+		{
+			name:      "negative_price",
+			sale:      valid(func(s *domain.Sale) { s.SalePrice = decimal.NewFromInt(-5) }),
+			wantError: true,
+			errorMsg:  "sale_price cannot be negative",
+		},
+		{
+			name:      "negative_shipping",
+			sale:      valid(func(s *domain.Sale) { s.ShippingCharged = decimal.NewFromInt(-5) }),
+			wantError: true,
+			errorMsg:  "shipping_charged cannot be negative",
+		},
+		{
+			name:      "unknown_platform",
+			sale:      valid(func(s *domain.Sale) { s.Platform = "craigslist" }),
+			wantError: true,
+			errorMsg:  "unknown platform",
+		},
+		{
+			name:      "missing_sold_at",
+			sale:      valid(func(s *domain.Sale) { s.SoldAt = time.Time{} }),
+			wantError: true,
+			errorMsg:  "sold_at is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sale.Validate()
+			if tt.wantError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// internal/core/domain/qrcode.go
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// QRLotIDPlaceholder is replaced with the item's lot ID in a QRTemplate
+const QRLotIDPlaceholder = "{lot_id}"
+
+// maxQRCodeLength is the size of the qr_code column
+const maxQRCodeLength = 100
+
+// QRTemplate is the content encoded in an item's QR code, such as a deep link like
+// https://resell.example.com/inventory/{lot_id}
+type QRTemplate string
+
+// DefaultQRTemplate encodes the bare lot ID
+const DefaultQRTemplate QRTemplate = QRLotIDPlaceholder
+
+// Validate requires the lot ID placeholder, and that the payload fits the qr_code column
+func (t QRTemplate) Validate() error {
+	if !strings.Contains(string(t), QRLotIDPlaceholder) {
+		return fmt.Errorf("qr template must contain %s", QRLotIDPlaceholder)
+	}
+	if n := len(t.Payload(uuid.Nil)); n > maxQRCodeLength {
+		return fmt.Errorf("qr template payload is %d characters, more than %d", n, maxQRCodeLength)
+	}
+	return nil
+}
+
+// Payload returns the content encoded for the lot
+func (t QRTemplate) Payload(lotID uuid.UUID) string {
+	return strings.ReplaceAll(string(t), QRLotIDPlaceholder, lotID.String())
+}
+
+// QRPayload returns the content of the item's QR code: the stored payload, so printed labels
+// keep working when the template changes, or else the template's
+func (i *InventoryItem) QRPayload(template QRTemplate) string {
+	if i.QRCode != "" {
+		return i.QRCode
+	}
+	return template.Payload(i.LotID)
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+func TestQRTemplate(t *testing.T) {
+	lotID := uuid.MustParse("8f14e45f-ceea-467f-a0f6-2b3c1a9e6d0b")
+
+	tests := []struct {
+		name     string
+		template domain.QRTemplate
+		want     string
+		wantErr  string
+	}{
+		{name: "lot_id", template: domain.DefaultQRTemplate, want: lotID.String()},
+		{
+			name:     "deep_link",
+			template: "https://resell.example.com/inventory/{lot_id}?src=qr",
+			want:     "https://resell.example.com/inventory/8f14e45f-ceea-467f-a0f6-2b3c1a9e6d0b?src=qr",
+		},
+		{name: "missing_placeholder", template: "https://resell.example.com/inventory", wantErr: "must contain {lot_id}"},
+		{
+			name:     "too_long",
+			template: domain.QRTemplate("https://resell.example.com/" + strings.Repeat("x", 40) + "/{lot_id}"),
+			wantErr:  "more than 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.template.Payload(lotID))
+		})
+	}
+}
+
+func TestInventoryItem_QRPayload(t *testing.T) {
+	item := domain.InventoryItem{LotID: uuid.New()}
+	template := domain.QRTemplate("https://resell.example.com/i/{lot_id}")
+
+	assert.Equal(t, template.Payload(item.LotID), item.QRPayload(template))
+
+	// A stored payload wins over the current template
+	item.QRCode = "https://old.example.com/i/" + item.LotID.String()
+	assert.Equal(t, item.QRCode, item.QRPayload(template))
+}
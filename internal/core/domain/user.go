@@ -0,0 +1,10 @@
+// internal/core/domain/user.go
+package domain
+
+// User is an API user. Only what authentication needs is loaded.
+type User struct {
+	ID           string `json:"id"`
+	PasswordHash string `json:"-"`
+	Role         string `json:"role"`
+	IsActive     bool   `json:"is_active"`
+}
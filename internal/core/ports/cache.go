@@ -18,8 +18,10 @@ type CacheRepository interface {
 	Expire(ctx context.Context, key string, ttl time.Duration) error
 
 	// Advanced operations
-	GetOrSet(ctx context.Context, key string, dest interface{},
-		fetch func() (interface{}, error), ttl time.Duration) error
+	// GetOrSet returns the bytes cached under key, running loader and caching its result on a
+	// miss. Concurrent misses for the same key share a single loader call.
+	GetOrSet(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error),
+		opts ...GetOrSetOption) ([]byte, error)
 
 	// Counter operations
 	Increment(ctx context.Context, key string) (int64, error)
@@ -27,9 +29,42 @@ type CacheRepository interface {
 
 	// Conditional operations
 	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	CompareAndDelete(ctx context.Context, key string, value interface{}) (bool, error)
 
 	// Utility operations
 	TTL(ctx context.Context, key string) (time.Duration, error)
 	Flush(ctx context.Context) error
 	Ping(ctx context.Context) error
 }
+
+// GetOrSetOptions configures a GetOrSet call
+type GetOrSetOptions struct {
+	// LockTTL, when positive, guards a miss with a distributed lock held for at most LockTTL,
+	// so only one caller across all instances runs the loader
+	LockTTL time.Duration
+	// LockWait is how long callers that lose the lock wait for the winner's value before
+	// running the loader themselves
+	LockWait time.Duration
+	// StaleTTL keeps the previous value for this long past its expiry; callers that lose the
+	// lock are served it instead of waiting
+	StaleTTL time.Duration
+}
+
+// GetOrSetOption sets a GetOrSet option
+type GetOrSetOption func(*GetOrSetOptions)
+
+// WithLock makes a miss take a distributed lock for up to lockTTL, with other callers waiting
+// up to wait for the value
+func WithLock(lockTTL, wait time.Duration) GetOrSetOption {
+	return func(o *GetOrSetOptions) {
+		o.LockTTL = lockTTL
+		o.LockWait = wait
+	}
+}
+
+// WithStale keeps expired values for staleTTL and serves them while the lock holder reloads
+func WithStale(staleTTL time.Duration) GetOrSetOption {
+	return func(o *GetOrSetOptions) {
+		o.StaleTTL = staleTTL
+	}
+}
@@ -0,0 +1,23 @@
+// internal/core/ports/category_mapping.go
+package ports
+
+import (
+	"context"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+// CategoryMappingRepository defines the persistence port for marketplace category mappings
+type CategoryMappingRepository interface {
+	FindAllEbay(ctx context.Context) (map[domain.ItemCategory]string, error)
+	UpsertEbay(ctx context.Context, category domain.ItemCategory, ebayCategoryID string) error
+}
+
+// CategoryMapper resolves inventory categories to marketplace category IDs
+type CategoryMapper interface {
+	// MapToEbay returns the eBay category ID for category. When the category has no
+	// mapping it returns the "other" mapping and false.
+	MapToEbay(category domain.ItemCategory) (string, bool)
+	EbayMappings() map[domain.ItemCategory]string
+	SetEbayMapping(ctx context.Context, category domain.ItemCategory, ebayCategoryID string) error
+}
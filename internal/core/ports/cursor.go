@@ -0,0 +1,43 @@
+// internal/core/ports/cursor.go
+package ports
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EncodeListCursor builds the opaque next_cursor token for keyset pagination
+// from the last item of a page
+func EncodeListCursor(createdAt time.Time, lotID uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + lotID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeListCursor parses a token produced by EncodeListCursor
+func DecodeListCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	ts, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor format")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+
+	lotID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor lot ID: %w", err)
+	}
+
+	return createdAt, lotID, nil
+}
@@ -0,0 +1,83 @@
+// internal/core/ports/dashboard.go
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+// DashboardFilters narrow the dashboard aggregates to items acquired in [From, To). Nil bounds
+// are open.
+type DashboardFilters struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// CategoryBreakdown sums the active items of one category. AvgROI averages the return on the
+// category's sold items: sale price less platform fees and total cost, as a percent of total cost.
+type CategoryBreakdown struct {
+	Category       string          `json:"category"`
+	Count          int             `json:"count"`
+	TotalCost      decimal.Decimal `json:"total_cost"`
+	EstimatedValue decimal.Decimal `json:"estimated_value"`
+	SoldCount      int             `json:"sold_count"`
+	AvgROI         decimal.Decimal `json:"avg_roi"`
+}
+
+// AgingParams select the unsold active items acquired more than MinDays ago, at most Limit of them
+type AgingParams struct {
+	MinDays int
+	Limit   int
+}
+
+// AgingItem is an unsold item with the whole days since it was acquired
+type AgingItem struct {
+	LotID           uuid.UUID           `json:"lot_id"`
+	ItemName        string              `json:"item_name"`
+	Category        domain.ItemCategory `json:"category"`
+	StorageLocation string              `json:"storage_location,omitempty"`
+	AcquisitionDate time.Time           `json:"acquisition_date"`
+	DaysHeld        int                 `json:"days_held"`
+	TotalCost       decimal.Decimal     `json:"total_cost"`
+	EstimatedValue  *decimal.Decimal    `json:"estimated_value,omitempty"`
+}
+
+// VarianceParams select the active items whose estimated value differs from their total cost by
+// more than MinPercent of the cost, at most Limit of them
+type VarianceParams struct {
+	MinPercent decimal.Decimal
+	Limit      int
+}
+
+// VarianceItem is an item whose estimated value strays from its total cost. Variance is
+// estimated value less total cost, negative when the item is valued below what it cost.
+type VarianceItem struct {
+	LotID           uuid.UUID           `json:"lot_id"`
+	ItemName        string              `json:"item_name"`
+	Category        domain.ItemCategory `json:"category"`
+	TotalCost       decimal.Decimal     `json:"total_cost"`
+	EstimatedValue  decimal.Decimal     `json:"estimated_value"`
+	Variance        decimal.Decimal     `json:"variance"`
+	VariancePercent decimal.Decimal     `json:"variance_percent"`
+}
+
+// DashboardRepository defines the persistence port for dashboard aggregates
+type DashboardRepository interface {
+	// CategoryBreakdown groups the active items by category, largest total cost first.
+	// Categories without matching items are omitted.
+	CategoryBreakdown(ctx context.Context, filters DashboardFilters) ([]CategoryBreakdown, error)
+
+	// AgingInventory lists the active items without a sold listing that were acquired more
+	// than params.MinDays ago, oldest first and then by total cost, largest first
+	AgingInventory(ctx context.Context, params AgingParams) ([]AgingItem, error)
+
+	// ValueVariance lists the active items valued more than params.MinPercent above or below
+	// their total cost, largest relative variance first. Items without an estimated value or
+	// with no cost are skipped.
+	ValueVariance(ctx context.Context, params VarianceParams) ([]VarianceItem, error)
+}
@@ -0,0 +1,167 @@
+// internal/core/ports/inventory_patch.go
+package ports
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+// Optional is one field of a sparse update. Set reports whether the field was present, and
+// Value is nil when it was present but null.
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// Some returns a field set to v
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Set: true, Value: &v}
+}
+
+// Null returns a field set to null
+func Null[T any]() Optional[T] {
+	return Optional[T]{Set: true}
+}
+
+// IsNull reports whether the field was present and null
+func (o Optional[T]) IsNull() bool {
+	return o.Set && o.Value == nil
+}
+
+// UnmarshalJSON marks the field as set; it is only called for keys present in the body
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	o.Value = nil
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Value = &v
+	return nil
+}
+
+// InventoryPatch holds the fields of a partial item update. Unset fields are left untouched and
+// null clears a field. Version, when set, must match the stored item.
+type InventoryPatch struct {
+	InvoiceID        Optional[string]                   `json:"invoice_id"`
+	AuctionID        Optional[int]                      `json:"auction_id"`
+	ItemName         Optional[string]                   `json:"item_name"`
+	Description      Optional[string]                   `json:"description"`
+	Category         Optional[domain.ItemCategory]      `json:"category"`
+	Subcategory      Optional[string]                   `json:"subcategory"`
+	Condition        Optional[domain.ItemCondition]     `json:"condition"`
+	Quantity         Optional[int]                      `json:"quantity"`
+	BidAmount        Optional[decimal.Decimal]          `json:"bid_amount"`
+	BuyersPremium    Optional[decimal.Decimal]          `json:"buyers_premium"`
+	SalesTax         Optional[decimal.Decimal]          `json:"sales_tax"`
+	ShippingCost     Optional[decimal.Decimal]          `json:"shipping_cost"`
+	AcquisitionDate  Optional[time.Time]                `json:"acquisition_date"`
+	StorageLocation  Optional[string]                   `json:"storage_location"`
+	StorageBin       Optional[string]                   `json:"storage_bin"`
+	EstimatedValue   Optional[decimal.Decimal]          `json:"estimated_value"`
+	MarketDemand     Optional[domain.MarketDemandLevel] `json:"market_demand"`
+	SeasonalityNotes Optional[string]                   `json:"seasonality_notes"`
+	NeedsRepair      Optional[bool]                     `json:"needs_repair"`
+	IsConsignment    Optional[bool]                     `json:"is_consignment"`
+	IsReturned       Optional[bool]                     `json:"is_returned"`
+	Keywords         Optional[[]string]                 `json:"keywords"`
+	Notes            Optional[string]                   `json:"notes"`
+
+	Version   int    `json:"version,omitempty"`
+	UpdatedBy string `json:"-"`
+}
+
+// NullRequiredField returns the JSON name of the first field set to null that the inventory
+// table requires, or "" when there is none
+func (p InventoryPatch) NullRequiredField() string {
+	required := []struct {
+		name string
+		null bool
+	}{
+		{"invoice_id", p.InvoiceID.IsNull()},
+		{"item_name", p.ItemName.IsNull()},
+		{"category", p.Category.IsNull()},
+		{"condition", p.Condition.IsNull()},
+		{"quantity", p.Quantity.IsNull()},
+		{"bid_amount", p.BidAmount.IsNull()},
+		{"buyers_premium", p.BuyersPremium.IsNull()},
+		{"sales_tax", p.SalesTax.IsNull()},
+		{"shipping_cost", p.ShippingCost.IsNull()},
+		{"acquisition_date", p.AcquisitionDate.IsNull()},
+		{"market_demand", p.MarketDemand.IsNull()},
+		{"needs_repair", p.NeedsRepair.IsNull()},
+		{"is_consignment", p.IsConsignment.IsNull()},
+		{"is_returned", p.IsReturned.IsNull()},
+	}
+	for _, f := range required {
+		if f.null {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// IsEmpty reports whether no fields are set
+func (p InventoryPatch) IsEmpty() bool {
+	return !p.InvoiceID.Set && !p.AuctionID.Set && !p.ItemName.Set && !p.Description.Set &&
+		!p.Category.Set && !p.Subcategory.Set && !p.Condition.Set && !p.Quantity.Set &&
+		!p.BidAmount.Set && !p.BuyersPremium.Set && !p.SalesTax.Set && !p.ShippingCost.Set &&
+		!p.AcquisitionDate.Set && !p.StorageLocation.Set && !p.StorageBin.Set &&
+		!p.EstimatedValue.Set && !p.MarketDemand.Set && !p.SeasonalityNotes.Set &&
+		!p.NeedsRepair.Set && !p.IsConsignment.Set && !p.IsReturned.Set &&
+		!p.Keywords.Set && !p.Notes.Set
+}
+
+// Apply writes the set fields onto item, with null clearing a field to its zero value
+func (p InventoryPatch) Apply(item *domain.InventoryItem) {
+	applyOptional(&item.InvoiceID, p.InvoiceID)
+	applyOptional(&item.AuctionID, p.AuctionID)
+	applyOptional(&item.ItemName, p.ItemName)
+	applyOptional(&item.Description, p.Description)
+	applyOptional(&item.Category, p.Category)
+	applyOptional(&item.Subcategory, p.Subcategory)
+	applyOptional(&item.Condition, p.Condition)
+	applyOptional(&item.Quantity, p.Quantity)
+	applyOptional(&item.BidAmount, p.BidAmount)
+	applyOptional(&item.BuyersPremium, p.BuyersPremium)
+	applyOptional(&item.SalesTax, p.SalesTax)
+	applyOptional(&item.ShippingCost, p.ShippingCost)
+	applyOptional(&item.AcquisitionDate, p.AcquisitionDate)
+	applyOptional(&item.StorageLocation, p.StorageLocation)
+	applyOptional(&item.StorageBin, p.StorageBin)
+	if p.EstimatedValue.Set {
+		item.EstimatedValue = p.EstimatedValue.Value
+	}
+	applyOptional(&item.MarketDemand, p.MarketDemand)
+	applyOptional(&item.SeasonalityNotes, p.SeasonalityNotes)
+	applyOptional(&item.NeedsRepair, p.NeedsRepair)
+	applyOptional(&item.IsConsignment, p.IsConsignment)
+	applyOptional(&item.IsReturned, p.IsReturned)
+	applyOptional(&item.Keywords, p.Keywords)
+	applyOptional(&item.Notes, p.Notes)
+	if p.Version > 0 {
+		item.Version = p.Version
+	}
+}
+
+// applyOptional copies a set field into dst, zeroing dst when the field is null
+func applyOptional[T any](dst *T, field Optional[T]) {
+	if !field.Set {
+		return
+	}
+	if field.Value == nil {
+		var zero T
+		*dst = zero
+		return
+	}
+	*dst = *field.Value
+}
@@ -15,16 +15,41 @@ type InventoryRepository interface {
 	// Basic CRUD operations
 	Save(ctx context.Context, item *domain.InventoryItem) error
 	SaveBatch(ctx context.Context, items []domain.InventoryItem) error
+	SaveBatchUpsert(ctx context.Context, items []domain.InventoryItem, target ConflictTarget) error
 	Update(ctx context.Context, item *domain.InventoryItem) error
+	UpdatePartial(ctx context.Context, lotID uuid.UUID, patch InventoryPatch) (*domain.InventoryItem, error)
 	Delete(ctx context.Context, lotID uuid.UUID) error
 	SoftDelete(ctx context.Context, lotID uuid.UUID) error
+	SoftDeleteByInvoiceID(ctx context.Context, invoiceID string) (int64, error)
+	Restore(ctx context.Context, lotID uuid.UUID) error
+	BulkUpdateFields(ctx context.Context, lotIDs []uuid.UUID, updates BulkFieldUpdates) (int64, error)
+	FillQRCodes(ctx context.Context, template domain.QRTemplate) (int64, error)
 
 	// Query operations
 	FindByID(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error)
 	FindByInvoiceID(ctx context.Context, invoiceID string) ([]domain.InventoryItem, error)
 	FindAll(ctx context.Context, params ListParams) ([]*domain.InventoryItem, int64, error)
+	SumTotals(ctx context.Context, params ListParams) (*ListTotals, error)
+	SearchHighlights(ctx context.Context, search string, lotIDs []uuid.UUID) (map[uuid.UUID]string, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]string, error)
+	FindHistory(ctx context.Context, lotID uuid.UUID) ([]domain.AuditEntry, error)
+	ListInvoices(ctx context.Context, params InvoiceListParams) ([]InvoiceSummary, int64, error)
+	StorageSummary(ctx context.Context) ([]StorageBinSummary, error)
+	TimeSeries(ctx context.Context, params TimeSeriesParams) ([]TimeSeriesBucket, error)
 
 	// Utility operations
 	Count(ctx context.Context) (int64, error)
 	Exists(ctx context.Context, lotID uuid.UUID) (bool, error)
+	HasInvoice(ctx context.Context, invoiceID string) (bool, error)
 }
+
+// ConflictTarget selects which existing row an upserted item replaces
+type ConflictTarget string
+
+const (
+	// ConflictOnLotID updates the row with the same lot_id
+	ConflictOnLotID ConflictTarget = "lot_id"
+	// ConflictOnInvoiceItem updates active rows with the same invoice_id and item_name,
+	// so re-importing an invoice updates its items instead of duplicating them
+	ConflictOnInvoiceItem ConflictTarget = "invoice_item"
+)
@@ -3,9 +3,11 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/ammerola/resell-be/internal/core/domain"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // InventoryService defines the application service port for inventory.
@@ -13,12 +15,192 @@ import (
 type InventoryService interface {
 	SaveItem(ctx context.Context, item *domain.InventoryItem) error
 	SaveItems(ctx context.Context, items []domain.InventoryItem) error
-	BulkUpsert(ctx context.Context, items []domain.InventoryItem) error
+	SaveItemsPartial(ctx context.Context, items []domain.InventoryItem) (SaveReport, error)
+	BulkUpsert(ctx context.Context, items []domain.InventoryItem, target ConflictTarget) error
 	GetByID(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error)
+	HasInvoice(ctx context.Context, invoiceID string) (bool, error)
+	GetInvoiceItems(ctx context.Context, invoiceID string) (*InvoiceItems, error)
+	ListInvoices(ctx context.Context, params InvoiceListParams) (*InvoiceListResult, error)
+	StorageSummary(ctx context.Context) (*StorageSummary, error)
+	TimeSeries(ctx context.Context, params TimeSeriesParams) (*TimeSeries, error)
 	UpdateItem(ctx context.Context, lotID uuid.UUID, item *domain.InventoryItem) error
+	PatchItem(ctx context.Context, lotID uuid.UUID, patch InventoryPatch) (*domain.InventoryItem, error)
 	DeleteItem(ctx context.Context, lotID uuid.UUID, permanent bool) error
+	DeleteInvoiceItems(ctx context.Context, invoiceID string) (int64, error)
+	RestoreItem(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error)
+	GetHistory(ctx context.Context, lotID uuid.UUID) ([]domain.AuditEntry, error)
+	BulkUpdateFields(ctx context.Context, lotIDs []uuid.UUID, updates BulkFieldUpdates) (int64, error)
+	GenerateQRCodes(ctx context.Context, template domain.QRTemplate) (int64, error)
+	Reclassify(ctx context.Context, params ReclassifyParams) (*ReclassifyResult, error)
 	// Note: We need to define ListParams and ListResult here to avoid circular dependencies.
 	List(ctx context.Context, params ListParams) (*ListResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]string, error)
+}
+
+// InvoiceItems are the active items of one invoice with invoice-level aggregates. An invoice
+// with no items has an empty Items and zero aggregates.
+type InvoiceItems struct {
+	InvoiceID  string                 `json:"invoice_id"`
+	Items      []domain.InventoryItem `json:"items"`
+	ItemCount  int                    `json:"item_count"`
+	TotalCost  decimal.Decimal        `json:"total_cost"`
+	AverageBid decimal.Decimal        `json:"average_bid"`
+}
+
+// Invoice list sort fields
+const (
+	InvoiceSortDate  = "date"  // acquisition date, the default
+	InvoiceSortTotal = "total" // summed total cost
+)
+
+// InvoiceListParams page and order the invoice listing
+type InvoiceListParams struct {
+	Page      int
+	PageSize  int
+	SortBy    string // InvoiceSortDate or InvoiceSortTotal
+	SortOrder string // asc or desc (default)
+}
+
+// InvoiceSummary is one invoice with the count and summed cost of its active items
+type InvoiceSummary struct {
+	InvoiceID       string          `json:"invoice_id"`
+	AuctionID       int             `json:"auction_id"`
+	AcquisitionDate time.Time       `json:"acquisition_date"` // earliest of its items
+	ItemCount       int             `json:"item_count"`
+	TotalCost       decimal.Decimal `json:"total_cost"`
+}
+
+// InvoiceListResult is a page of invoice summaries
+type InvoiceListResult struct {
+	Invoices   []InvoiceSummary `json:"invoices"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalCount int64            `json:"total_count"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// UnassignedStorage is the storage summary bucket for items with an empty storage location,
+// and for items in a location with an empty bin
+const UnassignedStorage = "unassigned"
+
+// StorageBinSummary counts the active items in one storage bin
+type StorageBinSummary struct {
+	Location       string          `json:"location"`
+	Bin            string          `json:"bin"`
+	ItemCount      int             `json:"item_count"`
+	EstimatedValue decimal.Decimal `json:"estimated_value"`
+}
+
+// StorageLocationSummary counts the active items in one storage location, bin by bin
+type StorageLocationSummary struct {
+	Location       string              `json:"location"`
+	ItemCount      int                 `json:"item_count"`
+	EstimatedValue decimal.Decimal     `json:"estimated_value"`
+	Bins           []StorageBinSummary `json:"bins"`
+}
+
+// StorageSummary counts the active items and their estimated value per storage location and bin
+type StorageSummary struct {
+	Locations      []StorageLocationSummary `json:"locations"`
+	ItemCount      int                      `json:"item_count"`
+	EstimatedValue decimal.Decimal          `json:"estimated_value"`
+}
+
+// Time series intervals, named after the Postgres date_trunc fields that bucket them
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week" // ISO weeks, starting Monday
+	IntervalMonth = "month"
+)
+
+// MaxTimeSeriesBuckets caps the length of a time series
+const MaxTimeSeriesBuckets = 1000
+
+// TimeSeriesParams select the buckets of a time series: every interval from the one containing
+// From up to, but not including, To. Buckets are in UTC.
+type TimeSeriesParams struct {
+	Interval string
+	From     time.Time
+	To       time.Time
+}
+
+// TimeSeriesBucket totals the acquisitions and sales of the interval starting at Start
+type TimeSeriesBucket struct {
+	Start        time.Time
+	Acquisitions int
+	Spend        decimal.Decimal
+	ItemsSold    int
+	NetProfit    decimal.Decimal
+}
+
+// TimeSeries holds one entry per bucket, empty buckets included, in parallel arrays for charting.
+// Net profit is the sale price less platform fees and the item's total cost.
+type TimeSeries struct {
+	Interval     string            `json:"interval"`
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	Buckets      []time.Time       `json:"buckets"`
+	Acquisitions []int             `json:"acquisitions"`
+	Spend        []decimal.Decimal `json:"spend"`
+	ItemsSold    []int             `json:"items_sold"`
+	NetProfit    []decimal.Decimal `json:"net_profit"`
+}
+
+// SaveReport describes the outcome of a partial batch save
+type SaveReport struct {
+	Saved  []uuid.UUID   `json:"saved"`
+	Failed []SaveFailure `json:"failed,omitempty"`
+}
+
+// SaveFailure records an item that could not be saved. Index is the item's position in
+// the input slice; LotID is uuid.Nil when the item failed validation before one was assigned.
+type SaveFailure struct {
+	Index    int       `json:"index"`
+	LotID    uuid.UUID `json:"lot_id"`
+	ItemName string    `json:"item_name"`
+	Error    string    `json:"error"`
+}
+
+// BulkFieldUpdates holds the fields that may be changed on many items at once.
+// Nil fields are left untouched.
+type BulkFieldUpdates struct {
+	StorageLocation *string
+	StorageBin      *string
+	NeedsRepair     *bool
+	MarketDemand    *domain.MarketDemandLevel
+}
+
+// IsEmpty reports whether no fields are set
+func (u BulkFieldUpdates) IsEmpty() bool {
+	return u.StorageLocation == nil && u.StorageBin == nil && u.NeedsRepair == nil && u.MarketDemand == nil
+}
+
+// ReclassifyParams selects the items to re-run the category classifier over
+type ReclassifyParams struct {
+	Filter        ListParams // items to reclassify; pagination and sorting are ignored
+	MinConfidence float64    // only apply classifications scoring above this, between 0 and 1
+	DryRun        bool       // report the changes without writing them
+}
+
+// ReclassifyChange is a new category or condition proposed for an item
+type ReclassifyChange struct {
+	LotID        uuid.UUID            `json:"lot_id"`
+	ItemName     string               `json:"item_name"`
+	OldCategory  domain.ItemCategory  `json:"old_category"`
+	NewCategory  domain.ItemCategory  `json:"new_category"`
+	OldCondition domain.ItemCondition `json:"old_condition"`
+	NewCondition domain.ItemCondition `json:"new_condition"`
+	Confidence   float64              `json:"confidence"`
+	Applied      bool                 `json:"applied"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// ReclassifyResult reports the outcome of a reclassification
+type ReclassifyResult struct {
+	DryRun   bool               `json:"dry_run"`
+	Examined int                `json:"examined"`
+	Updated  int                `json:"updated"`
+	Changes  []ReclassifyChange `json:"changes"`
 }
 
 // ListParams holds parameters for listing inventory
@@ -26,10 +208,24 @@ type ListParams struct {
 	Search          string
 	Category        string
 	Condition       string
+	Categories      []string
+	Conditions      []string
 	StorageLocation string
 	StorageBin      string
 	InvoiceID       string
 	NeedsRepair     *bool
+	MinTotalCost    *decimal.Decimal
+	MaxTotalCost    *decimal.Decimal
+	MinBidAmount    *decimal.Decimal
+	MaxBidAmount    *decimal.Decimal
+	AcquiredFrom    *time.Time // start of the first day included
+	AcquiredTo      *time.Time // start of the last day included; the whole day matches
+	IncludeDeleted  bool       // list soft-deleted items alongside active ones
+	OnlyDeleted     bool       // list only soft-deleted items; takes precedence over IncludeDeleted
+	AfterCreatedAt  *time.Time // keyset mode: return items strictly after this (created_at, lot_id)
+	AfterLotID      uuid.UUID  // tiebreaker for AfterCreatedAt
+	IncludeTotals   bool       // also compute monetary sums over all matching items
+	Highlight       bool       // with Search, return highlighted description snippets
 	SortBy          string
 	SortOrder       string
 	Page            int
@@ -43,4 +239,18 @@ type ListResult struct {
 	PageSize   int                     `json:"page_size"`
 	TotalCount int64                   `json:"total_count"`
 	TotalPages int                     `json:"total_pages"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+
+	// Sums over every matching item, not just this page; only set when IncludeTotals is requested
+	TotalCostSum           *decimal.Decimal `json:"total_cost_sum,omitempty"`
+	TotalEstimatedValueSum *decimal.Decimal `json:"total_estimated_value_sum,omitempty"`
+
+	// Highlighted description snippets keyed by lot ID; only set when Highlight is requested
+	Highlights map[uuid.UUID]string `json:"highlights,omitempty"`
+}
+
+// ListTotals holds monetary sums across every item matching a list query
+type ListTotals struct {
+	TotalCost      decimal.Decimal
+	EstimatedValue decimal.Decimal
 }
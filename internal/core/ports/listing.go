@@ -0,0 +1,44 @@
+// internal/core/ports/listing.go
+package ports
+
+import (
+	"context"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ListingRepository defines the persistence port for platform listings
+type ListingRepository interface {
+	Create(ctx context.Context, listing *domain.PlatformListing) error
+	Update(ctx context.Context, listing *domain.PlatformListing) error
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.PlatformListing, error)
+	FindByPlatform(ctx context.Context, platform domain.Platform, page, pageSize int) ([]*domain.PlatformListing, int64, error)
+	RecordSale(ctx context.Context, sale *domain.Sale) (*domain.PlatformListing, error)
+}
+
+// ListingService defines the application service port for platform listings
+type ListingService interface {
+	CreateListing(ctx context.Context, listing *domain.PlatformListing) error
+	UpdateListing(ctx context.Context, platform domain.Platform, id uuid.UUID, update ListingUpdate) (*domain.PlatformListing, error)
+	ListListings(ctx context.Context, platform domain.Platform, page, pageSize int) (*ListingListResult, error)
+	RecordSale(ctx context.Context, sale *domain.Sale) (*domain.PlatformListing, error)
+}
+
+// ListingUpdate holds the listing fields that may be changed; nil fields are left untouched
+type ListingUpdate struct {
+	ListPrice   *decimal.Decimal
+	Title       *string
+	Description *string
+	CategoryID  *string
+}
+
+// ListingListResult holds a page of platform listings
+type ListingListResult struct {
+	Listings   []*domain.PlatformListing `json:"listings"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"page_size"`
+	TotalCount int64                     `json:"total_count"`
+	TotalPages int                       `json:"total_pages"`
+}
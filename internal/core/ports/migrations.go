@@ -0,0 +1,10 @@
+// internal/core/ports/migrations.go
+package ports
+
+import "context"
+
+// SchemaVersioner reports the applied schema migration version. It is satisfied by the
+// db.Migrator; dirty is true when a migration failed part way through.
+type SchemaVersioner interface {
+	Version(ctx context.Context) (version uint, dirty bool, err error)
+}
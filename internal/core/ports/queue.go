@@ -0,0 +1,15 @@
+// internal/core/ports/queue.go
+package ports
+
+import "github.com/hibiken/asynq"
+
+// QueueInspector defines inspection and recovery of the asynq task queues. It is satisfied by
+// *asynq.Inspector and lets handlers be tested without a Redis-backed queue.
+type QueueInspector interface {
+	Queues() ([]string, error)
+	GetQueueInfo(queue string) (*asynq.QueueInfo, error)
+	Servers() ([]*asynq.ServerInfo, error)
+	ListArchivedTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+	RunTask(queue, id string) error
+	DeleteTask(queue, id string) error
+}
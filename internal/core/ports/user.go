@@ -0,0 +1,17 @@
+// internal/core/ports/user.go
+package ports
+
+import (
+	"context"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+// UserRepository defines the persistence port for API users. The finders return nil and no
+// error when no user matches.
+type UserRepository interface {
+	// FindByEmail matches the email case-insensitively
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindByID(ctx context.Context, id string) (*domain.User, error)
+	UpdatePasswordHash(ctx context.Context, id, passwordHash string) error
+}
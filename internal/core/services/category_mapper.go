@@ -0,0 +1,119 @@
+// internal/core/services/category_mapper.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// defaultEbayCategoryID is eBay's "Everything Else" category, used when even the
+// "other" mapping is missing from the table
+const defaultEbayCategoryID = "99"
+
+// CategoryMapper resolves inventory categories to eBay category IDs from the
+// ebay_category_mappings table, keeping an in-memory copy for fast lookups
+type CategoryMapper struct {
+	repo   ports.CategoryMappingRepository
+	logger *slog.Logger
+
+	mu   sync.RWMutex
+	ebay map[domain.ItemCategory]string
+}
+
+// Statically assert that *CategoryMapper implements the CategoryMapper interface
+var _ ports.CategoryMapper = (*CategoryMapper)(nil)
+
+// NewCategoryMapper creates a new category mapper. Call Load to populate it.
+func NewCategoryMapper(repo ports.CategoryMappingRepository, logger *slog.Logger) *CategoryMapper {
+	return &CategoryMapper{
+		repo:   repo,
+		logger: logger.With(slog.String("service", "category_mapper")),
+		ebay:   make(map[domain.ItemCategory]string),
+	}
+}
+
+// Load replaces the in-memory mappings with the contents of the table
+func (m *CategoryMapper) Load(ctx context.Context) error {
+	mappings, err := m.repo.FindAllEbay(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load category mappings: %w", err)
+	}
+	if mappings == nil {
+		mappings = make(map[domain.ItemCategory]string)
+	}
+
+	m.mu.Lock()
+	m.ebay = mappings
+	m.mu.Unlock()
+
+	m.logger.InfoContext(ctx, "loaded category mappings",
+		slog.Int("ebay", len(mappings)))
+
+	return nil
+}
+
+// MapToEbay returns the eBay category ID for category. Unmapped categories fall
+// back to the "other" mapping and report false.
+func (m *CategoryMapper) MapToEbay(category domain.ItemCategory) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id, ok := m.ebay[category]; ok {
+		return id, true
+	}
+	if id, ok := m.ebay[domain.CategoryOther]; ok {
+		return id, false
+	}
+	return defaultEbayCategoryID, false
+}
+
+// EbayMappings returns a copy of the current eBay mappings
+func (m *CategoryMapper) EbayMappings() map[domain.ItemCategory]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return maps.Clone(m.ebay)
+}
+
+// SetEbayMapping persists an override for a category and applies it immediately
+func (m *CategoryMapper) SetEbayMapping(ctx context.Context, category domain.ItemCategory, ebayCategoryID string) error {
+	if !category.IsValid() {
+		return fmt.Errorf("validation failed: unknown category %q", category)
+	}
+	if !isNumericID(ebayCategoryID) {
+		return fmt.Errorf("validation failed: ebay_category_id must be numeric")
+	}
+
+	if err := m.repo.UpsertEbay(ctx, category, ebayCategoryID); err != nil {
+		return fmt.Errorf("failed to save category mapping: %w", err)
+	}
+
+	m.mu.Lock()
+	m.ebay[category] = ebayCategoryID
+	m.mu.Unlock()
+
+	m.logger.InfoContext(ctx, "updated eBay category mapping",
+		slog.String("category", string(category)),
+		slog.String("ebay_category_id", ebayCategoryID))
+
+	return nil
+}
+
+// isNumericID reports whether s is a non-empty string of ASCII digits
+func isNumericID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
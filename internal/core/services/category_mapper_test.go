@@ -0,0 +1,186 @@
+// internal/core/services/category_mapper_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/services"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func newLoadedCategoryMapper(t *testing.T, ctrl *gomock.Controller, mappings map[domain.ItemCategory]string) (*services.CategoryMapper, *mocks.MockCategoryMappingRepository) {
+	t.Helper()
+
+	repo := mocks.NewMockCategoryMappingRepository(ctrl)
+	repo.EXPECT().FindAllEbay(gomock.Any()).Return(mappings, nil)
+
+	mapper := services.NewCategoryMapper(repo, helpers.TestLogger())
+	require.NoError(t, mapper.Load(context.Background()))
+
+	return mapper, repo
+}
+
+func TestCategoryMapper_MapToEbay(t *testing.T) {
+	seeded := map[domain.ItemCategory]string{
+		domain.CategoryAntiques:  "20081",
+		domain.CategoryFurniture: "3197",
+		domain.CategoryOther:     "99",
+	}
+
+	tests := []struct {
+		name       string
+		mappings   map[domain.ItemCategory]string
+		category   domain.ItemCategory
+		expectedID string
+		expectedOK bool
+	}{
+		{
+			name:       "known_category",
+			mappings:   seeded,
+			category:   domain.CategoryAntiques,
+			expectedID: "20081",
+			expectedOK: true,
+		},
+		{
+			name:       "other_is_a_known_category",
+			mappings:   seeded,
+			category:   domain.CategoryOther,
+			expectedID: "99",
+			expectedOK: true,
+		},
+		{
+			name:       "unmapped_category_falls_back_to_other",
+			mappings:   seeded,
+			category:   domain.CategoryToys,
+			expectedID: "99",
+			expectedOK: false,
+		},
+		{
+			name:       "missing_other_mapping_uses_default",
+			mappings:   map[domain.ItemCategory]string{domain.CategoryArt: "550"},
+			category:   domain.CategoryToys,
+			expectedID: "99",
+			expectedOK: false,
+		},
+		{
+			name:       "empty_table_uses_default",
+			mappings:   nil,
+			category:   domain.CategoryArt,
+			expectedID: "99",
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mapper, _ := newLoadedCategoryMapper(t, ctrl, tt.mappings)
+
+			id, ok := mapper.MapToEbay(tt.category)
+			assert.Equal(t, tt.expectedID, id)
+			assert.Equal(t, tt.expectedOK, ok)
+		})
+	}
+}
+
+func TestCategoryMapper_Load_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockCategoryMappingRepository(ctrl)
+	repo.EXPECT().FindAllEbay(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	mapper := services.NewCategoryMapper(repo, helpers.TestLogger())
+	err := mapper.Load(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load category mappings")
+
+	// Lookups still resolve to the default category
+	id, ok := mapper.MapToEbay(domain.CategoryArt)
+	assert.Equal(t, "99", id)
+	assert.False(t, ok)
+}
+
+func TestCategoryMapper_SetEbayMapping(t *testing.T) {
+	tests := []struct {
+		name          string
+		category      domain.ItemCategory
+		ebayID        string
+		setupMocks    func(*mocks.MockCategoryMappingRepository)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name:     "overrides_mapping",
+			category: domain.CategoryToys,
+			ebayID:   "2613",
+			setupMocks: func(m *mocks.MockCategoryMappingRepository) {
+				m.EXPECT().UpsertEbay(gomock.Any(), domain.CategoryToys, "2613").Return(nil)
+			},
+		},
+		{
+			name:          "unknown_category",
+			category:      domain.ItemCategory("spaceships"),
+			ebayID:        "2613",
+			setupMocks:    func(m *mocks.MockCategoryMappingRepository) {},
+			expectedError: true,
+			errorContains: "unknown category",
+		},
+		{
+			name:          "non_numeric_id",
+			category:      domain.CategoryToys,
+			ebayID:        "toys",
+			setupMocks:    func(m *mocks.MockCategoryMappingRepository) {},
+			expectedError: true,
+			errorContains: "ebay_category_id must be numeric",
+		},
+		{
+			name:     "repository_error_keeps_previous_mapping",
+			category: domain.CategoryToys,
+			ebayID:   "2613",
+			setupMocks: func(m *mocks.MockCategoryMappingRepository) {
+				m.EXPECT().UpsertEbay(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: true,
+			errorContains: "failed to save category mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mapper, repo := newLoadedCategoryMapper(t, ctrl, map[domain.ItemCategory]string{
+				domain.CategoryToys:  "220",
+				domain.CategoryOther: "99",
+			})
+			tt.setupMocks(repo)
+
+			err := mapper.SetEbayMapping(context.Background(), tt.category, tt.ebayID)
+
+			id, _ := mapper.MapToEbay(domain.CategoryToys)
+			if tt.expectedError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.Equal(t, "220", id)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.ebayID, id)
+			assert.Equal(t, tt.ebayID, mapper.EbayMappings()[domain.CategoryToys])
+		})
+	}
+}
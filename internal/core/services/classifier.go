@@ -0,0 +1,178 @@
+// internal/core/services/classifier.go
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+)
+
+// CategoryClassifier categorizes items from the keywords in their descriptions
+type CategoryClassifier struct {
+	categoryKeywords  map[domain.ItemCategory][]WeightedKeyword
+	conditionKeywords []conditionKeywords
+}
+
+// conditionKeywords are the terms that indicate a condition. They are checked in order, so a
+// text mentioning several conditions gets the first listed.
+type conditionKeywords struct {
+	condition domain.ItemCondition
+	terms     []string
+}
+
+// WeightedKeyword is a classifier term and the score it adds when matched
+type WeightedKeyword struct {
+	Term   string
+	Weight float64
+}
+
+// Default keyword weights; phrases are more specific than single words
+const (
+	wordWeight   = 1.0
+	phraseWeight = 2.0
+)
+
+// weighted builds keywords using the default weights for words and phrases
+func weighted(terms ...string) []WeightedKeyword {
+	keywords := make([]WeightedKeyword, 0, len(terms))
+	for _, term := range terms {
+		weight := wordWeight
+		if strings.Contains(term, " ") {
+			weight = phraseWeight
+		}
+		keywords = append(keywords, WeightedKeyword{Term: term, Weight: weight})
+	}
+	return keywords
+}
+
+// NewCategoryClassifier creates a classifier with the built-in keyword lists
+func NewCategoryClassifier() *CategoryClassifier {
+	return &CategoryClassifier{
+		categoryKeywords: map[domain.ItemCategory][]WeightedKeyword{
+			domain.CategoryAntiques: weighted("antique", "victorian", "edwardian", "georgian", "art deco",
+				"art nouveau", "mid century", "mcm", "vintage"),
+			domain.CategoryArt: weighted("painting", "print", "lithograph", "etching", "drawing", "framed",
+				"sculpture", "statue", "canvas", "watercolor", "oil painting", "serigraph"),
+			domain.CategoryBooks: weighted("book", "volume", "edition", "manuscript", "atlas",
+				"encyclopedia", "novel", "hardcover", "paperback"),
+			domain.CategoryCeramics: weighted("ceramic", "porcelain", "pottery", "stoneware", "earthenware",
+				"terracotta", "faience", "majolica", "capodimonte", "capidimonte"),
+			domain.CategoryChina: weighted("china", "dinnerware", "plate", "bowl", "teacup", "saucer",
+				"serving", "platter", "tureen", "gravy boat", "ming"),
+			domain.CategoryClothing: weighted("dress", "shirt", "pants", "jacket", "coat", "shoes",
+				"hat", "scarf", "vintage clothing", "designer"),
+			domain.CategoryCoins: weighted("coin", "numismatic", "currency", "mint", "proof",
+				"commemorative", "gold coin", "silver coin"),
+			domain.CategoryCollectibles: weighted("collectible", "limited edition", "memorabilia", "trading card",
+				"figurine", "model", "diecast", "precious moments", "danbury mint", "enesco", "lladro"),
+			domain.CategoryElectronics: weighted("electronic", "computer", "phone", "camera", "stereo",
+				"radio", "television", "console", "gadget", "sewing machine", "grinder"),
+			domain.CategoryFurniture: weighted("table", "chair", "desk", "cabinet", "dresser", "sofa", "lamp",
+				"bench", "ottoman", "bookcase", "sideboard", "chest", "console", "barstool", "shelves"),
+			domain.CategoryGlass: weighted("glass", "crystal", "cut glass", "pressed glass", "blown glass",
+				"stained glass", "depression glass", "carnival glass", "art glass", "vase", "bowl"),
+			domain.CategoryJewelry: weighted("jewelry", "ring", "necklace", "bracelet", "earring",
+				"brooch", "pendant", "gold", "silver", "diamond", "gemstone", "sterling"),
+			domain.CategoryLinens: weighted("linen", "tablecloth", "napkin", "doily", "runner",
+				"bedding", "quilt", "blanket", "textile", "fabric"),
+			domain.CategoryMusical: weighted("musical", "instrument", "piano", "guitar", "violin",
+				"trumpet", "saxophone", "drum", "sheet music", "music box"),
+			domain.CategorySilver: append(weighted("sterling", "silver", "silverplate", "flatware", "hollowware",
+				"tea set", "candelabra", "serving piece"),
+				// Plating phrases describe the material, not the form of the piece
+				WeightedKeyword{Term: "silver plate", Weight: 3},
+				WeightedKeyword{Term: "silver plated", Weight: 3},
+				WeightedKeyword{Term: "sterling silver", Weight: 3}),
+			domain.CategoryStamps: weighted("stamp", "philatelic", "postage", "first day cover",
+				"postmark", "album"),
+			domain.CategoryTools: weighted("tool", "drill", "saw", "hammer", "wrench", "pliers",
+				"vintage tool", "woodworking", "machinist", "grinder"),
+			domain.CategoryToys: weighted("toy", "doll", "action figure", "game", "puzzle",
+				"teddy bear", "train set", "lego", "vintage toy", "lionel"),
+			domain.CategoryVintage: weighted("brass", "cherub", "andirons", "bookend", "dolphin", "copper", "bronze"),
+		},
+		conditionKeywords: []conditionKeywords{
+			{domain.ConditionMint, []string{"mint", "pristine", "perfect", "new"}},
+			{domain.ConditionExcellent, []string{"excellent", "near mint", "superb"}},
+			{domain.ConditionVeryGood, []string{"very good", "vg", "great"}},
+			{domain.ConditionGood, []string{"good", "nice", "decent"}},
+			{domain.ConditionFair, []string{"fair", "acceptable", "wear"}},
+			{domain.ConditionPoor, []string{"poor", "damaged", "broken", "torn"}},
+			{domain.ConditionRestoration, []string{"restored", "repaired", "refinished"}},
+			{domain.ConditionParts, []string{"parts", "repair", "incomplete", "as-is"}},
+		},
+	}
+}
+
+// CategoryScore is a category with its share of the weighted keyword hits for a text
+type CategoryScore struct {
+	Category domain.ItemCategory
+	Score    float64
+}
+
+// Classify returns the best matching category and condition for a text
+func (c *CategoryClassifier) Classify(text string) (domain.ItemCategory, domain.ItemCondition) {
+	scores, condition, _ := c.ClassifyWithScores(text)
+	if len(scores) == 0 {
+		return domain.CategoryOther, condition
+	}
+	return scores[0].Category, condition
+}
+
+// ClassifyWithScores returns all matching categories ranked by normalized weighted score,
+// the detected condition, and the confidence of the top category
+func (c *CategoryClassifier) ClassifyWithScores(text string) ([]CategoryScore, domain.ItemCondition, float64) {
+	textLower := strings.ToLower(text)
+
+	// Sum keyword weights per category
+	categoryHits := make(map[domain.ItemCategory]float64)
+	totalHits := 0.0
+	for category, keywords := range c.categoryKeywords {
+		hits := 0.0
+		for _, kw := range keywords {
+			if strings.Contains(textLower, kw.Term) {
+				hits += kw.Weight
+			}
+		}
+		if hits > 0 {
+			categoryHits[category] = hits
+			totalHits += hits
+		}
+	}
+
+	scores := make([]CategoryScore, 0, len(categoryHits))
+	for category, hits := range categoryHits {
+		scores = append(scores, CategoryScore{
+			Category: category,
+			Score:    hits / totalHits,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Category < scores[j].Category
+	})
+
+	// Find condition
+	condition := domain.ConditionUnknown
+	for _, ck := range c.conditionKeywords {
+		for _, kw := range ck.terms {
+			if strings.Contains(textLower, kw) {
+				condition = ck.condition
+				break
+			}
+		}
+		if condition != domain.ConditionUnknown {
+			break
+		}
+	}
+
+	confidence := 0.0
+	if len(scores) > 0 {
+		confidence = scores[0].Score
+	}
+
+	return scores, condition, confidence
+}
@@ -0,0 +1,79 @@
+// internal/core/services/classifier_test.go
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/services"
+)
+
+func TestCategoryClassifier_Classify(t *testing.T) {
+	classifier := services.NewCategoryClassifier()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected domain.ItemCategory
+	}{
+		{
+			name:     "silver_plate_outranks_serving_dish",
+			text:     "Silver plate serving dish",
+			expected: domain.CategorySilver,
+		},
+		{
+			name:     "sterling_silver_outranks_plate",
+			text:     "Sterling silver cake plate",
+			expected: domain.CategorySilver,
+		},
+		{
+			name:     "porcelain_plate_stays_ceramics",
+			text:     "Porcelain stoneware plate",
+			expected: domain.CategoryCeramics,
+		},
+		{
+			name:     "depression_glass_phrase_wins",
+			text:     "Pink depression glass candy dish",
+			expected: domain.CategoryGlass,
+		},
+		{
+			name:     "no_keywords_is_other",
+			text:     "Miscellaneous box lot",
+			expected: domain.CategoryOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, _ := classifier.Classify(tt.text)
+			assert.Equal(t, tt.expected, category)
+		})
+	}
+}
+
+func TestCategoryClassifier_ClassifyWithScores(t *testing.T) {
+	classifier := services.NewCategoryClassifier()
+
+	scores, _, confidence := classifier.ClassifyWithScores("Silver plate serving dish")
+	require.NotEmpty(t, scores)
+
+	assert.Equal(t, domain.CategorySilver, scores[0].Category)
+	assert.Equal(t, scores[0].Score, confidence)
+
+	total := 0.0
+	for i, score := range scores {
+		total += score.Score
+		if i > 0 {
+			assert.GreaterOrEqual(t, scores[i-1].Score, score.Score)
+		}
+	}
+	assert.InDelta(t, 1.0, total, 0.0001)
+
+	scores, condition, confidence := classifier.ClassifyWithScores("Miscellaneous box lot")
+	assert.Empty(t, scores)
+	assert.Equal(t, domain.ConditionUnknown, condition)
+	assert.Zero(t, confidence)
+}
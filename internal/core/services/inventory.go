@@ -5,12 +5,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ammerola/resell-be/internal/core/domain"
 	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
 )
 
 // PgxPool interface defines the contract for database operations needed by the service
@@ -22,9 +27,10 @@ type PgxPool interface {
 // InventoryService orchestrates business logic for inventory management
 // It delegates ALL data access operations to the repository
 type InventoryService struct {
-	repo   ports.InventoryRepository
-	db     PgxPool // Only used for transaction management, not queries
-	logger *slog.Logger
+	repo       ports.InventoryRepository
+	db         PgxPool // Only used for transaction management, not queries
+	classifier *CategoryClassifier
+	logger     *slog.Logger
 }
 
 // Statically assert that *InventoryService implements the InventoryService interface
@@ -33,22 +39,43 @@ var _ ports.InventoryService = (*InventoryService)(nil)
 // NewInventoryService creates a new inventory service instance
 func NewInventoryService(repo ports.InventoryRepository, db PgxPool, logger *slog.Logger) *InventoryService {
 	return &InventoryService{
-		repo:   repo,
-		db:     db,
-		logger: logger.With(slog.String("service", "inventory")),
+		repo:       repo,
+		db:         db,
+		classifier: NewCategoryClassifier(),
+		logger:     logger.With(slog.String("service", "inventory")),
 	}
 }
 
+// stampCreator records the request's user as the item's creator and last editor. A creator
+// already on the item is kept so re-imports don't reassign ownership.
+func stampCreator(ctx context.Context, item *domain.InventoryItem) {
+	userID := logger.UserIDFromContext(ctx)
+	if item.CreatedBy == "" {
+		item.CreatedBy = userID
+	}
+	item.UpdatedBy = userID
+}
+
+// prepareItem validates a new item, calculates its costs and readies it for storage
+func prepareItem(ctx context.Context, item *domain.InventoryItem) error {
+	if err := item.Validate(); err != nil {
+		return err
+	}
+	if err := item.CalculateCosts(nil); err != nil {
+		return err
+	}
+	item.PrepareForStorage()
+	stampCreator(ctx, item)
+	return nil
+}
+
 // SaveItem validates and saves a single inventory item
 func (s *InventoryService) SaveItem(ctx context.Context, item *domain.InventoryItem) error {
-	// Business validation
-	if err := item.Validate(); err != nil {
+	// Business validation, cost calculation, UUID and timestamps
+	if err := prepareItem(ctx, item); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Prepare item for storage (sets UUID, timestamps, calculates totals)
-	item.PrepareForStorage()
-
 	// Delegate to repository for actual persistence
 	if err := s.repo.Save(ctx, item); err != nil {
 		return fmt.Errorf("failed to save item: %w", err)
@@ -71,10 +98,9 @@ func (s *InventoryService) SaveItems(ctx context.Context, items []domain.Invento
 
 	// Validate and prepare all items
 	for i := range items {
-		if err := items[i].Validate(); err != nil {
+		if err := prepareItem(ctx, &items[i]); err != nil {
 			return fmt.Errorf("validation failed for item %s: %w", items[i].ItemName, err)
 		}
-		items[i].PrepareForStorage()
 	}
 
 	// Delegate to repository for batch save
@@ -88,8 +114,80 @@ func (s *InventoryService) SaveItems(ctx context.Context, items []domain.Invento
 	return nil
 }
 
-// BulkUpsert performs a bulk upsert operation in batches for efficiency
-func (s *InventoryService) BulkUpsert(ctx context.Context, items []domain.InventoryItem) error {
+// SaveItemsPartial saves as many items as possible and reports which were saved and which failed.
+// Valid items are first saved as a single batch; if that fails each one is retried on its own so
+// a bad item only loses itself. The error is only non-nil when the context is cancelled.
+func (s *InventoryService) SaveItemsPartial(ctx context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+	report := ports.SaveReport{Saved: make([]uuid.UUID, 0, len(items))}
+
+	// Validate up front so invalid items never reach the repository
+	valid := make([]int, 0, len(items))
+	for i := range items {
+		if err := prepareItem(ctx, &items[i]); err != nil {
+			report.Failed = append(report.Failed, ports.SaveFailure{
+				Index:    i,
+				ItemName: items[i].ItemName,
+				Error:    fmt.Sprintf("validation failed: %v", err),
+			})
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	if len(valid) == 0 {
+		return report, nil
+	}
+
+	batch := make([]domain.InventoryItem, len(valid))
+	for j, i := range valid {
+		batch[j] = items[i]
+	}
+
+	err := s.repo.SaveBatch(ctx, batch)
+	if err == nil {
+		for j, i := range valid {
+			items[i] = batch[j]
+			report.Saved = append(report.Saved, items[i].LotID)
+		}
+		s.logger.InfoContext(ctx, "saved inventory items",
+			slog.Int("count", len(valid)),
+			slog.Int("failed", len(report.Failed)))
+		return report, nil
+	}
+
+	s.logger.WarnContext(ctx, "batch save failed, retrying items individually",
+		slog.Int("count", len(valid)),
+		slog.String("error", err.Error()))
+
+	for _, i := range valid {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := s.repo.Save(ctx, &items[i]); err != nil {
+			report.Failed = append(report.Failed, ports.SaveFailure{
+				Index:    i,
+				LotID:    items[i].LotID,
+				ItemName: items[i].ItemName,
+				Error:    err.Error(),
+			})
+			continue
+		}
+		report.Saved = append(report.Saved, items[i].LotID)
+	}
+
+	sort.Slice(report.Failed, func(a, b int) bool {
+		return report.Failed[a].Index < report.Failed[b].Index
+	})
+
+	s.logger.InfoContext(ctx, "saved inventory items individually",
+		slog.Int("saved", len(report.Saved)),
+		slog.Int("failed", len(report.Failed)))
+
+	return report, nil
+}
+
+// BulkUpsert saves items in batches, updating the existing row chosen by target on a conflict
+func (s *InventoryService) BulkUpsert(ctx context.Context, items []domain.InventoryItem, target ports.ConflictTarget) error {
 	const batchSize = 100
 
 	for i := 0; i < len(items); i += batchSize {
@@ -99,7 +197,13 @@ func (s *InventoryService) BulkUpsert(ctx context.Context, items []domain.Invent
 		}
 
 		batch := items[i:end]
-		if err := s.SaveItems(ctx, batch); err != nil {
+		for j := range batch {
+			if err := prepareItem(ctx, &batch[j]); err != nil {
+				return fmt.Errorf("validation failed for item %s: %w", batch[j].ItemName, err)
+			}
+		}
+
+		if err := s.repo.SaveBatchUpsert(ctx, batch, target); err != nil {
 			return fmt.Errorf("failed to save batch %d-%d: %w", i, end, err)
 		}
 
@@ -119,7 +223,7 @@ func (s *InventoryService) GetByID(ctx context.Context, lotID uuid.UUID) (*domai
 	}
 
 	if item == nil {
-		return nil, fmt.Errorf("inventory item not found: %s", lotID)
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
 	}
 
 	return item, nil
@@ -134,18 +238,47 @@ func (s *InventoryService) GetByInvoiceID(ctx context.Context, invoiceID string)
 	return items, nil
 }
 
+// GetInvoiceItems returns the active items of an invoice with its total cost and average bid
+func (s *InventoryService) GetInvoiceItems(ctx context.Context, invoiceID string) (*ports.InvoiceItems, error) {
+	items, err := s.GetByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ports.InvoiceItems{
+		InvoiceID: invoiceID,
+		Items:     items,
+		ItemCount: len(items),
+	}
+	if result.Items == nil {
+		result.Items = []domain.InventoryItem{}
+	}
+
+	bids := decimal.Zero
+	for _, item := range items {
+		result.TotalCost = result.TotalCost.Add(item.TotalCost)
+		bids = bids.Add(item.BidAmount)
+	}
+	if len(items) > 0 {
+		result.AverageBid = bids.DivRound(decimal.NewFromInt(int64(len(items))), 2)
+	}
+
+	return result, nil
+}
+
 // UpdateItem updates an existing inventory item
 func (s *InventoryService) UpdateItem(ctx context.Context, lotID uuid.UUID, item *domain.InventoryItem) error {
 	// Ensure the ID matches
 	item.LotID = lotID
 
-	// Validate the item
+	// Validate the item and recalculate financial fields
 	if err := item.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
-
-	// Recalculate financial fields
-	item.CalculateTotalCost()
+	if err := item.CalculateCosts(nil); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	item.UpdatedBy = logger.UserIDFromContext(ctx)
 
 	// Delegate to repository
 	if err := s.repo.Update(ctx, item); err != nil {
@@ -158,6 +291,45 @@ func (s *InventoryService) UpdateItem(ctx context.Context, lotID uuid.UUID, item
 	return nil
 }
 
+// PatchItem updates only the fields set in the patch and returns the stored item. The item is
+// validated as it would be after the patch; fields the table requires cannot be set to null.
+func (s *InventoryService) PatchItem(ctx context.Context, lotID uuid.UUID, patch ports.InventoryPatch) (*domain.InventoryItem, error) {
+	if patch.IsEmpty() {
+		return nil, fmt.Errorf("validation failed: at least one field to update is required")
+	}
+	if field := patch.NullRequiredField(); field != "" {
+		return nil, fmt.Errorf("validation failed: %s cannot be null", field)
+	}
+
+	current, err := s.repo.FindByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
+	}
+
+	patched := *current
+	patch.Apply(&patched)
+	if err := patched.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if !patched.Category.IsValid() || !patched.Condition.IsValid() || !patched.MarketDemand.IsValid() {
+		return nil, fmt.Errorf("validation failed: unknown category, condition or market_demand")
+	}
+	patch.UpdatedBy = logger.UserIDFromContext(ctx)
+
+	item, err := s.repo.UpdatePartial(ctx, lotID, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch item: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "patched inventory item",
+		slog.String("lot_id", lotID.String()))
+
+	return item, nil
+}
+
 // DeleteItem deletes an inventory item (soft or permanent)
 func (s *InventoryService) DeleteItem(ctx context.Context, lotID uuid.UUID, permanent bool) error {
 	// Check if item exists
@@ -167,7 +339,7 @@ func (s *InventoryService) DeleteItem(ctx context.Context, lotID uuid.UUID, perm
 	}
 
 	if !exists {
-		return fmt.Errorf("inventory item not found: %s", lotID)
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
 	}
 
 	// Perform deletion
@@ -188,6 +360,261 @@ func (s *InventoryService) DeleteItem(ctx context.Context, lotID uuid.UUID, perm
 	return nil
 }
 
+// DeleteInvoiceItems soft deletes every item on an invoice so it can be imported again
+func (s *InventoryService) DeleteInvoiceItems(ctx context.Context, invoiceID string) (int64, error) {
+	deleted, err := s.repo.SoftDeleteByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete invoice items: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "deleted invoice items",
+		slog.String("invoice_id", invoiceID),
+		slog.Int64("count", deleted))
+
+	return deleted, nil
+}
+
+// HasInvoice reports whether items from the invoice have already been imported
+func (s *InventoryService) HasInvoice(ctx context.Context, invoiceID string) (bool, error) {
+	exists, err := s.repo.HasInvoice(ctx, invoiceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check invoice: %w", err)
+	}
+	return exists, nil
+}
+
+// RestoreItem brings a soft-deleted item back and returns it
+func (s *InventoryService) RestoreItem(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error) {
+	if err := s.repo.Restore(ctx, lotID); err != nil {
+		return nil, fmt.Errorf("failed to restore item: %w", err)
+	}
+
+	item, err := s.repo.FindByID(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored item: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "restored inventory item",
+		slog.String("lot_id", lotID.String()))
+
+	return item, nil
+}
+
+// GetHistory returns an item's audit trail, newest first. Deleted items keep their history;
+// an item with no history is reported as not found unless it still exists.
+func (s *InventoryService) GetHistory(ctx context.Context, lotID uuid.UUID) ([]domain.AuditEntry, error) {
+	entries, err := s.repo.FindHistory(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory history: %w", err)
+	}
+
+	if len(entries) == 0 {
+		// Items created before auditing began have no entries yet
+		item, err := s.repo.FindByID(ctx, lotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inventory item: %w", err)
+		}
+		if item == nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, lotID)
+		}
+	}
+
+	return entries, nil
+}
+
+// BulkUpdateFields applies the same field updates to many items and returns how many were changed
+func (s *InventoryService) BulkUpdateFields(ctx context.Context, lotIDs []uuid.UUID, updates ports.BulkFieldUpdates) (int64, error) {
+	if len(lotIDs) == 0 {
+		return 0, fmt.Errorf("validation failed: at least one lot_id is required")
+	}
+	if updates.IsEmpty() {
+		return 0, fmt.Errorf("validation failed: at least one field to update is required")
+	}
+	if updates.MarketDemand != nil && !updates.MarketDemand.IsValid() {
+		return 0, fmt.Errorf("validation failed: unknown market_demand %q", *updates.MarketDemand)
+	}
+
+	affected, err := s.repo.BulkUpdateFields(ctx, lotIDs, updates)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk update items: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "bulk updated inventory items",
+		slog.Int("requested", len(lotIDs)),
+		slog.Int64("affected", affected))
+
+	return affected, nil
+}
+
+// GenerateQRCodes stores the template's payload as the QR code of every active item that has
+// none, returning how many were filled. Items that already have a payload keep it.
+func (s *InventoryService) GenerateQRCodes(ctx context.Context, template domain.QRTemplate) (int64, error) {
+	if err := template.Validate(); err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+
+	filled, err := s.repo.FillQRCodes(ctx, template)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate qr codes: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "generated inventory qr codes",
+		slog.Int64("filled", filled))
+
+	return filled, nil
+}
+
+// ListInvoices returns a page of invoices with the count and summed cost of their items
+func (s *InventoryService) ListInvoices(ctx context.Context, params ports.InvoiceListParams) (*ports.InvoiceListResult, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 50
+	}
+	if params.PageSize > 1000 {
+		params.PageSize = 1000
+	}
+	if params.SortBy == "" {
+		params.SortBy = ports.InvoiceSortDate
+	}
+	if params.SortBy != ports.InvoiceSortDate && params.SortBy != ports.InvoiceSortTotal {
+		return nil, fmt.Errorf("validation failed: sort_by must be %s or %s", ports.InvoiceSortDate, ports.InvoiceSortTotal)
+	}
+
+	invoices, totalCount, err := s.repo.ListInvoices(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	if invoices == nil {
+		invoices = []ports.InvoiceSummary{}
+	}
+
+	totalPages := int(totalCount) / params.PageSize
+	if int(totalCount)%params.PageSize > 0 {
+		totalPages++
+	}
+
+	return &ports.InvoiceListResult{
+		Invoices:   invoices,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// StorageSummary nests the per-bin counts and estimated values under their storage
+// locations, with totals per location and overall
+func (s *InventoryService) StorageSummary(ctx context.Context) (*ports.StorageSummary, error) {
+	bins, err := s.repo.StorageSummary(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize storage: %w", err)
+	}
+
+	summary := &ports.StorageSummary{Locations: []ports.StorageLocationSummary{}}
+	index := make(map[string]int)
+	for _, bin := range bins {
+		i, ok := index[bin.Location]
+		if !ok {
+			i = len(summary.Locations)
+			index[bin.Location] = i
+			summary.Locations = append(summary.Locations, ports.StorageLocationSummary{Location: bin.Location})
+		}
+		location := &summary.Locations[i]
+		location.Bins = append(location.Bins, bin)
+		location.ItemCount += bin.ItemCount
+		location.EstimatedValue = location.EstimatedValue.Add(bin.EstimatedValue)
+		summary.ItemCount += bin.ItemCount
+		summary.EstimatedValue = summary.EstimatedValue.Add(bin.EstimatedValue)
+	}
+
+	return summary, nil
+}
+
+// TimeSeries returns acquisitions, spend, sales and net profit per interval from the one
+// containing From up to To, with zeros for intervals that had no activity
+func (s *InventoryService) TimeSeries(ctx context.Context, params ports.TimeSeriesParams) (*ports.TimeSeries, error) {
+	if params.Interval == "" {
+		params.Interval = ports.IntervalDay
+	}
+	switch params.Interval {
+	case ports.IntervalDay, ports.IntervalWeek, ports.IntervalMonth:
+	default:
+		return nil, fmt.Errorf("validation failed: interval must be %s, %s or %s",
+			ports.IntervalDay, ports.IntervalWeek, ports.IntervalMonth)
+	}
+	if !params.From.Before(params.To) {
+		return nil, fmt.Errorf("validation failed: from must be before to")
+	}
+	params.From, params.To = params.From.UTC(), params.To.UTC()
+
+	var starts []time.Time
+	for start := truncateInterval(params.From, params.Interval); start.Before(params.To); start = nextInterval(start, params.Interval) {
+		if len(starts) == ports.MaxTimeSeriesBuckets {
+			return nil, fmt.Errorf("validation failed: range spans more than %d %s buckets",
+				ports.MaxTimeSeriesBuckets, params.Interval)
+		}
+		starts = append(starts, start)
+	}
+
+	buckets, err := s.repo.TimeSeries(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time series: %w", err)
+	}
+	byStart := make(map[time.Time]ports.TimeSeriesBucket, len(buckets))
+	for _, bucket := range buckets {
+		byStart[bucket.Start.UTC()] = bucket
+	}
+
+	series := &ports.TimeSeries{
+		Interval:     params.Interval,
+		From:         params.From,
+		To:           params.To,
+		Buckets:      starts,
+		Acquisitions: make([]int, len(starts)),
+		Spend:        make([]decimal.Decimal, len(starts)),
+		ItemsSold:    make([]int, len(starts)),
+		NetProfit:    make([]decimal.Decimal, len(starts)),
+	}
+	for i, start := range starts {
+		bucket := byStart[start]
+		series.Acquisitions[i] = bucket.Acquisitions
+		series.Spend[i] = bucket.Spend
+		series.ItemsSold[i] = bucket.ItemsSold
+		series.NetProfit[i] = bucket.NetProfit
+	}
+
+	return series, nil
+}
+
+// truncateInterval returns the start of the interval containing t in UTC, matching date_trunc:
+// midnight for days, Monday for weeks and the 1st for months
+func truncateInterval(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case ports.IntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case ports.IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextInterval returns the start of the interval after the one starting at start
+func nextInterval(start time.Time, interval string) time.Time {
+	switch interval {
+	case ports.IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case ports.IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
 // List retrieves inventory items with filtering and pagination
 // This method now simply delegates to the repository, which handles ALL query logic
 func (s *InventoryService) List(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
@@ -226,6 +653,38 @@ func (s *InventoryService) List(ctx context.Context, params ports.ListParams) (*
 		TotalPages: totalPages,
 	}
 
+	// Totals need an extra aggregate query, so they are only computed on request
+	if params.IncludeTotals {
+		totals, err := s.repo.SumTotals(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum inventory totals: %w", err)
+		}
+		result.TotalCostSum = &totals.TotalCost
+		result.TotalEstimatedValueSum = &totals.EstimatedValue
+	}
+
+	// Snippets are cosmetic, so a failure is logged rather than failing the listing
+	if params.Highlight && params.Search != "" && len(items) > 0 {
+		lotIDs := make([]uuid.UUID, len(items))
+		for i, item := range items {
+			lotIDs[i] = item.LotID
+		}
+
+		highlights, err := s.repo.SearchHighlights(ctx, params.Search, lotIDs)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to load search highlights",
+				slog.String("error", err.Error()))
+		} else {
+			result.Highlights = highlights
+		}
+	}
+
+	// A full page in (created_at, lot_id) order can be continued with keyset pagination
+	if len(items) > 0 && len(items) == params.PageSize && supportsCursor(params) {
+		last := items[len(items)-1]
+		result.NextCursor = ports.EncodeListCursor(last.CreatedAt, last.LotID)
+	}
+
 	s.logger.DebugContext(ctx, "listed inventory items",
 		slog.Int("count", len(items)),
 		slog.Int64("total", totalCount),
@@ -234,6 +693,33 @@ func (s *InventoryService) List(ctx context.Context, params ports.ListParams) (*
 	return result, nil
 }
 
+// Suggest returns item names and keywords that start with prefix, for type-ahead search
+func (s *InventoryService) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" || limit < 1 {
+		return []string{}, nil
+	}
+
+	suggestions, err := s.repo.Suggest(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+
+	return suggestions, nil
+}
+
+// supportsCursor reports whether results are ordered newest first, matching keyset pagination
+func supportsCursor(params ports.ListParams) bool {
+	if params.AfterCreatedAt != nil {
+		return true
+	}
+	sortBy := params.SortBy == "" || params.SortBy == "created_at" || params.SortBy == "created"
+	return sortBy && (params.SortOrder == "" || params.SortOrder == "desc")
+}
+
 // GetStatistics returns aggregate statistics about the inventory
 // This is a business logic method that could use specialized repository methods
 func (s *InventoryService) GetStatistics(ctx context.Context) (*InventoryStatistics, error) {
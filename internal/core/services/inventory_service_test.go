@@ -4,7 +4,9 @@ package services_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -15,6 +17,7 @@ import (
 	"github.com/ammerola/resell-be/internal/core/domain"
 	"github.com/ammerola/resell-be/internal/core/ports"
 	"github.com/ammerola/resell-be/internal/core/services"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
 	"github.com/ammerola/resell-be/test/helpers"
 	"github.com/ammerola/resell-be/test/mocks"
 )
@@ -120,6 +123,36 @@ func TestInventoryService_SaveItem(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "divides_cost_across_multi_quantity_lot",
+			item: helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.Quantity = 4
+				i.BidAmount = decimal.NewFromFloat(100.00)
+				i.BuyersPremium = decimal.NewFromFloat(18.00)
+				i.SalesTax = decimal.NewFromFloat(10.18)
+				i.ShippingCost = decimal.NewFromFloat(15.00)
+			}),
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, item *domain.InventoryItem) error {
+						assert.True(t, item.TotalCost.Equal(decimal.NewFromFloat(143.18)))
+						assert.True(t, item.CostPerItem.Equal(decimal.NewFromFloat(35.80)),
+							"Expected per item: 35.80, Got: %s", item.CostPerItem)
+						return nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name: "validation_fails_for_negative_shipping_cost",
+			item: helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.ShippingCost = decimal.NewFromFloat(-1)
+			}),
+			setupMocks:    func(m *mocks.MockInventoryRepository) {},
+			expectedError: true,
+			errorContains: "shipping_cost cannot be negative",
+		},
 	}
 
 	for _, tt := range tests {
@@ -154,6 +187,78 @@ func TestInventoryService_SaveItem(t *testing.T) {
 	}
 }
 
+func TestInventoryService_StampsUserFromContext(t *testing.T) {
+	authed := logger.WithUserID(context.Background(), "user-42")
+
+	t.Run("save_sets_creator_and_editor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		item := helpers.CreateTestInventoryItem()
+		require.NoError(t, service.SaveItem(authed, item))
+		assert.Equal(t, "user-42", item.CreatedBy)
+		assert.Equal(t, "user-42", item.UpdatedBy)
+	})
+
+	t.Run("batch_keeps_existing_creator", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().SaveBatch(gomock.Any(), gomock.Any()).Return(nil)
+
+		items := []domain.InventoryItem{
+			*helpers.CreateTestInventoryItem(),
+			*helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) { i.CreatedBy = "importer" }),
+		}
+		require.NoError(t, service.SaveItems(authed, items))
+		assert.Equal(t, "user-42", items[0].CreatedBy)
+		assert.Equal(t, "importer", items[1].CreatedBy)
+		assert.Equal(t, "user-42", items[1].UpdatedBy)
+	})
+
+	t.Run("update_sets_editor_only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, item *domain.InventoryItem) error {
+				assert.Equal(t, "user-42", item.UpdatedBy)
+				assert.Empty(t, item.CreatedBy)
+				return nil
+			})
+
+		item := helpers.CreateTestInventoryItem()
+		require.NoError(t, service.UpdateItem(authed, item.LotID, item))
+	})
+
+	t.Run("anonymous_request_leaves_stamps_empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		item := helpers.CreateTestInventoryItem()
+		require.NoError(t, service.SaveItem(context.Background(), item))
+		assert.Empty(t, item.CreatedBy)
+		assert.Empty(t, item.UpdatedBy)
+	})
+}
+
 func TestInventoryService_SaveItems(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -234,6 +339,100 @@ func TestInventoryService_SaveItems(t *testing.T) {
 	}
 }
 
+func TestInventoryService_SaveItemsPartial(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      func() []domain.InventoryItem
+		setupMocks func(*mocks.MockInventoryRepository, []domain.InventoryItem)
+		validate   func(*testing.T, []domain.InventoryItem, ports.SaveReport)
+	}{
+		{
+			name:  "saves_whole_batch_when_it_succeeds",
+			items: func() []domain.InventoryItem { return helpers.CreateTestInventoryItems(3) },
+			setupMocks: func(m *mocks.MockInventoryRepository, _ []domain.InventoryItem) {
+				m.EXPECT().
+					SaveBatch(gomock.Any(), gomock.Len(3)).
+					Return(nil)
+			},
+			validate: func(t *testing.T, items []domain.InventoryItem, report ports.SaveReport) {
+				assert.Empty(t, report.Failed)
+				require.Len(t, report.Saved, 3)
+				for i := range items {
+					assert.Equal(t, items[i].LotID, report.Saved[i])
+				}
+			},
+		},
+		{
+			name: "isolates_failures_when_batch_fails",
+			items: func() []domain.InventoryItem {
+				return []domain.InventoryItem{
+					*helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) { i.ItemName = "Lamp" }),
+					*helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+						i.ItemName = "No Invoice"
+						i.InvoiceID = ""
+					}),
+					*helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) { i.ItemName = "Duplicate" }),
+					*helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) { i.ItemName = "Chair" }),
+				}
+			},
+			setupMocks: func(m *mocks.MockInventoryRepository, items []domain.InventoryItem) {
+				// The invalid item never reaches the repository
+				m.EXPECT().
+					SaveBatch(gomock.Any(), gomock.Len(3)).
+					Return(errors.New("duplicate key value"))
+				m.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					Times(3).
+					DoAndReturn(func(_ context.Context, item *domain.InventoryItem) error {
+						if item.ItemName == "Duplicate" {
+							return errors.New("duplicate key value")
+						}
+						return nil
+					})
+			},
+			validate: func(t *testing.T, items []domain.InventoryItem, report ports.SaveReport) {
+				assert.Equal(t, []uuid.UUID{items[0].LotID, items[3].LotID}, report.Saved)
+				require.Len(t, report.Failed, 2)
+
+				assert.Equal(t, 1, report.Failed[0].Index)
+				assert.Equal(t, "No Invoice", report.Failed[0].ItemName)
+				assert.Contains(t, report.Failed[0].Error, "validation failed")
+
+				assert.Equal(t, 2, report.Failed[1].Index)
+				assert.Equal(t, items[2].LotID, report.Failed[1].LotID)
+				assert.Equal(t, "duplicate key value", report.Failed[1].Error)
+			},
+		},
+		{
+			name:       "returns_empty_report_for_no_items",
+			items:      func() []domain.InventoryItem { return nil },
+			setupMocks: func(m *mocks.MockInventoryRepository, _ []domain.InventoryItem) {},
+			validate: func(t *testing.T, _ []domain.InventoryItem, report ports.SaveReport) {
+				assert.Empty(t, report.Saved)
+				assert.Empty(t, report.Failed)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockInventoryRepository(ctrl)
+			service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+			items := tt.items()
+			tt.setupMocks(mockRepo, items)
+
+			report, err := service.SaveItemsPartial(context.Background(), items)
+
+			require.NoError(t, err)
+			tt.validate(t, items, report)
+		})
+	}
+}
+
 func TestInventoryService_GetByID(t *testing.T) {
 	testItem := helpers.CreateTestInventoryItem()
 
@@ -306,6 +505,7 @@ func TestInventoryService_GetByID(t *testing.T) {
 				if tt.errorContains != "" {
 					assert.Contains(t, err.Error(), tt.errorContains)
 				}
+				assert.Equal(t, tt.name == "item_not_found", errors.Is(err, domain.ErrNotFound))
 			} else {
 				require.NoError(t, err)
 				require.NotNil(t, result)
@@ -508,6 +708,213 @@ func TestInventoryService_DeleteItem(t *testing.T) {
 	}
 }
 
+func TestInventoryService_RestoreItem(t *testing.T) {
+	testLotID := uuid.New()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.MockInventoryRepository)
+		errorContains string
+	}{
+		{
+			name: "restores_deleted_item",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().Restore(gomock.Any(), testLotID).Return(nil)
+				m.EXPECT().FindByID(gomock.Any(), testLotID).
+					Return(&domain.InventoryItem{LotID: testLotID, ItemName: "Restored"}, nil)
+			},
+		},
+		{
+			name: "item_not_deleted",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().Restore(gomock.Any(), testLotID).
+					Return(fmt.Errorf("%w: %s", domain.ErrNotFound, testLotID))
+			},
+			errorContains: "inventory item not found: " + testLotID.String(),
+		},
+		{
+			name: "find_restored_item_error",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().Restore(gomock.Any(), testLotID).Return(nil)
+				m.EXPECT().FindByID(gomock.Any(), testLotID).Return(nil, errors.New("database error"))
+			},
+			errorContains: "failed to get restored item",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockInventoryRepository(ctrl)
+			mockDB := mocks.NewMockPgxPool(ctrl)
+			service := services.NewInventoryService(mockRepo, mockDB, helpers.TestLogger())
+
+			tt.setupMocks(mockRepo)
+
+			item, err := service.RestoreItem(context.Background(), testLotID)
+
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testLotID, item.LotID)
+		})
+	}
+}
+
+func TestInventoryService_GetHistory(t *testing.T) {
+	testLotID := uuid.New()
+	history := []domain.AuditEntry{
+		{ID: 2, LotID: testLotID, Operation: domain.AuditUpdate},
+		{ID: 1, LotID: testLotID, Operation: domain.AuditCreate},
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.MockInventoryRepository)
+		expectedCount int
+		errorContains string
+	}{
+		{
+			name: "returns_history",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindHistory(gomock.Any(), testLotID).Return(history, nil)
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "existing_item_without_history",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindHistory(gomock.Any(), testLotID).Return([]domain.AuditEntry{}, nil)
+				m.EXPECT().FindByID(gomock.Any(), testLotID).
+					Return(&domain.InventoryItem{LotID: testLotID}, nil)
+			},
+		},
+		{
+			name: "unknown_item",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindHistory(gomock.Any(), testLotID).Return([]domain.AuditEntry{}, nil)
+				m.EXPECT().FindByID(gomock.Any(), testLotID).Return(nil, nil)
+			},
+			errorContains: "inventory item not found: " + testLotID.String(),
+		},
+		{
+			name: "repository_error",
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindHistory(gomock.Any(), testLotID).Return(nil, errors.New("database error"))
+			},
+			errorContains: "failed to get inventory history",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockInventoryRepository(ctrl)
+			mockDB := mocks.NewMockPgxPool(ctrl)
+			service := services.NewInventoryService(mockRepo, mockDB, helpers.TestLogger())
+
+			tt.setupMocks(mockRepo)
+
+			entries, err := service.GetHistory(context.Background(), testLotID)
+
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, entries, tt.expectedCount)
+		})
+	}
+}
+
+func TestInventoryService_BulkUpdateFields(t *testing.T) {
+	lotIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	location := "Warehouse B"
+	badDemand := domain.MarketDemandLevel("extreme")
+
+	tests := []struct {
+		name             string
+		lotIDs           []uuid.UUID
+		updates          ports.BulkFieldUpdates
+		setupMocks       func(*mocks.MockInventoryRepository)
+		expectedAffected int64
+		errorContains    string
+	}{
+		{
+			name:    "updates_items",
+			lotIDs:  lotIDs,
+			updates: ports.BulkFieldUpdates{StorageLocation: &location},
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().
+					BulkUpdateFields(gomock.Any(), lotIDs, ports.BulkFieldUpdates{StorageLocation: &location}).
+					Return(int64(2), nil)
+			},
+			expectedAffected: 2,
+		},
+		{
+			name:          "requires_lot_ids",
+			updates:       ports.BulkFieldUpdates{StorageLocation: &location},
+			setupMocks:    func(m *mocks.MockInventoryRepository) {},
+			errorContains: "at least one lot_id is required",
+		},
+		{
+			name:          "requires_updates",
+			lotIDs:        lotIDs,
+			setupMocks:    func(m *mocks.MockInventoryRepository) {},
+			errorContains: "at least one field to update is required",
+		},
+		{
+			name:          "rejects_unknown_market_demand",
+			lotIDs:        lotIDs,
+			updates:       ports.BulkFieldUpdates{MarketDemand: &badDemand},
+			setupMocks:    func(m *mocks.MockInventoryRepository) {},
+			errorContains: "unknown market_demand",
+		},
+		{
+			name:    "repository_error",
+			lotIDs:  lotIDs,
+			updates: ports.BulkFieldUpdates{StorageLocation: &location},
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().
+					BulkUpdateFields(gomock.Any(), lotIDs, gomock.Any()).
+					Return(int64(0), errors.New("database error"))
+			},
+			errorContains: "failed to bulk update items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockInventoryRepository(ctrl)
+			mockDB := mocks.NewMockPgxPool(ctrl)
+			service := services.NewInventoryService(mockRepo, mockDB, helpers.TestLogger())
+
+			tt.setupMocks(mockRepo)
+
+			affected, err := service.BulkUpdateFields(context.Background(), tt.lotIDs, tt.updates)
+
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAffected, affected)
+		})
+	}
+}
+
 // TestInventoryService_List validates the refactored List method which delegates querying to the repository.
 func TestInventoryService_List(t *testing.T) {
 	ctx := context.Background()
@@ -572,6 +979,35 @@ func TestInventoryService_List(t *testing.T) {
 			expectedError:      false,
 			expectedRepoParams: ports.ListParams{Page: 1, PageSize: 1000},
 		},
+		{
+			name:             "returns_next_cursor_for_full_page",
+			inputParams:      ports.ListParams{Page: 1, PageSize: 1},
+			mockRepoResponse: testItems,
+			mockRepoTotal:    5,
+			expectedResult: &ports.ListResult{
+				Items:      testItems,
+				Page:       1,
+				PageSize:   1,
+				TotalCount: 5,
+				TotalPages: 5,
+				NextCursor: ports.EncodeListCursor(testItems[0].CreatedAt, testItems[0].LotID),
+			},
+			expectedRepoParams: ports.ListParams{Page: 1, PageSize: 1},
+		},
+		{
+			name:             "omits_cursor_when_not_sorted_by_created_at",
+			inputParams:      ports.ListParams{Page: 1, PageSize: 1, SortBy: "name", SortOrder: "asc"},
+			mockRepoResponse: testItems,
+			mockRepoTotal:    5,
+			expectedResult: &ports.ListResult{
+				Items:      testItems,
+				Page:       1,
+				PageSize:   1,
+				TotalCount: 5,
+				TotalPages: 5,
+			},
+			expectedRepoParams: ports.ListParams{Page: 1, PageSize: 1, SortBy: "name", SortOrder: "asc"},
+		},
 		{
 			name:               "handles_repository_error",
 			inputParams:        ports.ListParams{Page: 1, PageSize: 10},
@@ -629,63 +1065,264 @@ func TestInventoryService_List(t *testing.T) {
 	}
 }
 
-func TestInventoryService_BulkUpsert(t *testing.T) {
-	// Create test items
-	items := helpers.CreateTestInventoryItems(250) // More than batch size
+func TestInventoryService_List_Totals(t *testing.T) {
+	ctx := context.Background()
+	params := ports.ListParams{Page: 1, PageSize: 10, Category: "glass", IncludeTotals: true}
 
-	t.Run("processes_items_in_batches", func(t *testing.T) {
-		// Setup
+	t.Run("includes_totals_when_requested", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockRepo := mocks.NewMockInventoryRepository(ctrl)
-		mockDB := mocks.NewMockPgxPool(ctrl)
-		logger := helpers.TestLogger()
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
 
-		service := services.NewInventoryService(mockRepo, mockDB, logger)
+		mockRepo.EXPECT().FindAll(ctx, params).Return([]*domain.InventoryItem{}, int64(3), nil)
+		mockRepo.EXPECT().SumTotals(ctx, params).Return(&ports.ListTotals{
+			TotalCost:      decimal.RequireFromString("412.75"),
+			EstimatedValue: decimal.NewFromInt(900),
+		}, nil)
 
-		// Expect multiple batch saves (250 items / 100 batch size = 3 batches)
-		mockRepo.EXPECT().
-			SaveBatch(gomock.Any(), gomock.Any()).
-			Times(3).
-			Return(nil)
+		result, err := service.List(ctx, params)
+		require.NoError(t, err)
+		require.NotNil(t, result.TotalCostSum)
+		require.NotNil(t, result.TotalEstimatedValueSum)
+		assert.Equal(t, "412.75", result.TotalCostSum.String())
+		assert.Equal(t, "900", result.TotalEstimatedValueSum.String())
+	})
 
-		// Execute
-		err := service.BulkUpsert(context.Background(), items)
+	t.Run("skips_totals_by_default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		// Assert
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		noTotals := params
+		noTotals.IncludeTotals = false
+		mockRepo.EXPECT().FindAll(ctx, noTotals).Return([]*domain.InventoryItem{}, int64(3), nil)
+
+		result, err := service.List(ctx, noTotals)
 		require.NoError(t, err)
+		assert.Nil(t, result.TotalCostSum)
+		assert.Nil(t, result.TotalEstimatedValueSum)
 	})
 
-	t.Run("handles_batch_errors", func(t *testing.T) {
-		// Setup
+	t.Run("totals_error", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockRepo := mocks.NewMockInventoryRepository(ctrl)
-		mockDB := mocks.NewMockPgxPool(ctrl)
-		logger := helpers.TestLogger()
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FindAll(ctx, params).Return([]*domain.InventoryItem{}, int64(3), nil)
+		mockRepo.EXPECT().SumTotals(ctx, params).Return(nil, errors.New("database error"))
+
+		_, err := service.List(ctx, params)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to sum inventory totals")
+	})
+}
+
+func TestInventoryService_List_Highlights(t *testing.T) {
+	ctx := context.Background()
+	item := helpers.CreateTestInventoryItem()
+	params := ports.ListParams{Page: 1, PageSize: 10, Search: "tea", Highlight: true}
+
+	t.Run("attaches_highlights_for_page_items", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FindAll(ctx, params).Return([]*domain.InventoryItem{item}, int64(1), nil)
+		mockRepo.EXPECT().SearchHighlights(ctx, "tea", []uuid.UUID{item.LotID}).
+			Return(map[uuid.UUID]string{item.LotID: "porcelain <mark>tea</mark> set"}, nil)
+
+		result, err := service.List(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, "porcelain <mark>tea</mark> set", result.Highlights[item.LotID])
+	})
+
+	t.Run("highlight_failure_does_not_fail_listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FindAll(ctx, params).Return([]*domain.InventoryItem{item}, int64(1), nil)
+		mockRepo.EXPECT().SearchHighlights(ctx, "tea", gomock.Any()).Return(nil, errors.New("database error"))
+
+		result, err := service.List(ctx, params)
+		require.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Nil(t, result.Highlights)
+	})
+
+	t.Run("skips_highlights_without_search", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		noSearch := ports.ListParams{Page: 1, PageSize: 10, Highlight: true}
+		mockRepo.EXPECT().FindAll(ctx, noSearch).Return([]*domain.InventoryItem{item}, int64(1), nil)
+
+		result, err := service.List(ctx, noSearch)
+		require.NoError(t, err)
+		assert.Nil(t, result.Highlights)
+	})
+}
+
+func TestInventoryService_Suggest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("trims_prefix_before_lookup", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().Suggest(ctx, "vict", 10).Return([]string{"victorian"}, nil)
+
+		suggestions, err := service.Suggest(ctx, "  vict ", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"victorian"}, suggestions)
+	})
+
+	t.Run("never_returns_nil", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().Suggest(ctx, "zz", 10).Return(nil, nil)
+
+		suggestions, err := service.Suggest(ctx, "zz", 10)
+		require.NoError(t, err)
+		assert.NotNil(t, suggestions)
+		assert.Empty(t, suggestions)
+
+		// Blank prefixes never reach the repository
+		suggestions, err = service.Suggest(ctx, "   ", 10)
+		require.NoError(t, err)
+		assert.NotNil(t, suggestions)
+	})
+
+	t.Run("repository_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().Suggest(ctx, "lamp", 10).Return(nil, errors.New("database error"))
+
+		_, err := service.Suggest(ctx, "lamp", 10)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get suggestions")
+	})
+}
+
+func TestInventoryService_BulkUpsert(t *testing.T) {
+	// Create test items
+	items := helpers.CreateTestInventoryItems(250) // More than batch size
+
+	t.Run("processes_items_in_batches", func(t *testing.T) {
+		// Setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		mockDB := mocks.NewMockPgxPool(ctrl)
+		logger := helpers.TestLogger()
+
+		service := services.NewInventoryService(mockRepo, mockDB, logger)
+
+		// Expect multiple batch saves (250 items / 100 batch size = 3 batches)
+		mockRepo.EXPECT().
+			SaveBatchUpsert(gomock.Any(), gomock.Any(), ports.ConflictOnInvoiceItem).
+			Times(3).
+			Return(nil)
+
+		// Execute
+		err := service.BulkUpsert(context.Background(), items, ports.ConflictOnInvoiceItem)
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("handles_batch_errors", func(t *testing.T) {
+		// Setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		mockDB := mocks.NewMockPgxPool(ctrl)
+		logger := helpers.TestLogger()
 
 		service := services.NewInventoryService(mockRepo, mockDB, logger)
 
 		// First batch succeeds, second batch fails
 		gomock.InOrder(
 			mockRepo.EXPECT().
-				SaveBatch(gomock.Any(), gomock.Any()).
+				SaveBatchUpsert(gomock.Any(), gomock.Any(), ports.ConflictOnLotID).
 				Return(nil),
 			mockRepo.EXPECT().
-				SaveBatch(gomock.Any(), gomock.Any()).
+				SaveBatchUpsert(gomock.Any(), gomock.Any(), ports.ConflictOnLotID).
 				Return(errors.New("batch 2 failed")),
 		)
 
 		// Execute
-		err := service.BulkUpsert(context.Background(), items)
+		err := service.BulkUpsert(context.Background(), items, ports.ConflictOnLotID)
 
 		// Assert
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "batch 2 failed")
 		assert.Contains(t, err.Error(), "100-200") // Batch range
 	})
+
+	t.Run("prepares_items_before_upserting", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		item := *helpers.CreateTestInventoryItem()
+		item.LotID = uuid.Nil
+		mockRepo.EXPECT().
+			SaveBatchUpsert(gomock.Any(), gomock.Len(1), ports.ConflictOnInvoiceItem).
+			DoAndReturn(func(_ context.Context, batch []domain.InventoryItem, _ ports.ConflictTarget) error {
+				assert.NotEqual(t, uuid.Nil, batch[0].LotID)
+				assert.False(t, batch[0].UpdatedAt.IsZero())
+				return nil
+			})
+
+		err := service.BulkUpsert(context.Background(), []domain.InventoryItem{item}, ports.ConflictOnInvoiceItem)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects_invalid_items", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		item := *helpers.CreateTestInventoryItem()
+		item.ItemName = ""
+
+		err := service.BulkUpsert(context.Background(), []domain.InventoryItem{item}, ports.ConflictOnLotID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
 }
 
 // Benchmarks
@@ -741,3 +1378,656 @@ func BenchmarkInventoryService_SaveItems(b *testing.B) {
 		_ = service.SaveItems(ctx, items)
 	}
 }
+
+func TestInventoryService_Reclassify(t *testing.T) {
+	ctx := context.Background()
+
+	// newItems returns a silver item filed under antiques, one the classifier can't place and
+	// one already classified correctly
+	newItems := func() []*domain.InventoryItem {
+		return []*domain.InventoryItem{
+			helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.Description = "Sterling silver candlesticks, damaged base"
+				i.Category = domain.CategoryAntiques
+				i.Condition = domain.ConditionExcellent
+			}),
+			helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.Description = "Miscellaneous box lot"
+			}),
+			helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.Description = "Sterling silver spoon"
+				i.Category = domain.CategorySilver
+			}),
+		}
+	}
+	batch := ports.ListParams{Page: 1, PageSize: 500, SortBy: "created_at", SortOrder: "desc"}
+
+	t.Run("dry_run_proposes_without_writing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		items := newItems()
+		mockRepo.EXPECT().FindAll(ctx, batch).Return(items, int64(len(items)), nil)
+
+		result, err := service.Reclassify(ctx, ports.ReclassifyParams{MinConfidence: 0.5, DryRun: true})
+		require.NoError(t, err)
+
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 3, result.Examined)
+		assert.Zero(t, result.Updated)
+		require.Len(t, result.Changes, 1)
+
+		change := result.Changes[0]
+		assert.Equal(t, items[0].LotID, change.LotID)
+		assert.Equal(t, domain.CategoryAntiques, change.OldCategory)
+		assert.Equal(t, domain.CategorySilver, change.NewCategory)
+		assert.Equal(t, domain.ConditionExcellent, change.OldCondition)
+		assert.Equal(t, domain.ConditionPoor, change.NewCondition)
+		assert.False(t, change.Applied)
+
+		// The item itself is untouched
+		assert.Equal(t, domain.CategoryAntiques, items[0].Category)
+	})
+
+	t.Run("apply_updates_confident_changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		items := newItems()
+		mockRepo.EXPECT().FindAll(ctx, batch).Return(items, int64(len(items)), nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, item *domain.InventoryItem) error {
+				assert.Equal(t, items[0].LotID, item.LotID)
+				assert.Equal(t, domain.CategorySilver, item.Category)
+				assert.Equal(t, domain.ConditionPoor, item.Condition)
+				return nil
+			})
+
+		result, err := service.Reclassify(ctx, ports.ReclassifyParams{MinConfidence: 0.5})
+		require.NoError(t, err)
+
+		assert.False(t, result.DryRun)
+		assert.Equal(t, 1, result.Updated)
+		require.Len(t, result.Changes, 1)
+		assert.True(t, result.Changes[0].Applied)
+		assert.Empty(t, result.Changes[0].Error)
+	})
+
+	t.Run("failed_update_is_reported_and_skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		items := newItems()
+		mockRepo.EXPECT().FindAll(ctx, batch).Return(items, int64(len(items)), nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(domain.ErrVersionConflict)
+
+		result, err := service.Reclassify(ctx, ports.ReclassifyParams{MinConfidence: 0.5})
+		require.NoError(t, err)
+
+		assert.Zero(t, result.Updated)
+		require.Len(t, result.Changes, 1)
+		assert.False(t, result.Changes[0].Applied)
+		assert.Contains(t, result.Changes[0].Error, domain.ErrVersionConflict.Error())
+	})
+
+	t.Run("threshold_leaves_low_confidence_items", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		items := newItems()
+		mockRepo.EXPECT().FindAll(ctx, batch).Return(items, int64(len(items)), nil)
+
+		// A confidence of exactly 1 never exceeds the threshold
+		result, err := service.Reclassify(ctx, ports.ReclassifyParams{MinConfidence: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Examined)
+		assert.Empty(t, result.Changes)
+	})
+
+	t.Run("pages_with_keyset_cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		full := make([]*domain.InventoryItem, 500)
+		for i := range full {
+			full[i] = helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+				i.Description = "Miscellaneous box lot"
+			})
+		}
+		last := full[len(full)-1]
+
+		filter := ports.ListParams{Category: string(domain.CategoryAntiques)}
+		first := batch
+		first.Category = filter.Category
+		next := first
+		next.AfterCreatedAt, next.AfterLotID = &last.CreatedAt, last.LotID
+
+		gomock.InOrder(
+			mockRepo.EXPECT().FindAll(ctx, first).Return(full, int64(501), nil),
+			mockRepo.EXPECT().FindAll(ctx, next).Return(newItems()[1:2], int64(501), nil),
+		)
+
+		result, err := service.Reclassify(ctx, ports.ReclassifyParams{Filter: filter, MinConfidence: 0.5, DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, 501, result.Examined)
+	})
+
+	t.Run("rejects_out_of_range_confidence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := services.NewInventoryService(mocks.NewMockInventoryRepository(ctrl), mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		_, err := service.Reclassify(ctx, ports.ReclassifyParams{MinConfidence: 1.5})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "min_confidence must be between 0 and 1")
+	})
+}
+
+func TestInventoryService_GetInvoiceItems(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("aggregates_invoice_items", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		items := []domain.InventoryItem{
+			{InvoiceID: "INV-7", BidAmount: decimal.NewFromFloat(10), TotalCost: decimal.NewFromFloat(12.50)},
+			{InvoiceID: "INV-7", BidAmount: decimal.NewFromFloat(20), TotalCost: decimal.NewFromFloat(24.99)},
+			{InvoiceID: "INV-7", BidAmount: decimal.NewFromFloat(5), TotalCost: decimal.NewFromFloat(6.01)},
+		}
+		mockRepo.EXPECT().FindByInvoiceID(ctx, "INV-7").Return(items, nil)
+
+		result, err := service.GetInvoiceItems(ctx, "INV-7")
+		require.NoError(t, err)
+		assert.Equal(t, "INV-7", result.InvoiceID)
+		assert.Equal(t, 3, result.ItemCount)
+		assert.Len(t, result.Items, 3)
+		assert.True(t, decimal.NewFromFloat(43.50).Equal(result.TotalCost), "total: %s", result.TotalCost)
+		assert.True(t, decimal.NewFromFloat(11.67).Equal(result.AverageBid), "average bid: %s", result.AverageBid)
+	})
+
+	t.Run("unknown_invoice_is_empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FindByInvoiceID(ctx, "INV-404").Return(nil, nil)
+
+		result, err := service.GetInvoiceItems(ctx, "INV-404")
+		require.NoError(t, err)
+		assert.NotNil(t, result.Items)
+		assert.Empty(t, result.Items)
+		assert.Zero(t, result.ItemCount)
+		assert.True(t, result.AverageBid.IsZero())
+	})
+
+	t.Run("repository_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FindByInvoiceID(ctx, "INV-7").Return(nil, errors.New("database error"))
+
+		_, err := service.GetInvoiceItems(ctx, "INV-7")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get items by invoice ID")
+	})
+}
+
+func TestInventoryService_GenerateQRCodes(t *testing.T) {
+	ctx := context.Background()
+	template := domain.QRTemplate("https://resell.example.com/inventory/{lot_id}")
+
+	t.Run("fills_missing_payloads", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FillQRCodes(ctx, template).Return(int64(7), nil)
+
+		filled, err := service.GenerateQRCodes(ctx, template)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), filled)
+	})
+
+	t.Run("invalid_template", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		_, err := service.GenerateQRCodes(ctx, "https://resell.example.com/inventory")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed: qr template must contain {lot_id}")
+	})
+
+	t.Run("repository_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().FillQRCodes(ctx, template).Return(int64(0), errors.New("database error"))
+
+		_, err := service.GenerateQRCodes(ctx, template)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to generate qr codes")
+	})
+}
+
+func TestInventoryService_ListInvoices(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pages_invoice_summaries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		params := ports.InvoiceListParams{Page: 2, PageSize: 2, SortBy: ports.InvoiceSortTotal, SortOrder: "asc"}
+		invoices := []ports.InvoiceSummary{
+			{InvoiceID: "INV-3", ItemCount: 2, TotalCost: decimal.NewFromFloat(40)},
+			{InvoiceID: "INV-4", ItemCount: 1, TotalCost: decimal.NewFromFloat(55)},
+		}
+		mockRepo.EXPECT().ListInvoices(ctx, params).Return(invoices, int64(5), nil)
+
+		result, err := service.ListInvoices(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, invoices, result.Invoices)
+		assert.Equal(t, 2, result.Page)
+		assert.Equal(t, int64(5), result.TotalCount)
+		assert.Equal(t, 3, result.TotalPages)
+	})
+
+	t.Run("defaults_and_empty_list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().
+			ListInvoices(ctx, ports.InvoiceListParams{Page: 1, PageSize: 50, SortBy: ports.InvoiceSortDate}).
+			Return(nil, int64(0), nil)
+
+		result, err := service.ListInvoices(ctx, ports.InvoiceListParams{})
+		require.NoError(t, err)
+		assert.NotNil(t, result.Invoices)
+		assert.Empty(t, result.Invoices)
+		assert.Zero(t, result.TotalPages)
+	})
+
+	t.Run("invalid_sort", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		_, err := service.ListInvoices(ctx, ports.InvoiceListParams{SortBy: "auction"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sort_by must be date or total")
+	})
+
+	t.Run("repository_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().ListInvoices(ctx, gomock.Any()).Return(nil, int64(0), errors.New("database error"))
+
+		_, err := service.ListInvoices(ctx, ports.InvoiceListParams{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list invoices")
+	})
+}
+
+func TestInventoryService_TimeSeries(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	// A week of sample data, Monday 4 March to Sunday 10 March 2024, with quiet days in between
+	week := []ports.TimeSeriesBucket{
+		{Start: day(4), Acquisitions: 2, Spend: decimal.NewFromInt(55)},
+		{Start: day(6), Acquisitions: 1, Spend: decimal.NewFromInt(20)},
+		{Start: day(8), ItemsSold: 1, NetProfit: decimal.NewFromInt(35)},
+		{Start: day(10), Acquisitions: 1, Spend: decimal.NewFromInt(12), ItemsSold: 2, NetProfit: decimal.NewFromInt(-4)},
+	}
+
+	t.Run("daily_buckets_fill_quiet_days", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		params := ports.TimeSeriesParams{From: day(4), To: day(11)}
+		mockRepo.EXPECT().
+			TimeSeries(ctx, ports.TimeSeriesParams{Interval: ports.IntervalDay, From: day(4), To: day(11)}).
+			Return(week, nil)
+
+		series, err := service.TimeSeries(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, ports.IntervalDay, series.Interval)
+		assert.Equal(t, []time.Time{day(4), day(5), day(6), day(7), day(8), day(9), day(10)}, series.Buckets)
+		assert.Equal(t, []int{2, 0, 1, 0, 0, 0, 1}, series.Acquisitions)
+		assert.Equal(t, []int{0, 0, 0, 0, 1, 0, 2}, series.ItemsSold)
+		for i, want := range []int64{55, 0, 20, 0, 0, 0, 12} {
+			assert.True(t, decimal.NewFromInt(want).Equal(series.Spend[i]), "spend[%d] %s", i, series.Spend[i])
+		}
+		for i, want := range []int64{0, 0, 0, 0, 35, 0, -4} {
+			assert.True(t, decimal.NewFromInt(want).Equal(series.NetProfit[i]), "net_profit[%d] %s", i, series.NetProfit[i])
+		}
+	})
+
+	t.Run("bucket_boundaries", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			interval string
+			from, to time.Time
+			want     []time.Time
+		}{
+			{
+				name:     "days_start_at_midnight_utc",
+				interval: ports.IntervalDay,
+				from:     time.Date(2024, 3, 4, 15, 30, 0, 0, time.FixedZone("EST", -5*3600)),
+				to:       time.Date(2024, 3, 6, 0, 0, 0, 1, time.UTC),
+				want:     []time.Time{day(4), day(5), day(6)},
+			},
+			{
+				name:     "weeks_start_on_monday",
+				interval: ports.IntervalWeek,
+				from:     day(6), // Wednesday
+				to:       day(12),
+				want:     []time.Time{day(4), day(11)},
+			},
+			{
+				name:     "sunday_belongs_to_the_week_before",
+				interval: ports.IntervalWeek,
+				from:     day(10),
+				to:       day(11),
+				want:     []time.Time{day(4)},
+			},
+			{
+				name:     "months_start_on_the_first",
+				interval: ports.IntervalMonth,
+				from:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				to:       day(4),
+				want: []time.Time{
+					time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				mockRepo := mocks.NewMockInventoryRepository(ctrl)
+				service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+				mockRepo.EXPECT().TimeSeries(ctx, gomock.Any()).Return([]ports.TimeSeriesBucket{}, nil)
+
+				series, err := service.TimeSeries(ctx, ports.TimeSeriesParams{Interval: tt.interval, From: tt.from, To: tt.to})
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, series.Buckets)
+				assert.Len(t, series.Acquisitions, len(tt.want))
+				assert.Len(t, series.NetProfit, len(tt.want))
+			})
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			params        ports.TimeSeriesParams
+			errorContains string
+		}{
+			{
+				name:          "unknown_interval",
+				params:        ports.TimeSeriesParams{Interval: "hour", From: day(4), To: day(5)},
+				errorContains: "interval must be day, week or month",
+			},
+			{
+				name:          "empty_range",
+				params:        ports.TimeSeriesParams{From: day(5), To: day(5)},
+				errorContains: "from must be before to",
+			},
+			{
+				name:          "too_many_buckets",
+				params:        ports.TimeSeriesParams{From: day(1), To: day(1).AddDate(3, 0, 0)},
+				errorContains: "more than 1000 day buckets",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				mockRepo := mocks.NewMockInventoryRepository(ctrl)
+				service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+				_, err := service.TimeSeries(ctx, tt.params)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "validation failed")
+				assert.Contains(t, err.Error(), tt.errorContains)
+			})
+		}
+	})
+
+	t.Run("repository_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().TimeSeries(ctx, gomock.Any()).Return(nil, errors.New("database error"))
+
+		_, err := service.TimeSeries(ctx, ports.TimeSeriesParams{From: day(4), To: day(11)})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load time series")
+	})
+}
+
+func TestInventoryService_StorageSummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nests_bins_under_locations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		bins := []ports.StorageBinSummary{
+			{Location: "Garage", Bin: "A1", ItemCount: 2, EstimatedValue: decimal.NewFromInt(150)},
+			{Location: "Garage", Bin: ports.UnassignedStorage, ItemCount: 1, EstimatedValue: decimal.NewFromInt(20)},
+			{Location: ports.UnassignedStorage, Bin: "B2", ItemCount: 1, EstimatedValue: decimal.NewFromInt(7)},
+			{Location: ports.UnassignedStorage, Bin: ports.UnassignedStorage, ItemCount: 3, EstimatedValue: decimal.NewFromInt(15)},
+		}
+		mockRepo.EXPECT().StorageSummary(ctx).Return(bins, nil)
+
+		summary, err := service.StorageSummary(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 7, summary.ItemCount)
+		assert.True(t, decimal.NewFromInt(192).Equal(summary.EstimatedValue), "total %s", summary.EstimatedValue)
+
+		require.Len(t, summary.Locations, 2)
+		garage, unassigned := summary.Locations[0], summary.Locations[1]
+		assert.Equal(t, "Garage", garage.Location)
+		assert.Equal(t, 3, garage.ItemCount)
+		assert.True(t, decimal.NewFromInt(170).Equal(garage.EstimatedValue), "garage %s", garage.EstimatedValue)
+		assert.Equal(t, bins[:2], garage.Bins)
+
+		assert.Equal(t, ports.UnassignedStorage, unassigned.Location)
+		assert.Equal(t, 4, unassigned.ItemCount)
+		assert.True(t, decimal.NewFromInt(22).Equal(unassigned.EstimatedValue), "unassigned %s", unassigned.EstimatedValue)
+		assert.Equal(t, bins[2:], unassigned.Bins)
+	})
+
+	t.Run("empty_inventory", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().StorageSummary(ctx).Return([]ports.StorageBinSummary{}, nil)
+
+		summary, err := service.StorageSummary(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, summary.Locations)
+		assert.Empty(t, summary.Locations)
+		assert.Zero(t, summary.ItemCount)
+		assert.True(t, summary.EstimatedValue.IsZero())
+	})
+
+	t.Run("repository_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockInventoryRepository(ctrl)
+		service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+		mockRepo.EXPECT().StorageSummary(ctx).Return(nil, errors.New("database error"))
+
+		_, err := service.StorageSummary(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to summarize storage")
+	})
+}
+
+func TestInventoryService_PatchItem(t *testing.T) {
+	current := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+		i.Notes = "keep me"
+		i.StorageBin = "A1"
+		i.Version = 4
+	})
+	patch := ports.InventoryPatch{
+		StorageLocation: ports.Some("Garage"),
+		StorageBin:      ports.Null[string](),
+	}
+
+	tests := []struct {
+		name          string
+		patch         ports.InventoryPatch
+		setupMocks    func(*mocks.MockInventoryRepository)
+		errorContains string
+	}{
+		{
+			name:  "writes_only_the_set_fields",
+			patch: patch,
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindByID(gomock.Any(), current.LotID).Return(current, nil)
+				m.EXPECT().
+					UpdatePartial(gomock.Any(), current.LotID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, got ports.InventoryPatch) (*domain.InventoryItem, error) {
+						assert.Equal(t, "Garage", *got.StorageLocation.Value)
+						assert.True(t, got.StorageBin.IsNull())
+						assert.False(t, got.Notes.Set)
+
+						stored := *current
+						got.Apply(&stored)
+						return &stored, nil
+					})
+			},
+		},
+		{
+			name:          "requires_a_field",
+			setupMocks:    func(m *mocks.MockInventoryRepository) {},
+			errorContains: "at least one field to update is required",
+		},
+		{
+			name:          "rejects_null_required_field",
+			patch:         ports.InventoryPatch{ItemName: ports.Null[string]()},
+			setupMocks:    func(m *mocks.MockInventoryRepository) {},
+			errorContains: "item_name cannot be null",
+		},
+		{
+			name:  "validates_the_patched_item",
+			patch: ports.InventoryPatch{Quantity: ports.Some(0)},
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindByID(gomock.Any(), current.LotID).Return(current, nil)
+			},
+			errorContains: "validation failed: quantity must be positive",
+		},
+		{
+			name:  "item_not_found",
+			patch: patch,
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindByID(gomock.Any(), current.LotID).Return(nil, nil)
+			},
+			errorContains: "inventory item not found",
+		},
+		{
+			name:  "version_conflict",
+			patch: ports.InventoryPatch{Notes: ports.Some("new"), Version: 3},
+			setupMocks: func(m *mocks.MockInventoryRepository) {
+				m.EXPECT().FindByID(gomock.Any(), current.LotID).Return(current, nil)
+				m.EXPECT().
+					UpdatePartial(gomock.Any(), current.LotID, gomock.Any()).
+					Return(nil, domain.ErrVersionConflict)
+			},
+			errorContains: domain.ErrVersionConflict.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockInventoryRepository(ctrl)
+			service := services.NewInventoryService(mockRepo, mocks.NewMockPgxPool(ctrl), helpers.TestLogger())
+
+			tt.setupMocks(mockRepo)
+
+			item, err := service.PatchItem(context.Background(), current.LotID, tt.patch)
+
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Garage", item.StorageLocation)
+			assert.Empty(t, item.StorageBin)
+			assert.Equal(t, "keep me", item.Notes)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// internal/core/services/listing.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// ListingService manages listings of inventory items on external platforms
+type ListingService struct {
+	listings   ports.ListingRepository
+	inventory  ports.InventoryRepository
+	categories ports.CategoryMapper
+	logger     *slog.Logger
+}
+
+// Statically assert that *ListingService implements the ListingService interface
+var _ ports.ListingService = (*ListingService)(nil)
+
+// NewListingService creates a new listing service instance
+func NewListingService(listings ports.ListingRepository, inventory ports.InventoryRepository, categories ports.CategoryMapper, logger *slog.Logger) *ListingService {
+	return &ListingService{
+		listings:   listings,
+		inventory:  inventory,
+		categories: categories,
+		logger:     logger.With(slog.String("service", "listing")),
+	}
+}
+
+// CreateListing creates a draft listing for an existing, non-deleted inventory item.
+// The title and category default from the item when not supplied.
+func (s *ListingService) CreateListing(ctx context.Context, listing *domain.PlatformListing) error {
+	if err := checkPlatformSupported(listing.Platform); err != nil {
+		return err
+	}
+
+	// FindByID excludes soft-deleted items, so a deleted item is reported as not found
+	item, err := s.inventory.FindByID(ctx, listing.LotID)
+	if err != nil {
+		return fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	if item == nil {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, listing.LotID)
+	}
+
+	if listing.Title == "" {
+		listing.Title = truncateRunes(item.ItemName, domain.EbayTitleMaxLength)
+	}
+	if listing.CategoryID == "" {
+		categoryID, ok := s.categories.MapToEbay(item.Category)
+		if !ok {
+			s.logger.WarnContext(ctx, "no eBay mapping for category, using fallback",
+				slog.String("category", string(item.Category)),
+				slog.String("ebay_category_id", categoryID))
+		}
+		listing.CategoryID = categoryID
+	}
+	listing.Status = domain.StatusDraft
+
+	if err := listing.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.listings.Create(ctx, listing); err != nil {
+		return fmt.Errorf("failed to create listing: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "created platform listing",
+		slog.String("id", listing.ID.String()),
+		slog.String("lot_id", listing.LotID.String()),
+		slog.String("platform", string(listing.Platform)))
+
+	return nil
+}
+
+// UpdateListing applies a partial update to an existing listing on the given platform
+func (s *ListingService) UpdateListing(ctx context.Context, platform domain.Platform, id uuid.UUID, update ports.ListingUpdate) (*domain.PlatformListing, error) {
+	if err := checkPlatformSupported(platform); err != nil {
+		return nil, err
+	}
+
+	listing, err := s.listings.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing: %w", err)
+	}
+	// A listing on another platform is treated the same as a missing one
+	if listing == nil || listing.Platform != platform {
+		return nil, fmt.Errorf("listing not found: %s", id)
+	}
+
+	if update.ListPrice != nil {
+		listing.ListPrice = *update.ListPrice
+	}
+	if update.Title != nil {
+		listing.Title = *update.Title
+	}
+	if update.Description != nil {
+		listing.Description = *update.Description
+	}
+	if update.CategoryID != nil {
+		listing.CategoryID = *update.CategoryID
+	}
+
+	if err := listing.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.listings.Update(ctx, listing); err != nil {
+		return nil, fmt.Errorf("failed to update listing: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "updated platform listing",
+		slog.String("id", id.String()),
+		slog.String("platform", string(platform)))
+
+	return listing, nil
+}
+
+// ListListings returns a page of stored listings for a platform
+func (s *ListingService) ListListings(ctx context.Context, platform domain.Platform, page, pageSize int) (*ports.ListingListResult, error) {
+	if err := checkPlatformSupported(platform); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+
+	listings, total, err := s.listings.FindByPlatform(ctx, platform, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list listings: %w", err)
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &ports.ListingListResult{
+		Listings:   listings,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RecordSale records a sale of an existing, non-deleted inventory item on any platform.
+// Sales are not limited to platforms with listing support since items also sell locally.
+func (s *ListingService) RecordSale(ctx context.Context, sale *domain.Sale) (*domain.PlatformListing, error) {
+	if sale.SoldAt.IsZero() {
+		sale.SoldAt = time.Now()
+	}
+
+	if err := sale.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	item, err := s.inventory.FindByID(ctx, sale.LotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, sale.LotID)
+	}
+
+	listing, err := s.listings.RecordSale(ctx, sale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record sale: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "recorded sale",
+		slog.String("lot_id", sale.LotID.String()),
+		slog.String("platform", string(sale.Platform)),
+		slog.String("sale_price", sale.SalePrice.String()))
+
+	return listing, nil
+}
+
+// checkPlatformSupported returns an error for platforms without listing support
+func checkPlatformSupported(platform domain.Platform) error {
+	if platform != domain.PlatformEbay {
+		return fmt.Errorf("unsupported platform: %s", platform)
+	}
+	return nil
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,392 @@
+// internal/core/services/listing_service_test.go
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/core/services"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestListingService_CreateListing(t *testing.T) {
+	item := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+		i.ItemName = strings.Repeat("Victorian ", 10)
+		i.Category = domain.CategoryFurniture
+	})
+
+	tests := []struct {
+		name          string
+		listing       *domain.PlatformListing
+		setupMocks    func(*mocks.MockListingRepository, *mocks.MockInventoryRepository, *mocks.MockCategoryMapper)
+		expectedError bool
+		errorContains string
+		validate      func(*testing.T, *domain.PlatformListing)
+	}{
+		{
+			name: "creates_draft_with_defaults_from_item",
+			listing: &domain.PlatformListing{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				ListPrice: decimal.NewFromInt(120),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				c.EXPECT().MapToEbay(domain.CategoryFurniture).Return("3197", true)
+				l.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			validate: func(t *testing.T, l *domain.PlatformListing) {
+				assert.Equal(t, domain.StatusDraft, l.Status)
+				assert.Equal(t, "3197", l.CategoryID)
+				assert.Len(t, []rune(l.Title), domain.EbayTitleMaxLength)
+			},
+		},
+		{
+			name: "unmapped_category_uses_fallback",
+			listing: &domain.PlatformListing{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				ListPrice: decimal.NewFromInt(120),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				c.EXPECT().MapToEbay(domain.CategoryFurniture).Return("99", false)
+				l.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			validate: func(t *testing.T, l *domain.PlatformListing) {
+				assert.Equal(t, "99", l.CategoryID)
+			},
+		},
+		{
+			name: "keeps_supplied_title_and_category",
+			listing: &domain.PlatformListing{
+				LotID:      item.LotID,
+				Platform:   domain.PlatformEbay,
+				ListPrice:  decimal.NewFromInt(120),
+				Title:      "Oak Side Table",
+				CategoryID: "38199",
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				l.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			validate: func(t *testing.T, l *domain.PlatformListing) {
+				assert.Equal(t, "Oak Side Table", l.Title)
+				assert.Equal(t, "38199", l.CategoryID)
+			},
+		},
+		{
+			name: "missing_or_deleted_item",
+			listing: &domain.PlatformListing{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				ListPrice: decimal.NewFromInt(120),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(nil, nil)
+			},
+			expectedError: true,
+			errorContains: "inventory item not found",
+		},
+		{
+			name: "unsupported_platform",
+			listing: &domain.PlatformListing{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEtsy,
+				ListPrice: decimal.NewFromInt(120),
+			},
+			setupMocks:    func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {},
+			expectedError: true,
+			errorContains: "unsupported platform: etsy",
+		},
+		{
+			name: "title_too_long_for_ebay",
+			listing: &domain.PlatformListing{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				ListPrice: decimal.NewFromInt(120),
+				Title:     strings.Repeat("x", 81),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				c.EXPECT().MapToEbay(gomock.Any()).Return("3197", true)
+			},
+			expectedError: true,
+			errorContains: "title cannot exceed 80 characters",
+		},
+		{
+			name: "repository_error",
+			listing: &domain.PlatformListing{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				ListPrice: decimal.NewFromInt(120),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository, c *mocks.MockCategoryMapper) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				c.EXPECT().MapToEbay(gomock.Any()).Return("3197", true)
+				l.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: true,
+			errorContains: "failed to create listing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockListings := mocks.NewMockListingRepository(ctrl)
+			mockInventory := mocks.NewMockInventoryRepository(ctrl)
+			mockMapper := mocks.NewMockCategoryMapper(ctrl)
+			tt.setupMocks(mockListings, mockInventory, mockMapper)
+
+			service := services.NewListingService(mockListings, mockInventory, mockMapper, helpers.TestLogger())
+			err := service.CreateListing(context.Background(), tt.listing)
+
+			if tt.expectedError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.validate != nil {
+				tt.validate(t, tt.listing)
+			}
+		})
+	}
+}
+
+func TestListingService_UpdateListing(t *testing.T) {
+	id := uuid.New()
+	newPrice := decimal.NewFromInt(95)
+	newTitle := "Updated Title"
+
+	existing := func() *domain.PlatformListing {
+		return &domain.PlatformListing{
+			ID:        id,
+			LotID:     uuid.New(),
+			Platform:  domain.PlatformEbay,
+			Status:    domain.StatusDraft,
+			ListPrice: decimal.NewFromInt(120),
+			Title:     "Original Title",
+		}
+	}
+
+	tests := []struct {
+		name          string
+		update        ports.ListingUpdate
+		setupMocks    func(*mocks.MockListingRepository)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name:   "applies_partial_update",
+			update: ports.ListingUpdate{ListPrice: &newPrice, Title: &newTitle},
+			setupMocks: func(m *mocks.MockListingRepository) {
+				m.EXPECT().FindByID(gomock.Any(), id).Return(existing(), nil)
+				m.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, l *domain.PlatformListing) error {
+						assert.True(t, newPrice.Equal(l.ListPrice))
+						assert.Equal(t, newTitle, l.Title)
+						return nil
+					})
+			},
+		},
+		{
+			name:   "listing_not_found",
+			update: ports.ListingUpdate{Title: &newTitle},
+			setupMocks: func(m *mocks.MockListingRepository) {
+				m.EXPECT().FindByID(gomock.Any(), id).Return(nil, nil)
+			},
+			expectedError: true,
+			errorContains: "listing not found",
+		},
+		{
+			name:   "listing_on_other_platform_is_not_found",
+			update: ports.ListingUpdate{Title: &newTitle},
+			setupMocks: func(m *mocks.MockListingRepository) {
+				l := existing()
+				l.Platform = domain.PlatformEtsy
+				m.EXPECT().FindByID(gomock.Any(), id).Return(l, nil)
+			},
+			expectedError: true,
+			errorContains: "listing not found",
+		},
+		{
+			name:   "rejects_non_positive_price",
+			update: ports.ListingUpdate{ListPrice: &decimal.Zero},
+			setupMocks: func(m *mocks.MockListingRepository) {
+				m.EXPECT().FindByID(gomock.Any(), id).Return(existing(), nil)
+			},
+			expectedError: true,
+			errorContains: "list_price must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockListings := mocks.NewMockListingRepository(ctrl)
+			tt.setupMocks(mockListings)
+
+			service := services.NewListingService(mockListings, mocks.NewMockInventoryRepository(ctrl), mocks.NewMockCategoryMapper(ctrl), helpers.TestLogger())
+			listing, err := service.UpdateListing(context.Background(), domain.PlatformEbay, id, tt.update)
+
+			if tt.expectedError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, id, listing.ID)
+		})
+	}
+}
+
+func TestListingService_ListListings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockListings := mocks.NewMockListingRepository(ctrl)
+	mockListings.EXPECT().
+		FindByPlatform(gomock.Any(), domain.PlatformEbay, 2, 10).
+		Return([]*domain.PlatformListing{{ID: uuid.New()}}, int64(11), nil)
+
+	service := services.NewListingService(mockListings, mocks.NewMockInventoryRepository(ctrl), mocks.NewMockCategoryMapper(ctrl), helpers.TestLogger())
+	result, err := service.ListListings(context.Background(), domain.PlatformEbay, 2, 10)
+
+	require.NoError(t, err)
+	assert.Len(t, result.Listings, 1)
+	assert.Equal(t, int64(11), result.TotalCount)
+	assert.Equal(t, 2, result.TotalPages)
+}
+
+func TestListingService_RecordSale(t *testing.T) {
+	item := helpers.CreateTestInventoryItem()
+	soldAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		sale          *domain.Sale
+		setupMocks    func(*mocks.MockListingRepository, *mocks.MockInventoryRepository)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name: "records_sale",
+			sale: &domain.Sale{
+				LotID:           item.LotID,
+				Platform:        domain.PlatformEbay,
+				SalePrice:       decimal.NewFromInt(250),
+				ShippingCharged: decimal.NewFromInt(15),
+				SoldAt:          soldAt,
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				l.EXPECT().
+					RecordSale(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s *domain.Sale) (*domain.PlatformListing, error) {
+						assert.Equal(t, soldAt, s.SoldAt)
+						price := s.SalePrice
+						return &domain.PlatformListing{LotID: s.LotID, Status: domain.StatusSold, SoldPrice: &price}, nil
+					})
+			},
+		},
+		{
+			name: "defaults_sold_at_to_now",
+			sale: &domain.Sale{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformLocal,
+				SalePrice: decimal.Zero,
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				l.EXPECT().
+					RecordSale(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s *domain.Sale) (*domain.PlatformListing, error) {
+						assert.WithinDuration(t, time.Now(), s.SoldAt, time.Minute)
+						return &domain.PlatformListing{LotID: s.LotID, Status: domain.StatusSold}, nil
+					})
+			},
+		},
+		{
+			name: "negative_sale_price",
+			sale: &domain.Sale{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				SalePrice: decimal.NewFromInt(-1),
+			},
+			setupMocks:    func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository) {},
+			expectedError: true,
+			errorContains: "sale_price cannot be negative",
+		},
+		{
+			name: "missing_or_deleted_item",
+			sale: &domain.Sale{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				SalePrice: decimal.NewFromInt(250),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(nil, nil)
+			},
+			expectedError: true,
+			errorContains: "inventory item not found",
+		},
+		{
+			name: "repository_error",
+			sale: &domain.Sale{
+				LotID:     item.LotID,
+				Platform:  domain.PlatformEbay,
+				SalePrice: decimal.NewFromInt(250),
+			},
+			setupMocks: func(l *mocks.MockListingRepository, i *mocks.MockInventoryRepository) {
+				i.EXPECT().FindByID(gomock.Any(), item.LotID).Return(item, nil)
+				l.EXPECT().RecordSale(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedError: true,
+			errorContains: "failed to record sale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockListings := mocks.NewMockListingRepository(ctrl)
+			mockInventory := mocks.NewMockInventoryRepository(ctrl)
+			tt.setupMocks(mockListings, mockInventory)
+
+			service := services.NewListingService(mockListings, mockInventory, mocks.NewMockCategoryMapper(ctrl), helpers.TestLogger())
+			listing, err := service.RecordSale(context.Background(), tt.sale)
+
+			if tt.expectedError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, domain.StatusSold, listing.Status)
+		})
+	}
+}
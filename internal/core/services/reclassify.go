@@ -0,0 +1,103 @@
+// internal/core/services/reclassify.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// reclassifyBatchSize is how many items Reclassify reads per query
+const reclassifyBatchSize = 500
+
+// Reclassify re-runs the category classifier over the descriptions of the items matching
+// params.Filter. An item whose best category scores above params.MinConfidence takes that
+// category, and the condition its description mentions, if any. Changes are saved as normal
+// versioned updates, so they are audited and an item edited meanwhile is left alone.
+func (s *InventoryService) Reclassify(ctx context.Context, params ports.ReclassifyParams) (*ports.ReclassifyResult, error) {
+	if params.MinConfidence < 0 || params.MinConfidence > 1 {
+		return nil, fmt.Errorf("validation failed: min_confidence must be between 0 and 1")
+	}
+
+	// Walk the matching items newest first with the keyset cursor, which stays correct while
+	// updates move items out of a category filter
+	filter := params.Filter
+	filter.Page, filter.PageSize = 1, reclassifyBatchSize
+	filter.SortBy, filter.SortOrder = "created_at", "desc"
+	filter.AfterCreatedAt, filter.AfterLotID = nil, uuid.Nil
+	filter.IncludeTotals, filter.Highlight = false, false
+
+	result := &ports.ReclassifyResult{DryRun: params.DryRun, Changes: []ports.ReclassifyChange{}}
+	for {
+		items, _, err := s.repo.FindAll(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list items to reclassify: %w", err)
+		}
+
+		for _, item := range items {
+			result.Examined++
+			change, ok := s.proposeClassification(item, params.MinConfidence)
+			if !ok {
+				continue
+			}
+
+			if !params.DryRun {
+				item.Category, item.Condition = change.NewCategory, change.NewCondition
+				if err := s.UpdateItem(ctx, item.LotID, item); err != nil {
+					change.Error = err.Error()
+				} else {
+					change.Applied = true
+					result.Updated++
+				}
+			}
+			result.Changes = append(result.Changes, change)
+		}
+
+		if len(items) < filter.PageSize {
+			break
+		}
+		last := items[len(items)-1]
+		createdAt := last.CreatedAt
+		filter.AfterCreatedAt, filter.AfterLotID = &createdAt, last.LotID
+	}
+
+	s.logger.InfoContext(ctx, "reclassified inventory items",
+		slog.Bool("dry_run", params.DryRun),
+		slog.Int("examined", result.Examined),
+		slog.Int("changes", len(result.Changes)),
+		slog.Int("updated", result.Updated))
+
+	return result, nil
+}
+
+// proposeClassification returns the change the classifier suggests for item, if it is
+// confident enough and the suggestion differs from what is stored
+func (s *InventoryService) proposeClassification(item *domain.InventoryItem, minConfidence float64) (ports.ReclassifyChange, bool) {
+	scores, condition, confidence := s.classifier.ClassifyWithScores(item.Description)
+	if len(scores) == 0 || confidence <= minConfidence {
+		return ports.ReclassifyChange{}, false
+	}
+
+	change := ports.ReclassifyChange{
+		LotID:        item.LotID,
+		ItemName:     item.ItemName,
+		OldCategory:  item.Category,
+		NewCategory:  scores[0].Category,
+		OldCondition: item.Condition,
+		NewCondition: item.Condition,
+		Confidence:   confidence,
+	}
+	// A description that doesn't mention the condition keeps the one recorded
+	if condition != domain.ConditionUnknown {
+		change.NewCondition = condition
+	}
+
+	if change.NewCategory == change.OldCategory && change.NewCondition == change.OldCondition {
+		return ports.ReclassifyChange{}, false
+	}
+	return change, true
+}
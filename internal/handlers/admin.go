@@ -0,0 +1,132 @@
+// internal/handlers/admin.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/workers"
+)
+
+// refreshViewsLockTTL bounds how long a crashed worker can block new refreshes
+const refreshViewsLockTTL = 15 * time.Minute
+
+// refreshViewsLockKey guards against more than one view refresh in flight
+var refreshViewsLockKey = redis_a.BuildKey(redis_a.PrefixLock, "refresh_views")
+
+// AdminHandler handles administrative operations
+type AdminHandler struct {
+	db          ports.Database
+	cache       ports.CacheRepository
+	asynqClient *asynq.Client
+	logger      *slog.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(db ports.Database, cache ports.CacheRepository, asynqClient *asynq.Client, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		db:          db,
+		cache:       cache,
+		asynqClient: asynqClient,
+		logger:      logger.With(slog.String("handler", "admin")),
+	}
+}
+
+// RefreshViews handles POST /api/v1/admin/refresh-views
+func (h *AdminHandler) RefreshViews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := uuid.New().String()
+
+	acquired, err := h.cache.SetNX(ctx, refreshViewsLockKey, jobID, refreshViewsLockTTL)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to acquire refresh lock", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to queue view refresh")
+		return
+	}
+
+	if !acquired {
+		// Point the caller at the refresh that is already running
+		var current string
+		if err := h.cache.Get(ctx, refreshViewsLockKey, &current); err != nil {
+			h.logger.WarnContext(ctx, "failed to read refresh lock owner", slog.String("error", err.Error()))
+		}
+		h.respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":  "A view refresh is already in progress",
+			"job_id": current,
+		})
+		return
+	}
+
+	payload := workers.RefreshAnalyticsPayload{JobID: jobID, LockKey: refreshViewsLockKey}
+	if err := h.queueRefresh(ctx, payload); err != nil {
+		h.logger.ErrorContext(ctx, "failed to queue view refresh",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()))
+
+		if _, err := h.cache.CompareAndDelete(ctx, refreshViewsLockKey, jobID); err != nil {
+			h.logger.WarnContext(ctx, "failed to release refresh lock", slog.String("error", err.Error()))
+		}
+		h.respondError(w, http.StatusInternalServerError, "Failed to queue view refresh")
+		return
+	}
+
+	h.logger.InfoContext(ctx, "view refresh queued", slog.String("job_id", jobID))
+
+	h.respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"job_id":  jobID,
+		"status":  "queued",
+		"message": "View refresh has been queued",
+	})
+}
+
+// queueRefresh records the job so it can be polled via /import/status and enqueues it
+func (h *AdminHandler) queueRefresh(ctx context.Context, payload workers.RefreshAnalyticsPayload) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO async_jobs (id, job_type, payload, status, created_at)
+		VALUES ($1, $2, $3, 'queued', CURRENT_TIMESTAMP)`
+
+	if _, err := h.db.Exec(ctx, query, payload.JobID, workers.TypeRefreshAnalytics, b); err != nil {
+		return fmt.Errorf("failed to insert job record: %w", err)
+	}
+
+	task := asynq.NewTask(workers.TypeRefreshAnalytics, b)
+	if _, err := h.asynqClient.EnqueueContext(ctx, task,
+		asynq.Queue("default"),
+		asynq.MaxRetry(2),
+		asynq.Retention(24*time.Hour)); err != nil {
+		errMsg := err.Error()
+		_, _ = h.db.Exec(ctx, `UPDATE async_jobs SET status = 'failed', error = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+			payload.JobID, errMsg)
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	return nil
+}
+
+func (h *AdminHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *AdminHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
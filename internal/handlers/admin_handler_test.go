@@ -0,0 +1,178 @@
+// internal/handlers/admin_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestAdminHandler_RefreshViews(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockDatabase, *mocks.MockCacheRepository)
+		expectedStatus int
+		validateBody   func(*testing.T, map[string]interface{})
+		expectQueued   bool
+	}{
+		{
+			name: "queues_refresh",
+			setupMocks: func(db *mocks.MockDatabase, c *mocks.MockCacheRepository) {
+				c.EXPECT().SetNX(gomock.Any(), "lock:refresh_views", gomock.Any(), 15*time.Minute).Return(true, nil)
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), workers.TypeRefreshAnalytics, gomock.Any()).
+					Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+			},
+			expectedStatus: http.StatusAccepted,
+			validateBody: func(t *testing.T, body map[string]interface{}) {
+				assert.NotEmpty(t, body["job_id"])
+				assert.Equal(t, "queued", body["status"])
+			},
+			expectQueued: true,
+		},
+		{
+			name: "refresh_already_in_progress",
+			setupMocks: func(db *mocks.MockDatabase, c *mocks.MockCacheRepository) {
+				c.EXPECT().SetNX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				c.EXPECT().
+					Get(gomock.Any(), "lock:refresh_views", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, key string, dest any) error {
+						*(dest.(*string)) = "running-job"
+						return nil
+					})
+			},
+			expectedStatus: http.StatusConflict,
+			validateBody: func(t *testing.T, body map[string]interface{}) {
+				assert.Equal(t, "running-job", body["job_id"])
+				assert.Equal(t, "A view refresh is already in progress", body["error"])
+			},
+		},
+		{
+			name: "lock_error",
+			setupMocks: func(db *mocks.MockDatabase, c *mocks.MockCacheRepository) {
+				c.EXPECT().SetNX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errors.New("redis down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "job_record_error_releases_lock",
+			setupMocks: func(db *mocks.MockDatabase, c *mocks.MockCacheRepository) {
+				c.EXPECT().SetNX(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(pgconn.CommandTag{}, errors.New("database error"))
+				c.EXPECT().CompareAndDelete(gomock.Any(), "lock:refresh_views", gomock.Any()).Return(true, nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			testRedis := helpers.SetupTestRedis(t)
+			redisOpt := asynq.RedisClientOpt{Addr: testRedis.Server.Addr()}
+			client := asynq.NewClient(redisOpt)
+			defer client.Close()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockCache := mocks.NewMockCacheRepository(ctrl)
+			tt.setupMocks(mockDB, mockCache)
+
+			handler := handlers.NewAdminHandler(mockDB, mockCache, client, helpers.TestLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/refresh-views", nil)
+			w := httptest.NewRecorder()
+
+			handler.RefreshViews(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			if tt.validateBody != nil {
+				tt.validateBody(t, body)
+			}
+
+			inspector := asynq.NewInspector(redisOpt)
+			defer inspector.Close()
+
+			tasks, err := inspector.ListPendingTasks("default")
+			if !tt.expectQueued {
+				// The queue does not exist until something is enqueued
+				if err == nil {
+					assert.Empty(t, tasks)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+			assert.Equal(t, workers.TypeRefreshAnalytics, tasks[0].Type)
+
+			var payload workers.RefreshAnalyticsPayload
+			require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+			assert.Equal(t, body["job_id"], payload.JobID)
+			assert.Equal(t, "lock:refresh_views", payload.LockKey)
+		})
+	}
+}
+
+func TestAdminHandler_RefreshViews_DoesNotStack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testRedis := helpers.SetupTestRedis(t)
+	cache := redis_a.NewCache(testRedis.Client, time.Minute, helpers.TestLogger())
+	redisOpt := asynq.RedisClientOpt{Addr: testRedis.Server.Addr()}
+	client := asynq.NewClient(redisOpt)
+	defer client.Close()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil).
+		Times(1)
+
+	handler := handlers.NewAdminHandler(mockDB, cache, client, helpers.TestLogger())
+
+	codes := make([]int, 0, 2)
+	jobIDs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/refresh-views", nil)
+		w := httptest.NewRecorder()
+		handler.RefreshViews(w, req)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		codes = append(codes, w.Code)
+		jobIDs = append(jobIDs, body["job_id"].(string))
+	}
+
+	assert.Equal(t, []int{http.StatusAccepted, http.StatusConflict}, codes)
+	assert.Equal(t, jobIDs[0], jobIDs[1], "second caller should be pointed at the running refresh")
+
+	inspector := asynq.NewInspector(redisOpt)
+	defer inspector.Close()
+	tasks, err := inspector.ListPendingTasks("default")
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
@@ -0,0 +1,225 @@
+// internal/handlers/auth.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/auth"
+)
+
+// AuthHandler issues access and refresh tokens for API users
+type AuthHandler struct {
+	users      ports.UserRepository
+	secret     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	bcryptCost int
+	// dummyHash is compared against when the email is unknown so a miss takes as long as a wrong password
+	dummyHash []byte
+	logger    *slog.Logger
+}
+
+// NewAuthHandler creates a new auth handler. Tokens are signed with secret; stored password
+// hashes weaker than bcryptCost are upgraded on the next successful login.
+func NewAuthHandler(users ports.UserRepository, secret string, accessTTL, refreshTTL time.Duration, bcryptCost int, logger *slog.Logger) *AuthHandler {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("resell-dummy-password"), bcryptCost)
+
+	return &AuthHandler{
+		users:      users,
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		bcryptCost: bcryptCost,
+		dummyHash:  dummyHash,
+		logger:     logger.With(slog.String("handler", "auth")),
+	}
+}
+
+// LoginRequest represents the request body for POST /api/v1/auth/login
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest represents the request body for POST /api/v1/auth/refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse is returned by the login and refresh endpoints. RefreshToken is only set on login.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Login handles POST /api/v1/auth/login
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email == "" || req.Password == "" {
+		h.respondError(w, http.StatusBadRequest, "email and password are required")
+		return
+	}
+
+	user, err := h.users.FindByEmail(ctx, req.Email)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to look up user", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	if user == nil {
+		bcrypt.CompareHashAndPassword(h.dummyHash, []byte(req.Password))
+		h.respondError(w, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		h.logger.InfoContext(ctx, "login rejected", slog.String("user_id", user.ID))
+		h.respondError(w, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+
+	if !user.IsActive {
+		h.respondError(w, http.StatusForbidden, "Account is disabled")
+		return
+	}
+
+	h.upgradeHash(ctx, user, req.Password)
+
+	access, err := h.issue(user.ID, user.Role, auth.TokenAccess, h.accessTTL)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to sign access token", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+	refresh, err := h.issue(user.ID, user.Role, auth.TokenRefresh, h.refreshTTL)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to sign refresh token", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	h.logger.InfoContext(ctx, "user logged in", slog.String("user_id", user.ID))
+
+	h.respondJSON(w, http.StatusOK, TokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(h.accessTTL.Seconds()),
+	})
+}
+
+// Refresh handles POST /api/v1/auth/refresh
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.RefreshToken == "" {
+		h.respondError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	claims, err := auth.Parse(req.RefreshToken, h.secret, time.Now())
+	if err == nil && claims.Type != auth.TokenRefresh {
+		err = fmt.Errorf("%w: expected a refresh token", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		h.logger.InfoContext(ctx, "refresh rejected", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		return
+	}
+
+	// Re-read the user so a disabled account or changed role takes effect on the next refresh
+	user, err := h.users.FindByID(ctx, claims.Subject)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to look up user", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+	if user == nil || !user.IsActive {
+		h.respondError(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		return
+	}
+
+	access, err := h.issue(user.ID, user.Role, auth.TokenAccess, h.accessTTL)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to sign access token", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, TokenResponse{
+		AccessToken: access,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(h.accessTTL.Seconds()),
+	})
+}
+
+// issue signs a token of the given type for a user. Only the user ID and role are embedded.
+func (h *AuthHandler) issue(userID, role string, typ auth.TokenType, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return auth.Sign(auth.Claims{
+		Subject:   userID,
+		Role:      role,
+		Type:      typ,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}, h.secret)
+}
+
+// upgradeHash rehashes the password at the configured cost when the stored hash is weaker.
+// Failures are only logged since the login itself already succeeded.
+func (h *AuthHandler) upgradeHash(ctx context.Context, user *domain.User, password string) {
+	cost, err := bcrypt.Cost([]byte(user.PasswordHash))
+	if err != nil || cost >= h.bcryptCost {
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+	if err == nil {
+		err = h.users.UpdatePasswordHash(ctx, user.ID, string(hash))
+	}
+	if err != nil {
+		h.logger.WarnContext(ctx, "failed to upgrade password hash",
+			slog.String("user_id", user.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *AuthHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *AuthHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
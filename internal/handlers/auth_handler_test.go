@@ -0,0 +1,253 @@
+// internal/handlers/auth_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/internal/pkg/auth"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+const authTestSecret = "test-secret-with-at-least-32-characters"
+
+func newAuthHandler(users *mocks.MockUserRepository) *handlers.AuthHandler {
+	return handlers.NewAuthHandler(users, authTestSecret, 15*time.Minute, 24*time.Hour, bcrypt.MinCost, helpers.TestLogger())
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := func(active bool) *domain.User {
+		return &domain.User{ID: "user-42", PasswordHash: string(hash), Role: "admin", IsActive: active}
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(*mocks.MockUserRepository)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name: "valid_credentials",
+			body: `{"email":"Owner@Example.com","password":"correct-password"}`,
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().FindByEmail(gomock.Any(), "Owner@Example.com").Return(user(true), nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var resp handlers.TokenResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.Equal(t, "Bearer", resp.TokenType)
+				assert.Equal(t, int64(900), resp.ExpiresIn)
+
+				access, err := auth.Parse(resp.AccessToken, authTestSecret, time.Now())
+				require.NoError(t, err)
+				assert.Equal(t, "user-42", access.Subject)
+				assert.Equal(t, "admin", access.Role)
+				assert.Equal(t, auth.TokenAccess, access.Type)
+
+				refresh, err := auth.Parse(resp.RefreshToken, authTestSecret, time.Now())
+				require.NoError(t, err)
+				assert.Equal(t, auth.TokenRefresh, refresh.Type)
+			},
+		},
+		{
+			name: "wrong_password",
+			body: `{"email":"owner@example.com","password":"guess"}`,
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().FindByEmail(gomock.Any(), "owner@example.com").Return(user(true), nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validateBody: func(t *testing.T, body []byte) {
+				assert.Contains(t, string(body), "Invalid email or password")
+				assert.NotContains(t, string(body), "token")
+			},
+		},
+		{
+			name: "unknown_email",
+			body: `{"email":"nobody@example.com","password":"correct-password"}`,
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "disabled_account",
+			body: `{"email":"owner@example.com","password":"correct-password"}`,
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(user(false), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "lookup_error",
+			body: `{"email":"owner@example.com","password":"correct-password"}`,
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing_password",
+			body:           `{"email":"owner@example.com"}`,
+			setupMocks:     func(users *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			tt.setupMocks(mockUsers)
+
+			handler := newAuthHandler(mockUsers)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.Login(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Login_UpgradesWeakHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindByEmail(gomock.Any(), "owner@example.com").
+		Return(&domain.User{ID: "user-42", PasswordHash: string(hash), Role: "admin", IsActive: true}, nil)
+	mockUsers.EXPECT().
+		UpdatePasswordHash(gomock.Any(), "user-42", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, upgraded string) error {
+			cost, err := bcrypt.Cost([]byte(upgraded))
+			require.NoError(t, err)
+			assert.Equal(t, bcrypt.MinCost+1, cost)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(upgraded), []byte("correct-password")))
+			return nil
+		})
+
+	handler := handlers.NewAuthHandler(mockUsers, authTestSecret, 15*time.Minute, 24*time.Hour, bcrypt.MinCost+1, helpers.TestLogger())
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/login",
+		strings.NewReader(`{"email":"owner@example.com","password":"correct-password"}`))
+	w := httptest.NewRecorder()
+
+	handler.Login(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	now := time.Now()
+	sign := func(typ auth.TokenType, expiresAt time.Time) string {
+		token, err := auth.Sign(auth.Claims{Subject: "user-42", Role: "admin", Type: typ, ExpiresAt: expiresAt.Unix()}, authTestSecret)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		setupMocks     func(*mocks.MockUserRepository)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "valid_refresh_token",
+			token: sign(auth.TokenRefresh, now.Add(time.Hour)),
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().
+					FindByID(gomock.Any(), "user-42").
+					Return(&domain.User{ID: "user-42", PasswordHash: "hash", Role: "viewer", IsActive: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var resp handlers.TokenResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.Empty(t, resp.RefreshToken)
+
+				claims, err := auth.Parse(resp.AccessToken, authTestSecret, time.Now())
+				require.NoError(t, err)
+				assert.Equal(t, auth.TokenAccess, claims.Type)
+				// The role is re-read from the database rather than copied from the refresh token
+				assert.Equal(t, "viewer", claims.Role)
+			},
+		},
+		{
+			name:           "expired_refresh_token",
+			token:          sign(auth.TokenRefresh, now.Add(-time.Minute)),
+			setupMocks:     func(users *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+			validateBody: func(t *testing.T, body []byte) {
+				assert.Contains(t, string(body), "Invalid or expired refresh token")
+			},
+		},
+		{
+			name:           "access_token_rejected",
+			token:          sign(auth.TokenAccess, now.Add(time.Hour)),
+			setupMocks:     func(users *mocks.MockUserRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "deleted_user",
+			token: sign(auth.TokenRefresh, now.Add(time.Hour)),
+			setupMocks: func(users *mocks.MockUserRepository) {
+				users.EXPECT().FindByID(gomock.Any(), "user-42").Return(nil, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			tt.setupMocks(mockUsers)
+
+			handler := newAuthHandler(mockUsers)
+
+			body, err := json.Marshal(handlers.RefreshRequest{RefreshToken: tt.token})
+			require.NoError(t, err)
+			req := httptest.NewRequest("POST", "/api/v1/auth/refresh", strings.NewReader(string(body)))
+			w := httptest.NewRecorder()
+
+			handler.Refresh(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
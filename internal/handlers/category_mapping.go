@@ -0,0 +1,83 @@
+// internal/handlers/category_mapping.go
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// CategoryMappingHandler handles admin requests for marketplace category mappings
+type CategoryMappingHandler struct {
+	mapper ports.CategoryMapper
+	logger *slog.Logger
+}
+
+// NewCategoryMappingHandler creates a new category mapping handler
+func NewCategoryMappingHandler(mapper ports.CategoryMapper, logger *slog.Logger) *CategoryMappingHandler {
+	return &CategoryMappingHandler{
+		mapper: mapper,
+		logger: logger.With(slog.String("handler", "category_mapping")),
+	}
+}
+
+// EbayMappingsResponse is the response body for GET /api/v1/admin/category-mappings/ebay
+type EbayMappingsResponse struct {
+	Mappings map[domain.ItemCategory]string `json:"mappings"`
+}
+
+// UpdateEbayMappingRequest represents the request body for overriding an eBay mapping
+type UpdateEbayMappingRequest struct {
+	EbayCategoryID string `json:"ebay_category_id"`
+}
+
+// ListEbayMappings handles GET /api/v1/admin/category-mappings/ebay
+func (h *CategoryMappingHandler) ListEbayMappings(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, EbayMappingsResponse{Mappings: h.mapper.EbayMappings()})
+}
+
+// UpdateEbayMapping handles PUT /api/v1/admin/category-mappings/ebay/{category}
+func (h *CategoryMappingHandler) UpdateEbayMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	category := domain.ItemCategory(r.PathValue("category"))
+
+	var req UpdateEbayMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.mapper.SetEbayMapping(ctx, category, strings.TrimSpace(req.EbayCategoryID)); err != nil {
+		h.logger.ErrorContext(ctx, "failed to update eBay category mapping",
+			slog.String("category", string(category)),
+			slog.String("error", err.Error()))
+
+		if strings.Contains(err.Error(), "validation failed") {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		h.respondError(w, http.StatusInternalServerError, "Failed to update category mapping")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, EbayMappingsResponse{Mappings: h.mapper.EbayMappings()})
+}
+
+func (h *CategoryMappingHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *CategoryMappingHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,116 @@
+// internal/handlers/category_mapping_handler_test.go
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestCategoryMappingHandler_ListEbayMappings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMapper := mocks.NewMockCategoryMapper(ctrl)
+	mockMapper.EXPECT().EbayMappings().Return(map[domain.ItemCategory]string{
+		domain.CategoryAntiques: "20081",
+		domain.CategoryOther:    "99",
+	})
+	handler := handlers.NewCategoryMappingHandler(mockMapper, helpers.TestLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/category-mappings/ebay", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListEbayMappings(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"mappings":{"antiques":"20081","other":"99"}}`, w.Body.String())
+}
+
+func TestCategoryMappingHandler_UpdateEbayMapping(t *testing.T) {
+	tests := []struct {
+		name           string
+		category       string
+		body           string
+		setupMocks     func(*mocks.MockCategoryMapper)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:     "overrides_mapping",
+			category: "toys",
+			body:     `{"ebay_category_id":" 2613 "}`,
+			setupMocks: func(m *mocks.MockCategoryMapper) {
+				m.EXPECT().SetEbayMapping(gomock.Any(), domain.CategoryToys, "2613").Return(nil)
+				m.EXPECT().EbayMappings().Return(map[domain.ItemCategory]string{domain.CategoryToys: "2613"})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid_body",
+			category:       "toys",
+			body:           `not json`,
+			setupMocks:     func(m *mocks.MockCategoryMapper) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+		{
+			name:     "validation_error",
+			category: "spaceships",
+			body:     `{"ebay_category_id":"2613"}`,
+			setupMocks: func(m *mocks.MockCategoryMapper) {
+				m.EXPECT().SetEbayMapping(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New(`validation failed: unknown category "spaceships"`))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `validation failed: unknown category "spaceships"`,
+		},
+		{
+			name:     "save_error",
+			category: "toys",
+			body:     `{"ebay_category_id":"2613"}`,
+			setupMocks: func(m *mocks.MockCategoryMapper) {
+				m.EXPECT().SetEbayMapping(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("failed to save category mapping: database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to update category mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMapper := mocks.NewMockCategoryMapper(ctrl)
+			tt.setupMocks(mockMapper)
+			handler := handlers.NewCategoryMappingHandler(mockMapper, helpers.TestLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/category-mappings/ebay/"+tt.category, strings.NewReader(tt.body))
+			req.SetPathValue("category", tt.category)
+			w := httptest.NewRecorder()
+
+			handler.UpdateEbayMapping(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ammerola/resell-be/internal/adapters/db"
@@ -13,36 +17,87 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// Dashboard and analytics queries are expensive, so a miss is recomputed by one caller across
+// all instances while the others are served the previous value
+const (
+	dashboardLockTTL  = 30 * time.Second
+	dashboardLockWait = 10 * time.Second
+	dashboardStaleTTL = 15 * time.Minute
+)
+
+// defaultAnalyticsPeriod is the analytics range, ending today, when neither from nor period is given
+const defaultAnalyticsPeriod = "30d"
+
+// maxAnalyticsPeriodDays caps the period parameter at ten years
+const maxAnalyticsPeriodDays = 3650
+
+// Aging report defaults and bounds: items unsold after 90 days, 50 per report
+const (
+	defaultAgingDays  = 90
+	defaultAgingLimit = 50
+	maxAgingLimit     = 500
+)
+
+// defaultVariancePercent flags items valued more than 25% above or below their cost
+var defaultVariancePercent = decimal.NewFromInt(25)
+
 // DashboardHandler handles dashboard operations
 type DashboardHandler struct {
-	db     *db.Database
-	cache  ports.CacheRepository
-	logger *slog.Logger
+	service ports.InventoryService
+	repo    ports.DashboardRepository
+	db      *db.Database
+	cache   ports.CacheRepository
+	logger  *slog.Logger
 }
 
 // NewDashboardHandler creates a new dashboard handler
-func NewDashboardHandler(db *db.Database, cache ports.CacheRepository, logger *slog.Logger) *DashboardHandler {
+func NewDashboardHandler(service ports.InventoryService, repo ports.DashboardRepository, db *db.Database,
+	cache ports.CacheRepository, logger *slog.Logger) *DashboardHandler {
 	return &DashboardHandler{
-		db:     db,
-		cache:  cache,
-		logger: logger.With(slog.String("handler", "dashboard")),
+		service: service,
+		repo:    repo,
+		db:      db,
+		cache:   cache,
+		logger:  logger.With(slog.String("handler", "dashboard")),
 	}
 }
 
-// GetDashboard handles GET /api/v1/dashboard
+// GetDashboard handles GET /api/v1/dashboard. from and to (YYYY-MM-DD, inclusive) narrow the
+// category breakdown to items acquired in that range.
 func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Try cache first
+	from, to, err := parseDateRange(r, "from", "to")
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filters := ports.DashboardFilters{From: from}
 	cacheKey := redis_a.BuildKey(redis_a.PrefixDashboard, "main")
+	if from != nil || to != nil {
+		if to != nil {
+			end := to.AddDate(0, 0, 1)
+			filters.To = &end
+		}
+		cacheKey = redis_a.BuildKey(redis_a.PrefixDashboard, "main", formatDateParam(from), formatDateParam(to))
+	}
+
+	// Try cache first
 	var dashboard DashboardData
 
-	err := h.cache.GetOrSet(ctx, cacheKey, &dashboard, func() (interface{}, error) {
-		return h.loadDashboardData(ctx)
-	}, 5*time.Minute)
+	data, err := h.cache.GetOrSet(ctx, cacheKey, 5*time.Minute, func() ([]byte, error) {
+		dashboard, err := h.loadDashboardData(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(dashboard)
+	}, ports.WithLock(dashboardLockTTL, dashboardLockWait), ports.WithStale(dashboardStaleTTL))
+	if err == nil {
+		err = json.Unmarshal(data, &dashboard)
+	}
 
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to load dashboard", err)
+		h.logger.ErrorContext(ctx, "failed to load dashboard", slog.String("error", err.Error()))
 		h.respondError(w, http.StatusInternalServerError, "Failed to load dashboard")
 		return
 	}
@@ -50,25 +105,40 @@ func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request)
 	h.respondJSON(w, http.StatusOK, dashboard)
 }
 
-// GetAnalytics handles GET /api/v1/dashboard/analytics
+// GetAnalytics handles GET /api/v1/dashboard/analytics, returning acquisitions, spend, items
+// sold and net profit per interval (day, week or month) as arrays for charting. The range is
+// from/to (YYYY-MM-DD, inclusive) or the period (e.g. 30d) ending today, and each
+// (interval, range) is cached separately.
 func (h *DashboardHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Parse time range
-	period := r.URL.Query().Get("period")
-	if period == "" {
-		period = "30d"
+	period, params, err := parseAnalyticsParams(r, time.Now())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	cacheKey := redis_a.BuildKey(redis_a.PrefixAnalytics, period)
-	var analytics AnalyticsData
+	cacheKey := redis_a.BuildKey(redis_a.PrefixAnalytics, params.Interval,
+		params.From.Format("2006-01-02"), params.To.Format("2006-01-02"))
+	analytics := AnalyticsData{Period: period}
 
-	err := h.cache.GetOrSet(ctx, cacheKey, &analytics, func() (interface{}, error) {
-		return h.loadAnalyticsData(ctx, period)
-	}, 15*time.Minute)
+	data, err := h.cache.GetOrSet(ctx, cacheKey, 15*time.Minute, func() ([]byte, error) {
+		series, err := h.service.TimeSeries(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(series)
+	}, ports.WithLock(dashboardLockTTL, dashboardLockWait), ports.WithStale(dashboardStaleTTL))
+	if err == nil {
+		err = json.Unmarshal(data, &analytics.TimeSeries)
+	}
 
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to load analytics", err)
+		if strings.Contains(err.Error(), "validation failed") {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to load analytics", slog.String("error", err.Error()))
 		h.respondError(w, http.StatusInternalServerError, "Failed to load analytics")
 		return
 	}
@@ -76,7 +146,139 @@ func (h *DashboardHandler) GetAnalytics(w http.ResponseWriter, r *http.Request)
 	h.respondJSON(w, http.StatusOK, analytics)
 }
 
-func (h *DashboardHandler) loadDashboardData(ctx context.Context) (*DashboardData, error) {
+// GetAgingInventory handles GET /api/v1/dashboard/aging, listing the unsold items acquired more
+// than days (default 90) ago with the days each has been held, oldest and then costliest first
+func (h *DashboardHandler) GetAgingInventory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params, err := parseAgingParams(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.repo.AgingInventory(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to load aging inventory",
+			slog.Int("min_days", params.MinDays),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to load aging inventory")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, AgingReport{MinDays: params.MinDays, Items: items})
+}
+
+// parseAgingParams reads the days threshold and limit of an aging report
+func parseAgingParams(r *http.Request) (ports.AgingParams, error) {
+	params := ports.AgingParams{MinDays: defaultAgingDays, Limit: defaultAgingLimit}
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxAnalyticsPeriodDays {
+			return params, fmt.Errorf("days must be between 0 and %d", maxAnalyticsPeriodDays)
+		}
+		params.MinDays = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAgingLimit {
+			return params, fmt.Errorf("limit must be between 1 and %d", maxAgingLimit)
+		}
+		params.Limit = n
+	}
+	return params, nil
+}
+
+// GetValueVariance handles GET /api/v1/dashboard/variance, listing the items whose estimated
+// value is more than percent (default 25) above or below their total cost, largest variance first
+func (h *DashboardHandler) GetValueVariance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params, err := parseVarianceParams(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.repo.ValueVariance(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to load value variance",
+			slog.String("min_percent", params.MinPercent.String()),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to load value variance")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, VarianceReport{MinPercent: params.MinPercent, Items: items})
+}
+
+// parseVarianceParams reads the percentage threshold and limit of a variance report. The
+// limit shares the aging report's bounds.
+func parseVarianceParams(r *http.Request) (ports.VarianceParams, error) {
+	params := ports.VarianceParams{MinPercent: defaultVariancePercent, Limit: defaultAgingLimit}
+	if v := r.URL.Query().Get("percent"); v != "" {
+		d, err := decimal.NewFromString(v)
+		if err != nil || d.IsNegative() {
+			return params, errors.New("percent must be a non-negative number")
+		}
+		params.MinPercent = d
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAgingLimit {
+			return params, fmt.Errorf("limit must be between 1 and %d", maxAgingLimit)
+		}
+		params.Limit = n
+	}
+	return params, nil
+}
+
+// formatDateParam formats an optional date filter for a cache key, with "-" for an open bound
+func formatDateParam(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// parseAnalyticsParams reads the interval and range of an analytics request. Dates are whole
+// UTC days, so To is the midnight after the last day. Without from the range is the period
+// ending today, which is returned when it applies.
+func parseAnalyticsParams(r *http.Request, now time.Time) (string, ports.TimeSeriesParams, error) {
+	params := ports.TimeSeriesParams{Interval: r.URL.Query().Get("interval")}
+	if params.Interval == "" {
+		params.Interval = ports.IntervalDay
+	}
+
+	from, to, err := parseDateRange(r, "from", "to")
+	if err != nil {
+		return "", params, err
+	}
+
+	now = now.UTC()
+	params.To = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	if to != nil {
+		params.To = to.AddDate(0, 0, 1)
+	}
+	if from != nil {
+		params.From = *from
+		return "", params, nil
+	}
+
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = defaultAnalyticsPeriod
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+	if err != nil || !strings.HasSuffix(period, "d") || days < 1 || days > maxAnalyticsPeriodDays {
+		return "", params, fmt.Errorf("period must be a number of days between 1d and %dd", maxAnalyticsPeriodDays)
+	}
+	params.From = params.To.AddDate(0, 0, -days)
+
+	return period, params, nil
+}
+
+func (h *DashboardHandler) loadDashboardData(ctx context.Context, filters ports.DashboardFilters) (*DashboardData, error) {
 	dashboard := &DashboardData{
 		Timestamp: time.Now(),
 	}
@@ -120,35 +322,10 @@ func (h *DashboardHandler) loadDashboardData(ctx context.Context) (*DashboardDat
 	}
 
 	// Load category breakdown
-	categoryQuery := `
-		SELECT 
-			category,
-			COUNT(*) as count,
-			SUM(total_cost) as value,
-			COUNT(CASE WHEN EXISTS (
-				SELECT 1 FROM platform_listings pl 
-				WHERE pl.lot_id = i.lot_id AND pl.status = 'sold'
-			) THEN 1 END) as sold_count
-		FROM inventory i
-		WHERE deleted_at IS NULL
-		GROUP BY category
-		ORDER BY count DESC
-		LIMIT 10
-	`
-
-	rows, err := h.db.Query(ctx, categoryQuery)
+	dashboard.CategoryBreakdown, err = h.repo.CategoryBreakdown(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var cat CategoryBreakdown
-		if err := rows.Scan(&cat.Category, &cat.Count, &cat.Value, &cat.SoldCount); err != nil {
-			continue
-		}
-		dashboard.CategoryBreakdown = append(dashboard.CategoryBreakdown, cat)
-	}
 
 	// Load recent activity
 	activityQuery := `
@@ -176,24 +353,15 @@ func (h *DashboardHandler) loadDashboardData(ctx context.Context) (*DashboardDat
 	return dashboard, nil
 }
 
-func (h *DashboardHandler) loadAnalyticsData(ctx context.Context, period string) (*AnalyticsData, error) {
-	// Load analytics based on period
-	// This is a simplified version
-	return &AnalyticsData{
-		Period: period,
-		// ... load actual analytics
-	}, nil
-}
-
 // Type definitions
 
 type DashboardData struct {
-	Summary           DashboardSummary    `json:"summary"`
-	CategoryBreakdown []CategoryBreakdown `json:"category_breakdown"`
-	PlatformMetrics   []PlatformMetric    `json:"platform_metrics"`
-	AgingInventory    []AgingInventory    `json:"aging_inventory"`
-	RecentActivity    []RecentActivity    `json:"recent_activity"`
-	Timestamp         time.Time           `json:"timestamp"`
+	Summary           DashboardSummary          `json:"summary"`
+	CategoryBreakdown []ports.CategoryBreakdown `json:"category_breakdown"`
+	PlatformMetrics   []PlatformMetric          `json:"platform_metrics"`
+	AgingInventory    []AgingInventory          `json:"aging_inventory"`
+	RecentActivity    []RecentActivity          `json:"recent_activity"`
+	Timestamp         time.Time                 `json:"timestamp"`
 }
 
 type DashboardSummary struct {
@@ -207,14 +375,6 @@ type DashboardSummary struct {
 	AverageDaysToSell float64         `json:"average_days_to_sell"`
 }
 
-type CategoryBreakdown struct {
-	Category  string          `json:"category"`
-	Count     int             `json:"count"`
-	Value     decimal.Decimal `json:"value"`
-	SoldCount int             `json:"sold_count"`
-	AvgROI    decimal.Decimal `json:"avg_roi,omitempty"`
-}
-
 type PlatformMetric struct {
 	Platform       string          `json:"platform"`
 	ListedCount    int             `json:"listed_count"`
@@ -238,9 +398,22 @@ type RecentActivity struct {
 	Details   map[string]interface{} `json:"details,omitempty"`
 }
 
+// AnalyticsData is the analytics time series, with the period it covers when one was requested
 type AnalyticsData struct {
-	Period string `json:"period"`
-	// ... analytics fields
+	Period string `json:"period,omitempty"`
+	ports.TimeSeries
+}
+
+// AgingReport lists the items held unsold for more than MinDays
+type AgingReport struct {
+	MinDays int               `json:"min_days"`
+	Items   []ports.AgingItem `json:"items"`
+}
+
+// VarianceReport lists the items valued more than MinPercent above or below their cost
+type VarianceReport struct {
+	MinPercent decimal.Decimal      `json:"min_percent"`
+	Items      []ports.VarianceItem `json:"items"`
 }
 
 // Helper methods
@@ -0,0 +1,328 @@
+// internal/handlers/dashboard_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestDashboardHandler_GetAnalytics(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	today := time.Now().UTC()
+	tomorrow := day(today.Year(), today.Month(), today.Day()+1)
+
+	series := func(params ports.TimeSeriesParams) *ports.TimeSeries {
+		return &ports.TimeSeries{
+			Interval:     params.Interval,
+			From:         params.From,
+			To:           params.To,
+			Buckets:      []time.Time{params.From},
+			Acquisitions: []int{3},
+			Spend:        []decimal.Decimal{decimal.NewFromInt(45)},
+			ItemsSold:    []int{1},
+			NetProfit:    []decimal.Decimal{decimal.NewFromInt(20)},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantParams     *ports.TimeSeriesParams
+		serviceErr     error
+		expectedStatus int
+		validate       func(*testing.T, handlers.AnalyticsData)
+	}{
+		{
+			name:           "defaults_to_daily_buckets_for_the_last_30_days",
+			wantParams:     &ports.TimeSeriesParams{Interval: ports.IntervalDay, From: tomorrow.AddDate(0, 0, -30), To: tomorrow},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, data handlers.AnalyticsData) {
+				assert.Equal(t, "30d", data.Period)
+				assert.Equal(t, []int{3}, data.Acquisitions)
+				assert.Equal(t, []int{1}, data.ItemsSold)
+				require.Len(t, data.NetProfit, 1)
+				assert.True(t, decimal.NewFromInt(20).Equal(data.NetProfit[0]))
+			},
+		},
+		{
+			name:           "period_sets_the_range",
+			query:          "?period=7d&interval=week",
+			wantParams:     &ports.TimeSeriesParams{Interval: ports.IntervalWeek, From: tomorrow.AddDate(0, 0, -7), To: tomorrow},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, data handlers.AnalyticsData) {
+				assert.Equal(t, "7d", data.Period)
+				assert.Equal(t, ports.IntervalWeek, data.Interval)
+			},
+		},
+		{
+			name:           "from_and_to_are_inclusive_days",
+			query:          "?interval=month&from=2024-01-15&to=2024-03-31",
+			wantParams:     &ports.TimeSeriesParams{Interval: ports.IntervalMonth, From: day(2024, 1, 15), To: day(2024, 4, 1)},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, data handlers.AnalyticsData) {
+				assert.Empty(t, data.Period)
+				assert.Equal(t, day(2024, 1, 15), data.From)
+				assert.Equal(t, day(2024, 4, 1), data.To)
+			},
+		},
+		{
+			name:           "invalid_interval",
+			query:          "?interval=hour",
+			wantParams:     &ports.TimeSeriesParams{Interval: "hour", From: tomorrow.AddDate(0, 0, -30), To: tomorrow},
+			serviceErr:     errors.New("validation failed: interval must be day, week or month"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid_date",
+			query:          "?from=03/01/2024",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "inverted_range",
+			query:          "?from=2024-03-10&to=2024-03-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid_period",
+			query:          "?period=thirty",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service_error",
+			wantParams:     &ports.TimeSeriesParams{Interval: ports.IntervalDay, From: tomorrow.AddDate(0, 0, -30), To: tomorrow},
+			serviceErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			testRedis := helpers.SetupTestRedis(t)
+			cache := redis_a.NewCache(testRedis.Client, time.Minute, helpers.TestLogger())
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewDashboardHandler(mockService, nil, nil, cache, helpers.TestLogger())
+
+			if tt.wantParams != nil {
+				mockService.EXPECT().
+					TimeSeries(gomock.Any(), *tt.wantParams).
+					DoAndReturn(func(_ context.Context, params ports.TimeSeriesParams) (*ports.TimeSeries, error) {
+						if tt.serviceErr != nil {
+							return nil, tt.serviceErr
+						}
+						return series(params), nil
+					})
+			}
+
+			w := httptest.NewRecorder()
+			handler.GetAnalytics(w, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/analytics"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.validate != nil {
+				var data handlers.AnalyticsData
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &data))
+				tt.validate(t, data)
+			}
+		})
+	}
+}
+
+func TestDashboardHandler_GetAnalytics_CachesPerIntervalAndRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testRedis := helpers.SetupTestRedis(t)
+	cache := redis_a.NewCache(testRedis.Client, time.Minute, helpers.TestLogger())
+	mockService := mocks.NewMockInventoryService(ctrl)
+	handler := handlers.NewDashboardHandler(mockService, nil, nil, cache, helpers.TestLogger())
+
+	// One load per distinct (interval, range)
+	mockService.EXPECT().
+		TimeSeries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params ports.TimeSeriesParams) (*ports.TimeSeries, error) {
+			return &ports.TimeSeries{Interval: params.Interval, From: params.From, To: params.To}, nil
+		}).
+		Times(3)
+
+	get := func(query string) handlers.AnalyticsData {
+		w := httptest.NewRecorder()
+		handler.GetAnalytics(w, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/analytics"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var data handlers.AnalyticsData
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &data))
+		return data
+	}
+
+	get("?from=2024-03-04&to=2024-03-10")
+	cached := get("?from=2024-03-04&to=2024-03-10")
+	assert.Equal(t, ports.IntervalDay, cached.Interval)
+
+	get("?interval=week&from=2024-03-04&to=2024-03-10")
+	get("?from=2024-03-04&to=2024-03-17")
+	get("?interval=week&from=2024-03-04&to=2024-03-10")
+
+	assert.True(t, testRedis.Server.Exists("analytics:day:2024-03-04:2024-03-11"))
+	assert.True(t, testRedis.Server.Exists("analytics:week:2024-03-04:2024-03-11"))
+	assert.True(t, testRedis.Server.Exists("analytics:day:2024-03-04:2024-03-18"))
+}
+
+func TestDashboardHandler_GetAgingInventory(t *testing.T) {
+	item := ports.AgingItem{
+		LotID:     uuid.New(),
+		ItemName:  "Oak Dresser",
+		DaysHeld:  120,
+		TotalCost: decimal.NewFromInt(250),
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantParams     *ports.AgingParams
+		repoErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "defaults_to_90_days",
+			wantParams:     &ports.AgingParams{MinDays: 90, Limit: 50},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "custom_threshold_and_limit",
+			query:          "?days=30&limit=200",
+			wantParams:     &ports.AgingParams{MinDays: 30, Limit: 200},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "invalid_days", query: "?days=-1", expectedStatus: http.StatusBadRequest},
+		{name: "invalid_limit", query: "?limit=0", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "repository_error",
+			wantParams:     &ports.AgingParams{MinDays: 90, Limit: 50},
+			repoErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDashboardRepository(ctrl)
+			handler := handlers.NewDashboardHandler(nil, mockRepo, nil, nil, helpers.TestLogger())
+
+			if tt.wantParams != nil {
+				result := []ports.AgingItem{item}
+				if tt.repoErr != nil {
+					result = nil
+				}
+				mockRepo.EXPECT().AgingInventory(gomock.Any(), *tt.wantParams).Return(result, tt.repoErr)
+			}
+
+			w := httptest.NewRecorder()
+			handler.GetAgingInventory(w, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/aging"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var report handlers.AgingReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.wantParams.MinDays, report.MinDays)
+			require.Len(t, report.Items, 1)
+			assert.Equal(t, item.LotID, report.Items[0].LotID)
+			assert.Equal(t, 120, report.Items[0].DaysHeld)
+		})
+	}
+}
+
+func TestDashboardHandler_GetValueVariance(t *testing.T) {
+	over := ports.VarianceItem{
+		LotID:           uuid.New(),
+		TotalCost:       decimal.NewFromInt(100),
+		EstimatedValue:  decimal.NewFromInt(200),
+		Variance:        decimal.NewFromInt(100),
+		VariancePercent: decimal.NewFromInt(100),
+	}
+	under := ports.VarianceItem{
+		LotID:           uuid.New(),
+		TotalCost:       decimal.NewFromInt(100),
+		EstimatedValue:  decimal.NewFromInt(60),
+		Variance:        decimal.NewFromInt(-40),
+		VariancePercent: decimal.NewFromInt(-40),
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantPercent    string
+		wantLimit      int
+		repoErr        error
+		expectedStatus int
+	}{
+		{name: "defaults_to_25_percent", wantPercent: "25", wantLimit: 50, expectedStatus: http.StatusOK},
+		{name: "custom_threshold", query: "?percent=12.5&limit=10", wantPercent: "12.5", wantLimit: 10, expectedStatus: http.StatusOK},
+		{name: "negative_percent", query: "?percent=-5", expectedStatus: http.StatusBadRequest},
+		{name: "invalid_percent", query: "?percent=lots", expectedStatus: http.StatusBadRequest},
+		{name: "invalid_limit", query: "?limit=1000", expectedStatus: http.StatusBadRequest},
+		{name: "repository_error", wantPercent: "25", wantLimit: 50, repoErr: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDashboardRepository(ctrl)
+			handler := handlers.NewDashboardHandler(nil, mockRepo, nil, nil, helpers.TestLogger())
+
+			if tt.wantPercent != "" {
+				mockRepo.EXPECT().
+					ValueVariance(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params ports.VarianceParams) ([]ports.VarianceItem, error) {
+						assert.Equal(t, tt.wantPercent, params.MinPercent.String())
+						assert.Equal(t, tt.wantLimit, params.Limit)
+						if tt.repoErr != nil {
+							return nil, tt.repoErr
+						}
+						return []ports.VarianceItem{over, under}, nil
+					})
+			}
+
+			w := httptest.NewRecorder()
+			handler.GetValueVariance(w, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/variance"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var report handlers.VarianceReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.wantPercent, report.MinPercent.String())
+			require.Len(t, report.Items, 2)
+			assert.True(t, report.Items[0].VariancePercent.IsPositive())
+			assert.True(t, decimal.NewFromInt(-40).Equal(report.Items[1].Variance))
+		})
+	}
+}
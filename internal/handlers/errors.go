@@ -0,0 +1,46 @@
+// internal/handlers/errors.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// Error codes, stable values clients can switch on while messages are free to change
+const (
+	ErrCodeBadRequest      = "bad_request"
+	ErrCodeValidation      = "validation_failed"
+	ErrCodeNotFound        = "not_found"
+	ErrCodeConflict        = "conflict"
+	ErrCodePayloadTooLarge = "payload_too_large"
+	ErrCodeInternal        = "internal_error"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an error. RequestID matches the X-Request-ID response header so a
+// client can quote it when reporting a problem.
+type ErrorDetail struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
+
+// writeError writes the error envelope with the request ID the RequestID middleware stored in
+// r's context
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	requestID, _ := r.Context().Value(logger.ContextKeyRequestID).(string)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: ErrorDetail{
+		Code:      code,
+		Message:   message,
+		RequestID: requestID,
+	}})
+}
@@ -0,0 +1,84 @@
+// internal/handlers/errors_test.go
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/internal/handlers/middleware"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+// decodeError decodes an error envelope and returns its detail
+func decodeError(t *testing.T, body []byte) handlers.ErrorDetail {
+	t.Helper()
+	var response handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &response))
+	return response.Error
+}
+
+func TestErrorEnvelope_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lotID := uuid.New()
+	mockService := mocks.NewMockInventoryService(ctrl)
+	mockService.EXPECT().
+		GetByID(gomock.Any(), lotID).
+		Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, lotID))
+
+	handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory/"+lotID.String(), nil)
+	req.SetPathValue("id", lotID.String())
+	req.Header.Set("X-Request-ID", "req-404")
+	w := httptest.NewRecorder()
+
+	middleware.RequestID(http.HandlerFunc(handler.GetInventory)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	// Exactly one top level key, holding exactly code, message and request_id
+	var envelope map[string]map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, map[string]map[string]string{
+		"error": {
+			"code":       handlers.ErrCodeNotFound,
+			"message":    "Inventory item not found",
+			"request_id": "req-404",
+		},
+	}, envelope)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), envelope["error"]["request_id"])
+}
+
+func TestErrorEnvelope_WithoutRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := handlers.NewInventoryHandler(mocks.NewMockInventoryService(ctrl), newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+	req := httptest.NewRequest("GET", "/api/v1/inventory/not-a-uuid", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	w := httptest.NewRecorder()
+
+	handler.GetInventory(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, handlers.ErrorDetail{
+		Code:    handlers.ErrCodeBadRequest,
+		Message: "Invalid inventory ID format",
+	}, decodeError(t, w.Body.Bytes()))
+}
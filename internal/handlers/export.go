@@ -4,20 +4,29 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/tealeg/xlsx/v3"
 
 	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
 	"github.com/ammerola/resell-be/internal/core/ports"
 )
 
+// csvFlushInterval is the number of CSV rows buffered before flushing to the client
+const csvFlushInterval = 500
+
+// jsonExportCacheTTL is how long a JSON export is cached; inventory writes clear it sooner
+const jsonExportCacheTTL = 5 * time.Minute
+
 // ExportParams defines parameters for export operations
 type ExportParams struct {
 	Columns        []string   `json:"columns"`
@@ -84,15 +93,18 @@ type ExportHandler struct {
 	db               ports.Database
 	cache            ports.CacheRepository
 	logger           *slog.Logger
+	streamThreshold  int
 }
 
-// NewExportHandler creates a new export handler
-func NewExportHandler(inventoryService ports.InventoryService, db ports.Database, cache ports.CacheRepository, logger *slog.Logger) *ExportHandler {
+// NewExportHandler creates a new export handler. Excel exports with more rows than
+// streamThreshold are streamed without styling; a threshold of 0 disables streaming.
+func NewExportHandler(inventoryService ports.InventoryService, db ports.Database, cache ports.CacheRepository, logger *slog.Logger, streamThreshold int) *ExportHandler {
 	return &ExportHandler{
 		inventoryService: inventoryService,
 		db:               db,
 		cache:            cache,
 		logger:           logger.With(slog.String("handler", "export")),
+		streamThreshold:  streamThreshold,
 	}
 }
 
@@ -110,7 +122,23 @@ func (h *ExportHandler) ExportExcel(w http.ResponseWriter, r *http.Request) {
 	data, err := h.getInventoryData(ctx, params)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "Failed to retrieve inventory data", slog.String("error", err.Error()))
-		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve data")
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
+		return
+	}
+
+	filename := fmt.Sprintf("inventory_export_%s.xlsx", time.Now().Format("20060102_150405"))
+
+	// Large exports are written straight to the client from a disk-backed workbook
+	if h.streamThreshold > 0 && len(data) > h.streamThreshold {
+		h.setExcelHeaders(w, filename)
+		if err := h.streamExcelFile(w, data, params); err != nil {
+			h.logger.ErrorContext(ctx, "Failed to stream Excel file", slog.String("error", err.Error()))
+			return
+		}
+
+		h.logger.InfoContext(ctx, "Excel export streamed successfully",
+			slog.Int("total_rows", len(data)),
+			slog.String("filename", filename))
 		return
 	}
 
@@ -118,16 +146,13 @@ func (h *ExportHandler) ExportExcel(w http.ResponseWriter, r *http.Request) {
 	excelData, err := h.generateExcelFile(data, params)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "Failed to generate Excel file", slog.String("error", err.Error()))
-		h.respondError(w, http.StatusInternalServerError, "Failed to generate Excel file")
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to generate Excel file")
 		return
 	}
 
 	// Set response headers
-	filename := fmt.Sprintf("inventory_export_%s.xlsx", time.Now().Format("20060102_150405"))
-	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	h.setExcelHeaders(w, filename)
 	w.Header().Set("Content-Length", strconv.Itoa(len(excelData)))
-	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 
 	// Write file data
 	if _, err := w.Write(excelData); err != nil {
@@ -150,60 +175,49 @@ func (h *ExportHandler) ExportJSON(w http.ResponseWriter, r *http.Request) {
 	h.logger.InfoContext(ctx, "Starting JSON export",
 		slog.Any("params", params))
 
-	// Check cache first
+	// Serve from cache, building the export once for concurrent misses
 	cacheKey := redis_a.BuildKey(redis_a.PrefixExport, "json", h.getCacheKeyFromParams(params))
-	var cachedData []byte
-	if err := h.cache.Get(ctx, cacheKey, &cachedData); err == nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("X-Cache", "HIT")
-		w.Header().Set("Content-Length", strconv.Itoa(len(cachedData)))
-
-		if _, err := w.Write(cachedData); err != nil {
-			h.logger.ErrorContext(ctx, "Failed to write cached JSON response", slog.String("error", err.Error()))
-			return
+	loaded := false
+	responseData, err := h.cache.GetOrSet(ctx, cacheKey, jsonExportCacheTTL, func() ([]byte, error) {
+		loaded = true
+
+		// Get inventory data
+		data, err := h.getInventoryData(ctx, params)
+		if err != nil {
+			return nil, err
 		}
 
-		h.logger.InfoContext(ctx, "JSON export served from cache")
-		return
-	}
-
-	// Get inventory data
-	data, err := h.getInventoryData(ctx, params)
-	if err != nil {
-		h.logger.ErrorContext(ctx, "Failed to retrieve inventory data", slog.String("error", err.Error()))
-		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve data")
-		return
-	}
-
-	// Convert to JSON-friendly format
-	jsonData := make([]map[string]any, 0, len(data))
-	for _, item := range data {
-		jsonData = append(jsonData, h.itemToJSONMap(&item, params.Columns))
-	}
-
-	// Create response with metadata
-	response := JSONExportResponse{
-		Inventory: jsonData,
-		Metadata: ExportMetadata{
-			ExportDate:     time.Now(),
-			TotalItems:     len(jsonData),
-			FiltersApplied: params.Filters,
-			IncludeDeleted: params.IncludeDeleted,
-			Columns:        params.Columns,
-		},
-	}
+		// Convert to JSON-friendly format
+		jsonData := make([]map[string]any, 0, len(data))
+		for _, item := range data {
+			jsonData = append(jsonData, h.itemToJSONMap(&item, params.Columns))
+		}
 
-	// Marshal response
-	responseData, err := json.Marshal(response)
+		// Create response with metadata
+		return json.Marshal(JSONExportResponse{
+			Inventory: jsonData,
+			Metadata: ExportMetadata{
+				ExportDate:     time.Now(),
+				TotalItems:     len(jsonData),
+				FiltersApplied: params.Filters,
+				IncludeDeleted: params.IncludeDeleted,
+				Columns:        params.Columns,
+			},
+		})
+	})
 	if err != nil {
-		h.logger.ErrorContext(ctx, "Failed to marshal JSON response", slog.String("error", err.Error()))
-		h.respondError(w, http.StatusInternalServerError, "Failed to generate JSON")
+		h.logger.ErrorContext(ctx, "Failed to build JSON export", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
 		return
 	}
 
 	// Set response headers
+	cacheStatus := "HIT"
+	if loaded {
+		cacheStatus = "MISS"
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Cache", "MISS")
+	w.Header().Set("X-Cache", cacheStatus)
 	w.Header().Set("Content-Length", strconv.Itoa(len(responseData)))
 
 	// Write response
@@ -212,42 +226,113 @@ func (h *ExportHandler) ExportJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Cache the result for 5 minutes (async)
-	go func() {
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	h.logger.InfoContext(ctx, "JSON export completed successfully",
+		slog.String("cache", cacheStatus))
+}
+
+// ExportCSV handles GET /api/v1/export/csv
+func (h *ExportHandler) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-		if err := h.cache.Set(cacheCtx, cacheKey, responseData); err != nil {
-			h.logger.WarnContext(cacheCtx, "Failed to cache JSON response", slog.String("error", err.Error()))
+	// Parse parameters
+	params := h.parseExportParams(r)
+
+	h.logger.InfoContext(ctx, "Starting CSV export",
+		slog.Any("params", params))
+
+	// Query before writing anything so a failure can still be reported as an error response
+	rows, err := h.queryInventoryRows(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to retrieve inventory data", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
+		return
+	}
+	defer rows.Close()
+
+	// Set response headers
+	filename := fmt.Sprintf("inventory_export_%s.csv", time.Now().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	// Write each record as it is scanned so memory stays flat regardless of result size,
+	// flushing periodically
+	writer := csv.NewWriter(w)
+	if err := writer.Write(h.getExcelHeaders(params.Columns)); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to write CSV header", slog.String("error", err.Error()))
+		return
+	}
+
+	totalRows := 0
+	for rows.Next() {
+		item, err := scanExportRow(rows)
+		if err != nil {
+			h.logger.WarnContext(ctx, "Failed to scan inventory row", slog.String("error", err.Error()))
+			continue
+		}
+		if err := writer.Write(h.itemToExcelRow(&item, params.Columns)); err != nil {
+			h.logger.ErrorContext(ctx, "Failed to write CSV row", slog.String("error", err.Error()))
+			return
 		}
-	}()
+		totalRows++
+		if totalRows%csvFlushInterval == 0 {
+			writer.Flush()
+		}
+	}
 
-	h.logger.InfoContext(ctx, "JSON export completed successfully",
-		slog.Int("total_rows", len(data)))
+	writer.Flush()
+	if err := rows.Err(); err != nil {
+		// Headers are already sent, so the truncated body is all the client can be given
+		h.logger.ErrorContext(ctx, "Error iterating inventory rows", slog.String("error", err.Error()))
+		return
+	}
+	if err := writer.Error(); err != nil {
+		h.logger.ErrorContext(ctx, "Failed to write CSV response", slog.String("error", err.Error()))
+		return
+	}
+
+	h.logger.InfoContext(ctx, "CSV export completed successfully",
+		slog.Int("total_rows", totalRows),
+		slog.String("filename", filename))
 }
 
 // ExportPDF handles GET /api/v1/export/pdf
 func (h *ExportHandler) ExportPDF(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	h.logger.InfoContext(ctx, "PDF export not yet implemented")
+	// Parse parameters
+	params := h.parseExportParams(r)
+
+	h.logger.InfoContext(ctx, "Starting PDF export",
+		slog.Any("params", params))
+
+	// Get inventory data
+	data, err := h.getInventoryData(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Failed to retrieve inventory data", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
+		return
+	}
+
+	// Render the report in memory so the exact length is known up front
+	pdfData := h.generatePDFReport(data, params)
 
-	// Set response headers for future PDF implementation
+	// Set response headers
 	filename := fmt.Sprintf("inventory_report_%s.pdf", time.Now().Format("20060102_150405"))
 	w.Header().Set("Content-Type", "application/pdf")
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdfData)))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 
-	// Placeholder implementation
-	placeholder := []byte("%PDF-1.4\n1 0 obj\n<<\n/Type /Catalog\n/Pages 2 0 R\n>>\nendobj\n2 0 obj\n<<\n/Type /Pages\n/Kids [3 0 R]\n/Count 1\n>>\nendobj\n3 0 obj\n<<\n/Type /Page\n/Parent 2 0 R\n/MediaBox [0 0 612 792]\n/Contents 4 0 R\n>>\nendobj\n4 0 obj\n<<\n/Length 44\n>>\nstream\nBT\n/F1 12 Tf\n72 720 Td\n(PDF export coming soon!) Tj\nET\nendstream\nendobj\nxref\n0 5\n0000000000 65535 f \n0000000009 00000 n \n0000000058 00000 n \n0000000115 00000 n \n0000000206 00000 n \ntrailer\n<<\n/Size 5\n/Root 1 0 R\n>>\nstartxref\n299\n%%EOF")
-
-	w.Header().Set("Content-Length", strconv.Itoa(len(placeholder)))
-
-	if _, err := w.Write(placeholder); err != nil {
+	// Write file data
+	if _, err := w.Write(pdfData); err != nil {
 		h.logger.ErrorContext(ctx, "Failed to write PDF response", slog.String("error", err.Error()))
 		return
 	}
 
-	h.logger.InfoContext(ctx, "PDF placeholder response sent")
+	h.logger.InfoContext(ctx, "PDF export completed successfully",
+		slog.Int("total_rows", len(data)),
+		slog.String("filename", filename))
 }
 
 // Helper methods
@@ -297,18 +382,16 @@ func (h *ExportHandler) parseExportParams(r *http.Request) *ExportParams {
 
 // getInventoryData retrieves all inventory data based on export parameters
 func (h *ExportHandler) getInventoryData(ctx context.Context, params *ExportParams) ([]ExcelExportRow, error) {
-	query := h.buildExportQuery(params)
-
-	rows, err := h.db.Query(ctx, query, params.getQueryArgs()...)
+	rows, err := h.queryInventoryRows(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query inventory data: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	var data []ExcelExportRow
 	for rows.Next() {
-		var item ExcelExportRow
-		if err := rows.Scan(&item); err != nil {
+		item, err := scanExportRow(rows)
+		if err != nil {
 			h.logger.WarnContext(ctx, "Failed to scan inventory row", slog.String("error", err.Error()))
 			continue
 		}
@@ -322,9 +405,47 @@ func (h *ExportHandler) getInventoryData(ctx context.Context, params *ExportPara
 	return data, nil
 }
 
+// queryInventoryRows runs the export query. The caller must close the returned rows.
+func (h *ExportHandler) queryInventoryRows(ctx context.Context, params *ExportParams) (pgx.Rows, error) {
+	rows, err := h.db.Query(ctx, h.buildExportQuery(params), params.getQueryArgs()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory data: %w", err)
+	}
+	return rows, nil
+}
+
+// scanExportRow scans the current row, which must have been selected with exportSelectColumns
+func scanExportRow(rows pgx.Rows) (ExcelExportRow, error) {
+	var item ExcelExportRow
+	err := rows.Scan(
+		&item.LotID, &item.InvoiceID, &item.AuctionID, &item.ItemName, &item.Description,
+		&item.Category, &item.Condition, &item.Quantity,
+		&item.BidAmount, &item.BuyersPremium, &item.SalesTax, &item.ShippingCost,
+		&item.TotalCost, &item.CostPerItem, &item.AcquisitionDate,
+		&item.StorageLocation, &item.StorageBin,
+		&item.EbayListed, &item.EbayPrice, &item.EbayURL, &item.EbaySold,
+		&item.EtsyListed, &item.EtsyPrice, &item.EtsyURL, &item.EtsySold,
+		&item.SalePrice, &item.NetProfit, &item.ROIPercent, &item.DaysToSell,
+		&item.CreatedAt, &item.UpdatedAt,
+	)
+	return item, err
+}
+
+// exportSelectColumns lists the view columns in ExcelExportRow scan order. Numeric columns are
+// cast to float8 and nullable columns backing non-pointer fields are coalesced so they scan cleanly.
+const exportSelectColumns = `lot_id::text, invoice_id, COALESCE(auction_id, 0), item_name, COALESCE(description, ''),
+	COALESCE(category, ''), COALESCE(condition, ''), COALESCE(quantity, 1),
+	bid_amount::float8, buyers_premium::float8, sales_tax::float8, shipping_cost::float8,
+	total_cost::float8, cost_per_item::float8, acquisition_date,
+	storage_location, storage_bin,
+	COALESCE(ebay_listed, false), ebay_price::float8, ebay_url, COALESCE(ebay_sold, false),
+	COALESCE(etsy_listed, false), etsy_price::float8, etsy_url, COALESCE(etsy_sold, false),
+	sale_price::float8, net_profit::float8, roi_percent::float8, days_to_sell::int,
+	created_at, updated_at`
+
 // buildExportQuery constructs the SQL query based on export parameters
 func (h *ExportHandler) buildExportQuery(params *ExportParams) string {
-	query := "SELECT * FROM inventory_excel_export_mat WHERE 1=1"
+	query := "SELECT " + exportSelectColumns + " FROM inventory_excel_export_mat WHERE 1=1"
 
 	if params.DateFrom != nil {
 		query += " AND acquisition_date >= $1"
@@ -378,8 +499,8 @@ func (h *ExportHandler) generateExcelFile(data []ExcelExportRow, params *ExportP
 	}
 
 	// Auto-fit column widths (approximate)
-	for i := 0; i < len(headers); i++ {
-		sheet.SetColWidth(i, i, 15) // Set reasonable default width
+	for i := 1; i <= len(headers); i++ {
+		sheet.SetColWidth(i, i, 15) // Set reasonable default width (columns are 1-based)
 	}
 
 	// Save to buffer
@@ -391,114 +512,153 @@ func (h *ExportHandler) generateExcelFile(data []ExcelExportRow, params *ExportP
 	return buffer.Bytes(), nil
 }
 
-// getExcelHeaders returns the appropriate headers based on requested columns
-func (h *ExportHandler) getExcelHeaders(columns []string) []string {
-	allHeaders := []string{
-		"Lot ID", "Invoice ID", "Auction ID", "Item Name", "Description",
-		"Category", "Condition", "Quantity", "Bid Amount", "Buyer's Premium",
-		"Sales Tax", "Shipping Cost", "Total Cost", "Cost Per Item",
-		"Acquisition Date", "Storage Location", "Storage Bin",
-		"eBay Listed", "eBay Price", "eBay URL", "eBay Sold",
-		"Etsy Listed", "Etsy Price", "Etsy URL", "Etsy Sold",
-		"Sale Price", "Net Profit", "ROI %", "Days to Sell",
-		"Created At", "Updated At",
+// generatePDFReport renders the inventory data as a tabular PDF report with a summary footer
+func (h *ExportHandler) generatePDFReport(data []ExcelExportRow, params *ExportParams) []byte {
+	headers := h.getExcelHeaders(params.Columns)
+
+	rows := make([][]string, 0, len(data))
+	var totalCost float64
+	for _, item := range data {
+		rows = append(rows, h.itemToExcelRow(&item, params.Columns))
+		if item.TotalCost != nil {
+			totalCost += *item.TotalCost
+		}
 	}
 
+	subtitle := "Generated " + time.Now().Format("2006-01-02 15:04")
+	if params.DateFrom != nil {
+		subtitle += " | Acquired from " + h.safeDateValue(params.DateFrom)
+	}
+	if params.DateTo != nil {
+		subtitle += " | Acquired through " + h.safeDateValue(params.DateTo)
+	}
+
+	report := &pdfReport{
+		Title:    "Inventory Report",
+		Subtitle: subtitle,
+		Headers:  headers,
+		Rows:     rows,
+		Footer: []string{
+			fmt.Sprintf("Total Items: %d", len(data)),
+			fmt.Sprintf("Total Cost: $%.2f", totalCost),
+		},
+	}
+
+	return report.render()
+}
+
+// exportColumn describes a single exportable column: its query key, header label and cell value
+type exportColumn struct {
+	Key    string
+	Header string
+	Value  func(h *ExportHandler, item *ExcelExportRow) string
+}
+
+// exportColumns is the canonical, ordered list of columns shared by all tabular exporters
+var exportColumns = []exportColumn{
+	{"lot_id", "Lot ID", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeStringValue(item.LotID) }},
+	{"invoice_id", "Invoice ID", func(h *ExportHandler, item *ExcelExportRow) string { return item.InvoiceID }},
+	{"auction_id", "Auction ID", func(h *ExportHandler, item *ExcelExportRow) string { return strconv.Itoa(item.AuctionID) }},
+	{"item_name", "Item Name", func(h *ExportHandler, item *ExcelExportRow) string { return item.ItemName }},
+	{"description", "Description", func(h *ExportHandler, item *ExcelExportRow) string { return item.Description }},
+	{"category", "Category", func(h *ExportHandler, item *ExcelExportRow) string { return item.Category }},
+	{"condition", "Condition", func(h *ExportHandler, item *ExcelExportRow) string { return item.Condition }},
+	{"quantity", "Quantity", func(h *ExportHandler, item *ExcelExportRow) string { return strconv.Itoa(item.Quantity) }},
+	{"bid_amount", "Bid Amount", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.BidAmount) }},
+	{"buyers_premium", "Buyer's Premium", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.BuyersPremium) }},
+	{"sales_tax", "Sales Tax", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.SalesTax) }},
+	{"shipping_cost", "Shipping Cost", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.ShippingCost) }},
+	{"total_cost", "Total Cost", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.TotalCost) }},
+	{"cost_per_item", "Cost Per Item", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.CostPerItem) }},
+	{"acquisition_date", "Acquisition Date", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeDateValue(item.AcquisitionDate) }},
+	{"storage_location", "Storage Location", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeStringValue(item.StorageLocation) }},
+	{"storage_bin", "Storage Bin", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeStringValue(item.StorageBin) }},
+	{"ebay_listed", "eBay Listed", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeBoolValue(item.EbayListed) }},
+	{"ebay_price", "eBay Price", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.EbayPrice) }},
+	{"ebay_url", "eBay URL", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeStringValue(item.EbayURL) }},
+	{"ebay_sold", "eBay Sold", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeBoolValue(item.EbaySold) }},
+	{"etsy_listed", "Etsy Listed", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeBoolValue(item.EtsyListed) }},
+	{"etsy_price", "Etsy Price", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.EtsyPrice) }},
+	{"etsy_url", "Etsy URL", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeStringValue(item.EtsyURL) }},
+	{"etsy_sold", "Etsy Sold", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeBoolValue(item.EtsySold) }},
+	{"sale_price", "Sale Price", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.SalePrice) }},
+	{"net_profit", "Net Profit", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.NetProfit) }},
+	{"roi_percent", "ROI %", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeFloatValue(item.ROIPercent) }},
+	{"days_to_sell", "Days to Sell", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeIntValue(item.DaysToSell) }},
+	{"created_at", "Created At", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeTimestampValue(item.CreatedAt) }},
+	{"updated_at", "Updated At", func(h *ExportHandler, item *ExcelExportRow) string { return h.safeTimestampValue(item.UpdatedAt) }},
+}
+
+// selectExportColumns resolves the requested column keys to their definitions, in request order
+func (h *ExportHandler) selectExportColumns(columns []string) []exportColumn {
 	if len(columns) == 1 && columns[0] == "all" {
-		return allHeaders
-	}
-
-	// Map requested columns to headers
-	headerMap := map[string]string{
-		"lot_id":           "Lot ID",
-		"invoice_id":       "Invoice ID",
-		"auction_id":       "Auction ID",
-		"item_name":        "Item Name",
-		"description":      "Description",
-		"category":         "Category",
-		"condition":        "Condition",
-		"quantity":         "Quantity",
-		"bid_amount":       "Bid Amount",
-		"buyers_premium":   "Buyer's Premium",
-		"sales_tax":        "Sales Tax",
-		"shipping_cost":    "Shipping Cost",
-		"total_cost":       "Total Cost",
-		"cost_per_item":    "Cost Per Item",
-		"acquisition_date": "Acquisition Date",
-		"storage_location": "Storage Location",
-		"storage_bin":      "Storage Bin",
-		"ebay_listed":      "eBay Listed",
-		"ebay_price":       "eBay Price",
-		"ebay_url":         "eBay URL",
-		"ebay_sold":        "eBay Sold",
-		"etsy_listed":      "Etsy Listed",
-		"etsy_price":       "Etsy Price",
-		"etsy_url":         "Etsy URL",
-		"etsy_sold":        "Etsy Sold",
-		"sale_price":       "Sale Price",
-		"net_profit":       "Net Profit",
-		"roi_percent":      "ROI %",
-		"days_to_sell":     "Days to Sell",
-		"created_at":       "Created At",
-		"updated_at":       "Updated At",
-	}
-
-	var selectedHeaders []string
+		return exportColumns
+	}
+
+	var selected []exportColumn
 	for _, col := range columns {
-		if header, exists := headerMap[col]; exists {
-			selectedHeaders = append(selectedHeaders, header)
+		for _, def := range exportColumns {
+			if def.Key == col {
+				selected = append(selected, def)
+				break
+			}
 		}
 	}
 
-	if len(selectedHeaders) == 0 {
-		return allHeaders // Fallback to all headers if none match
+	if len(selected) == 0 {
+		return exportColumns // Fallback to all columns if none match
 	}
 
-	return selectedHeaders
+	return selected
 }
 
-// itemToExcelRow converts a data item to Excel row values
-func (h *ExportHandler) itemToExcelRow(item *ExcelExportRow, columns []string) []string {
-	allValues := []string{
-		h.safeStringValue(item.LotID),
-		item.InvoiceID,
-		strconv.Itoa(item.AuctionID),
-		item.ItemName,
-		item.Description,
-		item.Category,
-		item.Condition,
-		strconv.Itoa(item.Quantity),
-		h.safeFloatValue(item.BidAmount),
-		h.safeFloatValue(item.BuyersPremium),
-		h.safeFloatValue(item.SalesTax),
-		h.safeFloatValue(item.ShippingCost),
-		h.safeFloatValue(item.TotalCost),
-		h.safeFloatValue(item.CostPerItem),
-		h.safeDateValue(item.AcquisitionDate),
-		h.safeStringValue(item.StorageLocation),
-		h.safeStringValue(item.StorageBin),
-		h.safeBoolValue(item.EbayListed),
-		h.safeFloatValue(item.EbayPrice),
-		h.safeStringValue(item.EbayURL),
-		h.safeBoolValue(item.EbaySold),
-		h.safeBoolValue(item.EtsyListed),
-		h.safeFloatValue(item.EtsyPrice),
-		h.safeStringValue(item.EtsyURL),
-		h.safeBoolValue(item.EtsySold),
-		h.safeFloatValue(item.SalePrice),
-		h.safeFloatValue(item.NetProfit),
-		h.safeFloatValue(item.ROIPercent),
-		h.safeIntValue(item.DaysToSell),
-		item.CreatedAt.Format("2006-01-02 15:04:05"),
-		item.UpdatedAt.Format("2006-01-02 15:04:05"),
+// streamExcelFile writes an unstyled workbook directly to w one row at a time
+func (h *ExportHandler) streamExcelFile(w io.Writer, data []ExcelExportRow, params *ExportParams) error {
+	writer, err := newXLSXStreamWriter(w, "Inventory")
+	if err != nil {
+		return err
 	}
 
-	if len(columns) == 1 && columns[0] == "all" {
-		return allValues
+	if err := writer.WriteRow(h.getExcelHeaders(params.Columns)); err != nil {
+		return err
 	}
 
-	// Return only requested columns - would need column mapping logic here
-	return allValues // For simplicity, returning all for now
+	for _, item := range data {
+		if err := writer.WriteRow(h.itemToExcelRow(&item, params.Columns)); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// setExcelHeaders sets the response headers shared by buffered and streamed Excel exports
+func (h *ExportHandler) setExcelHeaders(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+}
+
+// getExcelHeaders returns the appropriate headers based on requested columns
+func (h *ExportHandler) getExcelHeaders(columns []string) []string {
+	selected := h.selectExportColumns(columns)
+
+	headers := make([]string, len(selected))
+	for i, col := range selected {
+		headers[i] = col.Header
+	}
+	return headers
+}
+
+// itemToExcelRow converts a data item to Excel row values aligned with getExcelHeaders
+func (h *ExportHandler) itemToExcelRow(item *ExcelExportRow, columns []string) []string {
+	selected := h.selectExportColumns(columns)
+
+	values := make([]string, len(selected))
+	for i, col := range selected {
+		values[i] = col.Value(h, item)
+	}
+	return values
 }
 
 // itemToJSONMap converts a data item to a JSON-friendly map
@@ -574,6 +734,10 @@ func (h *ExportHandler) safeDateValue(value *time.Time) string {
 	return value.Format("2006-01-02")
 }
 
+func (h *ExportHandler) safeTimestampValue(value time.Time) string {
+	return value.Format("2006-01-02 15:04:05")
+}
+
 func (h *ExportHandler) safeBoolValue(value bool) string {
 	if value {
 		return "Yes"
@@ -600,19 +764,6 @@ func (h *ExportHandler) getCacheKeyFromParams(params *ExportParams) string {
 	return key
 }
 
-func (h *ExportHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	response := map[string]string{
-		"error":   message,
-		"status":  "error",
-		"message": message,
-	}
-
-	json.NewEncoder(w).Encode(response)
-}
-
 // getQueryArgs returns the query arguments based on export parameters
 func (params *ExportParams) getQueryArgs() []any {
 	var args []any
@@ -0,0 +1,258 @@
+// internal/handlers/export_labels.go
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/pkg/qrcode"
+)
+
+// Label sheet geometry (US Letter, portrait) in points. The defaults match Avery 5160/8160
+// address labels: three columns of ten 2.625" x 1" labels.
+const (
+	labelPageWidth     = 612.0
+	labelPageHeight    = 792.0
+	labelMarginX       = 13.5
+	labelMarginY       = 36.0
+	labelColumnGap     = 9.0
+	labelPadding       = 5.0
+	labelNameFontSize  = 8.0
+	labelSmallFontSize = 6.0
+	labelLineGap       = 2.0
+
+	defaultLabelRows    = 10
+	defaultLabelColumns = 3
+	maxLabelRows        = 30
+	maxLabelColumns     = 6
+
+	// maxLabelItems caps a sheet at one listing page
+	maxLabelItems = 1000
+)
+
+// label is the content of one label cell
+type label struct {
+	Name    string
+	LotID   string
+	Storage string
+	QR      *qrcode.Code
+}
+
+// labelSheet lays labels out in a grid, filling each page row by row
+type labelSheet struct {
+	Rows    int
+	Columns int
+	Labels  []label
+}
+
+// cellSize returns the width and height of one label
+func (s *labelSheet) cellSize() (float64, float64) {
+	width := (labelPageWidth - 2*labelMarginX - float64(s.Columns-1)*labelColumnGap) / float64(s.Columns)
+	height := (labelPageHeight - 2*labelMarginY) / float64(s.Rows)
+	return width, height
+}
+
+// render returns the sheet as a PDF, with a blank page when there are no labels
+func (s *labelSheet) render() []byte {
+	width, height := s.cellSize()
+	perPage := s.Rows * s.Columns
+
+	pages := make([]string, 0, len(s.Labels)/perPage+1)
+	var page strings.Builder
+	for i, l := range s.Labels {
+		cell := i % perPage
+		if i > 0 && cell == 0 {
+			pages = append(pages, page.String())
+			page.Reset()
+		}
+		x := labelMarginX + float64(cell%s.Columns)*(width+labelColumnGap)
+		y := labelPageHeight - labelMarginY - float64(cell/s.Columns+1)*height
+
+		// Each label is drawn in its own coordinate space, origin at the cell's bottom left
+		fmt.Fprintf(&page, "q 1 0 0 1 %.2f %.2f cm\n", x, y)
+		writeLabel(&page, l, width, height)
+		page.WriteString("Q\n")
+	}
+	pages = append(pages, page.String())
+
+	return writePDF(pages, labelPageWidth, labelPageHeight)
+}
+
+// writeLabel draws the QR code on the left of the cell and the text beside it
+func writeLabel(sb *strings.Builder, l label, width, height float64) {
+	qrSide := min(height, width/2) - 2*labelPadding
+	textX := labelPadding
+	if l.QR != nil && qrSide > 0 {
+		writeQRCode(sb, l.QR, labelPadding, (height-qrSide)/2, qrSide)
+		textX += qrSide + labelPadding
+	}
+	maxChars := func(size float64) int {
+		return int((width - textX - labelPadding) / (size * pdfAvgGlyphWidth))
+	}
+
+	y := height - labelPadding - labelNameFontSize
+	for _, line := range wrapText(l.Name, maxChars(labelNameFontSize), 2) {
+		writeText(sb, "F2", labelNameFontSize, textX, y, line)
+		y -= labelNameFontSize + labelLineGap
+	}
+	if l.Storage != "" {
+		writeText(sb, "F1", labelSmallFontSize+1, textX, y, truncateCell(l.Storage, maxChars(labelSmallFontSize+1)))
+	}
+	writeText(sb, "F1", labelSmallFontSize, textX, labelPadding, truncateCell(l.LotID, maxChars(labelSmallFontSize)))
+}
+
+// writeQRCode fills the code's dark modules, one rectangle per horizontal run, in a square of
+// side points whose bottom left corner is at x, y. The square includes the quiet zone.
+func writeQRCode(sb *strings.Builder, code *qrcode.Code, x, y, side float64) {
+	module := side / float64(code.Size+2*qrcode.QuietZone)
+	left := x + float64(qrcode.QuietZone)*module
+	top := y + side - float64(qrcode.QuietZone)*module
+
+	sb.WriteString("0 g\n")
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; {
+			if !code.Module(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < code.Size && code.Module(col, row) {
+				col++
+			}
+			fmt.Fprintf(sb, "%.3f %.3f %.3f %.3f re\n",
+				left+float64(start)*module, top-float64(row+1)*module, float64(col-start)*module, module)
+		}
+	}
+	sb.WriteString("f\n")
+}
+
+// wrapText breaks text into at most maxLines lines of maxChars, truncating what doesn't fit
+func wrapText(text string, maxChars, maxLines int) []string {
+	words := strings.Fields(text)
+	var lines []string
+	for len(words) > 0 && len(lines) < maxLines {
+		line := words[0]
+		words = words[1:]
+		for len(words) > 0 && len([]rune(line))+1+len([]rune(words[0])) <= maxChars {
+			line += " " + words[0]
+			words = words[1:]
+		}
+		lines = append(lines, line)
+	}
+	if len(words) > 0 && len(lines) > 0 {
+		lines[len(lines)-1] += " " + strings.Join(words, " ")
+	}
+	for i, line := range lines {
+		lines[i] = truncateCell(line, maxChars)
+	}
+	return lines
+}
+
+// parseLabelGrid reads the rows and columns per page, defaulting to the Avery 5160 grid
+func parseLabelGrid(r *http.Request) (int, int, error) {
+	rows, columns := defaultLabelRows, defaultLabelColumns
+	if v := r.URL.Query().Get("rows"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLabelRows {
+			return 0, 0, fmt.Errorf("rows must be between 1 and %d", maxLabelRows)
+		}
+		rows = n
+	}
+	if v := r.URL.Query().Get("columns"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLabelColumns {
+			return 0, 0, fmt.Errorf("columns must be between 1 and %d", maxLabelColumns)
+		}
+		columns = n
+	}
+	return rows, columns, nil
+}
+
+// storageText describes where an item is kept, e.g. "Garage / Bin A3"
+func storageText(item *domain.InventoryItem) string {
+	switch {
+	case item.StorageLocation != "" && item.StorageBin != "":
+		return item.StorageLocation + " / Bin " + item.StorageBin
+	case item.StorageBin != "":
+		return "Bin " + item.StorageBin
+	default:
+		return item.StorageLocation
+	}
+}
+
+// ExportLabels handles GET /api/v1/export/labels, rendering a PDF sheet of item labels with
+// each item's QR code. Items are selected with the same query filters as ListInventory, and
+// rows and columns set the grid per page.
+func (h *QRCodeHandler) ExportLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params, err := parseListParams(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		return
+	}
+	rows, columns, err := parseLabelGrid(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		return
+	}
+
+	// One page of the listing holds every label; larger selections must be narrowed
+	params.Page, params.PageSize = 1, maxLabelItems
+	params.AfterCreatedAt, params.AfterLotID = nil, uuid.Nil
+	params.IncludeTotals, params.Highlight = false, false
+
+	result, err := h.service.List(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list items for labels",
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve data")
+		return
+	}
+	if result.TotalCount > maxLabelItems {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest,
+			fmt.Sprintf("%d items match; narrow the filters to at most %d labels", result.TotalCount, maxLabelItems))
+		return
+	}
+
+	sheet := &labelSheet{Rows: rows, Columns: columns, Labels: make([]label, 0, len(result.Items))}
+	for _, item := range result.Items {
+		code, err := qrcode.Encode(item.QRPayload(h.template), qrcode.LevelM)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to encode qr code",
+				slog.String("lot_id", item.LotID.String()),
+				slog.String("error", err.Error()))
+			writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to generate QR code")
+			return
+		}
+		sheet.Labels = append(sheet.Labels, label{
+			Name:    item.ItemName,
+			LotID:   item.LotID.String(),
+			Storage: storageText(item),
+			QR:      code,
+		})
+	}
+	pdfData := sheet.render()
+
+	filename := fmt.Sprintf("inventory_labels_%s.pdf", time.Now().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdfData)))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	if _, err := w.Write(pdfData); err != nil {
+		h.logger.ErrorContext(ctx, "failed to write label sheet", slog.String("error", err.Error()))
+		return
+	}
+
+	h.logger.InfoContext(ctx, "label sheet export completed",
+		slog.Int("labels", len(sheet.Labels)),
+		slog.String("filename", filename))
+}
@@ -0,0 +1,218 @@
+// internal/handlers/export_pdf.go
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PDF page geometry (US Letter, landscape) in points
+const (
+	pdfPageWidth      = 792.0
+	pdfPageHeight     = 612.0
+	pdfMargin         = 36.0
+	pdfTitleFontSize  = 14.0
+	pdfBodyFontSize   = 7.0
+	pdfMinFontSize    = 3.5
+	pdfMaxCellChars   = 40
+	pdfMaxHeaderChars = 10
+	pdfRowHeight      = 11.0
+	pdfAvgGlyphWidth  = 0.5 // approximate Helvetica glyph width as a fraction of font size
+	pdfCellPadding    = 2.0
+	pdfHeaderBlockGap = 30.0
+)
+
+// pdfReport describes a simple tabular report rendered as a PDF document
+type pdfReport struct {
+	Title    string
+	Subtitle string
+	Headers  []string
+	Rows     [][]string
+	Footer   []string
+}
+
+// render lays out the report across as many pages as needed and returns the PDF bytes
+func (r *pdfReport) render() []byte {
+	return writePDF(r.layoutPages(), pdfPageWidth, pdfPageHeight)
+}
+
+// writePDF assembles a document from page content streams drawn with the Helvetica (/F1) and
+// Helvetica-Bold (/F2) base fonts
+func writePDF(pages []string, pageWidth, pageHeight float64) []byte {
+	var buf bytes.Buffer
+	offsets := make([]int, 0, 4+2*len(pages))
+	writeObj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are fixed: catalog, page tree and the two fonts.
+	// Each page then takes two objects: the page itself and its content stream.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range pages {
+		writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		writeObj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	return buf.Bytes()
+}
+
+// layoutPages builds the content stream for each page of the report
+func (r *pdfReport) layoutPages() []string {
+	colWidths, fontSize := r.columnLayout()
+	colX := make([]float64, len(colWidths))
+	x := pdfMargin
+	for i, width := range colWidths {
+		colX[i] = x
+		x += width
+	}
+	maxChars := func(col int) int {
+		return int(math.Round((colWidths[col] - 2*pdfCellPadding) / (fontSize * pdfAvgGlyphWidth)))
+	}
+
+	top := pdfPageHeight - pdfMargin
+	bottom := pdfMargin + pdfRowHeight // keep room for the page number
+
+	var pages []string
+	var page strings.Builder
+	y := top
+
+	startPage := func() {
+		page.Reset()
+		y = top
+		if len(pages) == 0 {
+			writeText(&page, "F2", pdfTitleFontSize, pdfMargin, y-pdfTitleFontSize, r.Title)
+			if r.Subtitle != "" {
+				writeText(&page, "F1", pdfBodyFontSize+1, pdfMargin, y-pdfTitleFontSize-12, r.Subtitle)
+			}
+			y -= pdfHeaderBlockGap
+		}
+		y -= pdfRowHeight
+		for i, header := range r.Headers {
+			writeText(&page, "F2", fontSize, colX[i]+pdfCellPadding, y, truncateCell(header, maxChars(i)))
+		}
+		fmt.Fprintf(&page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y-3, pdfPageWidth-pdfMargin, y-3)
+	}
+	finishPage := func() {
+		writeText(&page, "F1", pdfBodyFontSize, pdfPageWidth-pdfMargin-40, pdfMargin, fmt.Sprintf("Page %d", len(pages)+1))
+		pages = append(pages, page.String())
+	}
+
+	startPage()
+	for _, row := range r.Rows {
+		if y-pdfRowHeight < bottom {
+			finishPage()
+			startPage()
+		}
+		y -= pdfRowHeight
+		for i := 0; i < len(r.Headers) && i < len(row); i++ {
+			writeText(&page, "F1", fontSize, colX[i]+pdfCellPadding, y, truncateCell(row[i], maxChars(i)))
+		}
+	}
+
+	// Summary footer, moved to a fresh page if it does not fit below the table
+	footerHeight := float64(len(r.Footer)+1) * pdfRowHeight
+	if y-footerHeight < bottom {
+		finishPage()
+		startPage()
+	}
+	y -= pdfRowHeight / 2
+	fmt.Fprintf(&page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+	for _, line := range r.Footer {
+		y -= pdfRowHeight
+		writeText(&page, "F2", pdfBodyFontSize+1, pdfMargin, y, line)
+	}
+	finishPage()
+
+	return pages
+}
+
+// columnLayout sizes each column to its widest cell (capped) and shrinks the
+// font when the natural table width exceeds the printable page width. Header
+// text counts for less than cell text since wide headers are cheap to truncate.
+func (r *pdfReport) columnLayout() ([]float64, float64) {
+	chars := make([]int, len(r.Headers))
+	for i, header := range r.Headers {
+		chars[i] = min(len([]rune(header)), pdfMaxHeaderChars)
+	}
+	for _, row := range r.Rows {
+		for i := 0; i < len(chars) && i < len(row); i++ {
+			chars[i] = max(chars[i], min(len([]rune(row[i])), pdfMaxCellChars))
+		}
+	}
+
+	totalChars := 0
+	for i := range chars {
+		chars[i] = max(chars[i], 1)
+		totalChars += chars[i]
+	}
+
+	// Text gets whatever is left of the printable width after cell padding
+	textWidth := pdfPageWidth - 2*pdfMargin - float64(len(chars))*2*pdfCellPadding
+	fontSize := pdfBodyFontSize
+	if natural := float64(totalChars) * fontSize * pdfAvgGlyphWidth; natural > textWidth {
+		fontSize = max(pdfMinFontSize, fontSize*textWidth/natural)
+	}
+
+	// Distribute the text width proportionally so the table always spans the page
+	widths := make([]float64, len(chars))
+	for i, c := range chars {
+		widths[i] = textWidth*float64(c)/float64(totalChars) + 2*pdfCellPadding
+	}
+
+	return widths, fontSize
+}
+
+// writeText appends a single positioned text run to a content stream
+func writeText(sb *strings.Builder, font string, size, x, y float64, text string) {
+	fmt.Fprintf(sb, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFText(text))
+}
+
+// truncateCell shortens text to fit a table cell, marking the cut with an ellipsis
+func truncateCell(text string, maxChars int) string {
+	runes := []rune(text)
+	if maxChars <= 3 || len(runes) <= maxChars {
+		return text
+	}
+	return string(runes[:maxChars-3]) + "..."
+}
+
+// escapePDFText escapes PDF string delimiters and replaces characters the base fonts cannot encode
+func escapePDFText(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			sb.WriteRune(' ')
+		case r < 32 || r > 126:
+			sb.WriteRune('?')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -2,10 +2,16 @@
 package handlers_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -14,6 +20,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tealeg/xlsx/v3"
 	"go.uber.org/mock/gomock"
 
 	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
@@ -23,9 +30,10 @@ import (
 	"github.com/ammerola/resell-be/test/mocks"
 )
 
-// MockRows implements pgx.Rows interface for testing
+// MockRows implements pgx.Rows interface for testing. Each row holds raw column
+// values in export query order; nil represents SQL NULL.
 type mockRows struct {
-	data   []handlers.ExcelExportRow
+	values [][]any
 	index  int
 	closed bool
 }
@@ -39,7 +47,7 @@ func (m *mockRows) Err() error {
 }
 
 func (m *mockRows) Next() bool {
-	if m.index < len(m.data) {
+	if m.index < len(m.values) {
 		m.index++
 		return true
 	}
@@ -47,9 +55,30 @@ func (m *mockRows) Next() bool {
 }
 
 func (m *mockRows) Scan(dest ...interface{}) error {
-	if m.index == 0 || m.index > len(m.data) {
+	if m.index == 0 || m.index > len(m.values) {
 		return pgx.ErrNoRows
 	}
+	row := m.values[m.index-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("scan expected %d destinations, got %d", len(row), len(dest))
+	}
+
+	for i, value := range row {
+		target := reflect.ValueOf(dest[i]).Elem()
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+
+		v := reflect.ValueOf(value)
+		if target.Kind() == reflect.Ptr {
+			ptr := reflect.New(target.Type().Elem())
+			ptr.Elem().Set(v)
+			target.Set(ptr)
+			continue
+		}
+		target.Set(v)
+	}
 	return nil
 }
 
@@ -73,15 +102,38 @@ func (m *mockRows) CommandTag() pgconn.CommandTag {
 	return pgconn.CommandTag{}
 }
 
-func createMockRows() pgx.Rows {
-	return &mockRows{
-		data: []handlers.ExcelExportRow{
-			{
-				InvoiceID: "INV-001",
-				ItemName:  "Test Item",
-			},
-		},
+// newMockRows builds a fake result set from export rows, flattening each into column order
+func newMockRows(items ...handlers.ExcelExportRow) *mockRows {
+	rows := &mockRows{}
+	for _, item := range items {
+		rows.values = append(rows.values, []any{
+			nullable(item.LotID), item.InvoiceID, item.AuctionID, item.ItemName, item.Description,
+			item.Category, item.Condition, item.Quantity,
+			nullable(item.BidAmount), nullable(item.BuyersPremium), nullable(item.SalesTax), nullable(item.ShippingCost),
+			nullable(item.TotalCost), nullable(item.CostPerItem), nullable(item.AcquisitionDate),
+			nullable(item.StorageLocation), nullable(item.StorageBin),
+			item.EbayListed, nullable(item.EbayPrice), nullable(item.EbayURL), item.EbaySold,
+			item.EtsyListed, nullable(item.EtsyPrice), nullable(item.EtsyURL), item.EtsySold,
+			nullable(item.SalePrice), nullable(item.NetProfit), nullable(item.ROIPercent), nullable(item.DaysToSell),
+			item.CreatedAt, item.UpdatedAt,
+		})
 	}
+	return rows
+}
+
+// nullable dereferences a pointer column value, mapping nil to SQL NULL
+func nullable[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func createMockRows() pgx.Rows {
+	return newMockRows(handlers.ExcelExportRow{
+		InvoiceID: "INV-001",
+		ItemName:  "Test Item",
+	})
 }
 
 func TestExportHandler_ExportJSON(t *testing.T) {
@@ -96,20 +148,17 @@ func TestExportHandler_ExportJSON(t *testing.T) {
 			name:        "exports_json_with_default_params",
 			queryParams: map[string]string{},
 			setupMocks: func(db *mocks.MockDatabase, cache *mocks.MockCacheRepository) {
-				// Cache miss
+				// Cache miss loads and caches the export
 				cache.EXPECT().
-					Get(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(redis_a.ErrCacheMiss)
+					GetOrSet(gomock.Any(), gomock.Any(), 5*time.Minute, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ time.Duration, loader func() ([]byte, error), _ ...ports.GetOrSetOption) ([]byte, error) {
+						return loader()
+					})
 
 				// Query database
 				db.EXPECT().
 					Query(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(createMockRows(), nil)
-
-				// Cache result
-				cache.EXPECT().
-					Set(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateBody: func(t *testing.T, body []byte) {
@@ -132,7 +181,7 @@ func TestExportHandler_ExportJSON(t *testing.T) {
 			mockService := mocks.NewMockInventoryService(ctrl)
 			logger := helpers.TestLogger()
 
-			handler := handlers.NewExportHandler(mockService, mockDB, mockCache, logger)
+			handler := handlers.NewExportHandler(mockService, mockDB, mockCache, logger, 0)
 
 			tt.setupMocks(mockDB, mockCache)
 
@@ -162,6 +211,65 @@ func TestExportHandler_ExportJSON(t *testing.T) {
 	}
 }
 
+func TestExportHandler_ScansColumnsIntoFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockService := mocks.NewMockInventoryService(ctrl)
+	handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 0)
+
+	acquired := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2024, 3, 16, 9, 30, 0, 0, time.UTC)
+	rows := &mockRows{values: [][]any{{
+		"6f1c1b2e-8d1a-4c55-9a8e-2d7f0b3c4e5a", "INV-042", 7, "Sterling Tea Set", "Five piece set",
+		"silver", "excellent", 2,
+		150.0, 27.0, nil, 12.5,
+		189.5, 94.75, acquired,
+		"Garage", nil,
+		true, 325.0, "https://ebay.example/itm/1", false,
+		false, nil, nil, false,
+		nil, nil, nil, nil,
+		created, created,
+	}}}
+
+	mockDB.EXPECT().
+		Query(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+			assert.NotContains(t, sql, "SELECT *")
+			return rows, nil
+		})
+
+	req := httptest.NewRequest("GET", "/api/v1/export/json", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportJSON(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response handlers.JSONExportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Inventory, 1)
+
+	item := response.Inventory[0]
+	assert.Equal(t, "6f1c1b2e-8d1a-4c55-9a8e-2d7f0b3c4e5a", item["lot_id"])
+	assert.Equal(t, "INV-042", item["invoice_id"])
+	assert.EqualValues(t, 7, item["auction_id"])
+	assert.Equal(t, "Sterling Tea Set", item["item_name"])
+	assert.Equal(t, "silver", item["category"])
+	assert.EqualValues(t, 2, item["quantity"])
+	assert.EqualValues(t, 150.0, item["bid_amount"])
+	assert.Nil(t, item["sales_tax"])
+	assert.EqualValues(t, 189.5, item["total_cost"])
+	assert.Equal(t, "Garage", item["storage_location"])
+	assert.Nil(t, item["storage_bin"])
+	assert.Equal(t, true, item["ebay_listed"])
+	assert.EqualValues(t, 325.0, item["ebay_price"])
+	assert.Equal(t, "https://ebay.example/itm/1", item["ebay_url"])
+	assert.Nil(t, item["etsy_url"])
+	assert.Nil(t, item["days_to_sell"])
+}
+
 func TestExportHandler_ExportExcel(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -171,7 +279,7 @@ func TestExportHandler_ExportExcel(t *testing.T) {
 	mockService := mocks.NewMockInventoryService(ctrl)
 	logger := helpers.TestLogger()
 
-	handler := handlers.NewExportHandler(mockService, mockDB, mockCache, logger)
+	handler := handlers.NewExportHandler(mockService, mockDB, mockCache, logger, 0)
 
 	// Setup mock expectations
 	mockDB.EXPECT().
@@ -194,6 +302,353 @@ func TestExportHandler_ExportExcel(t *testing.T) {
 	assert.NotEmpty(t, w.Body.Bytes())
 }
 
+func TestExportHandler_ExportPDF(t *testing.T) {
+	totalCost := 125.50
+
+	tests := []struct {
+		name        string
+		queryParams map[string]string
+		setupMocks  func(*mocks.MockDatabase)
+	}{
+		{
+			name:        "exports_pdf_with_default_params",
+			queryParams: map[string]string{},
+			setupMocks: func(db *mocks.MockDatabase) {
+				db.EXPECT().
+					Query(gomock.Any(), gomock.Any()).
+					Return(newMockRows(
+						handlers.ExcelExportRow{InvoiceID: "INV-001", ItemName: "Test Item", TotalCost: &totalCost},
+					), nil)
+			},
+		},
+		{
+			name: "exports_pdf_with_date_range",
+			queryParams: map[string]string{
+				"date_from": "2024-01-01",
+				"date_to":   "2024-12-31",
+			},
+			setupMocks: func(db *mocks.MockDatabase) {
+				from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+				db.EXPECT().
+					Query(gomock.Any(), gomock.Any(), from, to).
+					Return(newMockRows(
+						handlers.ExcelExportRow{InvoiceID: "INV-001", ItemName: "Test Item", TotalCost: &totalCost},
+					), nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 0)
+
+			tt.setupMocks(mockDB)
+
+			req := httptest.NewRequest("GET", "/api/v1/export/pdf", nil)
+			q := req.URL.Query()
+			for k, v := range tt.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			w := httptest.NewRecorder()
+
+			handler.ExportPDF(w, req)
+
+			resp := w.Result()
+			body := w.Body.Bytes()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+			assert.Contains(t, resp.Header.Get("Content-Disposition"), "inventory_report_")
+			assert.Equal(t, strconv.Itoa(len(body)), resp.Header.Get("Content-Length"))
+			assert.True(t, bytes.HasPrefix(body, []byte("%PDF")))
+			assert.Contains(t, string(body), "Test Item")
+			assert.Contains(t, string(body), "Total Cost: $125.50")
+			assert.Contains(t, string(body), "Total Items: 1")
+		})
+	}
+}
+
+func TestExportHandler_ExportCSV(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    map[string]string
+		expectedHeader []string
+	}{
+		{
+			name:        "selected_columns_in_requested_order",
+			queryParams: map[string]string{"columns": "item_name,total_cost,category"},
+			expectedHeader: []string{
+				"Item Name", "Total Cost", "Category",
+			},
+		},
+		{
+			name:        "unknown_columns_are_skipped",
+			queryParams: map[string]string{"columns": "item_name,bogus"},
+			expectedHeader: []string{
+				"Item Name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 0)
+
+			mockDB.EXPECT().
+				Query(gomock.Any(), gomock.Any()).
+				Return(createMockRows(), nil)
+
+			req := httptest.NewRequest("GET", "/api/v1/export/csv", nil)
+			q := req.URL.Query()
+			for k, v := range tt.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			w := httptest.NewRecorder()
+
+			handler.ExportCSV(w, req)
+
+			resp := w.Result()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+			assert.Contains(t, resp.Header.Get("Content-Disposition"), "inventory_export_")
+
+			header, err := csv.NewReader(w.Body).Read()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHeader, header)
+		})
+	}
+}
+
+// observedRows calls onNext before advancing, letting a test inspect the response mid-stream
+type observedRows struct {
+	*mockRows
+	onNext func(scanned int)
+}
+
+func (o *observedRows) Next() bool {
+	o.onNext(o.index)
+	return o.mockRows.Next()
+}
+
+func TestExportHandler_ExportCSV_StreamsRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockService := mocks.NewMockInventoryService(ctrl)
+	handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 0)
+
+	const total = 1200
+	items := make([]handlers.ExcelExportRow, total)
+	for i := range items {
+		items[i] = handlers.ExcelExportRow{InvoiceID: "INV-001", ItemName: fmt.Sprintf("Item %d", i)}
+	}
+
+	w := httptest.NewRecorder()
+	var bodyLenAfterFirstFlush int
+	rows := &observedRows{mockRows: newMockRows(items...), onNext: func(scanned int) {
+		if scanned == 500 {
+			bodyLenAfterFirstFlush = w.Body.Len()
+		}
+	}}
+	mockDB.EXPECT().
+		Query(gomock.Any(), gomock.Any()).
+		Return(rows, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/export/csv?columns=item_name", nil)
+	handler.ExportCSV(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, rows.closed)
+
+	// The first 500 records reach the client before the 501st row is scanned
+	records, err := csv.NewReader(bytes.NewReader(w.Body.Bytes()[:bodyLenAfterFirstFlush])).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 501)
+
+	records, err = csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, total+1)
+	assert.Equal(t, []string{"Item Name"}, records[0])
+	assert.Equal(t, []string{"Item 0"}, records[1])
+	assert.Equal(t, []string{"Item 1199"}, records[total])
+}
+
+func TestExportHandler_ExportCSV_QueryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockService := mocks.NewMockInventoryService(ctrl)
+	handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 0)
+
+	mockDB.EXPECT().
+		Query(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("connection refused"))
+
+	req := httptest.NewRequest("GET", "/api/v1/export/csv", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportCSV(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, handlers.ErrCodeInternal, decodeError(t, w.Body.Bytes()).Code)
+}
+
+func TestExportHandler_ExportExcel_SelectedColumns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockService := mocks.NewMockInventoryService(ctrl)
+	handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 0)
+
+	totalCost := 42.5
+	mockDB.EXPECT().
+		Query(gomock.Any(), gomock.Any()).
+		Return(newMockRows(
+			handlers.ExcelExportRow{InvoiceID: "INV-001", ItemName: "Brass Lamp", TotalCost: &totalCost},
+			handlers.ExcelExportRow{InvoiceID: "INV-002", ItemName: "Oak Chair"},
+		), nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/export/excel?columns=total_cost,item_name", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportExcel(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	file, err := xlsx.OpenBinary(w.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, file.Sheets, 1)
+
+	var rows [][]string
+	err = file.Sheets[0].ForEachRow(func(r *xlsx.Row) error {
+		var cells []string
+		if err := r.ForEachCell(func(c *xlsx.Cell) error {
+			cells = append(cells, c.Value)
+			return nil
+		}); err != nil {
+			return err
+		}
+		rows = append(rows, cells)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"Total Cost", "Item Name"},
+		{"42.50", "Brass Lamp"},
+		{"", "Oak Chair"},
+	}, rows)
+}
+
+func TestExportHandler_ExportExcel_StreamsAboveThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockService := mocks.NewMockInventoryService(ctrl)
+	handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), 1)
+
+	mockDB.EXPECT().
+		Query(gomock.Any(), gomock.Any()).
+		Return(newMockRows(
+			handlers.ExcelExportRow{InvoiceID: "INV-001", ItemName: "Brass Lamp"},
+			handlers.ExcelExportRow{InvoiceID: "INV-002", ItemName: "Oak Chair"},
+		), nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/export/excel", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportExcel(w, req)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		resp.Header.Get("Content-Type"))
+	assert.Empty(t, resp.Header.Get("Content-Length"))
+
+	file, err := xlsx.OpenBinary(w.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, file.Sheets, 1)
+
+	sheet := file.Sheets[0]
+	assert.Equal(t, 3, sheet.MaxRow)
+	assert.Equal(t, 31, sheet.MaxCol)
+
+	cell, err := sheet.Cell(2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Oak Chair", cell.Value)
+
+	cell, err = sheet.Cell(0, 30)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated At", cell.Value)
+}
+
+// BenchmarkExportHandler_ExportExcel compares the buffered and streamed Excel writers on 50k rows
+func BenchmarkExportHandler_ExportExcel(b *testing.B) {
+	const rowCount = 50000
+
+	totalCost := 189.5
+	items := make([]handlers.ExcelExportRow, rowCount)
+	for i := range items {
+		items[i] = handlers.ExcelExportRow{
+			InvoiceID: fmt.Sprintf("INV-%05d", i),
+			ItemName:  fmt.Sprintf("Benchmark Item %d", i),
+			Category:  "silver",
+			Quantity:  1,
+			TotalCost: &totalCost,
+		}
+	}
+	values := newMockRows(items...).values
+
+	benchmarks := []struct {
+		name            string
+		streamThreshold int
+	}{
+		{name: "buffered", streamThreshold: 0},
+		{name: "streamed", streamThreshold: 1},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ctrl := gomock.NewController(b)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewExportHandler(mockService, mockDB, newTestCacheMock(), helpers.TestLogger(), bm.streamThreshold)
+
+			mockDB.EXPECT().
+				Query(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(context.Context, string, ...any) (pgx.Rows, error) {
+					return &mockRows{values: values}, nil
+				}).
+				AnyTimes()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				req := httptest.NewRequest("GET", "/api/v1/export/excel", nil)
+				handler.ExportExcel(httptest.NewRecorder(), req)
+			}
+		})
+	}
+}
+
 // testCacheMock implements ports.CacheRepository for testing
 type testCacheMock struct {
 	mu       sync.RWMutex
@@ -331,33 +786,32 @@ func (m *testCacheMock) Expire(ctx context.Context, key string, ttl time.Duratio
 	return nil
 }
 
-// GetOrSet retrieves from cache or sets if not found
-func (m *testCacheMock) GetOrSet(ctx context.Context, key string, dest interface{},
-	fetch func() (interface{}, error), ttl time.Duration) error {
+// GetOrSet returns the raw cached bytes, loading and storing them on a miss
+func (m *testCacheMock) GetOrSet(ctx context.Context, key string, ttl time.Duration,
+	loader func() ([]byte, error), opts ...ports.GetOrSetOption) ([]byte, error) {
 
-	err := m.Get(ctx, key, dest)
-	if err == nil {
-		return nil // Cache hit
-	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	if err != redis_a.ErrCacheMiss {
-		return err
+	data, exists := m.data[key]
+	if expiry, hasTTL := m.ttls[key]; exists && (!hasTTL || time.Now().Before(expiry)) {
+		return data, nil // Cache hit
 	}
 
-	// Cache miss - fetch and store
-	value, err := fetch()
+	// Cache miss - load and store
+	data, err := loader()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Store in cache
-	if err := m.SetWithTTL(ctx, key, value, ttl); err != nil {
-		return err
+	m.data[key] = data
+	if ttl > 0 {
+		m.ttls[key] = time.Now().Add(ttl)
+	} else {
+		delete(m.ttls, key)
 	}
 
-	// Copy value to destination
-	data, _ := json.Marshal(value)
-	return json.Unmarshal(data, dest)
+	return data, nil
 }
 
 // Increment increments a counter
@@ -400,6 +854,25 @@ func (m *testCacheMock) SetNX(ctx context.Context, key string, value interface{}
 	return true, nil
 }
 
+// CompareAndDelete removes a key only if it still holds value
+func (m *testCacheMock) CompareAndDelete(ctx context.Context, key string, value interface{}) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	if current, exists := m.data[key]; !exists || !bytes.Equal(current, data) {
+		return false, nil
+	}
+
+	delete(m.data, key)
+	delete(m.ttls, key)
+	return true, nil
+}
+
 // TTL returns the time to live for a key
 func (m *testCacheMock) TTL(ctx context.Context, key string) (time.Duration, error) {
 	m.mu.RLock()
@@ -4,23 +4,31 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
 
-	"github.com/ammerola/resell-be/internal/adapters/db"
+	"github.com/ammerola/resell-be/internal/core/ports"
 	"github.com/ammerola/resell-be/internal/pkg/config"
 )
 
+// defaultReadinessTimeout bounds each readiness check when no timeout is configured
+const defaultReadinessTimeout = 2 * time.Second
+
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
-	db        *db.Database
+	db        ports.Database
 	redis     *redis.Client
-	asynq     *asynq.Inspector
+	asynq     ports.QueueInspector
+	migrator  ports.SchemaVersioner
 	config    *config.Config
 	logger    *slog.Logger
 	startTime time.Time
@@ -28,9 +36,10 @@ type HealthHandler struct {
 
 // NewHealthHandler creates a new health handler
 func NewHealthHandler(
-	database *db.Database,
+	database ports.Database,
 	redisClient *redis.Client,
-	asynqInspector *asynq.Inspector,
+	asynqInspector ports.QueueInspector,
+	migrator ports.SchemaVersioner,
 	cfg *config.Config,
 	logger *slog.Logger,
 ) *HealthHandler {
@@ -38,6 +47,7 @@ func NewHealthHandler(
 		db:        database,
 		redis:     redisClient,
 		asynq:     asynqInspector,
+		migrator:  migrator,
 		config:    cfg,
 		logger:    logger.With(slog.String("handler", "health")),
 		startTime: time.Now(),
@@ -55,6 +65,14 @@ type HealthStatus struct {
 	System      SystemInfo             `json:"system"`
 }
 
+// ReadinessCheck represents the outcome of one dependency check on the /ready endpoint
+type ReadinessCheck struct {
+	Status       string `json:"status"`
+	Critical     bool   `json:"critical"`
+	Message      string `json:"message,omitempty"`
+	ResponseTime string `json:"response_time"`
+}
+
 // ServiceInfo represents the status of a service dependency
 type ServiceInfo struct {
 	Status       string                 `json:"status"`
@@ -129,34 +147,49 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Readiness handles the /ready endpoint
+// Readiness handles the /ready endpoint. Each dependency is checked concurrently under its
+// own deadline so a single slow dependency can't stall the probe; the endpoint answers 503
+// when any critical check fails.
 func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
-	defer cancel()
+	ctx := r.Context()
 
-	ready := true
-	details := make(map[string]string)
+	probes := []readinessProbe{
+		{name: "database", critical: true, check: h.readyDatabase},
+		{name: "redis", critical: true, check: h.readyRedis},
+	}
+	if h.migrator != nil {
+		probes = append(probes, readinessProbe{name: "migrations", critical: true, check: h.readyMigrations})
+	}
+	if h.asynq != nil {
+		// Imports and exports queue up until the workers are reachable again, so the API stays in rotation
+		probes = append(probes, readinessProbe{name: "queues", critical: false, check: h.readyQueues})
+	}
 
-	// Check database readiness
-	if err := h.db.Ping(ctx); err != nil {
-		ready = false
-		details["database"] = "not ready"
-	} else {
-		details["database"] = "ready"
+	results := make([]ReadinessCheck, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.runReadinessProbe(ctx, probe)
+		}()
 	}
+	wg.Wait()
 
-	// Check Redis readiness
-	if err := h.redis.Ping(ctx).Err(); err != nil {
-		ready = false
-		details["redis"] = "not ready"
-	} else {
-		details["redis"] = "ready"
+	ready := true
+	details := make(map[string]ReadinessCheck, len(probes))
+	for i, probe := range probes {
+		details[probe.name] = results[i]
+		if probe.critical && results[i].Status != "ready" {
+			ready = false
+		}
 	}
 
-	// Prepare response
+	// Prepare response; pool stats help spot connection exhaustion under load
 	response := map[string]interface{}{
-		"ready":   ready,
-		"details": details,
+		"ready":         ready,
+		"details":       details,
+		"database_pool": h.db.Stats(),
 	}
 
 	// Set response status
@@ -176,6 +209,107 @@ func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// readinessProbe is a single dependency check run by Readiness. check returns an optional
+// note to report alongside a passing status.
+type readinessProbe struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) (string, error)
+}
+
+// runReadinessProbe runs a probe under its own deadline. Some clients, such as the migrator
+// and the asynq inspector, don't take a context, so the check runs on its own goroutine and
+// is abandoned if it overruns.
+func (h *HealthHandler) runReadinessProbe(ctx context.Context, probe readinessProbe) ReadinessCheck {
+	timeout := h.config.Server.ReadinessTimeout
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		message string
+		err     error
+	}
+
+	start := time.Now()
+	done := make(chan outcome, 1)
+	go func() {
+		message, err := probe.check(ctx)
+		done <- outcome{message: message, err: err}
+	}()
+
+	var result outcome
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result.err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	check := ReadinessCheck{
+		Status:       "ready",
+		Critical:     probe.critical,
+		Message:      result.message,
+		ResponseTime: time.Since(start).String(),
+	}
+	if result.err != nil {
+		check.Status = "not ready"
+		check.Message = result.err.Error()
+		h.logger.WarnContext(ctx, "readiness check failed",
+			slog.String("check", probe.name),
+			slog.String("error", result.err.Error()))
+	}
+	return check
+}
+
+// readyDatabase checks that the database accepts connections
+func (h *HealthHandler) readyDatabase(ctx context.Context) (string, error) {
+	return "", h.db.Ping(ctx)
+}
+
+// readyRedis checks that Redis accepts connections
+func (h *HealthHandler) readyRedis(ctx context.Context) (string, error) {
+	return "", h.redis.Ping(ctx).Err()
+}
+
+// readyMigrations checks that the schema has been migrated and no migration was left half applied
+func (h *HealthHandler) readyMigrations(ctx context.Context) (string, error) {
+	version, dirty, err := h.migrator.Version(ctx)
+	if err != nil {
+		return "", err
+	}
+	if dirty {
+		return "", fmt.Errorf("migration %d is dirty", version)
+	}
+	if version == 0 {
+		return "", errors.New("no migrations applied")
+	}
+	return fmt.Sprintf("version %d", version), nil
+}
+
+// readyQueues checks that the asynq Redis is reachable and reports configured queues it
+// doesn't know yet. asynq only registers a queue once a task is enqueued to it, so a missing
+// queue is noted rather than failed.
+func (h *HealthHandler) readyQueues(ctx context.Context) (string, error) {
+	queues, err := h.asynq.Queues()
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	for name := range h.config.Asynq.Queues {
+		if !slices.Contains(queues, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return "", nil
+	}
+	slices.Sort(missing)
+	return "not yet created: " + strings.Join(missing, ", "), nil
+}
+
 // checkDatabase checks the health of the database connection
 func (h *HealthHandler) checkDatabase(ctx context.Context) ServiceInfo {
 	start := time.Now()
@@ -0,0 +1,225 @@
+// internal/handlers/health_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	stats := ports.PoolStats{
+		AcquiredConns:        3,
+		IdleConns:            2,
+		TotalConns:           5,
+		MaxConns:             25,
+		AcquireCount:         120,
+		AcquireDuration:      250 * time.Millisecond,
+		EmptyAcquireCount:    4,
+		CanceledAcquireCount: 1,
+	}
+
+	tests := []struct {
+		name           string
+		pingErr        error
+		expectedStatus int
+		expectedReady  bool
+	}{
+		{
+			name:           "ready_with_pool_stats",
+			expectedStatus: http.StatusOK,
+			expectedReady:  true,
+		},
+		{
+			name:           "database_down_still_reports_pool",
+			pingErr:        errors.New("connection refused"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockDB.EXPECT().Ping(gomock.Any()).Return(tt.pingErr)
+			mockDB.EXPECT().Stats().Return(stats)
+
+			testRedis := helpers.SetupTestRedis(t)
+			handler := handlers.NewHealthHandler(mockDB, testRedis.Client, nil, nil, helpers.LoadTestConfig(), helpers.TestLogger())
+
+			req := httptest.NewRequest("GET", "/ready", nil)
+			w := httptest.NewRecorder()
+
+			handler.Readiness(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var body struct {
+				Ready        bool                               `json:"ready"`
+				Details      map[string]handlers.ReadinessCheck `json:"details"`
+				DatabasePool map[string]any                     `json:"database_pool"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedReady, body.Ready)
+			assert.Equal(t, "ready", body.Details["redis"].Status)
+
+			assert.EqualValues(t, 3, body.DatabasePool["acquired_conns"])
+			assert.EqualValues(t, 2, body.DatabasePool["idle_conns"])
+			assert.EqualValues(t, 5, body.DatabasePool["total_conns"])
+			assert.EqualValues(t, 25, body.DatabasePool["max_conns"])
+			assert.EqualValues(t, 120, body.DatabasePool["acquire_count"])
+			assert.EqualValues(t, 250*time.Millisecond, body.DatabasePool["acquire_duration_ns"])
+			assert.EqualValues(t, 4, body.DatabasePool["empty_acquire_count"])
+			assert.EqualValues(t, 1, body.DatabasePool["canceled_acquire_count"])
+		})
+	}
+}
+
+func TestHealthHandler_Readiness_DeepChecks(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, block <-chan struct{})
+		expectedStatus int
+		expectedChecks map[string]string
+		expectedNotes  map[string]string
+	}{
+		{
+			name: "all_ready",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, _ <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).Return(uint(12), false, nil)
+				inspector.EXPECT().Queues().Return([]string{"critical", "default", "low"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"database": "ready", "redis": "ready", "migrations": "ready", "queues": "ready"},
+			expectedNotes:  map[string]string{"migrations": "version 12"},
+		},
+		{
+			name: "dirty_migration",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, _ <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).Return(uint(7), true, nil)
+				inspector.EXPECT().Queues().Return([]string{"critical", "default", "low"}, nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"migrations": "not ready", "queues": "ready"},
+			expectedNotes:  map[string]string{"migrations": "migration 7 is dirty"},
+		},
+		{
+			name: "no_migrations_applied",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, _ <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).Return(uint(0), false, nil)
+				inspector.EXPECT().Queues().Return([]string{"critical", "default", "low"}, nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"migrations": "not ready"},
+			expectedNotes:  map[string]string{"migrations": "no migrations applied"},
+		},
+		{
+			name: "migrator_error",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, _ <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).Return(uint(0), false, errors.New("failed to get version"))
+				inspector.EXPECT().Queues().Return([]string{"critical", "default", "low"}, nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"migrations": "not ready"},
+			expectedNotes:  map[string]string{"migrations": "failed to get version"},
+		},
+		{
+			name: "queue_inspector_down_is_not_critical",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, _ <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).Return(uint(12), false, nil)
+				inspector.EXPECT().Queues().Return(nil, errors.New("dial tcp: connection refused"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"migrations": "ready", "queues": "not ready"},
+			expectedNotes:  map[string]string{"queues": "dial tcp: connection refused"},
+		},
+		{
+			name: "missing_queue_is_reported",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, _ <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).Return(uint(12), false, nil)
+				inspector.EXPECT().Queues().Return([]string{"default"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedChecks: map[string]string{"queues": "ready"},
+			expectedNotes:  map[string]string{"queues": "not yet created: critical, low"},
+		},
+		{
+			name: "slow_migrator_times_out",
+			setup: func(migrator *mocks.MockSchemaVersioner, inspector *mocks.MockQueueInspector, block <-chan struct{}) {
+				migrator.EXPECT().Version(gomock.Any()).DoAndReturn(func(context.Context) (uint, bool, error) {
+					<-block
+					return 12, false, nil
+				})
+				inspector.EXPECT().Queues().Return([]string{"critical", "default", "low"}, nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: map[string]string{"database": "ready", "redis": "ready", "migrations": "not ready", "queues": "ready"},
+			expectedNotes:  map[string]string{"migrations": "timed out after 50ms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			block := make(chan struct{})
+			t.Cleanup(func() { close(block) })
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockDB.EXPECT().Ping(gomock.Any()).Return(nil)
+			mockDB.EXPECT().Stats().Return(ports.PoolStats{})
+
+			migrator := mocks.NewMockSchemaVersioner(ctrl)
+			inspector := mocks.NewMockQueueInspector(ctrl)
+			tt.setup(migrator, inspector, block)
+
+			cfg := helpers.LoadTestConfig()
+			cfg.Server.ReadinessTimeout = 50 * time.Millisecond
+			cfg.Asynq.Queues = map[string]int{"critical": 6, "default": 3, "low": 1}
+
+			testRedis := helpers.SetupTestRedis(t)
+			handler := handlers.NewHealthHandler(mockDB, testRedis.Client, inspector, migrator, cfg, helpers.TestLogger())
+
+			req := httptest.NewRequest("GET", "/ready", nil)
+			w := httptest.NewRecorder()
+
+			start := time.Now()
+			handler.Readiness(w, req)
+			assert.Less(t, time.Since(start), time.Second, "a slow check must not stall the probe")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var body struct {
+				Ready   bool                               `json:"ready"`
+				Details map[string]handlers.ReadinessCheck `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, body.Ready)
+
+			for name, status := range tt.expectedChecks {
+				assert.Equal(t, status, body.Details[name].Status, name)
+			}
+			for name, note := range tt.expectedNotes {
+				assert.Contains(t, body.Details[name].Message, note, name)
+			}
+			assert.True(t, body.Details["migrations"].Critical)
+			assert.False(t, body.Details["queues"].Critical)
+		})
+	}
+}
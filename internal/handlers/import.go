@@ -3,36 +3,60 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
+	"net/mail"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
-
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
 	"github.com/ammerola/resell-be/internal/workers"
 )
 
+// importQueues are the queues import tasks are enqueued on
+var importQueues = []string{"default", "low"}
+
 // ImportHandler handles import operations
 type ImportHandler struct {
 	asynqClient *asynq.Client
+	inspector   ports.QueueInspector
+	db          ports.Database
+	storage     storage.StorageClient
 	logger      *slog.Logger
 	maxFileSize int64
-	uploadDir   string
+	profiles    *pdfparse.Profiles
 }
 
-// NewImportHandler creates a new import handler
-func NewImportHandler(asynqClient *asynq.Client, logger *slog.Logger, maxFileSize int64, uploadDir string) *ImportHandler {
+// NewImportHandler creates a new import handler. Uploads are written to storage so
+// the worker can read them regardless of which host it runs on; the inspector is used to
+// drop the queued task of a cancelled job. profiles are the invoice profiles a PDF upload may
+// select; nil allows only the default.
+func NewImportHandler(asynqClient *asynq.Client, inspector ports.QueueInspector, db ports.Database, storage storage.StorageClient, logger *slog.Logger, maxFileSize int64, profiles *pdfparse.Profiles) *ImportHandler {
 	return &ImportHandler{
 		asynqClient: asynqClient,
+		inspector:   inspector,
+		db:          db,
+		storage:     storage,
 		logger:      logger.With(slog.String("handler", "import")),
 		maxFileSize: maxFileSize,
-		uploadDir:   uploadDir,
+		profiles:    profiles,
 	}
 }
 
@@ -42,21 +66,21 @@ func (h *ImportHandler) ImportPDF(w http.ResponseWriter, r *http.Request) {
 
 	// Parse multipart form (50MB max)
 	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
-		h.respondError(w, http.StatusBadRequest, "Failed to parse form data")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Failed to parse form data")
 		return
 	}
 
 	// Get file from form
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "File is required")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "File is required")
 		return
 	}
 	defer file.Close()
 
 	// Validate file type
 	if header.Header.Get("Content-Type") != "application/pdf" {
-		h.respondError(w, http.StatusBadRequest, "Only PDF files are allowed")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Only PDF files are allowed")
 		return
 	}
 
@@ -68,79 +92,127 @@ func (h *ImportHandler) ImportPDF(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if invoiceID == "" {
-		h.respondError(w, http.StatusBadRequest, "invoice_id is required")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "invoice_id is required")
 		return
 	}
 
-	// Create upload directory if it doesn't exist
-	if err := os.MkdirAll(h.uploadDir, 0755); err != nil {
-		h.logger.ErrorContext(ctx, "failed to create upload directory", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to prepare upload")
+	layout := r.FormValue("layout")
+	if layout != "" && layout != workers.LayoutSingleColumn && layout != workers.LayoutTwoColumn {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "layout must be single-column or two-column")
 		return
 	}
 
-	// Save uploaded file
-	tempFile := filepath.Join(h.uploadDir, fmt.Sprintf("%s_%s", uuid.New().String(), header.Filename))
-	dst, err := os.Create(tempFile)
-	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to create temp file", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to save upload")
+	// The worker picks the auction's profile, or the default, when none is given
+	profile := r.FormValue("profile")
+	if _, ok := h.profiles.Lookup(profile); !ok {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, fmt.Sprintf("profile must be one of: %s", strings.Join(h.profiles.Names(), ", ")))
+		return
+	}
+
+	onDuplicate := r.FormValue("on_duplicate")
+	if !workers.ValidOnDuplicate(onDuplicate) {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "on_duplicate must be skip, replace or append")
+		return
+	}
+
+	// Shipping billed once for the invoice is split across its items by the worker
+	shippingTotal := decimal.Zero
+	if v := r.FormValue("shipping_total"); v != "" {
+		total, err := decimal.NewFromString(v)
+		if err != nil || total.IsNegative() {
+			writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "shipping_total must be a non-negative amount")
+			return
+		}
+		shippingTotal = total
+	}
+	shippingAllocation := r.FormValue("shipping_allocation")
+	if shippingAllocation != "" && !domain.ShippingAllocation(shippingAllocation).IsValid() {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "shipping_allocation must be proportional or even")
+		return
+	}
+
+	// Password for encrypted invoices; only passed to the worker, never stored on the job record
+	password := r.FormValue("password")
+
+	notifyEmail, ok := h.parseNotifyEmail(w, r)
+	if !ok {
 		return
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		os.Remove(tempFile)
-		h.logger.ErrorContext(ctx, "failed to save file", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to save upload")
+	// Save uploaded file, keyed by content so a re-uploaded invoice isn't processed twice
+	fileKey, existed, err := h.storeUploadByContent(ctx, file, header.Filename, "application/pdf")
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to save file", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to save upload")
+		return
+	}
+	if existed {
+		writeError(w, r, http.StatusConflict, ErrCodeConflict, "An identical PDF is already queued for import")
 		return
 	}
 
 	// Create job record
 	jobID := uuid.New().String()
 	if err := h.createAsyncJob(ctx, jobID, "pdf_import", map[string]interface{}{
-		"file_path":  tempFile,
-		"invoice_id": invoiceID,
-		"auction_id": auctionID,
+		"file_key":            fileKey,
+		"invoice_id":          invoiceID,
+		"auction_id":          auctionID,
+		"layout":              layout,
+		"profile":             profile,
+		"notify_email":        notifyEmail,
+		"on_duplicate":        onDuplicate,
+		"shipping_total":      shippingTotal,
+		"shipping_allocation": shippingAllocation,
 	}); err != nil {
-		os.Remove(tempFile)
-		h.logger.ErrorContext(ctx, "failed to create job record", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to create import job")
+		h.discardUpload(ctx, fileKey)
+		h.logger.ErrorContext(ctx, "failed to create job record", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to create import job")
 		return
 	}
 
 	// Queue PDF processing task
 	payload := workers.PDFJobPayload{
-		JobID:     jobID,
-		FilePath:  tempFile,
-		InvoiceID: invoiceID,
-		AuctionID: auctionID,
+		JobID:       jobID,
+		FileKey:     fileKey,
+		InvoiceID:   invoiceID,
+		AuctionID:   auctionID,
+		Layout:      layout,
+		Profile:     profile,
+		OnDuplicate: onDuplicate,
+		Password:    password,
+		FileName:    header.Filename,
+		NotifyEmail: notifyEmail,
+		UserID:      logger.UserIDFromContext(ctx),
+
+		ShippingTotal:      shippingTotal,
+		ShippingAllocation: shippingAllocation,
 	}
 
 	b, err := json.Marshal(payload)
 	if err != nil {
-		os.Remove(tempFile)
-		h.logger.ErrorContext(ctx, "failed to marshal PDFJobPayload", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to queue import job")
+		h.discardUpload(ctx, fileKey)
+		h.logger.ErrorContext(ctx, "failed to marshal PDFJobPayload", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to queue import job")
 		return
 	}
 
 	task := asynq.NewTask(workers.TypePDFProcess, b)
 	if err != nil {
-		os.Remove(tempFile)
-		h.logger.ErrorContext(ctx, "failed to create task", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to queue import job")
+		h.discardUpload(ctx, fileKey)
+		h.logger.ErrorContext(ctx, "failed to create task", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to queue import job")
 		return
 	}
 
 	info, err := h.asynqClient.Enqueue(task,
 		asynq.Queue("default"),
+		asynq.TaskID(jobID),
 		asynq.MaxRetry(3),
 		asynq.Retention(24*time.Hour))
 	if err != nil {
-		os.Remove(tempFile)
-		h.logger.ErrorContext(ctx, "failed to enqueue task", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to queue import job")
+		h.discardUpload(ctx, fileKey)
+		h.logger.ErrorContext(ctx, "failed to enqueue task", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to queue import job")
 		return
 	}
 
@@ -163,14 +235,14 @@ func (h *ImportHandler) ImportExcel(w http.ResponseWriter, r *http.Request) {
 	// Similar implementation to ImportPDF but for Excel files
 	// Parse multipart form
 	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
-		h.respondError(w, http.StatusBadRequest, "Failed to parse form data")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Failed to parse form data")
 		return
 	}
 
 	// Get file from form
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "File is required")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "File is required")
 		return
 	}
 	defer file.Close()
@@ -179,64 +251,92 @@ func (h *ImportHandler) ImportExcel(w http.ResponseWriter, r *http.Request) {
 	contentType := header.Header.Get("Content-Type")
 	if contentType != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" &&
 		contentType != "application/vnd.ms-excel" {
-		h.respondError(w, http.StatusBadRequest, "Only Excel files are allowed")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Only Excel files are allowed")
+		return
+	}
+
+	validateOnly := false
+	if v := r.FormValue("validate_only"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "validate_only must be true or false")
+			return
+		}
+		validateOnly = parsed
+	}
+
+	notifyEmail, ok := h.parseNotifyEmail(w, r)
+	if !ok {
 		return
 	}
 
 	// Save file and queue for processing
-	tempFile := filepath.Join(h.uploadDir, fmt.Sprintf("%s_%s", uuid.New().String(), header.Filename))
-	dst, err := os.Create(tempFile)
+	fileKey, err := h.storeUpload(ctx, file, header.Filename, contentType)
 	if err != nil {
-		h.logger.ErrorContext(ctx, "failed to create temp file", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to save upload")
+		h.logger.ErrorContext(ctx, "failed to save file", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to save upload")
 		return
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		os.Remove(tempFile)
-		h.respondError(w, http.StatusInternalServerError, "Failed to save upload")
+	// Create job record
+	jobID := uuid.New().String()
+	if err := h.createAsyncJob(ctx, jobID, "excel_import", map[string]interface{}{
+		"file_key":      fileKey,
+		"validate_only": validateOnly,
+		"notify_email":  notifyEmail,
+	}); err != nil {
+		h.discardUpload(ctx, fileKey)
+		h.logger.ErrorContext(ctx, "failed to create job record", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to create import job")
 		return
 	}
 
-	// Create and queue Excel import task
-	jobID := uuid.New().String()
-	payload := map[string]interface{}{
-		"job_id":    jobID,
-		"file_path": tempFile,
-		"type":      "inventory",
+	// Queue Excel import task
+	payload := workers.ExcelJobPayload{
+		JobID:        jobID,
+		FileKey:      fileKey,
+		ValidateOnly: validateOnly,
+		FileName:     header.Filename,
+		NotifyEmail:  notifyEmail,
 	}
 
 	b, err := json.Marshal(payload)
 	if err != nil {
-		os.Remove(tempFile)
-		h.logger.ErrorContext(ctx, "failed to marshal PDFJobPayload", err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to queue import job")
+		h.discardUpload(ctx, fileKey)
+		h.logger.ErrorContext(ctx, "failed to marshal ExcelJobPayload", slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to queue import job")
 		return
 	}
 
 	task := asynq.NewTask(workers.TypeExcelImport, b)
 	if err != nil {
-		os.Remove(tempFile)
-		h.respondError(w, http.StatusInternalServerError, "Failed to create import task")
+		h.discardUpload(ctx, fileKey)
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to create import task")
 		return
 	}
 
-	info, err := h.asynqClient.Enqueue(task, asynq.Queue("default"))
+	info, err := h.asynqClient.Enqueue(task, asynq.Queue("default"), asynq.TaskID(jobID))
 	if err != nil {
-		os.Remove(tempFile)
-		h.respondError(w, http.StatusInternalServerError, "Failed to queue import job")
+		h.discardUpload(ctx, fileKey)
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to queue import job")
 		return
 	}
 
 	h.logger.InfoContext(ctx, "Excel import queued",
 		slog.String("job_id", jobID),
-		slog.String("task_id", info.ID))
+		slog.String("task_id", info.ID),
+		slog.Bool("validate_only", validateOnly))
+
+	message := "Excel import has been queued for processing"
+	if validateOnly {
+		message = "Excel validation has been queued; no items will be saved"
+	}
 
 	h.respondJSON(w, http.StatusAccepted, map[string]interface{}{
-		"job_id":  jobID,
-		"status":  "queued",
-		"message": "Excel import has been queued for processing",
+		"job_id":        jobID,
+		"status":        "queued",
+		"validate_only": validateOnly,
+		"message":       message,
 	})
 }
 
@@ -246,20 +346,25 @@ func (h *ImportHandler) ImportBatch(w http.ResponseWriter, r *http.Request) {
 
 	// Parse multipart form
 	if err := r.ParseMultipartForm(h.maxFileSize * 10); err != nil { // Allow larger size for batch
-		h.respondError(w, http.StatusBadRequest, "Failed to parse form data")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Failed to parse form data")
 		return
 	}
 
 	fileType := r.FormValue("type")
 	if fileType != "pdf" && fileType != "excel" && fileType != "csv" {
-		h.respondError(w, http.StatusBadRequest, "Invalid file type. Must be pdf, excel, or csv")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid file type. Must be pdf, excel, or csv")
 		return
 	}
 
 	// Get all files
 	files := r.MultipartForm.File["files"]
 	if len(files) == 0 {
-		h.respondError(w, http.StatusBadRequest, "No files provided")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "No files provided")
+		return
+	}
+
+	notifyEmail, ok := h.parseNotifyEmail(w, r)
+	if !ok {
 		return
 	}
 
@@ -271,27 +376,19 @@ func (h *ImportHandler) ImportBatch(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			h.logger.WarnContext(ctx, "failed to open file in batch",
 				slog.String("filename", fileHeader.Filename),
-				err)
+				slog.String("error", err.Error()))
 			continue
 		}
 		defer file.Close()
 
 		// Save file
-		tempFile := filepath.Join(h.uploadDir, fmt.Sprintf("%s_%s", uuid.New().String(), fileHeader.Filename))
-		dst, err := os.Create(tempFile)
+		fileKey, err := h.storeUpload(ctx, file, fileHeader.Filename, fileHeader.Header.Get("Content-Type"))
 		if err != nil {
-			h.logger.WarnContext(ctx, "failed to create temp file",
+			h.logger.WarnContext(ctx, "failed to save file",
 				slog.String("filename", fileHeader.Filename),
-				err)
-			continue
-		}
-
-		if _, err := io.Copy(dst, file); err != nil {
-			dst.Close()
-			os.Remove(tempFile)
+				slog.String("error", err.Error()))
 			continue
 		}
-		dst.Close()
 
 		// Queue processing task based on type
 		jobID := uuid.New().String()
@@ -305,28 +402,38 @@ func (h *ImportHandler) ImportBatch(w http.ResponseWriter, r *http.Request) {
 		}
 
 		payload := map[string]interface{}{
-			"job_id":    jobID,
-			"batch_id":  batchID,
-			"file_path": tempFile,
-			"file_type": fileType,
+			"job_id":       jobID,
+			"batch_id":     batchID,
+			"file_key":     fileKey,
+			"file_type":    fileType,
+			"file_name":    fileHeader.Filename,
+			"notify_email": notifyEmail,
+		}
+
+		if err := h.createAsyncJob(ctx, jobID, fileType+"_import", payload); err != nil {
+			h.discardUpload(ctx, fileKey)
+			h.logger.WarnContext(ctx, "failed to create job record",
+				slog.String("filename", fileHeader.Filename),
+				slog.String("error", err.Error()))
+			continue
 		}
 
 		b, err := json.Marshal(payload)
 		if err != nil {
-			os.Remove(tempFile)
-			h.logger.ErrorContext(ctx, "failed to marshal PDFJobPayload", err)
-			h.respondError(w, http.StatusInternalServerError, "Failed to queue import job")
+			h.discardUpload(ctx, fileKey)
+			h.logger.ErrorContext(ctx, "failed to marshal PDFJobPayload", slog.String("error", err.Error()))
+			writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to queue import job")
 			return
 		}
 
 		task := asynq.NewTask(taskType, b)
 		if err != nil {
-			os.Remove(tempFile)
+			h.discardUpload(ctx, fileKey)
 			continue
 		}
 
-		if _, err := h.asynqClient.Enqueue(task, asynq.Queue("low")); err != nil {
-			os.Remove(tempFile)
+		if _, err := h.asynqClient.Enqueue(task, asynq.Queue("low"), asynq.TaskID(jobID)); err != nil {
+			h.discardUpload(ctx, fileKey)
 			continue
 		}
 
@@ -353,42 +460,234 @@ func (h *ImportHandler) ImportStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	jobID := r.PathValue("jobId")
 
+	if _, err := uuid.Parse(jobID); err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid job ID")
+		return
+	}
+
 	// Query job status from database
 	status, err := h.getJobStatus(ctx, jobID)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to get job status",
 			slog.String("job_id", jobID),
-			err)
-		h.respondError(w, http.StatusInternalServerError, "Failed to get job status")
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to get job status")
 		return
 	}
 
 	if status == nil {
-		h.respondError(w, http.StatusNotFound, "Job not found")
+		writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Job not found")
 		return
 	}
 
 	h.respondJSON(w, http.StatusOK, status)
 }
 
+// CancelImport handles DELETE /api/v1/import/{jobId}. A queued job's task is removed from
+// the queue; a job that is already running stops at the worker's next chunk boundary and
+// keeps whatever it saved.
+func (h *ImportHandler) CancelImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := r.PathValue("jobId")
+
+	if _, err := uuid.Parse(jobID); err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid job ID")
+		return
+	}
+
+	fileKey, cancelled, err := h.markJobCancelled(ctx, jobID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to cancel job",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to cancel job")
+		return
+	}
+
+	if !cancelled {
+		status, err := h.getJobStatus(ctx, jobID)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get job status",
+				slog.String("job_id", jobID),
+				slog.String("error", err.Error()))
+			writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to cancel job")
+			return
+		}
+		if status == nil {
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Job not found")
+			return
+		}
+		writeError(w, r, http.StatusConflict, ErrCodeConflict,
+			fmt.Sprintf("Job has already finished with status %s", status.Status))
+		return
+	}
+
+	// A task that is still waiting can be dropped along with its upload
+	if h.deleteQueuedTask(ctx, jobID) && fileKey != "" {
+		h.discardUpload(ctx, fileKey)
+	}
+
+	h.logger.InfoContext(ctx, "import cancelled", slog.String("job_id", jobID))
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"job_id":  jobID,
+		"status":  workers.JobStatusCancelled,
+		"message": "Import has been cancelled",
+	})
+}
+
+// JobStatus is the persisted state of an async import job
+type JobStatus struct {
+	JobID          string          `json:"job_id"`
+	Type           string          `json:"type"`
+	Status         string          `json:"status"`
+	Progress       int             `json:"progress"`
+	ItemsProcessed int             `json:"items_processed"`
+	Error          *string         `json:"error,omitempty"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	Attempts       int             `json:"attempts"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	UpdatedAt      *time.Time      `json:"updated_at,omitempty"`
+}
+
 // Helper methods
+
+// storeUpload copies an uploaded file to storage under a unique key and returns the key
+func (h *ImportHandler) storeUpload(ctx context.Context, file io.Reader, filename, contentType string) (string, error) {
+	key := fmt.Sprintf("imports/%s_%s", uuid.New().String(), filepath.Base(filename))
+	if _, err := h.storage.Upload(ctx, key, file, contentType); err != nil {
+		return "", fmt.Errorf("failed to store upload: %w", err)
+	}
+	return key, nil
+}
+
+// storeUploadByContent writes an upload to storage under the SHA-256 of its content.
+// Workers delete uploads once they are done with them, so existed means an identical
+// file is still queued or being processed.
+func (h *ImportHandler) storeUploadByContent(ctx context.Context, file io.ReadSeeker, filename, contentType string) (string, bool, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", false, fmt.Errorf("failed to hash upload: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", false, fmt.Errorf("failed to rewind upload: %w", err)
+	}
+
+	key := fmt.Sprintf("imports/%s%s", hex.EncodeToString(hash.Sum(nil)), strings.ToLower(filepath.Ext(filename)))
+	_, existed, err := h.storage.UploadIfAbsent(ctx, key, file, contentType)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to store upload: %w", err)
+	}
+	return key, existed, nil
+}
+
+// discardUpload removes an upload whose job could not be queued
+func (h *ImportHandler) discardUpload(ctx context.Context, key string) {
+	if err := h.storage.Delete(ctx, key); err != nil {
+		h.logger.WarnContext(ctx, "failed to delete orphaned upload",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+	}
+}
+
+// parseNotifyEmail reads the optional notify_email form value, writing a 400 when it is malformed
+func (h *ImportHandler) parseNotifyEmail(w http.ResponseWriter, r *http.Request) (string, bool) {
+	notifyEmail := strings.TrimSpace(r.FormValue("notify_email"))
+	if notifyEmail == "" {
+		return "", true
+	}
+
+	addr, err := mail.ParseAddress(notifyEmail)
+	if err != nil || addr.Address != notifyEmail {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "notify_email must be a valid email address")
+		return "", false
+	}
+	return notifyEmail, true
+}
 func (h *ImportHandler) createAsyncJob(ctx context.Context, jobID string, jobType string, payload interface{}) error {
-	// This would insert a job record into the async_jobs table
-	// Implementation depends on your database setup
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO async_jobs (id, job_type, payload, status, created_at)
+		VALUES ($1, $2, $3, 'queued', CURRENT_TIMESTAMP)`
+
+	if _, err := h.db.Exec(ctx, query, jobID, jobType, payloadJSON); err != nil {
+		return fmt.Errorf("failed to insert job record: %w", err)
+	}
 	return nil
 }
 
-func (h *ImportHandler) getJobStatus(ctx context.Context, jobID string) (map[string]interface{}, error) {
-	// This would query the async_jobs table for status
-	// Placeholder implementation
-	return map[string]interface{}{
-		"job_id":     jobID,
-		"status":     "processing",
-		"progress":   50,
-		"created_at": time.Now().Add(-5 * time.Minute),
-		"started_at": time.Now().Add(-4 * time.Minute),
-		"message":    "Processing items...",
-	}, nil
+// markJobCancelled cancels a job that hasn't finished yet, returning the storage key of its
+// upload. cancelled is false when the job doesn't exist or has already finished.
+func (h *ImportHandler) markJobCancelled(ctx context.Context, jobID string) (string, bool, error) {
+	query := `
+		UPDATE async_jobs
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('queued', 'processing')
+		RETURNING COALESCE(payload->>'file_key', '')`
+
+	var fileKey string
+	if err := h.db.QueryRow(ctx, query, jobID).Scan(&fileKey); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to update job status: %w", err)
+	}
+	return fileKey, true, nil
+}
+
+// deleteQueuedTask removes a job's task from the queue it was enqueued on, reporting whether
+// one was removed. Tasks are enqueued with the job ID as their task ID. A task that is already
+// running can't be deleted; the worker notices the cancellation instead.
+func (h *ImportHandler) deleteQueuedTask(ctx context.Context, jobID string) bool {
+	if h.inspector == nil {
+		return false
+	}
+
+	for _, queue := range importQueues {
+		err := h.inspector.DeleteTask(queue, jobID)
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+			continue
+		}
+		h.logger.DebugContext(ctx, "queued task not deleted",
+			slog.String("job_id", jobID),
+			slog.String("queue", queue),
+			slog.String("error", err.Error()))
+		return false
+	}
+	return false
+}
+
+// getJobStatus loads a job record, returning nil when no job with that ID exists
+func (h *ImportHandler) getJobStatus(ctx context.Context, jobID string) (*JobStatus, error) {
+	query := `
+		SELECT id::text, job_type, status, progress, items_processed, error, result, COALESCE(attempts, 0),
+		       created_at, started_at, completed_at, updated_at
+		FROM async_jobs
+		WHERE id = $1`
+
+	var status JobStatus
+	err := h.db.QueryRow(ctx, query, jobID).Scan(
+		&status.JobID, &status.Type, &status.Status, &status.Progress, &status.ItemsProcessed,
+		&status.Error, &status.Result, &status.Attempts,
+		&status.CreatedAt, &status.StartedAt, &status.CompletedAt, &status.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query job status: %w", err)
+	}
+
+	return &status, nil
 }
 
 func (h *ImportHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -396,7 +695,3 @@ func (h *ImportHandler) respondJSON(w http.ResponseWriter, status int, data inte
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
-
-func (h *ImportHandler) respondError(w http.ResponseWriter, status int, message string) {
-	h.respondJSON(w, status, map[string]string{"error": message})
-}
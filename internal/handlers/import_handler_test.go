@@ -0,0 +1,612 @@
+// internal/handlers/import_handler_test.go
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+// mockRow implements pgx.Row on top of mockRows, returning err instead when set
+type mockRow struct {
+	rows *mockRows
+	err  error
+}
+
+func (m *mockRow) Scan(dest ...interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.rows.Next()
+	return m.rows.Scan(dest...)
+}
+
+func TestImportHandler_ImportStatus(t *testing.T) {
+	jobID := uuid.New().String()
+	createdAt := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	completedAt := createdAt.Add(2 * time.Minute)
+
+	tests := []struct {
+		name           string
+		jobID          string
+		setupMocks     func(*mocks.MockDatabase)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "returns_persisted_job",
+			jobID: jobID,
+			setupMocks: func(m *mocks.MockDatabase) {
+				m.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), jobID).
+					Return(&mockRow{rows: &mockRows{values: [][]any{{
+						jobID, "pdf_import", "completed", 100, 3, nil, json.RawMessage(`{"items_created":3}`), 1,
+						createdAt, nil, completedAt, completedAt,
+					}}}})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response handlers.JobStatus
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, jobID, response.JobID)
+				assert.Equal(t, "pdf_import", response.Type)
+				assert.Equal(t, "completed", response.Status)
+				assert.Equal(t, 100, response.Progress)
+				assert.Equal(t, 3, response.ItemsProcessed)
+				assert.Nil(t, response.Error)
+				assert.JSONEq(t, `{"items_created":3}`, string(response.Result))
+				assert.Nil(t, response.StartedAt)
+				require.NotNil(t, response.CompletedAt)
+				assert.True(t, completedAt.Equal(*response.CompletedAt))
+			},
+		},
+		{
+			name:  "job_not_found",
+			jobID: jobID,
+			setupMocks: func(m *mocks.MockDatabase) {
+				m.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), jobID).
+					Return(&mockRow{err: pgx.ErrNoRows})
+			},
+			expectedStatus: http.StatusNotFound,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, "Job not found", response.Message)
+			},
+		},
+		{
+			name:  "database_error",
+			jobID: jobID,
+			setupMocks: func(m *mocks.MockDatabase) {
+				m.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), jobID).
+					Return(&mockRow{err: errors.New("connection refused")})
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "invalid_job_id",
+			jobID:          "not-a-uuid",
+			setupMocks:     func(m *mocks.MockDatabase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			tt.setupMocks(mockDB)
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			handler := handlers.NewImportHandler(nil, nil, mockDB, files, helpers.TestLogger(), 1<<20, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/import/status/"+tt.jobID, nil)
+			req.SetPathValue("jobId", tt.jobID)
+			rec := httptest.NewRecorder()
+
+			handler.ImportStatus(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestImportHandler_ImportExcel_NotifyEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		notifyEmail    string
+		expectQueued   bool
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "threads_notify_email_into_job_payload",
+			notifyEmail:    "owner@example.com",
+			expectQueued:   true,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "notify_email_is_optional",
+			expectQueued:   true,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "rejects_malformed_notify_email",
+			notifyEmail:    "not-an-email",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "notify_email must be a valid email address",
+		},
+		{
+			name:           "rejects_display_name_form",
+			notifyEmail:    "Owner <owner@example.com>",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "notify_email must be a valid email address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := helpers.SetupTestRedis(t)
+			redisOpt := asynq.RedisClientOpt{Addr: redis.Server.Addr()}
+			client := asynq.NewClient(redisOpt)
+			defer client.Close()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			if tt.expectQueued {
+				mockDB.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), "excel_import", gomock.Any()).
+					Return(pgconn.CommandTag{}, nil)
+			}
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			handler := handlers.NewImportHandler(client, nil, mockDB, files, helpers.TestLogger(), 1<<20, nil)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			partHeader := textproto.MIMEHeader{}
+			partHeader.Set("Content-Disposition", `form-data; name="file"; filename="lots.xlsx"`)
+			partHeader.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			part, err := writer.CreatePart(partHeader)
+			require.NoError(t, err)
+			_, err = part.Write([]byte("xlsx"))
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteField("notify_email", tt.notifyEmail))
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/import/excel", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			handler.ImportExcel(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedError != "" {
+				response := decodeError(t, rec.Body.Bytes())
+				assert.Equal(t, tt.expectedError, response.Message)
+			}
+
+			inspector := asynq.NewInspector(redisOpt)
+			defer inspector.Close()
+			tasks, err := inspector.ListPendingTasks("default")
+			if !tt.expectQueued {
+				assert.Empty(t, tasks)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			var payload workers.ExcelJobPayload
+			require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+			assert.Equal(t, tt.notifyEmail, payload.NotifyEmail)
+			assert.Equal(t, "lots.xlsx", payload.FileName)
+
+			// The upload is in storage under the key the worker will download
+			assert.Regexp(t, `^imports/[0-9a-f-]{36}_lots\.xlsx$`, payload.FileKey)
+			stored, err := files.Download(context.Background(), payload.FileKey)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("xlsx"), stored)
+		})
+	}
+}
+
+func TestImportHandler_ImportPDF_DuplicateUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redis := helpers.SetupTestRedis(t)
+	redisOpt := asynq.RedisClientOpt{Addr: redis.Server.Addr()}
+	client := asynq.NewClient(redisOpt)
+	defer client.Close()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), gomock.Any(), "pdf_import", gomock.Any()).
+		Return(pgconn.CommandTag{}, nil)
+
+	files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+	handler := handlers.NewImportHandler(client, nil, mockDB, files, helpers.TestLogger(), 1<<20, nil)
+
+	upload := func(filename string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+		partHeader.Set("Content-Type", "application/pdf")
+		part, err := writer.CreatePart(partHeader)
+		require.NoError(t, err)
+		_, err = part.Write([]byte("%PDF-1.4 invoice"))
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("invoice_id", "INV-100"))
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/import/pdf", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		handler.ImportPDF(rec, req)
+		return rec
+	}
+
+	first := upload("invoice.pdf")
+	require.Equal(t, http.StatusAccepted, first.Code)
+
+	// Same bytes under a different name are still the same invoice
+	second := upload("invoice-copy.pdf")
+	assert.Equal(t, http.StatusConflict, second.Code)
+	response := decodeError(t, second.Body.Bytes())
+	assert.Equal(t, "An identical PDF is already queued for import", response.Message)
+
+	inspector := asynq.NewInspector(redisOpt)
+	defer inspector.Close()
+	tasks, err := inspector.ListPendingTasks("default")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	var payload workers.PDFJobPayload
+	require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+	assert.Regexp(t, `^imports/[0-9a-f]{64}\.pdf$`, payload.FileKey)
+	exists, err := files.Exists(context.Background(), payload.FileKey)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestImportHandler_ImportPDF_OnDuplicate(t *testing.T) {
+	tests := []struct {
+		name           string
+		onDuplicate    string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "threads_on_duplicate_into_job_payload",
+			onDuplicate:    workers.OnDuplicateReplace,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "rejects_unknown_mode",
+			onDuplicate:    "merge",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "on_duplicate must be skip, replace or append",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := helpers.SetupTestRedis(t)
+			redisOpt := asynq.RedisClientOpt{Addr: redis.Server.Addr()}
+			client := asynq.NewClient(redisOpt)
+			defer client.Close()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			if tt.expectedStatus == http.StatusAccepted {
+				mockDB.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), "pdf_import", gomock.Any()).
+					Return(pgconn.CommandTag{}, nil)
+			}
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			handler := handlers.NewImportHandler(client, nil, mockDB, files, helpers.TestLogger(), 1<<20, nil)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			partHeader := textproto.MIMEHeader{}
+			partHeader.Set("Content-Disposition", `form-data; name="file"; filename="invoice.pdf"`)
+			partHeader.Set("Content-Type", "application/pdf")
+			part, err := writer.CreatePart(partHeader)
+			require.NoError(t, err)
+			_, err = part.Write([]byte("%PDF-1.4 invoice"))
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteField("invoice_id", "INV-100"))
+			require.NoError(t, writer.WriteField("on_duplicate", tt.onDuplicate))
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/import/pdf", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			handler.ImportPDF(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedError != "" {
+				response := decodeError(t, rec.Body.Bytes())
+				assert.Equal(t, tt.expectedError, response.Message)
+				return
+			}
+
+			inspector := asynq.NewInspector(redisOpt)
+			defer inspector.Close()
+			tasks, err := inspector.ListPendingTasks("default")
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			var payload workers.PDFJobPayload
+			require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+			assert.Equal(t, tt.onDuplicate, payload.OnDuplicate)
+		})
+	}
+}
+
+func TestImportHandler_ImportPDF_Shipping(t *testing.T) {
+	tests := []struct {
+		name               string
+		shippingTotal      string
+		shippingAllocation string
+		expectedStatus     int
+		expectedError      string
+	}{
+		{
+			name:               "threads_shipping_into_job_payload",
+			shippingTotal:      "24.50",
+			shippingAllocation: "even",
+			expectedStatus:     http.StatusAccepted,
+		},
+		{
+			name:           "allocation_defaults_to_proportional_in_worker",
+			shippingTotal:  "10",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "rejects_negative_total",
+			shippingTotal:  "-5",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "shipping_total must be a non-negative amount",
+		},
+		{
+			name:           "rejects_malformed_total",
+			shippingTotal:  "five dollars",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "shipping_total must be a non-negative amount",
+		},
+		{
+			name:               "rejects_unknown_allocation",
+			shippingTotal:      "10",
+			shippingAllocation: "by_weight",
+			expectedStatus:     http.StatusBadRequest,
+			expectedError:      "shipping_allocation must be proportional or even",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := helpers.SetupTestRedis(t)
+			redisOpt := asynq.RedisClientOpt{Addr: redis.Server.Addr()}
+			client := asynq.NewClient(redisOpt)
+			defer client.Close()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			if tt.expectedStatus == http.StatusAccepted {
+				mockDB.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), "pdf_import", gomock.Any()).
+					Return(pgconn.CommandTag{}, nil)
+			}
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			handler := handlers.NewImportHandler(client, nil, mockDB, files, helpers.TestLogger(), 1<<20, nil)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			partHeader := textproto.MIMEHeader{}
+			partHeader.Set("Content-Disposition", `form-data; name="file"; filename="invoice.pdf"`)
+			partHeader.Set("Content-Type", "application/pdf")
+			part, err := writer.CreatePart(partHeader)
+			require.NoError(t, err)
+			_, err = part.Write([]byte("%PDF-1.4 invoice"))
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteField("invoice_id", "INV-100"))
+			require.NoError(t, writer.WriteField("shipping_total", tt.shippingTotal))
+			require.NoError(t, writer.WriteField("shipping_allocation", tt.shippingAllocation))
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/import/pdf", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			handler.ImportPDF(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedError != "" {
+				response := decodeError(t, rec.Body.Bytes())
+				assert.Equal(t, tt.expectedError, response.Message)
+				return
+			}
+
+			inspector := asynq.NewInspector(redisOpt)
+			defer inspector.Close()
+			tasks, err := inspector.ListPendingTasks("default")
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			var payload workers.PDFJobPayload
+			require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+			assert.True(t, decimal.RequireFromString(tt.shippingTotal).Equal(payload.ShippingTotal),
+				"shipping_total: %s", payload.ShippingTotal)
+			assert.Equal(t, tt.shippingAllocation, payload.ShippingAllocation)
+		})
+	}
+}
+
+func TestImportHandler_CancelImport(t *testing.T) {
+	jobID := uuid.New().String()
+	fileKey := "imports/" + jobID + "_lots.xlsx"
+	createdAt := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	cancelledRow := func() pgx.Row {
+		return &mockRow{rows: &mockRows{values: [][]any{{fileKey}}}}
+	}
+
+	tests := []struct {
+		name           string
+		jobID          string
+		setupMocks     func(*mocks.MockDatabase, *mocks.MockQueueInspector)
+		expectedStatus int
+		expectUpload   bool
+		validateBody   func(*testing.T, map[string]any)
+	}{
+		{
+			name:  "drops_queued_task_and_upload",
+			jobID: jobID,
+			setupMocks: func(db *mocks.MockDatabase, i *mocks.MockQueueInspector) {
+				db.EXPECT().QueryRow(gomock.Any(), gomock.Any(), jobID).Return(cancelledRow())
+				i.EXPECT().DeleteTask("default", jobID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, jobID, body["job_id"])
+				assert.Equal(t, "cancelled", body["status"])
+			},
+		},
+		{
+			name:  "finds_batch_task_on_low_queue",
+			jobID: jobID,
+			setupMocks: func(db *mocks.MockDatabase, i *mocks.MockQueueInspector) {
+				db.EXPECT().QueryRow(gomock.Any(), gomock.Any(), jobID).Return(cancelledRow())
+				gomock.InOrder(
+					i.EXPECT().DeleteTask("default", jobID).Return(fmt.Errorf("asynq: %w", asynq.ErrTaskNotFound)),
+					i.EXPECT().DeleteTask("low", jobID).Return(nil),
+				)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "running_job_keeps_upload_for_worker",
+			jobID: jobID,
+			setupMocks: func(db *mocks.MockDatabase, i *mocks.MockQueueInspector) {
+				db.EXPECT().QueryRow(gomock.Any(), gomock.Any(), jobID).Return(cancelledRow())
+				i.EXPECT().DeleteTask("default", jobID).Return(errors.New("cannot delete task in active state"))
+			},
+			expectedStatus: http.StatusOK,
+			expectUpload:   true,
+			validateBody: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "cancelled", body["status"])
+			},
+		},
+		{
+			name:  "finished_job_conflicts",
+			jobID: jobID,
+			setupMocks: func(db *mocks.MockDatabase, i *mocks.MockQueueInspector) {
+				gomock.InOrder(
+					db.EXPECT().QueryRow(gomock.Any(), gomock.Any(), jobID).Return(&mockRow{err: pgx.ErrNoRows}),
+					db.EXPECT().QueryRow(gomock.Any(), gomock.Any(), jobID).Return(&mockRow{rows: &mockRows{values: [][]any{{
+						jobID, "excel_import", "completed", 100, 3, nil, json.RawMessage(nil), 1,
+						createdAt, nil, nil, nil,
+					}}}}),
+				)
+			},
+			expectedStatus: http.StatusConflict,
+			expectUpload:   true,
+			validateBody: func(t *testing.T, body map[string]any) {
+				detail, ok := body["error"].(map[string]any)
+				require.True(t, ok, "error envelope")
+				assert.Equal(t, handlers.ErrCodeConflict, detail["code"])
+				assert.Equal(t, "Job has already finished with status completed", detail["message"])
+			},
+		},
+		{
+			name:  "job_not_found",
+			jobID: jobID,
+			setupMocks: func(db *mocks.MockDatabase, i *mocks.MockQueueInspector) {
+				db.EXPECT().QueryRow(gomock.Any(), gomock.Any(), jobID).Return(&mockRow{err: pgx.ErrNoRows}).Times(2)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectUpload:   true,
+		},
+		{
+			name:           "invalid_job_id",
+			jobID:          "not-a-uuid",
+			setupMocks:     func(*mocks.MockDatabase, *mocks.MockQueueInspector) {},
+			expectedStatus: http.StatusBadRequest,
+			expectUpload:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			inspector := mocks.NewMockQueueInspector(ctrl)
+			tt.setupMocks(mockDB, inspector)
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			_, err := files.Upload(context.Background(), fileKey, bytes.NewReader([]byte("xlsx")), "")
+			require.NoError(t, err)
+
+			handler := handlers.NewImportHandler(nil, inspector, mockDB, files, helpers.TestLogger(), 1<<20, nil)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/import/"+tt.jobID, nil)
+			req.SetPathValue("jobId", tt.jobID)
+			rec := httptest.NewRecorder()
+
+			handler.CancelImport(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.validateBody != nil {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				tt.validateBody(t, body)
+			}
+
+			exists, err := files.Exists(context.Background(), fileKey)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectUpload, exists)
+		})
+	}
+}
@@ -2,31 +2,62 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
 	"github.com/ammerola/resell-be/internal/core/domain"
 	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
 )
 
+// Idempotency-Key support for POST /api/v1/inventory
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	idempotentReplayHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+)
+
+// defaultMaxBodyBytes caps JSON request bodies when no limit is configured
+const defaultMaxBodyBytes = 1 << 20
+
 // InventoryHandler handles inventory-related HTTP requests
 type InventoryHandler struct {
-	service ports.InventoryService
-	logger  *slog.Logger
+	service        ports.InventoryService
+	cache          ports.CacheRepository
+	caches         *redis_a.CacheManager
+	idempotencyTTL time.Duration
+	maxBodyBytes   int64
+	logger         *slog.Logger
 }
 
-// NewInventoryHandler creates a new inventory handler
-func NewInventoryHandler(service ports.InventoryService, logger *slog.Logger) *InventoryHandler {
+// NewInventoryHandler creates a new inventory handler. Idempotency keys sent when creating
+// items are remembered in cache for idempotencyTTL, and every write clears the cached
+// inventory reads and exports it may have made stale. Item bodies larger than maxBodyBytes
+// are rejected; zero uses a 1 MB limit.
+func NewInventoryHandler(service ports.InventoryService, cache ports.CacheRepository, idempotencyTTL time.Duration, maxBodyBytes int64, logger *slog.Logger) *InventoryHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	logger = logger.With(slog.String("handler", "inventory"))
 	return &InventoryHandler{
-		service: service,
-		logger:  logger.With(slog.String("handler", "inventory")),
+		service:        service,
+		cache:          cache,
+		caches:         redis_a.NewCacheManager(cache, logger),
+		idempotencyTTL: idempotencyTTL,
+		maxBodyBytes:   maxBodyBytes,
+		logger:         logger,
 	}
 }
 
@@ -38,7 +69,7 @@ func (h *InventoryHandler) GetInventory(w http.ResponseWriter, r *http.Request)
 	// Parse UUID
 	lotID, err := uuid.Parse(idStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid inventory ID format")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid inventory ID format")
 		return
 	}
 
@@ -49,12 +80,12 @@ func (h *InventoryHandler) GetInventory(w http.ResponseWriter, r *http.Request)
 			slog.String("lot_id", idStr),
 			slog.String("error", err.Error()))
 
-		if err.Error() == "inventory item not found: "+idStr {
-			h.respondError(w, http.StatusNotFound, "Inventory item not found")
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Inventory item not found")
 			return
 		}
 
-		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve inventory item")
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve inventory item")
 		return
 	}
 
@@ -66,47 +97,83 @@ func (h *InventoryHandler) ListInventory(w http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 
 	// Parse query parameters
-	params := h.parseListParams(r)
+	params, err := parseListParams(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		return
+	}
 
 	// List inventory items
 	result, err := h.service.List(ctx, params)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to list inventory items",
 			slog.String("error", err.Error()))
-		h.respondError(w, http.StatusInternalServerError, "Failed to list inventory items")
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to list inventory items")
 		return
 	}
 
 	h.respondJSON(w, http.StatusOK, result)
 }
 
-// CreateInventory handles POST /api/v1/inventory
+// CreateInventory handles POST /api/v1/inventory. A request with an Idempotency-Key header
+// that was already used returns the item the first request created instead of a new one.
 func (h *InventoryHandler) CreateInventory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest,
+			fmt.Sprintf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+		return
+	}
+
 	// Parse request body
 	var req CreateInventoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !h.decodeItemBody(w, r, &req) {
 		return
 	}
 
 	// Validate required fields
 	if err := req.Validate(); err != nil {
-		h.respondError(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, http.StatusBadRequest, ErrCodeValidation, err.Error())
 		return
 	}
 
 	// Convert to domain model
 	item := req.ToDomain()
 
+	// Claim the key for this item's lot ID before saving, so a retry finds it
+	var cacheKey string
+	if idempotencyKey != "" {
+		cacheKey = idempotencyCacheKey(r, idempotencyKey)
+		claimed, err := h.cache.SetNX(ctx, cacheKey, item.LotID, h.idempotencyTTL)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to claim idempotency key",
+				slog.String("error", err.Error()))
+			writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to create inventory item")
+			return
+		}
+		if !claimed {
+			h.replayCreate(w, r, cacheKey)
+			return
+		}
+	}
+
 	// Save inventory item
 	if err := h.service.SaveItem(ctx, item); err != nil {
 		h.logger.ErrorContext(ctx, "failed to create inventory item",
 			slog.String("error", err.Error()))
-		h.respondError(w, http.StatusInternalServerError, "Failed to create inventory item")
+
+		// Release the key so the client can retry with it
+		if cacheKey != "" {
+			if _, err := h.cache.CompareAndDelete(ctx, cacheKey, item.LotID); err != nil {
+				h.logger.WarnContext(ctx, "failed to release idempotency key", slog.String("error", err.Error()))
+			}
+		}
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to create inventory item")
 		return
 	}
+	h.invalidateCaches(ctx, item.LotID.String())
 
 	h.logger.InfoContext(ctx, "inventory item created",
 		slog.String("lot_id", item.LotID.String()),
@@ -115,6 +182,61 @@ func (h *InventoryHandler) CreateInventory(w http.ResponseWriter, r *http.Reques
 	h.respondJSON(w, http.StatusCreated, item)
 }
 
+// replayCreate answers a repeated Idempotency-Key with the item the first request created.
+// While that request is still saving, or when its item has since been deleted, there is
+// nothing to return and the client gets a conflict.
+func (h *InventoryHandler) replayCreate(w http.ResponseWriter, r *http.Request, cacheKey string) {
+	ctx := r.Context()
+
+	var lotID uuid.UUID
+	if err := h.cache.Get(ctx, cacheKey, &lotID); err != nil {
+		if errors.Is(err, redis_a.ErrCacheMiss) {
+			// The key expired just after the claim failed; a retry will claim it
+			writeError(w, r, http.StatusConflict, ErrCodeConflict, "A request with this Idempotency-Key is still being processed")
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to read idempotency key",
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to create inventory item")
+		return
+	}
+
+	item, err := h.service.GetByID(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusConflict, ErrCodeConflict, "A request with this Idempotency-Key is still being processed")
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to get idempotent inventory item",
+			slog.String("lot_id", lotID.String()),
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to retrieve inventory item")
+		return
+	}
+
+	h.logger.InfoContext(ctx, "replayed inventory creation",
+		slog.String("lot_id", lotID.String()))
+
+	w.Header().Set(idempotentReplayHeader, "true")
+	h.respondJSON(w, http.StatusCreated, item)
+}
+
+// invalidateCaches clears the cached reads and exports a successful write may have made
+// stale; an empty lotID clears every item's entries. The write has already been committed,
+// so failures are logged rather than failing the request.
+func (h *InventoryHandler) invalidateCaches(ctx context.Context, lotID string) {
+	if err := h.caches.InvalidateInventoryCache(ctx, lotID); err != nil {
+		h.logger.WarnContext(ctx, "failed to invalidate inventory caches",
+			slog.String("lot_id", lotID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// idempotencyCacheKey scopes an Idempotency-Key to the requesting user
+func idempotencyCacheKey(r *http.Request, key string) string {
+	return redis_a.BuildKey(redis_a.PrefixIdempotency, "inventory", logger.UserIDFromContext(r.Context()), key)
+}
+
 // UpdateInventory handles PUT /api/v1/inventory/{id}
 func (h *InventoryHandler) UpdateInventory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -123,25 +245,42 @@ func (h *InventoryHandler) UpdateInventory(w http.ResponseWriter, r *http.Reques
 	// Parse UUID
 	lotID, err := uuid.Parse(idStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid inventory ID format")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid inventory ID format")
 		return
 	}
 
 	// Parse request body
 	var req UpdateInventoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !h.decodeItemBody(w, r, &req) {
 		return
 	}
 
 	// Validate required fields
 	if err := req.Validate(); err != nil {
-		h.respondError(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, http.StatusBadRequest, ErrCodeValidation, err.Error())
 		return
 	}
 
+	// Load the stored item when the body omits a cost component so it isn't reset to zero
+	var current *domain.InventoryItem
+	if req.omitsCosts() {
+		current, err = h.service.GetByID(ctx, lotID)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to load inventory item for update",
+				slog.String("lot_id", idStr),
+				slog.String("error", err.Error()))
+
+			if errors.Is(err, domain.ErrNotFound) {
+				writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Inventory item not found")
+				return
+			}
+			writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to update inventory item")
+			return
+		}
+	}
+
 	// Convert to domain model
-	item := req.ToDomain()
+	item := req.ToDomain(current)
 
 	// Update inventory item
 	if err := h.service.UpdateItem(ctx, lotID, item); err != nil {
@@ -149,14 +288,19 @@ func (h *InventoryHandler) UpdateInventory(w http.ResponseWriter, r *http.Reques
 			slog.String("lot_id", idStr),
 			slog.String("error", err.Error()))
 
-		if err.Error() == "inventory item not found: "+idStr {
-			h.respondError(w, http.StatusNotFound, "Inventory item not found")
+		if errors.Is(err, domain.ErrVersionConflict) {
+			writeError(w, r, http.StatusConflict, ErrCodeConflict, "Inventory item was modified by another request; reload and retry")
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Inventory item not found")
 			return
 		}
 
-		h.respondError(w, http.StatusInternalServerError, "Failed to update inventory item")
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to update inventory item")
 		return
 	}
+	h.invalidateCaches(ctx, idStr)
 
 	// Retrieve updated item
 	updatedItem, err := h.service.GetByID(ctx, lotID)
@@ -175,6 +319,54 @@ func (h *InventoryHandler) UpdateInventory(w http.ResponseWriter, r *http.Reques
 	h.respondJSON(w, http.StatusOK, updatedItem)
 }
 
+// PatchInventory handles PATCH /api/v1/inventory/{id}. Only the fields present in the body are
+// changed, and null clears an optional field.
+func (h *InventoryHandler) PatchInventory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := r.PathValue("id")
+
+	lotID, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid inventory ID format")
+		return
+	}
+
+	var req PatchInventoryRequest
+	if !h.decodeItemBody(w, r, &req) {
+		return
+	}
+	patch, err := req.Validate()
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeValidation, err.Error())
+		return
+	}
+
+	item, err := h.service.PatchItem(ctx, lotID, patch)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to patch inventory item",
+			slog.String("lot_id", idStr),
+			slog.String("error", err.Error()))
+
+		switch {
+		case errors.Is(err, domain.ErrVersionConflict):
+			writeError(w, r, http.StatusConflict, ErrCodeConflict, "Inventory item was modified by another request; reload and retry")
+		case strings.Contains(err.Error(), "validation failed"):
+			writeError(w, r, http.StatusBadRequest, ErrCodeValidation, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Inventory item not found")
+		default:
+			writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to update inventory item")
+		}
+		return
+	}
+	h.invalidateCaches(ctx, idStr)
+
+	h.logger.InfoContext(ctx, "inventory item patched",
+		slog.String("lot_id", idStr))
+
+	h.respondJSON(w, http.StatusOK, item)
+}
+
 // DeleteInventory handles DELETE /api/v1/inventory/{id}
 func (h *InventoryHandler) DeleteInventory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -183,7 +375,7 @@ func (h *InventoryHandler) DeleteInventory(w http.ResponseWriter, r *http.Reques
 	// Parse UUID
 	lotID, err := uuid.Parse(idStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid inventory ID format")
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid inventory ID format")
 		return
 	}
 
@@ -197,14 +389,15 @@ func (h *InventoryHandler) DeleteInventory(w http.ResponseWriter, r *http.Reques
 			slog.Bool("permanent", permanent),
 			slog.String("error", err.Error()))
 
-		if err.Error() == "inventory item not found: "+idStr {
-			h.respondError(w, http.StatusNotFound, "Inventory item not found")
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Inventory item not found")
 			return
 		}
 
-		h.respondError(w, http.StatusInternalServerError, "Failed to delete inventory item")
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to delete inventory item")
 		return
 	}
+	h.invalidateCaches(ctx, idStr)
 
 	h.logger.InfoContext(ctx, "inventory item deleted",
 		slog.String("lot_id", idStr),
@@ -217,8 +410,239 @@ func (h *InventoryHandler) DeleteInventory(w http.ResponseWriter, r *http.Reques
 	})
 }
 
-// parseListParams parses query parameters for listing inventory
-func (h *InventoryHandler) parseListParams(r *http.Request) ports.ListParams {
+// RestoreInventory handles POST /api/v1/inventory/{id}/restore
+func (h *InventoryHandler) RestoreInventory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := r.PathValue("id")
+
+	// Parse UUID
+	lotID, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid inventory ID format")
+		return
+	}
+
+	item, err := h.service.RestoreItem(ctx, lotID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to restore inventory item",
+			slog.String("lot_id", idStr),
+			slog.String("error", err.Error()))
+
+		// Missing items and items that were never deleted are both reported as not found
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Deleted inventory item not found")
+			return
+		}
+
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to restore inventory item")
+		return
+	}
+	h.invalidateCaches(ctx, idStr)
+
+	h.logger.InfoContext(ctx, "inventory item restored",
+		slog.String("lot_id", idStr))
+
+	h.respondJSON(w, http.StatusOK, item)
+}
+
+// GetInventoryHistory handles GET /api/v1/inventory/{id}/history
+func (h *InventoryHandler) GetInventoryHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := r.PathValue("id")
+
+	// Parse UUID
+	lotID, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid inventory ID format")
+		return
+	}
+
+	entries, err := h.service.GetHistory(ctx, lotID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get inventory history",
+			slog.String("lot_id", idStr),
+			slog.String("error", err.Error()))
+
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, ErrCodeNotFound, "Inventory item not found")
+			return
+		}
+
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to get inventory history")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"lot_id":  idStr,
+		"history": entries,
+	})
+}
+
+// BulkUpdateInventory handles PATCH /api/v1/inventory/bulk
+func (h *InventoryHandler) BulkUpdateInventory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req BulkUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+		return
+	}
+
+	updates, err := req.Validate()
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeValidation, err.Error())
+		return
+	}
+
+	affected, err := h.service.BulkUpdateFields(ctx, req.LotIDs, updates)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to bulk update inventory items",
+			slog.Int("lot_ids", len(req.LotIDs)),
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to update inventory items")
+		return
+	}
+	h.invalidateCaches(ctx, "")
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "Inventory items updated successfully",
+		"rows_affected": affected,
+	})
+}
+
+// GetInvoiceItems handles GET /api/v1/invoices/{invoiceId}/items. An invoice with no items,
+// including one that was never imported, returns an empty list rather than 404.
+func (h *InventoryHandler) GetInvoiceItems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	invoiceID := strings.TrimSpace(r.PathValue("invoiceId"))
+	if invoiceID == "" {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invoice ID is required")
+		return
+	}
+
+	result, err := h.service.GetInvoiceItems(ctx, invoiceID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get invoice items",
+			slog.String("invoice_id", invoiceID),
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to get invoice items")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ListInvoices handles GET /api/v1/invoices, paging through invoices with their item counts
+// and summed total cost. sort is date (the default) or total, and order asc or desc.
+func (h *InventoryHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := ports.InvoiceListParams{
+		Page:      1,
+		PageSize:  50,
+		SortBy:    ports.InvoiceSortDate,
+		SortOrder: "desc",
+	}
+
+	if page := r.URL.Query().Get("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil && p > 0 {
+			params.Page = p
+		}
+	}
+	// Out-of-range limits fall back to the default page size
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 && l <= 100 {
+			params.PageSize = l
+		}
+	}
+
+	switch sortBy := r.URL.Query().Get("sort"); sortBy {
+	case "":
+	case ports.InvoiceSortDate, ports.InvoiceSortTotal:
+		params.SortBy = sortBy
+	default:
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "sort must be one of: date, total")
+		return
+	}
+	switch order := r.URL.Query().Get("order"); order {
+	case "":
+	case "asc", "desc":
+		params.SortOrder = order
+	default:
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "order must be one of: asc, desc")
+		return
+	}
+
+	result, err := h.service.ListInvoices(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list invoices",
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to list invoices")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// StorageSummary handles GET /api/v1/storage/summary, counting the active items and their
+// estimated value per storage location and bin
+func (h *InventoryHandler) StorageSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	summary, err := h.service.StorageSummary(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to summarize storage",
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to summarize storage")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, summary)
+}
+
+// defaultReclassifyConfidence is the classifier score a reclassification must beat when the
+// request doesn't set min_confidence
+const defaultReclassifyConfidence = 0.5
+
+// ReclassifyInventory handles POST /api/v1/inventory/reclassify. The items are selected with
+// the same query filters as ListInventory; with none, every active item is reclassified.
+func (h *InventoryHandler) ReclassifyInventory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseListParams(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		return
+	}
+
+	var req ReclassifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+		return
+	}
+	params, err := req.Validate()
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, ErrCodeValidation, err.Error())
+		return
+	}
+	params.Filter = filter
+
+	result, err := h.service.Reclassify(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to reclassify inventory items",
+			slog.Bool("dry_run", params.DryRun),
+			slog.String("error", err.Error()))
+		writeError(w, r, http.StatusInternalServerError, ErrCodeInternal, "Failed to reclassify inventory items")
+		return
+	}
+	if result.Updated > 0 {
+		h.invalidateCaches(ctx, "")
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// parseListParams parses query parameters for listing inventory, rejecting malformed filters
+func parseListParams(r *http.Request) (ports.ListParams, error) {
 	params := ports.ListParams{
 		Page:      1,
 		PageSize:  50,
@@ -233,16 +657,23 @@ func (h *InventoryHandler) parseListParams(r *http.Request) ports.ListParams {
 		}
 	}
 
+	// Out-of-range limits fall back to the default page size
 	if limit := r.URL.Query().Get("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			if l > 100 {
-				params.PageSize = 100
-			} else {
-				params.PageSize = l
-			}
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 && l <= 100 {
+			params.PageSize = l
 		}
 	}
 
+	// A cursor switches to keyset pagination and takes precedence over page
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		createdAt, lotID, err := ports.DecodeListCursor(cursor)
+		if err != nil {
+			return params, fmt.Errorf("cursor is invalid")
+		}
+		params.AfterCreatedAt = &createdAt
+		params.AfterLotID = lotID
+	}
+
 	// Parse filters
 	params.Search = r.URL.Query().Get("search")
 	params.Category = r.URL.Query().Get("category")
@@ -257,6 +688,55 @@ func (h *InventoryHandler) parseListParams(r *http.Request) ports.ListParams {
 		}
 	}
 
+	if includeTotals := r.URL.Query().Get("include_totals"); includeTotals != "" {
+		if val, err := strconv.ParseBool(includeTotals); err == nil {
+			params.IncludeTotals = val
+		}
+	}
+
+	if highlight := r.URL.Query().Get("highlight"); highlight != "" {
+		if val, err := strconv.ParseBool(highlight); err == nil {
+			params.Highlight = val
+		}
+	}
+
+	switch deleted := r.URL.Query().Get("deleted"); deleted {
+	case "":
+	case "include":
+		params.IncludeDeleted = true
+	case "only":
+		params.OnlyDeleted = true
+	default:
+		return params, fmt.Errorf("deleted must be one of: include, only")
+	}
+
+	var err error
+	params.Categories, err = parseEnumList(r, "categories", func(v string) bool {
+		return domain.ItemCategory(v).IsValid()
+	})
+	if err != nil {
+		return params, err
+	}
+	params.Conditions, err = parseEnumList(r, "conditions", func(v string) bool {
+		return domain.ItemCondition(v).IsValid()
+	})
+	if err != nil {
+		return params, err
+	}
+
+	params.MinTotalCost, params.MaxTotalCost, err = parseDecimalRange(r, "min_total_cost", "max_total_cost")
+	if err != nil {
+		return params, err
+	}
+	params.MinBidAmount, params.MaxBidAmount, err = parseDecimalRange(r, "min_bid_amount", "max_bid_amount")
+	if err != nil {
+		return params, err
+	}
+	params.AcquiredFrom, params.AcquiredTo, err = parseDateRange(r, "acquired_from", "acquired_to")
+	if err != nil {
+		return params, err
+	}
+
 	// Parse sorting
 	if sortBy := r.URL.Query().Get("sort"); sortBy != "" {
 		params.SortBy = sortBy
@@ -266,7 +746,87 @@ func (h *InventoryHandler) parseListParams(r *http.Request) ports.ListParams {
 		params.SortOrder = order
 	}
 
-	return params
+	return params, nil
+}
+
+// parseEnumList parses a comma-separated list of values, rejecting any that fail valid
+func parseEnumList(r *http.Request, key string, valid func(string) bool) ([]string, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !valid(v) {
+			return nil, fmt.Errorf("unknown value %q in %s", v, key)
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// parseDecimalRange parses an optional pair of non-negative bounds, rejecting inverted ranges
+func parseDecimalRange(r *http.Request, minKey, maxKey string) (*decimal.Decimal, *decimal.Decimal, error) {
+	parse := func(key string) (*decimal.Decimal, error) {
+		raw := r.URL.Query().Get(key)
+		if raw == "" {
+			return nil, nil
+		}
+		d, err := decimal.NewFromString(raw)
+		if err != nil || d.IsNegative() {
+			return nil, fmt.Errorf("%s must be a non-negative number", key)
+		}
+		return &d, nil
+	}
+
+	lo, err := parse(minKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	hi, err := parse(maxKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lo != nil && hi != nil && lo.GreaterThan(*hi) {
+		return nil, nil, fmt.Errorf("%s cannot be greater than %s", minKey, maxKey)
+	}
+
+	return lo, hi, nil
+}
+
+// parseDateRange parses an optional pair of YYYY-MM-DD dates, rejecting inverted ranges
+func parseDateRange(r *http.Request, fromKey, toKey string) (*time.Time, *time.Time, error) {
+	parse := func(key string) (*time.Time, error) {
+		raw := r.URL.Query().Get(key)
+		if raw == "" {
+			return nil, nil
+		}
+		t, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date in YYYY-MM-DD format", key)
+		}
+		return &t, nil
+	}
+
+	from, err := parse(fromKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parse(toKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, fmt.Errorf("%s cannot be after %s", fromKey, toKey)
+	}
+
+	return from, to, nil
 }
 
 // Helper methods
@@ -281,8 +841,30 @@ func (h *InventoryHandler) respondJSON(w http.ResponseWriter, status int, data i
 	}
 }
 
-func (h *InventoryHandler) respondError(w http.ResponseWriter, status int, message string) {
-	h.respondJSON(w, status, map[string]string{"error": message})
+// decodeItemBody decodes an item write body into dst, reading at most maxBodyBytes and
+// rejecting unknown fields so a misspelled field isn't silently ignored. On failure it writes
+// the error response and returns false.
+func (h *InventoryHandler) decodeItemBody(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(dst)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, r, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge,
+			fmt.Sprintf("Request body must be at most %d bytes", tooLarge.Limit))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest,
+			"Invalid request body: unknown field "+strings.TrimPrefix(err.Error(), "json: unknown field "))
+	default:
+		writeError(w, r, http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	return false
 }
 
 // Request/Response DTOs
@@ -329,9 +911,33 @@ func (r *CreateInventoryRequest) Validate() error {
 	if r.BidAmount.IsNegative() {
 		return fmt.Errorf("bid_amount cannot be negative")
 	}
+	return validateItemEnums(r.Category, r.Condition, r.MarketDemand)
+}
+
+// validateItemEnums rejects a category, condition or market demand the domain doesn't know,
+// listing the allowed values. Empty values are allowed and default in ToDomain.
+func validateItemEnums(category, condition, demand string) error {
+	if category != "" && !domain.ItemCategory(category).IsValid() {
+		return fmt.Errorf("unknown category %q; must be one of: %s", category, joinEnum(domain.AllCategories()))
+	}
+	if condition != "" && !domain.ItemCondition(condition).IsValid() {
+		return fmt.Errorf("unknown condition %q; must be one of: %s", condition, joinEnum(domain.AllConditions()))
+	}
+	if demand != "" && !domain.MarketDemandLevel(demand).IsValid() {
+		return fmt.Errorf("unknown market_demand %q; must be one of: %s", demand, joinEnum(domain.AllMarketDemandLevels()))
+	}
 	return nil
 }
 
+// joinEnum lists enum values for an error message
+func joinEnum[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = string(v)
+	}
+	return strings.Join(names, ", ")
+}
+
 // ToDomain converts the request to a domain model
 func (r *CreateInventoryRequest) ToDomain() *domain.InventoryItem {
 	item := &domain.InventoryItem{
@@ -380,7 +986,8 @@ func (r *CreateInventoryRequest) ToDomain() *domain.InventoryItem {
 	return item
 }
 
-// UpdateInventoryRequest represents the request body for updating inventory
+// UpdateInventoryRequest represents the request body for updating inventory. The cost components
+// are pointers so that one left out of the body keeps its stored value instead of becoming zero.
 type UpdateInventoryRequest struct {
 	InvoiceID        string           `json:"invoice_id"`
 	AuctionID        int              `json:"auction_id,omitempty"`
@@ -391,9 +998,9 @@ type UpdateInventoryRequest struct {
 	Condition        string           `json:"condition,omitempty"`
 	Quantity         int              `json:"quantity"`
 	BidAmount        decimal.Decimal  `json:"bid_amount"`
-	BuyersPremium    decimal.Decimal  `json:"buyers_premium,omitempty"`
-	SalesTax         decimal.Decimal  `json:"sales_tax,omitempty"`
-	ShippingCost     decimal.Decimal  `json:"shipping_cost,omitempty"`
+	BuyersPremium    *decimal.Decimal `json:"buyers_premium,omitempty"`
+	SalesTax         *decimal.Decimal `json:"sales_tax,omitempty"`
+	ShippingCost     *decimal.Decimal `json:"shipping_cost,omitempty"`
 	AcquisitionDate  time.Time        `json:"acquisition_date"`
 	StorageLocation  string           `json:"storage_location,omitempty"`
 	StorageBin       string           `json:"storage_bin,omitempty"`
@@ -405,6 +1012,13 @@ type UpdateInventoryRequest struct {
 	IsReturned       bool             `json:"is_returned,omitempty"`
 	Keywords         []string         `json:"keywords,omitempty"`
 	Notes            string           `json:"notes,omitempty"`
+	// Version is the version the client last read; zero skips the conflict check
+	Version int `json:"version,omitempty"`
+}
+
+// omitsCosts reports whether any cost component is missing and must come from the stored item
+func (r *UpdateInventoryRequest) omitsCosts() bool {
+	return r.BuyersPremium == nil || r.SalesTax == nil || r.ShippingCost == nil
 }
 
 // Validate validates the update inventory request
@@ -421,11 +1035,12 @@ func (r *UpdateInventoryRequest) Validate() error {
 	if r.BidAmount.IsNegative() {
 		return fmt.Errorf("bid_amount cannot be negative")
 	}
-	return nil
+	return validateItemEnums(r.Category, r.Condition, r.MarketDemand)
 }
 
-// ToDomain converts the request to a domain model
-func (r *UpdateInventoryRequest) ToDomain() *domain.InventoryItem {
+// ToDomain converts the request to a domain model. Cost components the request omits are
+// taken from current, the stored item, when it is non-nil.
+func (r *UpdateInventoryRequest) ToDomain(current *domain.InventoryItem) *domain.InventoryItem {
 	item := &domain.InventoryItem{
 		InvoiceID:        r.InvoiceID,
 		AuctionID:        r.AuctionID,
@@ -436,9 +1051,6 @@ func (r *UpdateInventoryRequest) ToDomain() *domain.InventoryItem {
 		Condition:        domain.ItemCondition(r.Condition),
 		Quantity:         r.Quantity,
 		BidAmount:        r.BidAmount,
-		BuyersPremium:    r.BuyersPremium,
-		SalesTax:         r.SalesTax,
-		ShippingCost:     r.ShippingCost,
 		AcquisitionDate:  r.AcquisitionDate,
 		StorageLocation:  r.StorageLocation,
 		StorageBin:       r.StorageBin,
@@ -450,6 +1062,22 @@ func (r *UpdateInventoryRequest) ToDomain() *domain.InventoryItem {
 		IsReturned:       r.IsReturned,
 		Keywords:         r.Keywords,
 		Notes:            r.Notes,
+		Version:          r.Version,
+	}
+
+	if current != nil {
+		item.BuyersPremium = current.BuyersPremium
+		item.SalesTax = current.SalesTax
+		item.ShippingCost = current.ShippingCost
+	}
+	if r.BuyersPremium != nil {
+		item.BuyersPremium = *r.BuyersPremium
+	}
+	if r.SalesTax != nil {
+		item.SalesTax = *r.SalesTax
+	}
+	if r.ShippingCost != nil {
+		item.ShippingCost = *r.ShippingCost
 	}
 
 	// Set defaults
@@ -465,3 +1093,105 @@ func (r *UpdateInventoryRequest) ToDomain() *domain.InventoryItem {
 
 	return item
 }
+
+// PatchInventoryRequest is the body of a partial update: any subset of the update fields, where
+// null clears an optional field
+type PatchInventoryRequest struct {
+	ports.InventoryPatch
+}
+
+// Validate checks the set fields and returns the patch
+func (r *PatchInventoryRequest) Validate() (ports.InventoryPatch, error) {
+	patch := r.InventoryPatch
+	if patch.IsEmpty() {
+		return patch, fmt.Errorf("at least one field to update is required")
+	}
+	if field := patch.NullRequiredField(); field != "" {
+		return patch, fmt.Errorf("%s cannot be null", field)
+	}
+
+	var category, condition, demand string
+	if patch.Category.Value != nil {
+		category = string(*patch.Category.Value)
+	}
+	if patch.Condition.Value != nil {
+		condition = string(*patch.Condition.Value)
+	}
+	if patch.MarketDemand.Value != nil {
+		demand = string(*patch.MarketDemand.Value)
+	}
+	if err := validateItemEnums(category, condition, demand); err != nil {
+		return patch, err
+	}
+
+	return patch, nil
+}
+
+// ReclassifyRequest holds the options of a reclassification; the body may be omitted
+type ReclassifyRequest struct {
+	MinConfidence *float64 `json:"min_confidence"`
+	DryRun        bool     `json:"dry_run"`
+}
+
+// Validate checks the options and converts them to the service's parameters
+func (r *ReclassifyRequest) Validate() (ports.ReclassifyParams, error) {
+	params := ports.ReclassifyParams{
+		MinConfidence: defaultReclassifyConfidence,
+		DryRun:        r.DryRun,
+	}
+	if r.MinConfidence != nil {
+		if *r.MinConfidence < 0 || *r.MinConfidence > 1 {
+			return params, fmt.Errorf("min_confidence must be between 0 and 1")
+		}
+		params.MinConfidence = *r.MinConfidence
+	}
+	return params, nil
+}
+
+// BulkUpdateRequest represents a request to update the same fields on many items
+type BulkUpdateRequest struct {
+	LotIDs  []uuid.UUID                `json:"lot_ids"`
+	Updates map[string]json.RawMessage `json:"updates"`
+}
+
+// Validate checks the request against the bulk-updatable field whitelist and converts it
+// to the service's update set
+func (r *BulkUpdateRequest) Validate() (ports.BulkFieldUpdates, error) {
+	var updates ports.BulkFieldUpdates
+
+	if len(r.LotIDs) == 0 {
+		return updates, fmt.Errorf("lot_ids is required")
+	}
+	if len(r.Updates) == 0 {
+		return updates, fmt.Errorf("updates is required")
+	}
+
+	for key, raw := range r.Updates {
+		var err error
+		switch key {
+		case "storage_location":
+			err = json.Unmarshal(raw, &updates.StorageLocation)
+		case "storage_bin":
+			err = json.Unmarshal(raw, &updates.StorageBin)
+		case "needs_repair":
+			err = json.Unmarshal(raw, &updates.NeedsRepair)
+		case "market_demand":
+			err = json.Unmarshal(raw, &updates.MarketDemand)
+			if err == nil && updates.MarketDemand != nil && !updates.MarketDemand.IsValid() {
+				return updates, fmt.Errorf("unknown market_demand %q; must be one of: %s",
+					*updates.MarketDemand, joinEnum(domain.AllMarketDemandLevels()))
+			}
+		default:
+			return updates, fmt.Errorf("field %q cannot be bulk updated", key)
+		}
+		if err != nil {
+			return updates, fmt.Errorf("invalid value for %s", key)
+		}
+	}
+
+	if updates.IsEmpty() {
+		return updates, fmt.Errorf("updates must set at least one field")
+	}
+
+	return updates, nil
+}
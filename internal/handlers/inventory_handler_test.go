@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -17,15 +19,24 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
 	"github.com/ammerola/resell-be/internal/core/domain"
-	"github.com/ammerola/resell-be/internal/core/services"
+	"github.com/ammerola/resell-be/internal/core/ports"
 	"github.com/ammerola/resell-be/internal/handlers"
 	"github.com/ammerola/resell-be/test/helpers"
 	"github.com/ammerola/resell-be/test/mocks"
 )
 
+// newInvalidatingCache returns a cache that accepts the invalidation every successful write performs
+func newInvalidatingCache(ctrl *gomock.Controller) *mocks.MockCacheRepository {
+	cache := mocks.NewMockCacheRepository(ctrl)
+	cache.EXPECT().DeletePattern(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return cache
+}
+
 func TestInventoryHandler_GetInventory(t *testing.T) {
 	testItem := helpers.CreateTestInventoryItem()
+	missingID := uuid.New()
 
 	tests := []struct {
 		name           string
@@ -57,26 +68,22 @@ func TestInventoryHandler_GetInventory(t *testing.T) {
 			setupMocks:     func(m *mocks.MockInventoryService) {},
 			expectedStatus: http.StatusBadRequest,
 			validateBody: func(t *testing.T, body []byte) {
-				var response map[string]string
-				err := json.Unmarshal(body, &response)
-				require.NoError(t, err)
-				assert.Equal(t, "Invalid inventory ID format", response["error"])
+				response := decodeError(t, body)
+				assert.Equal(t, "Invalid inventory ID format", response.Message)
 			},
 		},
 		{
 			name:  "item_not_found",
-			lotID: uuid.New().String(),
+			lotID: missingID.String(),
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
-					GetByID(gomock.Any(), gomock.Any()).
-					Return(nil, fmt.Errorf("inventory item not found: %s", uuid.New()))
+					GetByID(gomock.Any(), missingID).
+					Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, missingID))
 			},
 			expectedStatus: http.StatusNotFound,
 			validateBody: func(t *testing.T, body []byte) {
-				var response map[string]string
-				err := json.Unmarshal(body, &response)
-				require.NoError(t, err)
-				assert.Equal(t, "Inventory item not found", response["error"])
+				response := decodeError(t, body)
+				assert.Equal(t, "Inventory item not found", response.Message)
 			},
 		},
 		{
@@ -89,10 +96,8 @@ func TestInventoryHandler_GetInventory(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			validateBody: func(t *testing.T, body []byte) {
-				var response map[string]string
-				err := json.Unmarshal(body, &response)
-				require.NoError(t, err)
-				assert.Equal(t, "Failed to retrieve inventory item", response["error"])
+				response := decodeError(t, body)
+				assert.Equal(t, "Failed to retrieve inventory item", response.Message)
 			},
 		},
 	}
@@ -105,7 +110,7 @@ func TestInventoryHandler_GetInventory(t *testing.T) {
 
 			mockService := mocks.NewMockInventoryService(ctrl)
 			logger := helpers.TestLogger()
-			handler := handlers.NewInventoryHandler(mockService, logger)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, logger)
 
 			// Setup mocks
 			tt.setupMocks(mockService)
@@ -130,6 +135,8 @@ func TestInventoryHandler_GetInventory(t *testing.T) {
 }
 
 func TestInventoryHandler_ListInventory(t *testing.T) {
+	cursorLotID := uuid.New()
+
 	tests := []struct {
 		name           string
 		queryParams    map[string]string
@@ -146,10 +153,10 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					List(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, params services.ListParams) (*services.ListResult, error) {
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
 						assert.Equal(t, 1, params.Page)
 						assert.Equal(t, 10, params.PageSize)
-						return &services.ListResult{
+						return &ports.ListResult{
 							Items:      []*domain.InventoryItem{helpers.CreateTestInventoryItem()},
 							Page:       1,
 							PageSize:   10,
@@ -160,7 +167,7 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			validateBody: func(t *testing.T, body []byte) {
-				var response services.ListResult
+				var response ports.ListResult
 				err := json.Unmarshal(body, &response)
 				require.NoError(t, err)
 				assert.Equal(t, 1, len(response.Items))
@@ -175,9 +182,9 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					List(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, params services.ListParams) (*services.ListResult, error) {
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
 						assert.Equal(t, "antiques", params.Category)
-						return &services.ListResult{
+						return &ports.ListResult{
 							Items:      []*domain.InventoryItem{},
 							Page:       1,
 							PageSize:   50,
@@ -196,9 +203,9 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					List(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, params services.ListParams) (*services.ListResult, error) {
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
 						assert.Equal(t, "victorian", params.Search)
-						return &services.ListResult{
+						return &ports.ListResult{
 							Items:      []*domain.InventoryItem{},
 							Page:       1,
 							PageSize:   50,
@@ -217,10 +224,10 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					List(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, params services.ListParams) (*services.ListResult, error) {
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
 						require.NotNil(t, params.NeedsRepair)
 						assert.True(t, *params.NeedsRepair)
-						return &services.ListResult{
+						return &ports.ListResult{
 							Items:      []*domain.InventoryItem{},
 							Page:       1,
 							PageSize:   50,
@@ -241,6 +248,256 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "filters_by_cost_and_bid_ranges",
+			queryParams: map[string]string{
+				"min_total_cost": "10",
+				"max_total_cost": "250.50",
+				"min_bid_amount": "0",
+				"max_bid_amount": "100",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						require.NotNil(t, params.MinTotalCost)
+						require.NotNil(t, params.MaxTotalCost)
+						require.NotNil(t, params.MinBidAmount)
+						require.NotNil(t, params.MaxBidAmount)
+						assert.Equal(t, "10", params.MinTotalCost.String())
+						assert.Equal(t, "250.5", params.MaxTotalCost.String())
+						assert.True(t, params.MinBidAmount.IsZero())
+						assert.Equal(t, "100", params.MaxBidAmount.String())
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rejects_invalid_range_value",
+			queryParams: map[string]string{
+				"min_bid_amount": "cheap",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, "min_bid_amount must be a non-negative number", response.Message)
+			},
+		},
+		{
+			name: "rejects_negative_range_value",
+			queryParams: map[string]string{
+				"max_total_cost": "-5",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rejects_inverted_range",
+			queryParams: map[string]string{
+				"min_total_cost": "500",
+				"max_total_cost": "100",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, "min_total_cost cannot be greater than max_total_cost", response.Message)
+			},
+		},
+		{
+			name: "filters_by_multiple_categories_and_conditions",
+			queryParams: map[string]string{
+				"category":   "art",
+				"categories": "furniture, glass",
+				"conditions": "good,fair",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.Equal(t, "art", params.Category)
+						assert.Equal(t, []string{"furniture", "glass"}, params.Categories)
+						assert.Equal(t, []string{"good", "fair"}, params.Conditions)
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rejects_unknown_category",
+			queryParams: map[string]string{
+				"categories": "furniture,spaceships",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, `unknown value "spaceships" in categories`, response.Message)
+			},
+		},
+		{
+			name: "rejects_unknown_condition",
+			queryParams: map[string]string{
+				"conditions": "pristine",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "uses_keyset_pagination_with_cursor",
+			queryParams: map[string]string{
+				"cursor": ports.EncodeListCursor(time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC), cursorLotID),
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						require.NotNil(t, params.AfterCreatedAt)
+						assert.True(t, params.AfterCreatedAt.Equal(time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)))
+						assert.Equal(t, cursorLotID, params.AfterLotID)
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rejects_invalid_cursor",
+			queryParams: map[string]string{
+				"cursor": "not-a-cursor",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "requests_totals",
+			queryParams: map[string]string{
+				"include_totals": "true",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.True(t, params.IncludeTotals)
+						costSum := decimal.RequireFromString("125.50")
+						valueSum := decimal.NewFromInt(300)
+						return &ports.ListResult{
+							Items:                  []*domain.InventoryItem{},
+							Page:                   1,
+							PageSize:               50,
+							TotalCostSum:           &costSum,
+							TotalEstimatedValueSum: &valueSum,
+						}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, "125.5", response["total_cost_sum"])
+				assert.Equal(t, "300", response["total_estimated_value_sum"])
+			},
+		},
+		{
+			name: "sorts_by_relevance_with_highlights",
+			queryParams: map[string]string{
+				"search":    "lantern",
+				"sort":      "relevance",
+				"highlight": "true",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.Equal(t, "lantern", params.Search)
+						assert.Equal(t, "relevance", params.SortBy)
+						assert.True(t, params.Highlight)
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "lists_only_deleted_items",
+			queryParams: map[string]string{
+				"deleted": "only",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.True(t, params.OnlyDeleted)
+						assert.False(t, params.IncludeDeleted)
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "includes_deleted_items",
+			queryParams: map[string]string{
+				"deleted": "include",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.True(t, params.IncludeDeleted)
+						assert.False(t, params.OnlyDeleted)
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rejects_unknown_deleted_mode",
+			queryParams: map[string]string{
+				"deleted": "all",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "filters_by_acquisition_date_range",
+			queryParams: map[string]string{
+				"acquired_from": "2024-03-01",
+				"acquired_to":   "2024-03-31",
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						require.NotNil(t, params.AcquiredFrom)
+						require.NotNil(t, params.AcquiredTo)
+						assert.Equal(t, "2024-03-01", params.AcquiredFrom.Format("2006-01-02"))
+						assert.Equal(t, "2024-03-31", params.AcquiredTo.Format("2006-01-02"))
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 1, PageSize: 50}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "rejects_malformed_acquisition_date",
+			queryParams: map[string]string{
+				"acquired_from": "03/01/2024",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, "acquired_from must be a date in YYYY-MM-DD format", response.Message)
+			},
+		},
+		{
+			name: "rejects_inverted_acquisition_date_range",
+			queryParams: map[string]string{
+				"acquired_from": "2024-04-01",
+				"acquired_to":   "2024-03-01",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "validates_page_limit",
 			queryParams: map[string]string{
@@ -250,10 +507,10 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					List(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, params services.ListParams) (*services.ListResult, error) {
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
 						assert.Equal(t, 1, params.Page)      // Defaults to 1
 						assert.Equal(t, 50, params.PageSize) // Defaults to 50 (max is 100)
-						return &services.ListResult{
+						return &ports.ListResult{
 							Items:      []*domain.InventoryItem{},
 							Page:       1,
 							PageSize:   50,
@@ -274,7 +531,7 @@ func TestInventoryHandler_ListInventory(t *testing.T) {
 
 			mockService := mocks.NewMockInventoryService(ctrl)
 			logger := helpers.TestLogger()
-			handler := handlers.NewInventoryHandler(mockService, logger)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, logger)
 
 			// Setup mocks
 			tt.setupMocks(mockService)
@@ -329,6 +586,8 @@ func TestInventoryHandler_CreateInventory(t *testing.T) {
 					DoAndReturn(func(ctx context.Context, item *domain.InventoryItem) error {
 						assert.Equal(t, "INV-001", item.InvoiceID)
 						assert.Equal(t, "Victorian Tea Set", item.ItemName)
+						assert.Equal(t, domain.CategoryAntiques, item.Category)
+						assert.Equal(t, domain.ConditionExcellent, item.Condition)
 						return nil
 					})
 			},
@@ -346,10 +605,8 @@ func TestInventoryHandler_CreateInventory(t *testing.T) {
 			setupMocks:     func(m *mocks.MockInventoryService) {},
 			expectedStatus: http.StatusBadRequest,
 			validateBody: func(t *testing.T, body []byte) {
-				var response map[string]string
-				err := json.Unmarshal(body, &response)
-				require.NoError(t, err)
-				assert.Equal(t, "Invalid request body", response["error"])
+				response := decodeError(t, body)
+				assert.Equal(t, "Invalid request body", response.Message)
 			},
 		},
 		{
@@ -361,10 +618,9 @@ func TestInventoryHandler_CreateInventory(t *testing.T) {
 			setupMocks:     func(m *mocks.MockInventoryService) {},
 			expectedStatus: http.StatusBadRequest,
 			validateBody: func(t *testing.T, body []byte) {
-				var response map[string]string
-				err := json.Unmarshal(body, &response)
-				require.NoError(t, err)
-				assert.Equal(t, "invoice_id is required", response["error"])
+				response := decodeError(t, body)
+				assert.Equal(t, "invoice_id is required", response.Message)
+				assert.Equal(t, handlers.ErrCodeValidation, response.Code)
 			},
 		},
 		{
@@ -377,10 +633,53 @@ func TestInventoryHandler_CreateInventory(t *testing.T) {
 			setupMocks:     func(m *mocks.MockInventoryService) {},
 			expectedStatus: http.StatusBadRequest,
 			validateBody: func(t *testing.T, body []byte) {
-				var response map[string]string
-				err := json.Unmarshal(body, &response)
-				require.NoError(t, err)
-				assert.Equal(t, "bid_amount cannot be negative", response["error"])
+				response := decodeError(t, body)
+				assert.Equal(t, "bid_amount cannot be negative", response.Message)
+			},
+		},
+		{
+			name: "unknown_category",
+			requestBody: handlers.CreateInventoryRequest{
+				InvoiceID: "INV-001",
+				ItemName:  "Test Item",
+				Category:  "banana",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Contains(t, response.Message, `unknown category "banana"`)
+				assert.Contains(t, response.Message, "antiques, art, books")
+			},
+		},
+		{
+			name: "unknown_condition",
+			requestBody: handlers.CreateInventoryRequest{
+				InvoiceID: "INV-001",
+				ItemName:  "Test Item",
+				Category:  "furniture",
+				Condition: "like_new",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Contains(t, response.Message, `unknown condition "like_new"`)
+				assert.Contains(t, response.Message, "mint, excellent, very_good")
+			},
+		},
+		{
+			name: "unknown_market_demand",
+			requestBody: handlers.CreateInventoryRequest{
+				InvoiceID:    "INV-001",
+				ItemName:     "Test Item",
+				MarketDemand: "extreme",
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, `unknown market_demand "extreme"; must be one of: very_high, high, medium, low, very_low`, response.Message)
 			},
 		},
 		{
@@ -407,7 +706,7 @@ func TestInventoryHandler_CreateInventory(t *testing.T) {
 
 			mockService := mocks.NewMockInventoryService(ctrl)
 			logger := helpers.TestLogger()
-			handler := handlers.NewInventoryHandler(mockService, logger)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, logger)
 
 			// Setup mocks
 			tt.setupMocks(mockService)
@@ -432,37 +731,332 @@ func TestInventoryHandler_CreateInventory(t *testing.T) {
 	}
 }
 
-func TestInventoryHandler_UpdateInventory(t *testing.T) {
-	testLotID := uuid.New()
+func TestInventoryHandler_CreateInventory_IdempotencyKey(t *testing.T) {
+	body, err := json.Marshal(handlers.CreateInventoryRequest{
+		InvoiceID: "INV-001",
+		ItemName:  "Victorian Tea Set",
+		Quantity:  1,
+		BidAmount: decimal.NewFromFloat(150.00),
+	})
+	require.NoError(t, err)
+
+	create := func(handler *handlers.InventoryHandler, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		handler.CreateInventory(w, req)
+		return w
+	}
+	decodeItem := func(t *testing.T, w *httptest.ResponseRecorder) domain.InventoryItem {
+		var item domain.InventoryItem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+		return item
+	}
+	newHandler := func(t *testing.T) (*handlers.InventoryHandler, *mocks.MockInventoryService, *helpers.TestRedis) {
+		ctrl := gomock.NewController(t)
+		mockService := mocks.NewMockInventoryService(ctrl)
+		testRedis := helpers.SetupTestRedis(t)
+		cache := redis_a.NewCache(testRedis.Client, time.Minute, helpers.TestLogger())
+		return handlers.NewInventoryHandler(mockService, cache, time.Hour, 0, helpers.TestLogger()), mockService, testRedis
+	}
+
+	t.Run("first_call_creates_and_repeat_returns_same_item", func(t *testing.T) {
+		handler, mockService, _ := newHandler(t)
+
+		var saved *domain.InventoryItem
+		mockService.EXPECT().
+			SaveItem(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, item *domain.InventoryItem) error {
+				saved = item
+				return nil
+			}).
+			Times(1)
+
+		first := create(handler, "retry-123")
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+		created := decodeItem(t, first)
+		assert.Equal(t, saved.LotID, created.LotID)
+
+		mockService.EXPECT().GetByID(gomock.Any(), saved.LotID).Return(saved, nil).Times(2)
+
+		for i := 0; i < 2; i++ {
+			repeat := create(handler, "retry-123")
+			require.Equal(t, http.StatusCreated, repeat.Code)
+			assert.Equal(t, "true", repeat.Header().Get("Idempotent-Replayed"))
+			assert.Equal(t, created.LotID, decodeItem(t, repeat).LotID)
+		}
+	})
+
+	t.Run("different_keys_create_separate_items", func(t *testing.T) {
+		handler, mockService, _ := newHandler(t)
+		mockService.EXPECT().SaveItem(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+		first := decodeItem(t, create(handler, "key-a"))
+		second := decodeItem(t, create(handler, "key-b"))
+		unkeyed := decodeItem(t, create(handler, ""))
+		assert.NotEqual(t, first.LotID, second.LotID)
+		assert.NotEqual(t, second.LotID, unkeyed.LotID)
+	})
+
+	t.Run("failed_save_releases_key", func(t *testing.T) {
+		handler, mockService, _ := newHandler(t)
+		gomock.InOrder(
+			mockService.EXPECT().SaveItem(gomock.Any(), gomock.Any()).Return(errors.New("database error")),
+			mockService.EXPECT().SaveItem(gomock.Any(), gomock.Any()).Return(nil),
+		)
+
+		assert.Equal(t, http.StatusInternalServerError, create(handler, "retry-123").Code)
+
+		retry := create(handler, "retry-123")
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Empty(t, retry.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("repeat_while_first_is_saving_conflicts", func(t *testing.T) {
+		handler, mockService, _ := newHandler(t)
+
+		var concurrent *httptest.ResponseRecorder
+		mockService.EXPECT().
+			SaveItem(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, item *domain.InventoryItem) error {
+				concurrent = create(handler, "retry-123")
+				return nil
+			})
+		mockService.EXPECT().
+			GetByID(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, uuid.New()))
+
+		assert.Equal(t, http.StatusCreated, create(handler, "retry-123").Code)
+		require.NotNil(t, concurrent)
+		assert.Equal(t, http.StatusConflict, concurrent.Code)
+	})
+
+	t.Run("key_expires_after_ttl", func(t *testing.T) {
+		handler, mockService, testRedis := newHandler(t)
+		mockService.EXPECT().SaveItem(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		first := decodeItem(t, create(handler, "retry-123"))
+		testRedis.Server.FastForward(time.Hour + time.Second)
+
+		again := create(handler, "retry-123")
+		assert.Equal(t, http.StatusCreated, again.Code)
+		assert.Empty(t, again.Header().Get("Idempotent-Replayed"))
+		assert.NotEqual(t, first.LotID, decodeItem(t, again).LotID)
+	})
+
+	t.Run("key_too_long", func(t *testing.T) {
+		handler, _, _ := newHandler(t)
+
+		w := create(handler, strings.Repeat("k", 256))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Idempotency-Key must be at most 255 characters")
+	})
+}
+
+func TestInventoryHandler_CreateInventory_RefreshesExports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testRedis := helpers.SetupTestRedis(t)
+	cache := redis_a.NewCache(testRedis.Client, time.Minute, helpers.TestLogger())
+	mockService := mocks.NewMockInventoryService(ctrl)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	inventoryHandler := handlers.NewInventoryHandler(mockService, cache, time.Hour, 0, helpers.TestLogger())
+	exportHandler := handlers.NewExportHandler(mockService, mockDB, cache, helpers.TestLogger(), 0)
+
+	existing := handlers.ExcelExportRow{InvoiceID: "INV-001", ItemName: "Victorian Tea Set"}
+	added := handlers.ExcelExportRow{InvoiceID: "INV-002", ItemName: "Brass Candlesticks"}
+	gomock.InOrder(
+		mockDB.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(newMockRows(existing), nil),
+		mockDB.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(newMockRows(existing, added), nil),
+	)
+	mockService.EXPECT().SaveItem(gomock.Any(), gomock.Any()).Return(nil)
+
+	export := func() (*httptest.ResponseRecorder, handlers.JSONExportResponse) {
+		w := httptest.NewRecorder()
+		exportHandler.ExportJSON(w, httptest.NewRequest(http.MethodGet, "/api/v1/export/json", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.JSONExportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	w, response := export()
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 1, response.Metadata.TotalItems)
+
+	keys, err := testRedis.Client.Keys(context.Background(), "export:*").Result()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	w, _ = export()
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+
+	body, err := json.Marshal(handlers.CreateInventoryRequest{
+		InvoiceID: "INV-002",
+		ItemName:  "Brass Candlesticks",
+		Quantity:  1,
+		BidAmount: decimal.NewFromFloat(40.00),
+	})
+	require.NoError(t, err)
+	created := httptest.NewRecorder()
+	inventoryHandler.CreateInventory(created, httptest.NewRequest(http.MethodPost, "/api/v1/inventory", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, created.Code)
+
+	w, response = export()
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 2, response.Metadata.TotalItems)
+}
+
+func TestInventoryHandler_WritesSurviveInvalidationFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockInventoryService(ctrl)
+	mockCache := mocks.NewMockCacheRepository(ctrl)
+	handler := handlers.NewInventoryHandler(mockService, mockCache, time.Hour, 0, helpers.TestLogger())
+
+	lotID := uuid.New()
+	mockService.EXPECT().DeleteItem(gomock.Any(), lotID, false).Return(nil)
+	mockCache.EXPECT().
+		DeletePattern(gomock.Any(), gomock.Any()).
+		Return(errors.New("redis unavailable")).
+		MinTimes(1)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/inventory/"+lotID.String(), nil)
+	req.SetPathValue("id", lotID.String())
+	w := httptest.NewRecorder()
+
+	handler.DeleteInventory(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestInventoryHandler_ItemBodyDecoding(t *testing.T) {
+	lotID := uuid.New()
+	validBody := `{"invoice_id":"INV-001","item_name":"Tea Set","quantity":1,"bid_amount":"10"}`
 
 	tests := []struct {
 		name           string
-		lotID          string
-		requestBody    interface{}
-		setupMocks     func(*mocks.MockInventoryService)
+		method         string
+		body           string
 		expectedStatus int
-		validateBody   func(*testing.T, []byte)
+		expectedError  string
 	}{
 		{
-			name:  "successfully_updates_inventory_item",
-			lotID: testLotID.String(),
-			requestBody: handlers.UpdateInventoryRequest{
-				InvoiceID: "INV-002",
-				ItemName:  "Updated Tea Set",
-				BidAmount: decimal.NewFromFloat(200.00),
-				Quantity:  1,
-			},
-			setupMocks: func(m *mocks.MockInventoryService) {
-				m.EXPECT().
-					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
-					Return(nil)
-				m.EXPECT().
-					GetByID(gomock.Any(), testLotID).
-					Return(helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
-						i.LotID = testLotID
-						i.ItemName = "Updated Tea Set"
-					}), nil)
-			},
+			name:           "create_oversized_body",
+			method:         http.MethodPost,
+			body:           `{"invoice_id":"INV-001","item_name":"Tea Set","quantity":1,"bid_amount":"10","notes":"` + strings.Repeat("x", 256) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "Request body must be at most 256 bytes",
+		},
+		{
+			name:           "create_unknown_field",
+			method:         http.MethodPost,
+			body:           `{"invoice_id":"INV-001","item_nmae":"Tea Set","quantity":1,"bid_amount":"10"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `Invalid request body: unknown field "item_nmae"`,
+		},
+		{
+			name:           "update_oversized_body",
+			method:         http.MethodPut,
+			body:           strings.Replace(validBody, `"quantity"`, `"notes":"`+strings.Repeat("x", 256)+`","quantity"`, 1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "Request body must be at most 256 bytes",
+		},
+		{
+			name:           "update_unknown_field",
+			method:         http.MethodPut,
+			body:           strings.Replace(validBody, `"quantity"`, `"shiping_cost":"5","quantity"`, 1),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `Invalid request body: unknown field "shiping_cost"`,
+		},
+		{
+			name:           "malformed_json",
+			method:         http.MethodPut,
+			body:           `{"invoice_id":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// The service must not be reached for a rejected body
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 256, helpers.TestLogger())
+
+			req := httptest.NewRequest(tt.method, "/api/v1/inventory/"+lotID.String(), strings.NewReader(tt.body))
+			req.SetPathValue("id", lotID.String())
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			if tt.method == http.MethodPost {
+				handler.CreateInventory(w, req)
+			} else {
+				handler.UpdateInventory(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			response := decodeError(t, w.Body.Bytes())
+			assert.Equal(t, tt.expectedError, response.Message)
+			if tt.expectedStatus == http.StatusRequestEntityTooLarge {
+				assert.Equal(t, handlers.ErrCodePayloadTooLarge, response.Code)
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_UpdateInventory(t *testing.T) {
+	testLotID := uuid.New()
+	premium := decimal.NewFromFloat(30.00)
+	tax := decimal.NewFromFloat(10.40)
+	shipping := decimal.NewFromFloat(15.00)
+	stored := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+		i.LotID = testLotID
+		i.BuyersPremium = decimal.NewFromFloat(18.00)
+		i.SalesTax = decimal.NewFromFloat(9.44)
+		i.ShippingCost = decimal.NewFromFloat(25.00)
+	})
+
+	tests := []struct {
+		name           string
+		lotID          string
+		requestBody    interface{}
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "successfully_updates_inventory_item",
+			lotID: testLotID.String(),
+			requestBody: handlers.UpdateInventoryRequest{
+				InvoiceID: "INV-002",
+				ItemName:  "Updated Tea Set",
+				BidAmount: decimal.NewFromFloat(200.00),
+				Quantity:  1,
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(stored, nil)
+				m.EXPECT().
+					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
+					Return(nil)
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+						i.LotID = testLotID
+						i.ItemName = "Updated Tea Set"
+					}), nil)
+			},
 			expectedStatus: http.StatusOK,
 			validateBody: func(t *testing.T, body []byte) {
 				var response domain.InventoryItem
@@ -471,6 +1065,61 @@ func TestInventoryHandler_UpdateInventory(t *testing.T) {
 				assert.Equal(t, "Updated Tea Set", response.ItemName)
 			},
 		},
+		{
+			name:  "omitted_costs_keep_stored_values",
+			lotID: testLotID.String(),
+			requestBody: map[string]interface{}{
+				"invoice_id": stored.InvoiceID,
+				"item_name":  "Renamed Tea Set",
+				"quantity":   stored.Quantity,
+				"bid_amount": stored.BidAmount,
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(stored, nil)
+				m.EXPECT().
+					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, item *domain.InventoryItem) error {
+						assert.Equal(t, "Renamed Tea Set", item.ItemName)
+						assert.True(t, stored.BuyersPremium.Equal(item.BuyersPremium), "buyers_premium %s", item.BuyersPremium)
+						assert.True(t, stored.SalesTax.Equal(item.SalesTax), "sales_tax %s", item.SalesTax)
+						assert.True(t, stored.ShippingCost.Equal(item.ShippingCost), "shipping_cost %s", item.ShippingCost)
+						return nil
+					})
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(stored, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "provided_costs_override_stored_values",
+			lotID: testLotID.String(),
+			requestBody: map[string]interface{}{
+				"invoice_id":     stored.InvoiceID,
+				"item_name":      stored.ItemName,
+				"quantity":       stored.Quantity,
+				"bid_amount":     stored.BidAmount,
+				"buyers_premium": "0",
+				"sales_tax":      tax,
+				"shipping_cost":  shipping,
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, item *domain.InventoryItem) error {
+						assert.True(t, item.BuyersPremium.IsZero(), "an explicit zero is kept")
+						assert.True(t, tax.Equal(item.SalesTax))
+						assert.True(t, shipping.Equal(item.ShippingCost))
+						return nil
+					})
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(stored, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:           "invalid_uuid",
 			lotID:          "not-a-uuid",
@@ -490,6 +1139,51 @@ func TestInventoryHandler_UpdateInventory(t *testing.T) {
 			setupMocks:     func(m *mocks.MockInventoryService) {},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:  "unknown_category",
+			lotID: testLotID.String(),
+			requestBody: handlers.UpdateInventoryRequest{
+				InvoiceID: "INV-002",
+				ItemName:  "Test",
+				Category:  "banana",
+				BidAmount: decimal.NewFromFloat(100.00),
+				Quantity:  1,
+			},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Contains(t, response.Message, `unknown category "banana"; must be one of: antiques,`)
+			},
+		},
+		{
+			name:  "known_category_and_condition",
+			lotID: testLotID.String(),
+			requestBody: handlers.UpdateInventoryRequest{
+				InvoiceID: "INV-002",
+				ItemName:  "Test",
+				Category:  "furniture",
+				Condition: "very_good",
+				BidAmount: decimal.NewFromFloat(100.00),
+				Quantity:  1,
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(stored, nil)
+				m.EXPECT().
+					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, item *domain.InventoryItem) error {
+						assert.Equal(t, domain.CategoryFurniture, item.Category)
+						assert.Equal(t, domain.ConditionVeryGood, item.Condition)
+						return nil
+					})
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) { i.LotID = testLotID }), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:  "item_not_found",
 			lotID: testLotID.String(),
@@ -499,13 +1193,55 @@ func TestInventoryHandler_UpdateInventory(t *testing.T) {
 				BidAmount: decimal.NewFromFloat(100.00),
 				Quantity:  1,
 			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetByID(gomock.Any(), testLotID).
+					Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, testLotID))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "item_deleted_before_update",
+			lotID: testLotID.String(),
+			requestBody: handlers.UpdateInventoryRequest{
+				InvoiceID:     "INV-002",
+				ItemName:      "Test",
+				BidAmount:     decimal.NewFromFloat(100.00),
+				BuyersPremium: &premium,
+				SalesTax:      &tax,
+				ShippingCost:  &shipping,
+				Quantity:      1,
+			},
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
-					Return(fmt.Errorf("inventory item not found: %s", testLotID))
+					Return(fmt.Errorf("failed to update item: %w", fmt.Errorf("%w: %s", domain.ErrNotFound, testLotID)))
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:  "stale_version_conflict",
+			lotID: testLotID.String(),
+			requestBody: handlers.UpdateInventoryRequest{
+				InvoiceID:     "INV-002",
+				ItemName:      "Test",
+				BidAmount:     decimal.NewFromFloat(100.00),
+				BuyersPremium: &premium,
+				SalesTax:      &tax,
+				ShippingCost:  &shipping,
+				Quantity:      1,
+				Version:       3,
+			},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					UpdateItem(gomock.Any(), testLotID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, item *domain.InventoryItem) error {
+						assert.Equal(t, 3, item.Version)
+						return fmt.Errorf("%w: %s expected version 3", domain.ErrVersionConflict, testLotID)
+					})
+			},
+			expectedStatus: http.StatusConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -516,7 +1252,7 @@ func TestInventoryHandler_UpdateInventory(t *testing.T) {
 
 			mockService := mocks.NewMockInventoryService(ctrl)
 			logger := helpers.TestLogger()
-			handler := handlers.NewInventoryHandler(mockService, logger)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, logger)
 
 			// Setup mocks
 			tt.setupMocks(mockService)
@@ -542,6 +1278,142 @@ func TestInventoryHandler_UpdateInventory(t *testing.T) {
 	}
 }
 
+func TestInventoryHandler_PatchInventory(t *testing.T) {
+	testLotID := uuid.New()
+
+	tests := []struct {
+		name           string
+		lotID          string
+		body           string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "absent_fields_untouched_and_null_clears",
+			lotID: testLotID.String(),
+			body:  `{"storage_location":"Garage","notes":null,"version":2}`,
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					PatchItem(gomock.Any(), testLotID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, patch ports.InventoryPatch) (*domain.InventoryItem, error) {
+						require.True(t, patch.StorageLocation.Set)
+						assert.Equal(t, "Garage", *patch.StorageLocation.Value)
+						assert.True(t, patch.Notes.IsNull())
+						assert.False(t, patch.StorageBin.Set)
+						assert.False(t, patch.ItemName.Set)
+						assert.Equal(t, 2, patch.Version)
+						return helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+							i.LotID = testLotID
+							i.StorageLocation = "Garage"
+						}), nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response domain.InventoryItem
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, "Garage", response.StorageLocation)
+			},
+		},
+		{
+			name:           "invalid_uuid",
+			lotID:          "not-a-uuid",
+			body:           `{"notes":"x"}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown_field",
+			lotID:          testLotID.String(),
+			body:           `{"storage_locaton":"Garage"}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty_patch",
+			lotID:          testLotID.String(),
+			body:           `{}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "required_field_null",
+			lotID:          testLotID.String(),
+			body:           `{"item_name":null}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				assert.Contains(t, string(body), "item_name cannot be null")
+			},
+		},
+		{
+			name:           "unknown_category",
+			lotID:          testLotID.String(),
+			body:           `{"category":"banana"}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "item_not_found",
+			lotID: testLotID.String(),
+			body:  `{"notes":"x"}`,
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					PatchItem(gomock.Any(), testLotID, gomock.Any()).
+					Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, testLotID))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "stale_version_conflict",
+			lotID: testLotID.String(),
+			body:  `{"notes":"x","version":1}`,
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					PatchItem(gomock.Any(), testLotID, gomock.Any()).
+					Return(nil, fmt.Errorf("failed to patch item: %w", domain.ErrVersionConflict))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:  "service_validation_error",
+			lotID: testLotID.String(),
+			body:  `{"quantity":0}`,
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					PatchItem(gomock.Any(), testLotID, gomock.Any()).
+					Return(nil, errors.New("validation failed: quantity must be positive"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/inventory/"+tt.lotID, strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.lotID)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.PatchInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
 func TestInventoryHandler_DeleteInventory(t *testing.T) {
 	testLotID := uuid.New()
 
@@ -602,7 +1474,7 @@ func TestInventoryHandler_DeleteInventory(t *testing.T) {
 			setupMocks: func(m *mocks.MockInventoryService) {
 				m.EXPECT().
 					DeleteItem(gomock.Any(), testLotID, false).
-					Return(fmt.Errorf("inventory item not found: %s", testLotID))
+					Return(fmt.Errorf("%w: %s", domain.ErrNotFound, testLotID))
 			},
 			expectedStatus: http.StatusNotFound,
 		},
@@ -616,7 +1488,7 @@ func TestInventoryHandler_DeleteInventory(t *testing.T) {
 
 			mockService := mocks.NewMockInventoryService(ctrl)
 			logger := helpers.TestLogger()
-			handler := handlers.NewInventoryHandler(mockService, logger)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, logger)
 
 			// Setup mocks
 			tt.setupMocks(mockService)
@@ -643,3 +1515,647 @@ func TestInventoryHandler_DeleteInventory(t *testing.T) {
 		})
 	}
 }
+
+func TestInventoryHandler_BulkUpdateInventory(t *testing.T) {
+	lotA, lotB := uuid.New(), uuid.New()
+	location := "Warehouse B"
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name: "updates_whitelisted_fields",
+			body: fmt.Sprintf(`{"lot_ids":["%s","%s"],"updates":{"storage_location":"Warehouse B","needs_repair":true}}`, lotA, lotB),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					BulkUpdateFields(gomock.Any(), []uuid.UUID{lotA, lotB}, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, lotIDs []uuid.UUID, updates ports.BulkFieldUpdates) (int64, error) {
+						require.NotNil(t, updates.StorageLocation)
+						assert.Equal(t, location, *updates.StorageLocation)
+						require.NotNil(t, updates.NeedsRepair)
+						assert.True(t, *updates.NeedsRepair)
+						assert.Nil(t, updates.StorageBin)
+						assert.Nil(t, updates.MarketDemand)
+						return 2, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, float64(2), response["rows_affected"])
+			},
+		},
+		{
+			name:           "rejects_non_whitelisted_field",
+			body:           fmt.Sprintf(`{"lot_ids":["%s"],"updates":{"bid_amount":"10.00"}}`, lotA),
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				response := decodeError(t, body)
+				assert.Equal(t, `field "bid_amount" cannot be bulk updated`, response.Message)
+			},
+		},
+		{
+			name:           "rejects_unknown_market_demand",
+			body:           fmt.Sprintf(`{"lot_ids":["%s"],"updates":{"market_demand":"extreme"}}`, lotA),
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rejects_wrong_value_type",
+			body:           fmt.Sprintf(`{"lot_ids":["%s"],"updates":{"needs_repair":"yes"}}`, lotA),
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "requires_lot_ids",
+			body:           `{"lot_ids":[],"updates":{"storage_bin":"A1"}}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rejects_invalid_lot_id",
+			body:           `{"lot_ids":["not-a-uuid"],"updates":{"storage_bin":"A1"}}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service_error",
+			body: fmt.Sprintf(`{"lot_ids":["%s"],"updates":{"storage_bin":"A1"}}`, lotA),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					BulkUpdateFields(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/inventory/bulk", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.BulkUpdateInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_ReclassifyInventory(t *testing.T) {
+	lotID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "dry_run_returns_proposed_changes",
+			query: "?category=antiques",
+			body:  `{"dry_run":true,"min_confidence":0.7}`,
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					Reclassify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ReclassifyParams) (*ports.ReclassifyResult, error) {
+						assert.True(t, params.DryRun)
+						assert.Equal(t, 0.7, params.MinConfidence)
+						assert.Equal(t, "antiques", params.Filter.Category)
+						return &ports.ReclassifyResult{
+							DryRun:   true,
+							Examined: 1,
+							Changes: []ports.ReclassifyChange{{
+								LotID:       lotID,
+								OldCategory: domain.CategoryAntiques,
+								NewCategory: domain.CategorySilver,
+								Confidence:  0.9,
+							}},
+						}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response ports.ReclassifyResult
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.True(t, response.DryRun)
+				assert.Zero(t, response.Updated)
+				require.Len(t, response.Changes, 1)
+				assert.Equal(t, lotID, response.Changes[0].LotID)
+				assert.Equal(t, domain.CategorySilver, response.Changes[0].NewCategory)
+				assert.False(t, response.Changes[0].Applied)
+			},
+		},
+		{
+			name: "applies_with_default_confidence_without_body",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					Reclassify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ReclassifyParams) (*ports.ReclassifyResult, error) {
+						assert.False(t, params.DryRun)
+						assert.Equal(t, 0.5, params.MinConfidence)
+						return &ports.ReclassifyResult{
+							Examined: 1,
+							Updated:  1,
+							Changes:  []ports.ReclassifyChange{{LotID: lotID, Applied: true}},
+						}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response ports.ReclassifyResult
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.False(t, response.DryRun)
+				assert.Equal(t, 1, response.Updated)
+				require.Len(t, response.Changes, 1)
+				assert.True(t, response.Changes[0].Applied)
+			},
+		},
+		{
+			name:           "rejects_out_of_range_confidence",
+			body:           `{"min_confidence":1.5}`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rejects_malformed_body",
+			body:           `{"dry_run":`,
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service_error",
+			body: `{"dry_run":false}`,
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					Reclassify(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/reclassify"+tt.query, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.ReclassifyInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_RestoreInventory(t *testing.T) {
+	testLotID := uuid.New()
+
+	tests := []struct {
+		name           string
+		lotID          string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "restores_deleted_item",
+			lotID: testLotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					RestoreItem(gomock.Any(), testLotID).
+					Return(&domain.InventoryItem{LotID: testLotID, ItemName: "Restored Item"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response domain.InventoryItem
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, testLotID, response.LotID)
+			},
+		},
+		{
+			name:  "item_not_deleted",
+			lotID: testLotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					RestoreItem(gomock.Any(), testLotID).
+					Return(nil, fmt.Errorf("failed to restore item: %w: %s", domain.ErrNotFound, testLotID))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid_uuid",
+			lotID:          "not-a-uuid",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service_error",
+			lotID: testLotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					RestoreItem(gomock.Any(), testLotID).
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/"+tt.lotID+"/restore", nil)
+			req.SetPathValue("id", tt.lotID)
+			w := httptest.NewRecorder()
+
+			handler.RestoreInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_GetInventoryHistory(t *testing.T) {
+	testLotID := uuid.New()
+
+	tests := []struct {
+		name           string
+		lotID          string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "returns_history",
+			lotID: testLotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetHistory(gomock.Any(), testLotID).
+					Return([]domain.AuditEntry{{
+						ID:        1,
+						LotID:     testLotID,
+						Operation: domain.AuditUpdate,
+						Changes:   map[string]domain.FieldChange{"storage_bin": {Old: "A1", New: "B2"}},
+						UserID:    "user-1",
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response struct {
+					LotID   string              `json:"lot_id"`
+					History []domain.AuditEntry `json:"history"`
+				}
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, testLotID.String(), response.LotID)
+				require.Len(t, response.History, 1)
+				assert.Equal(t, domain.AuditUpdate, response.History[0].Operation)
+				assert.Equal(t, "B2", response.History[0].Changes["storage_bin"].New)
+			},
+		},
+		{
+			name:  "item_not_found",
+			lotID: testLotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetHistory(gomock.Any(), testLotID).
+					Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, testLotID))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid_uuid",
+			lotID:          "not-a-uuid",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service_error",
+			lotID: testLotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetHistory(gomock.Any(), testLotID).
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/"+tt.lotID+"/history", nil)
+			req.SetPathValue("id", tt.lotID)
+			w := httptest.NewRecorder()
+
+			handler.GetInventoryHistory(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_GetInvoiceItems(t *testing.T) {
+	item := helpers.CreateTestInventoryItem()
+
+	tests := []struct {
+		name           string
+		invoiceID      string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:      "returns_items_with_aggregates",
+			invoiceID: "INV-001",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetInvoiceItems(gomock.Any(), "INV-001").
+					Return(&ports.InvoiceItems{
+						InvoiceID:  "INV-001",
+						Items:      []domain.InventoryItem{*item},
+						ItemCount:  1,
+						TotalCost:  decimal.NewFromFloat(202.31),
+						AverageBid: decimal.NewFromFloat(150),
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response ports.InvoiceItems
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, "INV-001", response.InvoiceID)
+				require.Len(t, response.Items, 1)
+				assert.Equal(t, item.LotID, response.Items[0].LotID)
+				assert.Equal(t, 1, response.ItemCount)
+				assert.True(t, decimal.NewFromFloat(202.31).Equal(response.TotalCost))
+				assert.True(t, decimal.NewFromFloat(150).Equal(response.AverageBid))
+			},
+		},
+		{
+			name:      "unknown_invoice_is_an_empty_list",
+			invoiceID: "INV-404",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetInvoiceItems(gomock.Any(), "INV-404").
+					Return(&ports.InvoiceItems{InvoiceID: "INV-404", Items: []domain.InventoryItem{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, []interface{}{}, response["items"])
+				assert.Equal(t, float64(0), response["item_count"])
+			},
+		},
+		{
+			name:           "blank_invoice_id",
+			invoiceID:      " ",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "service_error",
+			invoiceID: "INV-001",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetInvoiceItems(gomock.Any(), "INV-001").
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/invoices/x/items", nil)
+			req.SetPathValue("invoiceId", tt.invoiceID)
+			w := httptest.NewRecorder()
+
+			handler.GetInvoiceItems(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_ListInvoices(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "defaults_to_newest_first",
+			query: "",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					ListInvoices(gomock.Any(), ports.InvoiceListParams{
+						Page: 1, PageSize: 50, SortBy: ports.InvoiceSortDate, SortOrder: "desc",
+					}).
+					Return(&ports.InvoiceListResult{
+						Invoices: []ports.InvoiceSummary{
+							{InvoiceID: "INV-001", AuctionID: 12345, ItemCount: 3, TotalCost: decimal.NewFromFloat(606.93)},
+						},
+						Page: 1, PageSize: 50, TotalCount: 1, TotalPages: 1,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response ports.InvoiceListResult
+				require.NoError(t, json.Unmarshal(body, &response))
+				require.Len(t, response.Invoices, 1)
+				assert.Equal(t, "INV-001", response.Invoices[0].InvoiceID)
+				assert.Equal(t, 12345, response.Invoices[0].AuctionID)
+				assert.Equal(t, 3, response.Invoices[0].ItemCount)
+				assert.True(t, decimal.NewFromFloat(606.93).Equal(response.Invoices[0].TotalCost))
+				assert.Equal(t, int64(1), response.TotalCount)
+			},
+		},
+		{
+			name:  "sorted_by_total_and_paged",
+			query: "?sort=total&order=asc&page=3&limit=10",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					ListInvoices(gomock.Any(), ports.InvoiceListParams{
+						Page: 3, PageSize: 10, SortBy: ports.InvoiceSortTotal, SortOrder: "asc",
+					}).
+					Return(&ports.InvoiceListResult{Invoices: []ports.InvoiceSummary{}, Page: 3, PageSize: 10}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid_sort",
+			query:          "?sort=auction",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid_order",
+			query:          "?order=up",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service_error",
+			query: "",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					ListInvoices(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/invoices"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ListInvoices(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestInventoryHandler_StorageSummary(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name: "success",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().StorageSummary(gomock.Any()).Return(&ports.StorageSummary{
+					Locations: []ports.StorageLocationSummary{
+						{
+							Location:       ports.UnassignedStorage,
+							ItemCount:      4,
+							EstimatedValue: decimal.NewFromFloat(22.5),
+							Bins: []ports.StorageBinSummary{
+								{Location: ports.UnassignedStorage, Bin: ports.UnassignedStorage, ItemCount: 4, EstimatedValue: decimal.NewFromFloat(22.5)},
+							},
+						},
+					},
+					ItemCount:      4,
+					EstimatedValue: decimal.NewFromFloat(22.5),
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response ports.StorageSummary
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, 4, response.ItemCount)
+				require.Len(t, response.Locations, 1)
+				assert.Equal(t, "unassigned", response.Locations[0].Location)
+				require.Len(t, response.Locations[0].Bins, 1)
+				assert.Equal(t, "unassigned", response.Locations[0].Bins[0].Bin)
+				assert.True(t, decimal.NewFromFloat(22.5).Equal(response.Locations[0].Bins[0].EstimatedValue))
+			},
+		},
+		{
+			name: "service_error",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().StorageSummary(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewInventoryHandler(mockService, newInvalidatingCache(ctrl), time.Hour, 0, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/storage/summary", nil)
+			w := httptest.NewRecorder()
+
+			handler.StorageSummary(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// internal/handlers/middleware/compression.go
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressionMinSize is the smallest body worth gzipping; below it the framing costs more than it saves
+const compressionMinSize = 1024
+
+// incompressibleTypes are content types that are already compressed. XLSX is a zip archive.
+var incompressibleTypes = []string{
+	"image/", "video/", "audio/",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/pdf",
+	"application/vnd.openxmlformats-officedocument.",
+}
+
+// Compression middleware gzips responses for clients that accept it. The first
+// compressionMinSize bytes are buffered so small bodies and already-compressed content
+// types go out untouched; a Content-Length set by the handler is dropped once the body is
+// gzipped since it no longer matches.
+func Compression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		// Check if client accepts gzip
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gz := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		defer gz.Close()
+
+		next.ServeHTTP(gz, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it can decide whether to compress
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	buf         []byte
+	status      int
+	wroteHeader bool // WriteHeader was called by the handler
+	decided     bool // headers have been sent downstream
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader || w.decided {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+
+	// Informational responses aren't the final header, pass them straight through
+	if status >= 100 && status < 200 {
+		w.wroteHeader = false
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.writer != nil {
+			return w.writer.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= compressionMinSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// start sends the headers, compressing when large is set and the response allows it,
+// then writes out whatever has been buffered
+func (w *gzipResponseWriter) start(large bool) error {
+	w.decided = true
+
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// Sniff the plain bytes; net/http would otherwise sniff the gzipped ones
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if large && w.compressible() {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		w.writer = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if w.writer != nil {
+		_, err := w.writer.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// compressible reports whether the response may be gzipped
+func (w *gzipResponseWriter) compressible() bool {
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if cl, err := strconv.Atoi(h.Get("Content-Length")); err == nil && cl < compressionMinSize {
+		return false
+	}
+
+	contentType := strings.ToLower(h.Get("Content-Type"))
+	for _, t := range incompressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Close flushes a still-buffered small response as is, or finishes the gzip stream
+func (w *gzipResponseWriter) Close() error {
+	if !w.decided {
+		if !w.wroteHeader && len(w.buf) == 0 {
+			// The handler wrote nothing; let net/http send its implicit 200
+			return nil
+		}
+		return w.start(false)
+	}
+	if w.writer != nil {
+		return w.writer.Close()
+	}
+	return nil
+}
+
+// Flush implements http.Flusher. A flush before the size threshold commits to compressing
+// so a streaming response isn't held back.
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		w.start(true)
+	}
+	if w.writer != nil {
+		w.writer.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, fmt.Errorf("ResponseWriter does not implement Hijacker")
+}
+
+// Push implements http.Pusher
+func (w *gzipResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return fmt.Errorf("ResponseWriter does not implement Pusher")
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
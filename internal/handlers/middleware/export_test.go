@@ -0,0 +1,11 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+)
+
+// GetClientIP exposes client IP resolution to external tests
+func GetClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	return getClientIP(r, trustedProxies)
+}
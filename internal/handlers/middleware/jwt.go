@@ -0,0 +1,67 @@
+// internal/handlers/middleware/jwt.go
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ammerola/resell-be/internal/pkg/auth"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// claimsContextKey is the context key Auth stores verified claims under
+type claimsContextKey struct{}
+
+// ClaimsFromContext returns the claims Auth verified for the request
+func ClaimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*auth.Claims)
+	return claims, ok
+}
+
+// Auth middleware requires a valid bearer token signed with secret. Missing, tampered and
+// expired tokens are rejected with 401; otherwise the claims and user ID are added to the context.
+func Auth(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				unauthorized(w, "Missing bearer token")
+				return
+			}
+
+			claims, err := parseAccessToken(strings.TrimPrefix(header, "Bearer "), secret)
+			if err != nil {
+				unauthorized(w, "Invalid or expired token")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsContextKey{}, claims)
+			ctx = logger.WithUserID(ctx, claims.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseAccessToken verifies a bearer token. Refresh tokens are rejected so they can only
+// be exchanged at the refresh endpoint.
+func parseAccessToken(token, secret string) (*auth.Claims, error) {
+	claims, err := auth.Parse(token, secret, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type == auth.TokenRefresh {
+		return nil, fmt.Errorf("%w: refresh token used as access token", auth.ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// unauthorized writes a 401 with a bearer challenge
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="resell-api"`)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"error":"` + message + `"}`))
+}
@@ -2,24 +2,23 @@
 package middleware
 
 import (
-	"bufio"
-	"compress/gzip"
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"runtime/debug"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/ammerola/resell-be/internal/pkg/logger"
 	"github.com/google/uuid"
-	"golang.org/x/time/rate"
 )
 
-// RequestID middleware adds a unique request ID to each request
+// RequestID middleware adds a unique request ID to each request. It belongs outermost in the
+// chain: Logger, Recovery and the error responses read the ID from the context it sets.
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if request already has an ID (from proxy/LB)
@@ -39,16 +38,16 @@ func RequestID(next http.Handler) http.Handler {
 	})
 }
 
-func Logger(l *logger.Logger) func(http.Handler) http.Handler {
+// Logger middleware enriches the request context with logging fields, including the user ID
+// from a bearer token signed with jwtSecret, and logs each request under the ID RequestID
+// stored in the context. Forwarded client IPs are only logged when the request came through
+// one of trustedProxies.
+func Logger(l *logger.Logger, jwtSecret string, trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
-			// Generate or extract request ID
-			requestID := r.Header.Get("X-Request-ID")
-			if requestID == "" {
-				requestID = uuid.New().String()
-			}
+			requestID, _ := r.Context().Value(logger.ContextKeyRequestID).(string)
 
 			// Generate trace ID for distributed tracing
 			traceID := r.Header.Get("X-Trace-ID")
@@ -57,11 +56,10 @@ func Logger(l *logger.Logger) func(http.Handler) http.Handler {
 			}
 
 			// Extract client IP
-			clientIP := getClientIP(r)
+			clientIP := getClientIP(r, trustedProxies)
 
 			// Enrich context with logging fields
 			ctx := r.Context()
-			ctx = context.WithValue(ctx, logger.ContextKeyRequestID, requestID)
 			ctx = context.WithValue(ctx, logger.ContextKeyTraceID, traceID)
 			ctx = context.WithValue(ctx, logger.ContextKeyClientIP, clientIP)
 			ctx = context.WithValue(ctx, logger.ContextKeyUserAgent, r.UserAgent())
@@ -69,8 +67,8 @@ func Logger(l *logger.Logger) func(http.Handler) http.Handler {
 			ctx = context.WithValue(ctx, logger.ContextKeyPath, r.URL.Path)
 
 			// Extract user ID from auth header or session (if available)
-			if userID := extractUserID(r); userID != "" {
-				ctx = context.WithValue(ctx, logger.ContextKeyUserID, userID)
+			if userID := extractUserID(r, jwtSecret); userID != "" {
+				ctx = logger.WithUserID(ctx, userID)
 			}
 
 			// Wrap response writer to capture status and size
@@ -79,8 +77,6 @@ func Logger(l *logger.Logger) func(http.Handler) http.Handler {
 				statusCode:     http.StatusOK,
 			}
 
-			// Set request ID in response header
-			w.Header().Set("X-Request-ID", requestID)
 			w.Header().Set("X-Trace-ID", traceID)
 
 			// Create logger with context
@@ -156,15 +152,16 @@ func Logger(l *logger.Logger) func(http.Handler) http.Handler {
 	}
 }
 
-// Helper function to extract user ID from request
-func extractUserID(r *http.Request) string {
+// extractUserID returns the subject of a valid bearer token. A missing, malformed or expired
+// token leaves the request anonymous rather than failing it; routes that need a user enforce that.
+func extractUserID(r *http.Request, jwtSecret string) string {
 	// Try JWT token first
-	if auth := r.Header.Get("Authorization"); auth != "" {
-		if strings.HasPrefix(auth, "Bearer ") {
-			// Parse JWT and extract user ID
-			// This is simplified - you'd actually validate and parse the JWT
-			return "" // Would return actual user ID
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		claims, err := parseAccessToken(strings.TrimPrefix(auth, "Bearer "), jwtSecret)
+		if err != nil {
+			return ""
 		}
+		return claims.Subject
 	}
 
 	// Try session cookie
@@ -191,10 +188,14 @@ func Recovery(slogger *slog.Logger) func(http.Handler) http.Handler {
 						slog.String("stack", string(debug.Stack())),
 					)
 
-					// Return error response
+					// Return the same error envelope as the handlers
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusInternalServerError)
-					w.Write([]byte(`{"error":"Internal Server Error","request_id":"` + requestID + `"}`))
+					json.NewEncoder(w).Encode(map[string]map[string]string{"error": {
+						"code":       "internal_error",
+						"message":    "Internal Server Error",
+						"request_id": requestID,
+					}})
 				}
 			}()
 
@@ -203,71 +204,54 @@ func Recovery(slogger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
-// RateLimit middleware implements rate limiting per IP
-func RateLimit(requestsPerMinute int, duration time.Duration) func(http.Handler) http.Handler {
-	// Store rate limiters per IP
-	limiters := &sync.Map{}
-
-	// Cleanup old limiters periodically
-	go func() {
-		ticker := time.NewTicker(10 * time.Minute)
-		for range ticker.C {
-			now := time.Now()
-			limiters.Range(func(key, value interface{}) bool {
-				limiter := value.(*rateLimiter)
-				if now.Sub(limiter.lastSeen) > 10*time.Minute {
-					limiters.Delete(key)
-				}
-				return true
-			})
-		}
-	}()
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get client IP
-			ip := getClientIP(r)
-
-			// Get or create rate limiter for this IP
-			val, _ := limiters.LoadOrStore(ip, &rateLimiter{
-				limiter:  rate.NewLimiter(rate.Every(duration/time.Duration(requestsPerMinute)), requestsPerMinute),
-				lastSeen: time.Now(),
-			})
-
-			rl := val.(*rateLimiter)
-			rl.lastSeen = time.Now()
-
-			// Check rate limit
-			if !rl.limiter.Allow() {
-				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
-				return
-			}
+// CORSConfig configures the CORS middleware
+type CORSConfig struct {
+	AllowedOrigins   []string // "*" allows any origin, but then without credentials
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool // Only sent for explicitly listed origins
+}
 
-			next.ServeHTTP(w, r)
-		})
+// CORS middleware handles Cross-Origin Resource Sharing. A listed origin is echoed back and
+// may receive credentials; a "*" entry answers every other origin with a literal "*" and no
+// credentials header, since browsers refuse credentialed responses to a wildcard.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	allowAll := false
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			allowAll = true
+			continue
+		}
+		origins[origin] = true
 	}
-}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
 
-// CORS middleware handles Cross-Origin Resource Sharing
-func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
-			// Check if origin is allowed
-			allowed := false
-			for _, allowedOrigin := range allowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					allowed = true
-					break
+			// The response differs per origin, so caches must key on it
+			w.Header().Add("Vary", "Origin")
+
+			allowed := true
+			switch {
+			case origin != "" && origins[origin]:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if cfg.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
 				}
+			case allowAll:
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			default:
+				allowed = false
 			}
 
 			if allowed {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Credentials", "true")
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-				w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Request-ID")
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
 				w.Header().Set("Access-Control-Max-Age", "86400")
 			}
 
@@ -301,48 +285,32 @@ func SecureHeaders(next http.Handler) http.Handler {
 	})
 }
 
-// Timeout middleware adds request timeout
-func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+// AdminAPIKeyHeader is the header admin clients send their API key in
+const AdminAPIKeyHeader = "X-API-Key"
+
+// AdminAuth middleware restricts a route to callers presenting the admin API key.
+// When no key is configured the routes are disabled rather than left open.
+func AdminAuth(apiKey string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx, cancel := context.WithTimeout(r.Context(), timeout)
-			defer cancel()
-
-			done := make(chan struct{})
-			go func() {
-				next.ServeHTTP(w, r.WithContext(ctx))
-				close(done)
-			}()
+			if apiKey == "" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"error":"Admin API is not configured"}`))
+				return
+			}
 
-			select {
-			case <-done:
+			provided := r.Header.Get(AdminAPIKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Unauthorized"}`))
 				return
-			case <-ctx.Done():
-				w.WriteHeader(http.StatusGatewayTimeout)
-				w.Write([]byte(`{"error":"Request timeout"}`))
 			}
-		})
-	}
-}
 
-// Compression middleware adds gzip compression
-func Compression(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check if client accepts gzip
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			next.ServeHTTP(w, r)
-			return
-		}
-
-		// Wrap response writer with gzip writer
-		gz := &gzipResponseWriter{
-			ResponseWriter: w,
-		}
-		defer gz.Close()
-
-		gz.Header().Set("Content-Encoding", "gzip")
-		next.ServeHTTP(gz, r)
-	})
+		})
+	}
 }
 
 // Helper types and functions
@@ -371,82 +339,83 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-type rateLimiter struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
-
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header
-	xff := r.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		parts := strings.Split(xff, ",")
-		return strings.TrimSpace(parts[0])
-	}
+// ParseTrustedProxies parses proxy addresses, each a CIDR or a single IP, into networks
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
 
-	// Check X-Real-IP header
-	xri := r.Header.Get("X-Real-IP")
-	if xri != "" {
-		return xri
-	}
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
 
-	// Fall back to RemoteAddr
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err == nil {
-		return host
+		_, ipNet, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+		}
+		nets = append(nets, ipNet)
 	}
-
-	return r.RemoteAddr
+	return nets, nil
 }
 
-// gzipResponseWriter implements gzip compression
-type gzipResponseWriter struct {
-	http.ResponseWriter
-	writer *gzip.Writer
-}
-
-func (w *gzipResponseWriter) Write(b []byte) (int, error) {
-	if w.writer == nil {
-		w.writer = gzip.NewWriter(w.ResponseWriter)
+// getClientIP returns the client address for r. Forwarding headers are only honored when the
+// socket peer is a trusted proxy, so a direct client can't spoof its IP. X-Forwarded-For is
+// read right to left, skipping trusted hops, and the first untrusted address is the client.
+func getClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
 	}
-	return w.writer.Write(b)
-}
 
-func (w *gzipResponseWriter) WriteHeader(status int) {
-	w.Header().Del("Content-Length")
-	w.ResponseWriter.WriteHeader(status)
-}
-
-func (w *gzipResponseWriter) Close() {
-	if w.writer != nil {
-		w.writer.Close()
+	if !isTrustedProxy(peer, trustedProxies) {
+		return peer
 	}
-}
 
-// Flush implements http.Flusher
-func (w *gzipResponseWriter) Flush() {
-	if w.writer != nil {
-		w.writer.Flush()
-	}
-	if f, ok := w.ResponseWriter.(http.Flusher); ok {
-		f.Flush()
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				// A malformed entry means everything to its left is untrustworthy
+				break
+			}
+			if i == 0 || !isTrustedProxy(hop, trustedProxies) {
+				return hop
+			}
+		}
 	}
-}
 
-// Hijack implements http.Hijacker
-func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
-		return h.Hijack()
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(xri) != nil {
+		return xri
 	}
-	return nil, nil, fmt.Errorf("ResponseWriter does not implement Hijacker")
+
+	return peer
 }
 
-// Push implements http.Pusher
-func (w *gzipResponseWriter) Push(target string, opts *http.PushOptions) error {
-	if p, ok := w.ResponseWriter.(http.Pusher); ok {
-		return p.Push(target, opts)
+// isTrustedProxy reports whether addr falls within one of the trusted networks
+func isTrustedProxy(addr string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
 	}
-	return fmt.Errorf("ResponseWriter does not implement Pusher")
+	return false
 }
 
 // ContentTypeJSON middleware ensures JSON content type
@@ -456,26 +425,3 @@ func ContentTypeJSON(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
-
-// MetricsMiddleware records metrics for monitoring
-func MetricsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		wrapped := &responseWriter{
-			ResponseWriter: w,
-			statusCode:     http.StatusOK,
-		}
-
-		next.ServeHTTP(wrapped, r)
-
-		// Record metrics (would integrate with Prometheus)
-		duration := time.Since(start)
-		recordHTTPMetric(r.Method, r.URL.Path, wrapped.statusCode, duration)
-	})
-}
-
-func recordHTTPMetric(method, path string, status int, duration time.Duration) {
-	// This would integrate with Prometheus or other metrics system
-	// For now, just a placeholder
-}
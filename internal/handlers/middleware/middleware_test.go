@@ -1,13 +1,24 @@
 package middleware_test
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ammerola/resell-be/internal/handlers/middleware"
 	"github.com/ammerola/resell-be/internal/pkg/logger"
@@ -75,7 +86,7 @@ func TestLogger(t *testing.T) {
 		w.Write([]byte("test response"))
 	})
 
-	wrapped := middleware.Logger(log)(handler)
+	wrapped := middleware.Logger(log, "test-secret", nil)(handler)
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req = req.WithContext(context.WithValue(req.Context(), logger.ContextKeyRequestID, "test-123"))
@@ -88,6 +99,90 @@ func TestLogger(t *testing.T) {
 	assert.Equal(t, "test response", w.Body.String())
 }
 
+// signToken builds a compact JWT with the given header and claims, signed with HS256 over secret
+func signToken(t *testing.T, header, claims map[string]interface{}, secret string) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	unsigned := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestLogger_UserIDFromJWT(t *testing.T) {
+	const secret = "test-secret-with-at-least-32-characters"
+	hs256 := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedUserID string
+	}{
+		{
+			name:           "valid_token",
+			authorization:  "Bearer " + signToken(t, hs256, map[string]interface{}{"sub": "user-42", "exp": now.Add(time.Hour).Unix()}, secret),
+			expectedUserID: "user-42",
+		},
+		{
+			name:          "expired_token",
+			authorization: "Bearer " + signToken(t, hs256, map[string]interface{}{"sub": "user-42", "exp": now.Add(-time.Minute).Unix()}, secret),
+		},
+		{
+			name:          "not_yet_valid_token",
+			authorization: "Bearer " + signToken(t, hs256, map[string]interface{}{"sub": "user-42", "nbf": now.Add(time.Hour).Unix()}, secret),
+		},
+		{
+			name:          "malformed_token",
+			authorization: "Bearer not.a-token",
+		},
+		{
+			name:          "wrong_secret",
+			authorization: "Bearer " + signToken(t, hs256, map[string]interface{}{"sub": "user-42"}, "some-other-secret"),
+		},
+		{
+			name:          "unsigned_alg_none",
+			authorization: "Bearer " + signToken(t, map[string]interface{}{"alg": "none"}, map[string]interface{}{"sub": "user-42"}, secret),
+		},
+		{
+			name:          "missing_subject",
+			authorization: "Bearer " + signToken(t, hs256, map[string]interface{}{"exp": now.Add(time.Hour).Unix()}, secret),
+		},
+		{
+			name: "no_authorization_header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userID string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID = logger.UserIDFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+			wrapped := middleware.Logger(logger.SetupLogger("error", "text"), secret, nil)(handler)
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			wrapped.ServeHTTP(w, req)
+
+			// A bad token never fails the request, it only leaves it anonymous
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedUserID, userID)
+		})
+	}
+}
+
 func TestRecovery(t *testing.T) {
 	log := helpers.TestLogger()
 
@@ -132,13 +227,56 @@ func TestRecovery(t *testing.T) {
 	}
 }
 
+func TestRequestID_SharedWithLoggerAndRecovery(t *testing.T) {
+	var seen string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = r.Context().Value(logger.ContextKeyRequestID).(string)
+		panic("test panic")
+	})
+
+	// The order cmd/api wires them in
+	wrapped := middleware.RequestID(
+		middleware.Recovery(helpers.TestLogger())(
+			middleware.Logger(logger.SetupLogger("error", "text"), "test-secret", nil)(handler)))
+
+	for _, incoming := range []string{"", "lb-assigned-id"} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if incoming != "" {
+			req.Header.Set("X-Request-ID", incoming)
+		}
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		var body struct {
+			Error struct {
+				Code      string `json:"code"`
+				RequestID string `json:"request_id"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+		requestID := w.Header().Get("X-Request-ID")
+		require.NotEmpty(t, requestID)
+		if incoming != "" {
+			assert.Equal(t, incoming, requestID)
+		}
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "internal_error", body.Error.Code)
+		assert.Equal(t, requestID, body.Error.RequestID)
+		assert.Equal(t, requestID, seen, "the handler saw a different ID")
+	}
+}
+
 func TestRateLimit(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
 	// Allow 2 requests per second
-	wrapped := middleware.RateLimit(2, time.Second)(handler)
+	wrapped := middleware.RateLimit(middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{Requests: 2, Period: time.Second},
+	})(handler)
 
 	// First two requests should succeed
 	for i := 0; i < 2; i++ {
@@ -166,6 +304,250 @@ func TestRateLimit(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestRateLimiter_UpdateKeepsBuckets(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	limiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{Requests: 1, Period: time.Hour},
+	})
+	wrapped := limiter.Middleware(handler)
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusOK, serve().Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve().Code)
+
+	// A larger burst applies to the existing bucket without handing out the spent token again
+	limiter.Update(middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{Requests: 1, Period: time.Hour, Burst: 5},
+	})
+	w := serve()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5", w.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimit_IgnoresSpoofedForwardedFor(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	trusted, err := middleware.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	wrapped := middleware.RateLimit(middleware.RateLimitConfig{
+		Default:        middleware.RateLimitRule{Requests: 1, Period: time.Second},
+		TrustedProxies: trusted,
+	})(handler)
+
+	// A direct client rotating X-Forwarded-For still shares one bucket
+	for i, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "203.0.113.9:1234"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code)
+	}
+
+	// Clients behind the trusted proxy are limited separately
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "10.0.0.5:1234"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestRateLimit_Burst(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// A slow steady rate so no tokens are replenished during the test
+	wrapped := middleware.RateLimit(middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{Requests: 1, Period: time.Hour, Burst: 5},
+	})(handler)
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, "request %d is within the burst", i+1)
+	}
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	w := httptest.NewRecorder()
+
+	wrapped.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestRateLimit_ThrottledResponse(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// One token every 30 seconds with room for two
+	wrapped := middleware.RateLimit(middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{Requests: 2, Period: time.Minute},
+	})(handler)
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	w = serve()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = serve()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	var body struct {
+		Error      string `json:"error"`
+		RetryAfter int    `json:"retry_after"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "rate limit exceeded", body.Error)
+	assert.InDelta(t, 30, body.RetryAfter, 1)
+	assert.Equal(t, fmt.Sprint(body.RetryAfter), w.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_RouteOverrides(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	wrapped := middleware.RateLimit(middleware.RateLimitConfig{
+		Default: middleware.RateLimitRule{Requests: 100, Period: time.Hour},
+		Routes: map[string]middleware.RateLimitRule{
+			"/api/v1/export/":    {Requests: 1, Period: time.Hour, Burst: 2},
+			"/api/v1/export/pdf": {Requests: 1, Period: time.Hour},
+		},
+	})(handler)
+
+	serve := func(path string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/api/v1/export/csv"))
+	assert.Equal(t, http.StatusOK, serve("/api/v1/export/json"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/export/excel"))
+
+	// The longest prefix wins and has its own bucket
+	assert.Equal(t, http.StatusOK, serve("/api/v1/export/pdf"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/export/pdf"))
+
+	// Exhausting export doesn't touch the default allowance
+	assert.Equal(t, http.StatusOK, serve("/api/v1/inventory"))
+}
+
+func TestGetClientIP(t *testing.T) {
+	trusted, err := middleware.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		xForwardedFor string
+		xRealIP       string
+		expected      string
+	}{
+		{
+			name:       "no_forwarding_headers",
+			remoteAddr: "203.0.113.9:1234",
+			expected:   "203.0.113.9",
+		},
+		{
+			name:          "spoofed_header_from_untrusted_peer",
+			remoteAddr:    "203.0.113.9:1234",
+			xForwardedFor: "1.2.3.4",
+			xRealIP:       "5.6.7.8",
+			expected:      "203.0.113.9",
+		},
+		{
+			name:          "forwarded_by_trusted_proxy",
+			remoteAddr:    "10.1.2.3:1234",
+			xForwardedFor: "198.51.100.7",
+			expected:      "198.51.100.7",
+		},
+		{
+			name:          "client_prepended_spoof_through_trusted_proxy",
+			remoteAddr:    "10.1.2.3:1234",
+			xForwardedFor: "1.2.3.4, 198.51.100.7, 192.168.1.1",
+			expected:      "198.51.100.7",
+		},
+		{
+			name:       "real_ip_from_trusted_proxy",
+			remoteAddr: "192.168.1.1:1234",
+			xRealIP:    "198.51.100.7",
+			expected:   "198.51.100.7",
+		},
+		{
+			name:          "malformed_forwarded_for",
+			remoteAddr:    "10.1.2.3:1234",
+			xForwardedFor: "not-an-ip",
+			expected:      "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.xForwardedFor)
+			}
+			if tt.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tt.xRealIP)
+			}
+
+			assert.Equal(t, tt.expected, middleware.GetClientIP(req, trusted))
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	nets, err := middleware.ParseTrustedProxies([]string{"10.0.0.0/8", " 127.0.0.1 ", "::1", ""})
+	require.NoError(t, err)
+	require.Len(t, nets, 3)
+	assert.Equal(t, "127.0.0.1/32", nets[1].String())
+	assert.Equal(t, "::1/128", nets[2].String())
+
+	_, err = middleware.ParseTrustedProxies([]string{"not-a-proxy"})
+	assert.Error(t, err)
+}
+
 func TestCORS(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -180,13 +562,25 @@ func TestCORS(t *testing.T) {
 		checkHeaders   func(*testing.T, http.Header)
 	}{
 		{
-			name:           "allows_wildcard_origin",
+			name:           "wildcard_origin_without_credentials",
 			allowedOrigins: []string{"*"},
 			requestOrigin:  "https://example.com",
 			requestMethod:  "GET",
 			expectedStatus: http.StatusOK,
 			checkHeaders: func(t *testing.T, headers http.Header) {
-				assert.Equal(t, "https://example.com", headers.Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, "*", headers.Get("Access-Control-Allow-Origin"))
+				assert.Empty(t, headers.Get("Access-Control-Allow-Credentials"))
+			},
+		},
+		{
+			name:           "explicit_origin_alongside_wildcard_gets_credentials",
+			allowedOrigins: []string{"*", "https://app.example.com"},
+			requestOrigin:  "https://app.example.com",
+			requestMethod:  "GET",
+			expectedStatus: http.StatusOK,
+			checkHeaders: func(t *testing.T, headers http.Header) {
+				assert.Equal(t, "https://app.example.com", headers.Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, "true", headers.Get("Access-Control-Allow-Credentials"))
 			},
 		},
 		{
@@ -197,6 +591,8 @@ func TestCORS(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			checkHeaders: func(t *testing.T, headers http.Header) {
 				assert.Equal(t, "https://app.example.com", headers.Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, "true", headers.Get("Access-Control-Allow-Credentials"))
+				assert.Contains(t, headers.Values("Vary"), "Origin")
 			},
 		},
 		{
@@ -206,9 +602,9 @@ func TestCORS(t *testing.T) {
 			requestMethod:  "OPTIONS",
 			expectedStatus: http.StatusNoContent,
 			checkHeaders: func(t *testing.T, headers http.Header) {
-				assert.Equal(t, "https://example.com", headers.Get("Access-Control-Allow-Origin"))
-				assert.NotEmpty(t, headers.Get("Access-Control-Allow-Methods"))
-				assert.NotEmpty(t, headers.Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, "*", headers.Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, "GET, POST", headers.Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "Authorization, X-CSRF-Token", headers.Get("Access-Control-Allow-Headers"))
 			},
 		},
 		{
@@ -219,13 +615,20 @@ func TestCORS(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			checkHeaders: func(t *testing.T, headers http.Header) {
 				assert.Empty(t, headers.Get("Access-Control-Allow-Origin"))
+				assert.Empty(t, headers.Get("Access-Control-Allow-Credentials"))
+				assert.Empty(t, headers.Get("Access-Control-Allow-Methods"))
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			wrapped := middleware.CORS(tt.allowedOrigins)(handler)
+			wrapped := middleware.CORS(middleware.CORSConfig{
+				AllowedOrigins:   tt.allowedOrigins,
+				AllowedMethods:   []string{"GET", "POST"},
+				AllowedHeaders:   []string{"Authorization", "X-CSRF-Token"},
+				AllowCredentials: true,
+			})(handler)
 
 			req := httptest.NewRequest(tt.requestMethod, "/test", nil)
 			req.Header.Set("Origin", tt.requestOrigin)
@@ -239,9 +642,380 @@ func TestCORS(t *testing.T) {
 	}
 }
 
+func TestCompression(t *testing.T) {
+	largeJSON := `{"items":"` + strings.Repeat("abcdefgh", 512) + `"}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		expectGzip     bool
+	}{
+		{
+			name:           "compresses_large_json",
+			acceptEncoding: "gzip, deflate",
+			contentType:    "application/json",
+			body:           largeJSON,
+			expectGzip:     true,
+		},
+		{
+			name:           "client_without_gzip",
+			acceptEncoding: "identity",
+			contentType:    "application/json",
+			body:           largeJSON,
+		},
+		{
+			name:           "gzip_refused_by_q0",
+			acceptEncoding: "gzip;q=0, deflate",
+			contentType:    "application/json",
+			body:           largeJSON,
+		},
+		{
+			name:           "skips_small_response",
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			body:           `{"ok":true}`,
+		},
+		{
+			name:           "skips_already_compressed_type",
+			acceptEncoding: "gzip",
+			contentType:    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			body:           largeJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Like the export handlers, declare the uncompressed length up front
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			})
+
+			server := httptest.NewServer(middleware.Compression(handler))
+			defer server.Close()
+
+			req, err := http.NewRequest("GET", server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+			// A custom Accept-Encoding stops the transport from transparently decompressing
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var reader io.Reader = resp.Body
+			if tt.expectGzip {
+				assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+				assert.NotEqual(t, strconv.Itoa(len(tt.body)), resp.Header.Get("Content-Length"))
+
+				gz, err := gzip.NewReader(resp.Body)
+				require.NoError(t, err)
+				defer gz.Close()
+				reader = gz
+			} else {
+				assert.Empty(t, resp.Header.Get("Content-Encoding"))
+				assert.Equal(t, strconv.Itoa(len(tt.body)), resp.Header.Get("Content-Length"))
+			}
+
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+			assert.Equal(t, tt.contentType, resp.Header.Get("Content-Type"))
+		})
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics-test/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.Handle("GET /metrics", promhttp.Handler())
+	wrapped := middleware.MetricsMiddleware(mux)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	// Different IDs share the route pattern, so they land in one series
+	for _, id := range []string{"1", "2", "3"} {
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, httptest.NewRequest("GET", "/metrics-test/items/"+id, nil))
+		require.Equal(t, http.StatusAccepted, w.Code)
+	}
+	w := httptest.NewRecorder()
+	wrapped.ServeHTTP(w, httptest.NewRequest("GET", "/metrics-test/nope", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	body := scrape()
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/metrics-test/items/{id}",status="202"} 3`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/metrics-test/items/{id}",status="202"} 3`)
+	assert.Contains(t, body, "http_requests_in_flight")
+	assert.NotContains(t, body, "/metrics-test/items/1")
+
+	wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test/items/4", nil))
+	assert.Contains(t, scrape(), `http_requests_total{method="GET",route="/metrics-test/items/{id}",status="202"} 4`)
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		configuredKey  string
+		providedKey    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid_key",
+			configuredKey:  "s3cret-admin-key",
+			providedKey:    "s3cret-admin-key",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name:           "wrong_key",
+			configuredKey:  "s3cret-admin-key",
+			providedKey:    "guess",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:           "missing_key",
+			configuredKey:  "s3cret-admin-key",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Unauthorized"}`,
+		},
+		{
+			name:           "not_configured",
+			providedKey:    "",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"Admin API is not configured"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("ok"))
+			})
+
+			wrapped := middleware.AdminAuth(tt.configuredKey)(handler)
+
+			req := httptest.NewRequest("POST", "/api/v1/admin/refresh-views", nil)
+			if tt.providedKey != "" {
+				req.Header.Set(middleware.AdminAPIKeyHeader, tt.providedKey)
+			}
+			w := httptest.NewRecorder()
+
+			wrapped.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuth(t *testing.T) {
+	const secret = "test-secret-with-at-least-32-characters"
+	hs256 := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	now := time.Now()
+	valid := signToken(t, hs256, map[string]interface{}{"sub": "user-42", "exp": now.Add(time.Hour).Unix()}, secret)
+
+	// Swap the claims for a different subject while keeping the original signature
+	parts := strings.Split(valid, ".")
+	forged := signToken(t, hs256, map[string]interface{}{"sub": "admin", "exp": now.Add(time.Hour).Unix()}, secret)
+	tampered := parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid_token",
+			authorization:  "Bearer " + valid,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user-42",
+		},
+		{
+			name:           "missing_token",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Missing bearer token"}`,
+		},
+		{
+			name:           "non_bearer_scheme",
+			authorization:  "Basic dXNlcjpwYXNz",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Missing bearer token"}`,
+		},
+		{
+			name:           "tampered_token",
+			authorization:  "Bearer " + tampered,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Invalid or expired token"}`,
+		},
+		{
+			name:           "expired_token",
+			authorization:  "Bearer " + signToken(t, hs256, map[string]interface{}{"sub": "user-42", "exp": now.Add(-time.Minute).Unix()}, secret),
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Invalid or expired token"}`,
+		},
+		{
+			name:           "malformed_token",
+			authorization:  "Bearer garbage",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Invalid or expired token"}`,
+		},
+		{
+			name:           "refresh_token",
+			authorization:  "Bearer " + signToken(t, hs256, map[string]interface{}{"sub": "user-42", "token_type": "refresh", "exp": now.Add(time.Hour).Unix()}, secret),
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Invalid or expired token"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := middleware.ClaimsFromContext(r.Context())
+				require.True(t, ok)
+				assert.Equal(t, claims.Subject, logger.UserIDFromContext(r.Context()))
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(claims.Subject))
+			})
+
+			wrapped := middleware.Auth(secret)(handler)
+
+			req := httptest.NewRequest("POST", "/api/v1/inventory", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			wrapped.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	const token = "csrf-token-value"
+	wrapped := middleware.CSRF([]string{"/api/v1/inventory"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		cookie         string
+		header         string
+		expectedStatus int
+		expectCookie   bool
+	}{
+		{
+			name:           "safe_method_issues_token",
+			method:         "GET",
+			path:           "/api/v1/platforms/ebay/listings",
+			expectedStatus: http.StatusOK,
+			expectCookie:   true,
+		},
+		{
+			name:           "post_missing_token",
+			method:         "POST",
+			path:           "/api/v1/platforms/ebay/list",
+			expectedStatus: http.StatusForbidden,
+			expectCookie:   true,
+		},
+		{
+			name:           "post_header_without_cookie",
+			method:         "POST",
+			path:           "/api/v1/platforms/ebay/list",
+			header:         token,
+			expectedStatus: http.StatusForbidden,
+			expectCookie:   true,
+		},
+		{
+			name:           "post_mismatched_token",
+			method:         "PUT",
+			path:           "/api/v1/platforms/ebay/listings/1",
+			cookie:         token,
+			header:         "something-else",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "post_valid_token",
+			method:         "POST",
+			path:           "/api/v1/platforms/ebay/list",
+			cookie:         token,
+			header:         token,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "exempt_path",
+			method:         "DELETE",
+			path:           "/api/v1/inventory/123",
+			expectedStatus: http.StatusOK,
+			expectCookie:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: middleware.CSRFCookieName, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(middleware.CSRFHeaderName, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			wrapped.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.JSONEq(t, `{"error":"Invalid or missing CSRF token"}`, w.Body.String())
+			}
+
+			var issued *http.Cookie
+			for _, c := range w.Result().Cookies() {
+				if c.Name == middleware.CSRFCookieName {
+					issued = c
+				}
+			}
+			if tt.expectCookie {
+				require.NotNil(t, issued)
+				assert.NotEmpty(t, issued.Value)
+				assert.Equal(t, http.SameSiteStrictMode, issued.SameSite)
+			} else {
+				assert.Nil(t, issued, "an existing token should not be reissued")
+			}
+		})
+	}
+}
+
 func TestTimeout(t *testing.T) {
 	tests := []struct {
 		name           string
+		path           string
 		timeout        time.Duration
 		handlerDelay   time.Duration
 		expectedStatus int
@@ -249,18 +1023,28 @@ func TestTimeout(t *testing.T) {
 	}{
 		{
 			name:           "completes_within_timeout",
+			path:           "/test",
 			timeout:        100 * time.Millisecond,
 			handlerDelay:   10 * time.Millisecond,
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusCreated,
 			expectedBody:   "success",
 		},
 		{
 			name:           "times_out",
+			path:           "/test",
 			timeout:        50 * time.Millisecond,
 			handlerDelay:   200 * time.Millisecond,
 			expectedStatus: http.StatusGatewayTimeout,
 			expectedBody:   "Request timeout",
 		},
+		{
+			name:           "exempt_path_runs_long",
+			path:           "/api/v1/export/excel",
+			timeout:        20 * time.Millisecond,
+			handlerDelay:   60 * time.Millisecond,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "success",
+		},
 	}
 
 	for _, tt := range tests {
@@ -268,22 +1052,67 @@ func TestTimeout(t *testing.T) {
 			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				select {
 				case <-time.After(tt.handlerDelay):
-					w.WriteHeader(http.StatusOK)
+					w.Header().Set("X-Handler", "done")
+					w.WriteHeader(http.StatusCreated)
 					w.Write([]byte("success"))
 				case <-r.Context().Done():
 					return
 				}
 			})
 
-			wrapped := middleware.Timeout(tt.timeout)(handler)
+			wrapped := middleware.Timeout(tt.timeout, []string{"/api/v1/export/"})(handler)
 
-			req := httptest.NewRequest("GET", "/test", nil)
+			req := httptest.NewRequest("GET", tt.path, nil)
 			w := httptest.NewRecorder()
 
 			wrapped.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedStatus == http.StatusCreated {
+				assert.Equal(t, "done", w.Header().Get("X-Handler"))
+			}
 		})
 	}
 }
+
+func TestTimeout_SlowHandlerCannotWriteAfterDeadline(t *testing.T) {
+	release := make(chan struct{})
+	writeErr := make(chan error, 1)
+
+	// Ignores its context and keeps writing after the deadline has passed
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("X-Late", "true")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte("late response"))
+		writeErr <- err
+	})
+
+	wrapped := middleware.Timeout(20*time.Millisecond, nil)(handler)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+
+	wrapped.ServeHTTP(w, req)
+	close(release)
+
+	assert.ErrorIs(t, <-writeErr, http.ErrHandlerTimeout)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.JSONEq(t, `{"error":"Request timeout"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("X-Late"))
+}
+
+func TestTimeout_PanicReachesRecovery(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	wrapped := middleware.Recovery(helpers.TestLogger())(middleware.Timeout(time.Second, nil)(handler))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+
+	assert.NotPanics(t, func() { wrapped.ServeHTTP(w, req) })
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,201 @@
+// internal/handlers/middleware/ratelimit.go
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTTL is how long an unused per-client limiter is kept
+const limiterIdleTTL = 10 * time.Minute
+
+// RateLimitRule sets the steady rate and burst allowance for a group of routes
+type RateLimitRule struct {
+	Requests int           // requests replenished per Period; zero means unlimited
+	Period   time.Duration // defaults to a minute
+	Burst    int           // requests allowed back to back; defaults to Requests
+}
+
+// RateLimitConfig configures RateLimit. Routes maps a path prefix to its own rule; the
+// longest matching prefix wins and every other path uses Default.
+type RateLimitConfig struct {
+	Default        RateLimitRule
+	Routes         map[string]RateLimitRule
+	TrustedProxies []*net.IPNet
+}
+
+// rateLimiter is the token bucket for one client on one route group
+type rateLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64 // Unix nanoseconds
+
+	mu   sync.Mutex
+	rule RateLimitRule // the rule limiter was last configured with
+}
+
+// routeRule is a RateLimitConfig.Routes entry with its prefix
+type routeRule struct {
+	prefix string
+	rule   RateLimitRule
+}
+
+// rateLimitSettings is a RateLimitConfig prepared for matching requests
+type rateLimitSettings struct {
+	defaultRule    RateLimitRule
+	routes         []routeRule // longest prefix first
+	trustedProxies []*net.IPNet
+}
+
+// RateLimiter limits requests per client IP, resolved through the trusted proxies. Each route
+// prefix has its own bucket, so exhausting a strict route such as export doesn't use up the
+// client's default allowance. Update changes the rules in place: clients keep the allowance
+// they have left, and one goroutine evicts idle buckets for the limiter's lifetime.
+type RateLimiter struct {
+	settings atomic.Pointer[rateLimitSettings]
+	limiters sync.Map // route prefix|client IP -> *rateLimiter
+}
+
+// NewRateLimiter creates a rate limiter enforcing cfg
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{}
+	l.Update(cfg)
+	go l.evictIdle()
+	return l
+}
+
+// RateLimit returns middleware backed by a new RateLimiter enforcing cfg
+func RateLimit(cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return NewRateLimiter(cfg).Middleware
+}
+
+// Update swaps in new rules. Existing buckets pick up their route's new rate and burst on their
+// next request without being refilled.
+func (l *RateLimiter) Update(cfg RateLimitConfig) {
+	routes := make([]routeRule, 0, len(cfg.Routes))
+	for prefix, rule := range cfg.Routes {
+		routes = append(routes, routeRule{prefix: prefix, rule: rule})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return len(routes[i].prefix) > len(routes[j].prefix)
+	})
+
+	l.settings.Store(&rateLimitSettings{
+		defaultRule:    cfg.Default,
+		routes:         routes,
+		trustedProxies: cfg.TrustedProxies,
+	})
+}
+
+// evictIdle periodically drops buckets that haven't been used for limiterIdleTTL
+func (l *RateLimiter) evictIdle() {
+	ticker := time.NewTicker(limiterIdleTTL)
+	for range ticker.C {
+		cutoff := time.Now().Add(-limiterIdleTTL).UnixNano()
+		l.limiters.Range(func(key, value interface{}) bool {
+			if value.(*rateLimiter).lastSeen.Load() < cutoff {
+				l.limiters.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+// Middleware rejects requests beyond the client's allowance with 429
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := l.settings.Load()
+		prefix, rule := "", settings.defaultRule
+		for _, route := range settings.routes {
+			if strings.HasPrefix(r.URL.Path, route.prefix) {
+				prefix, rule = route.prefix, route.rule
+				break
+			}
+		}
+
+		key := prefix + "|" + getClientIP(r, settings.trustedProxies)
+		val, ok := l.limiters.Load(key)
+		if !ok {
+			val, _ = l.limiters.LoadOrStore(key, newRateLimiter(rule))
+		}
+
+		rl := val.(*rateLimiter)
+		now := time.Now()
+		rl.lastSeen.Store(now.UnixNano())
+		rl.apply(rule, now)
+
+		// Reserve a token; a reservation that would have to wait is a rejection
+		res := rl.limiter.ReserveN(now, 1)
+		delay := res.DelayFrom(now)
+		if delay > 0 {
+			res.CancelAt(now)
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.limiter.Burst()))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(0, int(rl.limiter.TokensAt(now)))))
+
+		if delay > 0 {
+			retryAfter := int(math.Ceil(delay.Seconds()))
+			if !res.OK() {
+				// The bucket can never hold a token, so there is no exact wait to report
+				retryAfter = int(math.Ceil(limiterIdleTTL.Seconds()))
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(w, `{"error":"rate limit exceeded","retry_after":%d}`, retryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newRateLimiter builds a token bucket for rule, filling in defaults
+func newRateLimiter(rule RateLimitRule) *rateLimiter {
+	limit, burst := ruleLimits(rule)
+	return &rateLimiter{limiter: rate.NewLimiter(limit, burst), rule: rule}
+}
+
+// apply reconfigures the bucket when its route's rule has changed, keeping its tokens
+func (rl *rateLimiter) apply(rule RateLimitRule, now time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.rule == rule {
+		return
+	}
+
+	limit, burst := ruleLimits(rule)
+	rl.limiter.SetLimitAt(now, limit)
+	rl.limiter.SetBurstAt(now, burst)
+	rl.rule = rule
+}
+
+// ruleLimits returns the token rate and burst for rule, filling in defaults
+func ruleLimits(rule RateLimitRule) (rate.Limit, int) {
+	period := rule.Period
+	if period <= 0 {
+		period = time.Minute
+	}
+	burst := rule.Burst
+	if burst <= 0 {
+		burst = rule.Requests
+	}
+
+	limit := rate.Inf
+	if rule.Requests > 0 {
+		limit = rate.Every(period / time.Duration(rule.Requests))
+	}
+
+	return limit, burst
+}
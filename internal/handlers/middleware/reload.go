@@ -0,0 +1,32 @@
+// internal/handlers/middleware/reload.go
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Reloadable wraps next in middleware that can be replaced while the server is running, e.g.
+// after a configuration reload. Requests in flight finish with the middleware they started with.
+type Reloadable struct {
+	next    http.Handler
+	handler atomic.Pointer[http.Handler]
+}
+
+// NewReloadable creates a reloadable middleware around next, initially wrapped by wrap
+func NewReloadable(next http.Handler, wrap func(http.Handler) http.Handler) *Reloadable {
+	r := &Reloadable{next: next}
+	r.Reload(wrap)
+	return r
+}
+
+// Reload swaps in middleware built by wrap for subsequent requests
+func (r *Reloadable) Reload(wrap func(http.Handler) http.Handler) {
+	handler := wrap(r.next)
+	r.handler.Store(&handler)
+}
+
+// ServeHTTP serves the request through the current middleware
+func (r *Reloadable) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	(*r.handler.Load()).ServeHTTP(w, req)
+}
@@ -0,0 +1,291 @@
+// internal/handlers/platform.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/shopspring/decimal"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/workers"
+)
+
+// analyticsRefreshWindow collapses refresh requests from bursts of sales into a single task
+const analyticsRefreshWindow = time.Minute
+
+// PlatformHandler handles marketplace listing and sale requests
+type PlatformHandler struct {
+	service     ports.ListingService
+	asynqClient *asynq.Client
+	logger      *slog.Logger
+}
+
+// NewPlatformHandler creates a new platform handler. asynqClient may be nil, in
+// which case recorded sales do not trigger an analytics refresh.
+func NewPlatformHandler(service ports.ListingService, asynqClient *asynq.Client, logger *slog.Logger) *PlatformHandler {
+	return &PlatformHandler{
+		service:     service,
+		asynqClient: asynqClient,
+		logger:      logger.With(slog.String("handler", "platform")),
+	}
+}
+
+// CreateListingRequest represents the request body for creating a platform listing
+type CreateListingRequest struct {
+	LotID       uuid.UUID       `json:"lot_id"`
+	ListPrice   decimal.Decimal `json:"list_price"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	CategoryID  string          `json:"category_id,omitempty"`
+}
+
+// Validate validates the create listing request
+func (r *CreateListingRequest) Validate() error {
+	if r.LotID == uuid.Nil {
+		return fmt.Errorf("lot_id is required")
+	}
+	if !r.ListPrice.IsPositive() {
+		return fmt.Errorf("list_price must be positive")
+	}
+	return nil
+}
+
+// UpdateListingRequest represents the request body for updating a platform listing
+type UpdateListingRequest struct {
+	ListPrice   *decimal.Decimal `json:"list_price,omitempty"`
+	Title       *string          `json:"title,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	CategoryID  *string          `json:"category_id,omitempty"`
+}
+
+// RecordSaleRequest represents the request body for recording a sale
+type RecordSaleRequest struct {
+	SalePrice       *decimal.Decimal `json:"sale_price"`
+	Platform        domain.Platform  `json:"platform"`
+	SoldAt          *time.Time       `json:"sold_at,omitempty"`
+	ShippingCharged decimal.Decimal  `json:"shipping_charged"`
+}
+
+// Validate validates the record sale request
+func (r *RecordSaleRequest) Validate() error {
+	if r.SalePrice == nil {
+		return fmt.Errorf("sale_price is required")
+	}
+	if r.SalePrice.IsNegative() {
+		return fmt.Errorf("sale_price cannot be negative")
+	}
+	if r.Platform == "" {
+		return fmt.Errorf("platform is required")
+	}
+	if !r.Platform.IsValid() {
+		return fmt.Errorf("unknown platform %q", r.Platform)
+	}
+	return nil
+}
+
+// ListListings handles GET /api/v1/platforms/{platform}/listings
+func (h *PlatformHandler) ListListings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	platform := domain.Platform(r.PathValue("platform"))
+
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	pageSize := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		pageSize = l
+	}
+
+	result, err := h.service.ListListings(ctx, platform, page, pageSize)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list platform listings",
+			slog.String("platform", string(platform)),
+			slog.String("error", err.Error()))
+		h.respondServiceError(w, err, "Failed to list platform listings")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// CreateListing handles POST /api/v1/platforms/{platform}/list
+func (h *PlatformHandler) CreateListing(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	platform := domain.Platform(r.PathValue("platform"))
+
+	var req CreateListingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	listing := &domain.PlatformListing{
+		LotID:       req.LotID,
+		Platform:    platform,
+		ListPrice:   req.ListPrice,
+		Title:       req.Title,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+	}
+
+	if err := h.service.CreateListing(ctx, listing); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create platform listing",
+			slog.String("platform", string(platform)),
+			slog.String("lot_id", req.LotID.String()),
+			slog.String("error", err.Error()))
+		h.respondServiceError(w, err, "Failed to create platform listing")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, listing)
+}
+
+// UpdateListing handles PUT /api/v1/platforms/{platform}/listings/{id}
+func (h *PlatformHandler) UpdateListing(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	platform := domain.Platform(r.PathValue("platform"))
+	idStr := r.PathValue("id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid listing ID format")
+		return
+	}
+
+	var req UpdateListingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	listing, err := h.service.UpdateListing(ctx, platform, id, ports.ListingUpdate{
+		ListPrice:   req.ListPrice,
+		Title:       req.Title,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update platform listing",
+			slog.String("platform", string(platform)),
+			slog.String("id", idStr),
+			slog.String("error", err.Error()))
+		h.respondServiceError(w, err, "Failed to update platform listing")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, listing)
+}
+
+// RecordSale handles POST /api/v1/inventory/{id}/sale
+func (h *PlatformHandler) RecordSale(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := r.PathValue("id")
+
+	lotID, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid inventory ID format")
+		return
+	}
+
+	var req RecordSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sale := &domain.Sale{
+		LotID:           lotID,
+		Platform:        req.Platform,
+		SalePrice:       *req.SalePrice,
+		ShippingCharged: req.ShippingCharged,
+	}
+	if req.SoldAt != nil {
+		sale.SoldAt = *req.SoldAt
+	}
+
+	listing, err := h.service.RecordSale(ctx, sale)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to record sale",
+			slog.String("lot_id", idStr),
+			slog.String("error", err.Error()))
+		h.respondServiceError(w, err, "Failed to record sale")
+		return
+	}
+
+	h.enqueueAnalyticsRefresh(ctx)
+
+	h.respondJSON(w, http.StatusOK, listing)
+}
+
+// enqueueAnalyticsRefresh asks the worker to refresh the export view so profit
+// and ROI reflect the new sale. Failures are logged since the sale is already saved.
+func (h *PlatformHandler) enqueueAnalyticsRefresh(ctx context.Context) {
+	if h.asynqClient == nil {
+		return
+	}
+
+	task := asynq.NewTask(workers.TypeRefreshAnalytics, nil)
+	_, err := h.asynqClient.EnqueueContext(ctx, task,
+		asynq.Queue("low"),
+		asynq.Unique(analyticsRefreshWindow))
+	if err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		h.logger.WarnContext(ctx, "failed to enqueue analytics refresh",
+			slog.String("error", err.Error()))
+	}
+}
+
+// respondServiceError maps listing service errors to HTTP status codes
+func (h *PlatformHandler) respondServiceError(w http.ResponseWriter, err error, fallback string) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "unsupported platform"):
+		h.respondError(w, http.StatusBadRequest, "Platform is not supported for listings")
+	case errors.Is(err, domain.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "Inventory item not found")
+	case strings.Contains(msg, "listing not found"):
+		h.respondError(w, http.StatusNotFound, "Listing not found")
+	case strings.Contains(msg, "listing already exists"):
+		h.respondError(w, http.StatusConflict, "Listing already exists for this item on this platform")
+	case strings.Contains(msg, "validation failed"):
+		h.respondError(w, http.StatusBadRequest, msg)
+	default:
+		h.respondError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+func (h *PlatformHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *PlatformHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,370 @@
+// internal/handlers/platform_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestPlatformHandler_CreateListing(t *testing.T) {
+	lotID := uuid.New()
+
+	tests := []struct {
+		name           string
+		platform       string
+		body           string
+		setupMocks     func(*mocks.MockListingService)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:     "creates_draft_listing",
+			platform: "ebay",
+			body:     `{"lot_id":"` + lotID.String() + `","list_price":"120.00","title":"Oak Side Table"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().
+					CreateListing(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, l *domain.PlatformListing) error {
+						assert.Equal(t, lotID, l.LotID)
+						assert.Equal(t, domain.PlatformEbay, l.Platform)
+						assert.Equal(t, "Oak Side Table", l.Title)
+						l.ID = uuid.New()
+						l.Status = domain.StatusDraft
+						return nil
+					})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid_body",
+			platform:       "ebay",
+			body:           `{`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+		{
+			name:           "missing_price",
+			platform:       "ebay",
+			body:           `{"lot_id":"` + lotID.String() + `"}`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "list_price must be positive",
+		},
+		{
+			name:     "unsupported_platform",
+			platform: "etsy",
+			body:     `{"lot_id":"` + lotID.String() + `","list_price":"120.00"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().CreateListing(gomock.Any(), gomock.Any()).Return(errors.New("unsupported platform: etsy"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Platform is not supported for listings",
+		},
+		{
+			name:     "item_not_found",
+			platform: "ebay",
+			body:     `{"lot_id":"` + lotID.String() + `","list_price":"120.00"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().CreateListing(gomock.Any(), gomock.Any()).Return(fmt.Errorf("%w: %s", domain.ErrNotFound, lotID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Inventory item not found",
+		},
+		{
+			name:     "already_listed",
+			platform: "ebay",
+			body:     `{"lot_id":"` + lotID.String() + `","list_price":"120.00"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().CreateListing(gomock.Any(), gomock.Any()).
+					Return(errors.New("failed to create listing: listing already exists for lot " + lotID.String() + " on ebay"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockListingService(ctrl)
+			tt.setupMocks(mockService)
+			handler := handlers.NewPlatformHandler(mockService, nil, helpers.TestLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/platforms/"+tt.platform+"/list", strings.NewReader(tt.body))
+			req.SetPathValue("platform", tt.platform)
+			w := httptest.NewRecorder()
+
+			handler.CreateListing(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+			}
+		})
+	}
+}
+
+func TestPlatformHandler_ListListings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockListingService(ctrl)
+	mockService.EXPECT().
+		ListListings(gomock.Any(), domain.PlatformEbay, 2, 20).
+		Return(&ports.ListingListResult{
+			Listings:   []*domain.PlatformListing{{ID: uuid.New(), Platform: domain.PlatformEbay}},
+			Page:       2,
+			PageSize:   20,
+			TotalCount: 21,
+			TotalPages: 2,
+		}, nil)
+	handler := handlers.NewPlatformHandler(mockService, nil, helpers.TestLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/platforms/ebay/listings?page=2&limit=20", nil)
+	req.SetPathValue("platform", "ebay")
+	w := httptest.NewRecorder()
+
+	handler.ListListings(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response ports.ListingListResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Listings, 1)
+	assert.Equal(t, int64(21), response.TotalCount)
+}
+
+func TestPlatformHandler_UpdateListing(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		setupMocks     func(*mocks.MockListingService)
+		expectedStatus int
+	}{
+		{
+			name: "updates_listing",
+			id:   id.String(),
+			body: `{"title":"New Title"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().
+					UpdateListing(gomock.Any(), domain.PlatformEbay, id, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p domain.Platform, id uuid.UUID, u ports.ListingUpdate) (*domain.PlatformListing, error) {
+						require.NotNil(t, u.Title)
+						assert.Equal(t, "New Title", *u.Title)
+						assert.Nil(t, u.ListPrice)
+						return &domain.PlatformListing{ID: id, Title: *u.Title}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid_id",
+			id:             "not-a-uuid",
+			body:           `{}`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "listing_not_found",
+			id:   id.String(),
+			body: `{"title":"New Title"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().UpdateListing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("listing not found: "+id.String()))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "validation_error",
+			id:   id.String(),
+			body: `{"list_price":"0"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().UpdateListing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("validation failed: list_price must be positive"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockListingService(ctrl)
+			tt.setupMocks(mockService)
+			handler := handlers.NewPlatformHandler(mockService, nil, helpers.TestLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/platforms/ebay/listings/"+tt.id, strings.NewReader(tt.body))
+			req.SetPathValue("platform", "ebay")
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.UpdateListing(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPlatformHandler_RecordSale(t *testing.T) {
+	lotID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		setupMocks     func(*mocks.MockListingService)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "records_sale",
+			id:   lotID.String(),
+			body: `{"sale_price":"250.00","platform":"ebay","sold_at":"2024-06-01T12:00:00Z","shipping_charged":"15.00"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().
+					RecordSale(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s *domain.Sale) (*domain.PlatformListing, error) {
+						assert.Equal(t, lotID, s.LotID)
+						assert.Equal(t, domain.PlatformEbay, s.Platform)
+						assert.Equal(t, "250", s.SalePrice.String())
+						assert.Equal(t, "15", s.ShippingCharged.String())
+						assert.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), s.SoldAt.UTC())
+						return &domain.PlatformListing{LotID: lotID, Status: domain.StatusSold}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid_id",
+			id:             "not-a-uuid",
+			body:           `{}`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid inventory ID format",
+		},
+		{
+			name:           "missing_sale_price",
+			id:             lotID.String(),
+			body:           `{"platform":"ebay"}`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "sale_price is required",
+		},
+		{
+			name:           "negative_sale_price",
+			id:             lotID.String(),
+			body:           `{"sale_price":"-1","platform":"ebay"}`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "sale_price cannot be negative",
+		},
+		{
+			name:           "unknown_platform",
+			id:             lotID.String(),
+			body:           `{"sale_price":"10","platform":"craigslist"}`,
+			setupMocks:     func(m *mocks.MockListingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `unknown platform "craigslist"`,
+		},
+		{
+			name: "item_not_found",
+			id:   lotID.String(),
+			body: `{"sale_price":"10","platform":"local"}`,
+			setupMocks: func(m *mocks.MockListingService) {
+				m.EXPECT().RecordSale(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, lotID))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Inventory item not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockListingService(ctrl)
+			tt.setupMocks(mockService)
+			handler := handlers.NewPlatformHandler(mockService, nil, helpers.TestLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/"+tt.id+"/sale", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.RecordSale(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+			}
+		})
+	}
+}
+
+func TestPlatformHandler_RecordSale_EnqueuesAnalyticsRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testRedis := helpers.SetupTestRedis(t)
+	redisOpt := asynq.RedisClientOpt{Addr: testRedis.Server.Addr()}
+	client := asynq.NewClient(redisOpt)
+	defer client.Close()
+
+	mockService := mocks.NewMockListingService(ctrl)
+	mockService.EXPECT().
+		RecordSale(gomock.Any(), gomock.Any()).
+		Return(&domain.PlatformListing{Status: domain.StatusSold}, nil).
+		Times(2)
+	handler := handlers.NewPlatformHandler(mockService, client, helpers.TestLogger())
+
+	// A second sale inside the uniqueness window must not stack another refresh
+	for i := 0; i < 2; i++ {
+		id := uuid.New().String()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/"+id+"/sale",
+			strings.NewReader(`{"sale_price":"10","platform":"local"}`))
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+
+		handler.RecordSale(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	inspector := asynq.NewInspector(redisOpt)
+	defer inspector.Close()
+
+	tasks, err := inspector.ListPendingTasks("low")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, workers.TypeRefreshAnalytics, tasks[0].Type)
+}
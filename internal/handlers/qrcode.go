@@ -0,0 +1,148 @@
+// internal/handlers/qrcode.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/qrcode"
+)
+
+// QR image sizes, in pixels per module
+const (
+	defaultQRModuleSize = 8
+	maxQRModuleSize     = 40
+)
+
+// QRCodeHandler serves inventory item QR codes
+type QRCodeHandler struct {
+	service  ports.InventoryService
+	template domain.QRTemplate
+	logger   *slog.Logger
+}
+
+// NewQRCodeHandler creates a new QR code handler. template is encoded for items that have no
+// stored QR payload.
+func NewQRCodeHandler(service ports.InventoryService, template domain.QRTemplate, logger *slog.Logger) *QRCodeHandler {
+	return &QRCodeHandler{
+		service:  service,
+		template: template,
+		logger:   logger.With(slog.String("handler", "qrcode")),
+	}
+}
+
+// GetQRCode handles GET /api/v1/inventory/{id}/qr, rendering the item's QR code on the fly.
+// format is png (the default) or svg, and size the pixels per module.
+func (h *QRCodeHandler) GetQRCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := r.PathValue("id")
+
+	lotID, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid inventory ID format")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" {
+		h.respondError(w, http.StatusBadRequest, "format must be one of: png, svg")
+		return
+	}
+
+	size := defaultQRModuleSize
+	if s := r.URL.Query().Get("size"); s != "" {
+		size, err = strconv.Atoi(s)
+		if err != nil || size < 1 || size > maxQRModuleSize {
+			h.respondError(w, http.StatusBadRequest, "size must be between 1 and 40")
+			return
+		}
+	}
+
+	item, err := h.service.GetByID(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.respondError(w, http.StatusNotFound, "Inventory item not found")
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to get inventory item",
+			slog.String("lot_id", idStr),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve inventory item")
+		return
+	}
+
+	code, err := qrcode.Encode(item.QRPayload(h.template), qrcode.LevelM)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to encode qr code",
+			slog.String("lot_id", idStr),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to generate QR code")
+		return
+	}
+
+	var body []byte
+	contentType := "image/svg+xml"
+	if format == "png" {
+		contentType = "image/png"
+		if body, err = code.PNG(size); err != nil {
+			h.logger.ErrorContext(ctx, "failed to render qr code",
+				slog.String("lot_id", idStr),
+				slog.String("error", err.Error()))
+			h.respondError(w, http.StatusInternalServerError, "Failed to generate QR code")
+			return
+		}
+	} else {
+		body = code.SVG(size)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.logger.WarnContext(ctx, "failed to write qr code",
+			slog.String("error", err.Error()))
+	}
+}
+
+// GenerateQRCodes handles POST /api/v1/inventory/qr, storing the configured payload on every
+// active item that has none
+func (h *QRCodeHandler) GenerateQRCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	generated, err := h.service.GenerateQRCodes(ctx, h.template)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to generate qr codes",
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to generate QR codes")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"generated": generated,
+	})
+}
+
+func (h *QRCodeHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *QRCodeHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
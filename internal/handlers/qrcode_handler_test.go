@@ -0,0 +1,278 @@
+// internal/handlers/qrcode_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/internal/pkg/qrcode"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+const testQRTemplate domain.QRTemplate = "https://resell.example.com/inventory/{lot_id}"
+
+func TestQRCodeHandler_GetQRCode(t *testing.T) {
+	item := helpers.CreateTestInventoryItem()
+	stored := helpers.CreateTestInventoryItem(func(i *domain.InventoryItem) {
+		i.QRCode = "LABEL-0042"
+	})
+
+	tests := []struct {
+		name           string
+		id             string
+		query          string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validate       func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "png_encodes_deep_link",
+			id:   item.LotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().GetByID(gomock.Any(), item.LotID).Return(item, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+				code, err := qrcode.Encode("https://resell.example.com/inventory/"+item.LotID.String(), qrcode.LevelM)
+				require.NoError(t, err)
+				want, err := code.PNG(8)
+				require.NoError(t, err)
+				assert.Equal(t, want, w.Body.Bytes())
+			},
+		},
+		{
+			name:  "stored_payload_as_svg",
+			id:    stored.LotID.String(),
+			query: "?format=svg&size=4",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().GetByID(gomock.Any(), stored.LotID).Return(stored, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+
+				code, err := qrcode.Encode("LABEL-0042", qrcode.LevelM)
+				require.NoError(t, err)
+				assert.Equal(t, string(code.SVG(4)), w.Body.String())
+			},
+		},
+		{
+			name:           "invalid_id",
+			id:             "not-a-uuid",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown_format",
+			id:             item.LotID.String(),
+			query:          "?format=gif",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "size_out_of_range",
+			id:             item.LotID.String(),
+			query:          "?size=100",
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "item_not_found",
+			id:   item.LotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					GetByID(gomock.Any(), item.LotID).
+					Return(nil, fmt.Errorf("%w: %s", domain.ErrNotFound, item.LotID))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "service_error",
+			id:   item.LotID.String(),
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().GetByID(gomock.Any(), item.LotID).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewQRCodeHandler(mockService, testQRTemplate, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/"+tt.id+"/qr"+tt.query, nil)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.GetQRCode(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validate != nil {
+				tt.validate(t, w)
+			}
+		})
+	}
+}
+
+func TestQRCodeHandler_GenerateQRCodes(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "fills_missing_payloads",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().GenerateQRCodes(gomock.Any(), testQRTemplate).Return(int64(12), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"generated":12`,
+		},
+		{
+			name: "service_error",
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().GenerateQRCodes(gomock.Any(), testQRTemplate).Return(int64(0), errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Failed to generate QR codes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewQRCodeHandler(mockService, testQRTemplate, helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/qr", nil)
+			w := httptest.NewRecorder()
+
+			handler.GenerateQRCodes(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.True(t, json.Valid(w.Body.Bytes()))
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestQRCodeHandler_ExportLabels(t *testing.T) {
+	labelItems := func(n int) []*domain.InventoryItem {
+		items := make([]*domain.InventoryItem, n)
+		for i := range items {
+			items[i] = helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+				item.StorageLocation = "Garage"
+				item.StorageBin = fmt.Sprintf("A%d", i)
+			})
+		}
+		return items
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		items          int
+		totalCount     int64
+		wantPages      int
+		expectedStatus int
+	}{
+		{name: "default_avery_grid", query: "?storage_location=Garage", items: 31, wantPages: 2, expectedStatus: http.StatusOK},
+		{name: "custom_grid", query: "?rows=2&columns=2", items: 7, wantPages: 2, expectedStatus: http.StatusOK},
+		{name: "full_page", query: "?rows=2&columns=2", items: 4, wantPages: 1, expectedStatus: http.StatusOK},
+		{name: "no_items_is_a_blank_page", items: 0, wantPages: 1, expectedStatus: http.StatusOK},
+		{name: "too_many_items", items: 0, totalCount: 1001, expectedStatus: http.StatusBadRequest},
+		{name: "invalid_rows", query: "?rows=0", items: -1, expectedStatus: http.StatusBadRequest},
+		{name: "invalid_columns", query: "?columns=7", items: -1, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewQRCodeHandler(mockService, testQRTemplate, helpers.TestLogger())
+
+			items := labelItems(max(tt.items, 0))
+			if tt.items >= 0 {
+				total := tt.totalCount
+				if total == 0 {
+					total = int64(len(items))
+				}
+				mockService.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.Equal(t, 1, params.Page)
+						assert.Equal(t, 1000, params.PageSize)
+						if tt.name == "default_avery_grid" {
+							assert.Equal(t, "Garage", params.StorageLocation)
+						}
+						return &ports.ListResult{Items: items, TotalCount: total}, nil
+					})
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/export/labels"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ExportLabels(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+
+			pdf := w.Body.String()
+			assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4"))
+			assert.Equal(t, tt.wantPages, strings.Count(pdf, "/Type /Page /Parent"))
+
+			// One label cell, drawn in its own coordinate space, per item
+			assert.Equal(t, len(items), strings.Count(pdf, " cm\n"))
+			for _, item := range items {
+				assert.Equal(t, 1, strings.Count(pdf, "("+item.LotID.String()+")"))
+				assert.Contains(t, pdf, "(Garage / Bin "+item.StorageBin+")")
+			}
+		})
+	}
+}
+
+func TestQRCodeHandler_ExportLabels_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockInventoryService(ctrl)
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+	handler := handlers.NewQRCodeHandler(mockService, testQRTemplate, helpers.TestLogger())
+
+	w := httptest.NewRecorder()
+	handler.ExportLabels(w, httptest.NewRequest(http.MethodGet, "/api/v1/export/labels", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
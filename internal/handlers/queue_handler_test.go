@@ -0,0 +1,243 @@
+// internal/handlers/queue_handler_test.go
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestQueueHandler_ListQueues(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockQueueInspector)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name: "reports_counts_sorted_by_name",
+			setupMocks: func(i *mocks.MockQueueInspector) {
+				i.EXPECT().Queues().Return([]string{"low", "default"}, nil)
+				i.EXPECT().GetQueueInfo("default").Return(&asynq.QueueInfo{
+					Queue: "default", Size: 9, Pending: 4, Active: 2, Scheduled: 1, Retry: 1, Archived: 1,
+				}, nil)
+				i.EXPECT().GetQueueInfo("low").Return(&asynq.QueueInfo{
+					Queue: "low", Paused: true, Archived: 3,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, b []byte) {
+				var body struct {
+					Queues []handlers.QueueStats `json:"queues"`
+				}
+				require.NoError(t, json.Unmarshal(b, &body))
+				require.Len(t, body.Queues, 2)
+
+				assert.Equal(t, handlers.QueueStats{
+					Name: "default", Size: 9, Pending: 4, Active: 2, Scheduled: 1, Retry: 1, Archived: 1,
+				}, body.Queues[0])
+				assert.Equal(t, "low", body.Queues[1].Name)
+				assert.True(t, body.Queues[1].Paused)
+				assert.Equal(t, 3, body.Queues[1].Archived)
+			},
+		},
+		{
+			name: "inspector_error",
+			setupMocks: func(i *mocks.MockQueueInspector) {
+				i.EXPECT().Queues().Return(nil, errors.New("redis down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "queue_info_error",
+			setupMocks: func(i *mocks.MockQueueInspector) {
+				i.EXPECT().Queues().Return([]string{"default"}, nil)
+				i.EXPECT().GetQueueInfo("default").Return(nil, errors.New("redis down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			inspector := mocks.NewMockQueueInspector(ctrl)
+			tt.setupMocks(inspector)
+
+			handler := handlers.NewQueueHandler(inspector, helpers.TestLogger())
+
+			req := httptest.NewRequest("GET", "/api/v1/admin/queues", nil)
+			w := httptest.NewRecorder()
+
+			handler.ListQueues(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestQueueHandler_ListArchivedTasks(t *testing.T) {
+	failedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*mocks.MockQueueInspector)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:  "lists_dead_letter_tasks",
+			query: "?page=2&limit=10",
+			setupMocks: func(i *mocks.MockQueueInspector) {
+				i.EXPECT().
+					ListArchivedTasks("default", gomock.Any(), gomock.Any()).
+					Return([]*asynq.TaskInfo{{
+						ID:           "task-1",
+						Queue:        "default",
+						Type:         "import:excel",
+						Payload:      []byte(`{"job_id":"abc"}`),
+						LastErr:      "parse failed",
+						LastFailedAt: failedAt,
+						Retried:      3,
+						MaxRetry:     3,
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, b []byte) {
+				var body struct {
+					Tasks []handlers.ArchivedTask `json:"tasks"`
+					Page  int                     `json:"page"`
+					Limit int                     `json:"limit"`
+				}
+				require.NoError(t, json.Unmarshal(b, &body))
+				assert.Equal(t, 2, body.Page)
+				assert.Equal(t, 10, body.Limit)
+				require.Len(t, body.Tasks, 1)
+
+				task := body.Tasks[0]
+				assert.Equal(t, "task-1", task.ID)
+				assert.Equal(t, "import:excel", task.Type)
+				assert.JSONEq(t, `{"job_id":"abc"}`, string(task.Payload))
+				assert.Equal(t, "parse failed", task.LastError)
+				require.NotNil(t, task.LastFailedAt)
+				assert.True(t, failedAt.Equal(*task.LastFailedAt))
+				assert.Equal(t, 3, task.Retried)
+			},
+		},
+		{
+			name: "unknown_queue",
+			setupMocks: func(i *mocks.MockQueueInspector) {
+				i.EXPECT().
+					ListArchivedTasks("default", gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("asynq: %w", asynq.ErrQueueNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "inspector_error",
+			setupMocks: func(i *mocks.MockQueueInspector) {
+				i.EXPECT().
+					ListArchivedTasks("default", gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("redis down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			inspector := mocks.NewMockQueueInspector(ctrl)
+			tt.setupMocks(inspector)
+
+			handler := handlers.NewQueueHandler(inspector, helpers.TestLogger())
+
+			req := httptest.NewRequest("GET", "/api/v1/admin/queues/default/archived"+tt.query, nil)
+			req.SetPathValue("queue", "default")
+			w := httptest.NewRecorder()
+
+			handler.ListArchivedTasks(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestQueueHandler_RetryTask(t *testing.T) {
+	tests := []struct {
+		name           string
+		runErr         error
+		expectedStatus int
+	}{
+		{
+			name:           "re_enqueues_task",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "task_not_found",
+			runErr:         fmt.Errorf("asynq: %w", asynq.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "queue_not_found",
+			runErr:         fmt.Errorf("asynq: %w", asynq.ErrQueueNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "inspector_error",
+			runErr:         errors.New("redis down"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			inspector := mocks.NewMockQueueInspector(ctrl)
+			inspector.EXPECT().RunTask("default", "task-1").Return(tt.runErr)
+
+			handler := handlers.NewQueueHandler(inspector, helpers.TestLogger())
+
+			req := httptest.NewRequest("POST", "/api/v1/admin/queues/default/tasks/task-1/retry", nil)
+			req.SetPathValue("queue", "default")
+			req.SetPathValue("id", "task-1")
+			w := httptest.NewRecorder()
+
+			handler.RetryTask(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				var body map[string]string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, "task-1", body["task_id"])
+				assert.Equal(t, "pending", body["status"])
+			}
+		})
+	}
+}
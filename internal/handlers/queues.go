@@ -0,0 +1,201 @@
+// internal/handlers/queues.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// QueueHandler handles admin requests for inspecting the background task queues
+type QueueHandler struct {
+	inspector ports.QueueInspector
+	logger    *slog.Logger
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(inspector ports.QueueInspector, logger *slog.Logger) *QueueHandler {
+	return &QueueHandler{
+		inspector: inspector,
+		logger:    logger.With(slog.String("handler", "queues")),
+	}
+}
+
+// QueueStats summarizes the tasks in a single queue
+type QueueStats struct {
+	Name      string `json:"name"`
+	Paused    bool   `json:"paused"`
+	Size      int    `json:"size"`
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"`
+	Completed int    `json:"completed"`
+	Processed int    `json:"processed_today"`
+	Failed    int    `json:"failed_today"`
+}
+
+// ArchivedTask is a task that exhausted its retries and was moved to the dead-letter set
+type ArchivedTask struct {
+	ID           string          `json:"id"`
+	Queue        string          `json:"queue"`
+	Type         string          `json:"type"`
+	Payload      json.RawMessage `json:"payload,omitempty"`
+	LastError    string          `json:"last_error,omitempty"`
+	LastFailedAt *time.Time      `json:"last_failed_at,omitempty"`
+	Retried      int             `json:"retried"`
+	MaxRetry     int             `json:"max_retry"`
+}
+
+// ListQueues handles GET /api/v1/admin/queues
+func (h *QueueHandler) ListQueues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	names, err := h.inspector.Queues()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list queues", slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list queues")
+		return
+	}
+	slices.Sort(names)
+
+	queues := make([]QueueStats, 0, len(names))
+	for _, name := range names {
+		info, err := h.inspector.GetQueueInfo(name)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get queue info",
+				slog.String("queue", name),
+				slog.String("error", err.Error()))
+			h.respondError(w, http.StatusInternalServerError, "Failed to get queue info")
+			return
+		}
+
+		queues = append(queues, QueueStats{
+			Name:      name,
+			Paused:    info.Paused,
+			Size:      info.Size,
+			Pending:   info.Pending,
+			Active:    info.Active,
+			Scheduled: info.Scheduled,
+			Retry:     info.Retry,
+			Archived:  info.Archived,
+			Completed: info.Completed,
+			Processed: info.Processed,
+			Failed:    info.Failed,
+		})
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"queues": queues,
+	})
+}
+
+// ListArchivedTasks handles GET /api/v1/admin/queues/{queue}/archived
+func (h *QueueHandler) ListArchivedTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	queue := r.PathValue("queue")
+
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	pageSize := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		pageSize = l
+	}
+
+	tasks, err := h.inspector.ListArchivedTasks(queue, asynq.Page(page), asynq.PageSize(pageSize))
+	if err != nil {
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			h.respondError(w, http.StatusNotFound, "Queue not found")
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to list archived tasks",
+			slog.String("queue", queue),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list archived tasks")
+		return
+	}
+
+	archived := make([]ArchivedTask, 0, len(tasks))
+	for _, t := range tasks {
+		task := ArchivedTask{
+			ID:        t.ID,
+			Queue:     t.Queue,
+			Type:      t.Type,
+			LastError: t.LastErr,
+			Retried:   t.Retried,
+			MaxRetry:  t.MaxRetry,
+		}
+		if json.Valid(t.Payload) {
+			task.Payload = t.Payload
+		}
+		if !t.LastFailedAt.IsZero() {
+			lastFailedAt := t.LastFailedAt
+			task.LastFailedAt = &lastFailedAt
+		}
+		archived = append(archived, task)
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"queue": queue,
+		"tasks": archived,
+		"page":  page,
+		"limit": pageSize,
+	})
+}
+
+// RetryTask handles POST /api/v1/admin/queues/{queue}/tasks/{id}/retry. The task is moved
+// back to pending so a worker picks it up again.
+func (h *QueueHandler) RetryTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	queue := r.PathValue("queue")
+	taskID := r.PathValue("id")
+
+	if err := h.inspector.RunTask(queue, taskID); err != nil {
+		if errors.Is(err, asynq.ErrQueueNotFound) || errors.Is(err, asynq.ErrTaskNotFound) {
+			h.respondError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to retry task",
+			slog.String("queue", queue),
+			slog.String("task_id", taskID),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to retry task")
+		return
+	}
+
+	h.logger.InfoContext(ctx, "task re-enqueued",
+		slog.String("queue", queue),
+		slog.String("task_id", taskID))
+
+	h.respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"queue":   queue,
+		"task_id": taskID,
+		"status":  "pending",
+	})
+}
+
+func (h *QueueHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *QueueHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,149 @@
+// internal/handlers/search.go
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	redis_a "github.com/ammerola/resell-be/internal/adapters/redis_adapter"
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+const (
+	// minSuggestPrefix is the shortest prefix that is worth querying for suggestions
+	minSuggestPrefix = 2
+	// defaultSuggestLimit and maxSuggestLimit bound the number of suggestions returned
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 25
+	// suggestCacheTTL is short since new items should show up in type-ahead quickly
+	suggestCacheTTL = time.Minute
+)
+
+// SearchHandler handles full-text search requests
+type SearchHandler struct {
+	service ports.InventoryService
+	cache   ports.CacheRepository
+	logger  *slog.Logger
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(service ports.InventoryService, cache ports.CacheRepository, logger *slog.Logger) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+		cache:   cache,
+		logger:  logger.With(slog.String("handler", "search")),
+	}
+}
+
+// SuggestResponse is the response body for GET /api/v1/search/suggest
+type SuggestResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+// Search handles GET /api/v1/search
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		h.respondError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	params := ports.ListParams{
+		Search:    query,
+		Page:      1,
+		PageSize:  50,
+		SortBy:    "relevance",
+		SortOrder: "desc",
+	}
+
+	if page := r.URL.Query().Get("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil && p > 0 {
+			params.Page = p
+		}
+	}
+
+	// Out-of-range limits fall back to the default page size
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 && l <= 100 {
+			params.PageSize = l
+		}
+	}
+
+	result, err := h.service.List(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to search inventory",
+			slog.String("query", query),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to search inventory")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// Suggest handles GET /api/v1/search/suggest
+func (h *SearchHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	prefix := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+
+	// Very short prefixes match too much to be useful, so skip the lookup entirely
+	if utf8.RuneCountInString(prefix) < minSuggestPrefix {
+		h.respondJSON(w, http.StatusOK, SuggestResponse{Suggestions: []string{}})
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= maxSuggestLimit {
+		limit = l
+	}
+
+	cacheKey := redis_a.BuildKey(redis_a.PrefixSearch, "suggest", strconv.Itoa(limit), prefix)
+	var suggestions []string
+
+	data, err := h.cache.GetOrSet(ctx, cacheKey, suggestCacheTTL, func() ([]byte, error) {
+		suggestions, err := h.service.Suggest(ctx, prefix, limit)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(suggestions)
+	})
+	if err == nil {
+		err = json.Unmarshal(data, &suggestions)
+	}
+
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to load suggestions",
+			slog.String("prefix", prefix),
+			slog.String("error", err.Error()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to load suggestions")
+		return
+	}
+
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+
+	h.respondJSON(w, http.StatusOK, SuggestResponse{Suggestions: suggestions})
+}
+
+func (h *SearchHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode JSON response",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (h *SearchHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,226 @@
+// internal/handlers/search_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/handlers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestSearchHandler_Search(t *testing.T) {
+	testItem := helpers.CreateTestInventoryItem()
+
+	tests := []struct {
+		name           string
+		queryParams    map[string]string
+		setupMocks     func(*mocks.MockInventoryService)
+		expectedStatus int
+		validateBody   func(*testing.T, []byte)
+	}{
+		{
+			name:        "searches_by_relevance",
+			queryParams: map[string]string{"q": "tea set"},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.Equal(t, "tea set", params.Search)
+						assert.Equal(t, "relevance", params.SortBy)
+						assert.Equal(t, 1, params.Page)
+						assert.Equal(t, 50, params.PageSize)
+						return &ports.ListResult{
+							Items:      []*domain.InventoryItem{testItem},
+							Page:       1,
+							PageSize:   50,
+							TotalCount: 1,
+							TotalPages: 1,
+						}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			validateBody: func(t *testing.T, body []byte) {
+				var response ports.ListResult
+				require.NoError(t, json.Unmarshal(body, &response))
+				require.Len(t, response.Items, 1)
+				assert.Equal(t, testItem.LotID, response.Items[0].LotID)
+				assert.Equal(t, int64(1), response.TotalCount)
+			},
+		},
+		{
+			name:        "supports_page_and_limit",
+			queryParams: map[string]string{"q": "lamp", "page": "3", "limit": "20"},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
+						assert.Equal(t, 3, params.Page)
+						assert.Equal(t, 20, params.PageSize)
+						return &ports.ListResult{Items: []*domain.InventoryItem{}, Page: 3, PageSize: 20}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "requires_query",
+			queryParams:    map[string]string{"q": "  "},
+			setupMocks:     func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			validateBody: func(t *testing.T, body []byte) {
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, "q is required", response["error"])
+			},
+		},
+		{
+			name:        "service_error",
+			queryParams: map[string]string{"q": "lamp"},
+			setupMocks: func(m *mocks.MockInventoryService) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			handler := handlers.NewSearchHandler(mockService, mocks.NewMockCacheRepository(ctrl), helpers.TestLogger())
+
+			tt.setupMocks(mockService)
+
+			query := url.Values{}
+			for k, v := range tt.queryParams {
+				query.Set(k, v)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/search?"+query.Encode(), nil)
+			w := httptest.NewRecorder()
+
+			handler.Search(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.validateBody != nil {
+				tt.validateBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+// fetchOnMiss makes a mocked GetOrSet behave like a cache miss, returning the loaded bytes
+func fetchOnMiss(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error),
+	opts ...ports.GetOrSetOption) ([]byte, error) {
+	return loader()
+}
+
+func TestSearchHandler_Suggest(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    map[string]string
+		setupMocks     func(*mocks.MockInventoryService, *mocks.MockCacheRepository)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "returns_suggestions_keyed_by_normalized_prefix",
+			queryParams: map[string]string{"q": "  Vict "},
+			setupMocks: func(s *mocks.MockInventoryService, c *mocks.MockCacheRepository) {
+				c.EXPECT().
+					GetOrSet(gomock.Any(), "search:suggest:10:vict", time.Minute, gomock.Any()).
+					DoAndReturn(fetchOnMiss)
+				s.EXPECT().
+					Suggest(gomock.Any(), "vict", 10).
+					Return([]string{"victorian", "Victorian Tea Set"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions":["victorian","Victorian Tea Set"]}`,
+		},
+		{
+			name:        "respects_limit",
+			queryParams: map[string]string{"q": "lamp", "limit": "5"},
+			setupMocks: func(s *mocks.MockInventoryService, c *mocks.MockCacheRepository) {
+				c.EXPECT().
+					GetOrSet(gomock.Any(), "search:suggest:5:lamp", gomock.Any(), gomock.Any()).
+					DoAndReturn(fetchOnMiss)
+				s.EXPECT().Suggest(gomock.Any(), "lamp", 5).Return([]string{"lamp"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions":["lamp"]}`,
+		},
+		{
+			name:        "no_matches_returns_empty_array",
+			queryParams: map[string]string{"q": "zz"},
+			setupMocks: func(s *mocks.MockInventoryService, c *mocks.MockCacheRepository) {
+				c.EXPECT().
+					GetOrSet(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(fetchOnMiss)
+				s.EXPECT().Suggest(gomock.Any(), "zz", 10).Return([]string{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions":[]}`,
+		},
+		{
+			name:           "prefix_below_minimum_length_skips_lookup",
+			queryParams:    map[string]string{"q": " v "},
+			setupMocks:     func(s *mocks.MockInventoryService, c *mocks.MockCacheRepository) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions":[]}`,
+		},
+		{
+			name:        "lookup_error",
+			queryParams: map[string]string{"q": "lamp"},
+			setupMocks: func(s *mocks.MockInventoryService, c *mocks.MockCacheRepository) {
+				c.EXPECT().
+					GetOrSet(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(fetchOnMiss)
+				s.EXPECT().Suggest(gomock.Any(), "lamp", 10).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			mockCache := mocks.NewMockCacheRepository(ctrl)
+			handler := handlers.NewSearchHandler(mockService, mockCache, helpers.TestLogger())
+
+			tt.setupMocks(mockService, mockCache)
+
+			query := url.Values{}
+			for k, v := range tt.queryParams {
+				query.Set(k, v)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/search/suggest?"+query.Encode(), nil)
+			w := httptest.NewRecorder()
+
+			handler.Suggest(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// internal/pkg/auth/token.go
+
+// Package auth signs and verifies the HS256 JSON Web Tokens used for API authentication
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is wrapped by every verification failure
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenType distinguishes short-lived access tokens from refresh tokens
+type TokenType string
+
+// TokenType constants
+const (
+	TokenAccess  TokenType = "access"
+	TokenRefresh TokenType = "refresh"
+)
+
+// Claims are the claims carried in a token. Only the user ID and role are stored so a leaked
+// token reveals nothing else. Times are Unix seconds; zero means the claim is absent.
+type Claims struct {
+	Subject   string    `json:"sub"`
+	Role      string    `json:"role,omitempty"`
+	Type      TokenType `json:"token_type,omitempty"`
+	IssuedAt  int64     `json:"iat,omitempty"`
+	ExpiresAt int64     `json:"exp,omitempty"`
+	NotBefore int64     `json:"nbf,omitempty"`
+}
+
+// header is the JOSE header of a compact JWT
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// Sign encodes claims as a compact JWT signed with HS256
+func Sign(claims Claims, secret string) (string, error) {
+	if secret == "" {
+		return "", errors.New("no signing secret configured")
+	}
+
+	h, err := encodeSegment(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token header: %w", err)
+	}
+	c, err := encodeSegment(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token claims: %w", err)
+	}
+
+	unsigned := h + "." + c
+	return unsigned + "." + signature(unsigned, secret), nil
+}
+
+// Parse verifies an HS256 token signed with secret and returns its claims.
+// Only HS256 is accepted so a token can't choose a weaker algorithm such as "none".
+func Parse(token, secret string, now time.Time) (*Claims, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("%w: no signing secret configured", ErrInvalidToken)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 segments, got %d", ErrInvalidToken, len(parts))
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if h.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, h.Alg)
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(signature(parts[0]+"."+parts[1], secret))) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != 0 && now.Unix() < claims.NotBefore {
+		return nil, fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+
+	return &claims, nil
+}
+
+// signature returns the base64url HMAC-SHA256 of the signing input
+func signature(unsigned, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// encodeSegment encodes v as a base64url JSON segment
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeSegment decodes a base64url JSON segment into v
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -3,12 +3,15 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ammerola/resell-be/internal/pkg/logger"
@@ -19,6 +22,10 @@ import (
 // ErrMissingRequiredConfig indicates a required configuration value is missing
 var ErrMissingRequiredConfig = errors.New("missing required configuration")
 
+// defaultCSRFExemptPaths are the routes authenticated by a bearer token or API key, which a
+// browser never sends on its own and so can't be forged cross-site
+var defaultCSRFExemptPaths = []string{"/api/v1/auth/", "/api/v1/inventory", "/api/v1/import/", "/api/v1/admin/"}
+
 // Config holds all application configuration
 type Config struct {
 	// Application
@@ -36,9 +43,15 @@ type Config struct {
 	// AWS
 	AWS AWSConfig
 
+	// Email
+	Email EmailConfig
+
 	// File Processing
 	FileProcessing FileProcessingConfig
 
+	// Data Retention
+	Retention RetentionConfig
+
 	// Security
 	Security SecurityConfig
 
@@ -71,6 +84,10 @@ type AppConfig struct {
 	LogLevel    string
 	LogFormat   string `validate:"oneof=json text"`
 	Debug       bool
+	QRTemplate  string // Content of item QR codes, with {lot_id} replaced by the lot ID
+
+	// How long an Idempotency-Key on inventory creation is remembered
+	IdempotencyTTL time.Duration
 }
 
 // DatabaseConfig holds database configuration
@@ -120,11 +137,25 @@ type SecurityConfig struct {
 	BcryptCost           int `validate:"min=10,max=15"`
 	RateLimitRequests    int `validate:"min=1"`
 	RateLimitDuration    time.Duration
+	RateLimitBurst       int                       // Back-to-back requests allowed; 0 uses RateLimitRequests
+	RateLimitRoutes      map[string]RateLimitRoute // Path prefix -> limit overriding the global one
 	AllowedOrigins       []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool // Sent only to explicitly allowed origins, never with "*"
 	TrustedProxies       []string
 	SecureHeaders        bool
 	CSRFProtection       bool
+	CSRFExemptPaths      []string // Path prefixes skipped by CSRF checks, e.g. bearer-token API routes
 	RequestIDHeader      string
+	AdminAPIKey          string `sensitive:"true"`
+	RequireAuthForReads  bool   // Also require a bearer token on GET routes; writes always require one
+}
+
+// RateLimitRoute is a per-route rate limit, applied over Security.RateLimitDuration
+type RateLimitRoute struct {
+	Requests int
+	Burst    int // 0 uses Requests
 }
 
 // AsynqConfig holds Asynq configuration
@@ -133,12 +164,24 @@ type AsynqConfig struct {
 	RedisPassword        string
 	RedisDB              int
 	Concurrency          int
-	Queues               map[string]int // queue name -> priority
+	Queues               map[string]int        // queue name -> priority
+	QueueLimits          map[string]QueueLimit // queue name -> limits on top of Concurrency
 	StrictPriority       bool
 	RetryMax             int
+	RetryBaseDelay       time.Duration // first retry delay, doubled on each retry
+	RetryMaxDelay        time.Duration
 	ShutdownTimeout      time.Duration
 	HealthCheckInterval  time.Duration
 	DelayedTaskCheckTime time.Duration
+	AnalyticsCron        string // empty disables the scheduled analytics refresh
+	CleanupCron          string // empty disables the scheduled temp file cleanup
+	RetentionCron        string // empty disables the scheduled retention purge
+}
+
+// QueueLimit caps how much of the worker a single queue may use
+type QueueLimit struct {
+	Concurrency   int // tasks from the queue running at once; 0 is bounded only by Concurrency
+	RatePerMinute int // tasks from the queue started per minute; 0 is unlimited
 }
 
 // AWSConfig holds AWS configuration
@@ -151,13 +194,33 @@ type AWSConfig struct {
 	UsePathStyle    bool   // For MinIO compatibility
 }
 
+// EmailConfig holds outbound email configuration
+type EmailConfig struct {
+	Sender      string // ses, log
+	FromAddress string
+}
+
 // FileProcessingConfig holds file processing configuration
 type FileProcessingConfig struct {
-	PDFMaxSizeMB      int
-	ExcelMaxSizeMB    int
-	ProcessingTimeout time.Duration
-	TempDir           string
-	CleanupInterval   time.Duration
+	PDFMaxSizeMB          int
+	ExcelMaxSizeMB        int
+	ProcessingTimeout     time.Duration
+	TempDir               string
+	CleanupInterval       time.Duration
+	ExportStreamThreshold int    // Row count above which Excel exports are streamed; 0 disables streaming
+	ProgressInterval      int    // Items saved between import job progress updates
+	EnableOCR             bool   // OCR pages with no text layer (requires tesseract and pdftoppm)
+	StorageBackend        string // Where uploads and report artifacts are stored: s3 or local
+	LocalStoragePath      string // Root directory for the local backend; must be shared by API and worker
+	InvoiceProfilesFile   string // JSON invoice parsing profiles for other auction houses; empty uses only the default
+}
+
+// RetentionConfig controls how long CleanupOldData keeps data; a zero period keeps it forever
+type RetentionConfig struct {
+	SoftDeleted  time.Duration // soft-deleted inventory rows, measured from deleted_at
+	Jobs         time.Duration // finished async_jobs rows, measured from completion
+	ActivityLogs time.Duration
+	BatchSize    int // rows removed per statement, so no delete holds locks for long
 }
 
 // ServerConfig holds HTTP server configuration
@@ -168,40 +231,44 @@ type ServerConfig struct {
 	WriteTimeout      time.Duration
 	IdleTimeout       time.Duration
 	MaxHeaderBytes    int
+	MaxBodyBytes      int // Cap on JSON item bodies for inventory create and update
 	GracefulTimeout   time.Duration
+	RequestTimeout    time.Duration // Handler deadline; 0 disables it
+	TimeoutExempt     []string      // Path prefixes that may run past RequestTimeout, e.g. uploads and exports
+	ReadinessTimeout  time.Duration // Deadline for each dependency check on /ready
 	EnablePprof       bool
 	EnableMetrics     bool
 	EnableHealthCheck bool
+	EnableCompression bool // Gzip responses for clients that accept it
 	TLSEnabled        bool
 	TLSCertFile       string
 	TLSKeyFile        string
 }
 
-// OutputConfig defines logging output destinations
-type OutputConfig struct {
-	Type    string         `json:"type"` // console, file, elasticsearch, datadog, etc.
-	Level   string         `json:"level"`
-	Format  string         `json:"format"`
-	Options map[string]any `json:"options"`
-}
-
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level            string           `json:"level"`
-	Format           string           `json:"format"`
-	EnableSampling   bool             `json:"enable_sampling"`
-	SampleRate       float64          `json:"sample_rate"`
-	EnableELK        bool             `json:"enable_elk"`
-	ELKConfig        logger.ELKConfig `json:"elk"`
-	EnableStackTrace bool             `json:"enable_stack_trace"`
-	Outputs          []OutputConfig   `json:"outputs"`
+	Level            string                `json:"level"`
+	Format           string                `json:"format"`
+	EnableSampling   bool                  `json:"enable_sampling"`
+	SampleRate       float64               `json:"sample_rate"`
+	EnableELK        bool                  `json:"enable_elk"`
+	ELKConfig        logger.ELKConfig      `json:"elk"`
+	EnableStackTrace bool                  `json:"enable_stack_trace"`
+	Outputs          []logger.OutputConfig `json:"outputs"` // replace stdout when set
 }
 
+// processEnv records the variables set before .env was first loaded
+var (
+	processEnv     = make(map[string]bool)
+	processEnvOnce sync.Once
+)
+
 // ConfigLoader handles configuration loading with secrets management
 type ConfigLoader struct {
 	logger         *slog.Logger
 	secretsManager SecretsManager
 	validators     []Validator
+	allowMissing   bool
 }
 
 // SecretsManager interface for different secret providers
@@ -224,6 +291,13 @@ func NewConfigLoader(logger *slog.Logger) *ConfigLoader {
 	}
 }
 
+// AllowMissing lets configuration load with unset required environment variables, which are
+// left as MISSING_ placeholders. Meant for development, when only part of the stack is running.
+func (cl *ConfigLoader) AllowMissing(allow bool) *ConfigLoader {
+	cl.allowMissing = allow
+	return cl
+}
+
 // Load loads configuration from environment and secrets
 func Load(logger *slog.Logger) (*Config, error) {
 	loader := NewConfigLoader(logger)
@@ -272,6 +346,11 @@ func (cl *ConfigLoader) Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	if missing := missingEnvVars(cfg); len(missing) > 0 {
+		cl.logger.Warn("starting with unset environment variables",
+			slog.Any("missing", missing))
+	}
+
 	// Log configuration summary (without sensitive data)
 	cl.logConfigSummary(cfg)
 
@@ -280,9 +359,25 @@ func (cl *ConfigLoader) Load(ctx context.Context) (*Config, error) {
 
 // loadEnvFile loads .env file for development
 func (cl *ConfigLoader) loadEnvFile() error {
-	if err := godotenv.Load(); err != nil {
+	values, err := godotenv.Read()
+	if err != nil {
 		return err
 	}
+
+	// Variables set in the process environment win over .env, but values that came from the
+	// file are overwritten so a reload picks up edits to it
+	processEnvOnce.Do(func() {
+		for _, kv := range os.Environ() {
+			key, _, _ := strings.Cut(kv, "=")
+			processEnv[key] = true
+		}
+	})
+	for key, value := range values {
+		if !processEnv[key] {
+			os.Setenv(key, value)
+		}
+	}
+
 	cl.logger.Info(".env file loaded successfully")
 	return nil
 }
@@ -297,6 +392,9 @@ func (cl *ConfigLoader) buildConfig(env string) *Config {
 			LogLevel:    getEnv("LOG_LEVEL", cl.getDefaultLogLevel(env)),
 			LogFormat:   getEnv("LOG_FORMAT", "json"),
 			Debug:       getBoolEnv("APP_DEBUG", env == "development"),
+			QRTemplate:  getEnv("QR_URL_TEMPLATE", "{lot_id}"),
+
+			IdempotencyTTL: getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
 		},
 		Database: DatabaseConfig{
 			Host:               getEnvRequired("DB_HOST", env),
@@ -340,11 +438,22 @@ func (cl *ConfigLoader) buildConfig(env string) *Config {
 			BcryptCost:           getIntEnv("BCRYPT_COST", cl.getDefaultBcryptCost(env)),
 			RateLimitRequests:    getIntEnv("RATE_LIMIT_REQUESTS", 100),
 			RateLimitDuration:    getDurationEnv("RATE_LIMIT_DURATION", time.Minute),
+			RateLimitBurst:       getIntEnv("RATE_LIMIT_BURST", 0),
+			RateLimitRoutes:      parseRateLimitRoutes(getEnv("RATE_LIMIT_ROUTES", "/api/v1/export/=10:3")),
 			AllowedOrigins:       getSliceEnv("ALLOWED_ORIGINS", cl.getDefaultAllowedOrigins(env)),
+			CORSAllowedMethods:   getSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			CORSAllowedHeaders: getSliceEnv("CORS_ALLOWED_HEADERS", []string{
+				"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization",
+				"X-Request-ID", "X-API-Key", "X-CSRF-Token", "Idempotency-Key",
+			}),
+			CORSAllowCredentials: getBoolEnv("CORS_ALLOW_CREDENTIALS", true),
 			TrustedProxies:       getSliceEnv("TRUSTED_PROXIES", []string{}),
 			SecureHeaders:        getBoolEnv("SECURE_HEADERS", env == "production"),
 			CSRFProtection:       getBoolEnv("CSRF_PROTECTION", env == "production"),
+			CSRFExemptPaths:      getSliceEnv("CSRF_EXEMPT_PATHS", defaultCSRFExemptPaths),
 			RequestIDHeader:      getEnv("REQUEST_ID_HEADER", "X-Request-ID"),
+			AdminAPIKey:          getEnv("ADMIN_API_KEY", ""),
+			RequireAuthForReads:  getBoolEnv("REQUIRE_AUTH_FOR_READS", false),
 		},
 		Secrets: SecretsConfig{
 			Provider:        getEnv("SECRETS_PROVIDER", cl.getDefaultSecretsProvider(env)),
@@ -361,11 +470,17 @@ func (cl *ConfigLoader) buildConfig(env string) *Config {
 			RedisDB:              getIntEnv("ASYNQ_REDIS_DB", 0),
 			Concurrency:          getIntEnv("ASYNQ_CONCURRENCY", 10),
 			Queues:               parseQueues(getEnv("ASYNQ_QUEUES", "critical:6,default:3,low:1")),
+			QueueLimits:          parseQueueLimits(getEnv("ASYNQ_QUEUE_LIMITS", "")),
 			StrictPriority:       getBoolEnv("ASYNQ_STRICT_PRIORITY", false),
 			RetryMax:             getIntEnv("ASYNQ_RETRY_MAX", 3),
+			RetryBaseDelay:       getDurationEnv("ASYNQ_RETRY_BASE_DELAY", time.Second),
+			RetryMaxDelay:        getDurationEnv("ASYNQ_RETRY_MAX_DELAY", 10*time.Minute),
 			ShutdownTimeout:      getDurationEnv("ASYNQ_SHUTDOWN_TIMEOUT", 30*time.Second),
 			HealthCheckInterval:  getDurationEnv("ASYNQ_HEALTH_CHECK_INTERVAL", 30*time.Second),
 			DelayedTaskCheckTime: getDurationEnv("ASYNQ_DELAYED_TASK_CHECK", 5*time.Second),
+			AnalyticsCron:        getCronEnv("ASYNQ_ANALYTICS_CRON", "0 * * * *"),
+			CleanupCron:          getCronEnv("ASYNQ_CLEANUP_CRON", "30 3 * * *"),
+			RetentionCron:        getCronEnv("ASYNQ_RETENTION_CRON", "0 4 * * *"),
 		},
 		AWS: AWSConfig{
 			Region:          getEnv("AWS_REGION", "us-east-1"),
@@ -375,12 +490,28 @@ func (cl *ConfigLoader) buildConfig(env string) *Config {
 			S3Endpoint:      getEnv("AWS_S3_ENDPOINT", ""),
 			UsePathStyle:    getBoolEnv("AWS_S3_PATH_STYLE", env == "development"),
 		},
+		Email: EmailConfig{
+			Sender:      getEnv("EMAIL_SENDER", cl.getDefaultEmailSender(env)),
+			FromAddress: getEnv("EMAIL_FROM", "noreply@resell.com"),
+		},
 		FileProcessing: FileProcessingConfig{
-			PDFMaxSizeMB:      getIntEnv("PDF_MAX_SIZE_MB", 50),
-			ExcelMaxSizeMB:    getIntEnv("EXCEL_MAX_SIZE_MB", 100),
-			ProcessingTimeout: getDurationEnv("PROCESSING_TIMEOUT", 5*time.Minute),
-			TempDir:           getEnv("TEMP_DIR", "/tmp"),
-			CleanupInterval:   getDurationEnv("CLEANUP_INTERVAL", time.Hour),
+			PDFMaxSizeMB:          getIntEnv("PDF_MAX_SIZE_MB", 50),
+			ExcelMaxSizeMB:        getIntEnv("EXCEL_MAX_SIZE_MB", 100),
+			ProcessingTimeout:     getDurationEnv("PROCESSING_TIMEOUT", 5*time.Minute),
+			TempDir:               getEnv("TEMP_DIR", "/tmp"),
+			StorageBackend:        getEnv("STORAGE_BACKEND", cl.getDefaultStorageBackend(env)),
+			LocalStoragePath:      getEnv("LOCAL_STORAGE_PATH", "/tmp/resell-storage"),
+			CleanupInterval:       getDurationEnv("CLEANUP_INTERVAL", time.Hour),
+			ExportStreamThreshold: getIntEnv("EXPORT_STREAM_THRESHOLD", 10000),
+			ProgressInterval:      getIntEnv("JOB_PROGRESS_INTERVAL", 100),
+			EnableOCR:             getBoolEnv("ENABLE_OCR", false),
+			InvoiceProfilesFile:   getEnv("INVOICE_PROFILES_FILE", ""),
+		},
+		Retention: RetentionConfig{
+			SoftDeleted:  getRetentionEnv("CLEANUP_SOFT_DELETE_RETENTION", 90*24*time.Hour),
+			Jobs:         getRetentionEnv("CLEANUP_JOB_RETENTION", 30*24*time.Hour),
+			ActivityLogs: getRetentionEnv("CLEANUP_ACTIVITY_LOG_RETENTION", 90*24*time.Hour),
+			BatchSize:    getIntEnv("CLEANUP_BATCH_SIZE", 1000),
 		},
 		Server: ServerConfig{
 			Host:              getEnv("SERVER_HOST", "0.0.0.0"),
@@ -389,10 +520,15 @@ func (cl *ConfigLoader) buildConfig(env string) *Config {
 			WriteTimeout:      getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
 			IdleTimeout:       getDurationEnv("SERVER_IDLE_TIMEOUT", 60*time.Second),
 			MaxHeaderBytes:    getIntEnv("SERVER_MAX_HEADER_BYTES", 1<<20), // 1 MB
+			MaxBodyBytes:      getIntEnv("SERVER_MAX_BODY_BYTES", 1<<20),   // 1 MB
 			GracefulTimeout:   getDurationEnv("SERVER_GRACEFUL_TIMEOUT", 30*time.Second),
+			RequestTimeout:    getDurationEnv("SERVER_REQUEST_TIMEOUT", 10*time.Second),
+			TimeoutExempt:     getSliceEnv("SERVER_TIMEOUT_EXEMPT_PATHS", []string{"/api/v1/import/", "/api/v1/export/", "/api/v1/files/"}),
+			ReadinessTimeout:  getDurationEnv("SERVER_READINESS_TIMEOUT", 2*time.Second),
 			EnablePprof:       getBoolEnv("ENABLE_PPROF", env == "development"),
 			EnableMetrics:     getBoolEnv("ENABLE_METRICS", true),
 			EnableHealthCheck: getBoolEnv("ENABLE_HEALTH_CHECK", true),
+			EnableCompression: getBoolEnv("ENABLE_COMPRESSION", true),
 			TLSEnabled:        getBoolEnv("TLS_ENABLED", false),
 			TLSCertFile:       getEnv("TLS_CERT_FILE", ""),
 			TLSKeyFile:        getEnv("TLS_KEY_FILE", ""),
@@ -413,6 +549,12 @@ func (cl *ConfigLoader) buildConfig(env string) *Config {
 				Password:         getEnv("LOG_ELK_PASS", ""),
 				EnableBatching:   getBoolEnv("LOG_ELK_BATCHING_ENABLE", true),
 			},
+			Outputs: parseLogOutputs(getEnv("LOG_OUTPUTS", ""), map[string]any{
+				"max_size_mb":  getIntEnv("LOG_FILE_MAX_SIZE_MB", 100),
+				"max_backups":  getIntEnv("LOG_FILE_MAX_BACKUPS", 5),
+				"max_age_days": getIntEnv("LOG_FILE_MAX_AGE_DAYS", 0),
+				"compress":     getBoolEnv("LOG_FILE_COMPRESS", false),
+			}),
 		},
 	}
 }
@@ -427,10 +569,12 @@ func (cl *ConfigLoader) initializeSecretsManager(ctx context.Context, cfg *Confi
 		}
 		cl.secretsManager = sm
 	case "vault":
-		sm, err := NewVaultSecretsManager(cfg.Secrets.VaultAddr, cfg.Secrets.VaultToken, cfg.Secrets.VaultPath, cl.logger)
+		sm, err := NewVaultSecretsManager(cfg.Secrets.VaultAddr, cfg.Secrets.VaultToken, cfg.Secrets.VaultPath, cfg.Secrets.RefreshInterval, cl.logger)
 		if err != nil {
 			return err
 		}
+		// Keeps the cached secrets fresh and the token renewed for the life of the process
+		go sm.Run(ctx)
 		cl.secretsManager = sm
 	case "env", "":
 		cl.secretsManager = NewEnvSecretsManager()
@@ -440,6 +584,18 @@ func (cl *ConfigLoader) initializeSecretsManager(ctx context.Context, cfg *Confi
 	return nil
 }
 
+// SecretsWatcher returns a watcher over the loaded secrets provider, or nil when secrets come
+// from the environment and can't change while the process runs
+func (cl *ConfigLoader) SecretsWatcher(cfg *Config) *SecretsWatcher {
+	if cl.secretsManager == nil {
+		return nil
+	}
+	if _, ok := cl.secretsManager.(*EnvSecretsManager); ok {
+		return nil
+	}
+	return NewSecretsWatcher(cl.secretsManager, cfg.Secrets.RefreshInterval, cl.logger)
+}
+
 // loadSecrets loads secrets from the configured provider
 func (cl *ConfigLoader) loadSecrets(ctx context.Context, cfg *Config) error {
 	if cl.secretsManager == nil {
@@ -467,6 +623,9 @@ func (cl *ConfigLoader) loadSecrets(ctx context.Context, cfg *Config) error {
 	if val, ok := secrets["JWT_SECRET"]; ok && val != "" {
 		cfg.Security.JWTSecret = val
 	}
+	if val, ok := secrets["ADMIN_API_KEY"]; ok && val != "" {
+		cfg.Security.AdminAPIKey = val
+	}
 	if val, ok := secrets["REDIS_PASSWORD"]; ok && val != "" {
 		cfg.Redis.Password = val
 	}
@@ -483,7 +642,7 @@ func (cl *ConfigLoader) loadSecrets(ctx context.Context, cfg *Config) error {
 // addValidators adds appropriate validators based on environment
 func (cl *ConfigLoader) addValidators(env string) {
 	// Always add basic validator
-	cl.validators = append(cl.validators, &BasicValidator{})
+	cl.validators = append(cl.validators, &BasicValidator{AllowMissing: cl.allowMissing})
 
 	// Add production validator for production/staging
 	if env == "production" || env == "staging" {
@@ -494,14 +653,24 @@ func (cl *ConfigLoader) addValidators(env string) {
 	cl.validators = append(cl.validators, &SecurityValidator{})
 }
 
-// validateConfig runs all validators
+// validateConfig runs all validators, collecting every problem into a ConfigValidationError
+// and logging each one so they can be fixed in a single pass
 func (cl *ConfigLoader) validateConfig(cfg *Config) error {
+	errs := &ConfigValidationError{}
 	for _, validator := range cl.validators {
 		if err := validator.Validate(cfg); err != nil {
-			return err
+			errs.merge(err)
 		}
 	}
-	return nil
+
+	for _, field := range errs.Fields {
+		cl.logger.Error("invalid configuration",
+			slog.String("field", field.Field),
+			slog.String("rule", field.Rule),
+			slog.String("message", field.Message))
+	}
+
+	return errs.ErrOrNil()
 }
 
 // logConfigSummary logs configuration summary without sensitive data
@@ -567,6 +736,20 @@ func (cl *ConfigLoader) getDefaultSecretsProvider(env string) string {
 	return "env"
 }
 
+func (cl *ConfigLoader) getDefaultStorageBackend(env string) string {
+	if env == "production" || env == "staging" {
+		return "s3"
+	}
+	return "local"
+}
+
+func (cl *ConfigLoader) getDefaultEmailSender(env string) string {
+	if env == "production" || env == "staging" {
+		return "ses"
+	}
+	return "log"
+}
+
 // Helper functions remain the same but with better handling for required values
 func getEnvRequired(key string, env string) string {
 	value := os.Getenv(key)
@@ -576,7 +759,7 @@ func getEnvRequired(key string, env string) string {
 	}
 	// In development, return a placeholder that will trigger validation warning
 	if value == "" {
-		return fmt.Sprintf("MISSING_%s", key)
+		return missingPrefix + key
 	}
 	return value
 }
@@ -589,6 +772,15 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getCronEnv reads a cron spec; "off" disables the schedule
+func getCronEnv(key, defaultValue string) string {
+	value := getEnv(key, defaultValue)
+	if strings.EqualFold(value, "off") {
+		return ""
+	}
+	return value
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		b, err := strconv.ParseBool(value)
@@ -629,6 +821,40 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// getRetentionEnv reads a retention period as whole days ("90d") or a Go duration;
+// "off" keeps data forever
+func getRetentionEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if strings.EqualFold(value, "off") {
+		return 0
+	}
+	if d, err := parseRetention(value); err == nil {
+		return d
+	}
+	return defaultValue
+}
+
+// parseRetention parses "<n>d" as n days and anything else with time.ParseDuration
+func parseRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid retention period %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid retention period %q", value)
+	}
+	return d, nil
+}
+
 func getSliceEnv(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		return strings.Split(value, ",")
@@ -653,6 +879,27 @@ func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.Port)
 }
 
+// LogConfig returns the logger settings for c. Elasticsearch outputs take their defaults
+// from the LOG_ELK_* settings.
+func (c *Config) LogConfig() *logger.LogConfig {
+	logConfig := logger.NewLogConfig(c.App.LogLevel, c.App.LogFormat)
+	logConfig.EnableSampling = c.Logging.EnableSampling
+	logConfig.SampleRate = c.Logging.SampleRate
+
+	for _, output := range c.Logging.Outputs {
+		if output.Type == "elasticsearch" {
+			options := make(map[string]any)
+			if data, err := json.Marshal(c.Logging.ELKConfig); err == nil {
+				_ = json.Unmarshal(data, &options)
+			}
+			maps.Copy(options, output.Options)
+			output.Options = options
+		}
+		logConfig.Outputs = append(logConfig.Outputs, output)
+	}
+	return logConfig
+}
+
 func (c *Config) IsProduction() bool {
 	return c.App.Environment == "production"
 }
@@ -679,3 +926,107 @@ func parseQueues(queuesStr string) map[string]int {
 	}
 	return queues
 }
+
+// parseQueueLimits parses "queue:concurrency[:per_minute]" pairs, skipping malformed entries
+func parseQueueLimits(limitsStr string) map[string]QueueLimit {
+	limits := make(map[string]QueueLimit)
+	for _, pair := range strings.Split(limitsStr, ",") {
+		parts := strings.Split(pair, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			continue
+		}
+
+		concurrency, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || concurrency < 0 {
+			continue
+		}
+		limit := QueueLimit{Concurrency: concurrency}
+		if len(parts) == 3 {
+			perMinute, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+			if err != nil || perMinute < 0 {
+				continue
+			}
+			limit.RatePerMinute = perMinute
+		}
+		if limit.Concurrency == 0 && limit.RatePerMinute == 0 {
+			continue
+		}
+		limits[name] = limit
+	}
+	return limits
+}
+
+// parseRateLimitRoutes parses "prefix=requests[:burst]" pairs, skipping malformed entries
+func parseRateLimitRoutes(routesStr string) map[string]RateLimitRoute {
+	routes := make(map[string]RateLimitRoute)
+	for _, pair := range strings.Split(routesStr, ",") {
+		prefix, limit, ok := strings.Cut(pair, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || prefix == "" {
+			continue
+		}
+
+		requestsStr, burstStr, hasBurst := strings.Cut(limit, ":")
+		requests, err := strconv.Atoi(strings.TrimSpace(requestsStr))
+		if err != nil || requests <= 0 {
+			continue
+		}
+		route := RateLimitRoute{Requests: requests}
+		if hasBurst {
+			burst, err := strconv.Atoi(strings.TrimSpace(burstStr))
+			if err != nil || burst < 0 {
+				continue
+			}
+			route.Burst = burst
+		}
+		routes[prefix] = route
+	}
+	return routes
+}
+
+// parseLogOutputs parses "type[:level[:format[:target]]]" entries, e.g.
+// "console:debug:text,file:info:json:/var/log/resell/api.log". The target is the console
+// stream, the file name or the Elasticsearch URL. File outputs get fileOptions, their rotation
+// settings. Unknown types are kept so validation can report them.
+func parseLogOutputs(outputsStr string, fileOptions map[string]any) []logger.OutputConfig {
+	var outputs []logger.OutputConfig
+	for _, entry := range strings.Split(outputsStr, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 4)
+		for len(parts) < 4 {
+			parts = append(parts, "")
+		}
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if parts[0] == "" {
+			continue
+		}
+
+		output := logger.OutputConfig{
+			Type:    parts[0],
+			Level:   parts[1],
+			Format:  parts[2],
+			Options: make(map[string]any),
+		}
+		target := parts[3]
+		switch output.Type {
+		case "console":
+			if target != "" {
+				output.Options["stream"] = target
+			}
+		case "file":
+			maps.Copy(output.Options, fileOptions)
+			output.Options["filename"] = target
+		case "elasticsearch":
+			if target != "" {
+				output.Options["elasticsearch_url"] = target
+			}
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
@@ -0,0 +1,202 @@
+// internal/pkg/config/config_test.go
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+func TestParseQueueLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]config.QueueLimit
+	}{
+		{
+			name:  "concurrency_only",
+			input: "default:5,low:2",
+			expected: map[string]config.QueueLimit{
+				"default": {Concurrency: 5},
+				"low":     {Concurrency: 2},
+			},
+		},
+		{
+			name:  "with_rate_per_minute",
+			input: "default:3:30, low : 0 : 10",
+			expected: map[string]config.QueueLimit{
+				"default": {Concurrency: 3, RatePerMinute: 30},
+				"low":     {RatePerMinute: 10},
+			},
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: map[string]config.QueueLimit{},
+		},
+		{
+			name:  "skips_malformed_entries",
+			input: "default,low:x,critical:-1,:4,bulk:2:y,reports:1:2:3,idle:0,slow:0:-5,ok:1",
+			expected: map[string]config.QueueLimit{
+				"ok": {Concurrency: 1},
+			},
+		},
+		{
+			name:  "later_entry_wins",
+			input: "default:5,default:2",
+			expected: map[string]config.QueueLimit{
+				"default": {Concurrency: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, config.ParseQueueLimits(tt.input))
+		})
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "90d", expected: 90 * 24 * time.Hour},
+		{input: "0d", expected: 0},
+		{input: "36h", expected: 36 * time.Hour},
+		{input: " 7d ", expected: 7 * 24 * time.Hour},
+		{input: "d", wantErr: true},
+		{input: "-3d", wantErr: true},
+		{input: "1.5d", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "ninety days", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := config.ParseRetention(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestBasicValidator_QueueLimits(t *testing.T) {
+	cfg := validConfig()
+	cfg.Asynq.QueueLimits = map[string]config.QueueLimit{"default": {Concurrency: 2}}
+	assert.NoError(t, (&config.BasicValidator{}).Validate(cfg))
+
+	cfg.Asynq.QueueLimits["pdf"] = config.QueueLimit{RatePerMinute: 10}
+	assert.ErrorContains(t, (&config.BasicValidator{}).Validate(cfg), `unknown queue "pdf"`)
+}
+
+func TestBasicValidator_MissingEnvVars(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.Host = "MISSING_DB_HOST"
+	cfg.Redis.Port = "MISSING_REDIS_PORT"
+
+	err := (&config.BasicValidator{}).Validate(cfg)
+	require.ErrorIs(t, err, config.ErrMissingRequiredConfig)
+	assert.ErrorContains(t, err, "DB_HOST, REDIS_PORT")
+	assert.ErrorContains(t, err, "--allow-missing")
+
+	assert.NoError(t, (&config.BasicValidator{AllowMissing: true}).Validate(cfg))
+}
+
+func TestParseLogOutputs(t *testing.T) {
+	outputs := config.ParseLogOutputs("console:debug:text, file:info:json:/var/log/resell/api.log,elasticsearch::json:http://es:9200,,")
+	require.Len(t, outputs, 3)
+
+	assert.Equal(t, logger.OutputConfig{Type: "console", Level: "debug", Format: "text", Options: map[string]any{}}, outputs[0])
+	assert.Equal(t, logger.OutputConfig{Type: "file", Level: "info", Format: "json", Options: map[string]any{
+		"filename": "/var/log/resell/api.log", "max_size_mb": 100, "max_backups": 5,
+	}}, outputs[1])
+	assert.Equal(t, "http://es:9200", outputs[2].Options["elasticsearch_url"], "the target keeps its colons")
+	assert.Empty(t, outputs[2].Level)
+
+	assert.Empty(t, config.ParseLogOutputs(""))
+}
+
+func TestBasicValidator_LogOutputs(t *testing.T) {
+	cfg := validConfig()
+	cfg.Logging.Outputs = config.ParseLogOutputs("console:debug:text,file:info")
+
+	err := (&config.BasicValidator{}).Validate(cfg)
+	assert.ErrorContains(t, err, "Logging.Outputs[1]: file log output needs a file name")
+
+	cfg.Logging.Outputs = config.ParseLogOutputs("syslog:verbose")
+	var cve *config.ConfigValidationError
+	require.ErrorAs(t, (&config.BasicValidator{}).Validate(cfg), &cve)
+	assert.Len(t, cve.Fields, 2, "both the type and the level are reported")
+
+	cfg.Logging.Outputs = config.ParseLogOutputs("file:info:json:/var/log/resell/api.log")
+	cfg.Logging.Outputs[0].Options["max_size_mb"] = 0
+	assert.ErrorContains(t, (&config.BasicValidator{}).Validate(cfg), "max_size_mb must be positive")
+}
+
+func TestBasicValidator_ReportsEveryViolation(t *testing.T) {
+	cfg := validConfig()
+	cfg.Redis.PoolSize = 0
+	cfg.Email.Sender = "smtp"
+	cfg.Retention.BatchSize = 0
+	cfg.Database.Host = ""
+
+	err := (&config.BasicValidator{}).Validate(cfg)
+
+	var cve *config.ConfigValidationError
+	require.ErrorAs(t, err, &cve)
+	assert.Equal(t, []config.FieldError{
+		{Field: "Database.Host", Rule: "required", Message: "missing required configuration", Err: config.ErrMissingRequiredConfig},
+		{Field: "Redis.PoolSize", Rule: "positive", Message: "redis pool_size must be positive"},
+		{Field: "Email.Sender", Rule: "oneof", Message: "email sender must be one of: ses, log"},
+		{Field: "Retention.BatchSize", Rule: "positive", Message: "cleanup batch size must be positive"},
+	}, cve.Fields)
+	assert.ErrorIs(t, err, config.ErrMissingRequiredConfig)
+	assert.ErrorContains(t, err, "4 configuration problem(s)")
+}
+
+func TestValidateConfig_AggregatesAcrossValidators(t *testing.T) {
+	cfg := validConfig()
+	cfg.Redis.PoolSize = 0
+	cfg.Database.SSLMode = "disable"
+	cfg.Security.CSRFProtection = false
+	cfg.Security.BcryptCost = 4
+
+	err := config.ValidateWith(cfg, &config.BasicValidator{}, &config.ProductionValidator{}, &config.SecurityValidator{})
+
+	var cve *config.ConfigValidationError
+	require.ErrorAs(t, err, &cve)
+	fields := make([]string, 0, len(cve.Fields))
+	for _, field := range cve.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.Subset(t, fields, []string{"Redis.PoolSize", "Database.SSLMode", "Security.CSRFProtection", "Security.BcryptCost"},
+		"problems from every validator are reported together")
+
+	assert.NoError(t, config.ValidateWith(validConfig(), &config.BasicValidator{}, &config.SecurityValidator{}))
+}
+
+func validConfig() *config.Config {
+	cfg := helpers.LoadTestConfig()
+	cfg.FileProcessing.StorageBackend = "local"
+	cfg.Email.Sender = "log"
+	cfg.Asynq.Queues = map[string]int{"default": 1}
+	cfg.Asynq.RetryBaseDelay = time.Second
+	cfg.Asynq.RetryMaxDelay = time.Minute
+	cfg.Retention.BatchSize = 100
+	cfg.Security.JWTSecret = "a-test-secret-that-is-at-least-32-chars"
+	cfg.Security.BcryptCost = 10
+	return cfg
+}
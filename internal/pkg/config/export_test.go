@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// ParseQueueLimits exposes queue limit parsing to external tests
+func ParseQueueLimits(limitsStr string) map[string]QueueLimit {
+	return parseQueueLimits(limitsStr)
+}
+
+// ParseRetention exposes retention period parsing to external tests
+func ParseRetention(value string) (time.Duration, error) {
+	return parseRetention(value)
+}
+
+// ValidateWith exposes the loader's aggregated validation to external tests
+func ValidateWith(cfg *Config, validators ...Validator) error {
+	cl := NewConfigLoader(slog.New(slog.DiscardHandler))
+	cl.validators = validators
+	return cl.validateConfig(cfg)
+}
+
+// ParseLogOutputs exposes LOG_OUTPUTS parsing to external tests
+func ParseLogOutputs(outputsStr string) []logger.OutputConfig {
+	return parseLogOutputs(outputsStr, map[string]any{"max_size_mb": 100, "max_backups": 5})
+}
@@ -166,40 +166,3 @@ func (em *EnvSecretsManager) GetSecrets(ctx context.Context, keys []string) (map
 func (em *EnvSecretsManager) RefreshSecrets(ctx context.Context) error {
 	return nil
 }
-
-// VaultSecretsManager would implement HashiCorp Vault integration
-// This is a stub for future implementation
-type VaultSecretsManager struct {
-	addr   string
-	token  string
-	path   string
-	logger *slog.Logger
-	// Add Vault client here
-}
-
-// NewVaultSecretsManager creates a new Vault secrets manager
-func NewVaultSecretsManager(addr, token, path string, logger *slog.Logger) (*VaultSecretsManager, error) {
-	// Implementation would go here
-	return &VaultSecretsManager{
-		addr:   addr,
-		token:  token,
-		path:   path,
-		logger: logger,
-	}, nil
-}
-
-// Implement SecretsManager interface methods...
-func (vm *VaultSecretsManager) GetSecret(ctx context.Context, key string) (string, error) {
-	// Vault implementation
-	return "", fmt.Errorf("vault integration not yet implemented")
-}
-
-func (vm *VaultSecretsManager) GetSecrets(ctx context.Context, keys []string) (map[string]string, error) {
-	// Vault implementation
-	return nil, fmt.Errorf("vault integration not yet implemented")
-}
-
-func (vm *VaultSecretsManager) RefreshSecrets(ctx context.Context) error {
-	// Vault implementation
-	return fmt.Errorf("vault integration not yet implemented")
-}
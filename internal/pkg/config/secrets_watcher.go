@@ -0,0 +1,107 @@
+// internal/pkg/config/secrets_watcher.go
+package config
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RotateFunc applies a rotated secret, e.g. by reconnecting with the new password
+type RotateFunc func(ctx context.Context, value string) error
+
+// SecretsWatcher periodically refreshes secrets and hands rotated values to the callbacks
+// registered for them, so credentials can change without a restart.
+type SecretsWatcher struct {
+	manager  SecretsManager
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	current  map[string]string
+	handlers map[string]RotateFunc
+}
+
+// NewSecretsWatcher creates a new secrets watcher that checks for rotations every interval
+func NewSecretsWatcher(manager SecretsManager, interval time.Duration, logger *slog.Logger) *SecretsWatcher {
+	return &SecretsWatcher{
+		manager:  manager,
+		interval: interval,
+		logger:   logger.With(slog.String("component", "secrets_watcher")),
+		current:  make(map[string]string),
+		handlers: make(map[string]RotateFunc),
+	}
+}
+
+// Watch calls fn whenever key changes from the value currently in use
+func (w *SecretsWatcher) Watch(key, current string, fn RotateFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.current[key] = current
+	w.handlers[key] = fn
+}
+
+// Run checks for rotated secrets every interval until ctx is cancelled
+func (w *SecretsWatcher) Run(ctx context.Context) {
+	if w.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Check(ctx); err != nil {
+				w.logger.WarnContext(ctx, "failed to refresh secrets", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// Check refreshes the secrets and applies any that changed. A value whose callback fails
+// is retried on the next check.
+func (w *SecretsWatcher) Check(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.handlers) == 0 {
+		return nil
+	}
+
+	if err := w.manager.RefreshSecrets(ctx); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(w.handlers))
+	for key := range w.handlers {
+		keys = append(keys, key)
+	}
+	secrets, err := w.manager.GetSecrets(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		val, ok := secrets[key]
+		if !ok || val == "" || val == w.current[key] {
+			continue
+		}
+
+		if err := w.handlers[key](ctx, val); err != nil {
+			w.logger.ErrorContext(ctx, "failed to apply rotated secret",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		w.current[key] = val
+		w.logger.InfoContext(ctx, "applied rotated secret", slog.String("key", key))
+	}
+
+	return nil
+}
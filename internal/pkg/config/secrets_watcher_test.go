@@ -0,0 +1,72 @@
+// internal/pkg/config/secrets_watcher_test.go
+package config_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+func TestSecretsWatcher_ReconnectsOnceWhenPasswordRotates(t *testing.T) {
+	ctx := context.Background()
+	vault, server := newFakeVault(t)
+	vault.setSecret("REDIS_PASSWORD", "redis-password")
+
+	sm, err := config.NewVaultSecretsManager(server.URL, vaultTestToken, "secret/data/resell/production", time.Minute, helpers.TestLogger())
+	require.NoError(t, err)
+
+	watcher := config.NewSecretsWatcher(sm, time.Minute, helpers.TestLogger())
+
+	var dbReconnects []string
+	watcher.Watch("DB_PASSWORD", "first-password", func(_ context.Context, password string) error {
+		dbReconnects = append(dbReconnects, password)
+		return nil
+	})
+	watcher.Watch("REDIS_PASSWORD", "redis-password", func(context.Context, string) error {
+		t.Error("unchanged Redis password must not trigger a reconnect")
+		return nil
+	})
+
+	// Nothing has rotated yet
+	require.NoError(t, watcher.Check(ctx))
+	assert.Empty(t, dbReconnects)
+
+	vault.setSecret("DB_PASSWORD", "rotated-password")
+	require.NoError(t, watcher.Check(ctx))
+	require.NoError(t, watcher.Check(ctx))
+
+	assert.Equal(t, []string{"rotated-password"}, dbReconnects)
+}
+
+func TestSecretsWatcher_RetriesFailedReconnect(t *testing.T) {
+	ctx := context.Background()
+	vault, server := newFakeVault(t)
+
+	sm, err := config.NewVaultSecretsManager(server.URL, vaultTestToken, "secret/data/resell/production", time.Minute, helpers.TestLogger())
+	require.NoError(t, err)
+
+	watcher := config.NewSecretsWatcher(sm, time.Minute, helpers.TestLogger())
+
+	attempts := 0
+	watcher.Watch("DB_PASSWORD", "first-password", func(context.Context, string) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	vault.setSecret("DB_PASSWORD", "rotated-password")
+	require.NoError(t, watcher.Check(ctx))
+	require.NoError(t, watcher.Check(ctx))
+	require.NoError(t, watcher.Check(ctx))
+
+	assert.Equal(t, 2, attempts, "a failed reconnect is retried until it succeeds")
+}
@@ -2,35 +2,184 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/ammerola/resell-be/internal/pkg/logger"
 )
 
+// missingPrefix marks a required value whose environment variable isn't set in development
+const missingPrefix = "MISSING_"
+
+// FieldError is a single configuration value that failed a validation rule
+type FieldError struct {
+	Field   string // dotted path into Config, e.g. Database.Host
+	Rule    string // the rule that failed, e.g. required or min
+	Message string
+	Err     error // optional sentinel, e.g. ErrMissingRequiredConfig
+}
+
+// Error implements error
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Unwrap returns the sentinel error, if any
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigValidationError collects every validation failure so they can all be reported at once
+type ConfigValidationError struct {
+	Fields []FieldError
+}
+
+// Add records a failed rule for field
+func (e *ConfigValidationError) Add(field, rule, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// addMissing records a required field without a value, with optional detail
+func (e *ConfigValidationError) addMissing(field, detail string) {
+	message := ErrMissingRequiredConfig.Error()
+	if detail != "" {
+		message += ": " + detail
+	}
+	e.Fields = append(e.Fields, FieldError{
+		Field:   field,
+		Rule:    "required",
+		Message: message,
+		Err:     ErrMissingRequiredConfig,
+	})
+}
+
+// merge appends the issues in err, which is usually another ConfigValidationError
+func (e *ConfigValidationError) merge(err error) {
+	var cve *ConfigValidationError
+	if errors.As(err, &cve) {
+		e.Fields = append(e.Fields, cve.Fields...)
+		return
+	}
+	e.Fields = append(e.Fields, FieldError{Field: "config", Rule: "custom", Message: err.Error(), Err: err})
+}
+
+// ErrOrNil returns e when it holds any issues, so validators can return it directly
+func (e *ConfigValidationError) ErrOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error implements error, listing every issue
+func (e *ConfigValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		msgs[i] = field.Error()
+	}
+	return fmt.Sprintf("%d configuration problem(s): %s", len(e.Fields), strings.Join(msgs, "; "))
+}
+
+// Unwrap exposes the individual issues to errors.Is and errors.As
+func (e *ConfigValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = field
+	}
+	return errs
+}
+
 // BasicValidator performs basic configuration validation
-type BasicValidator struct{}
+type BasicValidator struct {
+	// AllowMissing lets unset required environment variables through as MISSING_ placeholders
+	AllowMissing bool
+}
 
 // Validate performs basic validation
 func (v *BasicValidator) Validate(cfg *Config) error {
-	// Validate required fields using reflection
-	if err := validateRequiredFields(cfg); err != nil {
-		return err
+	errs := &ConfigValidationError{}
+
+	// Report every unset variable at once rather than failing later on a placeholder
+	if missing := missingEnvVars(cfg); len(missing) > 0 && !v.AllowMissing {
+		errs.addMissing("env", fmt.Sprintf("environment variables not set: %s (set them in .env or pass --allow-missing)",
+			strings.Join(missing, ", ")))
 	}
 
+	// Validate required fields using reflection
+	validateRequiredFields(cfg, errs)
+
 	// Validate numeric ranges
 	if cfg.Database.MaxConnections < cfg.Database.MinConnections {
-		return fmt.Errorf("database max_connections must be >= min_connections")
+		errs.Add("Database.MaxConnections", "gte", "database max_connections must be >= min_connections")
 	}
 
 	if cfg.Redis.PoolSize <= 0 {
-		return fmt.Errorf("redis pool_size must be positive")
+		errs.Add("Redis.PoolSize", "positive", "redis pool_size must be positive")
 	}
 
 	if cfg.Security.RateLimitRequests <= 0 {
-		return fmt.Errorf("rate_limit_requests must be positive")
+		errs.Add("Security.RateLimitRequests", "positive", "rate_limit_requests must be positive")
+	}
+
+	if cfg.FileProcessing.StorageBackend != "s3" && cfg.FileProcessing.StorageBackend != "local" {
+		errs.Add("FileProcessing.StorageBackend", "oneof", "storage backend must be one of: s3, local")
 	}
 
-	return nil
+	if cfg.Email.Sender != "ses" && cfg.Email.Sender != "log" {
+		errs.Add("Email.Sender", "oneof", "email sender must be one of: ses, log")
+	}
+
+	if cfg.App.IdempotencyTTL <= 0 {
+		errs.Add("App.IdempotencyTTL", "positive", "idempotency key ttl must be positive")
+	}
+
+	if cfg.Retention.BatchSize <= 0 {
+		errs.Add("Retention.BatchSize", "positive", "cleanup batch size must be positive")
+	}
+
+	if cfg.Asynq.RetryBaseDelay <= 0 || cfg.Asynq.RetryMaxDelay < cfg.Asynq.RetryBaseDelay {
+		errs.Add("Asynq.RetryBaseDelay", "range", "asynq retry base delay must be positive and no more than the max delay")
+	}
+
+	for i, output := range cfg.Logging.Outputs {
+		field := fmt.Sprintf("Logging.Outputs[%d]", i)
+		switch output.Type {
+		case "console", "elasticsearch":
+		case "file":
+			if filename, _ := output.Options["filename"].(string); filename == "" {
+				errs.Add(field, "required", "file log output needs a file name")
+			}
+			if _, err := logger.ParseRotationConfig(output.Options); err != nil {
+				errs.Add(field, "rotation", "file log output rotation: %v", err)
+			}
+		default:
+			errs.Add(field, "oneof", "log output type %q must be one of: console, file, elasticsearch", output.Type)
+		}
+		switch strings.ToLower(output.Level) {
+		case "", "debug", "info", "warn", "warning", "error":
+		default:
+			errs.Add(field, "oneof", "log output level %q must be one of: debug, info, warn, error", output.Level)
+		}
+		if output.Format != "" && output.Format != "json" && output.Format != "text" {
+			errs.Add(field, "oneof", "log output format %q must be one of: json, text", output.Format)
+		}
+	}
+
+	queues := make([]string, 0, len(cfg.Asynq.QueueLimits))
+	for queue := range cfg.Asynq.QueueLimits {
+		queues = append(queues, queue)
+	}
+	sort.Strings(queues)
+	for _, queue := range queues {
+		if _, ok := cfg.Asynq.Queues[queue]; !ok {
+			errs.Add("Asynq.QueueLimits", "known_queue", "asynq queue limit set for unknown queue %q", queue)
+		}
+	}
+
+	return errs.ErrOrNil()
 }
 
 // ProductionValidator performs strict validation for production environments
@@ -38,45 +187,47 @@ type ProductionValidator struct{}
 
 // Validate performs production-specific validation
 func (v *ProductionValidator) Validate(cfg *Config) error {
+	errs := &ConfigValidationError{}
+
 	// Check for placeholder values
-	if strings.Contains(cfg.Database.Password, "MISSING_") {
-		return fmt.Errorf("%w: database password", ErrMissingRequiredConfig)
+	if strings.Contains(cfg.Database.Password, missingPrefix) {
+		errs.addMissing("Database.Password", "")
 	}
 
-	if strings.Contains(cfg.Security.JWTSecret, "MISSING_") {
-		return fmt.Errorf("%w: JWT secret", ErrMissingRequiredConfig)
+	if strings.Contains(cfg.Security.JWTSecret, missingPrefix) {
+		errs.addMissing("Security.JWTSecret", "")
 	}
 
 	// Ensure secure defaults in production
 	if cfg.Database.SSLMode == "disable" {
-		return fmt.Errorf("database SSL must be enabled in production")
+		errs.Add("Database.SSLMode", "production", "database SSL must be enabled in production")
 	}
 
 	if !cfg.Security.SecureHeaders {
-		return fmt.Errorf("secure headers must be enabled in production")
+		errs.Add("Security.SecureHeaders", "production", "secure headers must be enabled in production")
 	}
 
 	if !cfg.Security.CSRFProtection {
-		return fmt.Errorf("CSRF protection must be enabled in production")
+		errs.Add("Security.CSRFProtection", "production", "CSRF protection must be enabled in production")
 	}
 
 	if len(cfg.Security.AllowedOrigins) == 0 {
-		return fmt.Errorf("allowed origins must be configured in production")
+		errs.Add("Security.AllowedOrigins", "production", "allowed origins must be configured in production")
 	}
 
 	// Check for insecure defaults
 	if cfg.Security.JWTSecret == "development-secret-change-in-production" {
-		return fmt.Errorf("default JWT secret cannot be used in production")
+		errs.Add("Security.JWTSecret", "production", "default JWT secret cannot be used in production")
 	}
 
 	// Ensure proper TLS configuration
 	if cfg.Server.TLSEnabled {
 		if cfg.Server.TLSCertFile == "" || cfg.Server.TLSKeyFile == "" {
-			return fmt.Errorf("TLS cert and key files must be provided when TLS is enabled")
+			errs.Add("Server.TLSCertFile", "required_with", "TLS cert and key files must be provided when TLS is enabled")
 		}
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
 
 // SecurityValidator validates security-related configuration
@@ -84,40 +235,43 @@ type SecurityValidator struct{}
 
 // Validate performs security validation
 func (v *SecurityValidator) Validate(cfg *Config) error {
+	errs := &ConfigValidationError{}
+
 	// JWT secret strength
 	if len(cfg.Security.JWTSecret) < 32 {
-		return fmt.Errorf("JWT secret must be at least 32 characters")
+		errs.Add("Security.JWTSecret", "min", "JWT secret must be at least 32 characters")
 	}
 
 	// Bcrypt cost validation
 	if cfg.Security.BcryptCost < 10 {
-		return fmt.Errorf("bcrypt cost must be at least 10")
+		errs.Add("Security.BcryptCost", "min", "bcrypt cost must be at least 10")
 	}
 	if cfg.Security.BcryptCost > 15 {
-		return fmt.Errorf("bcrypt cost should not exceed 15 for performance reasons")
+		errs.Add("Security.BcryptCost", "max", "bcrypt cost should not exceed 15 for performance reasons")
 	}
 
 	// Validate allowed origins format
 	for _, origin := range cfg.Security.AllowedOrigins {
 		if origin == "*" && cfg.IsProduction() {
-			return fmt.Errorf("wildcard origin (*) not allowed in production")
+			errs.Add("Security.AllowedOrigins", "no_wildcard", "wildcard origin (*) not allowed in production")
+			break
 		}
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
 
 // validateRequiredFields uses reflection to check required struct tags
-func validateRequiredFields(cfg interface{}) error {
+func validateRequiredFields(cfg interface{}, errs *ConfigValidationError) {
 	v := reflect.ValueOf(cfg)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 
-	return validateStruct(v, "")
+	validateStruct(v, "", errs)
 }
 
-func validateStruct(v reflect.Value, prefix string) error {
+func validateStruct(v reflect.Value, prefix string, errs *ConfigValidationError) {
 	t := v.Type()
 
 	for i := 0; i < v.NumField(); i++ {
@@ -132,25 +286,21 @@ func validateStruct(v reflect.Value, prefix string) error {
 		// Check for required tag
 		if required := fieldType.Tag.Get("required"); required == "true" {
 			if isZeroValue(field) {
-				return fmt.Errorf("%w: %s", ErrMissingRequiredConfig, fieldName)
+				errs.addMissing(fieldName, "")
 			}
 		}
 
 		// Recursively check nested structs
 		if field.Kind() == reflect.Struct {
-			if err := validateStruct(field, fieldName); err != nil {
-				return err
-			}
+			validateStruct(field, fieldName, errs)
 		}
 	}
-
-	return nil
 }
 
 func isZeroValue(v reflect.Value) bool {
 	switch v.Kind() {
 	case reflect.String:
-		return v.String() == "" || strings.HasPrefix(v.String(), "MISSING_")
+		return v.String() == ""
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return v.Int() == 0
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -167,3 +317,27 @@ func isZeroValue(v reflect.Value) bool {
 		return false
 	}
 }
+
+// missingEnvVars returns the sorted names of the environment variables left as MISSING_
+// placeholders anywhere in cfg
+func missingEnvVars(cfg *Config) []string {
+	var missing []string
+	collectMissing(reflect.ValueOf(cfg).Elem(), &missing)
+	sort.Strings(missing)
+	return missing
+}
+
+func collectMissing(v reflect.Value, missing *[]string) {
+	switch v.Kind() {
+	case reflect.String:
+		if name, ok := strings.CutPrefix(v.String(), missingPrefix); ok {
+			*missing = append(*missing, name)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				collectMissing(v.Field(i), missing)
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// internal/pkg/config/vault.go
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// vaultRenewRetryDelay is how long to wait before trying again after a failed token renewal
+const vaultRenewRetryDelay = 30 * time.Second
+
+// VaultSecretsManager reads secrets from a HashiCorp Vault KV engine (v1 or v2) over the HTTP
+// API. Secrets are cached and re-read every refresh interval by Run, which also renews the
+// token before its lease expires.
+type VaultSecretsManager struct {
+	addr            string
+	token           string
+	path            string
+	refreshInterval time.Duration
+	client          *http.Client
+	logger          *slog.Logger
+
+	cache atomic.Pointer[map[string]string]
+
+	tokenMu     sync.RWMutex
+	tokenTTL    time.Duration
+	tokenExpiry time.Time
+	renewable   bool
+}
+
+// NewVaultSecretsManager creates a new Vault secrets manager for the secret at path, e.g.
+// secret/data/resell/production
+func NewVaultSecretsManager(addr, token, path string, refreshInterval time.Duration, logger *slog.Logger) (*VaultSecretsManager, error) {
+	if addr == "" || token == "" || path == "" {
+		return nil, fmt.Errorf("vault address, token and path are required")
+	}
+
+	return &VaultSecretsManager{
+		addr:            strings.TrimRight(addr, "/"),
+		token:           token,
+		path:            strings.Trim(path, "/"),
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		logger:          logger.With(slog.String("secrets", "vault")),
+	}, nil
+}
+
+// GetSecret retrieves a single secret
+func (vm *VaultSecretsManager) GetSecret(ctx context.Context, key string) (string, error) {
+	secrets, err := vm.GetSecrets(ctx, []string{key})
+	if err != nil {
+		return "", err
+	}
+
+	val, ok := secrets[key]
+	if !ok {
+		return "", fmt.Errorf("secret key %s not found", key)
+	}
+
+	return val, nil
+}
+
+// GetSecrets retrieves multiple secrets from the cache, reading them from Vault on first use
+func (vm *VaultSecretsManager) GetSecrets(ctx context.Context, keys []string) (map[string]string, error) {
+	if vm.cache.Load() == nil {
+		if err := vm.RefreshSecrets(ctx); err != nil {
+			return nil, err
+		}
+	}
+	cached := *vm.cache.Load()
+
+	filtered := make(map[string]string)
+	for _, key := range keys {
+		if val, ok := cached[key]; ok {
+			filtered[key] = val
+		} else {
+			vm.logger.Warn("secret key not found in Vault",
+				slog.String("key", key))
+		}
+	}
+
+	return filtered, nil
+}
+
+// RefreshSecrets re-reads the secret from Vault and swaps it into the cache in one step, so
+// readers see either the old or the new secrets but never a mix. The cache is left as is
+// when the read fails.
+func (vm *VaultSecretsManager) RefreshSecrets(ctx context.Context) error {
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := vm.do(ctx, http.MethodGet, "/v1/"+vm.path, &body); err != nil {
+		return fmt.Errorf("failed to read vault secret %s: %w", vm.path, err)
+	}
+
+	// KV v2 nests the values under data.data next to the version metadata
+	data := body.Data
+	if nested, ok := data["data"]; ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return fmt.Errorf("failed to parse vault secret %s: %w", vm.path, err)
+			}
+		}
+	}
+
+	secrets := make(map[string]string, len(data))
+	for key, raw := range data {
+		var val string
+		if err := json.Unmarshal(raw, &val); err != nil {
+			val = string(raw)
+		}
+		secrets[key] = val
+	}
+
+	vm.cache.Store(&secrets)
+	vm.logger.Debug("vault secrets refreshed", slog.Int("keys", len(secrets)))
+	return nil
+}
+
+// Run re-reads secrets every refresh interval and renews the token once two thirds of its
+// TTL have passed, until ctx is cancelled. Failures are logged and retried.
+func (vm *VaultSecretsManager) Run(ctx context.Context) {
+	if err := vm.lookupToken(ctx); err != nil {
+		vm.logger.Warn("failed to look up vault token; it will not be renewed",
+			slog.String("error", err.Error()))
+	}
+
+	var refreshC <-chan time.Time
+	if vm.refreshInterval > 0 {
+		ticker := time.NewTicker(vm.refreshInterval)
+		defer ticker.Stop()
+		refreshC = ticker.C
+	}
+
+	renewFailed := false
+	for {
+		var renewC <-chan time.Time
+		var renewTimer *time.Timer
+		if delay, ok := vm.renewalDelay(time.Now()); ok {
+			if renewFailed {
+				delay = max(delay, vaultRenewRetryDelay)
+			}
+			renewTimer = time.NewTimer(delay)
+			renewC = renewTimer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if renewTimer != nil {
+				renewTimer.Stop()
+			}
+			return
+		case <-refreshC:
+			if err := vm.RefreshSecrets(ctx); err != nil {
+				vm.logger.Warn("failed to refresh vault secrets", slog.String("error", err.Error()))
+			}
+		case <-renewC:
+			renewFailed = false
+			if err := vm.RenewToken(ctx); err != nil {
+				renewFailed = true
+				vm.logger.Warn("failed to renew vault token", slog.String("error", err.Error()))
+			}
+		}
+
+		if renewTimer != nil {
+			renewTimer.Stop()
+		}
+	}
+}
+
+// RenewToken extends the token's lease
+func (vm *VaultSecretsManager) RenewToken(ctx context.Context) error {
+	var body struct {
+		Auth struct {
+			LeaseDuration int  `json:"lease_duration"`
+			Renewable     bool `json:"renewable"`
+		} `json:"auth"`
+	}
+	if err := vm.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", &body); err != nil {
+		return err
+	}
+
+	vm.setTokenLease(time.Duration(body.Auth.LeaseDuration)*time.Second, body.Auth.Renewable)
+	vm.logger.Info("vault token renewed", slog.Int("ttl_seconds", body.Auth.LeaseDuration))
+	return nil
+}
+
+// lookupToken reads the token's remaining TTL and whether it can be renewed
+func (vm *VaultSecretsManager) lookupToken(ctx context.Context) error {
+	var body struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := vm.do(ctx, http.MethodGet, "/v1/auth/token/lookup-self", &body); err != nil {
+		return err
+	}
+
+	vm.setTokenLease(time.Duration(body.Data.TTL)*time.Second, body.Data.Renewable)
+	return nil
+}
+
+func (vm *VaultSecretsManager) setTokenLease(ttl time.Duration, renewable bool) {
+	vm.tokenMu.Lock()
+	defer vm.tokenMu.Unlock()
+	vm.tokenTTL = ttl
+	vm.tokenExpiry = time.Now().Add(ttl)
+	vm.renewable = renewable
+}
+
+// renewalDelay returns how long until the token should be renewed. Tokens that don't expire
+// or can't be renewed never are.
+func (vm *VaultSecretsManager) renewalDelay(now time.Time) (time.Duration, bool) {
+	vm.tokenMu.RLock()
+	defer vm.tokenMu.RUnlock()
+
+	if !vm.renewable || vm.tokenTTL <= 0 {
+		return 0, false
+	}
+	return max(vm.tokenExpiry.Sub(now)-vm.tokenTTL/3, 0), true
+}
+
+// do sends an authenticated request to Vault and decodes the JSON response into out
+func (vm *VaultSecretsManager) do(ctx context.Context, method, path string, out interface{}) error {
+	var body io.Reader
+	if method == http.MethodPost {
+		body = bytes.NewReader([]byte("{}"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, vm.addr+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", vm.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := vm.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errBody struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errBody)
+		if len(errBody.Errors) > 0 {
+			return fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(errBody.Errors, "; "))
+		}
+		return fmt.Errorf("vault returned %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// internal/pkg/config/vault_test.go
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+const vaultTestToken = "s.test-token"
+
+// fakeVault serves a single KV v2 secret and the token self-service endpoints
+type fakeVault struct {
+	mu       sync.Mutex
+	secret   map[string]interface{}
+	tokenTTL int
+	reads    atomic.Int32
+	renewals atomic.Int32
+}
+
+func (f *fakeVault) setSecret(key string, value interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secret[key] = value
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != vaultTestToken {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/secret/data/resell/production":
+		f.reads.Add(1)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     f.secret,
+				"metadata": map[string]interface{}{"version": f.reads.Load()},
+			},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/auth/token/lookup-self":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"ttl": f.tokenTTL, "renewable": true},
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/token/renew-self":
+		f.renewals.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"lease_duration": f.tokenTTL, "renewable": true},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+	}
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	t.Helper()
+	vault := &fakeVault{
+		secret:   map[string]interface{}{"DB_PASSWORD": "first-password", "DB_PORT": 5432},
+		tokenTTL: 3600,
+	}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+	return vault, server
+}
+
+func TestVaultSecretsManager_RefreshPicksUpRotatedPassword(t *testing.T) {
+	ctx := context.Background()
+	vault, server := newFakeVault(t)
+
+	sm, err := config.NewVaultSecretsManager(server.URL, vaultTestToken, "secret/data/resell/production", time.Minute, helpers.TestLogger())
+	require.NoError(t, err)
+
+	secrets, err := sm.GetSecrets(ctx, []string{"DB_PASSWORD", "DB_PORT", "MISSING"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "first-password", "DB_PORT": "5432"}, secrets)
+
+	vault.setSecret("DB_PASSWORD", "rotated-password")
+
+	// Served from the cache until the next refresh
+	password, err := sm.GetSecret(ctx, "DB_PASSWORD")
+	require.NoError(t, err)
+	assert.Equal(t, "first-password", password)
+	assert.EqualValues(t, 1, vault.reads.Load())
+
+	require.NoError(t, sm.RefreshSecrets(ctx))
+
+	password, err = sm.GetSecret(ctx, "DB_PASSWORD")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated-password", password)
+}
+
+func TestVaultSecretsManager_RunRefreshesAndRenews(t *testing.T) {
+	vault, server := newFakeVault(t)
+	vault.tokenTTL = 1 // renewed about every 670ms
+
+	sm, err := config.NewVaultSecretsManager(server.URL, vaultTestToken, "/secret/data/resell/production/", 20*time.Millisecond, helpers.TestLogger())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sm.Run(ctx)
+		close(done)
+	}()
+
+	vault.setSecret("DB_PASSWORD", "rotated-password")
+	assert.Eventually(t, func() bool {
+		password, err := sm.GetSecret(ctx, "DB_PASSWORD")
+		return err == nil && password == "rotated-password"
+	}, 2*time.Second, 10*time.Millisecond, "periodic refresh picks up the rotated password")
+
+	assert.Eventually(t, func() bool { return vault.renewals.Load() >= 1 },
+		3*time.Second, 20*time.Millisecond, "token is renewed before it expires")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
+
+func TestVaultSecretsManager_Errors(t *testing.T) {
+	ctx := context.Background()
+	_, server := newFakeVault(t)
+
+	_, err := config.NewVaultSecretsManager(server.URL, "", "secret/data/resell/production", time.Minute, helpers.TestLogger())
+	assert.Error(t, err, "a token is required")
+
+	sm, err := config.NewVaultSecretsManager(server.URL, "wrong-token", "secret/data/resell/production", time.Minute, helpers.TestLogger())
+	require.NoError(t, err)
+	_, err = sm.GetSecrets(ctx, []string{"DB_PASSWORD"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+
+	sm, err = config.NewVaultSecretsManager(server.URL, vaultTestToken, "secret/data/other", time.Minute, helpers.TestLogger())
+	require.NoError(t, err)
+	assert.Error(t, sm.RefreshSecrets(ctx))
+}
@@ -0,0 +1,120 @@
+// internal/pkg/keywords/keywords.go
+
+// Package keywords extracts search keywords from free-text item descriptions
+package keywords
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wordRe matches the tokens keywords are built from: letters and digits, e.g. "chair" or "14k"
+var wordRe = regexp.MustCompile(`[a-z0-9]+`)
+
+// stopWords are never keywords on their own, though "set" and "pair" still complete bigrams
+// such as "tea set"
+var stopWords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true,
+	"but": true, "in": true, "on": true, "at": true, "to": true,
+	"for": true, "of": true, "with": true, "by": true, "from": true,
+	"is": true, "was": true, "are": true, "were": true, "total": true,
+	"set": true, "lot": true, "pair": true,
+}
+
+// bigrams are the two-word phrases worth keeping whole, in their singular form
+var bigrams = map[string]bool{
+	"tea set": true, "coffee set": true, "dinner set": true, "chess set": true, "vanity set": true,
+	"oil painting": true, "watercolor painting": true, "framed print": true,
+	"sterling silver": true, "silver plate": true, "cast iron": true, "stained glass": true,
+	"depression glass": true, "cut glass": true, "milk glass": true, "art glass": true,
+	"costume jewelry": true, "pocket watch": true, "wrist watch": true, "wall clock": true,
+	"mantel clock": true, "table lamp": true, "floor lamp": true, "dining table": true,
+	"coffee table": true, "side table": true, "rocking chair": true, "dining chair": true,
+	"cookie jar": true, "music box": true, "record player": true, "first edition": true,
+	"baseball card": true, "comic book": true,
+}
+
+// irregular plurals that the suffix rules would get wrong
+var irregular = map[string]string{
+	"knives": "knife", "shelves": "shelf", "leaves": "leaf", "wolves": "wolf",
+	"men": "man", "women": "woman", "children": "child", "feet": "foot",
+	"teeth": "tooth", "mice": "mouse", "geese": "goose",
+	"series": "series", "species": "species",
+}
+
+// Extract returns up to limit unique keywords from text in the order they appear, with
+// plurals collapsed to their singular and known bigrams kept as phrases alongside their
+// words. A limit of zero or less returns them all.
+func Extract(text string, limit int) []string {
+	tokens := wordRe.FindAllString(strings.ToLower(text), -1)
+	for i, token := range tokens {
+		tokens[i] = Singular(token)
+	}
+
+	seen := make(map[string]bool)
+	var keywords []string
+	add := func(keyword string) bool {
+		if !seen[keyword] {
+			seen[keyword] = true
+			keywords = append(keywords, keyword)
+		}
+		return limit > 0 && len(keywords) >= limit
+	}
+
+	for i, token := range tokens {
+		if i+1 < len(tokens) {
+			if phrase := token + " " + tokens[i+1]; bigrams[phrase] {
+				if add(phrase) {
+					break
+				}
+			}
+		}
+		if stopWords[token] || len(token) <= 2 || isNumber(token) {
+			continue
+		}
+		if add(token) {
+			break
+		}
+	}
+
+	return keywords
+}
+
+// Singular returns the singular form of an English plural, leaving other words unchanged.
+// It is deliberately light: suffix rules plus a few irregular nouns.
+func Singular(word string) string {
+	if singular, ok := irregular[word]; ok {
+		return singular
+	}
+	if len(word) <= 3 || strings.ContainsAny(word, "0123456789") {
+		return word // 1950s stays a decade
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return strings.TrimSuffix(word, "ies") + "y" // candies
+	case strings.HasSuffix(word, "sses"),
+		strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "zzes"):
+		return strings.TrimSuffix(word, "es") // glasses, watches, dishes, boxes
+	case strings.HasSuffix(word, "ss"),
+		strings.HasSuffix(word, "us"),
+		strings.HasSuffix(word, "is"),
+		strings.HasSuffix(word, "as"):
+		return word // brass, cactus, tennis, canvas
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s") // chairs, vases
+	}
+	return word
+}
+
+func isNumber(token string) bool {
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+// internal/pkg/keywords/keywords_test.go
+package keywords_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ammerola/resell-be/internal/pkg/keywords"
+)
+
+func TestSingular(t *testing.T) {
+	tests := map[string]string{
+		"chairs":   "chair",
+		"vases":    "vase",
+		"glasses":  "glass",
+		"watches":  "watch",
+		"dishes":   "dish",
+		"boxes":    "box",
+		"candies":  "candy",
+		"knives":   "knife",
+		"brass":    "brass",
+		"canvas":   "canvas",
+		"series":   "series",
+		"1950s":    "1950s",
+		"lamp":     "lamp",
+		"gas":      "gas",
+		"figurine": "figurine",
+	}
+	for word, want := range tests {
+		assert.Equal(t, want, keywords.Singular(word), word)
+	}
+}
+
+func TestExtract_CollapsesPlurals(t *testing.T) {
+	got := keywords.Extract("Pair of oak chairs, matching chair and 2 side chairs", 0)
+	assert.Equal(t, []string{"oak", "chair", "matching", "side"}, got)
+}
+
+func TestExtract_CapturesBigrams(t *testing.T) {
+	got := keywords.Extract("Porcelain tea set with oil paintings of roses", 0)
+	assert.Equal(t, []string{"porcelain", "tea set", "tea", "oil painting", "oil", "painting", "rose"}, got)
+
+	// "set" stays a stopword outside a known phrase
+	assert.Equal(t, []string{"brass", "candlestick"}, keywords.Extract("Set of brass candlesticks", 0))
+}
+
+func TestExtract_Limit(t *testing.T) {
+	text := "Victorian walnut dining table with six carved dining chairs and leaf"
+
+	assert.Equal(t, []string{"victorian", "walnut", "dining table"}, keywords.Extract(text, 3))
+	assert.Len(t, keywords.Extract(text, 0), 10)
+	assert.Empty(t, keywords.Extract("the lot of 12", 5))
+}
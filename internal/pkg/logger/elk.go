@@ -6,9 +6,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -23,14 +27,30 @@ type ELKConfig struct {
 	EnableBatching   bool          `json:"enable_batching"`
 }
 
-// ELKHandler sends logs to Elasticsearch
+// elkQueueBatches is how many batches of records may wait to be shipped. Records logged while
+// the queue is full are dropped so a slow or unreachable Elasticsearch never blocks callers.
+const elkQueueBatches = 10
+
+// ELKHandler ships logs to Elasticsearch's _bulk API in batches, in addition to writing them
+// to its base handler. Handlers derived with WithAttrs or WithGroup share one shipper.
 type ELKHandler struct {
-	client      *http.Client
-	config      ELKConfig
-	buffer      []LogEntry
-	mu          sync.Mutex
-	flushTimer  *time.Timer
+	shipper     *elkShipper
 	baseHandler slog.Handler
+	attrs       []slog.Attr // from WithAttrs, keys qualified by their group
+	group       string      // prefix for record attribute keys, e.g. "request."
+}
+
+// elkShipper batches entries from every ELKHandler sharing it and sends them from a single
+// goroutine, flushing when a batch fills up or the flush interval passes
+type elkShipper struct {
+	client  *http.Client
+	config  ELKConfig
+	queue   chan LogEntry
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+	failing bool // only touched by the shipping goroutine
 }
 
 // LogEntry represents a log entry for Elasticsearch
@@ -61,22 +81,34 @@ type ErrorInfo struct {
 	Code       string `json:"code,omitempty"`
 }
 
-// NewELKHandler creates a new ELK handler
+// NewELKHandler creates a new ELK handler and starts shipping in the background. Call Close
+// to send what's still queued before exiting.
 func NewELKHandler(cfg ELKConfig, baseHandler slog.Handler) *ELKHandler {
-	handler := &ELKHandler{
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if !cfg.EnableBatching {
+		cfg.BatchSize = 1
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+
+	shipper := &elkShipper{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		config:      cfg,
-		buffer:      make([]LogEntry, 0, cfg.BatchSize),
-		baseHandler: baseHandler,
+		config:  cfg,
+		queue:   make(chan LogEntry, cfg.BatchSize*elkQueueBatches),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
 	}
+	go shipper.run()
 
-	if cfg.EnableBatching {
-		handler.startFlusher()
+	return &ELKHandler{
+		shipper:     shipper,
+		baseHandler: baseHandler,
 	}
-
-	return handler
 }
 
 func (h *ELKHandler) Enabled(ctx context.Context, level slog.Level) bool {
@@ -89,26 +121,22 @@ func (h *ELKHandler) Handle(ctx context.Context, record slog.Record) error {
 		return err
 	}
 
-	// Create log entry
-	entry := h.createLogEntry(ctx, record)
-
-	if h.config.EnableBatching {
-		h.mu.Lock()
-		h.buffer = append(h.buffer, entry)
-		shouldFlush := len(h.buffer) >= h.config.BatchSize
-		h.mu.Unlock()
-
-		if shouldFlush {
-			go h.flush()
-		}
-	} else {
-		// Send immediately
-		go h.sendToElasticsearch([]LogEntry{entry})
-	}
+	h.shipper.enqueue(h.createLogEntry(ctx, record))
+	return nil
+}
 
+// Close sends the records still queued and stops shipping
+func (h *ELKHandler) Close() error {
+	h.shipper.close()
 	return nil
 }
 
+// Dropped returns how many records were discarded because the queue was full or
+// Elasticsearch couldn't be reached
+func (h *ELKHandler) Dropped() int64 {
+	return h.shipper.dropped.Load()
+}
+
 func (h *ELKHandler) createLogEntry(ctx context.Context, record slog.Record) LogEntry {
 	entry := LogEntry{
 		Timestamp:   record.Time,
@@ -136,9 +164,13 @@ func (h *ELKHandler) createLogEntry(ctx context.Context, record slog.Record) Log
 		entry.Duration = float64(duration.Milliseconds())
 	}
 
+	for _, a := range h.attrs {
+		addField(entry.Fields, "", a)
+	}
+
 	// Extract attributes
 	record.Attrs(func(a slog.Attr) bool {
-		entry.Fields[a.Key] = a.Value.Any()
+		addField(entry.Fields, h.group, a)
 
 		// Check for error details
 		if a.Key == "error" || a.Key == "err" {
@@ -162,100 +194,194 @@ func (h *ELKHandler) createLogEntry(ctx context.Context, record slog.Record) Log
 	return entry
 }
 
-func (h *ELKHandler) sendToElasticsearch(entries []LogEntry) {
-	if len(entries) == 0 {
+// addField stores a, flattening groups into dotted keys. Errors are stored as their message,
+// since they don't marshal to JSON.
+func addField(fields map[string]interface{}, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range value.Group() {
+			addField(fields, prefix, ga)
+		}
+		return
+	}
+
+	if err, ok := value.Any().(error); ok {
+		fields[prefix+a.Key] = err.Error()
+		return
+	}
+	fields[prefix+a.Key] = value.Any()
+}
+
+func (h *ELKHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	qualified := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	qualified = append(qualified, h.attrs...)
+	for _, a := range attrs {
+		qualified = append(qualified, slog.Attr{Key: h.group + a.Key, Value: a.Value})
+	}
+
+	return &ELKHandler{
+		shipper:     h.shipper,
+		baseHandler: h.baseHandler.WithAttrs(attrs),
+		attrs:       qualified,
+		group:       h.group,
+	}
+}
+
+func (h *ELKHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &ELKHandler{
+		shipper:     h.shipper,
+		baseHandler: h.baseHandler.WithGroup(name),
+		attrs:       h.attrs,
+		group:       h.group + name + ".",
+	}
+}
+
+// enqueue hands entry to the shipping goroutine, dropping it if the queue is full
+func (s *elkShipper) enqueue(entry LogEntry) {
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// close stops the shipping goroutine once it has sent everything queued
+func (s *elkShipper) close() {
+	s.once.Do(func() { close(s.done) })
+	<-s.stopped
+}
+
+func (s *elkShipper) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]LogEntry, 0, s.config.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			s.send(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.done:
+			for {
+				select {
+				case entry := <-s.queue:
+					batch = append(batch, entry)
+					if len(batch) >= s.config.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send posts entries to the _bulk endpoint. A failed batch is dropped rather than retried so
+// an outage can't build up an unbounded backlog; the outage and recovery are reported on stderr.
+func (s *elkShipper) send(entries []LogEntry) {
+	err := s.post(entries)
+	if err != nil {
+		s.dropped.Add(int64(len(entries)))
+		if !s.failing {
+			fmt.Fprintf(os.Stderr, "failed to ship logs to Elasticsearch, dropping until it recovers: %v\n", err)
+		}
+		s.failing = true
 		return
 	}
 
+	if s.failing {
+		fmt.Fprintf(os.Stderr, "log shipping to Elasticsearch recovered, %d records dropped so far\n", s.dropped.Load())
+		s.failing = false
+	}
+}
+
+func (s *elkShipper) post(entries []LogEntry) error {
 	// Create bulk request
 	var buf bytes.Buffer
 	for _, entry := range entries {
-		// Bulk API metadata
-		indexName := fmt.Sprintf("%s-%s", h.config.IndexPattern, time.Now().Format("2006.01.02"))
+		// Bulk API metadata, indexed by the day the record was logged
 		meta := map[string]interface{}{
 			"index": map[string]string{
-				"_index": indexName,
+				"_index": fmt.Sprintf("%s-%s", s.config.IndexPattern, entry.Timestamp.UTC().Format("2006.01.02")),
 			},
 		}
 
-		metaJSON, _ := json.Marshal(meta)
+		metaJSON, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
 		buf.Write(metaJSON)
 		buf.WriteByte('\n')
 
 		// Document
-		docJSON, _ := json.Marshal(entry)
+		docJSON, err := json.Marshal(entry)
+		if err != nil {
+			// Skip the document rather than losing the whole batch to one unmarshalable field
+			buf.Truncate(buf.Len() - len(metaJSON) - 1)
+			s.dropped.Add(1)
+			continue
+		}
 		buf.Write(docJSON)
 		buf.WriteByte('\n')
 	}
 
+	if buf.Len() == 0 {
+		return nil
+	}
+
 	// Send bulk request
-	url := fmt.Sprintf("%s/_bulk", h.config.ElasticsearchURL)
-	req, err := http.NewRequest("POST", url, &buf)
+	url := fmt.Sprintf("%s/_bulk", strings.TrimRight(s.config.ElasticsearchURL, "/"))
+	req, err := http.NewRequest(http.MethodPost, url, &buf)
 	if err != nil {
-		return
+		return err
 	}
 
 	req.Header.Set("Content-Type", "application/x-ndjson")
 
-	if h.config.Username != "" && h.config.Password != "" {
-		req.SetBasicAuth(h.config.Username, h.config.Password)
+	if s.config.Username != "" && s.config.Password != "" {
+		req.SetBasicAuth(s.config.Username, s.config.Password)
 	}
 
-	resp, err := h.client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		// Log to stderr if we can't send to Elasticsearch
-		fmt.Printf("Failed to send logs to Elasticsearch: %v\n", err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		fmt.Printf("Elasticsearch returned error status: %d\n", resp.StatusCode)
+		return fmt.Errorf("elasticsearch returned status %d", resp.StatusCode)
 	}
-}
 
-func (h *ELKHandler) flush() {
-	h.mu.Lock()
-	if len(h.buffer) == 0 {
-		h.mu.Unlock()
-		return
+	// A 200 can still carry per-document failures, e.g. mapping conflicts
+	var result struct {
+		Errors bool `json:"errors"`
 	}
-
-	entries := make([]LogEntry, len(h.buffer))
-	copy(entries, h.buffer)
-	h.buffer = h.buffer[:0]
-	h.mu.Unlock()
-
-	h.sendToElasticsearch(entries)
-}
-
-func (h *ELKHandler) startFlusher() {
-	go func() {
-		ticker := time.NewTicker(h.config.FlushInterval)
-		defer ticker.Stop()
-
-		for range ticker.C {
-			h.flush()
-		}
-	}()
-}
-
-func (h *ELKHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &ELKHandler{
-		client:      h.client,
-		config:      h.config,
-		buffer:      h.buffer,
-		baseHandler: h.baseHandler.WithAttrs(attrs),
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Errors {
+		fmt.Fprintf(os.Stderr, "elasticsearch rejected some log records in a bulk request\n")
 	}
-}
 
-func (h *ELKHandler) WithGroup(name string) slog.Handler {
-	return &ELKHandler{
-		client:      h.client,
-		config:      h.config,
-		buffer:      h.buffer,
-		baseHandler: h.baseHandler.WithGroup(name),
-	}
+	return nil
 }
 
 // Helper function to get string from context
@@ -291,6 +417,7 @@ func SetupELKLogging(cfg ELKConfig) *Logger {
 	// Replace handler with ELK handler
 	baseLogger.Logger = slog.New(elkHandler)
 	baseLogger.handlers = []slog.Handler{elkHandler}
+	baseLogger.closers = []io.Closer{elkHandler}
 
 	return baseLogger
 }
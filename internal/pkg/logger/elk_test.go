@@ -0,0 +1,162 @@
+// internal/pkg/logger/elk_test.go
+package logger_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// fakeBulk records the documents of each _bulk request it receives
+type fakeBulk struct {
+	mu      sync.Mutex
+	batches [][]map[string]interface{}
+	indexes []string
+	users   []string
+}
+
+func (f *fakeBulk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/_bulk" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	user, _, _ := r.BasicAuth()
+
+	var docs []map[string]interface{}
+	var indexes []string
+	scanner := bufio.NewScanner(r.Body)
+	for line := 0; scanner.Scan(); line++ {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if line%2 == 0 {
+			indexes = append(indexes, obj["index"].(map[string]interface{})["_index"].(string))
+		} else {
+			docs = append(docs, obj)
+		}
+	}
+
+	f.mu.Lock()
+	f.batches = append(f.batches, docs)
+	f.indexes = append(f.indexes, indexes...)
+	f.users = append(f.users, user)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"errors":false,"items":[]}`)
+}
+
+func (f *fakeBulk) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sizes := make([]int, len(f.batches))
+	for i, batch := range f.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestELKHandler_ShipsInBatches(t *testing.T) {
+	bulk := &fakeBulk{}
+	server := httptest.NewServer(bulk)
+	defer server.Close()
+
+	handler := logger.NewELKHandler(logger.ELKConfig{
+		ElasticsearchURL: server.URL,
+		IndexPattern:     "resell-logs",
+		BatchSize:        3,
+		FlushInterval:    time.Hour,
+		Username:         "shipper",
+		Password:         "secret",
+		EnableBatching:   true,
+	}, slog.NewJSONHandler(io.Discard, nil))
+
+	log := slog.New(handler).With(slog.String("component", "test")).WithGroup("req")
+	for i := 0; i < 7; i++ {
+		log.Info("imported row", slog.Int("row", i))
+	}
+
+	// Two full batches go out without waiting for the flush interval
+	assert.Eventually(t, func() bool { return len(bulk.batchSizes()) == 2 },
+		2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{3, 3}, bulk.batchSizes())
+
+	// The partial batch is sent on close
+	require.NoError(t, handler.Close())
+	assert.Equal(t, []int{3, 3, 1}, bulk.batchSizes())
+	assert.Zero(t, handler.Dropped())
+
+	bulk.mu.Lock()
+	defer bulk.mu.Unlock()
+	doc := bulk.batches[0][0]
+	assert.Equal(t, "imported row", doc["message"])
+	assert.Equal(t, "INFO", doc["level"])
+	fields := doc["fields"].(map[string]interface{})
+	assert.Equal(t, "test", fields["component"])
+	assert.EqualValues(t, 0, fields["req.row"])
+	assert.True(t, strings.HasPrefix(bulk.indexes[0], "resell-logs-"))
+	assert.Equal(t, []string{"shipper", "shipper", "shipper"}, bulk.users)
+}
+
+func TestELKHandler_FlushesOnInterval(t *testing.T) {
+	bulk := &fakeBulk{}
+	server := httptest.NewServer(bulk)
+	defer server.Close()
+
+	handler := logger.NewELKHandler(logger.ELKConfig{
+		ElasticsearchURL: server.URL,
+		IndexPattern:     "resell-logs",
+		BatchSize:        100,
+		FlushInterval:    20 * time.Millisecond,
+		EnableBatching:   true,
+	}, slog.NewJSONHandler(io.Discard, nil))
+	defer handler.Close()
+
+	slog.New(handler).Warn("disk almost full")
+
+	assert.Eventually(t, func() bool {
+		sizes := bulk.batchSizes()
+		return len(sizes) == 1 && sizes[0] == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestELKHandler_DoesNotBlockWhenElasticsearchIsDown(t *testing.T) {
+	// Accepts connections but never answers, as an overloaded cluster would
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	handler := logger.NewELKHandler(logger.ELKConfig{
+		ElasticsearchURL: server.URL,
+		IndexPattern:     "resell-logs",
+		BatchSize:        5,
+		FlushInterval:    time.Hour,
+		EnableBatching:   true,
+	}, slog.NewJSONHandler(io.Discard, nil))
+
+	log := slog.New(handler)
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		log.Info("request handled", slog.Int("n", i))
+	}
+
+	assert.Less(t, time.Since(start), time.Second, "logging must not wait on Elasticsearch")
+	assert.Positive(t, handler.Dropped(), "records beyond the queue are dropped")
+}
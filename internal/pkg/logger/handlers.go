@@ -5,13 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"log/slog"
-	"math/rand"
+	"math/rand/v2"
 	"regexp"
 	"strings"
 	"sync"
-	"time"
 )
 
 // ContextHandler extracts values from context and adds them to log records
@@ -73,12 +73,12 @@ func (h *ContextHandler) WithGroup(name string) slog.Handler {
 	}
 }
 
-// SamplingHandler implements log sampling for high-volume production environments
+// SamplingHandler implements log sampling for high-volume production environments. Warnings
+// and errors always pass; info and debug records are kept at sampleRate. The decision is made
+// per trace, falling back to the request ID, so a request's logs are kept or dropped together.
 type SamplingHandler struct {
 	handler    slog.Handler
 	sampleRate float64
-	mu         sync.Mutex
-	rng        *rand.Rand
 }
 
 // NewSamplingHandler creates a handler that samples logs
@@ -86,7 +86,6 @@ func NewSamplingHandler(handler slog.Handler, sampleRate float64) *SamplingHandl
 	return &SamplingHandler{
 		handler:    handler,
 		sampleRate: sampleRate,
-		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -97,16 +96,30 @@ func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	}
 
 	// Sample info and debug logs
-	h.mu.Lock()
-	sample := h.rng.Float64() < h.sampleRate
-	h.mu.Unlock()
+	return h.sampled(ctx) && h.handler.Enabled(ctx, level)
+}
 
-	return sample && h.handler.Enabled(ctx, level)
+// sampled reports whether info and debug records logged with ctx are kept
+func (h *SamplingHandler) sampled(ctx context.Context) bool {
+	id := getContextString(ctx, ContextKeyTraceID)
+	if id == "" {
+		id = getContextString(ctx, ContextKeyRequestID)
+	}
+	if id == "" {
+		return rand.Float64() < h.sampleRate
+	}
+
+	// Map the ID onto [0, 1) so every record of a trace gets the same answer
+	hash := fnv.New64a()
+	hash.Write([]byte(id))
+	return float64(hash.Sum64()>>11)/(1<<53) < h.sampleRate
 }
 
 func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
 	// Add sampling metadata
-	record.AddAttrs(slog.Float64("sample_rate", h.sampleRate))
+	if record.Level < slog.LevelWarn {
+		record.AddAttrs(slog.Float64("sample_rate", h.sampleRate))
+	}
 	return h.handler.Handle(ctx, record)
 }
 
@@ -114,7 +127,6 @@ func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &SamplingHandler{
 		handler:    h.handler.WithAttrs(attrs),
 		sampleRate: h.sampleRate,
-		rng:        h.rng,
 	}
 }
 
@@ -122,7 +134,6 @@ func (h *SamplingHandler) WithGroup(name string) slog.Handler {
 	return &SamplingHandler{
 		handler:    h.handler.WithGroup(name),
 		sampleRate: h.sampleRate,
-		rng:        h.rng,
 	}
 }
 
@@ -229,10 +240,15 @@ func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return false
 }
 
+// Handle sends record to each handler enabled for its level, so every output keeps its
+// own threshold
 func (h *MultiHandler) Handle(ctx context.Context, record slog.Record) error {
 	var errs []error
 	for _, handler := range h.handlers {
-		if err := handler.Handle(ctx, record); err != nil {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
 			errs = append(errs, err)
 		}
 	}
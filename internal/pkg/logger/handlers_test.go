@@ -0,0 +1,88 @@
+// internal/pkg/logger/handlers_test.go
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// countingHandler counts the records that reach it, by level
+type countingHandler struct {
+	info, errors *atomic.Int64
+}
+
+func newCountingHandler() countingHandler {
+	return countingHandler{info: &atomic.Int64{}, errors: &atomic.Int64{}}
+}
+
+func (h countingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h countingHandler) Handle(_ context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError {
+		h.errors.Add(1)
+	} else {
+		h.info.Add(1)
+	}
+	return nil
+}
+
+func (h countingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h countingHandler) WithGroup(string) slog.Handler      { return h }
+
+func traceContext(id string) context.Context {
+	return context.WithValue(context.Background(), logger.ContextKeyTraceID, id)
+}
+
+func TestSamplingHandler_NeverDropsErrors(t *testing.T) {
+	counter := newCountingHandler()
+	log := slog.New(logger.NewSamplingHandler(counter, 0.01))
+
+	for i := 0; i < 1000; i++ {
+		log.ErrorContext(traceContext(fmt.Sprintf("trace-%d", i)), "payment failed")
+	}
+	log.Error("no trace")
+
+	assert.EqualValues(t, 1001, counter.errors.Load())
+}
+
+func TestSamplingHandler_SamplesInfoAtRate(t *testing.T) {
+	counter := newCountingHandler()
+	log := slog.New(logger.NewSamplingHandler(counter, 0.1))
+
+	const traces = 20000
+	for i := 0; i < traces; i++ {
+		log.InfoContext(traceContext(fmt.Sprintf("trace-%d", i)), "request handled")
+	}
+
+	assert.InDelta(t, 0.1, float64(counter.info.Load())/traces, 0.02)
+}
+
+func TestSamplingHandler_KeepsTracesWhole(t *testing.T) {
+	counter := newCountingHandler()
+	log := slog.New(logger.NewSamplingHandler(counter, 0.5))
+
+	for i := 0; i < 100; i++ {
+		ctx := traceContext(fmt.Sprintf("trace-%d", i))
+		before := counter.info.Load()
+		for j := 0; j < 20; j++ {
+			log.InfoContext(ctx, "step", slog.Int("step", j))
+		}
+		kept := counter.info.Load() - before
+		assert.Contains(t, []int64{0, 20}, kept, "a trace's logs are all kept or all dropped")
+	}
+
+	// Request IDs stand in for traces when there is none
+	ctx := context.WithValue(context.Background(), logger.ContextKeyRequestID, "req-1")
+	before := counter.info.Load()
+	for j := 0; j < 20; j++ {
+		log.InfoContext(ctx, "step")
+	}
+	assert.Contains(t, []int64{0, 20}, counter.info.Load()-before)
+}
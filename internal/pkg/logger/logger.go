@@ -3,7 +3,7 @@ package logger
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -38,7 +38,7 @@ const (
 
 // OutputConfig defines logging output destinations
 type OutputConfig struct {
-	Type    string         `json:"type"` // console, file, elasticsearch, datadog, etc.
+	Type    string         `json:"type"` // console, file or elasticsearch
 	Level   string         `json:"level"`
 	Format  string         `json:"format"`
 	Options map[string]any `json:"options"`
@@ -57,14 +57,16 @@ type LogConfig struct {
 	EnableSampling   bool           `json:"enable_sampling"`
 	EnableStackTrace bool           `json:"enable_stack_trace"`
 	Fields           map[string]any `json:"fields"`
-	Outputs          []OutputConfig `json:"outputs"`
+	Outputs          []OutputConfig `json:"outputs"` // replace Output when set
 }
 
 // Logger wraps slog.Logger with additional functionality
 type Logger struct {
 	*slog.Logger
 	config      *LogConfig
+	level       *slog.LevelVar
 	handlers    []slog.Handler
+	closers     []io.Closer
 	contextKeys []ContextKey
 }
 
@@ -75,7 +77,26 @@ var (
 
 // SetupLogger initializes the enhanced logger with production features
 func SetupLogger(level string, format string) *Logger {
-	config := &LogConfig{
+	logger := NewLogger(NewLogConfig(level, format))
+	setDefault(logger)
+	return logger
+}
+
+// Setup builds a logger from config and makes it the default. Unlike NewLogger it fails when
+// an output can't be created, so a misconfigured output stops startup instead of going quiet.
+func Setup(config *LogConfig) (*Logger, error) {
+	logger, err := newLogger(config)
+	if err != nil {
+		logger.Close()
+		return nil, err
+	}
+	setDefault(logger)
+	return logger, nil
+}
+
+// NewLogConfig returns the default configuration for level and format, writing to stdout
+func NewLogConfig(level string, format string) *LogConfig {
+	return &LogConfig{
 		Level:            level,
 		Format:           format,
 		Output:           "stdout",
@@ -86,16 +107,26 @@ func SetupLogger(level string, format string) *Logger {
 		ServiceVersion:   os.Getenv("SERVICE_VERSION"),
 		Environment:      os.Getenv("APP_ENV"),
 	}
+}
 
-	logger := NewLogger(config)
+func setDefault(logger *Logger) {
 	defaultLogger = logger
 	slog.SetDefault(logger.Logger)
+}
 
+// NewLogger creates a new enhanced logger. Outputs that can't be created are reported on
+// stderr and skipped; use Setup to fail instead.
+func NewLogger(config *LogConfig) *Logger {
+	logger, err := newLogger(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: skipping outputs: %v\n", err)
+	}
 	return logger
 }
 
-// NewLogger creates a new enhanced logger
-func NewLogger(config *LogConfig) *Logger {
+// newLogger builds the logger from the outputs that could be created, returning the errors
+// for the others
+func newLogger(config *LogConfig) (*Logger, error) {
 	if config == nil {
 		config = &LogConfig{
 			Level:  "info",
@@ -104,48 +135,68 @@ func NewLogger(config *LogConfig) *Logger {
 		}
 	}
 
-	// Create base handler options
-	opts := &slog.HandlerOptions{
-		Level:     parseLevel(config.Level),
-		AddSource: config.AddSource,
-		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-			// Customize attribute formatting
-			return replaceAttr(config, groups, a)
-		},
-	}
-
-	// Create primary handler based on format
-	var primaryHandler slog.Handler
-	writer := getWriter(config.Output)
+	// The level is shared by every logger derived from this one so SetLevel reaches them all
+	level := &slog.LevelVar{}
+	level.Set(parseLevel(config.Level).Level())
 
-	switch config.Format {
-	case "json":
-		primaryHandler = slog.NewJSONHandler(writer, opts)
-	case "text":
-		primaryHandler = NewPrettyTextHandler(writer, opts)
-	default:
-		primaryHandler = slog.NewJSONHandler(writer, opts)
-	}
+	// Each output gets its own context, sampling and sanitization so it sees the same records
+	// the primary output would
+	wrap := func(handler slog.Handler) slog.Handler {
+		// Wrap with context handler for automatic context extraction
+		handler = NewContextHandler(handler, config)
 
-	// Wrap with context handler for automatic context extraction
-	primaryHandler = NewContextHandler(primaryHandler, config)
+		// Add sampling if enabled
+		if config.EnableSampling && config.SampleRate > 0 && config.SampleRate < 1.0 {
+			handler = NewSamplingHandler(handler, config.SampleRate)
+		}
 
-	// Add sampling if enabled
-	if config.EnableSampling && config.SampleRate > 0 && config.SampleRate < 1.0 {
-		primaryHandler = NewSamplingHandler(primaryHandler, config.SampleRate)
+		// Add sanitization handler
+		return NewSanitizationHandler(handler)
 	}
 
-	// Add sanitization handler
-	primaryHandler = NewSanitizationHandler(primaryHandler)
+	// Configured outputs replace the primary output, each filtering on its own level
+	var (
+		handlers []slog.Handler
+		closers  []io.Closer
+		errs     []error
+	)
+	for i, output := range config.Outputs {
+		handler, closer, err := NewOutputHandler(output, config, level)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("log output %d: %w", i, err))
+			continue
+		}
+		handlers = append(handlers, wrap(handler))
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
 
-	// Create multi-handler if multiple outputs configured
-	handlers := []slog.Handler{primaryHandler}
+	if len(handlers) == 0 {
+		// Create base handler options
+		opts := &slog.HandlerOptions{
+			Level:     level,
+			AddSource: config.AddSource,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				// Customize attribute formatting
+				return replaceAttr(config, groups, a)
+			},
+		}
 
-	for _, output := range config.Outputs {
-		handler := createOutputHandler(output, parseLevel(output.Level))
-		if handler != nil {
-			handlers = append(handlers, handler)
+		// Create primary handler based on format
+		var primaryHandler slog.Handler
+		writer := getWriter(config.Output)
+
+		switch config.Format {
+		case "json":
+			primaryHandler = slog.NewJSONHandler(writer, opts)
+		case "text":
+			primaryHandler = NewPrettyTextHandler(writer, opts)
+		default:
+			primaryHandler = slog.NewJSONHandler(writer, opts)
 		}
+
+		handlers = append(handlers, wrap(primaryHandler))
 	}
 
 	// Use multi-handler if multiple handlers
@@ -153,7 +204,7 @@ func NewLogger(config *LogConfig) *Logger {
 	if len(handlers) > 1 {
 		finalHandler = NewMultiHandler(handlers...)
 	} else {
-		finalHandler = primaryHandler
+		finalHandler = handlers[0]
 	}
 
 	// Add global fields
@@ -174,11 +225,34 @@ func NewLogger(config *LogConfig) *Logger {
 	logger := &Logger{
 		Logger:      slog.New(finalHandler),
 		config:      config,
+		level:       level,
 		handlers:    handlers,
+		closers:     closers,
 		contextKeys: defaultContextKeys(),
 	}
 
-	return logger
+	return logger, errors.Join(errs...)
+}
+
+// SetLevel changes the minimum level of the primary output, and of outputs without their own
+// level, while the logger is in use
+func (l *Logger) SetLevel(level string) {
+	l.level.Set(parseLevel(level).Level())
+}
+
+// Level returns the current minimum level of the primary output
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// Close flushes and stops any outputs that ship logs in the background, such as ELK, and
+// closes log files
+func (l *Logger) Close() error {
+	var errs []error
+	for _, closer := range l.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
 }
 
 // WithContext creates a logger with context values automatically extracted
@@ -349,32 +423,6 @@ func replaceAttr(config *LogConfig, _ []string, a slog.Attr) slog.Attr {
 	return a
 }
 
-func createOutputHandler(output OutputConfig, level slog.Leveler) slog.Handler {
-	opts := &slog.HandlerOptions{
-		Level:     level,
-		AddSource: true,
-	}
-	baseHandler := slog.NewJSONHandler(io.Discard, opts)
-
-	switch output.Type {
-	case "elasticsearch":
-		var elkCfg ELKConfig
-		if cfgBytes, err := json.Marshal(output.Options); err == nil {
-			_ = json.Unmarshal(cfgBytes, &elkCfg)
-		}
-		return NewELKHandler(elkCfg, baseHandler)
-
-	case "file":
-		if filename, ok := output.Options["filename"].(string); ok {
-			if file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
-				return slog.NewJSONHandler(file, opts)
-			}
-		}
-	}
-
-	return nil
-}
-
 // GetDefault returns the default logger instance
 func GetDefault() *Logger {
 	if defaultLogger == nil {
@@ -405,3 +453,13 @@ func FromContext(ctx context.Context) *slog.Logger {
 func WithLogger(ctx context.Context, logger *Logger) context.Context {
 	return context.WithValue(ctx, "logger", logger)
 }
+
+// WithUserID stores the authenticated user ID on the context
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, ContextKeyUserID, userID)
+}
+
+// UserIDFromContext returns the user ID set by the request middleware, or "" for anonymous requests
+func UserIDFromContext(ctx context.Context) string {
+	return getContextString(ctx, ContextKeyUserID)
+}
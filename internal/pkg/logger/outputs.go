@@ -0,0 +1,94 @@
+// internal/pkg/logger/outputs.go
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// NewOutputHandler builds the handler for one configured output. The handler filters records
+// below the output's own level, so a MultiHandler can fan out to outputs with different
+// thresholds. Outputs without a level use level. The returned closer, if any, releases the
+// output's file or background shipper.
+func NewOutputHandler(output OutputConfig, config *LogConfig, level slog.Leveler) (slog.Handler, io.Closer, error) {
+	if output.Level != "" {
+		level = parseLevel(output.Level)
+	}
+	format := output.Format
+	if format == "" {
+		format = config.Format
+	}
+
+	formatConfig := *config
+	formatConfig.Format = format
+	opts := &slog.HandlerOptions{
+		Level:     level,
+		AddSource: config.AddSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			return replaceAttr(&formatConfig, groups, a)
+		},
+	}
+
+	switch output.Type {
+	case "console":
+		var writer io.Writer = os.Stdout
+		switch stream := optionString(output.Options, "stream", "stdout"); stream {
+		case "stdout":
+		case "stderr":
+			writer = os.Stderr
+		default:
+			return nil, nil, fmt.Errorf("console output: unknown stream %q", stream)
+		}
+		if format == "text" {
+			return NewPrettyTextHandler(writer, opts), nil, nil
+		}
+		return slog.NewJSONHandler(writer, opts), nil, nil
+
+	case "file":
+		filename := optionString(output.Options, "filename", "")
+		if filename == "" {
+			return nil, nil, fmt.Errorf("file output: filename is required")
+		}
+		rotation, err := ParseRotationConfig(output.Options)
+		if err != nil {
+			return nil, nil, fmt.Errorf("file output: %w", err)
+		}
+		file, err := openRotatingFile(filename, rotation)
+		if err != nil {
+			return nil, nil, fmt.Errorf("file output: %w", err)
+		}
+		// No colors in files, even for the text format
+		if format == "text" {
+			return slog.NewTextHandler(file, opts), file, nil
+		}
+		return slog.NewJSONHandler(file, opts), file, nil
+
+	case "elasticsearch":
+		var elkCfg ELKConfig
+		cfgBytes, err := json.Marshal(output.Options)
+		if err == nil {
+			err = json.Unmarshal(cfgBytes, &elkCfg)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("elasticsearch output: invalid options: %w", err)
+		}
+		if elkCfg.ElasticsearchURL == "" {
+			return nil, nil, fmt.Errorf("elasticsearch output: elasticsearch_url is required")
+		}
+		handler := NewELKHandler(elkCfg, slog.NewJSONHandler(io.Discard, opts))
+		return handler, handler, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown log output type %q", output.Type)
+	}
+}
+
+func optionString(options map[string]any, key, defaultValue string) string {
+	if value, ok := options[key].(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,180 @@
+// internal/pkg/logger/outputs_test.go
+package logger_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+)
+
+// captureStdout redirects os.Stdout to a file for the rest of the test, returning a func that
+// reads what was written so far
+func captureStdout(t *testing.T) func() string {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "stdout")
+	require.NoError(t, err)
+
+	original := os.Stdout
+	os.Stdout = file
+	t.Cleanup(func() {
+		os.Stdout = original
+		file.Close()
+	})
+
+	return func() string {
+		data, err := os.ReadFile(file.Name())
+		require.NoError(t, err)
+		return string(data)
+	}
+}
+
+func TestLogger_FansOutByOutputLevel(t *testing.T) {
+	stdout := captureStdout(t)
+	path := filepath.Join(t.TempDir(), "logs", "api.log")
+
+	log := logger.NewLogger(&logger.LogConfig{
+		Level:  "info",
+		Format: "json",
+		Outputs: []logger.OutputConfig{
+			{Type: "console", Level: "debug", Format: "text"},
+			{Type: "file", Level: "info", Format: "json", Options: map[string]any{"filename": path}},
+		},
+	})
+
+	log.Debug("cache miss", slog.String("key", "item:1"))
+	log.Info("item created", slog.String("password", "hunter2"))
+	require.NoError(t, log.Close())
+
+	console := stdout()
+	assert.Contains(t, console, "cache miss", "the debug console output gets debug records")
+	assert.Contains(t, console, "item created")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	file := string(data)
+	assert.NotContains(t, file, "cache miss", "the info file output drops debug records")
+	assert.Contains(t, file, `"msg":"item created"`)
+	assert.NotContains(t, file, "hunter2", "outputs are sanitized like the primary output")
+}
+
+func TestSetup_RejectsUnknownOutputType(t *testing.T) {
+	_, err := logger.Setup(&logger.LogConfig{
+		Level:   "info",
+		Outputs: []logger.OutputConfig{{Type: "syslog"}},
+	})
+	assert.ErrorContains(t, err, `unknown log output type "syslog"`)
+
+	_, err = logger.Setup(&logger.LogConfig{
+		Level:   "info",
+		Outputs: []logger.OutputConfig{{Type: "file"}},
+	})
+	assert.ErrorContains(t, err, "filename is required")
+
+	_, err = logger.Setup(&logger.LogConfig{
+		Level:   "info",
+		Outputs: []logger.OutputConfig{{Type: "file", Options: map[string]any{"filename": "api.log", "max_size_mb": -1}}},
+	})
+	assert.ErrorContains(t, err, "max_size_mb must be positive")
+}
+
+// fileLogger logs to path alone, with the given rotation options
+func fileLogger(path string, options map[string]any) *logger.Logger {
+	options["filename"] = path
+	return logger.NewLogger(&logger.LogConfig{
+		Level:   "info",
+		Outputs: []logger.OutputConfig{{Type: "file", Format: "json", Options: options}},
+	})
+}
+
+// writeMB logs about n megabytes of records
+func writeMB(log *logger.Logger, n int) {
+	padding := strings.Repeat("x", 1024)
+	for i := 0; i < n*1000; i++ {
+		log.Info("row imported", slog.Int("row", i), slog.String("payload", padding))
+	}
+}
+
+func TestFileOutput_RotatesPastSizeLimit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "worker.log")
+
+	log := fileLogger(path, map[string]any{"max_size_mb": 1, "max_backups": 2})
+	writeMB(log, 4)
+	require.NoError(t, log.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(1024*1024))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "worker-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2, "only max_backups rotated files are kept")
+	for _, backup := range backups {
+		info, err := os.Stat(backup)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(1024*1024), backup)
+	}
+}
+
+func TestFileOutput_CompressesBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.log")
+
+	log := fileLogger(path, map[string]any{"max_size_mb": 1, "compress": true})
+	writeMB(log, 2)
+	require.NoError(t, log.Close())
+
+	compressed, err := filepath.Glob(filepath.Join(dir, "api-*.log.gz"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, compressed)
+
+	plain, err := filepath.Glob(filepath.Join(dir, "api-*.log"))
+	require.NoError(t, err)
+	assert.Empty(t, plain, "rotated files are replaced by their compressed copy")
+}
+
+func TestFileOutput_RemovesExpiredBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.log")
+	old := filepath.Join(dir, "api-2020-01-01T00-00-00.000.log.gz")
+	recent := filepath.Join(dir, "api-"+time.Now().Add(-time.Hour).Format("2006-01-02T15-04-05.000")+".log")
+	for _, name := range []string{old, recent} {
+		require.NoError(t, os.WriteFile(name, []byte("{}\n"), 0o644))
+	}
+
+	log := fileLogger(path, map[string]any{"max_age_days": 7})
+	require.NoError(t, log.Close())
+
+	assert.NoFileExists(t, old, "backups older than max_age_days are deleted on startup")
+	assert.FileExists(t, recent)
+}
+
+func TestParseRotationConfig(t *testing.T) {
+	rotation, err := logger.ParseRotationConfig(map[string]any{
+		"max_size_mb": float64(50), "max_backups": "3", "max_age_days": 14, "compress": "true",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, logger.RotationConfig{MaxSizeMB: 50, MaxBackups: 3, MaxAgeDays: 14, Compress: true}, rotation)
+
+	rotation, err = logger.ParseRotationConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, logger.RotationConfig{MaxSizeMB: 100, MaxBackups: 5}, rotation)
+
+	for message, options := range map[string]map[string]any{
+		"max_size_mb must be positive":            {"max_size_mb": 0},
+		"max_backups must be zero or more":        {"max_backups": -1},
+		"max_age_days: 1.5 is not a whole number": {"max_age_days": 1.5},
+		`compress: "sometimes" is not a boolean`:  {"compress": "sometimes"},
+	} {
+		_, err := logger.ParseRotationConfig(options)
+		assert.ErrorContains(t, err, message)
+	}
+}
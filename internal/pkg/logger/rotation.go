@@ -0,0 +1,338 @@
+// internal/pkg/logger/rotation.go
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat stamps rotated files, e.g. api-2024-05-01T12-00-00.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Defaults for file outputs that don't set their rotation options
+const (
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 5
+)
+
+// RotationConfig bounds the disk used by a file output
+type RotationConfig struct {
+	MaxSizeMB  int  `json:"max_size_mb"`  // rotate once the file would grow past this
+	MaxBackups int  `json:"max_backups"`  // rotated files to keep, 0 keeps all
+	MaxAgeDays int  `json:"max_age_days"` // delete rotated files older than this, 0 keeps all
+	Compress   bool `json:"compress"`     // gzip rotated files
+}
+
+// ParseRotationConfig reads the rotation settings of a file output's options, rejecting values
+// of the wrong type or out of range
+func ParseRotationConfig(options map[string]any) (RotationConfig, error) {
+	rotation := RotationConfig{
+		MaxSizeMB:  defaultFileMaxSizeMB,
+		MaxBackups: defaultFileMaxBackups,
+	}
+
+	ints := []struct {
+		key  string
+		dst  *int
+		min  int
+		rule string
+	}{
+		{"max_size_mb", &rotation.MaxSizeMB, 1, "positive"},
+		{"max_backups", &rotation.MaxBackups, 0, "zero or more"},
+		{"max_age_days", &rotation.MaxAgeDays, 0, "zero or more"},
+	}
+	for _, opt := range ints {
+		value, ok := options[opt.key]
+		if !ok {
+			continue
+		}
+		n, err := optionInt(value)
+		if err != nil {
+			return rotation, fmt.Errorf("%s: %w", opt.key, err)
+		}
+		if n < opt.min {
+			return rotation, fmt.Errorf("%s must be %s, got %d", opt.key, opt.rule, n)
+		}
+		*opt.dst = n
+	}
+
+	if value, ok := options["compress"]; ok {
+		switch v := value.(type) {
+		case bool:
+			rotation.Compress = v
+		case string:
+			compress, err := strconv.ParseBool(v)
+			if err != nil {
+				return rotation, fmt.Errorf("compress: %q is not a boolean", v)
+			}
+			rotation.Compress = compress
+		default:
+			return rotation, fmt.Errorf("compress: %v is not a boolean", value)
+		}
+	}
+
+	return rotation, nil
+}
+
+// optionInt accepts the integer forms options take: Go ints, JSON numbers and env strings
+func optionInt(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not a whole number", v)
+		}
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a whole number", v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("%v is not a whole number", value)
+	}
+}
+
+// rotatingFile is a log file that is renamed with a timestamp once it would grow past the
+// size limit. Old files are compressed and pruned in the background so rotation never waits
+// on them.
+type rotatingFile struct {
+	mu       sync.Mutex
+	name     string
+	rotation RotationConfig
+	file     *os.File
+	size     int64
+	closed   bool
+
+	mill     chan struct{}
+	millDone chan struct{}
+}
+
+func openRotatingFile(name string, rotation RotationConfig) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, err
+	}
+	f := &rotatingFile{
+		name:     name,
+		rotation: rotation,
+		mill:     make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+	if err := f.open(os.O_APPEND); err != nil {
+		return nil, err
+	}
+
+	// Apply the limits to backups left by earlier runs too
+	go f.runMill()
+	f.mill <- struct{}{}
+
+	return f, nil
+}
+
+func (f *rotatingFile) open(mode int) error {
+	file, err := os.OpenFile(f.name, os.O_CREATE|os.O_WRONLY|mode, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write implements io.Writer, rotating first when p doesn't fit in the current file
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	maxSize := int64(f.rotation.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if err := os.Rename(f.name, f.backupName(time.Now())); err != nil {
+		// Keep appending to the current file rather than stop logging
+		if openErr := f.open(os.O_APPEND); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := f.open(os.O_TRUNC); err != nil {
+		return err
+	}
+
+	select {
+	case f.mill <- struct{}{}:
+	default: // a pass is already pending
+	}
+	return nil
+}
+
+// backupName returns the name a file rotated at t is renamed to
+func (f *rotatingFile) backupName(t time.Time) string {
+	dir, base := filepath.Split(f.name)
+	ext := filepath.Ext(base)
+	return filepath.Join(dir, strings.TrimSuffix(base, ext)+"-"+t.Format(backupTimeFormat)+ext)
+}
+
+func (f *rotatingFile) runMill() {
+	defer close(f.millDone)
+	for range f.mill {
+		if err := f.millOnce(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: cleaning up rotated logs for %s: %v\n", f.name, err)
+		}
+	}
+}
+
+type backupFile struct {
+	path       string
+	rotated    time.Time
+	compressed bool
+}
+
+// millOnce deletes the backups beyond the count and age limits and compresses the rest
+func (f *rotatingFile) millOnce() error {
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	cutoff := time.Now().AddDate(0, 0, -f.rotation.MaxAgeDays)
+	for i, backup := range backups {
+		expired := f.rotation.MaxAgeDays > 0 && backup.rotated.Before(cutoff)
+		if (f.rotation.MaxBackups > 0 && i >= f.rotation.MaxBackups) || expired {
+			if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if f.rotation.Compress && !backup.compressed {
+			if err := compressFile(backup.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backups lists the rotated files, newest first
+func (f *rotatingFile) backups() ([]backupFile, error) {
+	dir, base := filepath.Split(f.name)
+	if dir == "" {
+		dir = "."
+	}
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		stamp, compressed := strings.CutSuffix(stamp, ".gz")
+		stamp, ok = strings.CutSuffix(stamp, ext)
+		if !ok {
+			continue
+		}
+		rotated, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), rotated: rotated, compressed: compressed})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+	return backups, nil
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Close closes the current file, waiting for pending compression and cleanup
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+
+	close(f.mill)
+	<-f.millDone
+	return err
+}
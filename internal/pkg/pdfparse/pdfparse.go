@@ -0,0 +1,179 @@
+// internal/pkg/pdfparse/pdfparse.go
+
+// Package pdfparse turns the text lines of an auction invoice PDF into line items
+package pdfparse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Invoice layouts understood by the parser
+const (
+	LayoutSingleColumn = "single-column"
+	LayoutTwoColumn    = "two-column"
+)
+
+var (
+	// totalRe also ends the items at a bare TOTAL, see Options.StopAtTotal
+	totalRe      = regexp.MustCompile(`(?i)TOTAL`)
+	fillerDashRe = regexp.MustCompile(`-{7,}`)
+	amountRe     = regexp.MustCompile(`\d[\d,]*(?:\.\d+)?`)
+
+	// lotMetadataRe matches the lot columns some PDFs place between the description and the
+	// price, e.g. "18488 17" or "131811 65 G2CG2C"
+	lotMetadataRe = regexp.MustCompile(`\b[0-9A-Z]{2,}(?:\s+[0-9A-Z]{1,}){0,3}$`)
+
+	lotNumberRe     = regexp.MustCompile(`\b\d{5,6}\s+\d{1,3}\s+[A-Z0-9]+\b`)
+	leadingNumberRe = regexp.MustCompile(`^\d+\s+`)
+	trailingIDRe    = regexp.MustCompile(`\s+\d{4,}$`)
+	spaceRe         = regexp.MustCompile(`\s+`)
+	dashesRe        = regexp.MustCompile(`-{3,}`)
+)
+
+// ParsedItem is one line item of an invoice
+type ParsedItem struct {
+	Description string          `json:"description"`
+	Bid         decimal.Decimal `json:"bid"` // may be zero
+}
+
+// Options adjust the parser to the invoice and to the quirks each caller has relied on. The
+// zero value parses single-column invoices with the default profile.
+type Options struct {
+	Layout  string   // LayoutSingleColumn or LayoutTwoColumn
+	Profile *Profile // nil is the default profile
+
+	// StopAtTotal also ends the items at any line mentioning TOTAL, whatever the profile's
+	// footer
+	StopAtTotal bool
+
+	// KeepLotMetadata leaves lot columns, filler dashes and trailing IDs on the price line in
+	// the description instead of stripping them
+	KeepLotMetadata bool
+}
+
+// ParseInvoice parses a single-column invoice with the default options
+func ParseInvoice(lines []string) []ParsedItem {
+	return Options{}.ParseInvoice(lines)
+}
+
+// ParseInvoice returns the items between the invoice's column header and its footer, as the
+// profile defines them. Lines without a price are buffered as the start of the next item's
+// description; text left in the buffer at the footer has no price and is dropped.
+func (o Options) ParseInvoice(lines []string) []ParsedItem {
+	profile := o.Profile
+	if profile == nil {
+		profile = defaultProfile
+	}
+
+	// Items start on the line after the header, or at the top if there is none
+	start := 0
+	for i, line := range lines {
+		if profile.header.MatchString(line) {
+			start = i + 1
+			break
+		}
+	}
+
+	// Two-column invoices put two items on one physical line; split them into logical lines
+	if o.Layout == LayoutTwoColumn {
+		var split []string
+		for _, line := range lines[start:] {
+			split = append(split, splitTwoColumnLine(strings.TrimSpace(line), profile.price)...)
+		}
+		lines, start = split, 0
+	}
+
+	var items []ParsedItem
+	var pending []string
+
+	for _, line := range lines[start:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if profile.footer.MatchString(line) || (o.StopAtTotal && totalRe.MatchString(line)) {
+			break
+		}
+
+		// Keep the content left of long filler dashes
+		if !o.KeepLotMetadata && fillerDashRe.MatchString(line) {
+			line = strings.TrimSpace(fillerDashRe.Split(line, 2)[0])
+			if line == "" {
+				continue
+			}
+		}
+
+		if !profile.linePrice.MatchString(line) {
+			pending = append(pending, line)
+			continue
+		}
+
+		bid := ParseCurrency(profile.linePrice.FindString(line))
+		desc := strings.TrimSpace(profile.linePrice.ReplaceAllString(line, ""))
+		if !o.KeepLotMetadata {
+			desc = strings.TrimSpace(lotMetadataRe.ReplaceAllString(desc, ""))
+		}
+
+		desc = o.cleanDescription(strings.Join(append(pending, desc), " "))
+		if desc != "" {
+			items = append(items, ParsedItem{Description: desc, Bid: bid})
+		}
+		pending = pending[:0]
+	}
+
+	return items
+}
+
+// cleanDescription removes the lot numbers and filler PDFs embed in descriptions
+func (o Options) cleanDescription(desc string) string {
+	if o.KeepLotMetadata {
+		desc = leadingNumberRe.ReplaceAllString(strings.TrimSpace(desc), "")
+		desc = lotNumberRe.ReplaceAllString(desc, "")
+		desc = spaceRe.ReplaceAllString(desc, " ")
+		desc = dashesRe.ReplaceAllString(desc, "")
+		return strings.TrimSpace(desc)
+	}
+
+	desc = lotNumberRe.ReplaceAllString(strings.TrimSpace(desc), "")
+	desc = leadingNumberRe.ReplaceAllString(desc, "")
+	desc = trailingIDRe.ReplaceAllString(desc, "")
+	desc = spaceRe.ReplaceAllString(desc, " ")
+	desc = dashesRe.ReplaceAllString(desc, " ")
+	return strings.TrimSpace(desc)
+}
+
+// SplitTwoColumnLine splits a line holding two items and two prices into its left and right
+// halves. Lines with any other number of prices are returned unchanged.
+func SplitTwoColumnLine(line string) []string {
+	return splitTwoColumnLine(line, defaultProfile.price)
+}
+
+func splitTwoColumnLine(line string, priceRe *regexp.Regexp) []string {
+	locs := priceRe.FindAllStringIndex(line, -1)
+	if len(locs) != 2 {
+		return []string{line}
+	}
+
+	left := strings.TrimSpace(line[:locs[0][1]])
+	right := strings.TrimSpace(line[locs[0][1]:])
+	if left == "" || right == "" {
+		return []string{line}
+	}
+
+	return []string{left, right}
+}
+
+// ParseCurrency parses the amount in a price such as "$1,250.00" or "95.00 USD", returning zero
+// if there is none
+func ParseCurrency(val string) decimal.Decimal {
+	amount := strings.ReplaceAll(amountRe.FindString(val), ",", "")
+
+	d, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
@@ -0,0 +1,99 @@
+// internal/pkg/pdfparse/pdfparse_test.go
+package pdfparse_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// importOptions are the options the PDF import worker parses with
+var importOptions = pdfparse.Options{StopAtTotal: true, KeepLotMetadata: true}
+
+func TestParseInvoice_Golden(t *testing.T) {
+	twoColumn := pdfparse.Options{Layout: pdfparse.LayoutTwoColumn}
+	twoColumnImport := importOptions
+	twoColumnImport.Layout = pdfparse.LayoutTwoColumn
+
+	tests := []struct {
+		golden  string
+		invoice string
+		opts    pdfparse.Options
+	}{
+		{"single_column", "single_column", pdfparse.Options{}},
+		{"single_column_import", "single_column", importOptions},
+		{"two_column", "two_column", twoColumn},
+		{"two_column_import", "two_column", twoColumnImport},
+		{"total_description", "total_description", pdfparse.Options{}},
+		{"total_description_import", "total_description", importOptions},
+		{"no_header", "no_header", pdfparse.Options{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", tt.invoice+".txt"))
+			require.NoError(t, err)
+
+			items := tt.opts.ParseInvoice(strings.Split(string(input), "\n"))
+			got, err := json.MarshalIndent(items, "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			goldenPath := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(goldenPath, got, 0o644))
+			}
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err, "run go test -update to create the golden file")
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestParseInvoice_DefaultsToSingleColumn(t *testing.T) {
+	lines := []string{
+		"LOT ITEM PRICE",
+		"Cast iron doorstop $30.00   Cut glass punch bowl $55.00",
+		"SUBTOTAL $85.00",
+	}
+
+	items := pdfparse.ParseInvoice(lines)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Cast iron doorstop $30.00 Cut glass punch bowl", items[0].Description)
+	assert.True(t, decimal.RequireFromString("55").Equal(items[0].Bid))
+}
+
+func TestSplitTwoColumnLine(t *testing.T) {
+	assert.Equal(t,
+		[]string{"101 Cast iron doorstop $30.00", "102 Cut glass punch bowl $55.00"},
+		pdfparse.SplitTwoColumnLine("101 Cast iron doorstop $30.00 102 Cut glass punch bowl $55.00"))
+	assert.Equal(t,
+		[]string{"105 Comic book lot $210.00"},
+		pdfparse.SplitTwoColumnLine("105 Comic book lot $210.00"))
+	assert.Equal(t,
+		[]string{"$30.00 $55.00 $70.00"},
+		pdfparse.SplitTwoColumnLine("$30.00 $55.00 $70.00"))
+}
+
+func TestParseCurrency(t *testing.T) {
+	tests := map[string]string{
+		"$1,250.00": "1250",
+		" 85.00 ":   "85",
+		"$ 0.00":    "0",
+		"n/a":       "0",
+	}
+	for in, want := range tests {
+		assert.True(t, decimal.RequireFromString(want).Equal(pdfparse.ParseCurrency(in)), in)
+	}
+}
@@ -0,0 +1,191 @@
+// internal/pkg/pdfparse/profile.go
+package pdfparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfileName names the built-in profile, used when an invoice selects no other
+const DefaultProfileName = "default"
+
+// Patterns of the default profile
+const (
+	defaultHeader = `(?i)(LOT.*PRICE|LEAD.*ITEM.*PRICE)`
+	defaultFooter = `(?i)(A payment of|SUBTOTAL)`
+	// an optional $ and thousands separators
+	defaultPrice = `\$?\s*\d{1,3}(?:,\d{3})*\.\d{2}`
+)
+
+var defaultProfile = mustProfile(ProfileConfig{Name: DefaultProfileName})
+
+// Profile holds the patterns that find the items on one auction house's invoices: the column
+// header they follow, the footer they end at and the price that ends each item
+type Profile struct {
+	Name string
+
+	header    *regexp.Regexp
+	footer    *regexp.Regexp
+	price     *regexp.Regexp // a price anywhere in a line, used to split two-column lines
+	linePrice *regexp.Regexp // a price ending a line
+}
+
+// ProfileConfig is the configured form of a Profile. Patterns are Go regular expressions
+// matched against each trimmed line; empty ones use the default profile's.
+type ProfileConfig struct {
+	Name   string `json:"name"`
+	Header string `json:"header,omitempty"`
+	Footer string `json:"footer,omitempty"`
+	// Price must not be anchored; it is anchored to the end of the line where needed
+	Price string `json:"price,omitempty"`
+	// AuctionIDs are the auctions whose invoices use the profile unless another is selected
+	AuctionIDs []int `json:"auction_ids,omitempty"`
+}
+
+// DefaultProfile returns the profile for the "LOT ... PRICE" invoices the parser was built
+// for, ending at SUBTOTAL or the payment note
+func DefaultProfile() *Profile {
+	return defaultProfile
+}
+
+// NewProfile compiles config's patterns
+func NewProfile(config ProfileConfig) (*Profile, error) {
+	if strings.TrimSpace(config.Name) == "" {
+		return nil, fmt.Errorf("invoice profile name is required")
+	}
+
+	compile := func(field, pattern, fallback string) (*regexp.Regexp, error) {
+		if pattern == "" {
+			pattern = fallback
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invoice profile %q: invalid %s pattern: %w", config.Name, field, err)
+		}
+		return re, nil
+	}
+
+	profile := &Profile{Name: config.Name}
+	var err error
+	if profile.header, err = compile("header", config.Header, defaultHeader); err != nil {
+		return nil, err
+	}
+	if profile.footer, err = compile("footer", config.Footer, defaultFooter); err != nil {
+		return nil, err
+	}
+	if profile.price, err = compile("price", config.Price, defaultPrice); err != nil {
+		return nil, err
+	}
+	profile.linePrice = regexp.MustCompile(`(?:` + profile.price.String() + `)\s*$`)
+
+	return profile, nil
+}
+
+func mustProfile(config ProfileConfig) *Profile {
+	profile, err := NewProfile(config)
+	if err != nil {
+		panic(err)
+	}
+	return profile
+}
+
+// Profiles are the invoice profiles of a deployment, by name and by the auctions that use them.
+// A nil *Profiles holds only the default profile.
+type Profiles struct {
+	byName    map[string]*Profile
+	byAuction map[int]*Profile
+}
+
+// NewProfiles compiles configs, rejecting duplicate names and auctions claimed by two profiles.
+// The default profile is built in and cannot be redefined.
+func NewProfiles(configs []ProfileConfig) (*Profiles, error) {
+	profiles := &Profiles{
+		byName:    map[string]*Profile{DefaultProfileName: defaultProfile},
+		byAuction: make(map[int]*Profile),
+	}
+
+	for _, config := range configs {
+		if _, ok := profiles.byName[config.Name]; ok {
+			return nil, fmt.Errorf("invoice profile %q is defined more than once", config.Name)
+		}
+		profile, err := NewProfile(config)
+		if err != nil {
+			return nil, err
+		}
+		profiles.byName[profile.Name] = profile
+
+		for _, auctionID := range config.AuctionIDs {
+			if other, ok := profiles.byAuction[auctionID]; ok {
+				return nil, fmt.Errorf("auction %d is assigned to invoice profiles %q and %q", auctionID, other.Name, profile.Name)
+			}
+			profiles.byAuction[auctionID] = profile
+		}
+	}
+
+	return profiles, nil
+}
+
+// LoadProfiles reads a JSON array of ProfileConfig from path. An empty path loads only the
+// default profile.
+func LoadProfiles(path string) (*Profiles, error) {
+	if path == "" {
+		return NewProfiles(nil)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read invoice profiles: %w", err)
+	}
+	var configs []ProfileConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse invoice profiles %s: %w", path, err)
+	}
+	return NewProfiles(configs)
+}
+
+// Lookup returns the profile called name; an empty name is the default profile
+func (p *Profiles) Lookup(name string) (*Profile, bool) {
+	if name == "" || name == DefaultProfileName {
+		return defaultProfile, true
+	}
+	if p == nil {
+		return nil, false
+	}
+	profile, ok := p.byName[name]
+	return profile, ok
+}
+
+// Resolve picks the profile for an invoice: the one it names, else the one assigned to its
+// auction, else the default
+func (p *Profiles) Resolve(name string, auctionID int) (*Profile, error) {
+	if name != "" {
+		profile, ok := p.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown invoice profile %q (known: %s)", name, strings.Join(p.Names(), ", "))
+		}
+		return profile, nil
+	}
+	if p != nil {
+		if profile, ok := p.byAuction[auctionID]; ok {
+			return profile, nil
+		}
+	}
+	return defaultProfile, nil
+}
+
+// Names returns the profile names in order
+func (p *Profiles) Names() []string {
+	if p == nil {
+		return []string{DefaultProfileName}
+	}
+	names := make([]string, 0, len(p.byName))
+	for name := range p.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,160 @@
+// internal/pkg/pdfparse/profile_test.go
+package pdfparse_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
+)
+
+func readInvoice(t *testing.T, name string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return strings.Split(string(data), "\n")
+}
+
+type item struct {
+	description string
+	bid         string
+}
+
+func assertItems(t *testing.T, want []item, got []pdfparse.ParsedItem) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i, w := range want {
+		assert.Equal(t, w.description, got[i].Description)
+		assert.True(t, decimal.RequireFromString(w.bid).Equal(got[i].Bid), "item %d bid: got %s, want %s", i, got[i].Bid, w.bid)
+	}
+}
+
+func TestProfiles_ParseAuctionHouseInvoices(t *testing.T) {
+	profiles, err := pdfparse.LoadProfiles(filepath.Join("testdata", "invoice_profiles.json"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"balance-due", "default", "hammer-usd"}, profiles.Names())
+
+	tests := []struct {
+		name      string
+		invoice   string
+		auctionID int
+		want      []item
+	}{
+		{
+			name:      "item_description_amount_with_balance_due",
+			invoice:   "balance_due.txt",
+			auctionID: 302,
+			want: []item{
+				{"Oak library table, two drawers", "310"},
+				{"Pair of Staffordshire dogs, one with repaired ear", "65"},
+				{"Box of vintage postcards", "12.5"},
+			},
+		},
+		{
+			name:      "hammer_prices_in_usd_with_amount_due",
+			invoice:   "hammer_usd.txt",
+			auctionID: 88,
+			want: []item{
+				{"Bronze figure of a stag", "1450"},
+				{"Watercolor, coastal scene, framed and glazed", "220"},
+				{"Chinese export porcelain bowl", "95"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := readInvoice(t, tt.invoice)
+
+			profile, err := profiles.Resolve("", tt.auctionID)
+			require.NoError(t, err)
+			assertItems(t, tt.want, pdfparse.Options{Profile: profile}.ParseInvoice(lines))
+
+			// The default patterns find neither the header nor the footer
+			assert.NotEqual(t, len(tt.want), len(pdfparse.ParseInvoice(lines)))
+		})
+	}
+}
+
+func TestProfiles_Resolve(t *testing.T) {
+	profiles, err := pdfparse.NewProfiles([]pdfparse.ProfileConfig{
+		{Name: "balance-due", Footer: "(?i)Balance Due", AuctionIDs: []int{7}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		profile   string
+		auctionID int
+		want      string
+		wantErr   string
+	}{
+		{name: "auction_default", auctionID: 7, want: "balance-due"},
+		{name: "unassigned_auction", auctionID: 8, want: pdfparse.DefaultProfileName},
+		{name: "named_profile_wins", profile: "default", auctionID: 7, want: pdfparse.DefaultProfileName},
+		{name: "unknown_profile", profile: "missing", wantErr: `unknown invoice profile "missing"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := profiles.Resolve(tt.profile, tt.auctionID)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, profile.Name)
+		})
+	}
+
+	// Without any configured profiles only the default exists
+	var none *pdfparse.Profiles
+	profile, err := none.Resolve("", 7)
+	require.NoError(t, err)
+	assert.Same(t, pdfparse.DefaultProfile(), profile)
+}
+
+func TestNewProfiles_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []pdfparse.ProfileConfig
+		wantErr string
+	}{
+		{
+			name:    "missing_name",
+			configs: []pdfparse.ProfileConfig{{Footer: "Balance Due"}},
+			wantErr: "name is required",
+		},
+		{
+			name:    "redefines_default",
+			configs: []pdfparse.ProfileConfig{{Name: "default", Footer: "Balance Due"}},
+			wantErr: `"default" is defined more than once`,
+		},
+		{
+			name:    "bad_pattern",
+			configs: []pdfparse.ProfileConfig{{Name: "broken", Header: "(ITEM"}},
+			wantErr: `invoice profile "broken": invalid header pattern`,
+		},
+		{
+			name: "auction_in_two_profiles",
+			configs: []pdfparse.ProfileConfig{
+				{Name: "a", AuctionIDs: []int{1}},
+				{Name: "b", AuctionIDs: []int{1}},
+			},
+			wantErr: `auction 1 is assigned to invoice profiles "a" and "b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pdfparse.NewProfiles(tt.configs)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// internal/pkg/qrcode/qrcode.go
+
+// Package qrcode encodes text as QR codes (versions 1-10) with github.com/skip2/go-qrcode and
+// renders them as PNG or SVG images
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+// Level is the error correction level, the share of the code that can be damaged and still
+// read
+type Level int
+
+// Error correction levels
+const (
+	LevelL Level = iota // ~7%
+	LevelM              // ~15%
+	LevelQ              // ~25%
+	LevelH              // ~30%
+)
+
+// recoveryLevels maps each Level to the library's
+var recoveryLevels = [4]goqrcode.RecoveryLevel{goqrcode.Low, goqrcode.Medium, goqrcode.High, goqrcode.Highest}
+
+// maxVersion is the largest symbol encoded; a version 10 code at level M holds 213 bytes, which
+// is plenty for lot IDs and deep links and keeps the modules large enough to scan on a label
+const maxVersion = 10
+
+// ErrTooLong is returned when the content doesn't fit in the largest supported version
+var ErrTooLong = errors.New("qrcode: content too long")
+
+// Code is an encoded QR code: a square grid of modules, true for dark
+type Code struct {
+	Version int
+	Level   Level
+	Size    int
+
+	modules [][]bool
+}
+
+// Module reports whether the module at column x, row y is dark. Coordinates outside the code
+// are light.
+func (c *Code) Module(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
+
+// Encode encodes content with the smallest version that fits at level
+func Encode(content string, level Level) (*Code, error) {
+	if level < LevelL || level > LevelH {
+		return nil, fmt.Errorf("qrcode: unknown error correction level %d", level)
+	}
+
+	q, err := goqrcode.New(content, recoveryLevels[level])
+	if err != nil {
+		return nil, fmt.Errorf("qrcode: failed to encode: %w", err)
+	}
+	if q.VersionNumber > maxVersion {
+		return nil, ErrTooLong
+	}
+
+	// The quiet zone is added when rendering
+	q.DisableBorder = true
+	modules := q.Bitmap()
+	return &Code{
+		Version: q.VersionNumber,
+		Level:   level,
+		Size:    len(modules),
+		modules: modules,
+	}, nil
+}
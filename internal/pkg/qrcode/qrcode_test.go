@@ -0,0 +1,185 @@
+// internal/pkg/qrcode/qrcode_test.go
+package qrcode_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/qrcode"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// render draws the code one character per module, '#' for dark and '.' for light
+func render(code *qrcode.Code) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "version %d\n", code.Version)
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Module(x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestEncode_Golden(t *testing.T) {
+	// The golden files pin the library's output, so an upgrade that changes the symbols shows up
+	tests := []struct {
+		golden  string
+		content string
+		level   qrcode.Level
+	}{
+		{"lot_id_m", "8f14e45f-ceea-467f-a0f6-2b3c1a9e6d0b", qrcode.LevelM},
+		{"deep_link_m", "https://resell.example.com/inventory/8f14e45f-ceea-467f-a0f6-2b3c1a9e6d0b?src=label", qrcode.LevelM},
+		{"unicode_h", "Ünïcödé lot – №42", qrcode.LevelH},
+		{"label_l", "LABEL-0042", qrcode.LevelL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			code, err := qrcode.Encode(tt.content, tt.level)
+			require.NoError(t, err)
+			assert.Equal(t, 4*code.Version+17, code.Size)
+			got := render(code)
+
+			goldenPath := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(goldenPath, []byte(got), 0o644))
+			}
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err, "run go test -update to create the golden file")
+			assert.Equal(t, string(want), got)
+		})
+	}
+}
+
+func TestEncode_SmallestVersion(t *testing.T) {
+	// Byte capacities at level M from the standard
+	capacities := []int{14, 26, 42, 62, 84, 106, 122, 152, 180, 213}
+	for i, capacity := range capacities {
+		version := i + 1
+
+		code, err := qrcode.Encode(strings.Repeat("a", capacity), qrcode.LevelM)
+		require.NoError(t, err)
+		assert.Equal(t, version, code.Version, "%d bytes", capacity)
+
+		code, err = qrcode.Encode(strings.Repeat("a", capacity+1), qrcode.LevelM)
+		if version == len(capacities) {
+			assert.ErrorIs(t, err, qrcode.ErrTooLong)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, version+1, code.Version, "%d bytes", capacity+1)
+	}
+}
+
+func TestEncode_UnknownLevel(t *testing.T) {
+	_, err := qrcode.Encode("lot", qrcode.Level(7))
+	assert.EqualError(t, err, "qrcode: unknown error correction level 7")
+}
+
+func TestEncode_Level(t *testing.T) {
+	// The level's two bits in the format information, which is masked with 0x5412 and read
+	// beside the top left finder
+	levelBits := map[qrcode.Level]int{qrcode.LevelL: 1, qrcode.LevelM: 0, qrcode.LevelQ: 3, qrcode.LevelH: 2}
+
+	for level, want := range levelBits {
+		code, err := qrcode.Encode("lot", level)
+		require.NoError(t, err)
+		assert.Equal(t, level, code.Level)
+
+		format := 0
+		for i := 0; i < 15; i++ {
+			var dark bool
+			switch {
+			case i < 6:
+				dark = code.Module(8, i)
+			case i < 8:
+				dark = code.Module(8, i+1)
+			case i == 8:
+				dark = code.Module(7, 8)
+			default:
+				dark = code.Module(14-i, 8)
+			}
+			if dark {
+				format |= 1 << i
+			}
+		}
+		assert.Equal(t, want, (format^0x5412)>>13, "level %d", level)
+	}
+}
+
+func TestEncode_FunctionPatterns(t *testing.T) {
+	code, err := qrcode.Encode("lot", qrcode.LevelM)
+	require.NoError(t, err)
+	require.Equal(t, 1, code.Version)
+
+	// Finder ring and centre at the top left, the timing pattern and the dark module
+	for i := 0; i < 7; i++ {
+		assert.True(t, code.Module(i, 0))
+		assert.True(t, code.Module(0, i))
+	}
+	assert.False(t, code.Module(1, 1))
+	assert.True(t, code.Module(3, 3))
+	assert.False(t, code.Module(7, 7), "separator")
+	for i := 8; i < code.Size-8; i++ {
+		assert.Equal(t, i%2 == 0, code.Module(i, 6))
+	}
+	assert.True(t, code.Module(8, code.Size-8))
+	assert.False(t, code.Module(-1, 0))
+}
+
+func TestPNG(t *testing.T) {
+	code, err := qrcode.Encode("8f14e45f-ceea-467f-a0f6-2b3c1a9e6d0b", qrcode.LevelM)
+	require.NoError(t, err)
+
+	data, err := code.PNG(4)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, (code.Size+2*qrcode.QuietZone)*4, img.Bounds().Dx())
+
+	// Sample the centre of each module, including the quiet zone
+	for y := -qrcode.QuietZone; y < code.Size+qrcode.QuietZone; y++ {
+		for x := -qrcode.QuietZone; x < code.Size+qrcode.QuietZone; x++ {
+			px := color.GrayModel.Convert(img.At((x+qrcode.QuietZone)*4+2, (y+qrcode.QuietZone)*4+2)).(color.Gray)
+			require.Equal(t, code.Module(x, y), px.Y < 0x80, "module %d,%d", x, y)
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	code, err := qrcode.Encode("lot", qrcode.LevelM)
+	require.NoError(t, err)
+
+	svg := string(code.SVG(10))
+	side := (code.Size + 2*qrcode.QuietZone) * 10
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Contains(t, svg, fmt.Sprintf(`width="%d" height="%d"`, side, side))
+	assert.Contains(t, svg, "M4,4h1v1h-1z", "top left finder corner")
+
+	dark := 0
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Module(x, y) {
+				dark++
+			}
+		}
+	}
+	assert.Equal(t, dark, strings.Count(svg, "h1v1h-1z"))
+}
@@ -0,0 +1,68 @@
+// internal/pkg/qrcode/render.go
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// QuietZone is the light border, in modules, that readers need around a code
+const QuietZone = 4
+
+// Image draws the code scale pixels per module, surrounded by the quiet zone
+func (c *Code) Image(scale int) *image.Gray {
+	scale = max(scale, 1)
+	side := (c.Size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			left, top := (x+QuietZone)*scale, (y+QuietZone)*scale
+			for py := top; py < top+scale; py++ {
+				for px := left; px < left+scale; px++ {
+					img.SetGray(px, py, color.Gray{})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG encodes the code's Image as a PNG
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Image(scale)); err != nil {
+		return nil, fmt.Errorf("qrcode: failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG returns the code as an SVG document scale pixels per module, with the quiet zone. The
+// dark modules are a single path in module units, so the image scales without blurring.
+func (c *Code) SVG(scale int) []byte {
+	scale = max(scale, 1)
+	units := c.Size + 2*QuietZone
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		units*scale, units*scale, units, units)
+	buf.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				fmt.Fprintf(&buf, "M%d,%dh1v1h-1z", x+QuietZone, y+QuietZone)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
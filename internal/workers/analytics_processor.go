@@ -2,21 +2,161 @@
 package workers
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"time"
 
-	"github.com/ammerola/resell-be/internal/adapters/db"
+	"github.com/ammerola/resell-be/internal/core/ports"
 )
 
+// RefreshAnalyticsPayload represents the payload for analytics refresh jobs. Both
+// fields are optional: refreshes queued after a sale carry neither.
+type RefreshAnalyticsPayload struct {
+	// JobID is the async_jobs row to report status on
+	JobID string `json:"job_id,omitempty"`
+	// LockKey is the Redis lock held by the requester, released once the refresh finishes
+	LockKey string `json:"lock_key,omitempty"`
+}
+
 // AnalyticsProcessor handles analytics refresh tasks
 type AnalyticsProcessor struct {
-	db     *db.Database
-	logger *slog.Logger
+	db      ports.Database
+	cache   ports.CacheRepository
+	reports ReportUploader // Destination for generated reports; nil disables report generation
+	logger  *slog.Logger
 }
 
-// NewAnalyticsProcessor creates a new analytics processor
-func NewAnalyticsProcessor(db *db.Database, logger *slog.Logger) *AnalyticsProcessor {
+// NewAnalyticsProcessor creates a new analytics processor. reports may be nil when
+// no artifact storage is configured, in which case report jobs fail without retrying.
+func NewAnalyticsProcessor(db ports.Database, cache ports.CacheRepository, reports ReportUploader, logger *slog.Logger) *AnalyticsProcessor {
 	return &AnalyticsProcessor{
-		db:     db,
-		logger: logger.With(slog.String("processor", "analytics")),
+		db:      db,
+		cache:   cache,
+		reports: reports,
+		logger:  logger.With(slog.String("processor", "analytics")),
+	}
+}
+
+// releaseRefreshLock frees the lock taken when the refresh was requested, if any
+func (p *AnalyticsProcessor) releaseRefreshLock(ctx context.Context, payload RefreshAnalyticsPayload) {
+	if payload.LockKey == "" || p.cache == nil {
+		return
+	}
+
+	released, err := p.cache.CompareAndDelete(ctx, payload.LockKey, payload.JobID)
+	if err != nil {
+		// The lock expires on its own, so this only delays the next refresh
+		p.logger.WarnContext(ctx, "failed to release refresh lock",
+			slog.String("lock_key", payload.LockKey),
+			slog.String("error", err.Error()))
+		return
+	}
+	if !released {
+		p.logger.WarnContext(ctx, "refresh lock expired before the refresh finished",
+			slog.String("lock_key", payload.LockKey),
+			slog.String("job_id", payload.JobID))
+	}
+}
+
+func (p *AnalyticsProcessor) updateJobStatus(ctx context.Context, jobID string, status string, errorMsg *string) error {
+	if jobID == "" {
+		return nil
+	}
+
+	query := `
+		UPDATE async_jobs 
+		SET status = $2, error = $3, updated_at = CURRENT_TIMESTAMP,
+		    started_at = CASE WHEN $2 = 'processing' THEN CURRENT_TIMESTAMP ELSE started_at END,
+		    completed_at = CASE WHEN $2 IN ('completed', 'failed') THEN CURRENT_TIMESTAMP ELSE completed_at END
+		WHERE id = $1`
+
+	_, err := p.db.Exec(ctx, query, jobID, status, errorMsg)
+	return err
+}
+
+func (p *AnalyticsProcessor) completeJob(ctx context.Context, jobID string, result interface{}) error {
+	if jobID == "" {
+		return nil
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job result: %w", err)
 	}
+
+	query := `
+		UPDATE async_jobs
+		SET status = 'completed', result = $2, error = NULL,
+		    completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+	_, err = p.db.Exec(ctx, query, jobID, json.RawMessage(resultJSON))
+	return err
+}
+
+// buildWeeklySummary aggregates acquisitions and sales in [start, end)
+func (p *AnalyticsProcessor) buildWeeklySummary(ctx context.Context, start, end time.Time) (*WeeklySummary, error) {
+	summary := &WeeklySummary{
+		WindowStart:   start,
+		WindowEnd:     end,
+		TopCategories: []CategorySummary{},
+	}
+
+	acquisitionsQuery := `
+		SELECT COUNT(*), COALESCE(SUM(total_cost), 0)
+		FROM inventory
+		WHERE deleted_at IS NULL AND acquisition_date >= $1 AND acquisition_date < $2`
+
+	if err := p.db.QueryRow(ctx, acquisitionsQuery, start, end).Scan(
+		&summary.ItemsAcquired, &summary.TotalSpend,
+	); err != nil {
+		return nil, fmt.Errorf("failed to aggregate acquisitions: %w", err)
+	}
+
+	// Net profit matches the export view: sale price less item cost and platform fees
+	salesQuery := `
+		SELECT COUNT(*), COALESCE(SUM(pl.sold_price), 0),
+		       COALESCE(SUM(pl.sold_price - i.total_cost - COALESCE(pl.platform_fees, 0)), 0)
+		FROM platform_listings pl
+		JOIN inventory i ON i.lot_id = pl.lot_id
+		WHERE pl.status = 'sold' AND i.deleted_at IS NULL
+		  AND pl.sold_date >= $1 AND pl.sold_date < $2`
+
+	if err := p.db.QueryRow(ctx, salesQuery, start, end).Scan(
+		&summary.ItemsSold, &summary.Revenue, &summary.NetProfit,
+	); err != nil {
+		return nil, fmt.Errorf("failed to aggregate sales: %w", err)
+	}
+
+	categoriesQuery := `
+		SELECT category::text, COUNT(*), COALESCE(SUM(total_cost), 0)
+		FROM inventory
+		WHERE deleted_at IS NULL AND acquisition_date >= $1 AND acquisition_date < $2
+		GROUP BY category
+		ORDER BY SUM(total_cost) DESC NULLS LAST, category
+		LIMIT $3`
+
+	rows, err := p.db.Query(ctx, categoriesQuery, start, end, reportTopCategoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top categories: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category CategorySummary
+		if err := rows.Scan(&category.Category, &category.ItemsAcquired, &category.TotalSpend); err != nil {
+			return nil, fmt.Errorf("failed to scan category summary: %w", err)
+		}
+		summary.TopCategories = append(summary.TopCategories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read top categories: %w", err)
+	}
+
+	summary.TotalSpend = summary.TotalSpend.Round(2)
+	summary.Revenue = summary.Revenue.Round(2)
+	summary.NetProfit = summary.NetProfit.Round(2)
+	return summary, nil
 }
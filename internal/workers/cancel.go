@@ -0,0 +1,43 @@
+// internal/workers/cancel.go
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// JobStatusCancelled marks an async job the user cancelled. Workers stop at the next
+// chunk boundary and never move a cancelled job to another status.
+const JobStatusCancelled = "cancelled"
+
+// errJobCancelled stops an import once its job has been cancelled
+var errJobCancelled = errors.New("job was cancelled")
+
+// jobCancelled reports whether the job has been cancelled. A failed lookup is logged and
+// treated as not cancelled so a database hiccup doesn't abandon the import.
+func jobCancelled(ctx context.Context, db ports.Database, jobID string, logger *slog.Logger) bool {
+	var cancelled bool
+	err := db.QueryRow(ctx, `SELECT status = 'cancelled' FROM async_jobs WHERE id = $1`, jobID).Scan(&cancelled)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to check job cancellation",
+			slog.String("job_id", jobID),
+			slog.String("error", err.Error()))
+		return false
+	}
+	return cancelled
+}
+
+// recordCancelledResult stores what a cancelled job got done before it stopped
+func recordCancelledResult(ctx context.Context, db ports.Database, jobID string, result json.RawMessage) error {
+	query := `
+		UPDATE async_jobs
+		SET result = $2, completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'cancelled'`
+
+	_, err := db.Exec(ctx, query, jobID, result)
+	return err
+}
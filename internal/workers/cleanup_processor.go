@@ -3,83 +3,319 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/ammerola/resell-be/internal/adapters/db"
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/internal/core/ports"
 	"github.com/ammerola/resell-be/internal/pkg/config"
 	"github.com/hibiken/asynq"
 )
 
+// purgeInventoryQuery hard-deletes one batch of inventory rows soft-deleted before $1. Each
+// purged row gets a delete entry in the audit trail, which outlives the item. Child listings
+// and activity logs go with it through ON DELETE CASCADE.
+const purgeInventoryQuery = `
+	WITH purged AS (
+		DELETE FROM inventory
+		WHERE lot_id IN (
+			SELECT lot_id FROM inventory
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING lot_id, deleted_at
+	)
+	INSERT INTO inventory_audit (lot_id, operation, changes)
+	SELECT lot_id, 'delete', jsonb_build_object('deleted_at', jsonb_build_object('old', deleted_at, 'new', NULL))
+	FROM purged`
+
+// purgeJobsQuery deletes one batch of finished async jobs that completed before $1. Queued and
+// running jobs are never touched.
+const purgeJobsQuery = `
+	DELETE FROM async_jobs
+	WHERE id IN (
+		SELECT id FROM async_jobs
+		WHERE status IN ('completed', 'completed_with_errors', 'failed', 'cancelled')
+		AND COALESCE(completed_at, updated_at, created_at) < $1
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)`
+
+// purgeActivityLogsQuery deletes one batch of activity logs created before $1
+const purgeActivityLogsQuery = `
+	DELETE FROM activity_logs
+	WHERE id IN (
+		SELECT id FROM activity_logs
+		WHERE created_at < $1
+		LIMIT $2
+	)`
+
 // CleanupProcessor handles cleanup tasks
 type CleanupProcessor struct {
-	db     *db.Database
+	db     ports.Database
+	files  UploadStore
 	config *config.Config
 	logger *slog.Logger
 }
 
 // NewCleanupProcessor creates a new cleanup processor
-func NewCleanupProcessor(db *db.Database, config *config.Config, logger *slog.Logger) *CleanupProcessor {
+func NewCleanupProcessor(db ports.Database, files UploadStore, config *config.Config, logger *slog.Logger) *CleanupProcessor {
 	return &CleanupProcessor{
 		db:     db,
+		files:  files,
 		config: config,
 		logger: logger.With(slog.String("processor", "cleanup")),
 	}
 }
 
-// CleanupOldData removes old data from the database
+// CleanupOldData applies the retention policy: soft-deleted inventory, finished jobs and
+// activity logs older than their configured retention are permanently removed. A zero
+// retention keeps that data forever.
 func (p *CleanupProcessor) CleanupOldData(ctx context.Context, t *asynq.Task) error {
-	p.logger.InfoContext(ctx, "cleaning up old data")
+	retention := p.config.Retention
+	p.logger.InfoContext(ctx, "cleaning up old data",
+		slog.Duration("soft_delete_retention", retention.SoftDeleted),
+		slog.Duration("job_retention", retention.Jobs),
+		slog.Duration("activity_log_retention", retention.ActivityLogs))
 
-	// Clean up old activity logs (older than 90 days)
-	query := `DELETE FROM activity_logs WHERE created_at < NOW() - INTERVAL '90 days'`
-
-	result, err := p.db.Exec(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to cleanup activity logs: %w", err)
+	purges := []struct {
+		name      string
+		query     string
+		retention time.Duration
+	}{
+		{name: "inventory", query: purgeInventoryQuery, retention: retention.SoftDeleted},
+		{name: "async_jobs", query: purgeJobsQuery, retention: retention.Jobs},
+		{name: "activity_logs", query: purgeActivityLogsQuery, retention: retention.ActivityLogs},
 	}
 
-	p.logger.InfoContext(ctx, "old data cleaned up",
-		slog.Int64("rows_deleted", result.RowsAffected()))
+	for _, purge := range purges {
+		if purge.retention <= 0 {
+			continue
+		}
+
+		cutoff := time.Now().Add(-purge.retention)
+		deleted, err := p.deleteInBatches(ctx, purge.query, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to purge %s: %w", purge.name, err)
+		}
+
+		p.logger.InfoContext(ctx, "old data purged",
+			slog.String("table", purge.name),
+			slog.Time("cutoff", cutoff),
+			slog.Int64("rows_deleted", deleted))
+	}
 
 	return nil
 }
 
-// CleanupTempFiles removes old temporary files
+// deleteInBatches runs a batched delete until a batch comes back short. Each batch is its own
+// statement, so locks are held only briefly.
+func (p *CleanupProcessor) deleteInBatches(ctx context.Context, query string, cutoff time.Time) (int64, error) {
+	batchSize := p.config.Retention.BatchSize
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		result, err := p.db.Exec(ctx, query, cutoff, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += result.RowsAffected()
+		if result.RowsAffected() < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// importUploadPrefix is where the API stores import uploads until a worker is done with them
+const importUploadPrefix = "imports/"
+
+// defaultTempFileMaxAge applies when FileProcessing.CleanupInterval isn't set
+const defaultTempFileMaxAge = 24 * time.Hour
+
+// activeUploadsQuery selects the uploads still needed by a job. A failed job may be retried,
+// so its upload is kept until the job has been idle for longer than the cutoff in $1.
+const activeUploadsQuery = `
+	SELECT DISTINCT payload->>'file_key'
+	FROM async_jobs
+	WHERE payload ? 'file_key'
+	AND (status IN ('queued', 'processing') OR (status = 'failed' AND updated_at >= $1))`
+
+// UploadStore lists and removes stored uploads; storage.StorageClient satisfies it
+type UploadStore interface {
+	ListObjects(ctx context.Context, prefix string) ([]storage.ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// CleanupTempFiles removes files left behind by crashed imports: local temp files and stored
+// uploads older than FileProcessing.CleanupInterval. Uploads still referenced by an active
+// job are kept.
 func (p *CleanupProcessor) CleanupTempFiles(ctx context.Context, t *asynq.Task) error {
-	p.logger.InfoContext(ctx, "cleaning up temp files")
+	maxAge := p.config.FileProcessing.CleanupInterval
+	if maxAge <= 0 {
+		maxAge = defaultTempFileMaxAge
+	}
+	cutoff := time.Now().Add(-maxAge)
+
+	p.logger.InfoContext(ctx, "cleaning up temp files", slog.Duration("max_age", maxAge))
+
+	tempFiles, tempBytes, err := p.cleanupTempDir(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	uploads, uploadBytes, err := p.cleanupUploads(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	p.logger.InfoContext(ctx, "temp files cleaned up",
+		slog.Int("temp_files_deleted", tempFiles),
+		slog.Int("uploads_deleted", uploads),
+		slog.Int64("bytes_reclaimed", tempBytes+uploadBytes))
+
+	return nil
+}
 
+// cleanupTempDir removes files in FileProcessing.TempDir last modified before cutoff. The local
+// storage root is skipped when it sits inside the temp dir; its uploads are handled by
+// cleanupUploads, which knows which ones are still in use.
+func (p *CleanupProcessor) cleanupTempDir(ctx context.Context, cutoff time.Time) (int, int64, error) {
 	tempDir := p.config.FileProcessing.TempDir
-	maxAge := 24 * time.Hour
+	if tempDir == "" {
+		return 0, 0, nil
+	}
+
+	storageRoot := ""
+	if p.config.FileProcessing.LocalStoragePath != "" {
+		storageRoot = filepath.Clean(p.config.FileProcessing.LocalStoragePath)
+	}
 
-	var deletedCount int
-	err := filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
+	var deleted int
+	var reclaimed int64
+	err := filepath.WalkDir(tempDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
-			return err
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if path == tempDir {
+				return err
+			}
+			// A shared temp dir can hold entries owned by other users; skip what we can't read
+			p.logger.DebugContext(ctx, "skipping unreadable temp entry",
+				slog.String("path", path),
+				slog.String("error", err.Error()))
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
-
-		if !info.IsDir() && time.Since(info.ModTime()) > maxAge {
-			if err := os.Remove(path); err != nil {
-				p.logger.WarnContext(ctx, "failed to delete temp file",
-					slog.String("file", path),
-					err)
-			} else {
-				deletedCount++
+		if d.IsDir() {
+			if storageRoot != "" && filepath.Clean(path) == storageRoot {
+				return filepath.SkipDir
 			}
+			return nil
 		}
 
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			p.logger.WarnContext(ctx, "failed to delete temp file",
+				slog.String("file", path),
+				slog.String("error", err.Error()))
+			return nil
+		}
+		deleted++
+		reclaimed += info.Size()
 		return nil
 	})
+	if err != nil {
+		return deleted, reclaimed, fmt.Errorf("failed to walk temp directory: %w", err)
+	}
 
+	return deleted, reclaimed, nil
+}
+
+// cleanupUploads removes stored import uploads last modified before cutoff that no active job
+// references
+func (p *CleanupProcessor) cleanupUploads(ctx context.Context, cutoff time.Time) (int, int64, error) {
+	if p.files == nil {
+		return 0, 0, nil
+	}
+
+	objects, err := p.files.ListObjects(ctx, importUploadPrefix)
 	if err != nil {
-		return fmt.Errorf("failed to walk temp directory: %w", err)
+		return 0, 0, fmt.Errorf("failed to list uploads: %w", err)
 	}
 
-	p.logger.InfoContext(ctx, "temp files cleaned up",
-		slog.Int("files_deleted", deletedCount))
+	var stale []storage.ObjectInfo
+	for _, obj := range objects {
+		if obj.LastModified.Before(cutoff) {
+			stale = append(stale, obj)
+		}
+	}
+	if len(stale) == 0 {
+		return 0, 0, nil
+	}
 
-	return nil
+	active, err := p.activeUploads(ctx, cutoff)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var deleted int
+	var reclaimed int64
+	for _, obj := range stale {
+		if active[obj.Key] {
+			continue
+		}
+
+		if err := p.files.Delete(ctx, obj.Key); err != nil {
+			p.logger.WarnContext(ctx, "failed to delete orphaned upload",
+				slog.String("key", obj.Key),
+				slog.String("error", err.Error()))
+			continue
+		}
+		deleted++
+		reclaimed += obj.Size
+	}
+
+	return deleted, reclaimed, nil
+}
+
+// activeUploads returns the keys of uploads that queued, running or retryable jobs still need
+func (p *CleanupProcessor) activeUploads(ctx context.Context, cutoff time.Time) (map[string]bool, error) {
+	rows, err := p.db.Query(ctx, activeUploadsQuery, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active uploads: %w", err)
+	}
+	defer rows.Close()
+
+	active := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan active upload: %w", err)
+		}
+		active[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query active uploads: %w", err)
+	}
+
+	return active, nil
 }
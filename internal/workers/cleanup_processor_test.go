@@ -0,0 +1,224 @@
+// internal/workers/cleanup_processor_test.go
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+// softDeletedStore stands in for the inventory table, applying the purge's cutoff and batch limit
+type softDeletedStore struct {
+	deletedAt map[string]time.Time
+	batches   []int
+}
+
+func (s *softDeletedStore) purge(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if !strings.Contains(sql, "DELETE FROM inventory") || !strings.Contains(sql, "INSERT INTO inventory_audit") {
+		return pgconn.CommandTag{}, fmt.Errorf("unexpected query: %s", sql)
+	}
+	cutoff := args[0].(time.Time)
+	limit := args[1].(int)
+
+	var expired []string
+	for id, deletedAt := range s.deletedAt {
+		if deletedAt.Before(cutoff) {
+			expired = append(expired, id)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return s.deletedAt[expired[i]].Before(s.deletedAt[expired[j]]) })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	for _, id := range expired {
+		delete(s.deletedAt, id)
+	}
+	s.batches = append(s.batches, len(expired))
+	return pgconn.NewCommandTag(fmt.Sprintf("INSERT 0 %d", len(expired))), nil
+}
+
+func TestCleanupProcessor_CleanupOldData_PurgesExpiredSoftDeletes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+	store := &softDeletedStore{deletedAt: map[string]time.Time{
+		"deleted-yesterday": days(1),
+		"deleted-last-week": days(7),
+		"almost-expired":    days(89),
+		"just-expired":      days(91),
+		"half-year-old":     days(180),
+		"year-old":          days(365),
+	}}
+
+	cfg := helpers.LoadTestConfig()
+	cfg.Retention.SoftDeleted = 90 * 24 * time.Hour
+	cfg.Retention.Jobs = 0
+	cfg.Retention.ActivityLogs = 0
+	cfg.Retention.BatchSize = 2
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(store.purge).Times(2)
+
+	processor := workers.NewCleanupProcessor(mockDB, nil, cfg, helpers.TestLogger())
+	err := processor.CleanupOldData(context.Background(), asynq.NewTask(workers.TypeCleanupOldData, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{2, 1}, store.batches, "expired rows are removed in batches until one comes back short")
+	assert.ElementsMatch(t, []string{"deleted-yesterday", "deleted-last-week", "almost-expired"}, lotIDs(store.deletedAt))
+}
+
+func TestCleanupProcessor_CleanupOldData_PurgesEachTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := helpers.LoadTestConfig()
+	cfg.Retention.BatchSize = 500
+
+	var tables []string
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+			for _, table := range []string{"inventory", "async_jobs", "activity_logs"} {
+				if strings.Contains(sql, "DELETE FROM "+table+"\n") {
+					tables = append(tables, table)
+				}
+			}
+			assert.Equal(t, 500, args[1])
+			return pgconn.NewCommandTag("DELETE 0"), nil
+		}).
+		Times(3)
+
+	processor := workers.NewCleanupProcessor(mockDB, nil, cfg, helpers.TestLogger())
+	err := processor.CleanupOldData(context.Background(), asynq.NewTask(workers.TypeCleanupOldData, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"inventory", "async_jobs", "activity_logs"}, tables)
+}
+
+func TestCleanupProcessor_CleanupOldData_JobsKeepActiveRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := helpers.LoadTestConfig()
+	cfg.Retention.SoftDeleted = 0
+	cfg.Retention.ActivityLogs = 0
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+			assert.Contains(t, sql, "DELETE FROM async_jobs")
+			assert.Contains(t, sql, "status IN ('completed', 'completed_with_errors', 'failed', 'cancelled')")
+			assert.WithinDuration(t, time.Now().Add(-cfg.Retention.Jobs), args[0].(time.Time), time.Minute)
+			return pgconn.NewCommandTag("DELETE 4"), nil
+		})
+
+	processor := workers.NewCleanupProcessor(mockDB, nil, cfg, helpers.TestLogger())
+	err := processor.CleanupOldData(context.Background(), asynq.NewTask(workers.TypeCleanupOldData, nil))
+	require.NoError(t, err)
+}
+
+func TestCleanupProcessor_CleanupOldData_DeleteFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("lock timeout"))
+
+	processor := workers.NewCleanupProcessor(mockDB, nil, helpers.LoadTestConfig(), helpers.TestLogger())
+	err := processor.CleanupOldData(context.Background(), asynq.NewTask(workers.TypeCleanupOldData, nil))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to purge inventory")
+}
+
+func TestCleanupProcessor_CleanupTempFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stale := time.Now().Add(-3 * time.Hour)
+
+	// The local storage root sits inside the temp dir, as with the default /tmp/resell-storage
+	tempDir := t.TempDir()
+	storageRoot := filepath.Join(tempDir, "resell-storage")
+	files := storage.NewLocalStorage(storageRoot, helpers.TestLogger())
+
+	writeFile := func(path, content string, modTime time.Time) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	writeFile(filepath.Join(tempDir, "import-fresh.pdf"), "fresh", time.Now())
+	writeFile(filepath.Join(tempDir, "import-stale.pdf"), "stale pdf", stale)
+	writeFile(filepath.Join(tempDir, "nested", "import-stale.xlsx"), "stale sheet", stale)
+	writeFile(filepath.Join(storageRoot, "imports", "fresh.pdf"), "just uploaded", time.Now())
+	writeFile(filepath.Join(storageRoot, "imports", "active.pdf"), "still queued", stale)
+	writeFile(filepath.Join(storageRoot, "imports", "orphan.pdf"), "crashed import", stale)
+	writeFile(filepath.Join(storageRoot, "reports", "old.xlsx"), "report", stale)
+
+	cfg := helpers.LoadTestConfig()
+	cfg.FileProcessing.TempDir = tempDir
+	cfg.FileProcessing.LocalStoragePath = storageRoot
+	cfg.FileProcessing.CleanupInterval = time.Hour
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+			assert.Contains(t, sql, "payload->>'file_key'")
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), args[0].(time.Time), time.Minute)
+			return &fakeRows{values: [][]any{{"imports/active.pdf"}}}, nil
+		})
+
+	processor := workers.NewCleanupProcessor(mockDB, files, cfg, helpers.TestLogger())
+	require.NoError(t, processor.CleanupTempFiles(ctx, asynq.NewTask(workers.TypeCleanupTempFiles, nil)))
+
+	assert.FileExists(t, filepath.Join(tempDir, "import-fresh.pdf"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "import-stale.pdf"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "nested", "import-stale.xlsx"))
+
+	remaining, err := files.List(ctx, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"imports/fresh.pdf", "imports/active.pdf", "reports/old.xlsx"}, remaining,
+		"only the stale upload no job references is removed")
+}
+
+func TestCleanupProcessor_CleanupTempFiles_MissingTempDir(t *testing.T) {
+	cfg := helpers.LoadTestConfig()
+	cfg.FileProcessing.TempDir = filepath.Join(t.TempDir(), "missing")
+
+	files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+	processor := workers.NewCleanupProcessor(nil, files, cfg, helpers.TestLogger())
+
+	// With no stale uploads the job table isn't queried
+	require.NoError(t, processor.CleanupTempFiles(context.Background(), asynq.NewTask(workers.TypeCleanupTempFiles, nil)))
+}
+
+func lotIDs(m map[string]time.Time) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
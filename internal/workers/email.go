@@ -0,0 +1,167 @@
+// internal/workers/email.go
+package workers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+)
+
+// Email is a rendered message ready for delivery
+type Email struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers rendered emails
+type EmailSender interface {
+	Send(ctx context.Context, email *Email) error
+}
+
+// PermanentEmailError marks a delivery failure that will not succeed on retry
+type PermanentEmailError struct {
+	Err error
+}
+
+func (e *PermanentEmailError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentEmailError) Unwrap() error {
+	return e.Err
+}
+
+// LogEmailSender logs emails instead of sending them, for development
+type LogEmailSender struct {
+	logger *slog.Logger
+}
+
+// NewLogEmailSender creates a sender that only logs
+func NewLogEmailSender(logger *slog.Logger) *LogEmailSender {
+	return &LogEmailSender{logger: logger.With(slog.String("email_sender", "log"))}
+}
+
+// Send logs the email
+func (s *LogEmailSender) Send(ctx context.Context, email *Email) error {
+	s.logger.InfoContext(ctx, "email would be sent",
+		slog.String("to", email.To),
+		slog.String("subject", email.Subject),
+		slog.String("body", email.Body))
+	return nil
+}
+
+// SESEmailSender sends email through the Amazon SES v2 SendEmail API
+type SESEmailSender struct {
+	credentials aws.CredentialsProvider
+	region      string
+	endpoint    string
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// NewSESEmailSender creates an SES sender using the application's AWS configuration
+func NewSESEmailSender(ctx context.Context, cfg config.AWSConfig) (*SESEmailSender, error) {
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" && cfg.SecretAccessKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &SESEmailSender{
+		credentials: awsCfg.Credentials,
+		region:      cfg.Region,
+		endpoint:    fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", cfg.Region),
+		signer:      v4.NewSigner(),
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text sesContent `json:"Text"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+// Send signs and posts a SendEmail request. Throttling and server errors are
+// returned as-is so the task is retried; other 4xx responses are permanent.
+func (s *SESEmailSender) Send(ctx context.Context, email *Email) error {
+	var req sesSendEmailRequest
+	req.FromEmailAddress = email.From
+	req.Destination.ToAddresses = []string{email.To}
+	req.Content.Simple.Subject = sesContent{Data: email.Subject, Charset: "UTF-8"}
+	req.Content.Simple.Body.Text = sesContent{Data: email.Body, Charset: "UTF-8"}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SES request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create SES request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(body)
+	if err := s.signer.SignHTTP(ctx, creds, httpReq, hex.EncodeToString(hash[:]), "ses", s.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign SES request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call SES: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	err = fmt.Errorf("SES returned status %d: %s", resp.StatusCode, respBody)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return &PermanentEmailError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+// internal/workers/email_templates.go
+package workers
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Email template names accepted in SendEmailPayload.Template
+const (
+	EmailTemplateImportCompleted = "import_completed"
+	EmailTemplateWeeklySummary   = "weekly_summary"
+)
+
+type emailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var emailTemplates = map[string]emailTemplate{
+	EmailTemplateImportCompleted: newEmailTemplate(EmailTemplateImportCompleted,
+		`Import completed: {{.file_name}}`,
+		`Your import of {{.file_name}} has finished.
+
+Items imported: {{.items_imported}}
+Items failed: {{.items_failed}}
+{{with index . "errors"}}
+Errors:
+{{range .}}- {{.}}
+{{end}}{{end}}
+Job ID: {{.job_id}}
+`),
+	EmailTemplateWeeklySummary: newEmailTemplate(EmailTemplateWeeklySummary,
+		`Weekly summary for {{.week_start}}`,
+		`Here is your resale summary for the week of {{.week_start}}.
+
+Items acquired: {{.items_acquired}}
+Items sold: {{.items_sold}}
+Revenue: ${{.revenue}}
+Profit: ${{.profit}}
+`),
+}
+
+func newEmailTemplate(name, subject, body string) emailTemplate {
+	// missingkey=error makes incomplete template data a render failure instead of "<no value>"
+	return emailTemplate{
+		subject: template.Must(template.New(name + "_subject").Option("missingkey=error").Parse(subject)),
+		body:    template.Must(template.New(name + "_body").Option("missingkey=error").Parse(body)),
+	}
+}
+
+// RenderEmail renders the named template into a subject and body
+func RenderEmail(name string, data map[string]interface{}) (subject, body string, err error) {
+	tmpl, ok := emailTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template: %s", name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.subject.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("failed to render subject for %s: %w", name, err)
+	}
+	subject = buf.String()
+
+	buf.Reset()
+	if err := tmpl.body.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("failed to render body for %s: %w", name, err)
+	}
+
+	return subject, buf.String(), nil
+}
@@ -2,183 +2,658 @@
 package workers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"os"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/shopspring/decimal"
 	"github.com/tealeg/xlsx/v3"
 
-	"github.com/ammerola/resell-be/internal/adapters/db"
 	"github.com/ammerola/resell-be/internal/core/domain"
-	"github.com/ammerola/resell-be/internal/core/services"
+	"github.com/ammerola/resell-be/internal/core/ports"
 )
 
+// Excel import columns. Headers are matched case-insensitively, ignoring spaces,
+// hyphens and apostrophes, so the headers produced by the Excel export are accepted too.
+// invoice_id, item_name and bid_amount are required; all other columns are optional.
+const (
+	ExcelColInvoiceID       = "invoice_id"
+	ExcelColAuctionID       = "auction_id"
+	ExcelColItemName        = "item_name"
+	ExcelColDescription     = "description"
+	ExcelColCategory        = "category"
+	ExcelColSubcategory     = "subcategory"
+	ExcelColCondition       = "condition"
+	ExcelColQuantity        = "quantity"
+	ExcelColBidAmount       = "bid_amount"
+	ExcelColBuyersPremium   = "buyers_premium"
+	ExcelColSalesTax        = "sales_tax"
+	ExcelColShippingCost    = "shipping_cost"
+	ExcelColAcquisitionDate = "acquisition_date"
+	ExcelColStorageLocation = "storage_location"
+	ExcelColStorageBin      = "storage_bin"
+	ExcelColKeywords        = "keywords"
+	ExcelColNotes           = "notes"
+)
+
+// excelSaveChunkSize is how many rows are saved between checks for a cancelled job
+const excelSaveChunkSize = 100
+
+var (
+	excelKnownColumns = []string{
+		ExcelColInvoiceID, ExcelColAuctionID, ExcelColItemName, ExcelColDescription,
+		ExcelColCategory, ExcelColSubcategory, ExcelColCondition, ExcelColQuantity,
+		ExcelColBidAmount, ExcelColBuyersPremium, ExcelColSalesTax, ExcelColShippingCost,
+		ExcelColAcquisitionDate, ExcelColStorageLocation, ExcelColStorageBin,
+		ExcelColKeywords, ExcelColNotes,
+	}
+
+	excelRequiredColumns = []string{ExcelColInvoiceID, ExcelColItemName, ExcelColBidAmount}
+
+	excelDateLayouts = []string{"2006-01-02", "01/02/2006", "1/2/2006", "1/2/06", time.RFC3339}
+)
+
+// ExcelJobPayload represents the payload for Excel import jobs
+type ExcelJobPayload struct {
+	JobID        string `json:"job_id"`
+	FileKey      string `json:"file_key"` // Storage key of the uploaded workbook
+	BatchID      string `json:"batch_id,omitempty"`
+	ValidateOnly bool   `json:"validate_only,omitempty"` // Parse and validate rows without saving
+	FileName     string `json:"file_name,omitempty"`     // Original upload name, used in notifications
+	NotifyEmail  string `json:"notify_email,omitempty"`
+}
+
+// ExcelRowError describes a spreadsheet row that failed validation
+type ExcelRowError struct {
+	Row   int    `json:"row"`
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// ExcelJobResult represents the result of Excel import processing
+type ExcelJobResult struct {
+	RowsProcessed  int             `json:"rows_processed"`
+	ValidRows      int             `json:"valid_rows"`
+	ItemsCreated   int             `json:"items_created"`
+	ValidateOnly   bool            `json:"validate_only,omitempty"`
+	Errors         []ExcelRowError `json:"errors,omitempty"`
+	ProcessingTime string          `json:"processing_time"`
+}
+
 // ExcelProcessor handles Excel import tasks
 type ExcelProcessor struct {
-	service *services.InventoryService
-	db      *db.Database
-	logger  *slog.Logger
+	service  ports.InventoryService
+	db       ports.Database
+	files    FileStore
+	logger   *slog.Logger
+	notifier *ImportNotifier // Emails NotifyEmail on completion; nil disables notifications
 }
 
-// NewExcelProcessor creates a new Excel processor
-func NewExcelProcessor(service *services.InventoryService, db *db.Database, logger *slog.Logger) *ExcelProcessor {
+// NewExcelProcessor creates a new Excel processor. notifier may be nil to disable completion emails.
+func NewExcelProcessor(service ports.InventoryService, db ports.Database, files FileStore, logger *slog.Logger, notifier *ImportNotifier) *ExcelProcessor {
 	return &ExcelProcessor{
-		service: service,
-		db:      db,
-		logger:  logger.With(slog.String("processor", "excel")),
+		service:  service,
+		db:       db,
+		files:    files,
+		logger:   logger.With(slog.String("processor", "excel")),
+		notifier: notifier,
 	}
 }
 
 // ProcessExcel processes an Excel file and imports inventory items
 func (p *ExcelProcessor) ProcessExcel(ctx context.Context, t *asynq.Task) error {
-	var payload map[string]interface{}
+	start := time.Now()
+
+	var payload ExcelJobPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
-	jobID := payload["job_id"].(string)
-	filePath := payload["file_path"].(string)
-
 	p.logger.InfoContext(ctx, "processing Excel file",
-		slog.String("job_id", jobID),
-		slog.String("file_path", filePath))
+		slog.String("job_id", payload.JobID),
+		slog.String("file_key", payload.FileKey),
+		slog.Bool("validate_only", payload.ValidateOnly))
+
+	// Update job status to processing
+	_ = p.updateJobStatus(ctx, payload.JobID, "processing", nil)
+
+	filePath, cleanup, err := fetchImportFile(ctx, p.files, payload.FileKey)
+	if err != nil {
+		errMsg := err.Error()
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		return err
+	}
+	defer cleanup()
+
+	sheet, err := p.parseExcelFile(filePath)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to parse Excel file: %v", err)
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		err = errors.New(errMsg)
+		if finalAttempt(ctx, err) {
+			deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
+		}
+		return err
+	}
+	items, rowErrors, rowCount := sheet.items, sheet.rowErrors, sheet.rowCount
+
+	// Dry runs stop after validation so the report reflects exactly what a real import would do
+	itemsCreated := 0
+	if !payload.ValidateOnly {
+		var saveErrors []ExcelRowError
+		itemsCreated, saveErrors, err = p.saveItems(ctx, payload.JobID, sheet)
+		rowErrors = append(rowErrors, saveErrors...)
+		if errors.Is(err, errJobCancelled) {
+			return p.stopCancelledJob(ctx, payload, rowCount, len(items), itemsCreated, rowErrors, start)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, ExcelRowError{Error: err.Error()})
+		}
+	}
+
+	// Prepare result and update job status
+	status := "completed"
+	if len(rowErrors) > 0 {
+		status = "completed_with_errors"
+	}
+
+	result := ExcelJobResult{
+		RowsProcessed:  rowCount,
+		ValidRows:      len(items),
+		ItemsCreated:   itemsCreated,
+		ValidateOnly:   payload.ValidateOnly,
+		Errors:         rowErrors,
+		ProcessingTime: time.Since(start).String(),
+	}
+
+	resultJSON, _ := json.Marshal(result)
+	_ = p.updateJobStatusWithResult(ctx, payload.JobID, status, resultJSON)
+
+	// Valid rows aren't failures on a dry run even though nothing was created
+	itemsFailed := rowCount - len(items)
+	if !payload.ValidateOnly {
+		itemsFailed += len(items) - itemsCreated
+	}
+	p.notifier.NotifyImportCompleted(ctx, payload.NotifyEmail, ImportSummary{
+		JobID:         payload.JobID,
+		FileName:      notificationFileName(payload.FileName, payload.FileKey),
+		ItemsImported: itemsCreated,
+		ItemsFailed:   itemsFailed,
+		Errors:        formatExcelRowErrors(rowErrors),
+	})
+
+	// The upload is no longer needed once it will not be retried
+	if finalAttempt(ctx, err) {
+		deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
+	}
+
+	p.logger.InfoContext(ctx, "Excel processing completed",
+		slog.String("job_id", payload.JobID),
+		slog.Int("rows_processed", rowCount),
+		slog.Int("items_created", itemsCreated),
+		slog.Int("row_errors", len(rowErrors)))
+
+	// Rows that failed are reported on the job; retrying would duplicate those already saved
+	return err
+}
+
+// saveItems saves the sheet's items in chunks of excelSaveChunkSize, checking between chunks
+// whether the job has been cancelled. Rows that fail to save are returned as row errors; the
+// error is only set when saving had to stop early, which is errJobCancelled if the job was cancelled.
+func (p *ExcelProcessor) saveItems(ctx context.Context, jobID string, sheet *excelSheet) (int, []ExcelRowError, error) {
+	saved := 0
+	var rowErrors []ExcelRowError
+	for offset := 0; offset < len(sheet.items); offset += excelSaveChunkSize {
+		if jobCancelled(ctx, p.db, jobID, p.logger) {
+			return saved, rowErrors, errJobCancelled
+		}
+
+		end := min(offset+excelSaveChunkSize, len(sheet.items))
+		report, err := p.service.SaveItemsPartial(ctx, sheet.items[offset:end])
+		saved += len(report.Saved)
+		for _, f := range report.Failed {
+			rowErrors = append(rowErrors, ExcelRowError{Row: sheet.itemRows[offset+f.Index], Error: f.Error})
+		}
+		if err != nil {
+			return saved, rowErrors, err
+		}
+	}
+	return saved, rowErrors, nil
+}
+
+// stopCancelledJob records the rows saved before the job was cancelled and drops the upload.
+// Nothing is retried and no completion email is sent since the user stopped the import.
+func (p *ExcelProcessor) stopCancelledJob(ctx context.Context, payload ExcelJobPayload, rowCount, validRows, itemsCreated int, rowErrors []ExcelRowError, start time.Time) error {
+	result := ExcelJobResult{
+		RowsProcessed:  rowCount,
+		ValidRows:      validRows,
+		ItemsCreated:   itemsCreated,
+		Errors:         rowErrors,
+		ProcessingTime: time.Since(start).String(),
+	}
+	resultJSON, _ := json.Marshal(result)
+	if err := recordCancelledResult(ctx, p.db, payload.JobID, resultJSON); err != nil {
+		p.logger.WarnContext(ctx, "failed to record cancelled job result",
+			slog.String("job_id", payload.JobID),
+			slog.String("error", err.Error()))
+	}
+
+	deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
 
-	// Open Excel file
+	p.logger.InfoContext(ctx, "Excel import cancelled",
+		slog.String("job_id", payload.JobID),
+		slog.Int("items_created", itemsCreated),
+		slog.Int("valid_rows", validRows))
+
+	return nil
+}
+
+// formatExcelRowErrors renders row errors as "row N (field): message" lines
+func formatExcelRowErrors(rowErrors []ExcelRowError) []string {
+	lines := make([]string, 0, len(rowErrors))
+	for _, e := range rowErrors {
+		switch {
+		case e.Row > 0 && e.Field != "":
+			lines = append(lines, fmt.Sprintf("row %d (%s): %s", e.Row, e.Field, e.Error))
+		case e.Row > 0:
+			lines = append(lines, fmt.Sprintf("row %d: %s", e.Row, e.Error))
+		default:
+			lines = append(lines, e.Error)
+		}
+	}
+	return lines
+}
+
+// excelSheet holds the parsed contents of an import sheet
+type excelSheet struct {
+	items     []domain.InventoryItem
+	itemRows  []int // spreadsheet row number of each item
+	rowErrors []ExcelRowError
+	rowCount  int
+}
+
+// parseExcelFile reads the first sheet and maps every data row to an inventory item.
+// Rows that fail validation are reported in the returned errors rather than aborting.
+func (p *ExcelProcessor) parseExcelFile(filePath string) (*excelSheet, error) {
 	file, err := xlsx.OpenFile(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open Excel file: %w", err)
+		return nil, fmt.Errorf("failed to open Excel file: %w", err)
+	}
+	if len(file.Sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheets")
 	}
 
-	var items []domain.InventoryItem
+	var (
+		columns map[string]int
+		sheet   excelSheet
+		rowNum  int
+	)
 
-	// Process first sheet
-	if len(file.Sheets) > 0 {
-		sheet := file.Sheets[0]
-		rowIdx := 0
+	err = file.Sheets[0].ForEachRow(func(r *xlsx.Row) error {
+		rowNum++
+		cells := excelRowValues(r)
 
-		err = sheet.ForEachRow(func(r *xlsx.Row) error {
-			// Skip header row
-			if rowIdx == 0 {
-				rowIdx++
-				return nil
+		if columns == nil {
+			mapped, err := mapExcelColumns(cells)
+			if err != nil {
+				return err
 			}
-			rowIdx++
+			columns = mapped
+			return nil
+		}
 
-			item := p.parseRow(r)
-			if item != nil {
-				items = append(items, *item)
-			}
+		if isBlankRow(cells) {
 			return nil
-		})
+		}
+		sheet.rowCount++
 
-		if err != nil {
-			return fmt.Errorf("failed to process Excel rows: %w", err)
+		item, errs := parseExcelRow(rowNum, cells, columns)
+		if len(errs) > 0 {
+			sheet.rowErrors = append(sheet.rowErrors, errs...)
+			return nil
 		}
+		sheet.items = append(sheet.items, *item)
+		sheet.itemRows = append(sheet.itemRows, rowNum)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("sheet is empty")
 	}
 
-	// Save items
-	if len(items) > 0 {
-		if err := p.service.SaveItems(ctx, items); err != nil {
-			return fmt.Errorf("failed to save items: %w", err)
+	return &sheet, nil
+}
+
+// mapExcelColumns maps the header row to column indexes, failing if a required column is missing
+func mapExcelColumns(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(excelKnownColumns))
+	for _, col := range excelKnownColumns {
+		known[col] = true
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := normalizeExcelHeader(name)
+		if _, seen := columns[key]; known[key] && !seen {
+			columns[key] = i
 		}
 	}
 
-	// Clean up temp file
-	if strings.HasPrefix(filePath, "/tmp/") {
-		os.Remove(filePath)
+	var missing []string
+	for _, col := range excelRequiredColumns {
+		if _, ok := columns[col]; !ok {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
 	}
 
-	p.logger.InfoContext(ctx, "Excel processing completed",
-		slog.String("job_id", jobID),
-		slog.Int("items_processed", len(items)))
+	return columns, nil
+}
 
-	return nil
+// normalizeExcelHeader turns a header such as "Buyer's Premium" into "buyers_premium"
+func normalizeExcelHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	header = strings.NewReplacer("'", "", " ", "_", "-", "_").Replace(header)
+	return header
 }
 
-func (p *ExcelProcessor) parseRow(r *xlsx.Row) *domain.InventoryItem {
-	get := func(i int) string {
-		c := r.GetCell(i)
-		if c == nil {
+// parseExcelRow converts a data row into an inventory item, collecting every field error
+func parseExcelRow(rowNum int, cells []string, columns map[string]int) (*domain.InventoryItem, []ExcelRowError) {
+	var errs []ExcelRowError
+	fail := func(field, msg string) {
+		errs = append(errs, ExcelRowError{Row: rowNum, Field: field, Error: msg})
+	}
+	get := func(field string) string {
+		idx, ok := columns[field]
+		if !ok || idx >= len(cells) {
 			return ""
 		}
-		return strings.TrimSpace(c.String())
+		return cells[idx]
 	}
-
-	getDecimal := func(i int) decimal.Decimal {
-		s := get(i)
-		if s == "" {
+	getDecimal := func(field string) decimal.Decimal {
+		raw := get(field)
+		if raw == "" {
 			return decimal.Zero
 		}
-		d, _ := decimal.NewFromString(strings.TrimPrefix(s, "$"))
+		d, err := parseExcelCurrency(raw)
+		if err != nil {
+			fail(field, fmt.Sprintf("invalid amount %q", raw))
+			return decimal.Zero
+		}
+		if d.IsNegative() {
+			fail(field, "cannot be negative")
+		}
 		return d
 	}
 
-	// Parse required fields
-	itemName := get(3) // Assuming column D is item name
-	if itemName == "" {
+	item := &domain.InventoryItem{
+		InvoiceID:       get(ExcelColInvoiceID),
+		ItemName:        get(ExcelColItemName),
+		Description:     get(ExcelColDescription),
+		Subcategory:     get(ExcelColSubcategory),
+		Quantity:        1,
+		BidAmount:       getDecimal(ExcelColBidAmount),
+		BuyersPremium:   getDecimal(ExcelColBuyersPremium),
+		SalesTax:        getDecimal(ExcelColSalesTax),
+		ShippingCost:    getDecimal(ExcelColShippingCost),
+		StorageLocation: get(ExcelColStorageLocation),
+		StorageBin:      get(ExcelColStorageBin),
+		Notes:           get(ExcelColNotes),
+	}
+
+	if item.InvoiceID == "" {
+		fail(ExcelColInvoiceID, "is required")
+	}
+	if item.ItemName == "" {
+		fail(ExcelColItemName, "is required")
+	}
+	if get(ExcelColBidAmount) == "" {
+		fail(ExcelColBidAmount, "is required")
+	}
+
+	if raw := get(ExcelColAuctionID); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			fail(ExcelColAuctionID, fmt.Sprintf("invalid auction id %q", raw))
+		}
+		item.AuctionID = id
+	}
+
+	if raw := get(ExcelColQuantity); raw != "" {
+		qty, err := strconv.Atoi(raw)
+		if err != nil || qty <= 0 {
+			fail(ExcelColQuantity, fmt.Sprintf("must be a positive whole number, got %q", raw))
+		}
+		item.Quantity = qty
+	}
+
+	if raw := get(ExcelColCategory); raw != "" {
+		item.Category = domain.ItemCategory(normalizeExcelHeader(raw))
+		if !item.Category.IsValid() {
+			fail(ExcelColCategory, fmt.Sprintf("unknown category %q", raw))
+		}
+	}
+
+	if raw := get(ExcelColCondition); raw != "" {
+		item.Condition = domain.ItemCondition(normalizeExcelHeader(raw))
+		if !item.Condition.IsValid() {
+			fail(ExcelColCondition, fmt.Sprintf("unknown condition %q", raw))
+		}
+	}
+
+	if raw := get(ExcelColAcquisitionDate); raw != "" {
+		date, err := parseExcelDate(raw)
+		if err != nil {
+			fail(ExcelColAcquisitionDate, fmt.Sprintf("invalid date %q", raw))
+		}
+		item.AcquisitionDate = date
+	}
+
+	if raw := get(ExcelColKeywords); raw != "" {
+		for _, kw := range strings.Split(raw, ",") {
+			if kw = strings.TrimSpace(kw); kw != "" {
+				item.Keywords = append(item.Keywords, kw)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// Domain validation fills in defaults and catches anything the field checks missed
+	if err := item.Validate(); err != nil {
+		return nil, []ExcelRowError{{Row: rowNum, Error: err.Error()}}
+	}
+
+	return item, nil
+}
+
+// excelRowValues returns the trimmed display value of every cell in the row,
+// rendering date-formatted cells as YYYY-MM-DD
+func excelRowValues(r *xlsx.Row) []string {
+	var values []string
+	_ = r.ForEachCell(func(c *xlsx.Cell) error {
+		value := strings.TrimSpace(c.String())
+		if c.IsTime() {
+			if t, err := c.GetTime(false); err == nil {
+				value = t.Format("2006-01-02")
+			}
+		}
+		values = append(values, value)
 		return nil
+	})
+	return values
+}
+
+func isBlankRow(cells []string) bool {
+	for _, c := range cells {
+		if c != "" {
+			return false
+		}
 	}
+	return true
+}
 
-	return &domain.InventoryItem{
-		LotID:           uuid.New(),
-		InvoiceID:       get(0),
-		ItemName:        itemName,
-		Description:     get(4),
-		Category:        domain.ItemCategory(strings.ToLower(get(5))),
-		Condition:       domain.ItemCondition(strings.ToLower(strings.ReplaceAll(get(6), " ", "_"))),
-		Quantity:        1,
-		BidAmount:       getDecimal(7),
-		BuyersPremium:   getDecimal(8),
-		SalesTax:        getDecimal(9),
-		ShippingCost:    getDecimal(10),
-		AcquisitionDate: time.Now(),
+func parseExcelCurrency(val string) (decimal.Decimal, error) {
+	cleaned := strings.NewReplacer("$", "", ",", "").Replace(strings.TrimSpace(val))
+	return decimal.NewFromString(cleaned)
+}
+
+func parseExcelDate(val string) (time.Time, error) {
+	for _, layout := range excelDateLayouts {
+		if t, err := time.Parse(layout, val); err == nil {
+			return t, nil
+		}
 	}
+	return time.Time{}, fmt.Errorf("unrecognized date format")
+}
+
+func (p *ExcelProcessor) updateJobStatus(ctx context.Context, jobID string, status string, errorMsg *string) error {
+	query := `
+		UPDATE async_jobs 
+		SET status = $2, error = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status <> 'cancelled'`
+
+	_, err := p.db.Exec(ctx, query, jobID, status, errorMsg)
+	return err
+}
+
+func (p *ExcelProcessor) updateJobStatusWithResult(ctx context.Context, jobID string, status string, result json.RawMessage) error {
+	query := `
+		UPDATE async_jobs 
+		SET status = $2, result = $3, completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status <> 'cancelled'`
+
+	_, err := p.db.Exec(ctx, query, jobID, status, result)
+	return err
 }
 
 // RefreshAnalytics refreshes analytics materialized views
 func (p *AnalyticsProcessor) RefreshAnalytics(ctx context.Context, t *asynq.Task) error {
-	p.logger.InfoContext(ctx, "refreshing analytics")
+	var payload RefreshAnalyticsPayload
+	if len(t.Payload()) > 0 {
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+	}
+
+	p.logger.InfoContext(ctx, "refreshing analytics",
+		slog.String("job_id", payload.JobID))
+
+	_ = p.updateJobStatus(ctx, payload.JobID, "processing", nil)
 
 	// Refresh materialized view
 	query := `REFRESH MATERIALIZED VIEW CONCURRENTLY inventory_excel_export_mat`
 
 	if _, err := p.db.Exec(ctx, query); err != nil {
+		// Keep the lock while asynq still has retries left so new requests don't stack up
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried >= maxRetry {
+			errMsg := fmt.Sprintf("failed to refresh materialized view: %v", err)
+			_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+			p.releaseRefreshLock(ctx, payload)
+		}
 		return fmt.Errorf("failed to refresh materialized view: %w", err)
 	}
 
+	_ = p.updateJobStatus(ctx, payload.JobID, "completed", nil)
+	p.releaseRefreshLock(ctx, payload)
+
 	p.logger.InfoContext(ctx, "analytics refreshed successfully")
 	return nil
 }
 
-// GenerateReport generates analytics reports
+// GenerateReport builds the requested report, uploads it as an artifact and stores the
+// artifact key on the job result. Invalid payloads fail without retrying.
 func (p *AnalyticsProcessor) GenerateReport(ctx context.Context, t *asynq.Task) error {
-	var payload map[string]interface{}
+	var payload GenerateReportPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal payload: %w", err)
+		return fmt.Errorf("failed to unmarshal payload: %w: %w", err, asynq.SkipRetry)
+	}
+
+	if payload.Format == "" {
+		payload.Format = ReportFormatJSON
+	}
+
+	fail := func(err error) error {
+		errMsg := err.Error()
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		return err
 	}
 
-	reportType := payload["type"].(string)
+	if payload.Type != ReportTypeWeeklySummary {
+		return fail(fmt.Errorf("unsupported report type %q: %w", payload.Type, asynq.SkipRetry))
+	}
+	if payload.Format != ReportFormatJSON && payload.Format != ReportFormatXLSX {
+		return fail(fmt.Errorf("unsupported report format %q: %w", payload.Format, asynq.SkipRetry))
+	}
+	if p.reports == nil {
+		return fail(fmt.Errorf("report storage is not configured: %w", asynq.SkipRetry))
+	}
+
+	start, end, err := reportWindow(payload, time.Now())
+	if err != nil {
+		return fail(fmt.Errorf("%w: %w", err, asynq.SkipRetry))
+	}
 
 	p.logger.InfoContext(ctx, "generating report",
-		slog.String("type", reportType))
+		slog.String("type", payload.Type),
+		slog.String("format", payload.Format),
+		slog.String("start", start.Format(reportDateLayout)),
+		slog.String("end", end.Format(reportDateLayout)))
+
+	_ = p.updateJobStatus(ctx, payload.JobID, "processing", nil)
+
+	result, err := p.generateWeeklySummary(ctx, start, end, payload.Format)
+	if err != nil {
+		// Leave the job processing while asynq still has retries left
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried >= maxRetry {
+			return fail(err)
+		}
+		return err
+	}
 
-	// Report generation logic would go here
-	switch reportType {
-	case "monthly":
-		// Generate monthly report
-	case "quarterly":
-		// Generate quarterly report
-	case "annual":
-		// Generate annual report
+	if err := p.completeJob(ctx, payload.JobID, result); err != nil {
+		p.logger.WarnContext(ctx, "failed to record report result",
+			slog.String("job_id", payload.JobID),
+			slog.String("error", err.Error()))
 	}
 
-	p.logger.InfoContext(ctx, "report generated successfully")
+	p.logger.InfoContext(ctx, "report generated successfully",
+		slog.String("artifact_key", result.ArtifactKey))
 	return nil
 }
+
+func (p *AnalyticsProcessor) generateWeeklySummary(ctx context.Context, start, end time.Time, format string) (*ReportResult, error) {
+	summary, err := p.buildWeeklySummary(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	data, contentType, err := renderWeeklySummary(summary, format)
+	if err != nil {
+		return nil, err
+	}
+
+	key := reportArtifactKey(ReportTypeWeeklySummary, start, end, format)
+	if _, err := p.reports.Upload(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return nil, fmt.Errorf("failed to upload report: %w", err)
+	}
+
+	return &ReportResult{ArtifactKey: key, Summary: summary}, nil
+}
@@ -0,0 +1,172 @@
+// internal/workers/excel_processor_test.go
+package workers_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func TestExcelProcessor_ProcessExcel(t *testing.T) {
+	tests := []struct {
+		name          string
+		rows          [][]string
+		validateOnly  bool
+		setupService  func(*mocks.MockInventoryService)
+		expectedError string
+		validate      func(*testing.T, string, json.RawMessage)
+	}{
+		{
+			name: "maps_rows_and_collects_row_errors",
+			rows: [][]string{
+				{"Invoice ID", "Item Name", "Bid Amount", "Buyer's Premium", "Category", "Condition", "Quantity", "Acquisition Date"},
+				{"INV-100", "Brass Lamp", "$1,200.00", "216.00", "Antiques", "Very Good", "2", "2024-03-15"},
+				{"INV-100", "Oak Chair", "abc", "", "furniture", "", "", ""},
+				{"INV-100", "", "15.00", "", "", "", "", ""},
+				{"", "", "", "", "", "", "", ""},
+				{"INV-100", "Mystery Box", "5.00", "", "widgets", "", "0", ""},
+				{"INV-101", "Sterling Spoon", "8.50", "", "silver", "mint", "", "03/20/2024"},
+			},
+			setupService: func(service *mocks.MockInventoryService) {
+				service.EXPECT().
+					SaveItemsPartial(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+						require.Len(t, items, 2)
+
+						assert.Equal(t, "INV-100", items[0].InvoiceID)
+						assert.Equal(t, "Brass Lamp", items[0].ItemName)
+						assert.True(t, decimal.NewFromInt(1200).Equal(items[0].BidAmount))
+						assert.True(t, decimal.NewFromInt(216).Equal(items[0].BuyersPremium))
+						assert.Equal(t, domain.CategoryAntiques, items[0].Category)
+						assert.Equal(t, domain.ConditionVeryGood, items[0].Condition)
+						assert.Equal(t, 2, items[0].Quantity)
+						assert.Equal(t, "2024-03-15", items[0].AcquisitionDate.Format("2006-01-02"))
+
+						assert.Equal(t, "Sterling Spoon", items[1].ItemName)
+						assert.Equal(t, domain.CategorySilver, items[1].Category)
+						assert.Equal(t, domain.ConditionMint, items[1].Condition)
+						assert.Equal(t, 1, items[1].Quantity)
+						assert.Equal(t, "2024-03-20", items[1].AcquisitionDate.Format("2006-01-02"))
+
+						// The second item fails at the database and is reported against its row
+						return ports.SaveReport{
+							Saved:  []uuid.UUID{uuid.New()},
+							Failed: []ports.SaveFailure{{Index: 1, ItemName: "Sterling Spoon", Error: "duplicate key value"}},
+						}, nil
+					})
+			},
+			validate: func(t *testing.T, status string, raw json.RawMessage) {
+				assert.Equal(t, "completed_with_errors", status)
+
+				var result workers.ExcelJobResult
+				require.NoError(t, json.Unmarshal(raw, &result))
+				assert.Equal(t, 5, result.RowsProcessed)
+				assert.Equal(t, 2, result.ValidRows)
+				assert.Equal(t, 1, result.ItemsCreated)
+				assert.False(t, result.ValidateOnly)
+				assert.Equal(t, []workers.ExcelRowError{
+					{Row: 3, Field: "bid_amount", Error: `invalid amount "abc"`},
+					{Row: 4, Field: "item_name", Error: "is required"},
+					{Row: 6, Field: "quantity", Error: `must be a positive whole number, got "0"`},
+					{Row: 6, Field: "category", Error: `unknown category "widgets"`},
+					{Row: 7, Error: "duplicate key value"},
+				}, result.Errors)
+			},
+		},
+		{
+			name: "validate_only_reports_errors_without_saving",
+			rows: [][]string{
+				{"invoice_id", "item_name", "bid_amount", "acquisition_date"},
+				{"INV-200", "Brass Lamp", "12.00", "2024-03-15"},
+				{"INV-200", "Oak Chair", "30.00", "yesterday"},
+			},
+			validateOnly: true,
+			// No SaveItemsPartial expectation: gomock fails the test if it is called
+			validate: func(t *testing.T, status string, raw json.RawMessage) {
+				assert.Equal(t, "completed_with_errors", status)
+
+				var result workers.ExcelJobResult
+				require.NoError(t, json.Unmarshal(raw, &result))
+				assert.True(t, result.ValidateOnly)
+				assert.Equal(t, 2, result.RowsProcessed)
+				assert.Equal(t, 1, result.ValidRows)
+				assert.Equal(t, 0, result.ItemsCreated)
+				assert.Equal(t, []workers.ExcelRowError{
+					{Row: 3, Field: "acquisition_date", Error: `invalid date "yesterday"`},
+				}, result.Errors)
+			},
+		},
+		{
+			name: "fails_when_required_columns_missing",
+			rows: [][]string{
+				{"Item Name", "Description"},
+				{"Brass Lamp", "Working"},
+			},
+			expectedError: "missing required columns: invoice_id, bid_amount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			mockDB := mocks.NewMockDatabase(ctrl)
+			files, fileKey := stageUpload(t, helpers.CreateTestExcel(t, tt.rows))
+			processor := workers.NewExcelProcessor(mockService, mockDB, files, helpers.TestLogger(), nil)
+			expectJobRunning(mockDB)
+
+			// Capture the final job status update
+			var finalStatus string
+			var finalResult json.RawMessage
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(2).
+				DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+					finalStatus = args[1].(string)
+					if result, ok := args[2].(json.RawMessage); ok {
+						finalResult = result
+					}
+					return pgconn.CommandTag{}, nil
+				})
+
+			if tt.setupService != nil {
+				tt.setupService(mockService)
+			}
+
+			payload, err := json.Marshal(workers.ExcelJobPayload{
+				JobID:        uuid.New().String(),
+				FileKey:      fileKey,
+				ValidateOnly: tt.validateOnly,
+			})
+			require.NoError(t, err)
+
+			err = processor.ProcessExcel(context.Background(), asynq.NewTask(workers.TypeExcelImport, payload))
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Equal(t, "failed", finalStatus)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.validate(t, finalStatus, finalResult)
+		})
+	}
+}
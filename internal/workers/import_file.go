@@ -0,0 +1,71 @@
+// internal/workers/import_file.go
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/hibiken/asynq"
+)
+
+// FileStore provides uploaded import files; storage.StorageClient satisfies it
+type FileStore interface {
+	Download(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// fetchImportFile downloads the object at key into a local temp file for the parsers.
+// The returned cleanup removes the local copy only; the stored object is untouched.
+func fetchImportFile(ctx context.Context, files FileStore, key string) (string, func(), error) {
+	data, err := files.Download(ctx, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+
+	f, err := os.CreateTemp("", "import-*"+filepath.Ext(key))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	return f.Name(), cleanup, nil
+}
+
+// deleteImportFile removes a processed upload from storage. Failures are logged rather
+// than returned because the import itself has already succeeded.
+func deleteImportFile(ctx context.Context, files FileStore, key string, logger *slog.Logger) {
+	if err := files.Delete(ctx, key); err != nil {
+		logger.WarnContext(ctx, "failed to delete processed upload",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+	}
+}
+
+// finalAttempt reports whether a task ending with err will not run again, after which
+// its upload can be deleted. PDF uploads are keyed by content, so one left behind would
+// block re-uploading the same file.
+func finalAttempt(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, asynq.SkipRetry) {
+		return true
+	}
+	retried, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return false
+	}
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	return ok && retried >= maxRetry
+}
@@ -0,0 +1,188 @@
+// internal/workers/import_file_test.go
+package workers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/adapters/storage"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+// stageUpload exposes a file created by a test helper as an uploaded object
+func stageUpload(t *testing.T, path string) (*storage.LocalStorage, string) {
+	t.Helper()
+	return storage.NewLocalStorage(filepath.Dir(path), helpers.TestLogger()), filepath.Base(path)
+}
+
+// expectJobRunning answers the processors' cancellation checks with a job that is still running
+func expectJobRunning(db *mocks.MockDatabase) {
+	db.EXPECT().
+		QueryRow(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string, ...any) pgx.Row {
+			return &fakeRows{values: [][]any{{false}}}
+		}).
+		AnyTimes()
+}
+
+func TestExcelProcessor_ProcessExcel_UploadLifecycle(t *testing.T) {
+	rows := [][]string{
+		{"invoice_id", "item_name", "bid_amount"},
+		{"INV-400", "Brass Lamp", "12.00"},
+	}
+
+	tests := []struct {
+		name         string
+		saveErr      error
+		expectError  bool
+		expectExists bool
+	}{
+		{
+			name:         "deletes_upload_after_successful_import",
+			expectExists: false,
+		},
+		{
+			name:         "keeps_upload_when_import_will_be_retried",
+			saveErr:      errors.New("connection reset"),
+			expectError:  true,
+			expectExists: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(2).
+				Return(pgconn.CommandTag{}, nil)
+			expectJobRunning(mockDB)
+			mockService.EXPECT().
+				SaveItemsPartial(gomock.Any(), gomock.Any()).
+				Return(ports.SaveReport{Saved: []uuid.UUID{uuid.New()}}, tt.saveErr)
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			fileKey := "imports/lots.xlsx"
+			data, err := os.ReadFile(helpers.CreateTestExcel(t, rows))
+			require.NoError(t, err)
+			_, err = files.Upload(context.Background(), fileKey, bytes.NewReader(data), "")
+			require.NoError(t, err)
+
+			processor := workers.NewExcelProcessor(mockService, mockDB, files, helpers.TestLogger(), nil)
+			payload, err := json.Marshal(workers.ExcelJobPayload{JobID: uuid.New().String(), FileKey: fileKey})
+			require.NoError(t, err)
+
+			err = processor.ProcessExcel(context.Background(), asynq.NewTask(workers.TypeExcelImport, payload))
+
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			exists, err := files.Exists(context.Background(), fileKey)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectExists, exists)
+		})
+	}
+}
+
+func TestExcelProcessor_ProcessExcel_MissingUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	var statuses []string
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+			statuses = append(statuses, args[1].(string))
+			return pgconn.CommandTag{}, nil
+		})
+
+	files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+	processor := workers.NewExcelProcessor(mocks.NewMockInventoryService(ctrl), mockDB, files, helpers.TestLogger(), nil)
+	payload, err := json.Marshal(workers.ExcelJobPayload{JobID: uuid.New().String(), FileKey: "imports/missing.xlsx"})
+	require.NoError(t, err)
+
+	err = processor.ProcessExcel(context.Background(), asynq.NewTask(workers.TypeExcelImport, payload))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to download imports/missing.xlsx")
+	assert.Equal(t, []string{"processing", "failed"}, statuses)
+}
+
+func TestPDFProcessor_ProcessPDF_UploadLifecycle(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         func() []byte
+		expectExists bool
+	}{
+		{
+			name: "deletes_upload_when_failure_is_permanent",
+			data: func() []byte {
+				data, err := os.ReadFile(helpers.CreateEncryptedTestPDF(t, []string{"Brass table lamp 12.00"}, "hunter2"))
+				require.NoError(t, err)
+				return data
+			},
+			expectExists: false,
+		},
+		{
+			name:         "keeps_upload_when_failure_will_be_retried",
+			data:         func() []byte { return []byte("not a pdf") },
+			expectExists: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(2).
+				Return(pgconn.CommandTag{}, nil)
+
+			files := storage.NewLocalStorage(t.TempDir(), helpers.TestLogger())
+			fileKey := "imports/invoice.pdf"
+			_, err := files.Upload(context.Background(), fileKey, bytes.NewReader(tt.data()), "")
+			require.NoError(t, err)
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			mockService.EXPECT().HasInvoice(gomock.Any(), "INV-1").Return(false, nil)
+
+			processor := workers.NewPDFProcessor(mockService, mockDB, files, helpers.TestLogger(), 0, nil, nil, nil)
+			payload, err := json.Marshal(workers.PDFJobPayload{JobID: uuid.New().String(), FileKey: fileKey, InvoiceID: "INV-1"})
+			require.NoError(t, err)
+
+			err = processor.ProcessPDF(context.Background(), asynq.NewTask(workers.TypePDFProcess, payload))
+
+			require.Error(t, err)
+			exists, err := files.Exists(context.Background(), fileKey)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectExists, exists)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// internal/workers/import_notifier.go
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/hibiken/asynq"
+)
+
+// maxNotifiedErrors caps how many import errors are listed in a notification email
+const maxNotifiedErrors = 10
+
+// TaskEnqueuer enqueues follow-up tasks; *asynq.Client satisfies it
+type TaskEnqueuer interface {
+	Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+}
+
+// ImportSummary is the outcome of an import reported to the user
+type ImportSummary struct {
+	JobID         string
+	FileName      string
+	ItemsImported int
+	ItemsFailed   int
+	Errors        []string
+}
+
+// ImportNotifier queues import-completed emails
+type ImportNotifier struct {
+	enqueuer TaskEnqueuer
+	logger   *slog.Logger
+}
+
+// NewImportNotifier creates a notifier that enqueues TypeSendEmail tasks
+func NewImportNotifier(enqueuer TaskEnqueuer, logger *slog.Logger) *ImportNotifier {
+	return &ImportNotifier{
+		enqueuer: enqueuer,
+		logger:   logger.With(slog.String("component", "import_notifier")),
+	}
+}
+
+// NotifyImportCompleted queues an import-completed email to the given address.
+// It is a no-op for a nil notifier or empty address. Failures are logged and never
+// returned, so a notification problem can't fail the import itself.
+func (n *ImportNotifier) NotifyImportCompleted(ctx context.Context, to string, summary ImportSummary) {
+	if n == nil || to == "" {
+		return
+	}
+
+	errs := summary.Errors
+	if len(errs) > maxNotifiedErrors {
+		errs = append(errs[:maxNotifiedErrors:maxNotifiedErrors],
+			fmt.Sprintf("...and %d more", len(summary.Errors)-maxNotifiedErrors))
+	}
+
+	payload, err := json.Marshal(SendEmailPayload{
+		To:       to,
+		Template: EmailTemplateImportCompleted,
+		Data: map[string]interface{}{
+			"job_id":         summary.JobID,
+			"file_name":      summary.FileName,
+			"items_imported": summary.ItemsImported,
+			"items_failed":   summary.ItemsFailed,
+			"errors":         errs,
+		},
+	})
+	if err != nil {
+		n.logger.ErrorContext(ctx, "failed to marshal import notification",
+			slog.String("job_id", summary.JobID),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	// The task ID keeps a retried import from emailing the user twice
+	_, err = n.enqueuer.Enqueue(asynq.NewTask(TypeSendEmail, payload),
+		asynq.TaskID("import-email:"+summary.JobID),
+		asynq.Queue("default"),
+		asynq.MaxRetry(5))
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return
+		}
+		n.logger.ErrorContext(ctx, "failed to enqueue import notification",
+			slog.String("job_id", summary.JobID),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	n.logger.InfoContext(ctx, "import notification queued", slog.String("job_id", summary.JobID))
+}
+
+// notificationFileName prefers the original upload name over the stored temp path
+func notificationFileName(fileName, filePath string) string {
+	if fileName != "" {
+		return fileName
+	}
+	return filepath.Base(filePath)
+}
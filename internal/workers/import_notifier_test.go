@@ -0,0 +1,186 @@
+// internal/workers/import_notifier_test.go
+package workers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+type recordingEnqueuer struct {
+	tasks []*asynq.Task
+	err   error
+}
+
+func (r *recordingEnqueuer) Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.tasks = append(r.tasks, task)
+	return &asynq.TaskInfo{ID: "task-1"}, nil
+}
+
+func TestExcelProcessor_ProcessExcel_NotifiesOnCompletion(t *testing.T) {
+	rows := [][]string{
+		{"invoice_id", "item_name", "bid_amount"},
+		{"INV-300", "Brass Lamp", "12.00"},
+		{"INV-300", "Oak Chair", "abc"},
+	}
+
+	tests := []struct {
+		name        string
+		notifyEmail string
+		enqueueErr  error
+		validate    func(*testing.T, *recordingEnqueuer, string)
+	}{
+		{
+			name:        "enqueues_import_completed_email",
+			notifyEmail: "owner@example.com",
+			validate: func(t *testing.T, enqueuer *recordingEnqueuer, jobID string) {
+				require.Len(t, enqueuer.tasks, 1)
+				assert.Equal(t, workers.TypeSendEmail, enqueuer.tasks[0].Type())
+
+				var payload workers.SendEmailPayload
+				require.NoError(t, json.Unmarshal(enqueuer.tasks[0].Payload(), &payload))
+				assert.Equal(t, "owner@example.com", payload.To)
+				assert.Equal(t, workers.EmailTemplateImportCompleted, payload.Template)
+				assert.Equal(t, jobID, payload.Data["job_id"])
+				assert.Equal(t, "lots.xlsx", payload.Data["file_name"])
+				assert.EqualValues(t, 1, payload.Data["items_imported"])
+				assert.EqualValues(t, 1, payload.Data["items_failed"])
+				assert.Equal(t, []interface{}{`row 3 (bid_amount): invalid amount "abc"`}, payload.Data["errors"])
+
+				// The enqueued payload renders with the real template
+				subject, body, err := workers.RenderEmail(payload.Template, payload.Data)
+				require.NoError(t, err)
+				assert.Equal(t, "Import completed: lots.xlsx", subject)
+				assert.Contains(t, body, "Errors:\n- row 3 (bid_amount): invalid amount \"abc\"\n")
+			},
+		},
+		{
+			name: "skips_email_without_address",
+			validate: func(t *testing.T, enqueuer *recordingEnqueuer, _ string) {
+				assert.Empty(t, enqueuer.tasks)
+			},
+		},
+		{
+			name:        "enqueue_failure_does_not_fail_import",
+			notifyEmail: "owner@example.com",
+			enqueueErr:  errors.New("redis unavailable"),
+			validate: func(t *testing.T, enqueuer *recordingEnqueuer, _ string) {
+				assert.Empty(t, enqueuer.tasks)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			mockDB := mocks.NewMockDatabase(ctrl)
+			enqueuer := &recordingEnqueuer{err: tt.enqueueErr}
+			notifier := workers.NewImportNotifier(enqueuer, helpers.TestLogger())
+			files, fileKey := stageUpload(t, helpers.CreateTestExcel(t, rows))
+			processor := workers.NewExcelProcessor(mockService, mockDB, files, helpers.TestLogger(), notifier)
+			expectJobRunning(mockDB)
+
+			var finalStatus string
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(2).
+				DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+					finalStatus = args[1].(string)
+					return pgconn.CommandTag{}, nil
+				})
+			mockService.EXPECT().
+				SaveItemsPartial(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+					return ports.SaveReport{Saved: []uuid.UUID{uuid.New()}}, nil
+				})
+
+			jobID := uuid.New().String()
+			payload, err := json.Marshal(workers.ExcelJobPayload{
+				JobID:       jobID,
+				FileKey:     fileKey,
+				FileName:    "lots.xlsx",
+				NotifyEmail: tt.notifyEmail,
+			})
+			require.NoError(t, err)
+
+			err = processor.ProcessExcel(context.Background(), asynq.NewTask(workers.TypeExcelImport, payload))
+
+			require.NoError(t, err)
+			assert.Equal(t, "completed_with_errors", finalStatus)
+			tt.validate(t, enqueuer, jobID)
+		})
+	}
+}
+
+func TestImportNotifier_NotifyImportCompleted(t *testing.T) {
+	t.Run("caps_listed_errors", func(t *testing.T) {
+		enqueuer := &recordingEnqueuer{}
+		notifier := workers.NewImportNotifier(enqueuer, helpers.TestLogger())
+
+		errs := make([]string, 15)
+		for i := range errs {
+			errs[i] = fmt.Sprintf("error %d", i+1)
+		}
+
+		notifier.NotifyImportCompleted(context.Background(), "owner@example.com", workers.ImportSummary{
+			JobID:       "job-1",
+			FileName:    "invoice.pdf",
+			ItemsFailed: 15,
+			Errors:      errs,
+		})
+
+		require.Len(t, enqueuer.tasks, 1)
+		var payload workers.SendEmailPayload
+		require.NoError(t, json.Unmarshal(enqueuer.tasks[0].Payload(), &payload))
+		listed := payload.Data["errors"].([]interface{})
+		require.Len(t, listed, 11)
+		assert.Equal(t, "error 10", listed[9])
+		assert.Equal(t, "...and 5 more", listed[10])
+	})
+
+	t.Run("retried_import_does_not_email_twice", func(t *testing.T) {
+		redis := helpers.SetupTestRedis(t)
+		client := asynq.NewClient(asynq.RedisClientOpt{Addr: redis.Server.Addr()})
+		defer client.Close()
+		notifier := workers.NewImportNotifier(client, helpers.TestLogger())
+
+		summary := workers.ImportSummary{JobID: "job-2", FileName: "invoice.pdf", ItemsImported: 3}
+		notifier.NotifyImportCompleted(context.Background(), "owner@example.com", summary)
+		notifier.NotifyImportCompleted(context.Background(), "owner@example.com", summary)
+
+		inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: redis.Server.Addr()})
+		defer inspector.Close()
+		tasks, err := inspector.ListPendingTasks("default")
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, workers.TypeSendEmail, tasks[0].Type)
+	})
+
+	t.Run("nil_notifier_is_a_no_op", func(t *testing.T) {
+		var notifier *workers.ImportNotifier
+		assert.NotPanics(t, func() {
+			notifier.NotifyImportCompleted(context.Background(), "owner@example.com", workers.ImportSummary{})
+		})
+	})
+}
@@ -4,68 +4,76 @@ package workers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"net/smtp"
 
 	"github.com/ammerola/resell-be/internal/pkg/config"
 	"github.com/hibiken/asynq"
 )
 
+// SendEmailPayload is the payload for TypeSendEmail tasks
+type SendEmailPayload struct {
+	To       string                 `json:"to"`
+	Template string                 `json:"template"`
+	Data     map[string]interface{} `json:"data"`
+}
+
 // NotificationProcessor handles email notifications
 type NotificationProcessor struct {
 	config *config.Config
+	sender EmailSender
 	logger *slog.Logger
 }
 
 // NewNotificationProcessor creates a new notification processor
-func NewNotificationProcessor(config *config.Config, logger *slog.Logger) *NotificationProcessor {
+func NewNotificationProcessor(config *config.Config, sender EmailSender, logger *slog.Logger) *NotificationProcessor {
 	return &NotificationProcessor{
 		config: config,
+		sender: sender,
 		logger: logger.With(slog.String("processor", "notification")),
 	}
 }
 
-// SendEmail sends email notifications
+// SendEmail renders the requested template and delivers it. Bad payloads,
+// unknown templates and permanent delivery failures skip retries; transient
+// delivery failures are returned so asynq retries the task.
 func (p *NotificationProcessor) SendEmail(ctx context.Context, t *asynq.Task) error {
-	var payload map[string]interface{}
+	var payload SendEmailPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal payload: %w", err)
+		return fmt.Errorf("failed to unmarshal payload: %w: %w", err, asynq.SkipRetry)
 	}
 
-	to := payload["to"].(string)
-	subject := payload["subject"].(string)
-	body := payload["body"].(string)
-
-	p.logger.InfoContext(ctx, "sending email",
-		slog.String("to", to),
-		slog.String("subject", subject))
+	if payload.To == "" {
+		return fmt.Errorf("email recipient is required: %w", asynq.SkipRetry)
+	}
 
-	// In development, just log the email
-	if p.config.App.Environment == "development" {
-		p.logger.InfoContext(ctx, "email would be sent",
-			slog.String("to", to),
-			slog.String("subject", subject),
-			slog.String("body", body))
-		return nil
+	subject, body, err := RenderEmail(payload.Template, payload.Data)
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w: %w", err, asynq.SkipRetry)
 	}
 
-	// Production email sending
-	// This is a simplified version - in production you'd use a service like SendGrid
-	from := "noreply@resell.com"
-	msg := []byte(fmt.Sprintf(
-		"From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
-		from, to, subject, body,
-	))
+	p.logger.InfoContext(ctx, "sending email",
+		slog.String("to", payload.To),
+		slog.String("template", payload.Template))
 
-	// Send via SMTP (configure your SMTP settings)
-	auth := smtp.PlainAuth("", "", "", "smtp.example.com")
-	err := smtp.SendMail("smtp.example.com:587", auth, from, []string{to}, msg)
+	email := &Email{
+		From:    p.config.Email.FromAddress,
+		To:      payload.To,
+		Subject: subject,
+		Body:    body,
+	}
 
-	if err != nil {
+	if err := p.sender.Send(ctx, email); err != nil {
+		var permanent *PermanentEmailError
+		if errors.As(err, &permanent) {
+			return fmt.Errorf("failed to send email: %w: %w", err, asynq.SkipRetry)
+		}
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
-	p.logger.InfoContext(ctx, "email sent successfully")
+	p.logger.InfoContext(ctx, "email sent successfully",
+		slog.String("to", payload.To),
+		slog.String("template", payload.Template))
 	return nil
 }
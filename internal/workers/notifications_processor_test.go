@@ -0,0 +1,153 @@
+// internal/workers/notifications_processor_test.go
+package workers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/internal/workers"
+)
+
+type fakeEmailSender struct {
+	sent []*workers.Email
+	err  error
+}
+
+func (f *fakeEmailSender) Send(ctx context.Context, email *workers.Email) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, email)
+	return nil
+}
+
+func newEmailTask(t *testing.T, payload interface{}) *asynq.Task {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return asynq.NewTask(workers.TypeSendEmail, data)
+}
+
+func TestNotificationProcessor_SendEmail_ImportCompleted(t *testing.T) {
+	sender := &fakeEmailSender{}
+	cfg := &config.Config{Email: config.EmailConfig{FromAddress: "noreply@resell.com"}}
+	processor := workers.NewNotificationProcessor(cfg, sender, slog.Default())
+
+	task := newEmailTask(t, workers.SendEmailPayload{
+		To:       "owner@example.com",
+		Template: workers.EmailTemplateImportCompleted,
+		Data: map[string]interface{}{
+			"file_name":      "auction-2024-03.pdf",
+			"items_imported": 42,
+			"items_failed":   3,
+			"job_id":         "job-123",
+		},
+	})
+
+	err := processor.SendEmail(context.Background(), task)
+
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	email := sender.sent[0]
+	assert.Equal(t, "noreply@resell.com", email.From)
+	assert.Equal(t, "owner@example.com", email.To)
+	assert.Equal(t, "Import completed: auction-2024-03.pdf", email.Subject)
+	assert.Equal(t, `Your import of auction-2024-03.pdf has finished.
+
+Items imported: 42
+Items failed: 3
+
+Job ID: job-123
+`, email.Body)
+}
+
+func TestNotificationProcessor_SendEmail_Errors(t *testing.T) {
+	validData := map[string]interface{}{
+		"file_name":      "lots.xlsx",
+		"items_imported": 1,
+		"items_failed":   0,
+		"job_id":         "job-1",
+	}
+
+	tests := []struct {
+		name          string
+		payload       interface{}
+		senderErr     error
+		expectedError string
+		skipRetry     bool
+	}{
+		{
+			name:          "unknown template",
+			payload:       workers.SendEmailPayload{To: "a@example.com", Template: "nope"},
+			expectedError: "unknown email template: nope",
+			skipRetry:     true,
+		},
+		{
+			name: "missing template data",
+			payload: workers.SendEmailPayload{
+				To:       "a@example.com",
+				Template: workers.EmailTemplateImportCompleted,
+				Data:     map[string]interface{}{"file_name": "lots.xlsx"},
+			},
+			expectedError: "failed to render email",
+			skipRetry:     true,
+		},
+		{
+			name:          "missing recipient",
+			payload:       workers.SendEmailPayload{Template: workers.EmailTemplateImportCompleted, Data: validData},
+			expectedError: "email recipient is required",
+			skipRetry:     true,
+		},
+		{
+			name:          "transient delivery failure is retried",
+			payload:       workers.SendEmailPayload{To: "a@example.com", Template: workers.EmailTemplateImportCompleted, Data: validData},
+			senderErr:     errors.New("SES returned status 503"),
+			expectedError: "failed to send email",
+			skipRetry:     false,
+		},
+		{
+			name:          "permanent delivery failure is not retried",
+			payload:       workers.SendEmailPayload{To: "a@example.com", Template: workers.EmailTemplateImportCompleted, Data: validData},
+			senderErr:     &workers.PermanentEmailError{Err: errors.New("SES returned status 400")},
+			expectedError: "failed to send email",
+			skipRetry:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeEmailSender{err: tt.senderErr}
+			processor := workers.NewNotificationProcessor(&config.Config{}, sender, slog.Default())
+
+			err := processor.SendEmail(context.Background(), newEmailTask(t, tt.payload))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.Equal(t, tt.skipRetry, errors.Is(err, asynq.SkipRetry))
+			assert.Empty(t, sender.sent)
+		})
+	}
+}
+
+func TestRenderEmail_WeeklySummary(t *testing.T) {
+	subject, body, err := workers.RenderEmail(workers.EmailTemplateWeeklySummary, map[string]interface{}{
+		"week_start":     "2024-03-04",
+		"items_acquired": 12,
+		"items_sold":     5,
+		"revenue":        "1250.00",
+		"profit":         "430.50",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Weekly summary for 2024-03-04", subject)
+	assert.Contains(t, body, "Items sold: 5")
+	assert.Contains(t, body, "Profit: $430.50")
+}
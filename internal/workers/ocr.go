@@ -0,0 +1,81 @@
+// internal/workers/ocr.go
+package workers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// OCREngine recovers text from a PDF page that has no extractable text layer
+type OCREngine interface {
+	// RecognizePage returns the text found on the given 1-based page of the PDF at filePath.
+	// password is non-empty when the document is encrypted.
+	RecognizePage(ctx context.Context, filePath, password string, pageNum int) (string, error)
+}
+
+// TesseractOCR renders pages with pdftoppm and runs them through the tesseract CLI
+type TesseractOCR struct {
+	TesseractPath string
+	PdftoppmPath  string
+	Language      string
+	DPI           int
+}
+
+// NewTesseractOCR creates an OCR engine using the tesseract and pdftoppm binaries on PATH
+func NewTesseractOCR() *TesseractOCR {
+	return &TesseractOCR{
+		TesseractPath: "tesseract",
+		PdftoppmPath:  "pdftoppm",
+		Language:      "eng",
+		DPI:           300,
+	}
+}
+
+// RecognizePage renders a single page to PNG and returns the text tesseract finds on it
+func (o *TesseractOCR) RecognizePage(ctx context.Context, filePath, password string, pageNum int) (string, error) {
+	dir, err := os.MkdirTemp("", "ocr-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create OCR work directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	page := strconv.Itoa(pageNum)
+	prefix := filepath.Join(dir, "page")
+	args := []string{"-f", page, "-l", page, "-r", strconv.Itoa(o.DPI), "-png", "-singlefile"}
+	if password != "" {
+		args = append(args, "-upw", password)
+	}
+	args = append(args, filePath, prefix)
+
+	if err := o.run(ctx, o.PdftoppmPath, args, nil); err != nil {
+		return "", fmt.Errorf("failed to render page %d: %w", pageNum, err)
+	}
+
+	var text bytes.Buffer
+	if err := o.run(ctx, o.TesseractPath, []string{prefix + ".png", "stdout", "-l", o.Language}, &text); err != nil {
+		return "", fmt.Errorf("failed to recognize page %d: %w", pageNum, err)
+	}
+
+	return text.String(), nil
+}
+
+// run executes an external command, including its stderr in any returned error
+func (o *TesseractOCR) run(ctx context.Context, name string, args []string, stdout *bytes.Buffer) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -4,10 +4,10 @@ package workers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
-	"regexp"
 	"strings"
 	"time"
 
@@ -18,6 +18,9 @@ import (
 
 	"github.com/ammerola/resell-be/internal/core/domain"
 	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/keywords"
+	"github.com/ammerola/resell-be/internal/pkg/logger"
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
 )
 
 const (
@@ -30,37 +33,91 @@ const (
 	TypeCleanupTempFiles = "cleanup:temp_files"
 )
 
+// Invoice layouts understood by the PDF parser
+const (
+	LayoutSingleColumn = pdfparse.LayoutSingleColumn
+	LayoutTwoColumn    = pdfparse.LayoutTwoColumn
+)
+
+// maxItemKeywords caps the search keywords stored per imported item
+const maxItemKeywords = 10
+
+// How a PDF import handles an invoice whose items were already imported
+const (
+	OnDuplicateSkip    = "skip"    // leave the existing items and import nothing (default)
+	OnDuplicateReplace = "replace" // soft delete the existing items, then import
+	OnDuplicateAppend  = "append"  // import alongside the existing items
+)
+
 // PDFJobPayload represents the payload for PDF processing jobs
 type PDFJobPayload struct {
-	JobID     string `json:"job_id"`
-	FilePath  string `json:"file_path"`
-	InvoiceID string `json:"invoice_id"`
-	AuctionID int    `json:"auction_id"`
-	UserID    string `json:"user_id,omitempty"`
+	JobID       string `json:"job_id"`
+	FileKey     string `json:"file_key"` // Storage key of the uploaded PDF
+	InvoiceID   string `json:"invoice_id"`
+	AuctionID   int    `json:"auction_id"`
+	UserID      string `json:"user_id,omitempty"`
+	Layout      string `json:"layout,omitempty"`
+	Profile     string `json:"profile,omitempty"` // Invoice profile; empty picks the auction's, else the default
+	Password    string `json:"password,omitempty"`
+	FileName    string `json:"file_name,omitempty"` // Original upload name, used in notifications
+	NotifyEmail string `json:"notify_email,omitempty"`
+	OnDuplicate string `json:"on_duplicate,omitempty"` // OnDuplicate* mode; empty means skip
+
+	// ShippingTotal is shipping billed once for the whole invoice, split across its items by
+	// ShippingAllocation (a domain.ShippingAllocation; empty is proportional). Zero leaves each
+	// item's shipping cost at zero.
+	ShippingTotal      decimal.Decimal `json:"shipping_total"`
+	ShippingAllocation string          `json:"shipping_allocation,omitempty"`
 }
 
+// ErrEncryptedPDF is returned when a PDF is encrypted and no valid password was supplied
+var ErrEncryptedPDF = errors.New("PDF is encrypted")
+
 // PDFJobResult represents the result of PDF processing
 type PDFJobResult struct {
 	ItemsProcessed int      `json:"items_processed"`
 	ItemsCreated   int      `json:"items_created"`
 	ItemsUpdated   int      `json:"items_updated"`
+	ItemsReplaced  int      `json:"items_replaced,omitempty"`
+	Duplicate      string   `json:"duplicate,omitempty"` // skipped, replaced or appended when the invoice was already imported
 	Errors         []string `json:"errors,omitempty"`
 	ProcessingTime string   `json:"processing_time"`
 }
 
+// ValidOnDuplicate reports whether mode is an accepted on_duplicate value
+func ValidOnDuplicate(mode string) bool {
+	switch mode {
+	case "", OnDuplicateSkip, OnDuplicateReplace, OnDuplicateAppend:
+		return true
+	}
+	return false
+}
+
 // PDFProcessor handles PDF processing tasks
 type PDFProcessor struct {
-	service ports.InventoryService // Use the interface
-	db      ports.Database         // Use the interface
-	logger  *slog.Logger
+	service          ports.InventoryService // Use the interface
+	db               ports.Database         // Use the interface
+	files            FileStore
+	logger           *slog.Logger
+	progressInterval int             // Items saved between job progress updates; 0 saves everything at once
+	ocr              OCREngine       // Fallback for pages without a text layer; nil disables OCR
+	notifier         *ImportNotifier // Emails NotifyEmail on completion; nil disables notifications
+	profiles         *pdfparse.Profiles
 }
 
-// NewPDFProcessor creates a new PDF processor
-func NewPDFProcessor(service ports.InventoryService, db ports.Database, logger *slog.Logger) *PDFProcessor {
+// NewPDFProcessor creates a new PDF processor. ocr may be nil to disable the OCR fallback
+// and notifier may be nil to disable completion emails. profiles may be nil when only the
+// default invoice profile is used.
+func NewPDFProcessor(service ports.InventoryService, db ports.Database, files FileStore, logger *slog.Logger, progressInterval int, ocr OCREngine, notifier *ImportNotifier, profiles *pdfparse.Profiles) *PDFProcessor {
 	return &PDFProcessor{
-		service: service,
-		db:      db,
-		logger:  logger.With(slog.String("processor", "pdf")),
+		service:          service,
+		db:               db,
+		files:            files,
+		logger:           logger.With(slog.String("processor", "pdf")),
+		progressInterval: progressInterval,
+		ocr:              ocr,
+		notifier:         notifier,
+		profiles:         profiles,
 	}
 }
 
@@ -73,56 +130,274 @@ func (p *PDFProcessor) ProcessPDF(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
+	// Items saved by the job are stamped with the user who uploaded the PDF
+	if payload.UserID != "" {
+		ctx = logger.WithUserID(ctx, payload.UserID)
+	}
+
 	p.logger.InfoContext(ctx, "processing PDF",
 		slog.String("job_id", payload.JobID),
 		slog.String("invoice_id", payload.InvoiceID))
 
+	if !ValidOnDuplicate(payload.OnDuplicate) {
+		errMsg := fmt.Sprintf("invalid on_duplicate mode %q", payload.OnDuplicate)
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		return fmt.Errorf("%s: %w", errMsg, asynq.SkipRetry)
+	}
+
+	allocation := domain.ShippingAllocation(payload.ShippingAllocation)
+	if (allocation != "" && !allocation.IsValid()) || payload.ShippingTotal.IsNegative() {
+		errMsg := fmt.Sprintf("invalid shipping allocation of %s by %q", payload.ShippingTotal, payload.ShippingAllocation)
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		return fmt.Errorf("%s: %w", errMsg, asynq.SkipRetry)
+	}
+
+	profile, err := p.profiles.Resolve(payload.Profile, payload.AuctionID)
+	if err != nil {
+		errMsg := err.Error()
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		return fmt.Errorf("%s: %w", errMsg, asynq.SkipRetry)
+	}
+
 	// Update job status to processing
 	_ = p.updateJobStatus(ctx, payload.JobID, "processing", nil)
 
+	// Batch uploads carry no invoice ID, so there is nothing to match against
+	duplicate := false
+	if payload.InvoiceID != "" {
+		var err error
+		duplicate, err = p.service.HasInvoice(ctx, payload.InvoiceID)
+		if err != nil {
+			errMsg := err.Error()
+			_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+			return err
+		}
+	}
+	if duplicate && (payload.OnDuplicate == "" || payload.OnDuplicate == OnDuplicateSkip) {
+		return p.skipDuplicateInvoice(ctx, payload, start)
+	}
+
+	filePath, cleanup, err := fetchImportFile(ctx, p.files, payload.FileKey)
+	if err != nil {
+		errMsg := err.Error()
+		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+		return err
+	}
+	defer cleanup()
+
 	// Extract items from PDF
-	items, err := p.extractItemsFromPDF(ctx, payload.FilePath, payload.InvoiceID, payload.AuctionID)
+	opts := pdfparse.Options{Layout: payload.Layout, Profile: profile}
+	items, err := p.extractItemsFromPDF(ctx, filePath, payload.Password, payload.InvoiceID, payload.AuctionID, opts)
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to extract items: %v", err)
 		_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
-		return fmt.Errorf(errMsg)
+		if errors.Is(err, ErrEncryptedPDF) {
+			// Retrying cannot succeed without a different password
+			err = fmt.Errorf("failed to extract items: %w: %w", err, asynq.SkipRetry)
+		} else {
+			err = errors.New(errMsg)
+		}
+		if finalAttempt(ctx, err) {
+			deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
+		}
+		return err
+	}
+
+	if payload.ShippingTotal.IsPositive() {
+		if err := domain.AllocateShipping(items, payload.ShippingTotal, allocation); err != nil {
+			errMsg := fmt.Sprintf("failed to allocate shipping: %v", err)
+			_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+			deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
+			return fmt.Errorf("%s: %w", errMsg, asynq.SkipRetry)
+		}
 	}
 
-	err = p.service.SaveItems(ctx, items)
+	// Existing items are only removed once the new ones have been extracted
+	replaced := 0
+	if duplicate && payload.OnDuplicate == OnDuplicateReplace {
+		deleted, err := p.service.DeleteInvoiceItems(ctx, payload.InvoiceID)
+		if err != nil {
+			errMsg := err.Error()
+			_ = p.updateJobStatus(ctx, payload.JobID, "failed", &errMsg)
+			return err
+		}
+		replaced = int(deleted)
+	}
+
+	saved, saveErrors, err := p.saveItems(ctx, payload.JobID, items)
+	if errors.Is(err, errJobCancelled) {
+		return p.stopCancelledJob(ctx, payload, len(items), saved, replaced, saveErrors, start)
+	}
 
 	// Prepare result and update job status
-	var errors []string
-	status := "completed"
+	errors := saveErrors
 	if err != nil {
-		status = "completed_with_errors"
 		errors = append(errors, err.Error())
 	}
+	status := "completed"
+	if len(errors) > 0 {
+		status = "completed_with_errors"
+	}
 
 	result := PDFJobResult{
 		ItemsProcessed: len(items),
-		ItemsCreated:   len(items), // We are now only creating
+		ItemsCreated:   saved, // We are now only creating
 		ItemsUpdated:   0,
+		ItemsReplaced:  replaced,
 		Errors:         errors,
 		ProcessingTime: time.Since(start).String(),
 	}
+	if duplicate && payload.OnDuplicate == OnDuplicateReplace {
+		result.Duplicate = "replaced"
+	} else if duplicate {
+		result.Duplicate = "appended"
+	}
 
 	resultJSON, _ := json.Marshal(result)
 	_ = p.updateJobStatusWithResult(ctx, payload.JobID, status, resultJSON)
 
-	// Clean up temporary file
-	if strings.HasPrefix(payload.FilePath, os.TempDir()) {
-		_ = os.Remove(payload.FilePath)
+	p.notifier.NotifyImportCompleted(ctx, payload.NotifyEmail, ImportSummary{
+		JobID:         payload.JobID,
+		FileName:      notificationFileName(payload.FileName, payload.FileKey),
+		ItemsImported: saved,
+		ItemsFailed:   len(items) - saved,
+		Errors:        errors,
+	})
+
+	// The upload is no longer needed once it will not be retried
+	if finalAttempt(ctx, err) {
+		deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
 	}
 
 	p.logger.InfoContext(ctx, "PDF processing completed",
 		slog.String("job_id", payload.JobID),
 		slog.Int("items_processed", result.ItemsProcessed))
 
-	return err // Return the error from the service call, if any
+	// Items that failed are reported on the job; retrying would duplicate those already saved
+	return err
+}
+
+// openPDF opens a PDF for reading, decrypting it with password when the document is encrypted.
+// Documents with an empty user password open without one.
+func openPDF(filePath, password string) (*os.File, *pdf.Reader, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	tried := false
+	r, err := pdf.NewReaderEncrypted(f, fi.Size(), func() string {
+		if tried {
+			return ""
+		}
+		tried = true
+		return password
+	})
+	if err != nil {
+		f.Close()
+		if errors.Is(err, pdf.ErrInvalidPassword) {
+			if password == "" {
+				return nil, nil, fmt.Errorf("%w: a password is required", ErrEncryptedPDF)
+			}
+			return nil, nil, fmt.Errorf("%w: the password was rejected", ErrEncryptedPDF)
+		}
+		return nil, nil, err
+	}
+
+	return f, r, nil
+}
+
+// skipDuplicateInvoice completes the job without importing because the invoice is already in inventory
+func (p *PDFProcessor) skipDuplicateInvoice(ctx context.Context, payload PDFJobPayload, start time.Time) error {
+	result := PDFJobResult{
+		Duplicate:      "skipped",
+		ProcessingTime: time.Since(start).String(),
+	}
+	resultJSON, _ := json.Marshal(result)
+	_ = p.updateJobStatusWithResult(ctx, payload.JobID, "completed", resultJSON)
+
+	p.notifier.NotifyImportCompleted(ctx, payload.NotifyEmail, ImportSummary{
+		JobID:    payload.JobID,
+		FileName: notificationFileName(payload.FileName, payload.FileKey),
+		Errors:   []string{fmt.Sprintf("invoice %s was already imported; nothing was added", payload.InvoiceID)},
+	})
+
+	deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
+
+	p.logger.InfoContext(ctx, "skipped duplicate invoice",
+		slog.String("job_id", payload.JobID),
+		slog.String("invoice_id", payload.InvoiceID))
+
+	return nil
+}
+
+// saveItems saves items in chunks of progressInterval, recording job progress after each chunk.
+// Items that fail to save are skipped and described in the returned messages; the error is only
+// set when saving had to stop early, which is errJobCancelled if the job was cancelled.
+func (p *PDFProcessor) saveItems(ctx context.Context, jobID string, items []domain.InventoryItem) (int, []string, error) {
+	chunkSize := p.progressInterval
+	if chunkSize <= 0 {
+		chunkSize = len(items)
+	}
+
+	saved := 0
+	var failures []string
+	for offset := 0; ; offset += chunkSize {
+		if jobCancelled(ctx, p.db, jobID, p.logger) {
+			return saved, failures, errJobCancelled
+		}
+
+		end := min(offset+chunkSize, len(items))
+		report, err := p.service.SaveItemsPartial(ctx, items[offset:end])
+		saved += len(report.Saved)
+		for _, f := range report.Failed {
+			failures = append(failures, fmt.Sprintf("item %d (%s): %s", offset+f.Index+1, f.ItemName, f.Error))
+		}
+		if err != nil {
+			return saved, failures, err
+		}
+		p.updateJobProgress(ctx, jobID, end, len(items))
+
+		if end >= len(items) {
+			return saved, failures, nil
+		}
+	}
+}
+
+// stopCancelledJob records the items saved before the job was cancelled and drops the upload.
+// Nothing is retried and no completion email is sent since the user stopped the import.
+func (p *PDFProcessor) stopCancelledJob(ctx context.Context, payload PDFJobPayload, extracted, saved, replaced int, saveErrors []string, start time.Time) error {
+	result := PDFJobResult{
+		ItemsProcessed: extracted,
+		ItemsCreated:   saved,
+		ItemsReplaced:  replaced,
+		Errors:         saveErrors,
+		ProcessingTime: time.Since(start).String(),
+	}
+	resultJSON, _ := json.Marshal(result)
+	if err := recordCancelledResult(ctx, p.db, payload.JobID, resultJSON); err != nil {
+		p.logger.WarnContext(ctx, "failed to record cancelled job result",
+			slog.String("job_id", payload.JobID),
+			slog.String("error", err.Error()))
+	}
+
+	deleteImportFile(ctx, p.files, payload.FileKey, p.logger)
+
+	p.logger.InfoContext(ctx, "PDF import cancelled",
+		slog.String("job_id", payload.JobID),
+		slog.Int("items_created", saved),
+		slog.Int("items_extracted", extracted))
+
+	return nil
 }
 
-func (p *PDFProcessor) extractItemsFromPDF(ctx context.Context, filePath string, invoiceID string, auctionID int) ([]domain.InventoryItem, error) {
-	f, r, err := pdf.Open(filePath)
+func (p *PDFProcessor) extractItemsFromPDF(ctx context.Context, filePath, password string, invoiceID string, auctionID int, opts pdfparse.Options) ([]domain.InventoryItem, error) {
+	f, r, err := openPDF(filePath, password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PDF: %w", err)
 	}
@@ -142,7 +417,23 @@ func (p *PDFProcessor) extractItemsFromPDF(ctx context.Context, filePath string,
 		if err != nil {
 			p.logger.WarnContext(ctx, "failed to extract text from page",
 				slog.Int("page", pageNum),
-				err)
+				slog.String("error", err.Error()))
+			text = ""
+		}
+
+		// Scanned pages have no text layer; recover what we can with OCR
+		if strings.TrimSpace(text) == "" && p.ocr != nil {
+			text, err = p.ocr.RecognizePage(ctx, filePath, password, pageNum)
+			if err != nil {
+				p.logger.WarnContext(ctx, "failed to OCR page",
+					slog.Int("page", pageNum),
+					slog.String("error", err.Error()))
+				continue
+			}
+			p.logger.DebugContext(ctx, "recovered page text with OCR", slog.Int("page", pageNum))
+		}
+
+		if text == "" {
 			continue
 		}
 
@@ -151,12 +442,19 @@ func (p *PDFProcessor) extractItemsFromPDF(ctx context.Context, filePath string,
 	}
 
 	// Parse the extracted text to find items
-	rawItems := p.parseInvoiceItems(textLines)
+	rawItems := p.parseInvoiceItems(textLines, opts)
 
 	// Convert raw items to domain items
 	items := make([]domain.InventoryItem, 0, len(rawItems))
 	for _, rawItem := range rawItems {
-		item := p.createInventoryItem(rawItem, invoiceID, auctionID)
+		item, err := p.createInventoryItem(rawItem, invoiceID, auctionID)
+		if err != nil {
+			p.logger.WarnContext(ctx, "skipping invoice item",
+				slog.String("invoice_id", invoiceID),
+				slog.String("description", rawItem.description),
+				slog.String("error", err.Error()))
+			continue
+		}
 		items = append(items, item)
 	}
 
@@ -173,124 +471,32 @@ type rawInvoiceItem struct {
 	quantity    int
 }
 
-func (p *PDFProcessor) parseInvoiceItems(lines []string) []rawInvoiceItem {
-	var items []rawInvoiceItem
-
-	// Patterns for parsing invoice lines
-	headerRe := regexp.MustCompile(`(?i)(LOT.*PRICE|LEAD.*ITEM.*PRICE)`)
-	footerRe := regexp.MustCompile(`(?i)(A payment of|SUBTOTAL|TOTAL)`)
-	priceRe := regexp.MustCompile(`\$?\s*\d{1,3}(?:,\d{3})*\.\d{2}\s*$`)
-
-	// Find start of items section
-	startIdx := 0
-	for i, line := range lines {
-		if headerRe.MatchString(line) {
-			startIdx = i + 1
-			break
-		}
-	}
-
-	// Buffer for multi-line descriptions
-	var descBuffer []string
-
-	for i := startIdx; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		// Check if we've reached the footer
-		if footerRe.MatchString(line) {
-			break
-		}
-
-		// Check if line ends with a price
-		if priceRe.MatchString(line) {
-			// Extract price
-			priceStr := priceRe.FindString(line)
-			bidAmount := p.parseCurrency(priceStr)
-
-			// Extract description (everything before the price)
-			description := strings.TrimSpace(priceRe.ReplaceAllString(line, ""))
-
-			// Add buffered descriptions if any
-			if len(descBuffer) > 0 {
-				fullDesc := strings.Join(append(descBuffer, description), " ")
-				fullDesc = p.cleanDescription(fullDesc)
-
-				if fullDesc != "" {
-					items = append(items, rawInvoiceItem{
-						description: fullDesc,
-						bidAmount:   bidAmount,
-						quantity:    1,
-					})
-				}
-
-				// Clear buffer
-				descBuffer = descBuffer[:0]
-			} else if description != "" {
-				// Single-line item
-				description = p.cleanDescription(description)
-				if description != "" {
-					items = append(items, rawInvoiceItem{
-						description: description,
-						bidAmount:   bidAmount,
-						quantity:    1,
-					})
-				}
-			}
-		} else {
-			// This is part of a multi-line description
-			descBuffer = append(descBuffer, line)
-		}
+// parseInvoiceItems parses the items of an invoice with the layout and profile of opts, ending
+// them at any TOTAL line and leaving lot columns in descriptions as imports always have
+func (p *PDFProcessor) parseInvoiceItems(lines []string, opts pdfparse.Options) []rawInvoiceItem {
+	opts.StopAtTotal = true
+	opts.KeepLotMetadata = true
+	parsed := opts.ParseInvoice(lines)
+
+	items := make([]rawInvoiceItem, 0, len(parsed))
+	for _, item := range parsed {
+		items = append(items, rawInvoiceItem{
+			description: item.Description,
+			bidAmount:   item.Bid,
+			quantity:    1,
+		})
 	}
-
 	return items
 }
 
-func (p *PDFProcessor) cleanDescription(desc string) string {
-	// Remove item numbers and lot numbers
-	desc = regexp.MustCompile(`^\d+\s+`).ReplaceAllString(desc, "")
-	desc = regexp.MustCompile(`\b\d{5,6}\s+\d{1,3}\s+[A-Z0-9]+\b`).ReplaceAllString(desc, "")
-
-	// Remove multiple spaces
-	desc = regexp.MustCompile(`\s+`).ReplaceAllString(desc, " ")
-
-	// Remove dashes used as fillers
-	desc = regexp.MustCompile(`-{3,}`).ReplaceAllString(desc, "")
-
-	return strings.TrimSpace(desc)
-}
-
-func (p *PDFProcessor) parseCurrency(val string) decimal.Decimal {
-	// Remove dollar sign, commas, and spaces
-	cleaned := strings.ReplaceAll(val, "$", "")
-	cleaned = strings.ReplaceAll(cleaned, ",", "")
-	cleaned = strings.TrimSpace(cleaned)
-
-	d, err := decimal.NewFromString(cleaned)
-	if err != nil {
-		return decimal.Zero
-	}
-	return d
-}
-
-func (p *PDFProcessor) createInventoryItem(raw rawInvoiceItem, invoiceID string, auctionID int) domain.InventoryItem {
-	// Calculate buyer's premium and sales tax (using typical auction percentages)
-	bpRate := decimal.NewFromFloat(0.18)     // 18% buyer's premium
-	taxRate := decimal.NewFromFloat(0.08625) // 8.625% NY sales tax
-
-	buyersPremium := raw.bidAmount.Mul(bpRate).Round(2)
-	subtotal := raw.bidAmount.Add(buyersPremium)
-	salesTax := subtotal.Mul(taxRate).Round(2)
-
+func (p *PDFProcessor) createInventoryItem(raw rawInvoiceItem, invoiceID string, auctionID int) (domain.InventoryItem, error) {
 	// Categorize item based on description
 	category, condition := p.categorizeItem(raw.description)
 
 	// Generate item name from description
 	itemName := p.generateItemName(raw.description)
 
-	return domain.InventoryItem{
+	item := domain.InventoryItem{
 		LotID:           uuid.New(),
 		InvoiceID:       invoiceID,
 		AuctionID:       auctionID,
@@ -300,13 +506,17 @@ func (p *PDFProcessor) createInventoryItem(raw rawInvoiceItem, invoiceID string,
 		Condition:       condition,
 		Quantity:        raw.quantity,
 		BidAmount:       raw.bidAmount,
-		BuyersPremium:   buyersPremium,
-		SalesTax:        salesTax,
 		AcquisitionDate: time.Now(),
-		Keywords:        p.extractKeywords(raw.description),
+		Keywords:        keywords.Extract(raw.description, maxItemKeywords),
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
+
+	// Invoices don't state their rates, so charge the typical premium and tax
+	if err := item.CalculateCosts(&domain.DefaultCostRates); err != nil {
+		return domain.InventoryItem{}, err
+	}
+	return item, nil
 }
 
 func (p *PDFProcessor) categorizeItem(description string) (domain.ItemCategory, domain.ItemCondition) {
@@ -364,32 +574,6 @@ func (p *PDFProcessor) generateItemName(description string) string {
 	return name
 }
 
-func (p *PDFProcessor) extractKeywords(description string) []string {
-	// Simple keyword extraction
-	stopWords := map[string]bool{
-		"the": true, "a": true, "an": true, "and": true, "or": true,
-		"but": true, "in": true, "on": true, "at": true, "to": true,
-		"for": true, "of": true, "with": true, "by": true, "from": true,
-	}
-
-	words := strings.Fields(strings.ToLower(description))
-	var keywords []string
-	seen := make(map[string]bool)
-
-	for _, word := range words {
-		word = strings.Trim(word, ".,!?;:")
-		if !stopWords[word] && len(word) > 2 && !seen[word] {
-			keywords = append(keywords, word)
-			seen[word] = true
-			if len(keywords) >= 10 {
-				break
-			}
-		}
-	}
-
-	return keywords
-}
-
 func (p *PDFProcessor) checkItemExists(ctx context.Context, lotID uuid.UUID) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM inventory WHERE lot_id = $1 AND deleted_at IS NULL)`
 	var exists bool
@@ -401,17 +585,38 @@ func (p *PDFProcessor) updateJobStatus(ctx context.Context, jobID string, status
 	query := `
 		UPDATE async_jobs 
 		SET status = $2, error = $3, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1`
+		WHERE id = $1 AND status <> 'cancelled'`
 
 	_, err := p.db.Exec(ctx, query, jobID, status, errorMsg)
 	return err
 }
 
+// updateJobProgress records how far a job has got. Failures are logged rather than
+// returned since a missed progress update should never abort the import.
+func (p *PDFProcessor) updateJobProgress(ctx context.Context, jobID string, processed, total int) {
+	progress := 100
+	if total > 0 {
+		progress = processed * 100 / total
+	}
+
+	query := `
+		UPDATE async_jobs 
+		SET progress = $2, items_processed = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+	if _, err := p.db.Exec(ctx, query, jobID, progress, processed); err != nil {
+		p.logger.WarnContext(ctx, "failed to update job progress",
+			slog.String("job_id", jobID),
+			slog.Int("items_processed", processed),
+			slog.String("error", err.Error()))
+	}
+}
+
 func (p *PDFProcessor) updateJobStatusWithResult(ctx context.Context, jobID string, status string, result json.RawMessage) error {
 	query := `
 		UPDATE async_jobs 
 		SET status = $2, result = $3, completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1`
+		WHERE id = $1 AND status <> 'cancelled'`
 
 	_, err := p.db.Exec(ctx, query, jobID, status, result)
 	return err
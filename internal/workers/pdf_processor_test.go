@@ -4,66 +4,342 @@ package workers_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/ammerola/resell-be/internal/core/domain"
+	"github.com/ammerola/resell-be/internal/core/ports"
+	"github.com/ammerola/resell-be/internal/pkg/pdfparse"
 	"github.com/ammerola/resell-be/internal/workers"
 	"github.com/ammerola/resell-be/test/helpers"
 	"github.com/ammerola/resell-be/test/mocks"
 )
 
+// fakeOCR returns canned text per page and records which pages it was asked for
+type fakeOCR struct {
+	pages     map[int]string
+	requested []int
+}
+
+func (f *fakeOCR) RecognizePage(_ context.Context, _, _ string, pageNum int) (string, error) {
+	f.requested = append(f.requested, pageNum)
+	return f.pages[pageNum], nil
+}
+
 func TestPDFProcessor_ProcessPDF(t *testing.T) {
+	scannedOCR := &fakeOCR{pages: map[int]string{
+		1: "LOT DESCRIPTION PRICE\nBrass table lamp 12.00\nSUBTOTAL 12.00\n",
+	}}
+
 	// Test setup remains the same
 	tests := []struct {
-		name          string
-		payload       workers.PDFJobPayload
-		setupMocks    func(*mocks.MockInventoryService, *mocks.MockDatabase)
-		setupFile     func() string
-		expectedError bool
-		errorContains string
+		name             string
+		payload          workers.PDFJobPayload
+		progressInterval int
+		ocr              workers.OCREngine
+		setupMocks       func(*mocks.MockInventoryService, *mocks.MockDatabase)
+		setupFile        func() string
+		expectedError    bool
+		errorContains    string
+		errorIs          error
 	}{
 		{
 			name: "successfully_processes_valid_pdf",
 			payload: workers.PDFJobPayload{
 				JobID:     uuid.New().String(),
-				FilePath:  "", // Will be set by setupFile
 				InvoiceID: "TEST-001",
 				AuctionID: 12345,
 			},
 			setupFile: func() string {
 				// A minimal PDF that the parser can read without error
-				content := []byte(`%PDF-1.4
-1 0 obj<</Type/Catalog/Pages 2 0 R>>endobj 2 0 obj<</Type/Pages/Count 1/Kids[3 0 R]>>endobj 3 0 obj<</Type/Page/Parent 2 0 R/MediaBox[0 0 612 792]>>endobj
-xref
-0 4
-0000000000 65535 f
-0000000010 00000 n
-0000000059 00000 n
-0000000112 00000 n
-trailer<</Size 4/Root 1 0 R>>
-startxref
-178
-%%EOF`)
-				return helpers.CreateTempFile(t, content, ".pdf")
+				return helpers.CreateTestPDF(t, nil)
 			},
 			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
-				// Expect job status updates (processing and completed)
+				// Expect job status updates (processing, progress and completed)
 				db.EXPECT().
 					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-					Times(2).
+					Times(3).
 					Return(pgconn.CommandTag{}, nil)
 
-				// Expect the service's SaveItems method to be called once with all extracted items.
+				// Expect the service's SaveItemsPartial method to be called once with all extracted items.
 				// Since our test PDF is minimal and has no real items, we expect a call with an empty slice.
 				service.EXPECT().
-					SaveItems(gomock.Any(), gomock.Any()).
-					Return(nil)
+					SaveItemsPartial(gomock.Any(), gomock.Any()).
+					Return(ports.SaveReport{}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "splits_two_column_layout",
+			payload: workers.PDFJobPayload{
+				JobID:     uuid.New().String(),
+				InvoiceID: "TEST-002",
+				AuctionID: 12345,
+				Layout:    workers.LayoutTwoColumn,
+			},
+			setupFile: func() string {
+				return helpers.CreateTestPDF(t, []string{
+					"LOT DESCRIPTION PRICE",
+					"Brass table lamp 12.00 Oak side chair 30.00",
+					"Sterling silver spoon 8.50",
+					"SUBTOTAL 50.50",
+				})
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(3).
+					Return(pgconn.CommandTag{}, nil)
+
+				service.EXPECT().
+					SaveItemsPartial(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+						require.Len(t, items, 3)
+						assert.Equal(t, "Brass table lamp", items[0].Description)
+						assert.True(t, decimal.NewFromFloat(12).Equal(items[0].BidAmount))
+						assert.Equal(t, "Oak side chair", items[1].Description)
+						assert.True(t, decimal.NewFromFloat(30).Equal(items[1].BidAmount))
+						assert.Equal(t, "Sterling silver spoon", items[2].Description)
+						return ports.SaveReport{}, nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name: "allocates_invoice_shipping_by_bid",
+			payload: workers.PDFJobPayload{
+				JobID:         uuid.New().String(),
+				InvoiceID:     "TEST-003",
+				AuctionID:     12345,
+				ShippingTotal: decimal.RequireFromString("10.10"),
+			},
+			setupFile: func() string {
+				return helpers.CreateTestPDF(t, []string{
+					"LOT DESCRIPTION PRICE",
+					"Brass table lamp 12.00",
+					"Oak side chair 30.00",
+					"Sterling silver spoon 8.50",
+					"SUBTOTAL 50.50",
+				})
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(3).
+					Return(pgconn.CommandTag{}, nil)
+
+				service.EXPECT().
+					SaveItemsPartial(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+						require.Len(t, items, 3)
+						for i, want := range []string{"2.40", "6.00", "1.70"} {
+							assert.Equal(t, want, items[i].ShippingCost.StringFixed(2))
+							assert.True(t, items[i].TotalCost.GreaterThan(items[i].BidAmount.Add(items[i].ShippingCost)))
+						}
+						return ports.SaveReport{}, nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name: "decrypts_password_protected_pdf",
+			payload: workers.PDFJobPayload{
+				JobID:     uuid.New().String(),
+				InvoiceID: "TEST-004",
+				AuctionID: 12345,
+				Password:  "hunter2",
+			},
+			setupFile: func() string {
+				return helpers.CreateEncryptedTestPDF(t, []string{
+					"LOT DESCRIPTION PRICE",
+					"Brass table lamp 12.00",
+					"SUBTOTAL 12.00",
+				}, "hunter2")
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(3).
+					Return(pgconn.CommandTag{}, nil)
+
+				service.EXPECT().
+					SaveItemsPartial(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+						require.Len(t, items, 1)
+						assert.Equal(t, "Brass table lamp", items[0].Description)
+						return ports.SaveReport{}, nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name: "fails_encrypted_pdf_without_password",
+			payload: workers.PDFJobPayload{
+				JobID:     uuid.New().String(),
+				InvoiceID: "TEST-005",
+				AuctionID: 12345,
+			},
+			setupFile: func() string {
+				return helpers.CreateEncryptedTestPDF(t, []string{"Brass table lamp 12.00"}, "hunter2")
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				// Processing, then failed with the encryption error recorded on the job
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), "processing", gomock.Any()).
+					Return(pgconn.CommandTag{}, nil)
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), "failed", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+						errMsg := args[2].(*string)
+						assert.Contains(t, *errMsg, "PDF is encrypted: a password is required")
+						return pgconn.CommandTag{}, nil
+					})
+			},
+			expectedError: true,
+			errorIs:       workers.ErrEncryptedPDF,
+		},
+		{
+			name: "fails_encrypted_pdf_with_wrong_password",
+			payload: workers.PDFJobPayload{
+				JobID:     uuid.New().String(),
+				InvoiceID: "TEST-006",
+				AuctionID: 12345,
+				Password:  "wrong",
+			},
+			setupFile: func() string {
+				return helpers.CreateEncryptedTestPDF(t, []string{"Brass table lamp 12.00"}, "hunter2")
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(pgconn.CommandTag{}, nil)
+			},
+			expectedError: true,
+			errorContains: "the password was rejected",
+		},
+		{
+			name: "falls_back_to_ocr_for_image_only_pages",
+			payload: workers.PDFJobPayload{
+				JobID:     uuid.New().String(),
+				InvoiceID: "TEST-007",
+				AuctionID: 12345,
+			},
+			ocr: scannedOCR,
+			setupFile: func() string {
+				// No text operators, as with a scanned page
+				return helpers.CreateTestPDF(t, nil)
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(3).
+					Return(pgconn.CommandTag{}, nil)
+
+				service.EXPECT().
+					SaveItemsPartial(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+						require.Len(t, items, 1)
+						assert.Equal(t, "Brass table lamp", items[0].Description)
+						assert.Equal(t, []int{1}, scannedOCR.requested)
+						return ports.SaveReport{}, nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name: "reports_items_that_fail_to_save",
+			payload: workers.PDFJobPayload{
+				JobID:     "partial-job",
+				InvoiceID: "TEST-008",
+				AuctionID: 12345,
+			},
+			setupFile: func() string {
+				return helpers.CreateTestPDF(t, []string{
+					"LOT DESCRIPTION PRICE",
+					"Brass table lamp 12.00",
+					"Oak side chair 30.00",
+					"SUBTOTAL 42.00",
+				})
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), "partial-job", "processing", gomock.Any()).
+					Return(pgconn.CommandTag{}, nil)
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), "partial-job", 100, 2).
+					Return(pgconn.CommandTag{}, nil)
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), "partial-job", "completed_with_errors", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+						var result workers.PDFJobResult
+						require.NoError(t, json.Unmarshal(args[2].(json.RawMessage), &result))
+						assert.Equal(t, 2, result.ItemsProcessed)
+						assert.Equal(t, 1, result.ItemsCreated)
+						require.Len(t, result.Errors, 1)
+						assert.Contains(t, result.Errors[0], "item 2 (")
+						assert.Contains(t, result.Errors[0], "duplicate key value")
+						return pgconn.CommandTag{}, nil
+					})
+
+				service.EXPECT().
+					SaveItemsPartial(gomock.Any(), gomock.Len(2)).
+					DoAndReturn(func(_ context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+						return ports.SaveReport{
+							Saved:  []uuid.UUID{uuid.New()},
+							Failed: []ports.SaveFailure{{Index: 1, ItemName: items[1].ItemName, Error: "duplicate key value"}},
+						}, nil
+					})
+			},
+			// The job completes so saved items are not duplicated by a retry
+			expectedError: false,
+		},
+		{
+			name: "reports_progress_after_each_chunk",
+			payload: workers.PDFJobPayload{
+				JobID:     "progress-job",
+				InvoiceID: "TEST-003",
+				AuctionID: 12345,
+			},
+			progressInterval: 2,
+			setupFile: func() string {
+				return helpers.CreateTestPDF(t, []string{
+					"LOT DESCRIPTION PRICE",
+					"Brass table lamp 12.00",
+					"Oak side chair 30.00",
+					"Sterling silver spoon 8.50",
+					"SUBTOTAL 50.50",
+				})
+			},
+			setupMocks: func(service *mocks.MockInventoryService, db *mocks.MockDatabase) {
+				// A failed progress write is logged but must not abort the job
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), "progress-job", 66, 2).
+					Return(pgconn.CommandTag{}, errors.New("connection reset"))
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), "progress-job", 100, 3).
+					Return(pgconn.CommandTag{}, nil)
+				db.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(pgconn.CommandTag{}, nil)
+
+				gomock.InOrder(
+					service.EXPECT().
+						SaveItemsPartial(gomock.Any(), gomock.Len(2)).
+						Return(ports.SaveReport{}, nil),
+					service.EXPECT().
+						SaveItemsPartial(gomock.Any(), gomock.Len(1)).
+						Return(ports.SaveReport{}, nil),
+				)
 			},
 			expectedError: false,
 		},
@@ -79,14 +355,18 @@ startxref
 			mockDB := mocks.NewMockDatabase(ctrl)
 			logger := helpers.TestLogger()
 
-			// This now compiles correctly
-			processor := workers.NewPDFProcessor(mockService, mockDB, logger)
+			// None of these invoices have been imported before
+			mockService.EXPECT().HasInvoice(gomock.Any(), tt.payload.InvoiceID).Return(false, nil).AnyTimes()
 
-			// Setup file if needed
+			// Stage the uploaded file where the processor will download it from
+			var files workers.FileStore
 			if tt.setupFile != nil {
-				tt.payload.FilePath = tt.setupFile()
+				files, tt.payload.FileKey = stageUpload(t, tt.setupFile())
 			}
 
+			processor := workers.NewPDFProcessor(mockService, mockDB, files, logger, tt.progressInterval, tt.ocr, nil, nil)
+			expectJobRunning(mockDB)
+
 			// Setup mocks
 			tt.setupMocks(mockService, mockDB)
 
@@ -105,9 +385,265 @@ startxref
 				if tt.errorContains != "" {
 					assert.Contains(t, err.Error(), tt.errorContains)
 				}
+				if tt.errorIs != nil {
+					assert.ErrorIs(t, err, tt.errorIs)
+					assert.ErrorIs(t, err, asynq.SkipRetry)
+				}
 			} else {
 				require.NoError(t, err)
 			}
 		})
 	}
 }
+
+func TestPDFProcessor_ProcessPDF_OnDuplicate(t *testing.T) {
+	tests := []struct {
+		name              string
+		onDuplicate       string
+		alreadyImported   bool
+		setupMocks        func(*mocks.MockInventoryService)
+		expectedStatus    string
+		expectedDuplicate string
+		expectedCreated   int
+		expectedReplaced  int
+		expectUpload      bool
+	}{
+		{
+			name:              "skips_imported_invoice_by_default",
+			alreadyImported:   true,
+			expectedStatus:    "completed",
+			expectedDuplicate: "skipped",
+		},
+		{
+			name:              "skip",
+			onDuplicate:       workers.OnDuplicateSkip,
+			alreadyImported:   true,
+			expectedStatus:    "completed",
+			expectedDuplicate: "skipped",
+		},
+		{
+			name:            "replace",
+			onDuplicate:     workers.OnDuplicateReplace,
+			alreadyImported: true,
+			setupMocks: func(service *mocks.MockInventoryService) {
+				gomock.InOrder(
+					service.EXPECT().DeleteInvoiceItems(gomock.Any(), "INV-500").Return(int64(3), nil),
+					service.EXPECT().SaveItemsPartial(gomock.Any(), gomock.Len(1)).
+						Return(ports.SaveReport{Saved: []uuid.UUID{uuid.New()}}, nil),
+				)
+			},
+			expectedStatus:    "completed",
+			expectedDuplicate: "replaced",
+			expectedCreated:   1,
+			expectedReplaced:  3,
+		},
+		{
+			name:            "append",
+			onDuplicate:     workers.OnDuplicateAppend,
+			alreadyImported: true,
+			setupMocks: func(service *mocks.MockInventoryService) {
+				service.EXPECT().SaveItemsPartial(gomock.Any(), gomock.Len(1)).
+					Return(ports.SaveReport{Saved: []uuid.UUID{uuid.New()}}, nil)
+			},
+			expectedStatus:    "completed",
+			expectedDuplicate: "appended",
+			expectedCreated:   1,
+		},
+		{
+			name:        "replace_new_invoice_deletes_nothing",
+			onDuplicate: workers.OnDuplicateReplace,
+			setupMocks: func(service *mocks.MockInventoryService) {
+				service.EXPECT().SaveItemsPartial(gomock.Any(), gomock.Len(1)).
+					Return(ports.SaveReport{Saved: []uuid.UUID{uuid.New()}}, nil)
+			},
+			expectedStatus:  "completed",
+			expectedCreated: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockInventoryService(ctrl)
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockService.EXPECT().HasInvoice(gomock.Any(), "INV-500").Return(tt.alreadyImported, nil)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockService)
+			}
+
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), "dup-job", "processing", gomock.Any()).
+				Return(pgconn.CommandTag{}, nil)
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), "dup-job", 100, gomock.Any()).
+				Return(pgconn.CommandTag{}, nil).
+				AnyTimes()
+			var result workers.PDFJobResult
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), "dup-job", tt.expectedStatus, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+					require.NoError(t, json.Unmarshal(args[2].(json.RawMessage), &result))
+					return pgconn.CommandTag{}, nil
+				})
+
+			files, fileKey := stageUpload(t, helpers.CreateTestPDF(t, []string{
+				"LOT DESCRIPTION PRICE",
+				"Brass table lamp 12.00",
+				"SUBTOTAL 12.00",
+			}))
+			processor := workers.NewPDFProcessor(mockService, mockDB, files, helpers.TestLogger(), 0, nil, nil, nil)
+			expectJobRunning(mockDB)
+
+			payload, err := json.Marshal(workers.PDFJobPayload{
+				JobID:       "dup-job",
+				FileKey:     fileKey,
+				InvoiceID:   "INV-500",
+				OnDuplicate: tt.onDuplicate,
+			})
+			require.NoError(t, err)
+
+			err = processor.ProcessPDF(context.Background(), asynq.NewTask(workers.TypePDFProcess, payload))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDuplicate, result.Duplicate)
+			assert.Equal(t, tt.expectedCreated, result.ItemsCreated)
+			assert.Equal(t, tt.expectedReplaced, result.ItemsReplaced)
+
+			// The upload is cleaned up whether or not it was imported
+			exists, err := files.Exists(context.Background(), fileKey)
+			require.NoError(t, err)
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestPDFProcessor_ProcessPDF_InvalidOnDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), "dup-job", "failed", gomock.Any()).
+		Return(pgconn.CommandTag{}, nil)
+
+	processor := workers.NewPDFProcessor(mocks.NewMockInventoryService(ctrl), mockDB, nil, helpers.TestLogger(), 0, nil, nil, nil)
+	payload, err := json.Marshal(workers.PDFJobPayload{JobID: "dup-job", InvoiceID: "INV-500", OnDuplicate: "merge"})
+	require.NoError(t, err)
+
+	err = processor.ProcessPDF(context.Background(), asynq.NewTask(workers.TypePDFProcess, payload))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid on_duplicate mode "merge"`)
+	assert.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestPDFProcessor_ProcessPDF_InvalidShippingAllocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), "shipping-job", "failed", gomock.Any()).
+		Return(pgconn.CommandTag{}, nil)
+
+	processor := workers.NewPDFProcessor(mocks.NewMockInventoryService(ctrl), mockDB, nil, helpers.TestLogger(), 0, nil, nil, nil)
+	payload, err := json.Marshal(workers.PDFJobPayload{
+		JobID:              "shipping-job",
+		InvoiceID:          "INV-502",
+		ShippingTotal:      decimal.NewFromInt(20),
+		ShippingAllocation: "by_weight",
+	})
+	require.NoError(t, err)
+
+	err = processor.ProcessPDF(context.Background(), asynq.NewTask(workers.TypePDFProcess, payload))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid shipping allocation of 20 by "by_weight"`)
+	assert.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestPDFProcessor_ProcessPDF_UnknownProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), "profile-job", "failed", gomock.Any()).
+		Return(pgconn.CommandTag{}, nil)
+
+	profiles, err := pdfparse.NewProfiles([]pdfparse.ProfileConfig{{Name: "balance-due", Footer: "(?i)Balance Due"}})
+	require.NoError(t, err)
+	processor := workers.NewPDFProcessor(mocks.NewMockInventoryService(ctrl), mockDB, nil, helpers.TestLogger(), 0, nil, nil, profiles)
+	payload, err := json.Marshal(workers.PDFJobPayload{JobID: "profile-job", InvoiceID: "INV-501", Profile: "amount-due"})
+	require.NoError(t, err)
+
+	err = processor.ProcessPDF(context.Background(), asynq.NewTask(workers.TypePDFProcess, payload))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown invoice profile "amount-due"`)
+	assert.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestPDFProcessor_ProcessPDF_StopsWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockInventoryService(ctrl)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockService.EXPECT().HasInvoice(gomock.Any(), "INV-600").Return(false, nil)
+
+	// The job is cancelled after the first chunk has been saved
+	gomock.InOrder(
+		mockDB.EXPECT().
+			QueryRow(gomock.Any(), gomock.Any(), "cancel-job").
+			Return(&fakeRows{values: [][]any{{false}}}),
+		mockDB.EXPECT().
+			QueryRow(gomock.Any(), gomock.Any(), "cancel-job").
+			Return(&fakeRows{values: [][]any{{true}}}),
+	)
+	mockService.EXPECT().
+		SaveItemsPartial(gomock.Any(), gomock.Len(1)).
+		Return(ports.SaveReport{Saved: []uuid.UUID{uuid.New()}}, nil)
+
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), "cancel-job", "processing", gomock.Any()).
+		Return(pgconn.CommandTag{}, nil)
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), "cancel-job", 33, 1).
+		Return(pgconn.CommandTag{}, nil)
+
+	// Partial results are kept on the cancelled job; its status is never overwritten
+	var result workers.PDFJobResult
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), "cancel-job", gomock.Any()).
+		DoAndReturn(func(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			assert.Contains(t, sql, "status = 'cancelled'")
+			require.NoError(t, json.Unmarshal(args[1].(json.RawMessage), &result))
+			return pgconn.CommandTag{}, nil
+		})
+
+	files, fileKey := stageUpload(t, helpers.CreateTestPDF(t, []string{
+		"LOT DESCRIPTION PRICE",
+		"Brass table lamp 12.00",
+		"Oak side chair 30.00",
+		"Sterling silver spoon 8.50",
+		"SUBTOTAL 50.50",
+	}))
+	processor := workers.NewPDFProcessor(mockService, mockDB, files, helpers.TestLogger(), 1, nil, nil, nil)
+
+	payload, err := json.Marshal(workers.PDFJobPayload{JobID: "cancel-job", FileKey: fileKey, InvoiceID: "INV-600"})
+	require.NoError(t, err)
+
+	err = processor.ProcessPDF(context.Background(), asynq.NewTask(workers.TypePDFProcess, payload))
+
+	// A cancelled job is finished, not failed, so asynq must not retry it
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.ItemsProcessed)
+	assert.Equal(t, 1, result.ItemsCreated)
+
+	exists, err := files.Exists(context.Background(), fileKey)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,117 @@
+// internal/workers/queue_limits.go
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"golang.org/x/time/rate"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+)
+
+// queueBusyRetryDelay is how long a task waits before retrying when its queue has no free slot
+const queueBusyRetryDelay = 5 * time.Second
+
+// QueueLimitError is returned for a task whose queue is at its concurrency or rate limit.
+// It isn't a failure: the task goes back to asynq and runs again after RetryIn.
+type QueueLimitError struct {
+	Queue   string
+	RetryIn time.Duration
+}
+
+func (e *QueueLimitError) Error() string {
+	return fmt.Sprintf("queue %s is at its limit, retrying in %s", e.Queue, e.RetryIn)
+}
+
+// IsQueueLimitError reports whether err means the task was deferred by a queue limit
+func IsQueueLimitError(err error) bool {
+	var limitErr *QueueLimitError
+	return errors.As(err, &limitErr)
+}
+
+// QueueLimitRetryDelay returns how long to wait before retrying a task deferred by a queue limit
+func QueueLimitRetryDelay(err error) (time.Duration, bool) {
+	var limitErr *QueueLimitError
+	if !errors.As(err, &limitErr) {
+		return 0, false
+	}
+	return limitErr.RetryIn, true
+}
+
+// queueLimit holds the slots and rate limiter for one queue; either may be nil
+type queueLimit struct {
+	slots   chan struct{}
+	limiter *rate.Limiter
+}
+
+// QueueLimiter enforces per-queue concurrency and rate limits under the server's overall
+// Concurrency, so a burst of large PDF jobs can't take every worker. A task over its
+// queue's limit is handed back to asynq instead of holding a worker while it waits.
+type QueueLimiter struct {
+	queues map[string]*queueLimit
+}
+
+// NewQueueLimiter creates a limiter for the given queue limits
+func NewQueueLimiter(limits map[string]config.QueueLimit) *QueueLimiter {
+	queues := make(map[string]*queueLimit, len(limits))
+	for name, limit := range limits {
+		ql := &queueLimit{}
+		if limit.Concurrency > 0 {
+			ql.slots = make(chan struct{}, limit.Concurrency)
+		}
+		if limit.RatePerMinute > 0 {
+			ql.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(limit.RatePerMinute)), 1)
+		}
+		queues[name] = ql
+	}
+	return &QueueLimiter{queues: queues}
+}
+
+// Acquire claims a slot for a task from the queue. The returned release func must be called
+// once the task finishes. A *QueueLimitError means the queue is at its limit.
+func (l *QueueLimiter) Acquire(queue string) (func(), error) {
+	ql, ok := l.queues[queue]
+	if !ok {
+		return func() {}, nil
+	}
+
+	release := func() {}
+	if ql.slots != nil {
+		select {
+		case ql.slots <- struct{}{}:
+			release = func() { <-ql.slots }
+		default:
+			return nil, &QueueLimitError{Queue: queue, RetryIn: queueBusyRetryDelay}
+		}
+	}
+
+	if ql.limiter != nil {
+		reservation := ql.limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			release()
+			return nil, &QueueLimitError{Queue: queue, RetryIn: delay}
+		}
+	}
+
+	return release, nil
+}
+
+// Middleware applies the queue limits to every task handled by the mux
+func (l *QueueLimiter) Middleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		queue, _ := asynq.GetQueueName(ctx)
+
+		release, err := l.Acquire(queue)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		return next.ProcessTask(ctx, t)
+	})
+}
@@ -0,0 +1,79 @@
+// internal/workers/queue_limits_test.go
+package workers_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/internal/workers"
+)
+
+func TestQueueLimiter_Concurrency(t *testing.T) {
+	limiter := workers.NewQueueLimiter(map[string]config.QueueLimit{
+		"default": {Concurrency: 2},
+	})
+
+	first, err := limiter.Acquire("default")
+	require.NoError(t, err)
+	second, err := limiter.Acquire("default")
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire("default")
+	require.Error(t, err)
+	assert.True(t, workers.IsQueueLimitError(err))
+	delay, ok := workers.QueueLimitRetryDelay(err)
+	require.True(t, ok)
+	assert.Positive(t, delay)
+
+	// Queues without a limit are never held back
+	for i := 0; i < 5; i++ {
+		release, err := limiter.Acquire("low")
+		require.NoError(t, err)
+		defer release()
+	}
+
+	first()
+	third, err := limiter.Acquire("default")
+	require.NoError(t, err, "a released slot is reused")
+
+	second()
+	third()
+}
+
+func TestQueueLimiter_RatePerMinute(t *testing.T) {
+	limiter := workers.NewQueueLimiter(map[string]config.QueueLimit{
+		"default": {Concurrency: 1, RatePerMinute: 2},
+	})
+
+	release, err := limiter.Acquire("default")
+	require.NoError(t, err)
+	release()
+
+	_, err = limiter.Acquire("default")
+	require.Error(t, err)
+	delay, ok := workers.QueueLimitRetryDelay(err)
+	require.True(t, ok)
+	assert.Greater(t, delay, 25*time.Second)
+	assert.LessOrEqual(t, delay, 30*time.Second)
+
+	// A rate-limited task must not keep its concurrency slot
+	_, err = limiter.Acquire("default")
+	delay2, ok := workers.QueueLimitRetryDelay(err)
+	require.True(t, ok)
+	assert.Greater(t, delay2, 25*time.Second, "still limited by rate, not by a leaked slot")
+}
+
+func TestIsQueueLimitError(t *testing.T) {
+	wrapped := fmt.Errorf("process task: %w", &workers.QueueLimitError{Queue: "default", RetryIn: time.Second})
+	assert.True(t, workers.IsQueueLimitError(wrapped))
+	assert.False(t, workers.IsQueueLimitError(errors.New("boom")))
+
+	_, ok := workers.QueueLimitRetryDelay(errors.New("boom"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,171 @@
+// internal/workers/report.go
+package workers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tealeg/xlsx/v3"
+)
+
+// Report types and formats accepted in GenerateReportPayload
+const (
+	ReportTypeWeeklySummary = "weekly_summary"
+
+	ReportFormatJSON = "json"
+	ReportFormatXLSX = "xlsx"
+)
+
+const (
+	reportDateLayout       = "2006-01-02"
+	defaultReportWindow    = 7 * 24 * time.Hour
+	reportTopCategoryLimit = 5
+)
+
+// ReportUploader stores generated report artifacts; *storage.S3Storage satisfies it
+type ReportUploader interface {
+	Upload(ctx context.Context, key string, data io.Reader, contentType string) (string, error)
+}
+
+// GenerateReportPayload represents the payload for report generation jobs
+type GenerateReportPayload struct {
+	JobID  string `json:"job_id,omitempty"`
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"` // json (default) or xlsx
+	// Start and End bound the window as YYYY-MM-DD dates in UTC; Start is inclusive and End
+	// exclusive. End defaults to today and Start to seven days before End.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// CategorySummary is a category's share of acquisitions in a report window
+type CategorySummary struct {
+	Category      string          `json:"category"`
+	ItemsAcquired int             `json:"items_acquired"`
+	TotalSpend    decimal.Decimal `json:"total_spend"`
+}
+
+// WeeklySummary aggregates inventory activity for a report window
+type WeeklySummary struct {
+	WindowStart   time.Time         `json:"window_start"`
+	WindowEnd     time.Time         `json:"window_end"`
+	ItemsAcquired int               `json:"items_acquired"`
+	TotalSpend    decimal.Decimal   `json:"total_spend"`
+	ItemsSold     int               `json:"items_sold"`
+	Revenue       decimal.Decimal   `json:"revenue"`
+	NetProfit     decimal.Decimal   `json:"net_profit"`
+	TopCategories []CategorySummary `json:"top_categories"`
+}
+
+// ReportResult is stored on the job once the artifact has been uploaded
+type ReportResult struct {
+	ArtifactKey string         `json:"artifact_key"`
+	Summary     *WeeklySummary `json:"summary"`
+}
+
+// reportWindow resolves the payload's date window relative to now
+func reportWindow(payload GenerateReportPayload, now time.Time) (time.Time, time.Time, error) {
+	end := now.UTC().Truncate(24 * time.Hour)
+	if payload.End != "" {
+		parsed, err := time.Parse(reportDateLayout, payload.End)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q: expected YYYY-MM-DD", payload.End)
+		}
+		end = parsed
+	}
+
+	start := end.Add(-defaultReportWindow)
+	if payload.Start != "" {
+		parsed, err := time.Parse(reportDateLayout, payload.Start)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q: expected YYYY-MM-DD", payload.Start)
+		}
+		start = parsed
+		if payload.End == "" {
+			end = start.Add(defaultReportWindow)
+		}
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("report window start %s must be before end %s",
+			start.Format(reportDateLayout), end.Format(reportDateLayout))
+	}
+	return start, end, nil
+}
+
+// reportArtifactKey is stable for a window so a retried job overwrites its own artifact
+func reportArtifactKey(reportType string, start, end time.Time, format string) string {
+	return fmt.Sprintf("reports/%s/%s_%s.%s", reportType,
+		start.Format(reportDateLayout), end.Format(reportDateLayout), format)
+}
+
+// renderWeeklySummary encodes the summary in the requested format, returning the bytes and content type
+func renderWeeklySummary(summary *WeeklySummary, format string) ([]byte, string, error) {
+	switch format {
+	case ReportFormatJSON:
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode report: %w", err)
+		}
+		return data, "application/json", nil
+	case ReportFormatXLSX:
+		data, err := weeklySummaryWorkbook(summary)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+func weeklySummaryWorkbook(summary *WeeklySummary) ([]byte, error) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("Weekly Summary")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report sheet: %w", err)
+	}
+
+	addRow := func(label string, value func(*xlsx.Cell)) {
+		row := sheet.AddRow()
+		row.AddCell().SetString(label)
+		value(row.AddCell())
+	}
+	money := func(d decimal.Decimal) func(*xlsx.Cell) {
+		return func(c *xlsx.Cell) { c.SetFloatWithFormat(d.InexactFloat64(), "#,##0.00") }
+	}
+	count := func(n int) func(*xlsx.Cell) {
+		return func(c *xlsx.Cell) { c.SetInt(n) }
+	}
+
+	addRow("Window Start", func(c *xlsx.Cell) { c.SetString(summary.WindowStart.Format(reportDateLayout)) })
+	addRow("Window End", func(c *xlsx.Cell) { c.SetString(summary.WindowEnd.Format(reportDateLayout)) })
+	addRow("Items Acquired", count(summary.ItemsAcquired))
+	addRow("Total Spend", money(summary.TotalSpend))
+	addRow("Items Sold", count(summary.ItemsSold))
+	addRow("Revenue", money(summary.Revenue))
+	addRow("Net Profit", money(summary.NetProfit))
+
+	sheet.AddRow()
+	header := sheet.AddRow()
+	for _, title := range []string{"Top Category", "Items Acquired", "Total Spend"} {
+		header.AddCell().SetString(title)
+	}
+	for _, category := range summary.TopCategories {
+		row := sheet.AddRow()
+		row.AddCell().SetString(category.Category)
+		row.AddCell().SetInt(category.ItemsAcquired)
+		row.AddCell().SetFloatWithFormat(category.TotalSpend.InexactFloat64(), "#,##0.00")
+	}
+
+	var buf bytes.Buffer
+	if err := file.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write report workbook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
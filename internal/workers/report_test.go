@@ -0,0 +1,255 @@
+// internal/workers/report_test.go
+package workers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tealeg/xlsx/v3"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+// fakeRows is a minimal pgx.Rows over in-memory values; it also serves as a pgx.Row
+type fakeRows struct {
+	pgx.Rows
+	values [][]any
+	index  int
+}
+
+func (f *fakeRows) Next() bool {
+	if f.index < len(f.values) {
+		f.index++
+		return true
+	}
+	return false
+}
+
+func (f *fakeRows) Scan(dest ...interface{}) error {
+	if f.index == 0 {
+		// Used as a pgx.Row: advance to the first row
+		if !f.Next() {
+			return pgx.ErrNoRows
+		}
+	}
+	row := f.values[f.index-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("scan expected %d destinations, got %d", len(row), len(dest))
+	}
+	for i, value := range row {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func (f *fakeRows) Close()     {}
+func (f *fakeRows) Err() error { return nil }
+
+type recordingUploader struct {
+	key         string
+	contentType string
+	data        []byte
+}
+
+func (u *recordingUploader) Upload(ctx context.Context, key string, data io.Reader, contentType string) (string, error) {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return "", err
+	}
+	u.key, u.contentType, u.data = key, contentType, b
+	return "s3://bucket/" + key, nil
+}
+
+func TestAnalyticsProcessor_GenerateReport_WeeklySummary(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       string
+		acquisitions []any
+		sales        []any
+		categories   [][]any
+		validate     func(*testing.T, *recordingUploader, workers.ReportResult)
+	}{
+		{
+			name:         "empty_week",
+			acquisitions: []any{0, decimal.Zero},
+			sales:        []any{0, decimal.Zero, decimal.Zero},
+			validate: func(t *testing.T, uploader *recordingUploader, result workers.ReportResult) {
+				assert.Equal(t, "reports/weekly_summary/2024-03-04_2024-03-11.json", uploader.key)
+				assert.Equal(t, "application/json", uploader.contentType)
+
+				var artifact map[string]any
+				require.NoError(t, json.Unmarshal(uploader.data, &artifact))
+				assert.EqualValues(t, 0, artifact["items_acquired"])
+				assert.Equal(t, "0", artifact["total_spend"])
+				assert.EqualValues(t, 0, artifact["items_sold"])
+				assert.Equal(t, "0", artifact["net_profit"])
+				assert.Equal(t, []any{}, artifact["top_categories"])
+
+				require.NotNil(t, result.Summary)
+				assert.Equal(t, 0, result.Summary.ItemsAcquired)
+				assert.Empty(t, result.Summary.TopCategories)
+			},
+		},
+		{
+			name:         "populated_week",
+			format:       workers.ReportFormatXLSX,
+			acquisitions: []any{7, decimal.RequireFromString("1284.50")},
+			sales:        []any{3, decimal.RequireFromString("960.00"), decimal.RequireFromString("412.25")},
+			categories: [][]any{
+				{"furniture", 2, decimal.RequireFromString("800.00")},
+				{"silver", 4, decimal.RequireFromString("400.50")},
+				{"art", 1, decimal.RequireFromString("84.00")},
+			},
+			validate: func(t *testing.T, uploader *recordingUploader, result workers.ReportResult) {
+				assert.Equal(t, "reports/weekly_summary/2024-03-04_2024-03-11.xlsx", uploader.key)
+				assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", uploader.contentType)
+
+				require.NotNil(t, result.Summary)
+				assert.Equal(t, 7, result.Summary.ItemsAcquired)
+				assert.True(t, decimal.RequireFromString("1284.50").Equal(result.Summary.TotalSpend))
+				assert.Equal(t, 3, result.Summary.ItemsSold)
+				assert.True(t, decimal.RequireFromString("960").Equal(result.Summary.Revenue))
+				assert.True(t, decimal.RequireFromString("412.25").Equal(result.Summary.NetProfit))
+				require.Len(t, result.Summary.TopCategories, 3)
+				assert.Equal(t, "furniture", result.Summary.TopCategories[0].Category)
+
+				file, err := xlsx.OpenBinary(uploader.data)
+				require.NoError(t, err)
+				sheet := file.Sheets[0]
+				assert.Equal(t, "Weekly Summary", sheet.Name)
+
+				cell := func(row, col int) string {
+					c, err := sheet.Cell(row, col)
+					require.NoError(t, err)
+					return c.Value
+				}
+				assert.Equal(t, "Items Acquired", cell(2, 0))
+				assert.Equal(t, "7", cell(2, 1))
+				assert.Equal(t, "Net Profit", cell(6, 0))
+				assert.Equal(t, "412.25", cell(6, 1))
+				assert.Equal(t, "Top Category", cell(8, 0))
+				assert.Equal(t, "silver", cell(10, 0))
+				assert.Equal(t, "4", cell(10, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			uploader := &recordingUploader{}
+			processor := workers.NewAnalyticsProcessor(mockDB, nil, uploader, helpers.TestLogger())
+
+			gomock.InOrder(
+				mockDB.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&fakeRows{values: [][]any{tt.acquisitions}}),
+				mockDB.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&fakeRows{values: [][]any{tt.sales}}),
+			)
+			mockDB.EXPECT().
+				Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&fakeRows{values: tt.categories}, nil)
+
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), "job-1", "processing", gomock.Nil()).
+				Return(pgconn.CommandTag{}, nil)
+
+			var result workers.ReportResult
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), "job-1", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+					require.NoError(t, json.Unmarshal(args[1].(json.RawMessage), &result))
+					return pgconn.CommandTag{}, nil
+				})
+
+			payload, err := json.Marshal(workers.GenerateReportPayload{
+				JobID:  "job-1",
+				Type:   workers.ReportTypeWeeklySummary,
+				Format: tt.format,
+				Start:  "2024-03-04",
+			})
+			require.NoError(t, err)
+
+			err = processor.GenerateReport(context.Background(), asynq.NewTask(workers.TypeGenerateReport, payload))
+
+			require.NoError(t, err)
+			assert.Equal(t, uploader.key, result.ArtifactKey)
+			tt.validate(t, uploader, result)
+		})
+	}
+}
+
+func TestAnalyticsProcessor_GenerateReport_InvalidPayload(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       workers.GenerateReportPayload
+		uploader      workers.ReportUploader
+		expectedError string
+	}{
+		{
+			name:          "unsupported_type",
+			payload:       workers.GenerateReportPayload{JobID: "job-1", Type: "monthly"},
+			uploader:      &recordingUploader{},
+			expectedError: `unsupported report type "monthly"`,
+		},
+		{
+			name:          "unsupported_format",
+			payload:       workers.GenerateReportPayload{JobID: "job-1", Type: workers.ReportTypeWeeklySummary, Format: "csv"},
+			uploader:      &recordingUploader{},
+			expectedError: `unsupported report format "csv"`,
+		},
+		{
+			name:          "end_before_start",
+			payload:       workers.GenerateReportPayload{JobID: "job-1", Type: workers.ReportTypeWeeklySummary, Start: "2024-03-11", End: "2024-03-04"},
+			uploader:      &recordingUploader{},
+			expectedError: "report window start 2024-03-11 must be before end 2024-03-04",
+		},
+		{
+			name:          "storage_not_configured",
+			payload:       workers.GenerateReportPayload{JobID: "job-1", Type: workers.ReportTypeWeeklySummary},
+			expectedError: "report storage is not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			mockDB.EXPECT().
+				Exec(gomock.Any(), gomock.Any(), "job-1", "failed", gomock.Any()).
+				Return(pgconn.CommandTag{}, nil)
+
+			processor := workers.NewAnalyticsProcessor(mockDB, nil, tt.uploader, helpers.TestLogger())
+
+			payload, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			err = processor.GenerateReport(context.Background(), asynq.NewTask(workers.TypeGenerateReport, payload))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.True(t, errors.Is(err, asynq.SkipRetry))
+		})
+	}
+}
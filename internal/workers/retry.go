@@ -0,0 +1,32 @@
+// internal/workers/retry.go
+package workers
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ExponentialBackoff returns a retry delay func that doubles base with each retry up to
+// maxDelay and applies equal jitter: the delay is drawn from the upper half of the
+// exponential step, so tasks that failed together don't all retry at the same moment.
+// Delays never fall below base or exceed maxDelay.
+func ExponentialBackoff(base, maxDelay time.Duration) asynq.RetryDelayFunc {
+	return func(n int, _ error, _ *asynq.Task) time.Duration {
+		delay := base
+		for i := 0; i < n && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+
+		half := delay / 2
+		jittered := half + rand.N(delay-half+1)
+		if jittered < base {
+			jittered = base
+		}
+		return jittered
+	}
+}
@@ -0,0 +1,44 @@
+// internal/workers/retry_test.go
+package workers_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ammerola/resell-be/internal/workers"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	base := time.Second
+	maxDelay := 10 * time.Minute
+	backoff := workers.ExponentialBackoff(base, maxDelay)
+	task := asynq.NewTask(workers.TypePDFProcess, nil)
+	err := errors.New("database unavailable")
+
+	for _, n := range []int{0, 1, 3, 8, 12, 20, 64, 1000} {
+		for i := 0; i < 200; i++ {
+			delay := backoff(n, err, task)
+			assert.GreaterOrEqual(t, delay, base, "retry %d", n)
+			assert.LessOrEqual(t, delay, maxDelay, "retry %d", n)
+		}
+	}
+
+	t.Run("grows_with_each_retry", func(t *testing.T) {
+		// Equal jitter keeps at least half of the exponential step
+		assert.GreaterOrEqual(t, backoff(5, err, task), 16*time.Second)
+		assert.LessOrEqual(t, backoff(5, err, task), 32*time.Second)
+		assert.GreaterOrEqual(t, backoff(30, err, task), maxDelay/2)
+	})
+
+	t.Run("varies_for_the_same_retry", func(t *testing.T) {
+		seen := map[time.Duration]bool{}
+		for i := 0; i < 50; i++ {
+			seen[backoff(6, err, task)] = true
+		}
+		assert.Greater(t, len(seen), 1, "tasks failing together must not retry in lockstep")
+	})
+}
@@ -0,0 +1,70 @@
+// internal/workers/scheduler.go
+package workers
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/hibiken/asynq"
+)
+
+// TaskRegistrar registers periodic tasks; *asynq.Scheduler satisfies it
+type TaskRegistrar interface {
+	Register(cronspec string, task *asynq.Task, opts ...asynq.Option) (string, error)
+}
+
+// PeriodicTask describes a task enqueued on a cron schedule
+type PeriodicTask struct {
+	Cronspec string
+	TaskType string
+	Opts     []asynq.Option
+}
+
+// PeriodicTasks returns the recurring maintenance tasks for the given config.
+// Tasks with an empty cron spec are disabled.
+func PeriodicTasks(cfg config.AsynqConfig) []PeriodicTask {
+	candidates := []PeriodicTask{
+		{
+			Cronspec: cfg.AnalyticsCron,
+			TaskType: TypeRefreshAnalytics,
+			// Shares the uniqueness key with refreshes enqueued after a sale
+			Opts: []asynq.Option{asynq.Queue("low"), asynq.Unique(time.Minute)},
+		},
+		{
+			Cronspec: cfg.CleanupCron,
+			TaskType: TypeCleanupTempFiles,
+			Opts:     []asynq.Option{asynq.Queue("low")},
+		},
+		{
+			Cronspec: cfg.RetentionCron,
+			TaskType: TypeCleanupOldData,
+			Opts:     []asynq.Option{asynq.Queue("low")},
+		},
+	}
+
+	tasks := make([]PeriodicTask, 0, len(candidates))
+	for _, task := range candidates {
+		if task.Cronspec != "" {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// RegisterPeriodicTasks registers the recurring maintenance tasks with the scheduler
+func RegisterPeriodicTasks(scheduler TaskRegistrar, cfg config.AsynqConfig, logger *slog.Logger) error {
+	for _, task := range PeriodicTasks(cfg) {
+		entryID, err := scheduler.Register(task.Cronspec, asynq.NewTask(task.TaskType, nil), task.Opts...)
+		if err != nil {
+			return fmt.Errorf("failed to register periodic task %s: %w", task.TaskType, err)
+		}
+
+		logger.Info("registered periodic task",
+			slog.String("type", task.TaskType),
+			slog.String("cron", task.Cronspec),
+			slog.String("entry_id", entryID))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// internal/workers/scheduler_test.go
+package workers_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ammerola/resell-be/internal/pkg/config"
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+)
+
+type registeredEntry struct {
+	cronspec string
+	taskType string
+}
+
+type recordingRegistrar struct {
+	entries []registeredEntry
+}
+
+func (r *recordingRegistrar) Register(cronspec string, task *asynq.Task, opts ...asynq.Option) (string, error) {
+	r.entries = append(r.entries, registeredEntry{cronspec: cronspec, taskType: task.Type()})
+	return task.Type(), nil
+}
+
+func TestRegisterPeriodicTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.AsynqConfig
+		expected []registeredEntry
+	}{
+		{
+			name: "registers analytics refresh and temp file cleanup",
+			cfg:  config.AsynqConfig{AnalyticsCron: "0 * * * *", CleanupCron: "30 3 * * *"},
+			expected: []registeredEntry{
+				{cronspec: "0 * * * *", taskType: workers.TypeRefreshAnalytics},
+				{cronspec: "30 3 * * *", taskType: workers.TypeCleanupTempFiles},
+			},
+		},
+		{
+			name: "registers the retention purge",
+			cfg:  config.AsynqConfig{RetentionCron: "0 4 * * *"},
+			expected: []registeredEntry{
+				{cronspec: "0 4 * * *", taskType: workers.TypeCleanupOldData},
+			},
+		},
+		{
+			name: "skips disabled schedules",
+			cfg:  config.AsynqConfig{CleanupCron: "@every 6h"},
+			expected: []registeredEntry{
+				{cronspec: "@every 6h", taskType: workers.TypeCleanupTempFiles},
+			},
+		},
+		{
+			name:     "registers nothing when all schedules are disabled",
+			cfg:      config.AsynqConfig{},
+			expected: []registeredEntry{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registrar := &recordingRegistrar{entries: []registeredEntry{}}
+
+			err := workers.RegisterPeriodicTasks(registrar, tt.cfg, slog.Default())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, registrar.entries)
+		})
+	}
+}
+
+func TestRegisterPeriodicTasks_InvalidCronspec(t *testing.T) {
+	redis := helpers.SetupTestRedis(t)
+	scheduler := asynq.NewScheduler(asynq.RedisClientOpt{Addr: redis.Server.Addr()}, nil)
+
+	err := workers.RegisterPeriodicTasks(scheduler, config.AsynqConfig{AnalyticsCron: "not a cron"}, slog.Default())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to register periodic task analytics:refresh")
+}
+
+func TestPeriodicTasks_Queues(t *testing.T) {
+	tasks := workers.PeriodicTasks(config.AsynqConfig{AnalyticsCron: "0 * * * *", CleanupCron: "30 3 * * *"})
+
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Contains(t, task.Opts, asynq.Queue("low"), task.TaskType)
+	}
+}
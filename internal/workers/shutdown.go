@@ -0,0 +1,137 @@
+// internal/workers/shutdown.go
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/ammerola/resell-be/internal/core/ports"
+)
+
+// requeueJobsQuery hands jobs interrupted by a shutdown back to the queue. Jobs that reached
+// another status while the worker was stopping are left alone.
+const requeueJobsQuery = `
+	UPDATE async_jobs
+	SET status = 'queued', error = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE id = ANY($1::uuid[]) AND status = 'processing'`
+
+// JobTracker records the async jobs this worker is running, so any still unfinished when it
+// shuts down can be marked queued again instead of being stuck in processing. asynq requeues
+// the interrupted tasks themselves.
+type JobTracker struct {
+	db     ports.Database
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	active map[string]int
+	// interrupted holds jobs whose handler returned because asynq cancelled it, as it does
+	// when the drain timeout runs out, until RequeueUnfinished puts them back
+	interrupted map[string]struct{}
+}
+
+// NewJobTracker creates a new job tracker
+func NewJobTracker(db ports.Database, logger *slog.Logger) *JobTracker {
+	return &JobTracker{
+		db:          db,
+		logger:      logger.With(slog.String("component", "job_tracker")),
+		active:      make(map[string]int),
+		interrupted: make(map[string]struct{}),
+	}
+}
+
+// Middleware tracks every task whose payload carries a job_id while its handler runs
+func (t *JobTracker) Middleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		var payload struct {
+			JobID string `json:"job_id"`
+		}
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil || payload.JobID == "" {
+			return next.ProcessTask(ctx, task)
+		}
+
+		t.start(payload.JobID)
+		err := next.ProcessTask(ctx, task)
+		t.finish(payload.JobID, errors.Is(ctx.Err(), context.Canceled))
+		return err
+	})
+}
+
+func (t *JobTracker) start(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[jobID]++
+}
+
+func (t *JobTracker) finish(jobID string, cancelled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active[jobID]--; t.active[jobID] <= 0 {
+		delete(t.active, jobID)
+	}
+	if cancelled {
+		t.interrupted[jobID] = struct{}{}
+	}
+}
+
+// Active returns the IDs of the jobs currently being processed
+func (t *JobTracker) Active() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.active))
+	for id := range t.active {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// unfinished returns the IDs of the jobs still running or cancelled before they finished
+func (t *JobTracker) unfinished() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.active)+len(t.interrupted))
+	for id := range t.active {
+		ids = append(ids, id)
+	}
+	for id := range t.interrupted {
+		if _, running := t.active[id]; !running {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// RequeueUnfinished marks the jobs still running, or cancelled by the shutdown, as queued.
+// Call it once the server has shut down, after the drain timeout has passed.
+func (t *JobTracker) RequeueUnfinished(ctx context.Context) (int64, error) {
+	ids := t.unfinished()
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := t.db.Exec(ctx, requeueJobsQuery, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	t.mu.Lock()
+	for _, id := range ids {
+		delete(t.interrupted, id)
+	}
+	t.mu.Unlock()
+
+	t.logger.WarnContext(ctx, "requeued jobs interrupted by shutdown",
+		slog.Any("job_ids", ids),
+		slog.Int64("jobs_requeued", result.RowsAffected()))
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,113 @@
+// internal/workers/shutdown_test.go
+package workers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ammerola/resell-be/internal/workers"
+	"github.com/ammerola/resell-be/test/helpers"
+	"github.com/ammerola/resell-be/test/mocks"
+)
+
+func newJobTask(t *testing.T, taskType, jobID string) *asynq.Task {
+	t.Helper()
+	data, err := json.Marshal(map[string]string{"job_id": jobID})
+	require.NoError(t, err)
+	return asynq.NewTask(taskType, data)
+}
+
+func TestJobTracker_RequeuesJobsInterruptedByShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const longJobID = "0b0e7c1e-5b7a-4a53-9f49-2f1f4f0c8a11"
+	const shortJobID = "5d2d3c0a-1c55-4f0e-8c43-0e6f3b2a9d20"
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	tracker := workers.NewJobTracker(mockDB, helpers.TestLogger())
+
+	// A long PDF import that only stops when asynq aborts it at the end of the drain timeout
+	started := make(chan struct{})
+	handler := tracker.Middleware(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		if task.Type() == workers.TypePDFProcess {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}))
+
+	jobCtx, abort := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- handler.ProcessTask(jobCtx, newJobTask(t, workers.TypePDFProcess, longJobID))
+	}()
+	<-started
+
+	// Jobs that finish during the drain and tasks without a job aren't requeued
+	require.NoError(t, handler.ProcessTask(context.Background(), newJobTask(t, workers.TypeExcelImport, shortJobID)))
+	require.NoError(t, handler.ProcessTask(context.Background(), asynq.NewTask(workers.TypeCleanupTempFiles, nil)))
+	assert.Equal(t, []string{longJobID}, tracker.Active())
+
+	// srv.Shutdown aborts the long job, and only then does the worker requeue
+	abort()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("long job did not stop after being aborted")
+	}
+	assert.Empty(t, tracker.Active())
+
+	mockDB.EXPECT().
+		Exec(gomock.Any(), gomock.Any(), []string{longJobID}).
+		DoAndReturn(func(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+			assert.Contains(t, sql, "SET status = 'queued'")
+			assert.Contains(t, sql, "status = 'processing'")
+			return pgconn.NewCommandTag("UPDATE 1"), nil
+		})
+
+	requeued, err := tracker.RequeueUnfinished(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, requeued)
+
+	// Nothing left in flight, so a second pass doesn't touch the database
+	requeued, err = tracker.RequeueUnfinished(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, requeued)
+}
+
+func TestJobTracker_RequeueFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pgconn.CommandTag{}, errors.New("connection reset"))
+
+	tracker := workers.NewJobTracker(mockDB, helpers.TestLogger())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := tracker.Middleware(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	go handler.ProcessTask(context.Background(), newJobTask(t, workers.TypeExcelImport, "job-1"))
+	<-started
+	defer close(release)
+
+	_, err := tracker.RequeueUnfinished(context.Background())
+	assert.Error(t, err)
+}
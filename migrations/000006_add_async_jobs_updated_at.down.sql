@@ -0,0 +1,2 @@
+ALTER TABLE async_jobs ALTER COLUMN status SET DEFAULT 'pending';
+ALTER TABLE async_jobs DROP COLUMN IF EXISTS updated_at;
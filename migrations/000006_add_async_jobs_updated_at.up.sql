@@ -0,0 +1,5 @@
+-- Workers stamp updated_at on every status change
+ALTER TABLE async_jobs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+-- Jobs are created by the API before they are enqueued
+ALTER TABLE async_jobs ALTER COLUMN status SET DEFAULT 'queued';
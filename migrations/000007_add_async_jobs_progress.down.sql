@@ -0,0 +1,2 @@
+ALTER TABLE async_jobs DROP COLUMN IF EXISTS items_processed;
+ALTER TABLE async_jobs DROP COLUMN IF EXISTS progress;
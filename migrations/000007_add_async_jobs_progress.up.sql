@@ -0,0 +1,3 @@
+-- Incremental progress reported by workers while a job runs
+ALTER TABLE async_jobs ADD COLUMN IF NOT EXISTS progress INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE async_jobs ADD COLUMN IF NOT EXISTS items_processed INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ebay_category_mappings;
@@ -0,0 +1,31 @@
+-- Maps inventory categories to eBay category IDs; rows can be overridden through the admin API
+CREATE TABLE IF NOT EXISTS ebay_category_mappings (
+    category item_category PRIMARY KEY,
+    ebay_category_id VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO ebay_category_mappings (category, ebay_category_id) VALUES
+    ('antiques', '20081'),
+    ('art', '550'),
+    ('books', '267'),
+    ('ceramics', '870'),
+    ('china', '870'),
+    ('clothing', '11450'),
+    ('coins', '11116'),
+    ('collectibles', '1'),
+    ('electronics', '293'),
+    ('furniture', '3197'),
+    ('glass', '870'),
+    ('jewelry', '281'),
+    ('linens', '20081'),
+    ('memorabilia', '64482'),
+    ('musical', '619'),
+    ('pottery', '870'),
+    ('silver', '20096'),
+    ('stamps', '260'),
+    ('tools', '631'),
+    ('toys', '220'),
+    ('vintage', '1'),
+    ('other', '99')
+ON CONFLICT (category) DO NOTHING;
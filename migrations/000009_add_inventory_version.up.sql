@@ -0,0 +1,2 @@
+-- Row version for optimistic concurrency; every update increments it
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+-- Append-only history of inventory writes. lot_id has no foreign key so the trail
+-- outlives hard-deleted items.
+CREATE TABLE IF NOT EXISTS inventory_audit (
+    id BIGSERIAL PRIMARY KEY,
+    lot_id UUID NOT NULL,
+    operation VARCHAR(20) NOT NULL CHECK (operation IN ('create', 'update', 'soft_delete', 'delete')),
+    changes JSONB NOT NULL DEFAULT '{}',
+    user_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_audit_lot_id ON inventory_audit (lot_id, created_at DESC);
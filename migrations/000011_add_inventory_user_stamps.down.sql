@@ -0,0 +1,2 @@
+ALTER TABLE inventory DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE inventory DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,3 @@
+-- User IDs from the request token; NULL for rows written anonymously or before auth was wired up
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS created_by VARCHAR(255);
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS updated_by VARCHAR(255);
@@ -93,6 +93,68 @@ func BenchmarkInventoryOperations(b *testing.B) {
 	})
 }
 
+// BenchmarkListPagination compares OFFSET and keyset pagination on a deep page
+func BenchmarkListPagination(b *testing.B) {
+	const (
+		pageSize = 20
+		deepPage = 1000
+	)
+
+	testDB := helpers.SetupTestDB(&testing.T{})
+	defer testDB.Database.Close()
+
+	repo := db.NewInventoryRepository(testDB.Database, helpers.TestLogger())
+	service := services.NewInventoryService(repo, testDB.PgxPool, helpers.TestLogger())
+	ctx := context.Background()
+
+	// Seed enough rows to reach the deep page
+	total := pageSize * deepPage
+	for start := 0; start < total; start += 500 {
+		items := make([]domain.InventoryItem, 500)
+		for j := range items {
+			items[j] = *helpers.CreateTestInventoryItem(func(item *domain.InventoryItem) {
+				item.InvoiceID = fmt.Sprintf("PAGE-%d", start+j)
+			})
+		}
+		if err := service.SaveItems(ctx, items); err != nil {
+			b.Fatalf("failed to seed items: %v", err)
+		}
+	}
+
+	// The cursor for the deep page is the last item of the page before it
+	previous, err := service.List(ctx, ports.ListParams{Page: deepPage - 1, PageSize: pageSize})
+	if err != nil || previous.NextCursor == "" {
+		b.Fatalf("failed to get cursor for page %d: %v", deepPage, err)
+	}
+	afterCreatedAt, afterLotID, err := ports.DecodeListCursor(previous.NextCursor)
+	if err != nil {
+		b.Fatalf("failed to decode cursor: %v", err)
+	}
+
+	b.Run("Offset", func(b *testing.B) {
+		params := ports.ListParams{Page: deepPage, PageSize: pageSize}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = service.List(ctx, params)
+		}
+	})
+
+	b.Run("Keyset", func(b *testing.B) {
+		params := ports.ListParams{
+			Page:           1,
+			PageSize:       pageSize,
+			AfterCreatedAt: &afterCreatedAt,
+			AfterLotID:     afterLotID,
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = service.List(ctx, params)
+		}
+	})
+}
+
 func BenchmarkPDFProcessing(b *testing.B) {
 	// Benchmark PDF extraction performance
 	processor := createBenchmarkProcessor()
@@ -2,7 +2,10 @@
 package helpers
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/rc4"
 	"database/sql"
 	"fmt"
 	"log/slog"
@@ -22,6 +25,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"github.com/tealeg/xlsx/v3"
 
 	"github.com/ammerola/resell-be/internal/core/domain"
 )
@@ -182,6 +186,8 @@ func LoadTestConfig() *config.Config {
 			LogLevel:    "debug",
 			LogFormat:   "text",
 			Debug:       true,
+
+			IdempotencyTTL: time.Hour,
 		},
 		Database: config.DatabaseConfig{
 			Host:               "localhost",
@@ -202,10 +208,18 @@ func LoadTestConfig() *config.Config {
 			PoolSize: 10,
 		},
 		FileProcessing: config.FileProcessingConfig{
-			PDFMaxSizeMB:      50,
-			ExcelMaxSizeMB:    100,
-			ProcessingTimeout: 5 * time.Minute,
-			TempDir:           "/tmp",
+			PDFMaxSizeMB:          50,
+			ExcelMaxSizeMB:        100,
+			ProcessingTimeout:     5 * time.Minute,
+			TempDir:               "/tmp",
+			ExportStreamThreshold: 10000,
+			ProgressInterval:      100,
+		},
+		Retention: config.RetentionConfig{
+			SoftDeleted:  90 * 24 * time.Hour,
+			Jobs:         30 * 24 * time.Hour,
+			ActivityLogs: 90 * 24 * time.Hour,
+			BatchSize:    1000,
 		},
 		Security: config.SecurityConfig{
 			JWTSecret:         "test-secret",
@@ -392,3 +406,145 @@ func CreateTempFile(t *testing.T, content []byte, extension string) string {
 
 	return file.Name()
 }
+
+// CreateTestPDF writes a single-page PDF with one text line per entry and returns its path
+func CreateTestPDF(t *testing.T, lines []string) string {
+	t.Helper()
+	return CreateTempFile(t, buildTestPDF(lines, nil, ""), ".pdf")
+}
+
+// CreateEncryptedTestPDF writes the same document as CreateTestPDF, encrypted with the
+// standard 128-bit RC4 security handler so that it only opens with userPassword
+func CreateEncryptedTestPDF(t *testing.T, lines []string, userPassword string) string {
+	t.Helper()
+
+	id := []byte("resell-test-pdf!")
+	permissions := int32(-4)
+	p := uint32(permissions)
+	pBytes := []byte{byte(p), byte(p >> 8), byte(p >> 16), byte(p >> 24)}
+
+	// Owner entry: RC4 of the padded user password under a key derived from the owner password
+	ownerKey := md5.Sum(padPDFPassword("owner-" + userPassword))
+	for i := 0; i < 50; i++ {
+		ownerKey = md5.Sum(ownerKey[:])
+	}
+	o := rc4Rounds(ownerKey[:], padPDFPassword(userPassword))
+
+	// File key derived from the user password
+	h := md5.New()
+	h.Write(padPDFPassword(userPassword))
+	h.Write(o)
+	h.Write(pBytes)
+	h.Write(id)
+	key := h.Sum(nil)
+	for i := 0; i < 50; i++ {
+		sum := md5.Sum(key)
+		key = sum[:]
+	}
+
+	// User entry: RC4 of the hashed padding and ID, padded out to 32 bytes
+	uHash := md5.Sum(append(append([]byte{}, pdfPasswordPad...), id...))
+	u := append(rc4Rounds(key, uHash[:]), make([]byte, 16)...)
+
+	encrypt := func(data []byte, objNum int) []byte {
+		objKey := md5.Sum(append(append([]byte{}, key...), byte(objNum), byte(objNum>>8), byte(objNum>>16), 0, 0))
+		c, err := rc4.NewCipher(objKey[:])
+		require.NoError(t, err)
+		out := make([]byte, len(data))
+		c.XORKeyStream(out, data)
+		return out
+	}
+
+	trailer := fmt.Sprintf("/Encrypt<</Filter/Standard/V 2/R 3/Length 128/O<%x>/U<%x>/P %d>>/ID[<%x><%x>]",
+		o, u, permissions, id, id)
+
+	return CreateTempFile(t, buildTestPDF(lines, encrypt, trailer), ".pdf")
+}
+
+// pdfPasswordPad is the fixed padding string from the PDF standard security handler
+var pdfPasswordPad = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+func padPDFPassword(password string) []byte {
+	padded := append([]byte(password), pdfPasswordPad...)
+	return padded[:32]
+}
+
+// rc4Rounds applies the revision 3 key schedule: one RC4 pass with the key, then 19 more
+// passes with the key XORed by the round number
+func rc4Rounds(key, data []byte) []byte {
+	out := append([]byte{}, data...)
+	for i := 0; i <= 19; i++ {
+		roundKey := make([]byte, len(key))
+		for j := range key {
+			roundKey[j] = key[j] ^ byte(i)
+		}
+		c, _ := rc4.NewCipher(roundKey)
+		c.XORKeyStream(out, out)
+	}
+	return out
+}
+
+// buildTestPDF renders a single-page text PDF. When encrypt is set the content stream
+// is passed through it, and trailerExtra is appended to the trailer dictionary.
+func buildTestPDF(lines []string, encrypt func(data []byte, objNum int) []byte, trailerExtra string) []byte {
+	var stream bytes.Buffer
+	stream.WriteString("BT /F1 10 Tf 14 TL 50 750 Td\n")
+	for _, line := range lines {
+		fmt.Fprintf(&stream, "(%s) Tj T*\n", line)
+	}
+	stream.WriteString("ET")
+
+	content := stream.Bytes()
+	if encrypt != nil {
+		content = encrypt(content, 4)
+	}
+
+	objects := []string{
+		"<</Type/Catalog/Pages 2 0 R>>",
+		"<</Type/Pages/Count 1/Kids[3 0 R]>>",
+		"<</Type/Page/Parent 2 0 R/MediaBox[0 0 612 792]/Contents 4 0 R/Resources<</Font<</F1 5 0 R>>>>>>",
+		fmt.Sprintf("<</Length %d>>stream\n%s\nendstream", len(content), content),
+		"<</Type/Font/Subtype/Type1/BaseFont/Helvetica>>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj%sendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer<</Size %d/Root 1 0 R%s>>\nstartxref\n%d\n%%%%EOF", len(objects)+1, trailerExtra, xref)
+
+	return buf.Bytes()
+}
+
+// CreateTestExcel writes a single-sheet workbook with the given rows (the first row is the header) and returns its path
+func CreateTestExcel(t *testing.T, rows [][]string) string {
+	t.Helper()
+
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("Sheet1")
+	require.NoError(t, err)
+
+	for _, values := range rows {
+		row := sheet.AddRow()
+		for _, value := range values {
+			row.AddCell().SetString(value)
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	return CreateTempFile(t, buf.Bytes(), ".xlsx")
+}
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	ports "github.com/ammerola/resell-be/internal/core/ports"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +42,21 @@ func (m *MockCacheRepository) EXPECT() *MockCacheRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CompareAndDelete mocks base method.
+func (m *MockCacheRepository) CompareAndDelete(ctx context.Context, key string, value any) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndDelete", ctx, key, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndDelete indicates an expected call of CompareAndDelete.
+func (mr *MockCacheRepositoryMockRecorder) CompareAndDelete(ctx, key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndDelete", reflect.TypeOf((*MockCacheRepository)(nil).CompareAndDelete), ctx, key, value)
+}
+
 // Delete mocks base method.
 func (m *MockCacheRepository) Delete(ctx context.Context, keys ...string) error {
 	m.ctrl.T.Helper()
@@ -137,17 +153,23 @@ func (mr *MockCacheRepositoryMockRecorder) Get(ctx, key, dest any) *gomock.Call
 }
 
 // GetOrSet mocks base method.
-func (m *MockCacheRepository) GetOrSet(ctx context.Context, key string, dest any, fetch func() (any, error), ttl time.Duration) error {
+func (m *MockCacheRepository) GetOrSet(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error), opts ...ports.GetOrSetOption) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrSet", ctx, key, dest, fetch, ttl)
-	ret0, _ := ret[0].(error)
-	return ret0
+	varargs := []any{ctx, key, ttl, loader}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetOrSet", varargs...)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetOrSet indicates an expected call of GetOrSet.
-func (mr *MockCacheRepositoryMockRecorder) GetOrSet(ctx, key, dest, fetch, ttl any) *gomock.Call {
+func (mr *MockCacheRepositoryMockRecorder) GetOrSet(ctx, key, ttl, loader any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrSet", reflect.TypeOf((*MockCacheRepository)(nil).GetOrSet), ctx, key, dest, fetch, ttl)
+	varargs := append([]any{ctx, key, ttl, loader}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrSet", reflect.TypeOf((*MockCacheRepository)(nil).GetOrSet), varargs...)
 }
 
 // Increment mocks base method.
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	ports "github.com/ammerola/resell-be/internal/core/ports"
 	pgx "github.com/jackc/pgx/v5"
 	pgconn "github.com/jackc/pgx/v5/pgconn"
 	pgxpool "github.com/jackc/pgx/v5/pgxpool"
@@ -155,3 +156,17 @@ func (mr *MockDatabaseMockRecorder) QueryRow(ctx, sql any, args ...any) *gomock.
 	varargs := append([]any{ctx, sql}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRow", reflect.TypeOf((*MockDatabase)(nil).QueryRow), varargs...)
 }
+
+// Stats mocks base method.
+func (m *MockDatabase) Stats() ports.PoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(ports.PoolStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockDatabaseMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockDatabase)(nil).Stats))
+}
@@ -43,6 +43,21 @@ func (m *MockInventoryRepository) EXPECT() *MockInventoryRepositoryMockRecorder
 	return m.recorder
 }
 
+// BulkUpdateFields mocks base method.
+func (m *MockInventoryRepository) BulkUpdateFields(ctx context.Context, lotIDs []uuid.UUID, updates ports.BulkFieldUpdates) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateFields", ctx, lotIDs, updates)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateFields indicates an expected call of BulkUpdateFields.
+func (mr *MockInventoryRepositoryMockRecorder) BulkUpdateFields(ctx, lotIDs, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateFields", reflect.TypeOf((*MockInventoryRepository)(nil).BulkUpdateFields), ctx, lotIDs, updates)
+}
+
 // Count mocks base method.
 func (m *MockInventoryRepository) Count(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +102,21 @@ func (mr *MockInventoryRepositoryMockRecorder) Exists(ctx, lotID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockInventoryRepository)(nil).Exists), ctx, lotID)
 }
 
+// FillQRCodes mocks base method.
+func (m *MockInventoryRepository) FillQRCodes(ctx context.Context, template domain.QRTemplate) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillQRCodes", ctx, template)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FillQRCodes indicates an expected call of FillQRCodes.
+func (mr *MockInventoryRepositoryMockRecorder) FillQRCodes(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillQRCodes", reflect.TypeOf((*MockInventoryRepository)(nil).FillQRCodes), ctx, template)
+}
+
 // FindAll mocks base method.
 func (m *MockInventoryRepository) FindAll(ctx context.Context, params ports.ListParams) ([]*domain.InventoryItem, int64, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +163,66 @@ func (mr *MockInventoryRepositoryMockRecorder) FindByInvoiceID(ctx, invoiceID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByInvoiceID", reflect.TypeOf((*MockInventoryRepository)(nil).FindByInvoiceID), ctx, invoiceID)
 }
 
+// FindHistory mocks base method.
+func (m *MockInventoryRepository) FindHistory(ctx context.Context, lotID uuid.UUID) ([]domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindHistory", ctx, lotID)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHistory indicates an expected call of FindHistory.
+func (mr *MockInventoryRepositoryMockRecorder) FindHistory(ctx, lotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHistory", reflect.TypeOf((*MockInventoryRepository)(nil).FindHistory), ctx, lotID)
+}
+
+// HasInvoice mocks base method.
+func (m *MockInventoryRepository) HasInvoice(ctx context.Context, invoiceID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasInvoice", ctx, invoiceID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasInvoice indicates an expected call of HasInvoice.
+func (mr *MockInventoryRepositoryMockRecorder) HasInvoice(ctx, invoiceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasInvoice", reflect.TypeOf((*MockInventoryRepository)(nil).HasInvoice), ctx, invoiceID)
+}
+
+// ListInvoices mocks base method.
+func (m *MockInventoryRepository) ListInvoices(ctx context.Context, params ports.InvoiceListParams) ([]ports.InvoiceSummary, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoices", ctx, params)
+	ret0, _ := ret[0].([]ports.InvoiceSummary)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListInvoices indicates an expected call of ListInvoices.
+func (mr *MockInventoryRepositoryMockRecorder) ListInvoices(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoices", reflect.TypeOf((*MockInventoryRepository)(nil).ListInvoices), ctx, params)
+}
+
+// Restore mocks base method.
+func (m *MockInventoryRepository) Restore(ctx context.Context, lotID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, lotID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockInventoryRepositoryMockRecorder) Restore(ctx, lotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockInventoryRepository)(nil).Restore), ctx, lotID)
+}
+
 // Save mocks base method.
 func (m *MockInventoryRepository) Save(ctx context.Context, item *domain.InventoryItem) error {
 	m.ctrl.T.Helper()
@@ -161,6 +251,35 @@ func (mr *MockInventoryRepositoryMockRecorder) SaveBatch(ctx, items any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockInventoryRepository)(nil).SaveBatch), ctx, items)
 }
 
+// SaveBatchUpsert mocks base method.
+func (m *MockInventoryRepository) SaveBatchUpsert(ctx context.Context, items []domain.InventoryItem, target ports.ConflictTarget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatchUpsert", ctx, items, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBatchUpsert indicates an expected call of SaveBatchUpsert.
+func (mr *MockInventoryRepositoryMockRecorder) SaveBatchUpsert(ctx, items, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatchUpsert", reflect.TypeOf((*MockInventoryRepository)(nil).SaveBatchUpsert), ctx, items, target)
+}
+
+// SearchHighlights mocks base method.
+func (m *MockInventoryRepository) SearchHighlights(ctx context.Context, search string, lotIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchHighlights", ctx, search, lotIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchHighlights indicates an expected call of SearchHighlights.
+func (mr *MockInventoryRepositoryMockRecorder) SearchHighlights(ctx, search, lotIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchHighlights", reflect.TypeOf((*MockInventoryRepository)(nil).SearchHighlights), ctx, search, lotIDs)
+}
+
 // SoftDelete mocks base method.
 func (m *MockInventoryRepository) SoftDelete(ctx context.Context, lotID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -175,6 +294,81 @@ func (mr *MockInventoryRepositoryMockRecorder) SoftDelete(ctx, lotID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockInventoryRepository)(nil).SoftDelete), ctx, lotID)
 }
 
+// SoftDeleteByInvoiceID mocks base method.
+func (m *MockInventoryRepository) SoftDeleteByInvoiceID(ctx context.Context, invoiceID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteByInvoiceID", ctx, invoiceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteByInvoiceID indicates an expected call of SoftDeleteByInvoiceID.
+func (mr *MockInventoryRepositoryMockRecorder) SoftDeleteByInvoiceID(ctx, invoiceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteByInvoiceID", reflect.TypeOf((*MockInventoryRepository)(nil).SoftDeleteByInvoiceID), ctx, invoiceID)
+}
+
+// StorageSummary mocks base method.
+func (m *MockInventoryRepository) StorageSummary(ctx context.Context) ([]ports.StorageBinSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageSummary", ctx)
+	ret0, _ := ret[0].([]ports.StorageBinSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageSummary indicates an expected call of StorageSummary.
+func (mr *MockInventoryRepositoryMockRecorder) StorageSummary(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageSummary", reflect.TypeOf((*MockInventoryRepository)(nil).StorageSummary), ctx)
+}
+
+// Suggest mocks base method.
+func (m *MockInventoryRepository) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, prefix, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockInventoryRepositoryMockRecorder) Suggest(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockInventoryRepository)(nil).Suggest), ctx, prefix, limit)
+}
+
+// SumTotals mocks base method.
+func (m *MockInventoryRepository) SumTotals(ctx context.Context, params ports.ListParams) (*ports.ListTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumTotals", ctx, params)
+	ret0, _ := ret[0].(*ports.ListTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumTotals indicates an expected call of SumTotals.
+func (mr *MockInventoryRepositoryMockRecorder) SumTotals(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumTotals", reflect.TypeOf((*MockInventoryRepository)(nil).SumTotals), ctx, params)
+}
+
+// TimeSeries mocks base method.
+func (m *MockInventoryRepository) TimeSeries(ctx context.Context, params ports.TimeSeriesParams) ([]ports.TimeSeriesBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TimeSeries", ctx, params)
+	ret0, _ := ret[0].([]ports.TimeSeriesBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TimeSeries indicates an expected call of TimeSeries.
+func (mr *MockInventoryRepositoryMockRecorder) TimeSeries(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeSeries", reflect.TypeOf((*MockInventoryRepository)(nil).TimeSeries), ctx, params)
+}
+
 // Update mocks base method.
 func (m *MockInventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
 	m.ctrl.T.Helper()
@@ -188,3 +382,18 @@ func (mr *MockInventoryRepositoryMockRecorder) Update(ctx, item any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInventoryRepository)(nil).Update), ctx, item)
 }
+
+// UpdatePartial mocks base method.
+func (m *MockInventoryRepository) UpdatePartial(ctx context.Context, lotID uuid.UUID, patch ports.InventoryPatch) (*domain.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePartial", ctx, lotID, patch)
+	ret0, _ := ret[0].(*domain.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePartial indicates an expected call of UpdatePartial.
+func (mr *MockInventoryRepositoryMockRecorder) UpdatePartial(ctx, lotID, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePartial", reflect.TypeOf((*MockInventoryRepository)(nil).UpdatePartial), ctx, lotID, patch)
+}
@@ -43,18 +43,48 @@ func (m *MockInventoryService) EXPECT() *MockInventoryServiceMockRecorder {
 	return m.recorder
 }
 
+// BulkUpdateFields mocks base method.
+func (m *MockInventoryService) BulkUpdateFields(ctx context.Context, lotIDs []uuid.UUID, updates ports.BulkFieldUpdates) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateFields", ctx, lotIDs, updates)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateFields indicates an expected call of BulkUpdateFields.
+func (mr *MockInventoryServiceMockRecorder) BulkUpdateFields(ctx, lotIDs, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateFields", reflect.TypeOf((*MockInventoryService)(nil).BulkUpdateFields), ctx, lotIDs, updates)
+}
+
 // BulkUpsert mocks base method.
-func (m *MockInventoryService) BulkUpsert(ctx context.Context, items []domain.InventoryItem) error {
+func (m *MockInventoryService) BulkUpsert(ctx context.Context, items []domain.InventoryItem, target ports.ConflictTarget) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkUpsert", ctx, items)
+	ret := m.ctrl.Call(m, "BulkUpsert", ctx, items, target)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BulkUpsert indicates an expected call of BulkUpsert.
-func (mr *MockInventoryServiceMockRecorder) BulkUpsert(ctx, items any) *gomock.Call {
+func (mr *MockInventoryServiceMockRecorder) BulkUpsert(ctx, items, target any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpsert", reflect.TypeOf((*MockInventoryService)(nil).BulkUpsert), ctx, items)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpsert", reflect.TypeOf((*MockInventoryService)(nil).BulkUpsert), ctx, items, target)
+}
+
+// DeleteInvoiceItems mocks base method.
+func (m *MockInventoryService) DeleteInvoiceItems(ctx context.Context, invoiceID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInvoiceItems", ctx, invoiceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInvoiceItems indicates an expected call of DeleteInvoiceItems.
+func (mr *MockInventoryServiceMockRecorder) DeleteInvoiceItems(ctx, invoiceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInvoiceItems", reflect.TypeOf((*MockInventoryService)(nil).DeleteInvoiceItems), ctx, invoiceID)
 }
 
 // DeleteItem mocks base method.
@@ -71,6 +101,21 @@ func (mr *MockInventoryServiceMockRecorder) DeleteItem(ctx, lotID, permanent any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockInventoryService)(nil).DeleteItem), ctx, lotID, permanent)
 }
 
+// GenerateQRCodes mocks base method.
+func (m *MockInventoryService) GenerateQRCodes(ctx context.Context, template domain.QRTemplate) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateQRCodes", ctx, template)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateQRCodes indicates an expected call of GenerateQRCodes.
+func (mr *MockInventoryServiceMockRecorder) GenerateQRCodes(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateQRCodes", reflect.TypeOf((*MockInventoryService)(nil).GenerateQRCodes), ctx, template)
+}
+
 // GetByID mocks base method.
 func (m *MockInventoryService) GetByID(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +131,51 @@ func (mr *MockInventoryServiceMockRecorder) GetByID(ctx, lotID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockInventoryService)(nil).GetByID), ctx, lotID)
 }
 
+// GetHistory mocks base method.
+func (m *MockInventoryService) GetHistory(ctx context.Context, lotID uuid.UUID) ([]domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, lotID)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockInventoryServiceMockRecorder) GetHistory(ctx, lotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockInventoryService)(nil).GetHistory), ctx, lotID)
+}
+
+// GetInvoiceItems mocks base method.
+func (m *MockInventoryService) GetInvoiceItems(ctx context.Context, invoiceID string) (*ports.InvoiceItems, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceItems", ctx, invoiceID)
+	ret0, _ := ret[0].(*ports.InvoiceItems)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceItems indicates an expected call of GetInvoiceItems.
+func (mr *MockInventoryServiceMockRecorder) GetInvoiceItems(ctx, invoiceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceItems", reflect.TypeOf((*MockInventoryService)(nil).GetInvoiceItems), ctx, invoiceID)
+}
+
+// HasInvoice mocks base method.
+func (m *MockInventoryService) HasInvoice(ctx context.Context, invoiceID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasInvoice", ctx, invoiceID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasInvoice indicates an expected call of HasInvoice.
+func (mr *MockInventoryServiceMockRecorder) HasInvoice(ctx, invoiceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasInvoice", reflect.TypeOf((*MockInventoryService)(nil).HasInvoice), ctx, invoiceID)
+}
+
 // List mocks base method.
 func (m *MockInventoryService) List(ctx context.Context, params ports.ListParams) (*ports.ListResult, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +191,66 @@ func (mr *MockInventoryServiceMockRecorder) List(ctx, params any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInventoryService)(nil).List), ctx, params)
 }
 
+// ListInvoices mocks base method.
+func (m *MockInventoryService) ListInvoices(ctx context.Context, params ports.InvoiceListParams) (*ports.InvoiceListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoices", ctx, params)
+	ret0, _ := ret[0].(*ports.InvoiceListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoices indicates an expected call of ListInvoices.
+func (mr *MockInventoryServiceMockRecorder) ListInvoices(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoices", reflect.TypeOf((*MockInventoryService)(nil).ListInvoices), ctx, params)
+}
+
+// PatchItem mocks base method.
+func (m *MockInventoryService) PatchItem(ctx context.Context, lotID uuid.UUID, patch ports.InventoryPatch) (*domain.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchItem", ctx, lotID, patch)
+	ret0, _ := ret[0].(*domain.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchItem indicates an expected call of PatchItem.
+func (mr *MockInventoryServiceMockRecorder) PatchItem(ctx, lotID, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchItem", reflect.TypeOf((*MockInventoryService)(nil).PatchItem), ctx, lotID, patch)
+}
+
+// Reclassify mocks base method.
+func (m *MockInventoryService) Reclassify(ctx context.Context, params ports.ReclassifyParams) (*ports.ReclassifyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reclassify", ctx, params)
+	ret0, _ := ret[0].(*ports.ReclassifyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reclassify indicates an expected call of Reclassify.
+func (mr *MockInventoryServiceMockRecorder) Reclassify(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reclassify", reflect.TypeOf((*MockInventoryService)(nil).Reclassify), ctx, params)
+}
+
+// RestoreItem mocks base method.
+func (m *MockInventoryService) RestoreItem(ctx context.Context, lotID uuid.UUID) (*domain.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreItem", ctx, lotID)
+	ret0, _ := ret[0].(*domain.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreItem indicates an expected call of RestoreItem.
+func (mr *MockInventoryServiceMockRecorder) RestoreItem(ctx, lotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreItem", reflect.TypeOf((*MockInventoryService)(nil).RestoreItem), ctx, lotID)
+}
+
 // SaveItem mocks base method.
 func (m *MockInventoryService) SaveItem(ctx context.Context, item *domain.InventoryItem) error {
 	m.ctrl.T.Helper()
@@ -129,6 +279,66 @@ func (mr *MockInventoryServiceMockRecorder) SaveItems(ctx, items any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveItems", reflect.TypeOf((*MockInventoryService)(nil).SaveItems), ctx, items)
 }
 
+// SaveItemsPartial mocks base method.
+func (m *MockInventoryService) SaveItemsPartial(ctx context.Context, items []domain.InventoryItem) (ports.SaveReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveItemsPartial", ctx, items)
+	ret0, _ := ret[0].(ports.SaveReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveItemsPartial indicates an expected call of SaveItemsPartial.
+func (mr *MockInventoryServiceMockRecorder) SaveItemsPartial(ctx, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveItemsPartial", reflect.TypeOf((*MockInventoryService)(nil).SaveItemsPartial), ctx, items)
+}
+
+// StorageSummary mocks base method.
+func (m *MockInventoryService) StorageSummary(ctx context.Context) (*ports.StorageSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageSummary", ctx)
+	ret0, _ := ret[0].(*ports.StorageSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageSummary indicates an expected call of StorageSummary.
+func (mr *MockInventoryServiceMockRecorder) StorageSummary(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageSummary", reflect.TypeOf((*MockInventoryService)(nil).StorageSummary), ctx)
+}
+
+// Suggest mocks base method.
+func (m *MockInventoryService) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, prefix, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockInventoryServiceMockRecorder) Suggest(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockInventoryService)(nil).Suggest), ctx, prefix, limit)
+}
+
+// TimeSeries mocks base method.
+func (m *MockInventoryService) TimeSeries(ctx context.Context, params ports.TimeSeriesParams) (*ports.TimeSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TimeSeries", ctx, params)
+	ret0, _ := ret[0].(*ports.TimeSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TimeSeries indicates an expected call of TimeSeries.
+func (mr *MockInventoryServiceMockRecorder) TimeSeries(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeSeries", reflect.TypeOf((*MockInventoryService)(nil).TimeSeries), ctx, params)
+}
+
 // UpdateItem mocks base method.
 func (m *MockInventoryService) UpdateItem(ctx context.Context, lotID uuid.UUID, item *domain.InventoryItem) error {
 	m.ctrl.T.Helper()
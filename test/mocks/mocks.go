@@ -9,3 +9,9 @@ package mocks
 //go:generate mockgen -source=../../internal/core/services/inventory.go -destination=pgxpool_mock.go -package=mocks PgxPool
 //go:generate mockgen -source=../../internal/core/ports/cache.go -destination=cache_repository_mock.go -package=mocks
 //go:generate mockgen -source=../../internal/core/ports/database.go -destination=database_mock.go -package=mocks
+//go:generate mockgen -source=../../internal/core/ports/listing.go -destination=listing_mock.go -package=mocks
+//go:generate mockgen -source=../../internal/core/ports/category_mapping.go -destination=category_mapping_mock.go -package=mocks
+//go:generate mockgen -source=../../internal/core/ports/dashboard.go -destination=dashboard_mock.go -package=mocks
+//go:generate mockgen -source=../../internal/core/ports/queue.go -destination=queue_mock.go -package=mocks
+//go:generate mockgen -source=../../internal/core/ports/migrations.go -destination=migrations_mock.go -package=mocks
+//go:generate mockgen -source=../../internal/core/ports/user.go -destination=user_mock.go -package=mocks